@@ -31,6 +31,19 @@ We truncate at 63 chars because some Kubernetes name fields are limited to this
 {{ if .Values.enableTLSCluster }}https{{ else }}http{{ end }}
 {{- end -}}
 
+{{/*
+The CA trusted by the component mounting its certificate in the directory, the certificates issued
+through the Kubernetes CSR API are signed by the Kubernetes cluster CA
+*/}}
+{{- define "tls-ca-path" -}}
+{{- $root := index . 0 -}}
+{{- if and $root.Values.tlsIssuer (ne ($root.Values.tlsIssuer.type | default "CSR") "CSR") -}}
+{{ index . 1 }}/ca
+{{- else -}}
+/var/run/secrets/kubernetes.io/serviceaccount/ca.crt
+{{- end -}}
+{{- end -}}
+
 {{/*
 Encapsulate PD configmap data for consistent digest calculation
 */}}
@@ -43,7 +56,7 @@ config-file: |-
     {{- end -}}
     {{- if .Values.enableTLSCluster }}
   [security]
-  cacert-path = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+  cacert-path = "{{ include "tls-ca-path" (list . "/var/lib/pd-tls") }}"
   cert-path = "/var/lib/pd-tls/cert"
   key-path = "/var/lib/pd-tls/key"
     {{- end -}}
@@ -66,7 +79,7 @@ config-file: |-
     {{- end -}}
     {{- if .Values.enableTLSCluster }}
   [security]
-  ca-path = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+  ca-path = "{{ include "tls-ca-path" (list . "/var/lib/tikv-tls") }}"
   cert-path = "/var/lib/tikv-tls/cert"
   key-path = "/var/lib/tikv-tls/key"
     {{- end -}}
@@ -77,6 +90,32 @@ config-file: |-
 {{ include "tikv-configmap.data" . | sha256sum | trunc 8 }}
 {{- end -}}
 
+{{/*
+Encapsulate the configmap data of a TiKV group for consistent digest calculation,
+the config of the group replaces the TiKV config if present
+*/}}
+{{- define "tikv-group-configmap.data" -}}
+{{- $root := index . 0 -}}
+{{- $group := index . 1 -}}
+startup-script: |-
+{{ tuple "scripts/_start_tikv.sh.tpl" $root | include "helm-toolkit.utils.template" | indent 2 }}
+config-file: |-
+    {{- with ($group.config | default $root.Values.tikv.config) }}
+{{ . | indent 2 }}
+    {{- end -}}
+    {{- if $root.Values.enableTLSCluster }}
+  [security]
+  ca-path = "{{ include "tls-ca-path" (list $root "/var/lib/tikv-tls") }}"
+  cert-path = "/var/lib/tikv-tls/cert"
+  key-path = "/var/lib/tikv-tls/key"
+    {{- end -}}
+
+{{- end -}}
+
+{{- define "tikv-group-configmap.data-digest" -}}
+{{ include "tikv-group-configmap.data" . | sha256sum | trunc 8 }}
+{{- end -}}
+
 {{/*
 Encapsulate TiDB configmap data for consistent digest calculation
 */}}
@@ -91,18 +130,21 @@ config-file: |-
     {{- if .Values.tidb.config }}
 {{ .Values.tidb.config | indent 2 }}
     {{- end -}}
-    {{- if or .Values.enableTLSCluster .Values.enableTLSClient }}
+    {{- if or .Values.enableTLSCluster .Values.tidb.enableTLSClient }}
   [security]
     {{- end -}}
     {{- if .Values.enableTLSCluster }}
-  cluster-ssl-ca = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+  cluster-ssl-ca = "{{ include "tls-ca-path" (list . "/var/lib/tidb-tls") }}"
   cluster-ssl-cert = "/var/lib/tidb-tls/cert"
   cluster-ssl-key = "/var/lib/tidb-tls/key"
     {{- end -}}
     {{- if .Values.tidb.enableTLSClient }}
-  ssl-ca = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+  ssl-ca = "{{ include "tls-ca-path" (list . "/var/lib/tidb-server-tls") }}"
   ssl-cert = "/var/lib/tidb-server-tls/cert"
   ssl-key = "/var/lib/tidb-server-tls/key"
+    {{- if and .Values.tidb.tlsClient .Values.tidb.tlsClient.requireSecureTransport }}
+  require-secure-transport = true
+    {{- end -}}
     {{- end -}}
 
 {{- end -}}
@@ -111,6 +153,42 @@ config-file: |-
 {{ include "tidb-configmap.data" . | sha256sum | trunc 8 }}
 {{- end -}}
 
+{{/*
+Encapsulate the configmap data of a TiDB group for consistent digest calculation,
+the config of the group replaces the TiDB config if present
+*/}}
+{{- define "tidb-group-configmap.data" -}}
+{{- $root := index . 0 -}}
+{{- $group := index . 1 -}}
+startup-script: |-
+{{ tuple "scripts/_start_tidb.sh.tpl" $root | include "helm-toolkit.utils.template" | indent 2 }}
+config-file: |-
+    {{- with ($group.config | default $root.Values.tidb.config) }}
+{{ . | indent 2 }}
+    {{- end -}}
+    {{- if or $root.Values.enableTLSCluster $root.Values.tidb.enableTLSClient }}
+  [security]
+    {{- end -}}
+    {{- if $root.Values.enableTLSCluster }}
+  cluster-ssl-ca = "{{ include "tls-ca-path" (list $root "/var/lib/tidb-tls") }}"
+  cluster-ssl-cert = "/var/lib/tidb-tls/cert"
+  cluster-ssl-key = "/var/lib/tidb-tls/key"
+    {{- end -}}
+    {{- if $root.Values.tidb.enableTLSClient }}
+  ssl-ca = "{{ include "tls-ca-path" (list $root "/var/lib/tidb-server-tls") }}"
+  ssl-cert = "/var/lib/tidb-server-tls/cert"
+  ssl-key = "/var/lib/tidb-server-tls/key"
+    {{- if and $root.Values.tidb.tlsClient $root.Values.tidb.tlsClient.requireSecureTransport }}
+  require-secure-transport = true
+    {{- end -}}
+    {{- end -}}
+
+{{- end -}}
+
+{{- define "tidb-group-configmap.data-digest" -}}
+{{ include "tidb-group-configmap.data" . | sha256sum | trunc 8 }}
+{{- end -}}
+
 {{/*
 Encapsulate pump configmap data for consistent digest calculation
 */}}
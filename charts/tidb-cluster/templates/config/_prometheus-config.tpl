@@ -22,7 +22,7 @@ scrape_configs:
     tls_config:
       insecure_skip_verify: true
     {{- if .Values.enableTLSCluster }}
-      ca_file: /var/run/secrets/kubernetes.io/serviceaccount/ca.crt
+      ca_file: {{ include "tls-ca-path" (list . "/var/lib/pd-client-tls") }}
       cert_file: /var/lib/pd-client-tls/cert
       key_file: /var/lib/pd-client-tls/key
 
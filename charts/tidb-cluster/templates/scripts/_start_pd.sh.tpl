@@ -33,6 +33,11 @@ POD_NAME=${POD_NAME:-$HOSTNAME}
 # the general form of variable PEER_SERVICE_NAME is: "<clusterName>-pd-peer"
 cluster_name=`echo ${PEER_SERVICE_NAME} | sed 's/-pd-peer//'`
 domain="${POD_NAME}.${PEER_SERVICE_NAME}.${NAMESPACE}.svc"
+# the members in other Kubernetes clusters reach this member by the domain of this Kubernetes cluster
+if [[ -n "${CLUSTER_DOMAIN}" ]]
+then
+    domain="${domain}.${CLUSTER_DOMAIN}"
+fi
 discovery_url="${cluster_name}-discovery.${NAMESPACE}.svc:10261"
 encoded_domain_url=`echo ${domain}:2380 | base64 | tr "\n" " " | sed "s/ //g"`
 
@@ -30,13 +30,27 @@ fi
 
 # Use HOSTNAME if POD_NAME is unset for backward compatibility.
 POD_NAME=${POD_NAME:-$HOSTNAME}
+advertise_addr="${POD_NAME}.${HEADLESS_SERVICE_NAME}.${NAMESPACE}.svc"
+# the members in other Kubernetes clusters reach this store by the domain of this Kubernetes cluster
+if [[ -n "${CLUSTER_DOMAIN}" ]]
+then
+    advertise_addr="${advertise_addr}.${CLUSTER_DOMAIN}"
+fi
+# the operator sizes the shared block cache from the memory limit, the size in tikv.toml takes precedence
+config=/etc/tikv/tikv.toml
+if [[ -n "${BLOCK_CACHE_CAPACITY:-}" ]] && ! grep -q '^\[storage.block-cache\]' ${config}
+then
+    cp ${config} /tmp/tikv.toml
+    printf '\n[storage.block-cache]\ncapacity = "%s"\n' "${BLOCK_CACHE_CAPACITY}" >> /tmp/tikv.toml
+    config=/tmp/tikv.toml
+fi
 ARGS="--pd={{ template "cluster.scheme" . }}://${CLUSTER_NAME}-pd:2379 \
---advertise-addr=${POD_NAME}.${HEADLESS_SERVICE_NAME}.${NAMESPACE}.svc:20160 \
+--advertise-addr=${advertise_addr}:20160 \
 --addr=0.0.0.0:20160 \
 --status-addr=0.0.0.0:20180 \
 --data-dir=/var/lib/tikv \
 --capacity=${CAPACITY} \
---config=/etc/tikv/tikv.toml
+--config=${config}
 "
 
 echo "starting tikv-server ..."
@@ -32,6 +32,7 @@ func NewBackupMgrCommand() *cobra.Command {
 	cmds.AddCommand(NewBackupCommand())
 	cmds.AddCommand(NewRestoreCommand())
 	cmds.AddCommand(NewCleanCommand())
+	cmds.AddCommand(NewImportCommand())
 	return cmds
 }
 
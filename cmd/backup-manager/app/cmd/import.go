@@ -0,0 +1,77 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+
+	// registry mysql drive
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/pingcap/tidb-operator/cmd/backup-manager/app/constants"
+	"github.com/pingcap/tidb-operator/cmd/backup-manager/app/dataimport"
+	"github.com/pingcap/tidb-operator/cmd/backup-manager/app/util"
+	informers "github.com/pingcap/tidb-operator/pkg/client/informers/externalversions"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/tools/cache"
+	glog "k8s.io/klog"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+// NewImportCommand implements the import command
+func NewImportCommand() *cobra.Command {
+	do := dataimport.DataImportOpts{}
+
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import data into specific tidb cluster with tidb-lightning.",
+		Run: func(cmd *cobra.Command, args []string) {
+			util.ValidCmdFlags(cmd.CommandPath(), cmd.LocalFlags())
+			cmdutil.CheckErr(runImport(do, kubecfg))
+		},
+	}
+
+	cmd.Flags().StringVarP(&do.Namespace, "namespace", "n", "", "Tidb cluster's namespace")
+	cmd.Flags().StringVarP(&do.TcName, "tidbcluster", "t", "", "Tidb cluster name")
+	cmd.Flags().StringVarP(&do.Password, "password", "p", "", "Password to use when connecting to tidb cluster")
+	cmd.Flags().StringVarP(&do.TidbSvc, "tidbservice", "s", "", "Tidb cluster access service address")
+	cmd.Flags().StringVarP(&do.User, "user", "u", "", "User for login tidb cluster")
+	cmd.Flags().StringVarP(&do.DataImportName, "dataImportName", "d", "", "DataImport CRD object name")
+	cmd.Flags().StringVar(&do.PDAddr, "pd", "", "PD address of the tidb cluster")
+	cmd.Flags().StringVar(&do.Importer, "importer", "", "Address of tikv-importer")
+	return cmd
+}
+
+func runImport(diOpts dataimport.DataImportOpts, kubecfg string) error {
+	kubeCli, cli, err := util.NewKubeAndCRCli(kubecfg)
+	cmdutil.CheckErr(err)
+	options := []informers.SharedInformerOption{
+		informers.WithNamespace(diOpts.Namespace),
+	}
+	informerFactory := informers.NewSharedInformerFactoryWithOptions(cli, constants.ResyncDuration, options...)
+	recorder := util.NewEventRecorder(kubeCli, "data-import")
+	diInformer := informerFactory.Pingcap().V1alpha1().DataImports()
+	statusUpdater := controller.NewRealDataImportStatusUpdater(cli, diInformer.Lister(), recorder)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go informerFactory.Start(ctx.Done())
+
+	// waiting for the shared informer's store has synced.
+	cache.WaitForCacheSync(ctx.Done(), diInformer.Informer().HasSynced)
+
+	glog.Infof("start to process data import %s", diOpts.String())
+	dm := dataimport.NewDataImportManager(diInformer.Lister(), statusUpdater, diOpts)
+	return dm.ProcessDataImport()
+}
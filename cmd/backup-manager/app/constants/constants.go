@@ -53,4 +53,16 @@ const (
 
 	// RcloneConfigArg represents the config argument to rclone cmd
 	RcloneConfigArg = "--config=" + RcloneConfigFile
+
+	// LightningBin is the path to the tidb-lightning binary copied by the init container of the data import job
+	LightningBin = "/lightning/tidb-lightning"
+
+	// LightningStatusAddr is the address of the status server of tidb-lightning
+	LightningStatusAddr = ":8289"
+
+	// DataImportSourcePath is the mount path of the PVC storing the source data of the local storage
+	DataImportSourcePath = "/source"
+
+	// ProgressInterval is the interval to update the progress of the data import
+	ProgressInterval = 30 * time.Second
 )
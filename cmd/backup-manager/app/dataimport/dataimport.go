@@ -0,0 +1,260 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataimport
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/mholt/archiver"
+	"github.com/pingcap/tidb-operator/cmd/backup-manager/app/constants"
+	"github.com/pingcap/tidb-operator/cmd/backup-manager/app/util"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+)
+
+const (
+	// downloadedMark is the file created after the source data is downloaded,
+	// so that the retried job can skip the downloading
+	downloadedMark = ".downloaded"
+	// schemaFileSuffix is the suffix of the table schema files of the mydumper source
+	schemaFileSuffix = "-schema.sql"
+	// schemaCreateFileSuffix is the suffix of the database schema files of the mydumper source
+	schemaCreateFileSuffix = "-schema-create.sql"
+)
+
+// DataImportOpts contains the input arguments to the import command
+type DataImportOpts struct {
+	Namespace      string
+	TcName         string
+	Password       string
+	TidbSvc        string
+	User           string
+	DataImportName string
+	PDAddr         string
+	Importer       string
+}
+
+func (do *DataImportOpts) String() string {
+	return fmt.Sprintf("%s/%s", do.Namespace, do.TcName)
+}
+
+func (do *DataImportOpts) getDSN(db string) string {
+	return fmt.Sprintf("%s:%s@(%s:4000)/%s?charset=utf8", do.User, do.Password, do.TidbSvc, db)
+}
+
+// getDataImportDir returns the dir keeping the downloaded data and the checkpoint of the data import
+func (do *DataImportOpts) getDataImportDir() string {
+	return filepath.Join(constants.BackupRootPath, fmt.Sprintf("%s-%s", do.Namespace, do.DataImportName))
+}
+
+// prepareSourceData downloads and unarchives the source data if needed, and returns the data source dir of tidb-lightning
+func (do *DataImportOpts) prepareSourceData(di *v1alpha1.DataImport) (string, error) {
+	path := di.Spec.Path
+	isArchive := strings.HasSuffix(path, constants.DefaultArchiveExtention)
+	dataDir := filepath.Join(do.getDataImportDir(), "data")
+
+	if di.Spec.StorageType == v1alpha1.BackupStorageTypeLocal {
+		localPath := filepath.Join(constants.DataImportSourcePath, path)
+		if !isArchive {
+			return localPath, nil
+		}
+		return unarchiveSourceData(localPath, dataDir)
+	}
+
+	localPath := filepath.Join(dataDir, filepath.Base(path))
+	if !isArchive {
+		if err := do.downloadSourceData("copy", path, localPath); err != nil {
+			return "", err
+		}
+		return localPath, nil
+	}
+	if err := do.downloadSourceData("copyto", path, localPath); err != nil {
+		return "", err
+	}
+	return unarchiveSourceData(localPath, dataDir)
+}
+
+// downloadSourceData downloads the source data with rclone, the downloading is skipped if it is already done
+func (do *DataImportOpts) downloadSourceData(rcloneCmd, remotePath, localPath string) error {
+	mark := filepath.Join(filepath.Dir(localPath), downloadedMark)
+	if util.IsFileExist(mark) {
+		return nil
+	}
+	if err := util.EnsureDirectoryExist(filepath.Dir(localPath)); err != nil {
+		return err
+	}
+
+	remoteBucket := util.NormalizeBucketURI(remotePath)
+	output, err := exec.Command("rclone", constants.RcloneConfigArg, rcloneCmd, remoteBucket, localPath).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("cluster %s, execute rclone %s command for download source data %s failed, output: %s, err: %v", do, rcloneCmd, remotePath, string(output), err)
+	}
+	return ioutil.WriteFile(mark, nil, 0644)
+}
+
+// unarchiveSourceData unarchives the source data to dest dir and returns the unarchived dir
+func unarchiveSourceData(file, destDir string) (string, error) {
+	unarchivePath := filepath.Join(destDir, strings.TrimSuffix(filepath.Base(file), constants.DefaultArchiveExtention))
+	if util.IsDirExist(unarchivePath) {
+		return unarchivePath, nil
+	}
+	if err := util.EnsureDirectoryExist(destDir); err != nil {
+		return "", err
+	}
+	tarGz := archiver.NewTarGz()
+	// overwrite if the file already exists
+	tarGz.OverwriteExisting = true
+	if err := tarGz.Unarchive(file, destDir); err != nil {
+		return "", fmt.Errorf("unarchive source data %s to %s failed, err: %v", file, destDir, err)
+	}
+	return unarchivePath, nil
+}
+
+// getLightningConfig returns the config of tidb-lightning, the config of the DataImport is merged into it
+func (do *DataImportOpts) getLightningConfig(di *v1alpha1.DataImport, sourceDir string) ([]byte, error) {
+	config := map[string]interface{}{
+		"lightning": map[string]interface{}{
+			"status-addr": constants.LightningStatusAddr,
+			"level":       "info",
+		},
+		"checkpoint": map[string]interface{}{
+			"enable": true,
+			"driver": "file",
+			"dsn":    filepath.Join(do.getDataImportDir(), "checkpoint.pb"),
+		},
+		"tikv-importer": map[string]interface{}{
+			"backend": string(di.GetBackend()),
+		},
+		"mydumper": map[string]interface{}{
+			"data-source-dir": sourceDir,
+		},
+		"tidb": map[string]interface{}{
+			"host":        do.TidbSvc,
+			"port":        4000,
+			"user":        do.User,
+			"password":    do.Password,
+			"status-port": 10080,
+			"pd-addr":     do.PDAddr,
+		},
+		"post-restore": map[string]interface{}{
+			"checksum": true,
+		},
+	}
+	if di.GetBackend() == v1alpha1.ImportBackendImporter {
+		config["tikv-importer"].(map[string]interface{})["addr"] = do.Importer
+	}
+	mergeConfig(config, di.Spec.Config)
+
+	buff := new(bytes.Buffer)
+	if err := toml.NewEncoder(buff).Encode(config); err != nil {
+		return nil, err
+	}
+	return buff.Bytes(), nil
+}
+
+// mergeConfig merges the config src into dst, the tables are merged recursively and the other values of src win
+func mergeConfig(dst, src map[string]interface{}) {
+	for k, v := range src {
+		srcTable, ok := v.(map[string]interface{})
+		dstTable, dstOk := dst[k].(map[string]interface{})
+		if ok && dstOk {
+			mergeConfig(dstTable, srcTable)
+			continue
+		}
+		dst[k] = v
+	}
+}
+
+// getProgress returns the percentage of the finished chunks from the metrics of tidb-lightning
+func getProgress() (string, error) {
+	resp, err := http.Get(fmt.Sprintf("http://127.0.0.1%s/metrics", constants.LightningStatusAddr))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("get tidb-lightning metrics failed, status code: %d", resp.StatusCode)
+	}
+	return parseProgress(resp.Body)
+}
+
+// parseProgress parses the lightning_chunks metrics, e.g.
+// lightning_chunks{state="estimated"} 100
+// lightning_chunks{state="finished"} 45
+func parseProgress(r io.Reader) (string, error) {
+	var finished, estimated float64
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "lightning_chunks{") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		value, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return "", fmt.Errorf("parse metric %q failed, err: %v", line, err)
+		}
+		switch {
+		case strings.Contains(fields[0], `state="finished"`):
+			finished = value
+		case strings.Contains(fields[0], `state="estimated"`):
+			estimated = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	if estimated == 0 {
+		return "", fmt.Errorf("the chunks of the source data are not estimated yet")
+	}
+	return formatProgress(finished / estimated * 100), nil
+}
+
+func formatProgress(percent float64) string {
+	return fmt.Sprintf("%.2f%%", percent)
+}
+
+// getImportedTables returns the tables in the form of `db`.`table` from the schema files of the source data
+func getImportedTables(sourceDir string) ([]string, error) {
+	var tables []string
+	err := filepath.Walk(sourceDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		name := info.Name()
+		if info.IsDir() || !strings.HasSuffix(name, schemaFileSuffix) || strings.HasSuffix(name, schemaCreateFileSuffix) {
+			return nil
+		}
+		parts := strings.SplitN(strings.TrimSuffix(name, schemaFileSuffix), ".", 2)
+		if len(parts) != 2 {
+			return nil
+		}
+		tables = append(tables, fmt.Sprintf("`%s`.`%s`", parts[0], parts[1]))
+		return nil
+	})
+	return tables, err
+}
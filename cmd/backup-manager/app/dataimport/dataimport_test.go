@@ -0,0 +1,229 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataimport
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/util/config"
+)
+
+func TestParseProgress(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name     string
+		metrics  string
+		progress string
+		err      bool
+	}
+
+	tests := []testcase{
+		{
+			name: "half finished",
+			metrics: `# HELP lightning_chunks count number of chunks processed
+# TYPE lightning_chunks gauge
+lightning_chunks{state="estimated"} 200
+lightning_chunks{state="pending"} 80
+lightning_chunks{state="finished"} 100
+lightning_engines{state="open"} 1`,
+			progress: "50.00%",
+		},
+		{
+			name: "all finished",
+			metrics: `lightning_chunks{state="finished"} 3
+lightning_chunks{state="estimated"} 3`,
+			progress: "100.00%",
+		},
+		{
+			name:     "nothing finished",
+			metrics:  `lightning_chunks{state="estimated"} 3`,
+			progress: "0.00%",
+		},
+		{
+			name:    "not estimated",
+			metrics: `lightning_chunks{state="finished"} 3`,
+			err:     true,
+		},
+		{
+			name:    "no metrics",
+			metrics: "",
+			err:     true,
+		},
+		{
+			name:    "invalid value",
+			metrics: `lightning_chunks{state="estimated"} abc`,
+			err:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Log(test.name)
+		progress, err := parseProgress(strings.NewReader(test.metrics))
+		if test.err {
+			g.Expect(err).To(HaveOccurred())
+			continue
+		}
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(progress).To(Equal(test.progress))
+	}
+}
+
+func TestGetImportedTables(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name   string
+		files  []string
+		tables []string
+	}
+
+	tests := []testcase{
+		{
+			name: "mydumper source",
+			files: []string{
+				"metadata",
+				"db1-schema-create.sql",
+				"db1.t1-schema.sql",
+				"db1.t1.000000001.sql",
+				"db1.t2-schema.sql",
+				"db2-schema-create.sql",
+				"db2.t1-schema.sql",
+			},
+			tables: []string{"`db1`.`t1`", "`db1`.`t2`", "`db2`.`t1`"},
+		},
+		{
+			name:   "schema files in sub dirs",
+			files:  []string{"part1/db1.t1-schema.sql", "part2/db1.t2-schema.sql"},
+			tables: []string{"`db1`.`t1`", "`db1`.`t2`"},
+		},
+		{
+			name:   "no schema files",
+			files:  []string{"metadata", "db1-schema-create.sql", "invalid-schema.sql"},
+			tables: nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Log(test.name)
+		dir, err := ioutil.TempDir("", "dataimport")
+		g.Expect(err).NotTo(HaveOccurred())
+		for _, file := range test.files {
+			path := filepath.Join(dir, file)
+			g.Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+			g.Expect(ioutil.WriteFile(path, nil, 0644)).To(Succeed())
+		}
+
+		tables, err := getImportedTables(dir)
+		os.RemoveAll(dir)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(tables).To(Equal(test.tables))
+	}
+
+	_, err := getImportedTables("/not/exist")
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestGetLightningConfig(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name     string
+		backend  v1alpha1.ImportBackend
+		config   map[string]interface{}
+		expectFn func(rendered map[string]interface{})
+	}
+
+	tests := []testcase{
+		{
+			name: "default backend",
+			expectFn: func(rendered map[string]interface{}) {
+				g.Expect(rendered["tikv-importer"]).To(Equal(map[string]interface{}{
+					"backend": "importer",
+					"addr":    "demo-importer:8287",
+				}))
+				g.Expect(rendered["mydumper"]).To(Equal(map[string]interface{}{"data-source-dir": "/data/source"}))
+				g.Expect(rendered["checkpoint"]).To(HaveKeyWithValue("dsn", "/backup/ns-demo-import/checkpoint.pb"))
+				g.Expect(rendered["tidb"]).To(HaveKeyWithValue("host", "demo-tidb"))
+				g.Expect(rendered["tidb"]).To(HaveKeyWithValue("pd-addr", "demo-pd:2379"))
+				g.Expect(rendered["post-restore"]).To(HaveKeyWithValue("checksum", true))
+			},
+		},
+		{
+			name:    "tidb backend",
+			backend: v1alpha1.ImportBackendTiDB,
+			expectFn: func(rendered map[string]interface{}) {
+				g.Expect(rendered["tikv-importer"]).To(Equal(map[string]interface{}{"backend": "tidb"}))
+			},
+		},
+		{
+			name: "merge the config of the DataImport",
+			config: map[string]interface{}{
+				"lightning": map[string]interface{}{
+					"level":              "debug",
+					"region-concurrency": int64(4),
+				},
+				"post-restore": map[string]interface{}{
+					"checksum": false,
+				},
+				"black-white-list": map[string]interface{}{
+					"do-dbs": []interface{}{"db1"},
+				},
+			},
+			expectFn: func(rendered map[string]interface{}) {
+				g.Expect(rendered["lightning"]).To(Equal(map[string]interface{}{
+					"status-addr":        ":8289",
+					"level":              "debug",
+					"region-concurrency": int64(4),
+				}))
+				g.Expect(rendered["post-restore"]).To(HaveKeyWithValue("checksum", false))
+				g.Expect(rendered["black-white-list"]).To(Equal(map[string]interface{}{"do-dbs": []interface{}{"db1"}}))
+				g.Expect(rendered["tidb"]).To(HaveKeyWithValue("host", "demo-tidb"))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Log(test.name)
+		do := &DataImportOpts{
+			Namespace:      "ns",
+			TcName:         "demo",
+			TidbSvc:        "demo-tidb",
+			User:           "root",
+			DataImportName: "demo-import",
+			PDAddr:         "demo-pd:2379",
+			Importer:       "demo-importer:8287",
+		}
+		di := &v1alpha1.DataImport{
+			Spec: v1alpha1.DataImportSpec{
+				Backend:       test.backend,
+				GenericConfig: config.New(test.config),
+			},
+		}
+
+		data, err := do.getLightningConfig(di, "/data/source")
+		g.Expect(err).NotTo(HaveOccurred())
+		rendered := map[string]interface{}{}
+		_, err = toml.Decode(string(data), &rendered)
+		g.Expect(err).NotTo(HaveOccurred())
+		test.expectFn(rendered)
+	}
+}
@@ -0,0 +1,229 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataimport
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pingcap/tidb-operator/cmd/backup-manager/app/constants"
+	"github.com/pingcap/tidb-operator/cmd/backup-manager/app/util"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	listers "github.com/pingcap/tidb-operator/pkg/client/listers/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	glog "k8s.io/klog"
+)
+
+// DataImportManager mainly used to manage data import related work
+type DataImportManager struct {
+	diLister      listers.DataImportLister
+	StatusUpdater controller.DataImportStatusUpdaterInterface
+	DataImportOpts
+}
+
+// NewDataImportManager return a DataImportManager
+func NewDataImportManager(
+	diLister listers.DataImportLister,
+	statusUpdater controller.DataImportStatusUpdaterInterface,
+	diOpts DataImportOpts) *DataImportManager {
+	return &DataImportManager{
+		diLister,
+		statusUpdater,
+		diOpts,
+	}
+}
+
+// ProcessDataImport used to process the data import logic
+func (dm *DataImportManager) ProcessDataImport() error {
+	di, err := dm.diLister.DataImports(dm.Namespace).Get(dm.DataImportName)
+	if err != nil {
+		glog.Errorf("can't find cluster %s data import %s CRD object, err: %v", dm, dm.DataImportName, err)
+		return err
+	}
+	di = di.DeepCopy()
+
+	err = wait.PollImmediate(constants.PollInterval, constants.CheckTimeout, func() (done bool, err error) {
+		db, err := util.OpenDB(dm.getDSN(constants.TidbMetaDB))
+		if err != nil {
+			glog.Warningf("can't open connection to tidb cluster %s, err: %v", dm, err)
+			return false, nil
+		}
+
+		if err := db.Ping(); err != nil {
+			glog.Warningf("can't connect to tidb cluster %s, err: %s", dm, err)
+			return false, nil
+		}
+		db.Close()
+		return true, nil
+	})
+
+	if err != nil {
+		glog.Errorf("cluster %s connect failed, err: %s", dm, err)
+		return dm.StatusUpdater.Update(di, &v1alpha1.DataImportCondition{
+			Type:    v1alpha1.DataImportFailed,
+			Status:  corev1.ConditionTrue,
+			Reason:  "ConnectTidbFailed",
+			Message: err.Error(),
+		})
+	}
+
+	return dm.performDataImport(di)
+}
+
+func (dm *DataImportManager) performDataImport(di *v1alpha1.DataImport) error {
+	di.Status.TimeStarted = metav1.Time{Time: time.Now()}
+	err := dm.StatusUpdater.Update(di, &v1alpha1.DataImportCondition{
+		Type:   v1alpha1.DataImportRunning,
+		Status: corev1.ConditionTrue,
+	})
+	if err != nil {
+		return err
+	}
+
+	sourceDir, err := dm.prepareSourceData(di)
+	if err != nil {
+		glog.Errorf("prepare cluster %s source data %s failed, err: %s", dm, di.Spec.Path, err)
+		return dm.StatusUpdater.Update(di, &v1alpha1.DataImportCondition{
+			Type:    v1alpha1.DataImportFailed,
+			Status:  corev1.ConditionTrue,
+			Reason:  "PrepareSourceDataFailed",
+			Message: fmt.Sprintf("prepare source data %s failed, err: %v", di.Spec.Path, err),
+		})
+	}
+	glog.Infof("prepare cluster %s source data %s success", dm, di.Spec.Path)
+
+	if err := dm.runLightning(di, sourceDir); err != nil {
+		glog.Errorf("import cluster %s source data %s failed, err: %s", dm, di.Spec.Path, err)
+		return dm.StatusUpdater.Update(di, &v1alpha1.DataImportCondition{
+			Type:    v1alpha1.DataImportFailed,
+			Status:  corev1.ConditionTrue,
+			Reason:  "LightningFailed",
+			Message: fmt.Sprintf("import source data %s failed, err: %v", di.Spec.Path, err),
+		})
+	}
+	glog.Infof("import cluster %s source data %s success", dm, di.Spec.Path)
+
+	checksums, err := dm.getChecksums(sourceDir)
+	if err != nil {
+		glog.Errorf("get cluster %s imported tables checksum failed, err: %s", dm, err)
+		return dm.StatusUpdater.Update(di, &v1alpha1.DataImportCondition{
+			Type:    v1alpha1.DataImportFailed,
+			Status:  corev1.ConditionTrue,
+			Reason:  "GetChecksumFailed",
+			Message: fmt.Sprintf("get imported tables checksum failed, err: %v", err),
+		})
+	}
+
+	di.Status.Checksums = checksums
+	di.Status.Progress = formatProgress(100)
+	di.Status.TimeCompleted = metav1.Time{Time: time.Now()}
+	return dm.StatusUpdater.Update(di, &v1alpha1.DataImportCondition{
+		Type:   v1alpha1.DataImportComplete,
+		Status: corev1.ConditionTrue,
+	})
+}
+
+// runLightning runs tidb-lightning and updates the progress of the data import until tidb-lightning exits
+func (dm *DataImportManager) runLightning(di *v1alpha1.DataImport, sourceDir string) error {
+	config, err := dm.getLightningConfig(di, sourceDir)
+	if err != nil {
+		return fmt.Errorf("generate tidb-lightning config failed, err: %v", err)
+	}
+	configFile := filepath.Join(dm.getDataImportDir(), "tidb-lightning.toml")
+	if err := util.EnsureDirectoryExist(filepath.Dir(configFile)); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(configFile, config, 0600); err != nil {
+		return fmt.Errorf("write tidb-lightning config %s failed, err: %v", configFile, err)
+	}
+
+	lightning := exec.Command(constants.LightningBin, fmt.Sprintf("--config=%s", configFile))
+	lightning.Stdout = os.Stdout
+	lightning.Stderr = os.Stderr
+	if err := lightning.Start(); err != nil {
+		return fmt.Errorf("cluster %s, start tidb-lightning failed, err: %v", dm, err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- lightning.Wait()
+	}()
+
+	ticker := time.NewTicker(constants.ProgressInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case err := <-done:
+			if err != nil {
+				return fmt.Errorf("cluster %s, execute tidb-lightning failed, err: %v", dm, err)
+			}
+			return nil
+		case <-ticker.C:
+			progress, err := getProgress()
+			if err != nil {
+				glog.Warningf("get cluster %s data import progress failed, err: %v", dm, err)
+				continue
+			}
+			if progress == di.Status.Progress {
+				continue
+			}
+			di.Status.Progress = progress
+			if err := dm.StatusUpdater.Update(di, nil); err != nil {
+				glog.Warningf("update cluster %s data import progress to %s failed, err: %v", dm, progress, err)
+			}
+		}
+	}
+}
+
+// getChecksums returns the checksums of the imported tables computed by tidb
+func (dm *DataImportManager) getChecksums(sourceDir string) ([]v1alpha1.ImportTableChecksum, error) {
+	tables, err := getImportedTables(sourceDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(tables) == 0 {
+		return nil, nil
+	}
+
+	db, err := util.OpenDB(dm.getDSN(constants.TidbMetaDB))
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	var checksums []v1alpha1.ImportTableChecksum
+	for _, table := range tables {
+		var dbName, tableName, checksum string
+		var totalKVs, totalBytes int64
+		row := db.QueryRow(fmt.Sprintf("ADMIN CHECKSUM TABLE %s", table))
+		if err := row.Scan(&dbName, &tableName, &checksum, &totalKVs, &totalBytes); err != nil {
+			return nil, fmt.Errorf("cluster %s, checksum table %s failed, err: %v", dm, table, err)
+		}
+		checksums = append(checksums, v1alpha1.ImportTableChecksum{
+			Table:      strings.Join([]string{dbName, tableName}, "."),
+			Checksum:   checksum,
+			TotalKVs:   totalKVs,
+			TotalBytes: totalBytes,
+		})
+	}
+	return checksums, nil
+}
@@ -8,9 +8,11 @@ import (
 	"time"
 
 	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned"
+	"github.com/pingcap/tidb-operator/pkg/discovery"
 	"github.com/pingcap/tidb-operator/pkg/discovery/server"
 	"github.com/pingcap/tidb-operator/pkg/version"
 	"k8s.io/apimachinery/pkg/util/wait"
+	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/component-base/logs"
@@ -52,8 +54,18 @@ func main() {
 		glog.Fatalf("failed to get kubernetes Clientset: %v", err)
 	}
 
+	// the discovery only reads the Secrets in its own namespace
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(kubeCli, 0, kubeinformers.WithNamespace(os.Getenv("MY_POD_NAMESPACE")))
+	td := discovery.NewTiDBDiscovery(cli, kubeInformerFactory.Core().V1().Secrets())
+	kubeInformerFactory.Start(wait.NeverStop)
+	for v, synced := range kubeInformerFactory.WaitForCacheSync(wait.NeverStop) {
+		if !synced {
+			glog.Fatalf("error syncing informer for %v", v)
+		}
+	}
+
 	go wait.Forever(func() {
-		server.StartServer(cli, kubeCli, port)
+		server.StartServer(td, port)
 	}, 5*time.Second)
 	glog.Fatal(http.ListenAndServe(":6060", nil))
 }
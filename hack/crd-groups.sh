@@ -40,6 +40,9 @@ function generate_crd {
 	$1/bin/to-crdgen generate backup >> $2
 	$1/bin/to-crdgen generate restore >> $2
 	$1/bin/to-crdgen generate backupschedule >> $2
+	$1/bin/to-crdgen generate dataimport >> $2
+	$1/bin/to-crdgen generate tidbmonitor >> $2
+	$1/bin/to-crdgen generate tidbclusterautoscaler >> $2
 }
 
 if test $ACTION == 'generate' ;then
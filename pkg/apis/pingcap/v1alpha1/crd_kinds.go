@@ -37,6 +37,18 @@ const (
 	BackupScheduleKind    = "BackupSchedule"
 	BackupScheduleKindKey = "backupschedule"
 
+	DataImportName    = "dataimports"
+	DataImportKind    = "DataImport"
+	DataImportKindKey = "dataimport"
+
+	TiDBMonitorName    = "tidbmonitors"
+	TiDBMonitorKind    = "TidbMonitor"
+	TiDBMonitorKindKey = "tidbmonitor"
+
+	TidbClusterAutoScalerName    = "tidbclusterautoscalers"
+	TidbClusterAutoScalerKind    = "TidbClusterAutoScaler"
+	TidbClusterAutoScalerKindKey = "tidbclusterautoscaler"
+
 	SpecPath = "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1."
 )
 
@@ -49,17 +61,23 @@ type CrdKind struct {
 }
 
 type CrdKinds struct {
-	KindsString    string
-	TiDBCluster    CrdKind
-	Backup         CrdKind
-	Restore        CrdKind
-	BackupSchedule CrdKind
+	KindsString           string
+	TiDBCluster           CrdKind
+	Backup                CrdKind
+	Restore               CrdKind
+	BackupSchedule        CrdKind
+	DataImport            CrdKind
+	TiDBMonitor           CrdKind
+	TidbClusterAutoScaler CrdKind
 }
 
 var DefaultCrdKinds = CrdKinds{
-	KindsString:    "",
-	TiDBCluster:    CrdKind{Plural: TiDBClusterName, Kind: TiDBClusterKind, ShortNames: []string{"tc"}, SpecName: SpecPath + TiDBClusterKind},
-	Backup:         CrdKind{Plural: BackupName, Kind: BackupKind, ShortNames: []string{"bk"}, SpecName: SpecPath + BackupKind},
-	Restore:        CrdKind{Plural: RestoreName, Kind: RestoreKind, ShortNames: []string{"rt"}, SpecName: SpecPath + RestoreKind},
-	BackupSchedule: CrdKind{Plural: BackupScheduleName, Kind: BackupScheduleKind, ShortNames: []string{"bks"}, SpecName: SpecPath + BackupScheduleKind},
+	KindsString:           "",
+	TiDBCluster:           CrdKind{Plural: TiDBClusterName, Kind: TiDBClusterKind, ShortNames: []string{"tc"}, SpecName: SpecPath + TiDBClusterKind},
+	Backup:                CrdKind{Plural: BackupName, Kind: BackupKind, ShortNames: []string{"bk"}, SpecName: SpecPath + BackupKind},
+	Restore:               CrdKind{Plural: RestoreName, Kind: RestoreKind, ShortNames: []string{"rt"}, SpecName: SpecPath + RestoreKind},
+	BackupSchedule:        CrdKind{Plural: BackupScheduleName, Kind: BackupScheduleKind, ShortNames: []string{"bks"}, SpecName: SpecPath + BackupScheduleKind},
+	DataImport:            CrdKind{Plural: DataImportName, Kind: DataImportKind, ShortNames: []string{"di"}, SpecName: SpecPath + DataImportKind},
+	TiDBMonitor:           CrdKind{Plural: TiDBMonitorName, Kind: TiDBMonitorKind, ShortNames: []string{"tm"}, SpecName: SpecPath + TiDBMonitorKind},
+	TidbClusterAutoScaler: CrdKind{Plural: TidbClusterAutoScalerName, Kind: TidbClusterAutoScalerKind, ShortNames: []string{"ta"}, SpecName: SpecPath + TidbClusterAutoScalerKind},
 }
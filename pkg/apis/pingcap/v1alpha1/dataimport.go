@@ -0,0 +1,105 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetDataImportJobName return the import job name
+func (im *DataImport) GetDataImportJobName() string {
+	return fmt.Sprintf("import-%s", im.GetName())
+}
+
+// GetDataImportPVCName return the import pvc name, the pvc is kept across the retries of the import
+// so that tidb-lightning resumes from its checkpoint
+func (im *DataImport) GetDataImportPVCName() string {
+	return fmt.Sprintf("import-%s-pvc", im.GetName())
+}
+
+// GetBackend returns the backend of tidb-lightning
+func (im *DataImport) GetBackend() ImportBackend {
+	if im.Spec.Backend == "" {
+		return ImportBackendImporter
+	}
+	return im.Spec.Backend
+}
+
+// GetDataImportCondition get the specify type's DataImportCondition from the given DataImportStatus
+func GetDataImportCondition(status *DataImportStatus, conditionType DataImportConditionType) (int, *DataImportCondition) {
+	if status == nil {
+		return -1, nil
+	}
+	for i := range status.Conditions {
+		if status.Conditions[i].Type == conditionType {
+			return i, &status.Conditions[i]
+		}
+	}
+	return -1, nil
+}
+
+// UpdateDataImportCondition updates existing DataImport condition or creates a new
+// one. Sets LastTransitionTime to now if the status has changed.
+// Returns true if DataImport condition has changed or has been added.
+func UpdateDataImportCondition(status *DataImportStatus, condition *DataImportCondition) bool {
+	condition.LastTransitionTime = metav1.Now()
+	// Try to find this DataImport condition.
+	conditionIndex, oldCondition := GetDataImportCondition(status, condition.Type)
+
+	if oldCondition == nil {
+		// We are adding new DataImport condition.
+		status.Conditions = append(status.Conditions, *condition)
+		return true
+	}
+	// We are updating an existing condition, so we need to check if it has changed.
+	if condition.Status == oldCondition.Status {
+		condition.LastTransitionTime = oldCondition.LastTransitionTime
+	}
+
+	isUpdate := condition.Status == oldCondition.Status &&
+		condition.Reason == oldCondition.Reason &&
+		condition.Message == oldCondition.Message &&
+		condition.LastTransitionTime.Equal(&oldCondition.LastTransitionTime)
+
+	status.Conditions[conditionIndex] = *condition
+	// Return true if one of the fields have changed.
+	return !isUpdate
+}
+
+// IsDataImportComplete returns true if a DataImport has successfully completed
+func IsDataImportComplete(im *DataImport) bool {
+	_, condition := GetDataImportCondition(&im.Status, DataImportComplete)
+	return condition != nil && condition.Status == corev1.ConditionTrue
+}
+
+// IsDataImportFailed returns true if a DataImport has failed
+func IsDataImportFailed(im *DataImport) bool {
+	_, condition := GetDataImportCondition(&im.Status, DataImportFailed)
+	return condition != nil && condition.Status == corev1.ConditionTrue
+}
+
+// IsDataImportScheduled returns true if a DataImport has successfully scheduled
+func IsDataImportScheduled(im *DataImport) bool {
+	_, condition := GetDataImportCondition(&im.Status, DataImportScheduled)
+	return condition != nil && condition.Status == corev1.ConditionTrue
+}
+
+// IsTiKVInImportMode returns true if the TiKV stores have been switched to import mode for the DataImport
+func IsTiKVInImportMode(im *DataImport) bool {
+	_, condition := GetDataImportCondition(&im.Status, DataImportTiKVImportMode)
+	return condition != nil && condition.Status == corev1.ConditionTrue
+}
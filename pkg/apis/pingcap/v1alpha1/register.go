@@ -58,6 +58,12 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&BackupScheduleList{},
 		&Restore{},
 		&RestoreList{},
+		&DataImport{},
+		&DataImportList{},
+		&TidbMonitor{},
+		&TidbMonitorList{},
+		&TidbClusterAutoScaler{},
+		&TidbClusterAutoScalerList{},
 		&DataResource{},
 		&DataResourceList{},
 	)
@@ -15,13 +15,24 @@ package v1alpha1
 
 import (
 	"fmt"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 const (
 	// defaultHelperImage is default image of helper
 	defaultHelperImage = "busybox:1.26.2"
+	// defaultUpgradeHealthWindow is default health window of a staged upgrade
+	defaultUpgradeHealthWindow = time.Minute
+	// defaultEvictLeaderTimeout is default timeout of evicting leaders from a TiKV store
+	defaultEvictLeaderTimeout = 3 * time.Minute
+	// defaultTiDBDrainTimeout is default timeout of draining the connections of a TiDB server
+	defaultTiDBDrainTimeout = time.Minute
+	// defaultTLSCertRenewBefore is default time before expiry to renew the TLS certificates
+	defaultTLSCertRenewBefore = 30 * 24 * time.Hour
 )
 
 // ComponentAccessor is the interface to access component details, which respects the cluster-level properties
@@ -169,6 +180,19 @@ func (tc *TidbCluster) BasePumpSpec() (ComponentAccessor, bool) {
 	return &componentAccessorImpl{&tc.Spec, &tc.Spec.Pump.ComponentSpec}, true
 }
 
+// BaseDrainerSpec returns the base spec of the drainer
+func (tc *TidbCluster) BaseDrainerSpec(spec *DrainerSpec) ComponentAccessor {
+	return &componentAccessorImpl{&tc.Spec, &spec.ComponentSpec}
+}
+
+// BaseTiFlashSpec returns the base spec of TiFlash servers and whether TiFlash is deployed
+func (tc *TidbCluster) BaseTiFlashSpec() (ComponentAccessor, bool) {
+	if tc.Spec.TiFlash == nil {
+		return nil, false
+	}
+	return &componentAccessorImpl{&tc.Spec, &tc.Spec.TiFlash.ComponentSpec}, true
+}
+
 func (tc *TidbCluster) HelperImage() string {
 	image := tc.Spec.Helper.Image
 	if image == "" {
@@ -193,6 +217,87 @@ func (tc *TidbCluster) HelperImagePullPolicy() corev1.PullPolicy {
 	return *pp
 }
 
+// Staged returns whether the upgrade pauses after a canary
+func (us *UpgradeStrategy) Staged() bool {
+	return us != nil && us.Canary != nil
+}
+
+// CanaryReplicas returns the number of pods upgraded before the upgrade pauses, a percentage is rounded up
+func (us *UpgradeStrategy) CanaryReplicas(replicas int32) int32 {
+	if !us.Staged() {
+		return replicas
+	}
+	canary, err := intstr.GetValueFromIntOrPercent(us.Canary, int(replicas), true)
+	if err != nil || canary < 0 {
+		return 0
+	}
+	if canary > int(replicas) {
+		return replicas
+	}
+	return int32(canary)
+}
+
+// HealthWindow returns how long the canary pods must stay healthy
+func (us *UpgradeStrategy) HealthWindow() time.Duration {
+	if us == nil || us.HealthWindowSeconds == nil {
+		return defaultUpgradeHealthWindow
+	}
+	return time.Duration(*us.HealthWindowSeconds) * time.Second
+}
+
+// Timeout returns the longest time to wait for the connections to drain
+func (ds *TiDBDrainSpec) Timeout() time.Duration {
+	if ds == nil || ds.TimeoutSeconds == nil {
+		return defaultTiDBDrainTimeout
+	}
+	return time.Duration(*ds.TimeoutSeconds) * time.Second
+}
+
+// HealthDeadline returns how long an upgraded pod may stay unhealthy and whether the deadline is set
+func (us *UpgradeStrategy) HealthDeadline() (time.Duration, bool) {
+	if us == nil || us.HealthDeadlineSeconds == nil {
+		return 0, false
+	}
+	return time.Duration(*us.HealthDeadlineSeconds) * time.Second, true
+}
+
+// GetTidbClusterCondition get the specify type's TidbClusterCondition from the given TidbClusterStatus
+func GetTidbClusterCondition(status *TidbClusterStatus, conditionType TidbClusterConditionType) (int, *TidbClusterCondition) {
+	if status == nil {
+		return -1, nil
+	}
+	for i := range status.Conditions {
+		if status.Conditions[i].Type == conditionType {
+			return i, &status.Conditions[i]
+		}
+	}
+	return -1, nil
+}
+
+// UpdateTidbClusterCondition updates existing TidbCluster condition or creates a new
+// one. Sets LastTransitionTime to now if the status has changed.
+// Returns true if TidbCluster condition has changed or has been added.
+func UpdateTidbClusterCondition(status *TidbClusterStatus, condition *TidbClusterCondition) bool {
+	condition.LastTransitionTime = metav1.Now()
+	conditionIndex, oldCondition := GetTidbClusterCondition(status, condition.Type)
+
+	if oldCondition == nil {
+		status.Conditions = append(status.Conditions, *condition)
+		return true
+	}
+	if condition.Status == oldCondition.Status {
+		condition.LastTransitionTime = oldCondition.LastTransitionTime
+	}
+
+	isUpdate := condition.Status == oldCondition.Status &&
+		condition.Reason == oldCondition.Reason &&
+		condition.Message == oldCondition.Message &&
+		condition.LastTransitionTime.Equal(&oldCondition.LastTransitionTime)
+
+	status.Conditions[conditionIndex] = *condition
+	return !isUpdate
+}
+
 func (mt MemberType) String() string {
 	return string(mt)
 }
@@ -205,6 +310,16 @@ func (tc *TidbCluster) TiKVUpgrading() bool {
 	return tc.Status.TiKV.Phase == UpgradePhase
 }
 
+// TiKVGroupUpgrading returns whether any of the TiKV groups is upgrading
+func (tc *TidbCluster) TiKVGroupUpgrading() bool {
+	for _, status := range tc.Status.TiKVGroups {
+		if status.Phase == UpgradePhase {
+			return true
+		}
+	}
+	return false
+}
+
 func (tc *TidbCluster) TiDBUpgrading() bool {
 	return tc.Status.TiDB.Phase == UpgradePhase
 }
@@ -270,7 +385,12 @@ func (tc *TidbCluster) TiKVAllStoresReady() bool {
 }
 
 func (tc *TidbCluster) TiKVStsDesiredReplicas() int32 {
-	return tc.Spec.TiKV.Replicas + int32(len(tc.Status.TiKV.FailureStores))
+	replicas := tc.Spec.TiKV.Replicas + int32(len(tc.Status.TiKV.FailureStores))
+	// a replacement store is added while the stores are migrated to another StorageClass
+	if tc.Status.TiKV.Migration != nil {
+		replicas++
+	}
+	return replicas
 }
 
 func (tc *TidbCluster) TiKVStsActualReplicas() int32 {
@@ -281,6 +401,94 @@ func (tc *TidbCluster) TiKVStsActualReplicas() int32 {
 	return stsStatus.Replicas
 }
 
+// TiKVEvictLeaderTimeout returns the timeout of evicting leaders from a TiKV store,
+// the default timeout is returned if spec.tikv.evictLeaderTimeout is not a valid duration
+func (tc *TidbCluster) TiKVEvictLeaderTimeout() time.Duration {
+	if tc.Spec.TiKV.EvictLeaderTimeout != nil {
+		if d, err := time.ParseDuration(*tc.Spec.TiKV.EvictLeaderTimeout); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultEvictLeaderTimeout
+}
+
+// TLSCertRenewBefore returns how long before expiry the TLS certificates are renewed,
+// the default is returned if spec.tlsCertRenewBefore is not a valid duration
+func (tc *TidbCluster) TLSCertRenewBefore() time.Duration {
+	if tc.Spec.TLSCertRenewBefore != nil {
+		if d, err := time.ParseDuration(*tc.Spec.TLSCertRenewBefore); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultTLSCertRenewBefore
+}
+
+// TLSIssuerType returns the type of the issuer of the TLS certificates
+func (tc *TidbCluster) TLSIssuerType() TLSIssuerType {
+	if tc.Spec.TLSIssuer == nil || tc.Spec.TLSIssuer.Type == "" {
+		return TLSIssuerCSR
+	}
+	return tc.Spec.TLSIssuer.Type
+}
+
+// TLSCASecretName returns the name of the Secret holding the CA of the SelfSignedCA issuer
+func (tc *TidbCluster) TLSCASecretName() string {
+	if tc.Spec.TLSIssuer != nil && tc.Spec.TLSIssuer.CASecretName != "" {
+		return tc.Spec.TLSIssuer.CASecretName
+	}
+	return fmt.Sprintf("%s-ca", tc.Name)
+}
+
+// ClusterRef returns the reference to the TidbCluster whose PD cluster tc joins with the namespace defaulted,
+// nil is returned if tc does not join another cluster
+func (tc *TidbCluster) ClusterRef() *TidbClusterRef {
+	if tc.Spec.Cluster == nil {
+		return nil
+	}
+	ref := *tc.Spec.Cluster
+	if ref.Namespace == "" {
+		ref.Namespace = tc.Namespace
+	}
+	return &ref
+}
+
+func (tc *TidbCluster) TiFlashUpgrading() bool {
+	return tc.Status.TiFlash.Phase == UpgradePhase
+}
+
+func (tc *TidbCluster) TiFlashAllPodsStarted() bool {
+	return tc.TiFlashStsDesiredReplicas() == tc.TiFlashStsActualReplicas()
+}
+
+func (tc *TidbCluster) TiFlashAllStoresReady() bool {
+	if int(tc.TiFlashStsDesiredReplicas()) != len(tc.Status.TiFlash.Stores) {
+		return false
+	}
+
+	for _, store := range tc.Status.TiFlash.Stores {
+		if store.State != TiKVStateUp {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (tc *TidbCluster) TiFlashStsDesiredReplicas() int32 {
+	if tc.Spec.TiFlash == nil {
+		return 0
+	}
+	return tc.Spec.TiFlash.Replicas + int32(len(tc.Status.TiFlash.FailureStores))
+}
+
+func (tc *TidbCluster) TiFlashStsActualReplicas() int32 {
+	stsStatus := tc.Status.TiFlash.StatefulSet
+	if stsStatus == nil {
+		return 0
+	}
+	return stsStatus.Replicas
+}
+
 func (tc *TidbCluster) TiDBAllPodsStarted() bool {
 	return tc.TiDBStsDesiredReplicas() == tc.TiDBStsActualReplicas()
 }
@@ -369,3 +577,9 @@ func (tc *TidbCluster) Scheme() string {
 	}
 	return "http"
 }
+
+// HATopologyKey returns the node label which the PD and TiKV pods are spread across by the HA predicate of
+// tidb-scheduler, it's empty unless spec.haTopologyKey is set, then the pods are spread across the nodes
+func (tc *TidbCluster) HATopologyKey() string {
+	return tc.Spec.HATopologyKey
+}
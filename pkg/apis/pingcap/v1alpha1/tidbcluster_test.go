@@ -15,6 +15,7 @@ package v1alpha1
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	apps "k8s.io/api/apps/v1"
@@ -164,6 +165,60 @@ func TestTiKVIsAvailable(t *testing.T) {
 	}
 }
 
+func TestTiKVEvictLeaderTimeout(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name    string
+		timeout *string
+		expect  time.Duration
+	}
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+
+		tc := newTidbCluster()
+		tc.Spec.TiKV.EvictLeaderTimeout = test.timeout
+		g.Expect(tc.TiKVEvictLeaderTimeout()).To(Equal(test.expect))
+	}
+	timeout := "10m"
+	invalid := "ten minutes"
+	tests := []testcase{
+		{
+			name:    "timeout is not set",
+			timeout: nil,
+			expect:  3 * time.Minute,
+		},
+		{
+			name:    "timeout is set",
+			timeout: &timeout,
+			expect:  10 * time.Minute,
+		},
+		{
+			name:    "timeout is invalid",
+			timeout: &invalid,
+			expect:  3 * time.Minute,
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
+
+func TestTLSCertRenewBefore(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbCluster()
+	g.Expect(tc.TLSCertRenewBefore()).To(Equal(30 * 24 * time.Hour))
+
+	renewBefore := "240h"
+	tc.Spec.TLSCertRenewBefore = &renewBefore
+	g.Expect(tc.TLSCertRenewBefore()).To(Equal(10 * 24 * time.Hour))
+
+	renewBefore = "-1h"
+	g.Expect(tc.TLSCertRenewBefore()).To(Equal(30 * 24 * time.Hour))
+}
+
 func TestComponentAccessor(t *testing.T) {
 	g := NewGomegaWithT(t)
 
@@ -470,6 +525,22 @@ func TestHelperImagePullPolicy(t *testing.T) {
 	}
 }
 
+func TestHATopologyKey(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbCluster()
+	g.Expect(tc.HATopologyKey()).To(Equal(""))
+
+	tc.Spec.TiKV.LocationLabels = []StoreLabel{{Name: "zone", NodeLabel: "topology.kubernetes.io/zone"}, {Name: "host"}}
+	g.Expect(tc.HATopologyKey()).To(Equal(""))
+	// the isolation level doesn't spread the pods across the topology domains without an explicit key
+	tc.Spec.TiKV.IsolationLevel = "zone"
+	g.Expect(tc.HATopologyKey()).To(Equal(""))
+
+	tc.Spec.HATopologyKey = "rack"
+	g.Expect(tc.HATopologyKey()).To(Equal("rack"))
+}
+
 func newTidbCluster() *TidbCluster {
 	return &TidbCluster{
 		TypeMeta: metav1.TypeMeta{
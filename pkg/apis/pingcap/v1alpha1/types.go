@@ -19,6 +19,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 const (
@@ -42,6 +43,12 @@ const (
 	TiDBMemberType MemberType = "tidb"
 	// TiKVMemberType is tikv container type
 	TiKVMemberType MemberType = "tikv"
+	// TiFlashMemberType is tiflash container type
+	TiFlashMemberType MemberType = "tiflash"
+	// PumpMemberType is pump container type
+	PumpMemberType MemberType = "pump"
+	// DrainerMemberType is drainer container type
+	DrainerMemberType MemberType = "drainer"
 	// SlowLogTailerMemberType is tidb log tailer container type
 	SlowLogTailerMemberType MemberType = "slowlog"
 	// UnknownMemberType is unknown container type
@@ -69,6 +76,21 @@ const (
 	ConfigUpdateStrategyRollingUpdate ConfigUpdateStrategy = "RollingUpdate"
 )
 
+// TLSIssuerType represents how the TLS certificates of a cluster are issued
+type TLSIssuerType string
+
+const (
+	// TLSIssuerCSR signs the certificates through the Kubernetes CSR API with the cluster CA
+	TLSIssuerCSR TLSIssuerType = "CSR"
+	// TLSIssuerSelfSignedCA signs the certificates with a CA stored in a Secret, which is generated
+	// by tidb-operator if the Secret does not exist
+	TLSIssuerSelfSignedCA TLSIssuerType = "SelfSignedCA"
+	// TLSIssuerSecret uses the certificates provided by the user in Secrets of the kubernetes.io/tls type
+	TLSIssuerSecret TLSIssuerType = "Secret"
+	// TLSIssuerCertManager requests the certificates by cert-manager Certificate resources
+	TLSIssuerCertManager TLSIssuerType = "CertManager"
+)
+
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
@@ -109,12 +131,28 @@ type TidbClusterSpec struct {
 	// TiDB cluster spec
 	TiDB TiDBSpec `json:"tidb,omitempty"`
 
+	// TiDBGroups are the groups of TiDB servers besides spec.tidb, each of which runs in its own StatefulSet
+	// behind its own Service with its own replicas, resources and config, e.g. a group serving the application
+	// and a group serving the reporting queries
+	TiDBGroups []TiDBGroupSpec `json:"tidbGroups,omitempty"`
+
 	// TiKV cluster spec
 	TiKV TiKVSpec `json:"tikv,omitempty"`
 
+	// TiKVGroups are the groups of TiKV stores besides spec.tikv, each of which runs in its own StatefulSet
+	// with its own replicas, resources, storage and PD store labels, e.g. a hot group on NVMe disks and a
+	// cold group on HDDs. A group should be scaled in to 0 replicas before it is removed
+	TiKVGroups []TiKVGroupSpec `json:"tikvGroups,omitempty"`
+
+	// TiFlash cluster spec, TiFlash is not deployed if it is nil
+	TiFlash *TiFlashSpec `json:"tiflash,omitempty"`
+
 	// Pump cluster spec
 	Pump *PumpSpec `json:"pump,omitempty"`
 
+	// Drainers replicate the binlog collected by Pump to the downstreams, Pump must be deployed
+	Drainers []DrainerSpec `json:"drainers,omitempty"`
+
 	// Helper spec
 	Helper HelperSpec `json:"helper,omitempty"`
 
@@ -128,6 +166,14 @@ type TidbClusterSpec struct {
 	// SchedulerName of TiDB cluster Pods
 	SchedulerName string `json:"schedulerName,omitempty"`
 
+	// HATopologyKey is the label of the nodes which the HA predicate of tidb-scheduler spreads the PD and TiKV pods
+	// across, e.g. topology.kubernetes.io/zone, so that the majority of the replicas survives the loss of any
+	// single topology domain. The pods are still spread across the nodes of each domain.
+	// The pods are only spread across the nodes if it's not set.
+	// It should be the node label of one of spec.tikv.locationLabels so that PD isolates the region replicas
+	// across it too, the LocationLabelsMismatch condition is set otherwise
+	HATopologyKey string `json:"haTopologyKey,omitempty"`
+
 	// Persistent volume reclaim policy applied to the PVs that consumed by TiDB cluster
 	PVReclaimPolicy corev1.PersistentVolumeReclaimPolicy `json:"pvReclaimPolicy,omitempty"`
 
@@ -137,6 +183,21 @@ type TidbClusterSpec struct {
 	// Enable TLS connection between TiDB server components
 	EnableTLSCluster bool `json:"enableTLSCluster,omitempty"`
 
+	// TLSCertRenewBefore is how long before expiry the TLS certificates are renewed, defaults to 720h
+	TLSCertRenewBefore *string `json:"tlsCertRenewBefore,omitempty"`
+
+	// TLSIssuer is the issuer of the TLS certificates, defaults to the Kubernetes CSR API
+	TLSIssuer *TLSIssuer `json:"tlsIssuer,omitempty"`
+
+	// Cluster is the TidbCluster whose PD cluster the members of this cluster join, so that the members
+	// of a TiDB cluster can span several namespaces or Kubernetes clusters
+	Cluster *TidbClusterRef `json:"cluster,omitempty"`
+
+	// ClusterDomain is the domain of the Kubernetes cluster, the PD and TiKV members advertise the addresses
+	// <pod>.<peer service>.<namespace>.svc.<clusterDomain> if it is set, so that they are reachable from other
+	// Kubernetes clusters
+	ClusterDomain string `json:"clusterDomain,omitempty"`
+
 	// Time zone of TiDB cluster Pods
 	Timezone string `json:"timezone,omitempty"`
 
@@ -160,14 +221,169 @@ type TidbClusterSpec struct {
 
 	// Base tolerations of TiDB cluster Pods, components may add more tolreations upon this respectively
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// ReplaceMembers are the names of the PD and TiKV pods to replace, e.g. whose disks go bad. The member
+	// is removed from PD, then its pod and PVC are recreated, the name is removed after the new member joins.
+	// The members are replaced one at a time.
+	ReplaceMembers []string `json:"replaceMembers,omitempty"`
 }
 
 // TidbClusterStatus represents the current status of a tidb cluster.
 type TidbClusterStatus struct {
-	ClusterID string     `json:"clusterID,omitempty"`
-	PD        PDStatus   `json:"pd,omitempty"`
-	TiKV      TiKVStatus `json:"tikv,omitempty"`
-	TiDB      TiDBStatus `json:"tidb,omitempty"`
+	ClusterID  string                 `json:"clusterID,omitempty"`
+	PD         PDStatus               `json:"pd,omitempty"`
+	TiKV       TiKVStatus             `json:"tikv,omitempty"`
+	TiDB       TiDBStatus             `json:"tidb,omitempty"`
+	Conditions []TidbClusterCondition `json:"conditions,omitempty"`
+	// TiKVGroups is the status of the TiKV groups, keyed by the group name
+	TiKVGroups map[string]TiKVStatus `json:"tikvGroups,omitempty"`
+	// TiDBGroups is the status of the TiDB groups, keyed by the group name
+	TiDBGroups map[string]TiDBStatus `json:"tidbGroups,omitempty"`
+	TiFlash    TiFlashStatus         `json:"tiflash,omitempty"`
+	Pump       PumpStatus            `json:"pump,omitempty"`
+	// Drainers is the status of the drainers, keyed by the drainer name
+	Drainers map[string]DrainerStatus `json:"drainers,omitempty"`
+	// TLSCerts is the status of the TLS certificates issued for the cluster, keyed by the Secret name
+	TLSCerts map[string]TLSCertStatus `json:"tlsCerts,omitempty"`
+	// Storage is the status of the PVCs of PD, TiKV and Pump, keyed by the member type,
+	// the PVCs of a TiKV group are keyed by "tikv-<group name>"
+	Storage map[string]StorageStatus `json:"storage,omitempty"`
+	// MemberReplacements is the progress of replacing the members in spec.replaceMembers, keyed by the pod name
+	MemberReplacements map[string]MemberReplacement `json:"memberReplacements,omitempty"`
+}
+
+// MemberReplacementPhase is the phase of replacing a member
+type MemberReplacementPhase string
+
+const (
+	// MemberReplacementOfflining means the member is being removed from PD, the regions
+	// of a TiKV store are migrated to the other stores
+	MemberReplacementOfflining MemberReplacementPhase = "Offlining"
+	// MemberReplacementDeleting means the pod and the PVC of the member are being deleted
+	MemberReplacementDeleting MemberReplacementPhase = "Deleting"
+	// MemberReplacementJoining means the new member is joining the cluster
+	MemberReplacementJoining MemberReplacementPhase = "Joining"
+	// MemberReplacementCompleted means the new member has joined, the name is being removed from spec.replaceMembers
+	MemberReplacementCompleted MemberReplacementPhase = "Completed"
+)
+
+// MemberReplacement is the progress of replacing a PD or TiKV member
+type MemberReplacement struct {
+	MemberType MemberType             `json:"memberType"`
+	Phase      MemberReplacementPhase `json:"phase,omitempty"`
+	// ID is the member ID of PD or the store ID of TiKV being replaced
+	ID string `json:"id,omitempty"`
+	// PVCUID is the UID of the PVC of the member being replaced
+	PVCUID    types.UID   `json:"pvcUID,omitempty"`
+	StartTime metav1.Time `json:"startTime,omitempty"`
+	Message   string      `json:"message,omitempty"`
+}
+
+// StorageStatus is the status of the PVCs of a component
+type StorageStatus struct {
+	// Size is the storage size requested in the spec, the PVCs are expanded to it
+	Size string `json:"size,omitempty"`
+	// LastFileSystemResizeTime is the last time the pods were restarted to resize
+	// the file systems of the PVCs pending on a restart
+	LastFileSystemResizeTime *metav1.Time `json:"lastFileSystemResizeTime,omitempty"`
+	// PVCs is the resize progress of the PVCs not expanded to Size yet, keyed by the PVC name
+	PVCs map[string]PVCResizeStatus `json:"pvcs,omitempty"`
+}
+
+// PVCResizePhase is the phase of resizing a PVC
+type PVCResizePhase string
+
+const (
+	// PVCResizeUnsupported means the StorageClass of the PVC doesn't allow volume expansion
+	PVCResizeUnsupported PVCResizePhase = "Unsupported"
+	// PVCResizing means the volume of the PVC is being expanded
+	PVCResizing PVCResizePhase = "Resizing"
+	// PVCFileSystemResizePending means the volume is expanded and the file system is resized
+	// after the pod is restarted
+	PVCFileSystemResizePending PVCResizePhase = "FileSystemResizePending"
+)
+
+// PVCResizeStatus is the resize progress of a PVC
+type PVCResizeStatus struct {
+	Phase PVCResizePhase `json:"phase,omitempty"`
+	// CurrentSize is the capacity of the volume bound to the PVC
+	CurrentSize string `json:"currentSize,omitempty"`
+	Message     string `json:"message,omitempty"`
+	// LastTransitionTime is the last time the PVC entered the phase
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// TLSCertStatus is the status of a TLS certificate issued for the cluster
+type TLSCertStatus struct {
+	NotBefore metav1.Time `json:"notBefore,omitempty"`
+	NotAfter  metav1.Time `json:"notAfter,omitempty"`
+	// LastRenewTime is the last time the certificate was renewed
+	LastRenewTime *metav1.Time `json:"lastRenewTime,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// TidbClusterRef is a reference to a TidbCluster, which may run in another Kubernetes cluster
+type TidbClusterRef struct {
+	// Namespace is the namespace of the TidbCluster, defaults to the namespace of the referring cluster
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name is the name of the TidbCluster
+	Name string `json:"name"`
+
+	// ClusterDomain is the domain of the Kubernetes cluster where the TidbCluster runs,
+	// empty means the TidbCluster runs in the local Kubernetes cluster
+	ClusterDomain string `json:"clusterDomain,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// TLSIssuer is the issuer of the TLS certificates of a cluster
+type TLSIssuer struct {
+	// Type of the issuer, one of CSR, SelfSignedCA, Secret and CertManager, defaults to CSR
+	Type TLSIssuerType `json:"type,omitempty"`
+	// CASecretName is the name of the Secret holding the CA certificate and key for the SelfSignedCA issuer,
+	// defaults to <cluster>-ca
+	CASecretName string `json:"caSecretName,omitempty"`
+	// CertManager is the cert-manager issuer referenced by the Certificate resources for the CertManager issuer
+	CertManager *CertManagerIssuer `json:"certManager,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// CertManagerIssuer references an Issuer or ClusterIssuer of cert-manager
+type CertManagerIssuer struct {
+	// Name of the issuer
+	Name string `json:"name"`
+	// Kind of the issuer, Issuer or ClusterIssuer, defaults to Issuer
+	Kind string `json:"kind,omitempty"`
+}
+
+// TidbClusterConditionType represents a valid condition of a TidbCluster.
+type TidbClusterConditionType string
+
+const (
+	// TidbClusterUpgradeFailed means an upgrade was rolled back because
+	// the upgraded pods did not become healthy before the health deadline
+	TidbClusterUpgradeFailed TidbClusterConditionType = "UpgradeFailed"
+	// TidbClusterUpgradeRejected means the desired version of a component
+	// is not compatible with the versions of the other components
+	TidbClusterUpgradeRejected TidbClusterConditionType = "UpgradeRejected"
+	// TidbClusterResizeRejected means the new resource requests of a component
+	// don't fit the nodes of its pods, the pods keep the old resources
+	TidbClusterResizeRejected TidbClusterConditionType = "ResizeRejected"
+	// TidbClusterResizePaused means a resized pod of a component was OOMKilled or
+	// could not be scheduled, the resize continues after the resources are changed again
+	TidbClusterResizePaused TidbClusterConditionType = "ResizePaused"
+	// TidbClusterLocationLabelsMismatch means the location labels of PD or the TiKV stores
+	// don't match spec.tikv.locationLabels
+	TidbClusterLocationLabelsMismatch TidbClusterConditionType = "LocationLabelsMismatch"
+)
+
+// TidbClusterCondition describes the observed state of a TidbCluster at a certain point.
+type TidbClusterCondition struct {
+	Type               TidbClusterConditionType `json:"type"`
+	Status             corev1.ConditionStatus   `json:"status"`
+	LastTransitionTime metav1.Time              `json:"lastTransitionTime,omitempty"`
+	Reason             string                   `json:"reason,omitempty"`
+	Message            string                   `json:"message,omitempty"`
 }
 
 // +k8s:openapi-gen=true
@@ -201,11 +417,93 @@ type TiKVSpec struct {
 	StorageClassName string       `json:"storageClassName,omitempty"`
 	MaxFailoverCount int32        `json:"maxFailoverCount,omitempty"`
 
+	// EvictLeaderTimeout is how long to wait for the leaders to be evicted from a TiKV store
+	// before its pod is restarted during upgrade, defaults to 3m
+	EvictLeaderTimeout *string `json:"evictLeaderTimeout,omitempty"`
+
+	// UpgradeStrategy controls the canary and staged upgrade of TiKV
+	UpgradeStrategy *UpgradeStrategy `json:"upgradeStrategy,omitempty"`
+
+	// LocationLabels maps the labels of the Kubernetes nodes to the location labels of the TiKV stores in PD,
+	// from the top level of the topology, e.g. zone, rack and host. The location-labels of PD are kept
+	// as the names in this order. It's not named storeLabels which are the fixed labels of a TiKV group.
+	// The location labels of spec.tikv apply to the TiKV groups and TiFlash too
+	LocationLabels []StoreLabel `json:"locationLabels,omitempty"`
+
+	// IsolationLevel is the isolation-level of PD, the replicas of a region are isolated at least
+	// at this level. It must be the name of one of the locationLabels. It's not supported by PD before 4.0
+	IsolationLevel string `json:"isolationLevel,omitempty"`
+
+	// BlockCacheFromMemoryLimit sizes the shared block cache of TiKV to half of the memory limit unless
+	// storage.block-cache.capacity is set in the config. The TiKV pods are restarted when the memory limit changes.
+	// Optional: Defaults to false
+	BlockCacheFromMemoryLimit bool `json:"blockCacheFromMemoryLimit,omitempty"`
+
+	// MigrateStorageClass migrates the existing stores to storageClassName by store replacement when their PVCs
+	// are on another StorageClass. The stores are never migrated if storageClassName is not set, e.g. when the
+	// default StorageClass changes. A started migration is finished even if this is unset.
+	// Optional: Defaults to false
+	MigrateStorageClass bool `json:"migrateStorageClass,omitempty"`
+
 	// +k8s:openapi-gen=false
 	// TODO: add schema
 	config.GenericConfig `json:",inline"`
 }
 
+// +k8s:openapi-gen=true
+// StoreLabel maps a label of the Kubernetes nodes to a location label of the TiKV stores
+type StoreLabel struct {
+	// Name of the location label in PD, e.g. zone
+	Name string `json:"name"`
+	// NodeLabel is the key of the node label, e.g. topology.kubernetes.io/zone,
+	// the name is used if it's empty
+	NodeLabel string `json:"nodeLabel,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// TiKVGroupSpec contains details of a group of TiKV members
+type TiKVGroupSpec struct {
+	// Name of the group, the StatefulSet of the group is named <cluster>-tikv-<name>.
+	// It must be a DNS-1123 label other than peer
+	Name string `json:"name"`
+
+	// TiKVSpec is the spec of the group, the image of spec.tikv is used if neither image nor baseImage is set.
+	// The TiKV config of the group is loaded from the ConfigMap <cluster>-tikv-<name>
+	TiKVSpec `json:",inline"`
+
+	// StoreLabels are set to the stores of the group in PD, in addition to the location labels
+	StoreLabels map[string]string `json:"storeLabels,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// TiFlashSpec contains details of TiFlash members, which keep the columnar learner replicas of the TiKV regions.
+// The placement rules of PD must be enabled by replication.enable-placement-rules in the PD config
+type TiFlashSpec struct {
+	// +k8s:openapi-gen=false
+	ComponentSpec
+	// +k8s:openapi-gen=false
+	Resources
+	Replicas         int32  `json:"replicas"`
+	Privileged       bool   `json:"privileged,omitempty"`
+	StorageClassName string `json:"storageClassName,omitempty"`
+	MaxFailoverCount int32  `json:"maxFailoverCount,omitempty"`
+
+	// StoreLabels are set to the TiFlash stores in PD in addition to the location labels,
+	// the label engine=tiflash is always set so that PD places only learner replicas on them
+	StoreLabels map[string]string `json:"storeLabels,omitempty"`
+
+	// UpgradeStrategy controls the canary and staged upgrade of TiFlash
+	UpgradeStrategy *UpgradeStrategy `json:"upgradeStrategy,omitempty"`
+
+	// StableScheduling schedules a recreated TiFlash pod to the node it ran on
+	StableScheduling bool `json:"stableScheduling,omitempty"`
+
+	// +k8s:openapi-gen=false
+	// Config of TiFlash, the addresses of the pod, the PD cluster and the TiDB status service are filled in
+	// by tidb-operator unless they are set
+	config.GenericConfig `json:",inline"`
+}
+
 // +k8s:openapi-gen=true
 // TiDBSpec contains details of TiDB members
 type TiDBSpec struct {
@@ -227,10 +525,39 @@ type TiDBSpec struct {
 	// Plugins is a list of plugins that are loaded by TiDB server, empty means plugin disabled
 	Plugins []string `json:"plugins,omitempty"`
 
+	// UpgradeStrategy controls the canary and staged upgrade of TiDB
+	UpgradeStrategy *UpgradeStrategy `json:"upgradeStrategy,omitempty"`
+
+	// Drain enables draining the client connections of a TiDB server before it is restarted
+	Drain *TiDBDrainSpec `json:"drain,omitempty"`
+
+	// HA spreads the TiDB pods across the nodes, or the topology domains of spec.haTopologyKey, by the HA
+	// predicate of tidb-scheduler, no more than ceil(replicas / domains) pods are placed in one of them
+	HA bool `json:"ha,omitempty"`
+
+	// StableScheduling schedules a recreated TiDB pod to the node it ran on,
+	// it defaults to the StableScheduling feature of tidb-scheduler
+	StableScheduling *bool `json:"stableScheduling,omitempty"`
+
+	// TLSClient configures the certificates issued for the MySQL clients when EnableTLSClient is true
+	TLSClient *TiDBTLSClient `json:"tlsClient,omitempty"`
+
 	// Config is the Configuration of tidb-servers
 	Config *TiDBConfig `json:"config,omitempty"`
 }
 
+// +k8s:openapi-gen=true
+// TiDBGroupSpec contains details of a group of TiDB members
+type TiDBGroupSpec struct {
+	// Name of the group, the StatefulSet and the Service of the group are named <cluster>-tidb-<name>.
+	// It must be a DNS-1123 label other than peer and client-ca
+	Name string `json:"name"`
+
+	// TiDBSpec is the spec of the group, the image of spec.tidb is used if neither image nor baseImage is set.
+	// The TiDB config of the group is loaded from the ConfigMap <cluster>-tidb-<name>
+	TiDBSpec `json:",inline"`
+}
+
 // +k8s:openapi-gen=true
 // PumpSpec contains details of Pump members
 type PumpSpec struct {
@@ -242,6 +569,9 @@ type PumpSpec struct {
 	StorageClassName string `json:"storageClassName,omitempty"`
 	Replicas         int32  `json:"replicas"`
 
+	// StableScheduling schedules a recreated Pump pod to the node it ran on
+	StableScheduling bool `json:"stableScheduling,omitempty"`
+
 	// ConfigUpdateStrategy determines how to apply the configuration change,
 	// change this field without actually changing the configuration will not trigger rolling-update
 	ConfigUpdateStrategy ConfigUpdateStrategy `json:"configUpdateStrategy,omitempty"`
@@ -254,6 +584,80 @@ type PumpSpec struct {
 	SetTimeZone *bool `json:"setTimeZone,omitempty"`
 }
 
+// DrainerSinkType is the type of the downstream of a drainer
+type DrainerSinkType string
+
+const (
+	// DrainerSinkMySQL replicates the binlog to a MySQL compatible database
+	DrainerSinkMySQL DrainerSinkType = "mysql"
+	// DrainerSinkTiDB replicates the binlog to a TiDB cluster
+	DrainerSinkTiDB DrainerSinkType = "tidb"
+	// DrainerSinkKafka writes the binlog to Kafka
+	DrainerSinkKafka DrainerSinkType = "kafka"
+	// DrainerSinkFile writes the binlog to local files on the checkpoint volume
+	DrainerSinkFile DrainerSinkType = "file"
+)
+
+// +k8s:openapi-gen=true
+// DrainerSpec contains details of a Drainer, which runs in the StatefulSet <clusterName>-<name>-drainer.
+// The StatefulSet is named as the one of the tidb-drainer chart released with the same name, so that
+// the drainer keeps its checkpoint PVC after the chart release is deleted and the drainer is added here.
+type DrainerSpec struct {
+	// Name of the drainer, it must be unique in the cluster
+	Name string `json:"name"`
+
+	// +k8s:openapi-gen=false
+	ComponentSpec
+	// +k8s:openapi-gen=false
+	Resources
+
+	// StorageClassName of the checkpoint volume, the volume is retained when the drainer is removed
+	StorageClassName string `json:"storageClassName,omitempty"`
+
+	// DisableDetect disables the causality detection of the mysql and tidb sinks
+	DisableDetect bool `json:"disableDetect,omitempty"`
+
+	// InitialCommitTs is the TSO to start the replication from if the drainer has no checkpoint
+	InitialCommitTs int64 `json:"initialCommitTs,omitempty"`
+
+	// Sink is the downstream of the drainer
+	Sink DrainerSinkSpec `json:"sink"`
+
+	// UpgradeStrategy controls the staged upgrade of the drainer, e.g. a canary of 0 with manualApproval
+	// makes the upgrade wait for the approval before the drainer restarts
+	UpgradeStrategy *UpgradeStrategy `json:"upgradeStrategy,omitempty"`
+
+	// +k8s:openapi-gen=false
+	// Config of drainer, the syncer.db-type and syncer.to sections are rendered from the sink unless they are set
+	config.GenericConfig `json:",inline"`
+}
+
+// +k8s:openapi-gen=true
+// DrainerSinkSpec is the downstream of a drainer
+type DrainerSinkSpec struct {
+	// Type of the sink, one of mysql, tidb, kafka and file
+	Type DrainerSinkType `json:"type"`
+
+	// Host of the mysql or tidb sink
+	Host string `json:"host,omitempty"`
+	// Port of the mysql or tidb sink, defaults to 3306 for mysql and 4000 for tidb
+	Port int32 `json:"port,omitempty"`
+	// User of the mysql or tidb sink, defaults to root
+	User string `json:"user,omitempty"`
+	// PasswordSecret is the name of the Secret whose password key is the password of the mysql or tidb sink
+	PasswordSecret string `json:"passwordSecret,omitempty"`
+
+	// KafkaAddrs is the comma separated addresses of the brokers of the kafka sink
+	KafkaAddrs string `json:"kafkaAddrs,omitempty"`
+	// KafkaVersion of the kafka sink
+	KafkaVersion string `json:"kafkaVersion,omitempty"`
+	// TopicName of the kafka sink, defaults to <cluster-id>_obinlog
+	TopicName string `json:"topicName,omitempty"`
+
+	// Dir of the file sink, defaults to /data/pb on the checkpoint volume
+	Dir string `json:"dir,omitempty"`
+}
+
 // +k8s:openapi-gen=true
 // HelperSpec contains details of helper component
 type HelperSpec struct {
@@ -264,6 +668,29 @@ type HelperSpec struct {
 	ImagePullPolicy *corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
 }
 
+// +k8s:openapi-gen=true
+// TiDBDrainSpec controls how the client connections are drained from a TiDB server before it is restarted.
+// The server is removed from the service endpoints first, and restarted after its active connections
+// drop to MaxConnections or the timeout is exceeded.
+type TiDBDrainSpec struct {
+	// MaxConnections is the number of active connections at which the server is restarted, defaults to 0
+	MaxConnections int32 `json:"maxConnections,omitempty"`
+
+	// TimeoutSeconds is the longest time to wait for the connections to drain, defaults to 60
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// TiDBTLSClient configures the certificates issued for the MySQL clients of TiDB
+type TiDBTLSClient struct {
+	// ExtraSANs are the extra hostnames and IPs added to the subject alternative names of the TiDB server certificate,
+	// the TiDB Service and the ingress of its LoadBalancer are always included
+	ExtraSANs []string `json:"extraSANs,omitempty"`
+
+	// Users are the MySQL users whose client certificates are issued into the Secrets named <cluster>-tidb-client-<user>
+	Users []string `json:"users,omitempty"`
+}
+
 // +k8s:openapi-gen=true
 // TiDBSlowLogTailerSpec represents an optional log tailer sidecar with TiDB
 type TiDBSlowLogTailerSpec struct {
@@ -279,6 +706,26 @@ type TiDBSlowLogTailerSpec struct {
 	ImagePullPolicy *corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
 }
 
+// +k8s:openapi-gen=true
+// UpgradeStrategy controls how the pods of a component are rolled to a new revision
+type UpgradeStrategy struct {
+	// Canary is the number (e.g. 1) or percentage (e.g. 10%) of pods upgraded before the upgrade pauses,
+	// the upgrade is not staged if it is empty
+	Canary *intstr.IntOrString `json:"canary,omitempty"`
+
+	// HealthWindowSeconds is how long the canary pods must stay healthy before the upgrade proceeds.
+	// Defaults to 60
+	HealthWindowSeconds *int32 `json:"healthWindowSeconds,omitempty"`
+
+	// ManualApproval makes the upgrade wait after the health window until the update revision
+	// is listed in the tidb.pingcap.com/upgrade-continue annotation of the TidbCluster
+	ManualApproval bool `json:"manualApproval,omitempty"`
+
+	// HealthDeadlineSeconds is how long an upgraded pod may stay unhealthy before the upgrade
+	// is rolled back to the previous pod template, the upgrade is never rolled back if it is empty
+	HealthDeadlineSeconds *int32 `json:"healthDeadlineSeconds,omitempty"`
+}
+
 // +k8s:openapi-gen=true
 type Resources struct {
 	// Resource requests of the component
@@ -381,6 +828,8 @@ type PDStatus struct {
 	Members        map[string]PDMember        `json:"members,omitempty"`
 	Leader         PDMember                   `json:"leader,omitempty"`
 	FailureMembers map[string]PDFailureMember `json:"failureMembers,omitempty"`
+	// PeerMembers are the members of the PD cluster which belong to other TidbClusters
+	PeerMembers map[string]PDMember `json:"peerMembers,omitempty"`
 }
 
 // PDMember is PD member
@@ -404,6 +853,33 @@ type PDFailureMember struct {
 	CreatedAt     metav1.Time `json:"createdAt,omitempty"`
 }
 
+// UpgradeStep is the step of a staged upgrade
+type UpgradeStep string
+
+const (
+	// UpgradeStepCanary means the canary pods are being upgraded
+	UpgradeStepCanary UpgradeStep = "Canary"
+	// UpgradeStepHealthCheck means the upgrade is observing the canary pods during the health window
+	UpgradeStepHealthCheck UpgradeStep = "HealthCheck"
+	// UpgradeStepWaitingApproval means the upgrade is waiting for the upgrade-continue annotation
+	UpgradeStepWaitingApproval UpgradeStep = "WaitingApproval"
+	// UpgradeStepRolling means the remaining pods are being upgraded
+	UpgradeStepRolling UpgradeStep = "Rolling"
+)
+
+// UpgradeProgress is the progress of a staged upgrade
+type UpgradeProgress struct {
+	// Revision is the update revision of the statefulset being rolled out
+	Revision         string      `json:"revision,omitempty"`
+	Step             UpgradeStep `json:"step,omitempty"`
+	CanaryReplicas   int32       `json:"canaryReplicas,omitempty"`
+	UpgradedReplicas int32       `json:"upgradedReplicas,omitempty"`
+	// HealthySince is the time since which all the upgraded pods have been healthy
+	HealthySince *metav1.Time `json:"healthySince,omitempty"`
+	// UnhealthySince is the time since which an upgraded pod has been unhealthy
+	UnhealthySince *metav1.Time `json:"unhealthySince,omitempty"`
+}
+
 // TiDBStatus is TiDB status
 type TiDBStatus struct {
 	Phase                    MemberPhase                  `json:"phase,omitempty"`
@@ -411,6 +887,7 @@ type TiDBStatus struct {
 	Members                  map[string]TiDBMember        `json:"members,omitempty"`
 	FailureMembers           map[string]TiDBFailureMember `json:"failureMembers,omitempty"`
 	ResignDDLOwnerRetryCount int32                        `json:"resignDDLOwnerRetryCount,omitempty"`
+	Upgrade                  *UpgradeProgress             `json:"upgrade,omitempty"`
 }
 
 // TiDBMember is TiDB member
@@ -437,6 +914,79 @@ type TiKVStatus struct {
 	Stores          map[string]TiKVStore        `json:"stores,omitempty"`
 	TombstoneStores map[string]TiKVStore        `json:"tombstoneStores,omitempty"`
 	FailureStores   map[string]TiKVFailureStore `json:"failureStores,omitempty"`
+	Upgrade         *UpgradeProgress            `json:"upgrade,omitempty"`
+	// PeerStores are the stores in the PD cluster which belong to other TidbClusters
+	PeerStores map[string]TiKVStore `json:"peerStores,omitempty"`
+	// Migration is the progress of migrating the stores to another StorageClass
+	Migration *StorageMigrationProgress `json:"migration,omitempty"`
+}
+
+// StorageMigrationPhase is the phase of migrating the TiKV stores to another StorageClass
+type StorageMigrationPhase string
+
+const (
+	// StorageMigrationRebalancing means PD is rebalancing the regions to the stores on the new StorageClass
+	StorageMigrationRebalancing StorageMigrationPhase = "Rebalancing"
+	// StorageMigrationDeletingStore means the old store of a pod is being deleted from PD
+	StorageMigrationDeletingStore StorageMigrationPhase = "DeletingStore"
+	// StorageMigrationRecreating means a pod is being recreated with a new PVC
+	StorageMigrationRecreating StorageMigrationPhase = "Recreating"
+)
+
+// StorageMigrationProgress is the progress of migrating the TiKV stores to another StorageClass.
+// A replacement store is added during the migration, and the old stores are replaced one at a time.
+type StorageMigrationProgress struct {
+	// StorageClassName is the StorageClass the stores are migrated to
+	StorageClassName string                `json:"storageClassName"`
+	Phase            StorageMigrationPhase `json:"phase,omitempty"`
+	// Ordinal is the ordinal of the pod whose store is being replaced
+	Ordinal *int32 `json:"ordinal,omitempty"`
+	// Replicas is the number of the stores to migrate
+	Replicas int32 `json:"replicas"`
+	// MigratedReplicas is the number of the stores migrated
+	MigratedReplicas int32       `json:"migratedReplicas"`
+	StartTime        metav1.Time `json:"startTime,omitempty"`
+	Message          string      `json:"message,omitempty"`
+}
+
+// TiFlashStatus is TiFlash status
+type TiFlashStatus struct {
+	Synced          bool                        `json:"synced,omitempty"`
+	Phase           MemberPhase                 `json:"phase,omitempty"`
+	StatefulSet     *apps.StatefulSetStatus     `json:"statefulSet,omitempty"`
+	Stores          map[string]TiKVStore        `json:"stores,omitempty"`
+	TombstoneStores map[string]TiKVStore        `json:"tombstoneStores,omitempty"`
+	FailureStores   map[string]TiKVFailureStore `json:"failureStores,omitempty"`
+	Upgrade         *UpgradeProgress            `json:"upgrade,omitempty"`
+}
+
+// PumpStatus is Pump status
+type PumpStatus struct {
+	StatefulSet *apps.StatefulSetStatus `json:"statefulSet,omitempty"`
+	Members     map[string]PumpMember   `json:"members,omitempty"`
+}
+
+// PumpMember is Pump member
+type PumpMember struct {
+	Name string `json:"name"`
+	// Node hosting pod of this Pump member.
+	NodeName string `json:"node,omitempty"`
+}
+
+// DrainerStatus is the status of a drainer, the replication progress is reported by the status API of drainer
+type DrainerStatus struct {
+	Phase       MemberPhase             `json:"phase,omitempty"`
+	StatefulSet *apps.StatefulSetStatus `json:"statefulSet,omitempty"`
+	// Synced is whether the drainer has replicated all the binlog collected by pump
+	Synced bool `json:"synced,omitempty"`
+	// SyncedTS is the TSO of the last binlog replicated to the downstream
+	SyncedTS int64 `json:"syncedTS,omitempty"`
+	// Lag is how far the downstream falls behind the upstream, it is computed from the physical time of SyncedTS
+	Lag *metav1.Duration `json:"lag,omitempty"`
+	// LastUpdateTime is the last time the replication progress was updated from drainer
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+	// Upgrade is the progress of the staged upgrade of the drainer
+	Upgrade *UpgradeProgress `json:"upgrade,omitempty"`
 }
 
 // TiKVStores is either Up/Down/Offline/Tombstone
@@ -446,10 +996,15 @@ type TiKVStore struct {
 	PodName           string      `json:"podName"`
 	IP                string      `json:"ip"`
 	LeaderCount       int32       `json:"leaderCount"`
+	RegionCount       int32       `json:"regionCount,omitempty"`
 	State             string      `json:"state"`
 	LastHeartbeatTime metav1.Time `json:"lastHeartbeatTime"`
 	// Last time the health transitioned from one to another.
 	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// How long the leader eviction took when the store was upgraded last time
+	LastEvictLeaderDuration *metav1.Duration `json:"lastEvictLeaderDuration,omitempty"`
+	// Node hosting pod of this store.
+	NodeName string `json:"node,omitempty"`
 }
 
 // TiKVFailureStore is the tikv failure store information
@@ -496,6 +1051,9 @@ const (
 
 	// BackupStorageTypeGcs represents the google cloud storage
 	BackupStorageTypeGcs BackupStorageType = "gcs"
+
+	// BackupStorageTypeLocal represents the storage on a PersistentVolumeClaim, it is only supported by DataImport
+	BackupStorageTypeLocal BackupStorageType = "local"
 )
 
 // +k8s:openapi-gen=true
@@ -512,8 +1070,9 @@ const (
 // +k8s:openapi-gen=true
 // StorageProvider defines the configuration for storing a backup in backend storage.
 type StorageProvider struct {
-	S3  *S3StorageProvider  `json:"s3,omitempty"`
-	Gcs *GcsStorageProvider `json:"gcs,omitempty"`
+	S3    *S3StorageProvider    `json:"s3,omitempty"`
+	Gcs   *GcsStorageProvider   `json:"gcs,omitempty"`
+	Local *LocalStorageProvider `json:"local,omitempty"`
 }
 
 // +k8s:openapi-gen=true
@@ -556,6 +1115,14 @@ type GcsStorageProvider struct {
 	SecretName string `json:"secretName"`
 }
 
+// +k8s:openapi-gen=true
+// LocalStorageProvider represents the data on a PersistentVolumeClaim in the namespace,
+// e.g. the PVC of a backup which is not uploaded to the cloud storage.
+type LocalStorageProvider struct {
+	// ClaimName is the name of the PersistentVolumeClaim
+	ClaimName string `json:"claimName"`
+}
+
 // +k8s:openapi-gen=true
 // BackupType represents the backup type.
 type BackupType string
@@ -765,3 +1332,448 @@ type RestoreStatus struct {
 	TimeCompleted metav1.Time        `json:"timeCompleted"`
 	Conditions    []RestoreCondition `json:"conditions"`
 }
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// +k8s:openapi-gen=true
+// DataImport represents the import of the data in mydumper or CSV format into a tidb cluster by tidb-lightning.
+type DataImport struct {
+	metav1.TypeMeta `json:",inline"`
+	// +k8s:openapi-gen=false
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec DataImportSpec `json:"spec"`
+	// +k8s:openapi-gen=false
+	Status DataImportStatus `json:"status"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// +k8s:openapi-gen=true
+// DataImportList contains a list of DataImport.
+type DataImportList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +k8s:openapi-gen=false
+	metav1.ListMeta `json:"metadata"`
+
+	Items []DataImport `json:"items"`
+}
+
+// +k8s:openapi-gen=true
+// ImportBackend represents the backend tidb-lightning writes the data to.
+type ImportBackend string
+
+const (
+	// ImportBackendImporter writes the data as SST files through tikv-importer, TiKV is switched to import mode
+	// during the import, the tidb cluster should not serve other workloads.
+	ImportBackendImporter ImportBackend = "importer"
+	// ImportBackendTiDB writes the data as SQL statements through TiDB, it is slower but the tidb cluster
+	// keeps serving.
+	ImportBackendTiDB ImportBackend = "tidb"
+)
+
+// +k8s:openapi-gen=true
+// DataImportSpec contains the specification for an import into a tidb cluster.
+type DataImportSpec struct {
+	// Cluster represents the tidb cluster to import into.
+	Cluster string `json:"cluster"`
+	// TidbSecretName is the name of the secret which stores
+	// tidb cluster's username and password.
+	TidbSecretName string `json:"tidbSecretName"`
+	// Backend is the backend of tidb-lightning, defaults to importer
+	Backend ImportBackend `json:"backend,omitempty"`
+	// Importer is the address of tikv-importer for the importer backend, defaults to <cluster>-importer:8287
+	Importer string `json:"importer,omitempty"`
+	// Image is the image of tidb-lightning, defaults to pingcap/tidb-lightning with the version of the tidb cluster
+	Image string `json:"image,omitempty"`
+	// StorageType is the storage type of the source data.
+	StorageType BackupStorageType `json:"storageType"`
+	// StorageProvider configures where the source data is stored.
+	StorageProvider `json:",inline"`
+	// Path is the location of the source data, e.g. s3://bucket/path or gcs://bucket/path for the cloud storage,
+	// or the path in the PVC for the local storage. A path ending with .tgz is unarchived after downloading.
+	Path string `json:"path"`
+	// StorageClassName is the storage class for the PV of the import job, which keeps the downloaded data
+	// and the checkpoint of tidb-lightning.
+	StorageClassName string `json:"storageClassName,omitempty"`
+	// StorageSize is the request storage size for the import job
+	StorageSize string `json:"storageSize,omitempty"`
+	// Resources of the import job
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Config is the tidb-lightning config, the sections set by tidb-operator are overridden
+	config.GenericConfig `json:",inline"`
+}
+
+// DataImportConditionType represents a valid condition of a DataImport.
+type DataImportConditionType string
+
+const (
+	// DataImportScheduled means the import job has been created
+	DataImportScheduled DataImportConditionType = "Scheduled"
+	// DataImportRunning means the import is currently being executed.
+	DataImportRunning DataImportConditionType = "Running"
+	// DataImportComplete means the data has been imported and the checksums have been verified.
+	DataImportComplete DataImportConditionType = "Complete"
+	// DataImportFailed means the import has failed.
+	DataImportFailed DataImportConditionType = "Failed"
+	// DataImportRetryFailed means this failure can be retried
+	DataImportRetryFailed DataImportConditionType = "RetryFailed"
+	// DataImportTiKVImportMode means the TiKV stores of the tidb cluster have been switched to import mode
+	// by tidb-operator, they are switched back to normal mode after the import completes or fails.
+	DataImportTiKVImportMode DataImportConditionType = "TiKVImportMode"
+)
+
+// DataImportCondition describes the observed state of a DataImport at a certain point.
+type DataImportCondition struct {
+	Type               DataImportConditionType `json:"type"`
+	Status             corev1.ConditionStatus  `json:"status"`
+	LastTransitionTime metav1.Time             `json:"lastTransitionTime"`
+	Reason             string                  `json:"reason"`
+	Message            string                  `json:"message"`
+}
+
+// ImportTableChecksum is the checksum of an imported table computed by TiDB.
+type ImportTableChecksum struct {
+	// Table is the name of the table in the form of db.table
+	Table string `json:"table"`
+	// Checksum is the crc64 xor checksum of the table
+	Checksum string `json:"checksum"`
+	// TotalKVs is the number of the kv pairs of the table
+	TotalKVs int64 `json:"totalKVs"`
+	// TotalBytes is the size of the kv pairs of the table
+	TotalBytes int64 `json:"totalBytes"`
+}
+
+// DataImportStatus represents the current status of an import.
+type DataImportStatus struct {
+	// TimeStarted is the time at which the import was started.
+	TimeStarted metav1.Time `json:"timeStarted"`
+	// TimeCompleted is the time at which the import was completed.
+	TimeCompleted metav1.Time `json:"timeCompleted"`
+	// Progress is the percentage of the imported chunks of the source data
+	Progress string `json:"progress,omitempty"`
+	// Checksums are the checksums of the imported tables
+	Checksums  []ImportTableChecksum `json:"checksums,omitempty"`
+	Conditions []DataImportCondition `json:"conditions"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// +k8s:openapi-gen=true
+// TidbMonitor is the monitoring of one or more tidb clusters by Prometheus and Grafana
+type TidbMonitor struct {
+	metav1.TypeMeta `json:",inline"`
+	// +k8s:openapi-gen=false
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec TidbMonitorSpec `json:"spec"`
+	// +k8s:openapi-gen=false
+	Status TidbMonitorStatus `json:"status"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// +k8s:openapi-gen=true
+// TidbMonitorList is TidbMonitor list
+type TidbMonitorList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +k8s:openapi-gen=false
+	metav1.ListMeta `json:"metadata"`
+
+	Items []TidbMonitor `json:"items"`
+}
+
+// +k8s:openapi-gen=true
+// TidbMonitorSpec describes the Prometheus and Grafana monitoring the tidb clusters
+type TidbMonitorSpec struct {
+	// Clusters are the tidb clusters to monitor, the namespace defaults to the namespace of the TidbMonitor.
+	// Only the clusters running in the local Kubernetes cluster can be monitored
+	Clusters []MonitorClusterRef `json:"clusters"`
+
+	Prometheus PrometheusSpec `json:"prometheus"`
+	// Grafana is not deployed if nil
+	Grafana     *GrafanaSpec    `json:"grafana,omitempty"`
+	Reloader    ReloaderSpec    `json:"reloader,omitempty"`
+	Initializer InitializerSpec `json:"initializer,omitempty"`
+
+	// ImagePullPolicy of the monitor Pod, defaults to IfNotPresent
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// Persistent keeps the data of Prometheus and Grafana in a PV if true, or in an emptyDir otherwise
+	Persistent bool `json:"persistent,omitempty"`
+	// StorageClassName of the PV, the default storage class is used if empty
+	StorageClassName *string `json:"storageClassName,omitempty"`
+	// Storage is the size of the PV, defaults to 10Gi
+	Storage string `json:"storage,omitempty"`
+
+	// KubePrometheusURL is the URL of the Prometheus monitoring the Kubernetes cluster, used by the dashboards
+	KubePrometheusURL string `json:"kubePrometheusURL,omitempty"`
+	// AlertmanagerURL is the URL of the Alertmanager the alerts are sent to
+	AlertmanagerURL string `json:"alertmanagerURL,omitempty"`
+
+	// NodeSelector of the monitor Pod
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Annotations of the monitor Pod
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Tolerations of the monitor Pod
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// MonitorClusterRef is a reference to a TidbCluster monitored by a TidbMonitor
+type MonitorClusterRef struct {
+	TidbClusterRef `json:",inline"`
+
+	// ClientTLSSecretName is the name of the Secret in the namespace of the TidbMonitor with the client
+	// certificate to scrape the TLS enabled cluster. It defaults to the PD client Secret of the cluster
+	// if the cluster runs in the namespace of the TidbMonitor, and is required otherwise, as the Secrets
+	// in the namespaces of other clusters are never read
+	ClientTLSSecretName string `json:"clientTLSSecretName,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// MonitorContainer is the common spec of the containers of the monitor Pod
+type MonitorContainer struct {
+	Resources
+
+	// BaseImage of the container, the image tag is specified by version
+	BaseImage string `json:"baseImage,omitempty"`
+	// Version of the container
+	Version string `json:"version,omitempty"`
+	// ImagePullPolicy of the container. Override the monitor-level imagePullPolicy if present
+	ImagePullPolicy *corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// PrometheusSpec is the spec of Prometheus
+type PrometheusSpec struct {
+	MonitorContainer
+
+	// LogLevel of Prometheus, defaults to info
+	LogLevel string `json:"logLevel,omitempty"`
+	// Service of Prometheus, defaults to ClusterIP
+	Service ServiceSpec `json:"service,omitempty"`
+	// ReserveDays is the retention of the metrics, defaults to 12
+	ReserveDays int `json:"reserveDays,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// GrafanaSpec is the spec of Grafana
+type GrafanaSpec struct {
+	MonitorContainer
+
+	// LogLevel of Grafana, defaults to info
+	LogLevel string `json:"logLevel,omitempty"`
+	// Service of Grafana, defaults to ClusterIP
+	Service ServiceSpec `json:"service,omitempty"`
+	// Username of the Grafana admin, defaults to admin
+	Username string `json:"username,omitempty"`
+	// Password of the Grafana admin, defaults to admin
+	Password string `json:"password,omitempty"`
+	// Envs configure Grafana by environment variables except GF_PATHS_DATA, GF_SECURITY_ADMIN_USER and GF_SECURITY_ADMIN_PASSWORD
+	// Ref https://grafana.com/docs/installation/configuration/#using-environment-variables
+	Envs map[string]string `json:"envs,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// ReloaderSpec is the spec of the reloader, which reloads the Prometheus rules
+type ReloaderSpec struct {
+	MonitorContainer
+
+	// Service of the reloader, defaults to ClusterIP
+	Service ServiceSpec `json:"service,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// InitializerSpec is the spec of the initializer, which installs the dashboards and the Prometheus rules
+// of the version of the monitored clusters
+type InitializerSpec struct {
+	MonitorContainer
+
+	// Envs of the initializer
+	Envs map[string]string `json:"envs,omitempty"`
+}
+
+// TidbMonitorStatus is the status of TidbMonitor
+type TidbMonitorStatus struct {
+	// Deployment is the status of the Deployment of the monitor Pod
+	Deployment *apps.DeploymentStatus `json:"deployment,omitempty"`
+	// DashboardVersion is the version of the installed dashboards and Prometheus rules
+	DashboardVersion string `json:"dashboardVersion,omitempty"`
+	// Clusters are the monitored clusters with the number of the scraped targets
+	Clusters []MonitoredCluster `json:"clusters,omitempty"`
+}
+
+// MonitoredCluster is the status of a tidb cluster monitored by TidbMonitor
+type MonitoredCluster struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Targets is the number of the scraped Pods of the cluster
+	Targets int32 `json:"targets"`
+	// TLS is true if the Pods are scraped with the client certificate of the cluster
+	TLS bool `json:"tls,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// +k8s:openapi-gen=true
+// TidbClusterAutoScaler scales the TiDB and TiKV of a tidb cluster horizontally by the metrics
+type TidbClusterAutoScaler struct {
+	metav1.TypeMeta `json:",inline"`
+	// +k8s:openapi-gen=false
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec TidbClusterAutoScalerSpec `json:"spec"`
+	// +k8s:openapi-gen=false
+	Status TidbClusterAutoScalerStatus `json:"status"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// +k8s:openapi-gen=true
+// TidbClusterAutoScalerList is TidbClusterAutoScaler list
+type TidbClusterAutoScalerList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +k8s:openapi-gen=false
+	metav1.ListMeta `json:"metadata"`
+
+	Items []TidbClusterAutoScaler `json:"items"`
+}
+
+// +k8s:openapi-gen=true
+// TidbClusterAutoScalerSpec describes the bounds and the metrics to scale the TiDB and TiKV
+type TidbClusterAutoScalerSpec struct {
+	// Cluster is the TidbCluster to scale, the namespace defaults to the namespace of the TidbClusterAutoScaler.
+	// Only the cluster running in the local Kubernetes cluster can be scaled
+	Cluster TidbClusterRef `json:"cluster"`
+
+	// MetricsURL is the address of the Prometheus compatible API querying the metrics of the cluster,
+	// e.g. http://prometheus:9090
+	// +optional
+	MetricsURL string `json:"metricsUrl,omitempty"`
+
+	// Monitor is the TidbMonitor of the cluster, the metrics are queried from its Prometheus if MetricsURL is empty
+	// +optional
+	Monitor *TidbMonitorRef `json:"monitor,omitempty"`
+
+	// TiKV is the auto-scaling of TiKV, TiKV isn't scaled if it's nil
+	// +optional
+	TiKV *TikvAutoScalerSpec `json:"tikv,omitempty"`
+
+	// TiDB is the auto-scaling of TiDB, TiDB isn't scaled if it's nil
+	// +optional
+	TiDB *TidbAutoScalerSpec `json:"tidb,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// TidbMonitorRef is a reference to a TidbMonitor
+type TidbMonitorRef struct {
+	// Namespace is the namespace of the TidbMonitor, defaults to the namespace of the referring object
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name is the name of the TidbMonitor
+	Name string `json:"name"`
+}
+
+// +k8s:openapi-gen=true
+// BasicAutoScalerSpec is the common auto-scaling spec of TiDB and TiKV
+type BasicAutoScalerSpec struct {
+	// MaxReplicas is the upper limit of the replicas
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// MinReplicas is the lower limit of the replicas, defaults to 1 for TiDB and 3 for TiKV.
+	// TiKV is never scaled in below the max-replicas of PD
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// ScaleOutIntervalSeconds is the cooldown after the last scaling before scaling out, defaults to 300
+	// +optional
+	ScaleOutIntervalSeconds *int32 `json:"scaleOutIntervalSeconds,omitempty"`
+
+	// ScaleInIntervalSeconds is the cooldown after the last scaling before scaling in, defaults to 500
+	// +optional
+	ScaleInIntervalSeconds *int32 `json:"scaleInIntervalSeconds,omitempty"`
+
+	// MetricsTimeDuration is the time range of the rate of the metrics, defaults to 3m
+	// +optional
+	MetricsTimeDuration string `json:"metricsTimeDuration,omitempty"`
+
+	// Metrics are the metrics to compute the recommended replicas, the largest recommendation of them is taken.
+	// The CPU utilization of 80% is the default
+	// +optional
+	Metrics []AutoScalerMetric `json:"metrics,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// TikvAutoScalerSpec is the auto-scaling spec of TiKV
+type TikvAutoScalerSpec struct {
+	BasicAutoScalerSpec `json:",inline"`
+}
+
+// +k8s:openapi-gen=true
+// TidbAutoScalerSpec is the auto-scaling spec of TiDB
+type TidbAutoScalerSpec struct {
+	BasicAutoScalerSpec `json:",inline"`
+}
+
+// AutoScalerMetricType is the type of the metric driving the auto-scaling
+type AutoScalerMetricType string
+
+const (
+	// CPUAutoScalerMetricType is the CPU usage in proportion to the CPU requests of the Pods
+	CPUAutoScalerMetricType AutoScalerMetricType = "cpu"
+	// QPSAutoScalerMetricType is the requests per second of each instance
+	QPSAutoScalerMetricType AutoScalerMetricType = "qps"
+	// StorageAutoScalerMetricType is the used proportion of the store capacity reported by PD, it's TiKV only
+	// and never scales TiKV in
+	StorageAutoScalerMetricType AutoScalerMetricType = "storage"
+)
+
+// +k8s:openapi-gen=true
+// AutoScalerMetric is a metric and its target value
+type AutoScalerMetric struct {
+	// Type is one of cpu, qps and storage
+	Type AutoScalerMetricType `json:"type"`
+
+	// TargetAverageUtilization is the target percentage of the cpu and storage metrics
+	// +optional
+	TargetAverageUtilization *int32 `json:"targetAverageUtilization,omitempty"`
+
+	// TargetAverageValue is the target value per instance of the qps metric
+	// +optional
+	TargetAverageValue *int64 `json:"targetAverageValue,omitempty"`
+}
+
+// TidbClusterAutoScalerStatus is the status of the auto-scaling
+type TidbClusterAutoScalerStatus struct {
+	TiKV *AutoScalerStatus `json:"tikv,omitempty"`
+	TiDB *AutoScalerStatus `json:"tidb,omitempty"`
+}
+
+// AutoScalerStatus is the last auto-scaling decision of a component
+type AutoScalerStatus struct {
+	// Metrics are the observed metrics
+	Metrics []MetricsStatus `json:"metrics,omitempty"`
+	// CurrentReplicas is the replicas when the decision was made
+	CurrentReplicas int32 `json:"currentReplicas"`
+	// RecommendedReplicas is the replicas recommended by the metrics within the bounds
+	RecommendedReplicas int32 `json:"recommendedReplicas"`
+	// LastAutoScalingTimestamp is the last time the replicas were changed by the auto-scaler
+	LastAutoScalingTimestamp *metav1.Time `json:"lastAutoScalingTimestamp,omitempty"`
+	// Message explains the decision, e.g. the scaling is in cooldown
+	Message string `json:"message,omitempty"`
+}
+
+// MetricsStatus is the observed value of a metric
+type MetricsStatus struct {
+	Type AutoScalerMetricType `json:"type"`
+	// CurrentValue is the observed average value, in percentage for the cpu and storage metrics
+	CurrentValue string `json:"currentValue"`
+	// TargetValue is the target average value
+	TargetValue string `json:"targetValue"`
+	// RecommendedReplicas is the replicas recommended by the metric
+	RecommendedReplicas int32 `json:"recommendedReplicas"`
+}
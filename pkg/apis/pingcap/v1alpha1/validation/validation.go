@@ -0,0 +1,99 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation
+
+import (
+	"fmt"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ValidateTidbCluster validates a TidbCluster, the cluster is not synced until the returned errors are fixed
+func ValidateTidbCluster(tc *v1alpha1.TidbCluster) field.ErrorList {
+	allErrs := field.ErrorList{}
+	allErrs = append(allErrs, validateTiDBSpec(tc, &tc.Spec.TiDB, field.NewPath("spec", "tidb"))...)
+	allErrs = append(allErrs, validateTiKVGroups(tc.Spec.TiKVGroups, field.NewPath("spec", "tikvGroups"))...)
+	allErrs = append(allErrs, validateTiDBGroups(tc.Spec.TiDBGroups, field.NewPath("spec", "tidbGroups"))...)
+	return allErrs
+}
+
+// reservedTiKVGroupNames are the group names whose StatefulSet <cluster>-tikv-<name> would collide
+// with the other objects of TiKV
+var reservedTiKVGroupNames = sets.NewString("peer")
+
+func validateTiKVGroups(groups []v1alpha1.TiKVGroupSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := sets.NewString()
+	for i, group := range groups {
+		allErrs = append(allErrs, validateGroupName(group.Name, names, reservedTiKVGroupNames, fldPath.Index(i).Child("name"))...)
+	}
+	return allErrs
+}
+
+// reservedTiDBGroupNames are the group names whose StatefulSet, Service and ConfigMap <cluster>-tidb-<name> would collide
+// with the other objects of TiDB
+var reservedTiDBGroupNames = sets.NewString("peer", "client-ca")
+
+func validateTiDBGroups(groups []v1alpha1.TiDBGroupSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := sets.NewString()
+	for i, group := range groups {
+		allErrs = append(allErrs, validateGroupName(group.Name, names, reservedTiDBGroupNames, fldPath.Index(i).Child("name"))...)
+	}
+	return allErrs
+}
+
+// validateGroupName validates the name of a group, which is a part of the names of its StatefulSet,
+// Service and ConfigMap, so it must be a DNS-1123 label not used by other groups or reserved
+func validateGroupName(name string, names, reserved sets.String, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if name == "" {
+		return append(allErrs, field.Required(fldPath, ""))
+	}
+	for _, msg := range validation.IsDNS1123Label(name) {
+		allErrs = append(allErrs, field.Invalid(fldPath, name, msg))
+	}
+	if reserved.Has(name) {
+		allErrs = append(allErrs, field.Invalid(fldPath, name, "the name is reserved"))
+	}
+	if names.Has(name) {
+		allErrs = append(allErrs, field.Duplicate(fldPath, name))
+	}
+	names.Insert(name)
+	return allErrs
+}
+
+func validateTiDBSpec(tc *v1alpha1.TidbCluster, spec *v1alpha1.TiDBSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if spec.TLSClient != nil {
+		allErrs = append(allErrs, validateTiDBTLSClient(tc, spec.TLSClient, fldPath.Child("tlsClient"))...)
+	}
+	return allErrs
+}
+
+func validateTiDBTLSClient(tc *v1alpha1.TidbCluster, tlsClient *v1alpha1.TiDBTLSClient, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, user := range tlsClient.Users {
+		// the client cert pair of the user is issued into the Secret <cluster>-tidb-client-<user>
+		secretName := fmt.Sprintf("%s-tidb-client-%s", tc.GetName(), user)
+		for _, msg := range validation.IsDNS1123Subdomain(secretName) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("users").Index(i), user,
+				fmt.Sprintf("the Secret name %s of the user is invalid: %s", secretName, msg)))
+		}
+	}
+	return allErrs
+}
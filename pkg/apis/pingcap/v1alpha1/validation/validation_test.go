@@ -0,0 +1,98 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateTidbCluster(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name     string
+		update   func(*v1alpha1.TidbCluster)
+		expected []string
+	}
+
+	tests := []testcase{
+		{
+			name:     "valid cluster",
+			update:   func(tc *v1alpha1.TidbCluster) {},
+			expected: nil,
+		},
+		{
+			name: "valid tls client users",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.TiDB.TLSClient = &v1alpha1.TiDBTLSClient{Users: []string{"app", "app.readonly", "app-1"}}
+			},
+			expected: nil,
+		},
+		{
+			name: "invalid tls client users",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.TiDB.TLSClient = &v1alpha1.TiDBTLSClient{Users: []string{"app", "app_user", "App"}}
+			},
+			expected: []string{"spec.tidb.tlsClient.users[1]", "spec.tidb.tlsClient.users[2]"},
+		},
+		{
+			name: "valid tikv groups",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.TiKVGroups = []v1alpha1.TiKVGroupSpec{{Name: "ssd"}, {Name: "hdd-1"}}
+			},
+			expected: nil,
+		},
+		{
+			name: "invalid tikv groups",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.TiKVGroups = []v1alpha1.TiKVGroupSpec{{Name: "ssd"}, {Name: ""}, {Name: "peer"}, {Name: "SSD"}, {Name: "ssd"}}
+			},
+			expected: []string{
+				"spec.tikvGroups[1].name",
+				"spec.tikvGroups[2].name",
+				"spec.tikvGroups[3].name",
+				"spec.tikvGroups[4].name",
+			},
+		},
+		{
+			name: "invalid tidb groups",
+			update: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.TiDBGroups = []v1alpha1.TiDBGroupSpec{{Name: "reporting"}, {Name: "peer"}, {Name: "client-ca"}, {Name: "ad_hoc"}}
+			},
+			expected: []string{
+				"spec.tidbGroups[1].name",
+				"spec.tidbGroups[2].name",
+				"spec.tidbGroups[3].name",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Log(test.name)
+		tc := &v1alpha1.TidbCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: metav1.NamespaceDefault},
+		}
+		test.update(tc)
+
+		var fields []string
+		for _, err := range ValidateTidbCluster(tc) {
+			fields = append(fields, err.Field)
+		}
+		g.Expect(fields).To(Equal(test.expected))
+	}
+}
@@ -21,9 +21,62 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	v1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	intstr "k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoScalerMetric) DeepCopyInto(out *AutoScalerMetric) {
+	*out = *in
+	if in.TargetAverageUtilization != nil {
+		in, out := &in.TargetAverageUtilization, &out.TargetAverageUtilization
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetAverageValue != nil {
+		in, out := &in.TargetAverageValue, &out.TargetAverageValue
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoScalerMetric.
+func (in *AutoScalerMetric) DeepCopy() *AutoScalerMetric {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoScalerMetric)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoScalerStatus) DeepCopyInto(out *AutoScalerStatus) {
+	*out = *in
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]MetricsStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastAutoScalingTimestamp != nil {
+		in, out := &in.LastAutoScalingTimestamp, &out.LastAutoScalingTimestamp
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoScalerStatus.
+func (in *AutoScalerStatus) DeepCopy() *AutoScalerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoScalerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Backup) DeepCopyInto(out *Backup) {
 	*out = *in
@@ -256,6 +309,44 @@ func (in *BackupStatus) DeepCopy() *BackupStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BasicAutoScalerSpec) DeepCopyInto(out *BasicAutoScalerSpec) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ScaleOutIntervalSeconds != nil {
+		in, out := &in.ScaleOutIntervalSeconds, &out.ScaleOutIntervalSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ScaleInIntervalSeconds != nil {
+		in, out := &in.ScaleInIntervalSeconds, &out.ScaleInIntervalSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]AutoScalerMetric, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BasicAutoScalerSpec.
+func (in *BasicAutoScalerSpec) DeepCopy() *BasicAutoScalerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BasicAutoScalerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Binlog) DeepCopyInto(out *Binlog) {
 	*out = *in
@@ -272,6 +363,22 @@ func (in *Binlog) DeepCopy() *Binlog {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertManagerIssuer) DeepCopyInto(out *CertManagerIssuer) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerIssuer.
+func (in *CertManagerIssuer) DeepCopy() *CertManagerIssuer {
+	if in == nil {
+		return nil
+	}
+	out := new(CertManagerIssuer)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ComponentSpec) DeepCopyInto(out *ComponentSpec) {
 	*out = *in
@@ -362,6 +469,9 @@ func (in *CrdKinds) DeepCopyInto(out *CrdKinds) {
 	in.Backup.DeepCopyInto(&out.Backup)
 	in.Restore.DeepCopyInto(&out.Restore)
 	in.BackupSchedule.DeepCopyInto(&out.BackupSchedule)
+	in.DataImport.DeepCopyInto(&out.DataImport)
+	in.TiDBMonitor.DeepCopyInto(&out.TiDBMonitor)
+	in.TidbClusterAutoScaler.DeepCopyInto(&out.TidbClusterAutoScaler)
 	return
 }
 
@@ -376,30 +486,27 @@ func (in *CrdKinds) DeepCopy() *CrdKinds {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataResource) DeepCopyInto(out *DataResource) {
+func (in *DataImport) DeepCopyInto(out *DataImport) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	if in.Data != nil {
-		in, out := &in.Data, &out.Data
-		*out = make([]byte, len(*in))
-		copy(*out, *in)
-	}
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataResource.
-func (in *DataResource) DeepCopy() *DataResource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataImport.
+func (in *DataImport) DeepCopy() *DataImport {
 	if in == nil {
 		return nil
 	}
-	out := new(DataResource)
+	out := new(DataImport)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DataResource) DeepCopyObject() runtime.Object {
+func (in *DataImport) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -407,13 +514,30 @@ func (in *DataResource) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DataResourceList) DeepCopyInto(out *DataResourceList) {
+func (in *DataImportCondition) DeepCopyInto(out *DataImportCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataImportCondition.
+func (in *DataImportCondition) DeepCopy() *DataImportCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(DataImportCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataImportList) DeepCopyInto(out *DataImportList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]DataResource, len(*in))
+		*out = make([]DataImport, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -421,18 +545,18 @@ func (in *DataResourceList) DeepCopyInto(out *DataResourceList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataResourceList.
-func (in *DataResourceList) DeepCopy() *DataResourceList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataImportList.
+func (in *DataImportList) DeepCopy() *DataImportList {
 	if in == nil {
 		return nil
 	}
-	out := new(DataResourceList)
+	out := new(DataImportList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *DataResourceList) DeepCopyObject() runtime.Object {
+func (in *DataImportList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -440,1140 +564,2345 @@ func (in *DataResourceList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GcsStorageProvider) DeepCopyInto(out *GcsStorageProvider) {
+func (in *DataImportSpec) DeepCopyInto(out *DataImportSpec) {
 	*out = *in
+	in.StorageProvider.DeepCopyInto(&out.StorageProvider)
+	in.Resources.DeepCopyInto(&out.Resources)
+	in.GenericConfig.DeepCopyInto(&out.GenericConfig)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GcsStorageProvider.
-func (in *GcsStorageProvider) DeepCopy() *GcsStorageProvider {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataImportSpec.
+func (in *DataImportSpec) DeepCopy() *DataImportSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(GcsStorageProvider)
+	out := new(DataImportSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HelperSpec) DeepCopyInto(out *HelperSpec) {
+func (in *DataImportStatus) DeepCopyInto(out *DataImportStatus) {
 	*out = *in
-	if in.ImagePullPolicy != nil {
-		in, out := &in.ImagePullPolicy, &out.ImagePullPolicy
-		*out = new(v1.PullPolicy)
-		**out = **in
+	in.TimeStarted.DeepCopyInto(&out.TimeStarted)
+	in.TimeCompleted.DeepCopyInto(&out.TimeCompleted)
+	if in.Checksums != nil {
+		in, out := &in.Checksums, &out.Checksums
+		*out = make([]ImportTableChecksum, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]DataImportCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelperSpec.
-func (in *HelperSpec) DeepCopy() *HelperSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataImportStatus.
+func (in *DataImportStatus) DeepCopy() *DataImportStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(HelperSpec)
+	out := new(DataImportStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Log) DeepCopyInto(out *Log) {
+func (in *DataResource) DeepCopyInto(out *DataResource) {
 	*out = *in
-	out.File = in.File
-	if in.SlowThreshold != nil {
-		in, out := &in.SlowThreshold, &out.SlowThreshold
-		*out = new(uint64)
-		**out = **in
-	}
-	if in.ExpensiveThreshold != nil {
-		in, out := &in.ExpensiveThreshold, &out.ExpensiveThreshold
-		*out = new(uint)
-		**out = **in
-	}
-	if in.QueryLogMaxLen != nil {
-		in, out := &in.QueryLogMaxLen, &out.QueryLogMaxLen
-		*out = new(uint64)
-		**out = **in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Data != nil {
+		in, out := &in.Data, &out.Data
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Log.
-func (in *Log) DeepCopy() *Log {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataResource.
+func (in *DataResource) DeepCopy() *DataResource {
 	if in == nil {
 		return nil
 	}
-	out := new(Log)
+	out := new(DataResource)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DataResource) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpenTracing) DeepCopyInto(out *OpenTracing) {
+func (in *DataResourceList) DeepCopyInto(out *DataResourceList) {
 	*out = *in
-	in.Sampler.DeepCopyInto(&out.Sampler)
-	in.Reporter.DeepCopyInto(&out.Reporter)
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DataResource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenTracing.
-func (in *OpenTracing) DeepCopy() *OpenTracing {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataResourceList.
+func (in *DataResourceList) DeepCopy() *DataResourceList {
 	if in == nil {
 		return nil
 	}
-	out := new(OpenTracing)
+	out := new(DataResourceList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DataResourceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpenTracingReporter) DeepCopyInto(out *OpenTracingReporter) {
+func (in *DrainerSinkSpec) DeepCopyInto(out *DrainerSinkSpec) {
 	*out = *in
-	if in.QueueSize != nil {
-		in, out := &in.QueueSize, &out.QueueSize
-		*out = new(int)
-		**out = **in
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenTracingReporter.
-func (in *OpenTracingReporter) DeepCopy() *OpenTracingReporter {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrainerSinkSpec.
+func (in *DrainerSinkSpec) DeepCopy() *DrainerSinkSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(OpenTracingReporter)
+	out := new(DrainerSinkSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpenTracingSampler) DeepCopyInto(out *OpenTracingSampler) {
+func (in *DrainerSpec) DeepCopyInto(out *DrainerSpec) {
 	*out = *in
-	if in.Param != nil {
-		in, out := &in.Param, &out.Param
-		*out = new(float64)
-		**out = **in
-	}
-	if in.MaxOperations != nil {
-		in, out := &in.MaxOperations, &out.MaxOperations
-		*out = new(int)
-		**out = **in
+	in.ComponentSpec.DeepCopyInto(&out.ComponentSpec)
+	in.Resources.DeepCopyInto(&out.Resources)
+	out.Sink = in.Sink
+	if in.UpgradeStrategy != nil {
+		in, out := &in.UpgradeStrategy, &out.UpgradeStrategy
+		*out = new(UpgradeStrategy)
+		(*in).DeepCopyInto(*out)
 	}
+	in.GenericConfig.DeepCopyInto(&out.GenericConfig)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenTracingSampler.
-func (in *OpenTracingSampler) DeepCopy() *OpenTracingSampler {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrainerSpec.
+func (in *DrainerSpec) DeepCopy() *DrainerSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(OpenTracingSampler)
+	out := new(DrainerSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PDFailureMember) DeepCopyInto(out *PDFailureMember) {
+func (in *DrainerStatus) DeepCopyInto(out *DrainerStatus) {
 	*out = *in
-	in.CreatedAt.DeepCopyInto(&out.CreatedAt)
+	if in.StatefulSet != nil {
+		in, out := &in.StatefulSet, &out.StatefulSet
+		*out = new(appsv1.StatefulSetStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Lag != nil {
+		in, out := &in.Lag, &out.Lag
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(UpgradeProgress)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PDFailureMember.
-func (in *PDFailureMember) DeepCopy() *PDFailureMember {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DrainerStatus.
+func (in *DrainerStatus) DeepCopy() *DrainerStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PDFailureMember)
+	out := new(DrainerStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PDMember) DeepCopyInto(out *PDMember) {
+func (in *GcsStorageProvider) DeepCopyInto(out *GcsStorageProvider) {
 	*out = *in
-	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PDMember.
-func (in *PDMember) DeepCopy() *PDMember {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GcsStorageProvider.
+func (in *GcsStorageProvider) DeepCopy() *GcsStorageProvider {
 	if in == nil {
 		return nil
 	}
-	out := new(PDMember)
+	out := new(GcsStorageProvider)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PDSpec) DeepCopyInto(out *PDSpec) {
+func (in *GrafanaSpec) DeepCopyInto(out *GrafanaSpec) {
 	*out = *in
-	in.ComponentSpec.DeepCopyInto(&out.ComponentSpec)
-	in.Resources.DeepCopyInto(&out.Resources)
-	if in.Service != nil {
-		in, out := &in.Service, &out.Service
-		*out = new(ServiceSpec)
-		(*in).DeepCopyInto(*out)
+	in.MonitorContainer.DeepCopyInto(&out.MonitorContainer)
+	in.Service.DeepCopyInto(&out.Service)
+	if in.Envs != nil {
+		in, out := &in.Envs, &out.Envs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
-	in.GenericConfig.DeepCopyInto(&out.GenericConfig)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PDSpec.
-func (in *PDSpec) DeepCopy() *PDSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaSpec.
+func (in *GrafanaSpec) DeepCopy() *GrafanaSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(PDSpec)
+	out := new(GrafanaSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PDStatus) DeepCopyInto(out *PDStatus) {
-	*out = *in
-	if in.StatefulSet != nil {
-		in, out := &in.StatefulSet, &out.StatefulSet
-		*out = new(appsv1.StatefulSetStatus)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Members != nil {
-		in, out := &in.Members, &out.Members
-		*out = make(map[string]PDMember, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
-	}
-	in.Leader.DeepCopyInto(&out.Leader)
-	if in.FailureMembers != nil {
-		in, out := &in.FailureMembers, &out.FailureMembers
-		*out = make(map[string]PDFailureMember, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
-	}
-	return
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PDStatus.
-func (in *PDStatus) DeepCopy() *PDStatus {
-	if in == nil {
-		return nil
-	}
-	out := new(PDStatus)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Performance) DeepCopyInto(out *Performance) {
+func (in *HelperSpec) DeepCopyInto(out *HelperSpec) {
 	*out = *in
-	if in.MaxProcs != nil {
-		in, out := &in.MaxProcs, &out.MaxProcs
-		*out = new(uint)
-		**out = **in
-	}
-	if in.MaxMemory != nil {
-		in, out := &in.MaxMemory, &out.MaxMemory
-		*out = new(uint64)
-		**out = **in
-	}
-	if in.StmtCountLimit != nil {
-		in, out := &in.StmtCountLimit, &out.StmtCountLimit
-		*out = new(uint)
-		**out = **in
-	}
-	if in.FeedbackProbability != nil {
-		in, out := &in.FeedbackProbability, &out.FeedbackProbability
-		*out = new(float64)
-		**out = **in
-	}
-	if in.QueryFeedbackLimit != nil {
-		in, out := &in.QueryFeedbackLimit, &out.QueryFeedbackLimit
-		*out = new(uint)
-		**out = **in
-	}
-	if in.PseudoEstimateRatio != nil {
-		in, out := &in.PseudoEstimateRatio, &out.PseudoEstimateRatio
-		*out = new(float64)
-		**out = **in
-	}
-	if in.TxnEntryCountLimit != nil {
-		in, out := &in.TxnEntryCountLimit, &out.TxnEntryCountLimit
-		*out = new(uint64)
-		**out = **in
-	}
-	if in.TxnTotalSizeLimit != nil {
-		in, out := &in.TxnTotalSizeLimit, &out.TxnTotalSizeLimit
-		*out = new(uint64)
+	if in.ImagePullPolicy != nil {
+		in, out := &in.ImagePullPolicy, &out.ImagePullPolicy
+		*out = new(v1.PullPolicy)
 		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Performance.
-func (in *Performance) DeepCopy() *Performance {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelperSpec.
+func (in *HelperSpec) DeepCopy() *HelperSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(Performance)
+	out := new(HelperSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PessimisticTxn) DeepCopyInto(out *PessimisticTxn) {
+func (in *ImportTableChecksum) DeepCopyInto(out *ImportTableChecksum) {
 	*out = *in
-	if in.MaxRetryCount != nil {
-		in, out := &in.MaxRetryCount, &out.MaxRetryCount
-		*out = new(uint)
-		**out = **in
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PessimisticTxn.
-func (in *PessimisticTxn) DeepCopy() *PessimisticTxn {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImportTableChecksum.
+func (in *ImportTableChecksum) DeepCopy() *ImportTableChecksum {
 	if in == nil {
 		return nil
 	}
-	out := new(PessimisticTxn)
+	out := new(ImportTableChecksum)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PlanCache) DeepCopyInto(out *PlanCache) {
+func (in *InitializerSpec) DeepCopyInto(out *InitializerSpec) {
 	*out = *in
-	if in.Capacity != nil {
-		in, out := &in.Capacity, &out.Capacity
-		*out = new(uint)
-		**out = **in
-	}
-	if in.Shards != nil {
-		in, out := &in.Shards, &out.Shards
-		*out = new(uint)
-		**out = **in
+	in.MonitorContainer.DeepCopyInto(&out.MonitorContainer)
+	if in.Envs != nil {
+		in, out := &in.Envs, &out.Envs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlanCache.
-func (in *PlanCache) DeepCopy() *PlanCache {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InitializerSpec.
+func (in *InitializerSpec) DeepCopy() *InitializerSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(PlanCache)
+	out := new(InitializerSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Plugin) DeepCopyInto(out *Plugin) {
+func (in *LocalStorageProvider) DeepCopyInto(out *LocalStorageProvider) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Plugin.
-func (in *Plugin) DeepCopy() *Plugin {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalStorageProvider.
+func (in *LocalStorageProvider) DeepCopy() *LocalStorageProvider {
 	if in == nil {
 		return nil
 	}
-	out := new(Plugin)
+	out := new(LocalStorageProvider)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PreparedPlanCache) DeepCopyInto(out *PreparedPlanCache) {
+func (in *Log) DeepCopyInto(out *Log) {
 	*out = *in
-	if in.Capacity != nil {
-		in, out := &in.Capacity, &out.Capacity
+	out.File = in.File
+	if in.SlowThreshold != nil {
+		in, out := &in.SlowThreshold, &out.SlowThreshold
+		*out = new(uint64)
+		**out = **in
+	}
+	if in.ExpensiveThreshold != nil {
+		in, out := &in.ExpensiveThreshold, &out.ExpensiveThreshold
 		*out = new(uint)
 		**out = **in
 	}
-	if in.MemoryGuardRatio != nil {
-		in, out := &in.MemoryGuardRatio, &out.MemoryGuardRatio
-		*out = new(float64)
+	if in.QueryLogMaxLen != nil {
+		in, out := &in.QueryLogMaxLen, &out.QueryLogMaxLen
+		*out = new(uint64)
 		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreparedPlanCache.
-func (in *PreparedPlanCache) DeepCopy() *PreparedPlanCache {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Log.
+func (in *Log) DeepCopy() *Log {
 	if in == nil {
 		return nil
 	}
-	out := new(PreparedPlanCache)
+	out := new(Log)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProxyProtocol) DeepCopyInto(out *ProxyProtocol) {
+func (in *MemberReplacement) DeepCopyInto(out *MemberReplacement) {
 	*out = *in
-	if in.HeaderTimeout != nil {
-		in, out := &in.HeaderTimeout, &out.HeaderTimeout
-		*out = new(uint)
-		**out = **in
-	}
+	in.StartTime.DeepCopyInto(&out.StartTime)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyProtocol.
-func (in *ProxyProtocol) DeepCopy() *ProxyProtocol {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemberReplacement.
+func (in *MemberReplacement) DeepCopy() *MemberReplacement {
 	if in == nil {
 		return nil
 	}
-	out := new(ProxyProtocol)
+	out := new(MemberReplacement)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PumpSpec) DeepCopyInto(out *PumpSpec) {
+func (in *MetricsStatus) DeepCopyInto(out *MetricsStatus) {
 	*out = *in
-	in.ComponentSpec.DeepCopyInto(&out.ComponentSpec)
-	in.Resources.DeepCopyInto(&out.Resources)
-	in.GenericConfig.DeepCopyInto(&out.GenericConfig)
-	if in.SetTimeZone != nil {
-		in, out := &in.SetTimeZone, &out.SetTimeZone
-		*out = new(bool)
-		**out = **in
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PumpSpec.
-func (in *PumpSpec) DeepCopy() *PumpSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsStatus.
+func (in *MetricsStatus) DeepCopy() *MetricsStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PumpSpec)
+	out := new(MetricsStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ResourceRequirement) DeepCopyInto(out *ResourceRequirement) {
+func (in *MonitorClusterRef) DeepCopyInto(out *MonitorClusterRef) {
 	*out = *in
+	out.TidbClusterRef = in.TidbClusterRef
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceRequirement.
-func (in *ResourceRequirement) DeepCopy() *ResourceRequirement {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitorClusterRef.
+func (in *MonitorClusterRef) DeepCopy() *MonitorClusterRef {
 	if in == nil {
 		return nil
 	}
-	out := new(ResourceRequirement)
+	out := new(MonitorClusterRef)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Resources) DeepCopyInto(out *Resources) {
+func (in *MonitorContainer) DeepCopyInto(out *MonitorContainer) {
 	*out = *in
-	if in.Requests != nil {
-		in, out := &in.Requests, &out.Requests
-		*out = new(ResourceRequirement)
-		**out = **in
-	}
-	if in.Limits != nil {
-		in, out := &in.Limits, &out.Limits
-		*out = new(ResourceRequirement)
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.ImagePullPolicy != nil {
+		in, out := &in.ImagePullPolicy, &out.ImagePullPolicy
+		*out = new(v1.PullPolicy)
 		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Resources.
-func (in *Resources) DeepCopy() *Resources {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitorContainer.
+func (in *MonitorContainer) DeepCopy() *MonitorContainer {
 	if in == nil {
 		return nil
 	}
-	out := new(Resources)
+	out := new(MonitorContainer)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Restore) DeepCopyInto(out *Restore) {
+func (in *MonitoredCluster) DeepCopyInto(out *MonitoredCluster) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Restore.
-func (in *Restore) DeepCopy() *Restore {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoredCluster.
+func (in *MonitoredCluster) DeepCopy() *MonitoredCluster {
 	if in == nil {
 		return nil
 	}
-	out := new(Restore)
+	out := new(MonitoredCluster)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Restore) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RestoreCondition) DeepCopyInto(out *RestoreCondition) {
+func (in *OpenTracing) DeepCopyInto(out *OpenTracing) {
 	*out = *in
-	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	in.Sampler.DeepCopyInto(&out.Sampler)
+	in.Reporter.DeepCopyInto(&out.Reporter)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreCondition.
-func (in *RestoreCondition) DeepCopy() *RestoreCondition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenTracing.
+func (in *OpenTracing) DeepCopy() *OpenTracing {
 	if in == nil {
 		return nil
 	}
-	out := new(RestoreCondition)
+	out := new(OpenTracing)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RestoreList) DeepCopyInto(out *RestoreList) {
+func (in *OpenTracingReporter) DeepCopyInto(out *OpenTracingReporter) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Restore, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.QueueSize != nil {
+		in, out := &in.QueueSize, &out.QueueSize
+		*out = new(int)
+		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreList.
-func (in *RestoreList) DeepCopy() *RestoreList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenTracingReporter.
+func (in *OpenTracingReporter) DeepCopy() *OpenTracingReporter {
 	if in == nil {
 		return nil
 	}
-	out := new(RestoreList)
+	out := new(OpenTracingReporter)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *RestoreList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RestoreSpec) DeepCopyInto(out *RestoreSpec) {
+func (in *OpenTracingSampler) DeepCopyInto(out *OpenTracingSampler) {
 	*out = *in
+	if in.Param != nil {
+		in, out := &in.Param, &out.Param
+		*out = new(float64)
+		**out = **in
+	}
+	if in.MaxOperations != nil {
+		in, out := &in.MaxOperations, &out.MaxOperations
+		*out = new(int)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreSpec.
-func (in *RestoreSpec) DeepCopy() *RestoreSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenTracingSampler.
+func (in *OpenTracingSampler) DeepCopy() *OpenTracingSampler {
 	if in == nil {
 		return nil
 	}
-	out := new(RestoreSpec)
+	out := new(OpenTracingSampler)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RestoreStatus) DeepCopyInto(out *RestoreStatus) {
+func (in *PDFailureMember) DeepCopyInto(out *PDFailureMember) {
 	*out = *in
-	in.TimeStarted.DeepCopyInto(&out.TimeStarted)
-	in.TimeCompleted.DeepCopyInto(&out.TimeCompleted)
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]RestoreCondition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	in.CreatedAt.DeepCopyInto(&out.CreatedAt)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreStatus.
-func (in *RestoreStatus) DeepCopy() *RestoreStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PDFailureMember.
+func (in *PDFailureMember) DeepCopy() *PDFailureMember {
 	if in == nil {
 		return nil
 	}
-	out := new(RestoreStatus)
+	out := new(PDFailureMember)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *S3StorageProvider) DeepCopyInto(out *S3StorageProvider) {
+func (in *PDMember) DeepCopyInto(out *PDMember) {
 	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3StorageProvider.
-func (in *S3StorageProvider) DeepCopy() *S3StorageProvider {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PDMember.
+func (in *PDMember) DeepCopy() *PDMember {
 	if in == nil {
 		return nil
 	}
-	out := new(S3StorageProvider)
+	out := new(PDMember)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Security) DeepCopyInto(out *Security) {
+func (in *PDSpec) DeepCopyInto(out *PDSpec) {
 	*out = *in
+	in.ComponentSpec.DeepCopyInto(&out.ComponentSpec)
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(ServiceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	in.GenericConfig.DeepCopyInto(&out.GenericConfig)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Security.
-func (in *Security) DeepCopy() *Security {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PDSpec.
+func (in *PDSpec) DeepCopy() *PDSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(Security)
+	out := new(PDSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Service) DeepCopyInto(out *Service) {
+func (in *PDStatus) DeepCopyInto(out *PDStatus) {
 	*out = *in
+	if in.StatefulSet != nil {
+		in, out := &in.StatefulSet, &out.StatefulSet
+		*out = new(appsv1.StatefulSetStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make(map[string]PDMember, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	in.Leader.DeepCopyInto(&out.Leader)
+	if in.FailureMembers != nil {
+		in, out := &in.FailureMembers, &out.FailureMembers
+		*out = make(map[string]PDFailureMember, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.PeerMembers != nil {
+		in, out := &in.PeerMembers, &out.PeerMembers
+		*out = make(map[string]PDMember, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Service.
-func (in *Service) DeepCopy() *Service {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PDStatus.
+func (in *PDStatus) DeepCopy() *PDStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(Service)
+	out := new(PDStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ServiceSpec) DeepCopyInto(out *ServiceSpec) {
+func (in *PVCResizeStatus) DeepCopyInto(out *PVCResizeStatus) {
 	*out = *in
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceSpec.
-func (in *ServiceSpec) DeepCopy() *ServiceSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PVCResizeStatus.
+func (in *PVCResizeStatus) DeepCopy() *PVCResizeStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ServiceSpec)
+	out := new(PVCResizeStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Status) DeepCopyInto(out *Status) {
+func (in *Performance) DeepCopyInto(out *Performance) {
 	*out = *in
-	if in.StatusPort != nil {
-		in, out := &in.StatusPort, &out.StatusPort
+	if in.MaxProcs != nil {
+		in, out := &in.MaxProcs, &out.MaxProcs
 		*out = new(uint)
 		**out = **in
 	}
-	if in.MetricsInterval != nil {
-		in, out := &in.MetricsInterval, &out.MetricsInterval
+	if in.MaxMemory != nil {
+		in, out := &in.MaxMemory, &out.MaxMemory
+		*out = new(uint64)
+		**out = **in
+	}
+	if in.StmtCountLimit != nil {
+		in, out := &in.StmtCountLimit, &out.StmtCountLimit
+		*out = new(uint)
+		**out = **in
+	}
+	if in.FeedbackProbability != nil {
+		in, out := &in.FeedbackProbability, &out.FeedbackProbability
+		*out = new(float64)
+		**out = **in
+	}
+	if in.QueryFeedbackLimit != nil {
+		in, out := &in.QueryFeedbackLimit, &out.QueryFeedbackLimit
 		*out = new(uint)
 		**out = **in
 	}
+	if in.PseudoEstimateRatio != nil {
+		in, out := &in.PseudoEstimateRatio, &out.PseudoEstimateRatio
+		*out = new(float64)
+		**out = **in
+	}
+	if in.TxnEntryCountLimit != nil {
+		in, out := &in.TxnEntryCountLimit, &out.TxnEntryCountLimit
+		*out = new(uint64)
+		**out = **in
+	}
+	if in.TxnTotalSizeLimit != nil {
+		in, out := &in.TxnTotalSizeLimit, &out.TxnTotalSizeLimit
+		*out = new(uint64)
+		**out = **in
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Status.
-func (in *Status) DeepCopy() *Status {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Performance.
+func (in *Performance) DeepCopy() *Performance {
 	if in == nil {
 		return nil
 	}
-	out := new(Status)
+	out := new(Performance)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StmtSummary) DeepCopyInto(out *StmtSummary) {
+func (in *PessimisticTxn) DeepCopyInto(out *PessimisticTxn) {
 	*out = *in
-	if in.MaxStmtCount != nil {
-		in, out := &in.MaxStmtCount, &out.MaxStmtCount
-		*out = new(uint)
-		**out = **in
-	}
-	if in.MaxSQLLength != nil {
-		in, out := &in.MaxSQLLength, &out.MaxSQLLength
+	if in.MaxRetryCount != nil {
+		in, out := &in.MaxRetryCount, &out.MaxRetryCount
 		*out = new(uint)
 		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StmtSummary.
-func (in *StmtSummary) DeepCopy() *StmtSummary {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PessimisticTxn.
+func (in *PessimisticTxn) DeepCopy() *PessimisticTxn {
 	if in == nil {
 		return nil
 	}
-	out := new(StmtSummary)
+	out := new(PessimisticTxn)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StorageProvider) DeepCopyInto(out *StorageProvider) {
+func (in *PlanCache) DeepCopyInto(out *PlanCache) {
 	*out = *in
-	if in.S3 != nil {
-		in, out := &in.S3, &out.S3
-		*out = new(S3StorageProvider)
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		*out = new(uint)
 		**out = **in
 	}
-	if in.Gcs != nil {
-		in, out := &in.Gcs, &out.Gcs
-		*out = new(GcsStorageProvider)
+	if in.Shards != nil {
+		in, out := &in.Shards, &out.Shards
+		*out = new(uint)
 		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageProvider.
-func (in *StorageProvider) DeepCopy() *StorageProvider {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlanCache.
+func (in *PlanCache) DeepCopy() *PlanCache {
 	if in == nil {
 		return nil
 	}
-	out := new(StorageProvider)
+	out := new(PlanCache)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TiDBConfig) DeepCopyInto(out *TiDBConfig) {
+func (in *Plugin) DeepCopyInto(out *Plugin) {
 	*out = *in
-	if in.Port != nil {
-		in, out := &in.Port, &out.Port
-		*out = new(uint)
-		**out = **in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Plugin.
+func (in *Plugin) DeepCopy() *Plugin {
+	if in == nil {
+		return nil
 	}
-	if in.TokenLimit != nil {
-		in, out := &in.TokenLimit, &out.TokenLimit
+	out := new(Plugin)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreparedPlanCache) DeepCopyInto(out *PreparedPlanCache) {
+	*out = *in
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
 		*out = new(uint)
 		**out = **in
 	}
-	if in.MemQuotaQuery != nil {
-		in, out := &in.MemQuotaQuery, &out.MemQuotaQuery
-		*out = new(int64)
+	if in.MemoryGuardRatio != nil {
+		in, out := &in.MemoryGuardRatio, &out.MemoryGuardRatio
+		*out = new(float64)
 		**out = **in
 	}
-	if in.TxnLocalLatches != nil {
-		in, out := &in.TxnLocalLatches, &out.TxnLocalLatches
-		*out = new(TxnLocalLatches)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.LowerCaseTableNames != nil {
-		in, out := &in.LowerCaseTableNames, &out.LowerCaseTableNames
-		*out = new(int)
-		**out = **in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreparedPlanCache.
+func (in *PreparedPlanCache) DeepCopy() *PreparedPlanCache {
+	if in == nil {
+		return nil
 	}
-	if in.Log != nil {
-		in, out := &in.Log, &out.Log
-		*out = new(Log)
-		(*in).DeepCopyInto(*out)
+	out := new(PreparedPlanCache)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusSpec) DeepCopyInto(out *PrometheusSpec) {
+	*out = *in
+	in.MonitorContainer.DeepCopyInto(&out.MonitorContainer)
+	in.Service.DeepCopyInto(&out.Service)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusSpec.
+func (in *PrometheusSpec) DeepCopy() *PrometheusSpec {
+	if in == nil {
+		return nil
 	}
-	if in.Security != nil {
-		in, out := &in.Security, &out.Security
-		*out = new(Security)
+	out := new(PrometheusSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxyProtocol) DeepCopyInto(out *ProxyProtocol) {
+	*out = *in
+	if in.HeaderTimeout != nil {
+		in, out := &in.HeaderTimeout, &out.HeaderTimeout
+		*out = new(uint)
 		**out = **in
 	}
-	if in.Status != nil {
-		in, out := &in.Status, &out.Status
-		*out = new(Status)
-		(*in).DeepCopyInto(*out)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyProtocol.
+func (in *ProxyProtocol) DeepCopy() *ProxyProtocol {
+	if in == nil {
+		return nil
 	}
-	if in.Performance != nil {
+	out := new(ProxyProtocol)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PumpMember) DeepCopyInto(out *PumpMember) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PumpMember.
+func (in *PumpMember) DeepCopy() *PumpMember {
+	if in == nil {
+		return nil
+	}
+	out := new(PumpMember)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PumpSpec) DeepCopyInto(out *PumpSpec) {
+	*out = *in
+	in.ComponentSpec.DeepCopyInto(&out.ComponentSpec)
+	in.Resources.DeepCopyInto(&out.Resources)
+	in.GenericConfig.DeepCopyInto(&out.GenericConfig)
+	if in.SetTimeZone != nil {
+		in, out := &in.SetTimeZone, &out.SetTimeZone
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PumpSpec.
+func (in *PumpSpec) DeepCopy() *PumpSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PumpSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PumpStatus) DeepCopyInto(out *PumpStatus) {
+	*out = *in
+	if in.StatefulSet != nil {
+		in, out := &in.StatefulSet, &out.StatefulSet
+		*out = new(appsv1.StatefulSetStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make(map[string]PumpMember, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PumpStatus.
+func (in *PumpStatus) DeepCopy() *PumpStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PumpStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReloaderSpec) DeepCopyInto(out *ReloaderSpec) {
+	*out = *in
+	in.MonitorContainer.DeepCopyInto(&out.MonitorContainer)
+	in.Service.DeepCopyInto(&out.Service)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReloaderSpec.
+func (in *ReloaderSpec) DeepCopy() *ReloaderSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ReloaderSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceRequirement) DeepCopyInto(out *ResourceRequirement) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceRequirement.
+func (in *ResourceRequirement) DeepCopy() *ResourceRequirement {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceRequirement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Resources) DeepCopyInto(out *Resources) {
+	*out = *in
+	if in.Requests != nil {
+		in, out := &in.Requests, &out.Requests
+		*out = new(ResourceRequirement)
+		**out = **in
+	}
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		*out = new(ResourceRequirement)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Resources.
+func (in *Resources) DeepCopy() *Resources {
+	if in == nil {
+		return nil
+	}
+	out := new(Resources)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Restore) DeepCopyInto(out *Restore) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Restore.
+func (in *Restore) DeepCopy() *Restore {
+	if in == nil {
+		return nil
+	}
+	out := new(Restore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Restore) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreCondition) DeepCopyInto(out *RestoreCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreCondition.
+func (in *RestoreCondition) DeepCopy() *RestoreCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreList) DeepCopyInto(out *RestoreList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Restore, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreList.
+func (in *RestoreList) DeepCopy() *RestoreList {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RestoreList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreSpec) DeepCopyInto(out *RestoreSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreSpec.
+func (in *RestoreSpec) DeepCopy() *RestoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestoreStatus) DeepCopyInto(out *RestoreStatus) {
+	*out = *in
+	in.TimeStarted.DeepCopyInto(&out.TimeStarted)
+	in.TimeCompleted.DeepCopyInto(&out.TimeCompleted)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]RestoreCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestoreStatus.
+func (in *RestoreStatus) DeepCopy() *RestoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RestoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3StorageProvider) DeepCopyInto(out *S3StorageProvider) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3StorageProvider.
+func (in *S3StorageProvider) DeepCopy() *S3StorageProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(S3StorageProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Security) DeepCopyInto(out *Security) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Security.
+func (in *Security) DeepCopy() *Security {
+	if in == nil {
+		return nil
+	}
+	out := new(Security)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Service) DeepCopyInto(out *Service) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Service.
+func (in *Service) DeepCopy() *Service {
+	if in == nil {
+		return nil
+	}
+	out := new(Service)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceSpec) DeepCopyInto(out *ServiceSpec) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceSpec.
+func (in *ServiceSpec) DeepCopy() *ServiceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Status) DeepCopyInto(out *Status) {
+	*out = *in
+	if in.StatusPort != nil {
+		in, out := &in.StatusPort, &out.StatusPort
+		*out = new(uint)
+		**out = **in
+	}
+	if in.MetricsInterval != nil {
+		in, out := &in.MetricsInterval, &out.MetricsInterval
+		*out = new(uint)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Status.
+func (in *Status) DeepCopy() *Status {
+	if in == nil {
+		return nil
+	}
+	out := new(Status)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StmtSummary) DeepCopyInto(out *StmtSummary) {
+	*out = *in
+	if in.MaxStmtCount != nil {
+		in, out := &in.MaxStmtCount, &out.MaxStmtCount
+		*out = new(uint)
+		**out = **in
+	}
+	if in.MaxSQLLength != nil {
+		in, out := &in.MaxSQLLength, &out.MaxSQLLength
+		*out = new(uint)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StmtSummary.
+func (in *StmtSummary) DeepCopy() *StmtSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(StmtSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageMigrationProgress) DeepCopyInto(out *StorageMigrationProgress) {
+	*out = *in
+	if in.Ordinal != nil {
+		in, out := &in.Ordinal, &out.Ordinal
+		*out = new(int32)
+		**out = **in
+	}
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageMigrationProgress.
+func (in *StorageMigrationProgress) DeepCopy() *StorageMigrationProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageMigrationProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageProvider) DeepCopyInto(out *StorageProvider) {
+	*out = *in
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(S3StorageProvider)
+		**out = **in
+	}
+	if in.Gcs != nil {
+		in, out := &in.Gcs, &out.Gcs
+		*out = new(GcsStorageProvider)
+		**out = **in
+	}
+	if in.Local != nil {
+		in, out := &in.Local, &out.Local
+		*out = new(LocalStorageProvider)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageProvider.
+func (in *StorageProvider) DeepCopy() *StorageProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageStatus) DeepCopyInto(out *StorageStatus) {
+	*out = *in
+	if in.LastFileSystemResizeTime != nil {
+		in, out := &in.LastFileSystemResizeTime, &out.LastFileSystemResizeTime
+		*out = (*in).DeepCopy()
+	}
+	if in.PVCs != nil {
+		in, out := &in.PVCs, &out.PVCs
+		*out = make(map[string]PVCResizeStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageStatus.
+func (in *StorageStatus) DeepCopy() *StorageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StoreLabel) DeepCopyInto(out *StoreLabel) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StoreLabel.
+func (in *StoreLabel) DeepCopy() *StoreLabel {
+	if in == nil {
+		return nil
+	}
+	out := new(StoreLabel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSCertStatus) DeepCopyInto(out *TLSCertStatus) {
+	*out = *in
+	in.NotBefore.DeepCopyInto(&out.NotBefore)
+	in.NotAfter.DeepCopyInto(&out.NotAfter)
+	if in.LastRenewTime != nil {
+		in, out := &in.LastRenewTime, &out.LastRenewTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSCertStatus.
+func (in *TLSCertStatus) DeepCopy() *TLSCertStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSCertStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSIssuer) DeepCopyInto(out *TLSIssuer) {
+	*out = *in
+	if in.CertManager != nil {
+		in, out := &in.CertManager, &out.CertManager
+		*out = new(CertManagerIssuer)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSIssuer.
+func (in *TLSIssuer) DeepCopy() *TLSIssuer {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSIssuer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiDBConfig) DeepCopyInto(out *TiDBConfig) {
+	*out = *in
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(uint)
+		**out = **in
+	}
+	if in.TokenLimit != nil {
+		in, out := &in.TokenLimit, &out.TokenLimit
+		*out = new(uint)
+		**out = **in
+	}
+	if in.MemQuotaQuery != nil {
+		in, out := &in.MemQuotaQuery, &out.MemQuotaQuery
+		*out = new(int64)
+		**out = **in
+	}
+	if in.TxnLocalLatches != nil {
+		in, out := &in.TxnLocalLatches, &out.TxnLocalLatches
+		*out = new(TxnLocalLatches)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LowerCaseTableNames != nil {
+		in, out := &in.LowerCaseTableNames, &out.LowerCaseTableNames
+		*out = new(int)
+		**out = **in
+	}
+	if in.Log != nil {
+		in, out := &in.Log, &out.Log
+		*out = new(Log)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Security != nil {
+		in, out := &in.Security, &out.Security
+		*out = new(Security)
+		**out = **in
+	}
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(Status)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Performance != nil {
 		in, out := &in.Performance, &out.Performance
 		*out = new(Performance)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.PreparedPlanCache != nil {
-		in, out := &in.PreparedPlanCache, &out.PreparedPlanCache
-		*out = new(PreparedPlanCache)
+	if in.PreparedPlanCache != nil {
+		in, out := &in.PreparedPlanCache, &out.PreparedPlanCache
+		*out = new(PreparedPlanCache)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OpenTracing != nil {
+		in, out := &in.OpenTracing, &out.OpenTracing
+		*out = new(OpenTracing)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProxyProtocol != nil {
+		in, out := &in.ProxyProtocol, &out.ProxyProtocol
+		*out = new(ProxyProtocol)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TiKVClient != nil {
+		in, out := &in.TiKVClient, &out.TiKVClient
+		*out = new(TiKVClient)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Binlog != nil {
+		in, out := &in.Binlog, &out.Binlog
+		*out = new(Binlog)
+		**out = **in
+	}
+	if in.Plugin != nil {
+		in, out := &in.Plugin, &out.Plugin
+		*out = new(Plugin)
+		**out = **in
+	}
+	if in.PessimisticTxn != nil {
+		in, out := &in.PessimisticTxn, &out.PessimisticTxn
+		*out = new(PessimisticTxn)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SplitRegionMaxNum != nil {
+		in, out := &in.SplitRegionMaxNum, &out.SplitRegionMaxNum
+		*out = new(uint64)
+		**out = **in
+	}
+	if in.StmtSummary != nil {
+		in, out := &in.StmtSummary, &out.StmtSummary
+		*out = new(StmtSummary)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiDBConfig.
+func (in *TiDBConfig) DeepCopy() *TiDBConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TiDBConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiDBDrainSpec) DeepCopyInto(out *TiDBDrainSpec) {
+	*out = *in
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiDBDrainSpec.
+func (in *TiDBDrainSpec) DeepCopy() *TiDBDrainSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TiDBDrainSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiDBFailureMember) DeepCopyInto(out *TiDBFailureMember) {
+	*out = *in
+	in.CreatedAt.DeepCopyInto(&out.CreatedAt)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiDBFailureMember.
+func (in *TiDBFailureMember) DeepCopy() *TiDBFailureMember {
+	if in == nil {
+		return nil
+	}
+	out := new(TiDBFailureMember)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiDBGroupSpec) DeepCopyInto(out *TiDBGroupSpec) {
+	*out = *in
+	in.TiDBSpec.DeepCopyInto(&out.TiDBSpec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiDBGroupSpec.
+func (in *TiDBGroupSpec) DeepCopy() *TiDBGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TiDBGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiDBMember) DeepCopyInto(out *TiDBMember) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiDBMember.
+func (in *TiDBMember) DeepCopy() *TiDBMember {
+	if in == nil {
+		return nil
+	}
+	out := new(TiDBMember)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiDBServiceSpec) DeepCopyInto(out *TiDBServiceSpec) {
+	*out = *in
+	in.ServiceSpec.DeepCopyInto(&out.ServiceSpec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiDBServiceSpec.
+func (in *TiDBServiceSpec) DeepCopy() *TiDBServiceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TiDBServiceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiDBSlowLogTailerSpec) DeepCopyInto(out *TiDBSlowLogTailerSpec) {
+	*out = *in
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.ImagePullPolicy != nil {
+		in, out := &in.ImagePullPolicy, &out.ImagePullPolicy
+		*out = new(v1.PullPolicy)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiDBSlowLogTailerSpec.
+func (in *TiDBSlowLogTailerSpec) DeepCopy() *TiDBSlowLogTailerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TiDBSlowLogTailerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiDBSpec) DeepCopyInto(out *TiDBSpec) {
+	*out = *in
+	in.ComponentSpec.DeepCopyInto(&out.ComponentSpec)
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(TiDBServiceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	in.SlowLogTailer.DeepCopyInto(&out.SlowLogTailer)
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.UpgradeStrategy != nil {
+		in, out := &in.UpgradeStrategy, &out.UpgradeStrategy
+		*out = new(UpgradeStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Drain != nil {
+		in, out := &in.Drain, &out.Drain
+		*out = new(TiDBDrainSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StableScheduling != nil {
+		in, out := &in.StableScheduling, &out.StableScheduling
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TLSClient != nil {
+		in, out := &in.TLSClient, &out.TLSClient
+		*out = new(TiDBTLSClient)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = new(TiDBConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiDBSpec.
+func (in *TiDBSpec) DeepCopy() *TiDBSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TiDBSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiDBStatus) DeepCopyInto(out *TiDBStatus) {
+	*out = *in
+	if in.StatefulSet != nil {
+		in, out := &in.StatefulSet, &out.StatefulSet
+		*out = new(appsv1.StatefulSetStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make(map[string]TiDBMember, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.FailureMembers != nil {
+		in, out := &in.FailureMembers, &out.FailureMembers
+		*out = make(map[string]TiDBFailureMember, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(UpgradeProgress)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiDBStatus.
+func (in *TiDBStatus) DeepCopy() *TiDBStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TiDBStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiDBTLSClient) DeepCopyInto(out *TiDBTLSClient) {
+	*out = *in
+	if in.ExtraSANs != nil {
+		in, out := &in.ExtraSANs, &out.ExtraSANs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiDBTLSClient.
+func (in *TiDBTLSClient) DeepCopy() *TiDBTLSClient {
+	if in == nil {
+		return nil
+	}
+	out := new(TiDBTLSClient)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiFlashSpec) DeepCopyInto(out *TiFlashSpec) {
+	*out = *in
+	in.ComponentSpec.DeepCopyInto(&out.ComponentSpec)
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.StoreLabels != nil {
+		in, out := &in.StoreLabels, &out.StoreLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.UpgradeStrategy != nil {
+		in, out := &in.UpgradeStrategy, &out.UpgradeStrategy
+		*out = new(UpgradeStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	in.GenericConfig.DeepCopyInto(&out.GenericConfig)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiFlashSpec.
+func (in *TiFlashSpec) DeepCopy() *TiFlashSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TiFlashSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiFlashStatus) DeepCopyInto(out *TiFlashStatus) {
+	*out = *in
+	if in.StatefulSet != nil {
+		in, out := &in.StatefulSet, &out.StatefulSet
+		*out = new(appsv1.StatefulSetStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Stores != nil {
+		in, out := &in.Stores, &out.Stores
+		*out = make(map[string]TiKVStore, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.TombstoneStores != nil {
+		in, out := &in.TombstoneStores, &out.TombstoneStores
+		*out = make(map[string]TiKVStore, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.FailureStores != nil {
+		in, out := &in.FailureStores, &out.FailureStores
+		*out = make(map[string]TiKVFailureStore, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(UpgradeProgress)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiFlashStatus.
+func (in *TiFlashStatus) DeepCopy() *TiFlashStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TiFlashStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiKVClient) DeepCopyInto(out *TiKVClient) {
+	*out = *in
+	if in.GrpcConnectionCount != nil {
+		in, out := &in.GrpcConnectionCount, &out.GrpcConnectionCount
+		*out = new(uint)
+		**out = **in
+	}
+	if in.GrpcKeepAliveTime != nil {
+		in, out := &in.GrpcKeepAliveTime, &out.GrpcKeepAliveTime
+		*out = new(uint)
+		**out = **in
+	}
+	if in.GrpcKeepAliveTimeout != nil {
+		in, out := &in.GrpcKeepAliveTimeout, &out.GrpcKeepAliveTimeout
+		*out = new(uint)
+		**out = **in
+	}
+	if in.MaxTxnTimeUse != nil {
+		in, out := &in.MaxTxnTimeUse, &out.MaxTxnTimeUse
+		*out = new(uint)
+		**out = **in
+	}
+	if in.MaxBatchSize != nil {
+		in, out := &in.MaxBatchSize, &out.MaxBatchSize
+		*out = new(uint)
+		**out = **in
+	}
+	if in.OverloadThreshold != nil {
+		in, out := &in.OverloadThreshold, &out.OverloadThreshold
+		*out = new(uint)
+		**out = **in
+	}
+	if in.BatchWaitSize != nil {
+		in, out := &in.BatchWaitSize, &out.BatchWaitSize
+		*out = new(uint)
+		**out = **in
+	}
+	if in.RegionCacheTTL != nil {
+		in, out := &in.RegionCacheTTL, &out.RegionCacheTTL
+		*out = new(uint)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiKVClient.
+func (in *TiKVClient) DeepCopy() *TiKVClient {
+	if in == nil {
+		return nil
+	}
+	out := new(TiKVClient)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiKVFailureStore) DeepCopyInto(out *TiKVFailureStore) {
+	*out = *in
+	in.CreatedAt.DeepCopyInto(&out.CreatedAt)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiKVFailureStore.
+func (in *TiKVFailureStore) DeepCopy() *TiKVFailureStore {
+	if in == nil {
+		return nil
+	}
+	out := new(TiKVFailureStore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiKVGroupSpec) DeepCopyInto(out *TiKVGroupSpec) {
+	*out = *in
+	in.TiKVSpec.DeepCopyInto(&out.TiKVSpec)
+	if in.StoreLabels != nil {
+		in, out := &in.StoreLabels, &out.StoreLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiKVGroupSpec.
+func (in *TiKVGroupSpec) DeepCopy() *TiKVGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TiKVGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiKVSpec) DeepCopyInto(out *TiKVSpec) {
+	*out = *in
+	in.ComponentSpec.DeepCopyInto(&out.ComponentSpec)
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(ServiceSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EvictLeaderTimeout != nil {
+		in, out := &in.EvictLeaderTimeout, &out.EvictLeaderTimeout
+		*out = new(string)
+		**out = **in
+	}
+	if in.UpgradeStrategy != nil {
+		in, out := &in.UpgradeStrategy, &out.UpgradeStrategy
+		*out = new(UpgradeStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LocationLabels != nil {
+		in, out := &in.LocationLabels, &out.LocationLabels
+		*out = make([]StoreLabel, len(*in))
+		copy(*out, *in)
+	}
+	in.GenericConfig.DeepCopyInto(&out.GenericConfig)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiKVSpec.
+func (in *TiKVSpec) DeepCopy() *TiKVSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TiKVSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiKVStatus) DeepCopyInto(out *TiKVStatus) {
+	*out = *in
+	if in.StatefulSet != nil {
+		in, out := &in.StatefulSet, &out.StatefulSet
+		*out = new(appsv1.StatefulSetStatus)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.OpenTracing != nil {
-		in, out := &in.OpenTracing, &out.OpenTracing
-		*out = new(OpenTracing)
-		(*in).DeepCopyInto(*out)
+	if in.Stores != nil {
+		in, out := &in.Stores, &out.Stores
+		*out = make(map[string]TiKVStore, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
 	}
-	if in.ProxyProtocol != nil {
-		in, out := &in.ProxyProtocol, &out.ProxyProtocol
-		*out = new(ProxyProtocol)
-		(*in).DeepCopyInto(*out)
+	if in.TombstoneStores != nil {
+		in, out := &in.TombstoneStores, &out.TombstoneStores
+		*out = make(map[string]TiKVStore, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
 	}
-	if in.TiKVClient != nil {
-		in, out := &in.TiKVClient, &out.TiKVClient
-		*out = new(TiKVClient)
-		(*in).DeepCopyInto(*out)
+	if in.FailureStores != nil {
+		in, out := &in.FailureStores, &out.FailureStores
+		*out = make(map[string]TiKVFailureStore, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
 	}
-	if in.Binlog != nil {
-		in, out := &in.Binlog, &out.Binlog
-		*out = new(Binlog)
-		**out = **in
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(UpgradeProgress)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.Plugin != nil {
-		in, out := &in.Plugin, &out.Plugin
-		*out = new(Plugin)
-		**out = **in
+	if in.PeerStores != nil {
+		in, out := &in.PeerStores, &out.PeerStores
+		*out = make(map[string]TiKVStore, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
 	}
-	if in.PessimisticTxn != nil {
-		in, out := &in.PessimisticTxn, &out.PessimisticTxn
-		*out = new(PessimisticTxn)
+	if in.Migration != nil {
+		in, out := &in.Migration, &out.Migration
+		*out = new(StorageMigrationProgress)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.SplitRegionMaxNum != nil {
-		in, out := &in.SplitRegionMaxNum, &out.SplitRegionMaxNum
-		*out = new(uint64)
-		**out = **in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiKVStatus.
+func (in *TiKVStatus) DeepCopy() *TiKVStatus {
+	if in == nil {
+		return nil
 	}
-	if in.StmtSummary != nil {
-		in, out := &in.StmtSummary, &out.StmtSummary
-		*out = new(StmtSummary)
-		(*in).DeepCopyInto(*out)
+	out := new(TiKVStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TiKVStore) DeepCopyInto(out *TiKVStore) {
+	*out = *in
+	in.LastHeartbeatTime.DeepCopyInto(&out.LastHeartbeatTime)
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	if in.LastEvictLeaderDuration != nil {
+		in, out := &in.LastEvictLeaderDuration, &out.LastEvictLeaderDuration
+		*out = new(metav1.Duration)
+		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiDBConfig.
-func (in *TiDBConfig) DeepCopy() *TiDBConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiKVStore.
+func (in *TiKVStore) DeepCopy() *TiKVStore {
 	if in == nil {
 		return nil
 	}
-	out := new(TiDBConfig)
+	out := new(TiKVStore)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TiDBFailureMember) DeepCopyInto(out *TiDBFailureMember) {
+func (in *TidbAutoScalerSpec) DeepCopyInto(out *TidbAutoScalerSpec) {
 	*out = *in
-	in.CreatedAt.DeepCopyInto(&out.CreatedAt)
+	in.BasicAutoScalerSpec.DeepCopyInto(&out.BasicAutoScalerSpec)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiDBFailureMember.
-func (in *TiDBFailureMember) DeepCopy() *TiDBFailureMember {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TidbAutoScalerSpec.
+func (in *TidbAutoScalerSpec) DeepCopy() *TidbAutoScalerSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(TiDBFailureMember)
+	out := new(TidbAutoScalerSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TiDBMember) DeepCopyInto(out *TiDBMember) {
+func (in *TidbCluster) DeepCopyInto(out *TidbCluster) {
 	*out = *in
-	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiDBMember.
-func (in *TiDBMember) DeepCopy() *TiDBMember {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TidbCluster.
+func (in *TidbCluster) DeepCopy() *TidbCluster {
 	if in == nil {
 		return nil
 	}
-	out := new(TiDBMember)
+	out := new(TidbCluster)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TidbCluster) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TiDBServiceSpec) DeepCopyInto(out *TiDBServiceSpec) {
+func (in *TidbClusterAutoScaler) DeepCopyInto(out *TidbClusterAutoScaler) {
 	*out = *in
-	in.ServiceSpec.DeepCopyInto(&out.ServiceSpec)
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiDBServiceSpec.
-func (in *TiDBServiceSpec) DeepCopy() *TiDBServiceSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TidbClusterAutoScaler.
+func (in *TidbClusterAutoScaler) DeepCopy() *TidbClusterAutoScaler {
 	if in == nil {
 		return nil
 	}
-	out := new(TiDBServiceSpec)
+	out := new(TidbClusterAutoScaler)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TidbClusterAutoScaler) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TiDBSlowLogTailerSpec) DeepCopyInto(out *TiDBSlowLogTailerSpec) {
+func (in *TidbClusterAutoScalerList) DeepCopyInto(out *TidbClusterAutoScalerList) {
 	*out = *in
-	in.Resources.DeepCopyInto(&out.Resources)
-	if in.ImagePullPolicy != nil {
-		in, out := &in.ImagePullPolicy, &out.ImagePullPolicy
-		*out = new(v1.PullPolicy)
-		**out = **in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TidbClusterAutoScaler, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiDBSlowLogTailerSpec.
-func (in *TiDBSlowLogTailerSpec) DeepCopy() *TiDBSlowLogTailerSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TidbClusterAutoScalerList.
+func (in *TidbClusterAutoScalerList) DeepCopy() *TidbClusterAutoScalerList {
 	if in == nil {
 		return nil
 	}
-	out := new(TiDBSlowLogTailerSpec)
+	out := new(TidbClusterAutoScalerList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TidbClusterAutoScalerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TiDBSpec) DeepCopyInto(out *TiDBSpec) {
+func (in *TidbClusterAutoScalerSpec) DeepCopyInto(out *TidbClusterAutoScalerSpec) {
 	*out = *in
-	in.ComponentSpec.DeepCopyInto(&out.ComponentSpec)
-	in.Resources.DeepCopyInto(&out.Resources)
-	if in.Service != nil {
-		in, out := &in.Service, &out.Service
-		*out = new(TiDBServiceSpec)
-		(*in).DeepCopyInto(*out)
+	out.Cluster = in.Cluster
+	if in.Monitor != nil {
+		in, out := &in.Monitor, &out.Monitor
+		*out = new(TidbMonitorRef)
+		**out = **in
 	}
-	in.SlowLogTailer.DeepCopyInto(&out.SlowLogTailer)
-	if in.Plugins != nil {
-		in, out := &in.Plugins, &out.Plugins
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.TiKV != nil {
+		in, out := &in.TiKV, &out.TiKV
+		*out = new(TikvAutoScalerSpec)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.Config != nil {
-		in, out := &in.Config, &out.Config
-		*out = new(TiDBConfig)
+	if in.TiDB != nil {
+		in, out := &in.TiDB, &out.TiDB
+		*out = new(TidbAutoScalerSpec)
 		(*in).DeepCopyInto(*out)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiDBSpec.
-func (in *TiDBSpec) DeepCopy() *TiDBSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TidbClusterAutoScalerSpec.
+func (in *TidbClusterAutoScalerSpec) DeepCopy() *TidbClusterAutoScalerSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(TiDBSpec)
+	out := new(TidbClusterAutoScalerSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TiDBStatus) DeepCopyInto(out *TiDBStatus) {
+func (in *TidbClusterAutoScalerStatus) DeepCopyInto(out *TidbClusterAutoScalerStatus) {
 	*out = *in
-	if in.StatefulSet != nil {
-		in, out := &in.StatefulSet, &out.StatefulSet
-		*out = new(appsv1.StatefulSetStatus)
+	if in.TiKV != nil {
+		in, out := &in.TiKV, &out.TiKV
+		*out = new(AutoScalerStatus)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Members != nil {
-		in, out := &in.Members, &out.Members
-		*out = make(map[string]TiDBMember, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
-	}
-	if in.FailureMembers != nil {
-		in, out := &in.FailureMembers, &out.FailureMembers
-		*out = make(map[string]TiDBFailureMember, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
+	if in.TiDB != nil {
+		in, out := &in.TiDB, &out.TiDB
+		*out = new(AutoScalerStatus)
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiDBStatus.
-func (in *TiDBStatus) DeepCopy() *TiDBStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TidbClusterAutoScalerStatus.
+func (in *TidbClusterAutoScalerStatus) DeepCopy() *TidbClusterAutoScalerStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(TiDBStatus)
+	out := new(TidbClusterAutoScalerStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TiKVClient) DeepCopyInto(out *TiKVClient) {
+func (in *TidbClusterCondition) DeepCopyInto(out *TidbClusterCondition) {
 	*out = *in
-	if in.GrpcConnectionCount != nil {
-		in, out := &in.GrpcConnectionCount, &out.GrpcConnectionCount
-		*out = new(uint)
-		**out = **in
-	}
-	if in.GrpcKeepAliveTime != nil {
-		in, out := &in.GrpcKeepAliveTime, &out.GrpcKeepAliveTime
-		*out = new(uint)
-		**out = **in
-	}
-	if in.GrpcKeepAliveTimeout != nil {
-		in, out := &in.GrpcKeepAliveTimeout, &out.GrpcKeepAliveTimeout
-		*out = new(uint)
-		**out = **in
-	}
-	if in.MaxTxnTimeUse != nil {
-		in, out := &in.MaxTxnTimeUse, &out.MaxTxnTimeUse
-		*out = new(uint)
-		**out = **in
-	}
-	if in.MaxBatchSize != nil {
-		in, out := &in.MaxBatchSize, &out.MaxBatchSize
-		*out = new(uint)
-		**out = **in
-	}
-	if in.OverloadThreshold != nil {
-		in, out := &in.OverloadThreshold, &out.OverloadThreshold
-		*out = new(uint)
-		**out = **in
-	}
-	if in.BatchWaitSize != nil {
-		in, out := &in.BatchWaitSize, &out.BatchWaitSize
-		*out = new(uint)
-		**out = **in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TidbClusterCondition.
+func (in *TidbClusterCondition) DeepCopy() *TidbClusterCondition {
+	if in == nil {
+		return nil
 	}
-	if in.RegionCacheTTL != nil {
-		in, out := &in.RegionCacheTTL, &out.RegionCacheTTL
-		*out = new(uint)
-		**out = **in
+	out := new(TidbClusterCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TidbClusterList) DeepCopyInto(out *TidbClusterList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TidbCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiKVClient.
-func (in *TiKVClient) DeepCopy() *TiKVClient {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TidbClusterList.
+func (in *TidbClusterList) DeepCopy() *TidbClusterList {
 	if in == nil {
 		return nil
 	}
-	out := new(TiKVClient)
+	out := new(TidbClusterList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TidbClusterList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TiKVFailureStore) DeepCopyInto(out *TiKVFailureStore) {
+func (in *TidbClusterRef) DeepCopyInto(out *TidbClusterRef) {
 	*out = *in
-	in.CreatedAt.DeepCopyInto(&out.CreatedAt)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiKVFailureStore.
-func (in *TiKVFailureStore) DeepCopy() *TiKVFailureStore {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TidbClusterRef.
+func (in *TidbClusterRef) DeepCopy() *TidbClusterRef {
 	if in == nil {
 		return nil
 	}
-	out := new(TiKVFailureStore)
+	out := new(TidbClusterRef)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TiKVSpec) DeepCopyInto(out *TiKVSpec) {
+func (in *TidbClusterSpec) DeepCopyInto(out *TidbClusterSpec) {
 	*out = *in
-	in.ComponentSpec.DeepCopyInto(&out.ComponentSpec)
-	in.Resources.DeepCopyInto(&out.Resources)
-	if in.Service != nil {
-		in, out := &in.Service, &out.Service
-		*out = new(ServiceSpec)
+	in.PD.DeepCopyInto(&out.PD)
+	in.TiDB.DeepCopyInto(&out.TiDB)
+	if in.TiDBGroups != nil {
+		in, out := &in.TiDBGroups, &out.TiDBGroups
+		*out = make([]TiDBGroupSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.TiKV.DeepCopyInto(&out.TiKV)
+	if in.TiKVGroups != nil {
+		in, out := &in.TiKVGroups, &out.TiKVGroups
+		*out = make([]TiKVGroupSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TiFlash != nil {
+		in, out := &in.TiFlash, &out.TiFlash
+		*out = new(TiFlashSpec)
 		(*in).DeepCopyInto(*out)
 	}
-	in.GenericConfig.DeepCopyInto(&out.GenericConfig)
+	if in.Pump != nil {
+		in, out := &in.Pump, &out.Pump
+		*out = new(PumpSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Drainers != nil {
+		in, out := &in.Drainers, &out.Drainers
+		*out = make([]DrainerSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Helper.DeepCopyInto(&out.Helper)
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = make([]Service, len(*in))
+		copy(*out, *in)
+	}
+	if in.TLSCertRenewBefore != nil {
+		in, out := &in.TLSCertRenewBefore, &out.TLSCertRenewBefore
+		*out = new(string)
+		**out = **in
+	}
+	if in.TLSIssuer != nil {
+		in, out := &in.TLSIssuer, &out.TLSIssuer
+		*out = new(TLSIssuer)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Cluster != nil {
+		in, out := &in.Cluster, &out.Cluster
+		*out = new(TidbClusterRef)
+		**out = **in
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ReplaceMembers != nil {
+		in, out := &in.ReplaceMembers, &out.ReplaceMembers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiKVSpec.
-func (in *TiKVSpec) DeepCopy() *TiKVSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TidbClusterSpec.
+func (in *TidbClusterSpec) DeepCopy() *TidbClusterSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(TiKVSpec)
+	out := new(TidbClusterSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TiKVStatus) DeepCopyInto(out *TiKVStatus) {
+func (in *TidbClusterStatus) DeepCopyInto(out *TidbClusterStatus) {
 	*out = *in
-	if in.StatefulSet != nil {
-		in, out := &in.StatefulSet, &out.StatefulSet
-		*out = new(appsv1.StatefulSetStatus)
-		(*in).DeepCopyInto(*out)
+	in.PD.DeepCopyInto(&out.PD)
+	in.TiKV.DeepCopyInto(&out.TiKV)
+	in.TiDB.DeepCopyInto(&out.TiDB)
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]TidbClusterCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.Stores != nil {
-		in, out := &in.Stores, &out.Stores
-		*out = make(map[string]TiKVStore, len(*in))
+	if in.TiKVGroups != nil {
+		in, out := &in.TiKVGroups, &out.TiKVGroups
+		*out = make(map[string]TiKVStatus, len(*in))
 		for key, val := range *in {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
-	if in.TombstoneStores != nil {
-		in, out := &in.TombstoneStores, &out.TombstoneStores
-		*out = make(map[string]TiKVStore, len(*in))
+	if in.TiDBGroups != nil {
+		in, out := &in.TiDBGroups, &out.TiDBGroups
+		*out = make(map[string]TiDBStatus, len(*in))
 		for key, val := range *in {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
-	if in.FailureStores != nil {
-		in, out := &in.FailureStores, &out.FailureStores
-		*out = make(map[string]TiKVFailureStore, len(*in))
+	in.TiFlash.DeepCopyInto(&out.TiFlash)
+	in.Pump.DeepCopyInto(&out.Pump)
+	if in.Drainers != nil {
+		in, out := &in.Drainers, &out.Drainers
+		*out = make(map[string]DrainerStatus, len(*in))
 		for key, val := range *in {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
-	return
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiKVStatus.
-func (in *TiKVStatus) DeepCopy() *TiKVStatus {
-	if in == nil {
-		return nil
+	if in.TLSCerts != nil {
+		in, out := &in.TLSCerts, &out.TLSCerts
+		*out = make(map[string]TLSCertStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = make(map[string]StorageStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.MemberReplacements != nil {
+		in, out := &in.MemberReplacements, &out.MemberReplacements
+		*out = make(map[string]MemberReplacement, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
 	}
-	out := new(TiKVStatus)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TiKVStore) DeepCopyInto(out *TiKVStore) {
-	*out = *in
-	in.LastHeartbeatTime.DeepCopyInto(&out.LastHeartbeatTime)
-	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TiKVStore.
-func (in *TiKVStore) DeepCopy() *TiKVStore {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TidbClusterStatus.
+func (in *TidbClusterStatus) DeepCopy() *TidbClusterStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(TiKVStore)
+	out := new(TidbClusterStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TidbCluster) DeepCopyInto(out *TidbCluster) {
+func (in *TidbMonitor) DeepCopyInto(out *TidbMonitor) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -1582,18 +2911,18 @@ func (in *TidbCluster) DeepCopyInto(out *TidbCluster) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TidbCluster.
-func (in *TidbCluster) DeepCopy() *TidbCluster {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TidbMonitor.
+func (in *TidbMonitor) DeepCopy() *TidbMonitor {
 	if in == nil {
 		return nil
 	}
-	out := new(TidbCluster)
+	out := new(TidbMonitor)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *TidbCluster) DeepCopyObject() runtime.Object {
+func (in *TidbMonitor) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1601,13 +2930,13 @@ func (in *TidbCluster) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TidbClusterList) DeepCopyInto(out *TidbClusterList) {
+func (in *TidbMonitorList) DeepCopyInto(out *TidbMonitorList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]TidbCluster, len(*in))
+		*out = make([]TidbMonitor, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -1615,18 +2944,18 @@ func (in *TidbClusterList) DeepCopyInto(out *TidbClusterList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TidbClusterList.
-func (in *TidbClusterList) DeepCopy() *TidbClusterList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TidbMonitorList.
+func (in *TidbMonitorList) DeepCopy() *TidbMonitorList {
 	if in == nil {
 		return nil
 	}
-	out := new(TidbClusterList)
+	out := new(TidbMonitorList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *TidbClusterList) DeepCopyObject() runtime.Object {
+func (in *TidbMonitorList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1634,27 +2963,42 @@ func (in *TidbClusterList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TidbClusterSpec) DeepCopyInto(out *TidbClusterSpec) {
+func (in *TidbMonitorRef) DeepCopyInto(out *TidbMonitorRef) {
 	*out = *in
-	in.PD.DeepCopyInto(&out.PD)
-	in.TiDB.DeepCopyInto(&out.TiDB)
-	in.TiKV.DeepCopyInto(&out.TiKV)
-	if in.Pump != nil {
-		in, out := &in.Pump, &out.Pump
-		*out = new(PumpSpec)
-		(*in).DeepCopyInto(*out)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TidbMonitorRef.
+func (in *TidbMonitorRef) DeepCopy() *TidbMonitorRef {
+	if in == nil {
+		return nil
 	}
-	in.Helper.DeepCopyInto(&out.Helper)
-	if in.Services != nil {
-		in, out := &in.Services, &out.Services
-		*out = make([]Service, len(*in))
+	out := new(TidbMonitorRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TidbMonitorSpec) DeepCopyInto(out *TidbMonitorSpec) {
+	*out = *in
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]MonitorClusterRef, len(*in))
 		copy(*out, *in)
 	}
-	if in.Affinity != nil {
-		in, out := &in.Affinity, &out.Affinity
-		*out = new(v1.Affinity)
+	in.Prometheus.DeepCopyInto(&out.Prometheus)
+	if in.Grafana != nil {
+		in, out := &in.Grafana, &out.Grafana
+		*out = new(GrafanaSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	in.Reloader.DeepCopyInto(&out.Reloader)
+	in.Initializer.DeepCopyInto(&out.Initializer)
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
 	if in.NodeSelector != nil {
 		in, out := &in.NodeSelector, &out.NodeSelector
 		*out = make(map[string]string, len(*in))
@@ -1679,31 +3023,55 @@ func (in *TidbClusterSpec) DeepCopyInto(out *TidbClusterSpec) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TidbClusterSpec.
-func (in *TidbClusterSpec) DeepCopy() *TidbClusterSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TidbMonitorSpec.
+func (in *TidbMonitorSpec) DeepCopy() *TidbMonitorSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(TidbClusterSpec)
+	out := new(TidbMonitorSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TidbClusterStatus) DeepCopyInto(out *TidbClusterStatus) {
+func (in *TidbMonitorStatus) DeepCopyInto(out *TidbMonitorStatus) {
 	*out = *in
-	in.PD.DeepCopyInto(&out.PD)
-	in.TiKV.DeepCopyInto(&out.TiKV)
-	in.TiDB.DeepCopyInto(&out.TiDB)
+	if in.Deployment != nil {
+		in, out := &in.Deployment, &out.Deployment
+		*out = new(appsv1.DeploymentStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]MonitoredCluster, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TidbClusterStatus.
-func (in *TidbClusterStatus) DeepCopy() *TidbClusterStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TidbMonitorStatus.
+func (in *TidbMonitorStatus) DeepCopy() *TidbMonitorStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(TidbClusterStatus)
+	out := new(TidbMonitorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TikvAutoScalerSpec) DeepCopyInto(out *TikvAutoScalerSpec) {
+	*out = *in
+	in.BasicAutoScalerSpec.DeepCopyInto(&out.BasicAutoScalerSpec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TikvAutoScalerSpec.
+func (in *TikvAutoScalerSpec) DeepCopy() *TikvAutoScalerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TikvAutoScalerSpec)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1728,3 +3096,58 @@ func (in *TxnLocalLatches) DeepCopy() *TxnLocalLatches {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeProgress) DeepCopyInto(out *UpgradeProgress) {
+	*out = *in
+	if in.HealthySince != nil {
+		in, out := &in.HealthySince, &out.HealthySince
+		*out = (*in).DeepCopy()
+	}
+	if in.UnhealthySince != nil {
+		in, out := &in.UnhealthySince, &out.UnhealthySince
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeProgress.
+func (in *UpgradeProgress) DeepCopy() *UpgradeProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeStrategy) DeepCopyInto(out *UpgradeStrategy) {
+	*out = *in
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.HealthWindowSeconds != nil {
+		in, out := &in.HealthWindowSeconds, &out.HealthWindowSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.HealthDeadlineSeconds != nil {
+		in, out := &in.HealthDeadlineSeconds, &out.HealthDeadlineSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeStrategy.
+func (in *UpgradeStrategy) DeepCopy() *UpgradeStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
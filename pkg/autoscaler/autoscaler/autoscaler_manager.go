@@ -0,0 +1,411 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoscaler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/autoscaler"
+	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned"
+	listers "github.com/pingcap/tidb-operator/pkg/client/listers/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	glog "k8s.io/klog"
+)
+
+type autoScalerManager struct {
+	cli        versioned.Interface
+	tcLister   listers.TidbClusterLister
+	tacControl controller.TidbClusterAutoScalerControlInterface
+	pdControl  pdapi.PDControlInterface
+	httpClient *http.Client
+	recorder   record.EventRecorder
+}
+
+// NewAutoScalerManager returns an AutoScalerManager
+func NewAutoScalerManager(
+	cli versioned.Interface,
+	tcLister listers.TidbClusterLister,
+	tacControl controller.TidbClusterAutoScalerControlInterface,
+	pdControl pdapi.PDControlInterface,
+	recorder record.EventRecorder,
+) autoscaler.AutoScalerManager {
+	return &autoScalerManager{
+		cli,
+		tcLister,
+		tacControl,
+		pdControl,
+		&http.Client{Timeout: 10 * time.Second},
+		recorder,
+	}
+}
+
+func (am *autoScalerManager) Sync(tac *v1alpha1.TidbClusterAutoScaler) error {
+	if tac.DeletionTimestamp != nil {
+		return nil
+	}
+
+	ref := tac.Spec.Cluster
+	if ref.ClusterDomain != "" {
+		return fmt.Errorf("tidbclusterautoscaler %s/%s: cluster %s/%s in cluster domain %s is not supported",
+			tac.GetNamespace(), tac.GetName(), ref.Namespace, ref.Name, ref.ClusterDomain)
+	}
+	ns := ref.Namespace
+	if ns == "" {
+		ns = tac.GetNamespace()
+	}
+	tc, err := am.tcLister.TidbClusters(ns).Get(ref.Name)
+	if errors.IsNotFound(err) {
+		glog.Warningf("tidbclusterautoscaler %s/%s: tidbcluster %s/%s not found", tac.GetNamespace(), tac.GetName(), ns, ref.Name)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("tidbclusterautoscaler %s/%s get tidbcluster %s/%s failed, err: %v", tac.GetNamespace(), tac.GetName(), ns, ref.Name, err)
+	}
+
+	if err := validateAutoScaler(tac); err != nil {
+		// the cluster is not scaled until the spec is fixed
+		glog.Errorf("tidbclusterautoscaler %s/%s is invalid, err: %v", tac.GetNamespace(), tac.GetName(), err)
+		am.recorder.Event(tac, corev1.EventTypeWarning, "FailedValidation", err.Error())
+		return nil
+	}
+
+	oldStatus := tac.Status.DeepCopy()
+	tc = tc.DeepCopy()
+	now := time.Now()
+	var tikvScaled, tidbScaled bool
+	if tac.Spec.TiKV != nil {
+		tac.Status.TiKV, tikvScaled = am.syncTiKV(tac, tc, now)
+	} else {
+		tac.Status.TiKV = nil
+	}
+	if tac.Spec.TiDB != nil {
+		tac.Status.TiDB, tidbScaled = am.syncTiDB(tac, tc, now)
+	} else {
+		tac.Status.TiDB = nil
+	}
+
+	if tikvScaled || tidbScaled {
+		// the existing scalers of the tidbcluster controller apply the replicas
+		if err := am.updateReplicas(tc, tikvScaled, tidbScaled); err != nil {
+			return fmt.Errorf("tidbclusterautoscaler %s/%s update tidbcluster %s/%s failed, err: %v", tac.GetNamespace(), tac.GetName(), ns, ref.Name, err)
+		}
+		// the cooldown starts only after the replicas are applied
+		if tikvScaled {
+			am.recordScaled(tac, tc, v1alpha1.TiKVMemberType, tac.Status.TiKV, now)
+		}
+		if tidbScaled {
+			am.recordScaled(tac, tc, v1alpha1.TiDBMemberType, tac.Status.TiDB, now)
+		}
+	}
+	if apiequality.Semantic.DeepEqual(&tac.Status, oldStatus) {
+		return nil
+	}
+	_, err = am.tacControl.UpdateTidbClusterAutoScaler(tac)
+	return err
+}
+
+// updateReplicas sets the scaled replicas of tc to the latest TidbCluster, only the replicas are changed so that
+// the other changes of the spec made since tc was listed are kept
+func (am *autoScalerManager) updateReplicas(tc *v1alpha1.TidbCluster, tikvScaled, tidbScaled bool) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest, err := am.cli.PingcapV1alpha1().TidbClusters(ns).Get(tcName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if tikvScaled {
+			latest.Spec.TiKV.Replicas = tc.Spec.TiKV.Replicas
+		}
+		if tidbScaled {
+			latest.Spec.TiDB.Replicas = tc.Spec.TiDB.Replicas
+		}
+		_, err = am.cli.PingcapV1alpha1().TidbClusters(ns).Update(latest)
+		return err
+	})
+}
+
+// recordScaled starts the cooldown of the scaled component and records the scaling
+func (am *autoScalerManager) recordScaled(tac *v1alpha1.TidbClusterAutoScaler, tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType, status *v1alpha1.AutoScalerStatus, now time.Time) {
+	ts := metav1.NewTime(now)
+	status.LastAutoScalingTimestamp = &ts
+	msg := fmt.Sprintf("scale %s of TidbCluster %s/%s from %d to %d replicas", memberType, tc.GetNamespace(), tc.GetName(), status.CurrentReplicas, status.RecommendedReplicas)
+	status.Message = msg
+	am.recorder.Event(tac, corev1.EventTypeNormal, "AutoScaling", msg)
+	glog.Infof("tidbclusterautoscaler %s/%s: %s", tac.GetNamespace(), tac.GetName(), msg)
+}
+
+// syncTiKV computes the recommended replicas of TiKV and sets them to tc if TiKV can be scaled now
+func (am *autoScalerManager) syncTiKV(tac *v1alpha1.TidbClusterAutoScaler, tc *v1alpha1.TidbCluster, now time.Time) (*v1alpha1.AutoScalerStatus, bool) {
+	spec := &tac.Spec.TiKV.BasicAutoScalerSpec
+	current := tc.Spec.TiKV.Replicas
+	status := am.recommend(tac, tc, v1alpha1.TiKVMemberType, spec, tc.Spec.TiKV.Resources, current,
+		minReplicas(spec, defaultTiKVMinReplicas))
+	if status.RecommendedReplicas < current {
+		// TiKV stores are deleted one by one so that the regions can be migrated safely
+		status.RecommendedReplicas = current - 1
+		if maxReplicas := am.pdMaxReplicas(tac, tc); status.RecommendedReplicas < maxReplicas {
+			status.RecommendedReplicas = current
+			status.Message = fmt.Sprintf("tikv is not scaled in below the max-replicas %d of pd", maxReplicas)
+		}
+	}
+
+	if !am.decide(v1alpha1.TiKVMemberType, spec, status, tac.Status.TiKV, tc.Status.TiKV.Phase, tc.Status.TiKV.StatefulSet, now) {
+		return status, false
+	}
+	tc.Spec.TiKV.Replicas = status.RecommendedReplicas
+	return status, true
+}
+
+// syncTiDB computes the recommended replicas of TiDB and sets them to tc if TiDB can be scaled now
+func (am *autoScalerManager) syncTiDB(tac *v1alpha1.TidbClusterAutoScaler, tc *v1alpha1.TidbCluster, now time.Time) (*v1alpha1.AutoScalerStatus, bool) {
+	spec := &tac.Spec.TiDB.BasicAutoScalerSpec
+	status := am.recommend(tac, tc, v1alpha1.TiDBMemberType, spec, tc.Spec.TiDB.Resources, tc.Spec.TiDB.Replicas,
+		minReplicas(spec, defaultTiDBMinReplicas))
+
+	if !am.decide(v1alpha1.TiDBMemberType, spec, status, tac.Status.TiDB, tc.Status.TiDB.Phase, tc.Status.TiDB.StatefulSet, now) {
+		return status, false
+	}
+	tc.Spec.TiDB.Replicas = status.RecommendedReplicas
+	return status, true
+}
+
+// pdMaxReplicas returns the max-replicas of PD, or the default max-replicas if PD can not be reached
+func (am *autoScalerManager) pdMaxReplicas(tac *v1alpha1.TidbClusterAutoScaler, tc *v1alpha1.TidbCluster) int32 {
+	config, err := controller.GetPDClient(am.pdControl, tc).GetConfig()
+	if err != nil {
+		glog.Warningf("tidbclusterautoscaler %s/%s: get the config of pd failed, err: %v", tac.GetNamespace(), tac.GetName(), err)
+		return defaultTiKVMinReplicas
+	}
+	return int32(config.Replication.MaxReplicas)
+}
+
+// validateAutoScaler validates the bounds of the replicas of the spec
+func validateAutoScaler(tac *v1alpha1.TidbClusterAutoScaler) error {
+	if tac.Spec.TiKV != nil {
+		if err := validateReplicas(v1alpha1.TiKVMemberType, &tac.Spec.TiKV.BasicAutoScalerSpec, defaultTiKVMinReplicas); err != nil {
+			return err
+		}
+	}
+	if tac.Spec.TiDB != nil {
+		if err := validateReplicas(v1alpha1.TiDBMemberType, &tac.Spec.TiDB.BasicAutoScalerSpec, defaultTiDBMinReplicas); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recommend returns the status with the largest replicas recommended by the metrics within the bounds,
+// the current replicas are recommended if none of the metrics is available
+func (am *autoScalerManager) recommend(
+	tac *v1alpha1.TidbClusterAutoScaler,
+	tc *v1alpha1.TidbCluster,
+	memberType v1alpha1.MemberType,
+	spec *v1alpha1.BasicAutoScalerSpec,
+	resources v1alpha1.Resources,
+	current int32,
+	min int32,
+) *v1alpha1.AutoScalerStatus {
+	status := &v1alpha1.AutoScalerStatus{CurrentReplicas: current}
+	var recommended int32
+	var messages []string
+	for _, metric := range defaultMetrics(spec) {
+		ms, err := am.recommendByMetric(tac, tc, memberType, spec, resources, current, metric)
+		if err != nil {
+			glog.Warningf("tidbclusterautoscaler %s/%s: %s metric %s is unavailable, err: %v", tac.GetNamespace(), tac.GetName(), memberType, metric.Type, err)
+			messages = append(messages, fmt.Sprintf("%s: %v", metric.Type, err))
+			continue
+		}
+		status.Metrics = append(status.Metrics, *ms)
+		if ms.RecommendedReplicas > recommended {
+			recommended = ms.RecommendedReplicas
+		}
+	}
+	if len(status.Metrics) == 0 {
+		recommended = current
+	}
+	status.RecommendedReplicas = limitReplicas(spec, min, recommended)
+	if len(messages) > 0 {
+		status.Message = fmt.Sprintf("metrics unavailable: %v", messages)
+	}
+	return status
+}
+
+func (am *autoScalerManager) recommendByMetric(
+	tac *v1alpha1.TidbClusterAutoScaler,
+	tc *v1alpha1.TidbCluster,
+	memberType v1alpha1.MemberType,
+	spec *v1alpha1.BasicAutoScalerSpec,
+	resources v1alpha1.Resources,
+	current int32,
+	metric v1alpha1.AutoScalerMetric,
+) (*v1alpha1.MetricsStatus, error) {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	duration := metricsTimeDuration(spec)
+
+	switch metric.Type {
+	case v1alpha1.CPUAutoScalerMetricType:
+		if metric.TargetAverageUtilization == nil {
+			return nil, fmt.Errorf("targetAverageUtilization is not set")
+		}
+		cores, err := cpuRequestCores(resources)
+		if err != nil {
+			return nil, err
+		}
+		usage, err := am.query(tac, fmt.Sprintf(cpuQueryPattern, tcName, ns, memberType, duration))
+		if err != nil {
+			return nil, err
+		}
+		return &v1alpha1.MetricsStatus{
+			Type:                metric.Type,
+			CurrentValue:        formatPercentage(usage / (cores * float64(current))),
+			TargetValue:         fmt.Sprintf("%d%%", *metric.TargetAverageUtilization),
+			RecommendedReplicas: recommendByUtilization(usage, cores, *metric.TargetAverageUtilization),
+		}, nil
+	case v1alpha1.QPSAutoScalerMetricType:
+		if metric.TargetAverageValue == nil {
+			return nil, fmt.Errorf("targetAverageValue is not set")
+		}
+		pattern := tidbQPSQueryPattern
+		if memberType == v1alpha1.TiKVMemberType {
+			pattern = tikvQPSQueryPattern
+		}
+		qps, err := am.query(tac, fmt.Sprintf(pattern, tcName, ns, duration))
+		if err != nil {
+			return nil, err
+		}
+		return &v1alpha1.MetricsStatus{
+			Type:                metric.Type,
+			CurrentValue:        strconv.FormatFloat(qps/float64(current), 'f', 2, 64),
+			TargetValue:         strconv.FormatInt(*metric.TargetAverageValue, 10),
+			RecommendedReplicas: recommendByUtilization(qps, float64(*metric.TargetAverageValue), 100),
+		}, nil
+	case v1alpha1.StorageAutoScalerMetricType:
+		if memberType != v1alpha1.TiKVMemberType {
+			return nil, fmt.Errorf("storage metric is only supported by tikv")
+		}
+		if metric.TargetAverageUtilization == nil {
+			return nil, fmt.Errorf("targetAverageUtilization is not set")
+		}
+		return am.recommendByStorage(tc, current, *metric.TargetAverageUtilization)
+	default:
+		return nil, fmt.Errorf("unknown metric type %s", metric.Type)
+	}
+}
+
+// recommendByStorage recommends the replicas by the capacity of the stores of the cluster reported by PD,
+// the stores are never scaled in by the storage
+func (am *autoScalerManager) recommendByStorage(tc *v1alpha1.TidbCluster, current int32, target int32) (*v1alpha1.MetricsStatus, error) {
+	storesInfo, err := controller.GetPDClient(am.pdControl, tc).GetStores()
+	if err != nil {
+		return nil, err
+	}
+	var capacity, used float64
+	var count int
+	for _, store := range storesInfo.Stores {
+		if store.Store == nil || store.Status == nil {
+			continue
+		}
+		if _, ok := tc.Status.TiKV.Stores[strconv.FormatUint(store.Store.GetId(), 10)]; !ok {
+			// TiFlash stores and the stores of other tidbclusters
+			continue
+		}
+		capacity += float64(store.Status.Capacity)
+		used += float64(store.Status.Capacity - store.Status.Available)
+		count++
+	}
+	if count == 0 || capacity == 0 {
+		return nil, fmt.Errorf("no store capacity reported by pd")
+	}
+
+	recommended := recommendByUtilization(used, capacity/float64(count), target)
+	if recommended < current {
+		recommended = current
+	}
+	return &v1alpha1.MetricsStatus{
+		Type:                v1alpha1.StorageAutoScalerMetricType,
+		CurrentValue:        formatPercentage(used / capacity),
+		TargetValue:         fmt.Sprintf("%d%%", target),
+		RecommendedReplicas: recommended,
+	}, nil
+}
+
+// decide returns true if the component can be scaled to the recommended replicas now, the reason
+// is recorded in the status otherwise. The cooldown is started by recordScaled after the scaling
+func (am *autoScalerManager) decide(
+	memberType v1alpha1.MemberType,
+	spec *v1alpha1.BasicAutoScalerSpec,
+	status *v1alpha1.AutoScalerStatus,
+	lastStatus *v1alpha1.AutoScalerStatus,
+	phase v1alpha1.MemberPhase,
+	set *apps.StatefulSetStatus,
+	now time.Time,
+) bool {
+	if lastStatus != nil {
+		status.LastAutoScalingTimestamp = lastStatus.LastAutoScalingTimestamp
+	}
+	current := status.CurrentReplicas
+	recommended := status.RecommendedReplicas
+	if recommended == current {
+		return false
+	}
+	if phase == v1alpha1.UpgradePhase {
+		status.Message = fmt.Sprintf("%s is upgrading", memberType)
+		return false
+	}
+	if set != nil && set.Replicas != current {
+		status.Message = fmt.Sprintf("%s is scaling from %d to %d replicas", memberType, set.Replicas, current)
+		return false
+	}
+	if remaining := inCooldown(spec, status.LastAutoScalingTimestamp, current, recommended, now); remaining > 0 {
+		status.Message = fmt.Sprintf("%s is in cooldown for %v", memberType, remaining.Round(time.Second))
+		return false
+	}
+
+	return true
+}
+
+// query queries the metric from the Prometheus of the auto-scaler
+func (am *autoScalerManager) query(tac *v1alpha1.TidbClusterAutoScaler, query string) (float64, error) {
+	metricsURL := tac.Spec.MetricsURL
+	if metricsURL == "" && tac.Spec.Monitor != nil {
+		ns := tac.Spec.Monitor.Namespace
+		if ns == "" {
+			ns = tac.GetNamespace()
+		}
+		metricsURL = fmt.Sprintf("http://%s-prometheus.%s.svc:9090", tac.Spec.Monitor.Name, ns)
+	}
+	if metricsURL == "" {
+		return 0, fmt.Errorf("neither metricsUrl nor monitor is set")
+	}
+	return queryMetric(am.httpClient, metricsURL, query)
+}
+
+func formatPercentage(ratio float64) string {
+	return fmt.Sprintf("%.2f%%", ratio*100)
+}
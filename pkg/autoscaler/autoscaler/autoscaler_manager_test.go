@@ -0,0 +1,449 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoscaler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/pd/pkg/typeutil"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned/fake"
+	informers "github.com/pingcap/tidb-operator/pkg/client/informers/externalversions"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	core "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestAutoScalerManagerSync(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name string
+		// metrics are the results of the queries of the metrics
+		metrics map[string]string
+		stores  []*pdapi.StoreInfo
+		// pdMaxReplicas is the max-replicas of pd, pd can not be reached if it is 0
+		pdMaxReplicas uint64
+		prepare       func(tac *v1alpha1.TidbClusterAutoScaler, tc *v1alpha1.TidbCluster)
+		expectFn      func(*GomegaWithT, *v1alpha1.TidbClusterAutoScaler, *v1alpha1.TidbCluster)
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+
+		server := newMetricsServer(test.metrics)
+		defer server.Close()
+
+		tac := newTidbClusterAutoScaler(server.URL)
+		tc := newTidbCluster()
+		if test.prepare != nil {
+			test.prepare(tac, tc)
+		}
+
+		am, fakePDClient, indexers := newFakeAutoScalerManager(tc)
+		indexers.addTidbCluster(tc)
+		indexers.tac.Add(tac)
+		fakePDClient.AddReaction(pdapi.GetStoresActionType, func(action *pdapi.Action) (interface{}, error) {
+			return &pdapi.StoresInfo{Count: len(test.stores), Stores: test.stores}, nil
+		})
+		fakePDClient.AddReaction(pdapi.GetConfigActionType, func(action *pdapi.Action) (interface{}, error) {
+			if test.pdMaxReplicas == 0 {
+				return nil, fmt.Errorf("pd is unavailable")
+			}
+			return &pdapi.Config{Replication: pdapi.ReplicationConfig{MaxReplicas: test.pdMaxReplicas}}, nil
+		})
+
+		g.Expect(am.Sync(tac)).To(Succeed())
+
+		newTC, err := indexers.cli.PingcapV1alpha1().TidbClusters(tc.GetNamespace()).Get(tc.GetName(), metav1.GetOptions{})
+		g.Expect(err).NotTo(HaveOccurred())
+		obj, _, err := indexers.tac.Get(tac)
+		g.Expect(err).NotTo(HaveOccurred())
+		test.expectFn(g, obj.(*v1alpha1.TidbClusterAutoScaler), newTC)
+	}
+
+	tests := []testcase{
+		{
+			name:    "scale out tidb by cpu",
+			metrics: map[string]string{"process_cpu_seconds_total": "3.2"},
+			expectFn: func(g *GomegaWithT, tac *v1alpha1.TidbClusterAutoScaler, tc *v1alpha1.TidbCluster) {
+				g.Expect(tc.Spec.TiDB.Replicas).To(Equal(int32(4)))
+				g.Expect(tac.Status.TiDB.CurrentReplicas).To(Equal(int32(2)))
+				g.Expect(tac.Status.TiDB.RecommendedReplicas).To(Equal(int32(4)))
+				g.Expect(tac.Status.TiDB.LastAutoScalingTimestamp).NotTo(BeNil())
+				g.Expect(tac.Status.TiDB.Metrics).To(Equal([]v1alpha1.MetricsStatus{{
+					Type:                v1alpha1.CPUAutoScalerMetricType,
+					CurrentValue:        "160.00%",
+					TargetValue:         "80%",
+					RecommendedReplicas: 4,
+				}}))
+				g.Expect(tac.Status.TiKV).To(BeNil())
+			},
+		},
+		{
+			name:    "scale out tidb by qps within max replicas",
+			metrics: map[string]string{"process_cpu_seconds_total": "1.6", "tidb_server_query_total": "60000"},
+			prepare: func(tac *v1alpha1.TidbClusterAutoScaler, _ *v1alpha1.TidbCluster) {
+				cpu := int32(80)
+				qps := int64(5000)
+				tac.Spec.TiDB.Metrics = []v1alpha1.AutoScalerMetric{
+					{Type: v1alpha1.CPUAutoScalerMetricType, TargetAverageUtilization: &cpu},
+					{Type: v1alpha1.QPSAutoScalerMetricType, TargetAverageValue: &qps},
+				}
+			},
+			expectFn: func(g *GomegaWithT, tac *v1alpha1.TidbClusterAutoScaler, tc *v1alpha1.TidbCluster) {
+				g.Expect(tac.Status.TiDB.Metrics).To(HaveLen(2))
+				g.Expect(tac.Status.TiDB.Metrics[1].RecommendedReplicas).To(Equal(int32(12)))
+				g.Expect(tac.Status.TiDB.RecommendedReplicas).To(Equal(int32(5)))
+				g.Expect(tc.Spec.TiDB.Replicas).To(Equal(int32(5)))
+			},
+		},
+		{
+			name:    "scale in tidb to min replicas",
+			metrics: map[string]string{"process_cpu_seconds_total": "0.1"},
+			prepare: func(_ *v1alpha1.TidbClusterAutoScaler, tc *v1alpha1.TidbCluster) {
+				tc.Spec.TiDB.Replicas = 4
+				tc.Status.TiDB.StatefulSet.Replicas = 4
+			},
+			expectFn: func(g *GomegaWithT, tac *v1alpha1.TidbClusterAutoScaler, tc *v1alpha1.TidbCluster) {
+				g.Expect(tc.Spec.TiDB.Replicas).To(Equal(int32(2)))
+			},
+		},
+		{
+			name:    "tidb is in cooldown",
+			metrics: map[string]string{"process_cpu_seconds_total": "3.2"},
+			prepare: func(tac *v1alpha1.TidbClusterAutoScaler, _ *v1alpha1.TidbCluster) {
+				ts := metav1.NewTime(time.Now().Add(-time.Minute))
+				tac.Status.TiDB = &v1alpha1.AutoScalerStatus{LastAutoScalingTimestamp: &ts}
+			},
+			expectFn: func(g *GomegaWithT, tac *v1alpha1.TidbClusterAutoScaler, tc *v1alpha1.TidbCluster) {
+				g.Expect(tc.Spec.TiDB.Replicas).To(Equal(int32(2)))
+				g.Expect(tac.Status.TiDB.RecommendedReplicas).To(Equal(int32(4)))
+				g.Expect(tac.Status.TiDB.Message).To(ContainSubstring("cooldown"))
+			},
+		},
+		{
+			name:    "tidb is upgrading",
+			metrics: map[string]string{"process_cpu_seconds_total": "3.2"},
+			prepare: func(_ *v1alpha1.TidbClusterAutoScaler, tc *v1alpha1.TidbCluster) {
+				tc.Status.TiDB.Phase = v1alpha1.UpgradePhase
+			},
+			expectFn: func(g *GomegaWithT, tac *v1alpha1.TidbClusterAutoScaler, tc *v1alpha1.TidbCluster) {
+				g.Expect(tc.Spec.TiDB.Replicas).To(Equal(int32(2)))
+				g.Expect(tac.Status.TiDB.Message).To(ContainSubstring("upgrading"))
+				g.Expect(tac.Status.TiDB.LastAutoScalingTimestamp).To(BeNil())
+			},
+		},
+		{
+			name:    "metrics are unavailable",
+			metrics: map[string]string{},
+			expectFn: func(g *GomegaWithT, tac *v1alpha1.TidbClusterAutoScaler, tc *v1alpha1.TidbCluster) {
+				g.Expect(tc.Spec.TiDB.Replicas).To(Equal(int32(2)))
+				g.Expect(tac.Status.TiDB.RecommendedReplicas).To(Equal(int32(2)))
+				g.Expect(tac.Status.TiDB.Message).To(ContainSubstring("no data"))
+			},
+		},
+		{
+			name:    "scale out tikv by storage",
+			metrics: map[string]string{"process_cpu_seconds_total": "1"},
+			stores: []*pdapi.StoreInfo{
+				newStoreInfo(1, 100, 10),
+				newStoreInfo(2, 100, 10),
+				newStoreInfo(3, 100, 10),
+				// the store of another cluster
+				newStoreInfo(4, 100, 100),
+			},
+			prepare: func(tac *v1alpha1.TidbClusterAutoScaler, _ *v1alpha1.TidbCluster) {
+				target := int32(70)
+				tac.Spec.TiKV = &v1alpha1.TikvAutoScalerSpec{BasicAutoScalerSpec: v1alpha1.BasicAutoScalerSpec{
+					MaxReplicas: 6,
+					Metrics: []v1alpha1.AutoScalerMetric{
+						{Type: v1alpha1.CPUAutoScalerMetricType, TargetAverageUtilization: &target},
+						{Type: v1alpha1.StorageAutoScalerMetricType, TargetAverageUtilization: &target},
+					},
+				}}
+			},
+			expectFn: func(g *GomegaWithT, tac *v1alpha1.TidbClusterAutoScaler, tc *v1alpha1.TidbCluster) {
+				g.Expect(tac.Status.TiKV.Metrics[0].RecommendedReplicas).To(Equal(int32(2)))
+				g.Expect(tac.Status.TiKV.Metrics[1].CurrentValue).To(Equal("90.00%"))
+				g.Expect(tac.Status.TiKV.Metrics[1].RecommendedReplicas).To(Equal(int32(4)))
+				g.Expect(tc.Spec.TiKV.Replicas).To(Equal(int32(4)))
+			},
+		},
+		{
+			name:    "scale in tikv one by one",
+			metrics: map[string]string{"process_cpu_seconds_total": "0.1"},
+			prepare: func(tac *v1alpha1.TidbClusterAutoScaler, tc *v1alpha1.TidbCluster) {
+				tc.Spec.TiKV.Replicas = 5
+				tc.Status.TiKV.StatefulSet.Replicas = 5
+				min := int32(3)
+				tac.Spec.TiKV = &v1alpha1.TikvAutoScalerSpec{BasicAutoScalerSpec: v1alpha1.BasicAutoScalerSpec{
+					MinReplicas: &min,
+					MaxReplicas: 6,
+				}}
+			},
+			expectFn: func(g *GomegaWithT, tac *v1alpha1.TidbClusterAutoScaler, tc *v1alpha1.TidbCluster) {
+				g.Expect(tac.Status.TiKV.Metrics[0].RecommendedReplicas).To(Equal(int32(1)))
+				g.Expect(tac.Status.TiKV.RecommendedReplicas).To(Equal(int32(4)))
+				g.Expect(tc.Spec.TiKV.Replicas).To(Equal(int32(4)))
+			},
+		},
+		{
+			name:          "tikv is not scaled in below the max-replicas of pd",
+			metrics:       map[string]string{"process_cpu_seconds_total": "0.1"},
+			pdMaxReplicas: 5,
+			prepare: func(tac *v1alpha1.TidbClusterAutoScaler, tc *v1alpha1.TidbCluster) {
+				tc.Spec.TiKV.Replicas = 5
+				tc.Status.TiKV.StatefulSet.Replicas = 5
+				tac.Spec.TiKV = &v1alpha1.TikvAutoScalerSpec{BasicAutoScalerSpec: v1alpha1.BasicAutoScalerSpec{
+					MaxReplicas: 6,
+				}}
+			},
+			expectFn: func(g *GomegaWithT, tac *v1alpha1.TidbClusterAutoScaler, tc *v1alpha1.TidbCluster) {
+				g.Expect(tac.Status.TiKV.Metrics[0].RecommendedReplicas).To(Equal(int32(1)))
+				g.Expect(tac.Status.TiKV.RecommendedReplicas).To(Equal(int32(5)))
+				g.Expect(tac.Status.TiKV.Message).To(ContainSubstring("max-replicas"))
+				g.Expect(tc.Spec.TiKV.Replicas).To(Equal(int32(5)))
+			},
+		},
+		{
+			name:    "tikv defaults to 3 min replicas",
+			metrics: map[string]string{"process_cpu_seconds_total": "0.1"},
+			prepare: func(tac *v1alpha1.TidbClusterAutoScaler, _ *v1alpha1.TidbCluster) {
+				tac.Spec.TiKV = &v1alpha1.TikvAutoScalerSpec{BasicAutoScalerSpec: v1alpha1.BasicAutoScalerSpec{
+					MaxReplicas: 6,
+				}}
+			},
+			expectFn: func(g *GomegaWithT, tac *v1alpha1.TidbClusterAutoScaler, tc *v1alpha1.TidbCluster) {
+				g.Expect(tac.Status.TiKV.RecommendedReplicas).To(Equal(int32(3)))
+				g.Expect(tc.Spec.TiKV.Replicas).To(Equal(int32(3)))
+			},
+		},
+		{
+			name:    "min replicas larger than max replicas",
+			metrics: map[string]string{"process_cpu_seconds_total": "3.2"},
+			prepare: func(tac *v1alpha1.TidbClusterAutoScaler, _ *v1alpha1.TidbCluster) {
+				tac.Spec.TiDB.MinReplicas = controller.Int32Ptr(6)
+			},
+			expectFn: func(g *GomegaWithT, tac *v1alpha1.TidbClusterAutoScaler, tc *v1alpha1.TidbCluster) {
+				g.Expect(tc.Spec.TiDB.Replicas).To(Equal(int32(2)))
+				g.Expect(tac.Status.TiDB).To(BeNil())
+			},
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
+
+func TestAutoScalerManagerSyncConflict(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	server := newMetricsServer(map[string]string{"process_cpu_seconds_total": "3.2"})
+	defer server.Close()
+	tac := newTidbClusterAutoScaler(server.URL)
+	tc := newTidbCluster()
+	am, _, indexers := newFakeAutoScalerManager(tc)
+	indexers.addTidbCluster(tc)
+	indexers.tac.Add(tac)
+
+	// the tidbcluster is changed by another writer after it is listed
+	tcs := indexers.cli.PingcapV1alpha1().TidbClusters(tc.GetNamespace())
+	conflicted := false
+	indexers.cli.PrependReactor("update", "tidbclusters", func(action core.Action) (bool, runtime.Object, error) {
+		if conflicted {
+			return false, nil, nil
+		}
+		conflicted = true
+		changed := tc.DeepCopy()
+		changed.Spec.TiDB.Image = "tidb-new-image"
+		if err := indexers.cli.Tracker().Update(v1alpha1.SchemeGroupVersion.WithResource("tidbclusters"), changed, tc.GetNamespace()); err != nil {
+			return true, nil, err
+		}
+		return true, nil, errors.NewConflict(v1alpha1.Resource("tidbclusters"), tc.GetName(), fmt.Errorf("the object has been modified"))
+	})
+
+	g.Expect(am.Sync(tac)).To(Succeed())
+	g.Expect(conflicted).To(BeTrue())
+	newTC, err := tcs.Get(tc.GetName(), metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(newTC.Spec.TiDB.Replicas).To(Equal(int32(4)))
+	g.Expect(newTC.Spec.TiDB.Image).To(Equal("tidb-new-image"))
+
+	obj, _, err := indexers.tac.Get(tac)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(obj.(*v1alpha1.TidbClusterAutoScaler).Status.TiDB.LastAutoScalingTimestamp).NotTo(BeNil())
+}
+
+func TestAutoScalerManagerSyncUpdateFailed(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	server := newMetricsServer(map[string]string{"process_cpu_seconds_total": "3.2"})
+	defer server.Close()
+	tac := newTidbClusterAutoScaler(server.URL)
+	tc := newTidbCluster()
+	am, _, indexers := newFakeAutoScalerManager(tc)
+	indexers.addTidbCluster(tc)
+	indexers.tac.Add(tac)
+	indexers.cli.PrependReactor("update", "tidbclusters", func(action core.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.NewInternalError(fmt.Errorf("API server failed"))
+	})
+
+	g.Expect(am.Sync(tac)).NotTo(Succeed())
+	// the cooldown is not started as the replicas are not applied
+	obj, _, err := indexers.tac.Get(tac)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(obj.(*v1alpha1.TidbClusterAutoScaler).Status.TiDB.LastAutoScalingTimestamp).To(BeNil())
+}
+
+type fakeIndexers struct {
+	cli *fake.Clientset
+	tc  cache.Indexer
+	tac cache.Indexer
+}
+
+// addTidbCluster adds tc to both the apiserver and the lister
+func (fi *fakeIndexers) addTidbCluster(tc *v1alpha1.TidbCluster) {
+	fi.tc.Add(tc)
+	fi.cli.PingcapV1alpha1().TidbClusters(tc.GetNamespace()).Create(tc)
+}
+
+func newFakeAutoScalerManager(tc *v1alpha1.TidbCluster) (*autoScalerManager, *pdapi.FakePDClient, *fakeIndexers) {
+	cli := fake.NewSimpleClientset()
+	kubeCli := kubefake.NewSimpleClientset()
+	informerFactory := informers.NewSharedInformerFactory(cli, 0)
+	tcInformer := informerFactory.Pingcap().V1alpha1().TidbClusters()
+	tacInformer := informerFactory.Pingcap().V1alpha1().TidbClusterAutoScalers()
+	pdControl := pdapi.NewFakePDControl(kubeCli)
+	pdClient := pdapi.NewFakePDClient()
+	pdControl.SetPDClient(pdapi.Namespace(tc.GetNamespace()), tc.GetName(), pdClient)
+
+	am := &autoScalerManager{
+		cli,
+		tcInformer.Lister(),
+		controller.NewFakeTidbClusterAutoScalerControl(tacInformer),
+		pdControl,
+		&http.Client{Timeout: time.Second},
+		record.NewFakeRecorder(10),
+	}
+	return am, pdClient, &fakeIndexers{
+		cli: cli,
+		tc:  tcInformer.Informer().GetIndexer(),
+		tac: tacInformer.Informer().GetIndexer(),
+	}
+}
+
+// newMetricsServer returns a stub of the Prometheus query API, the value of the metric in the query is returned
+func newMetricsServer(metrics map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("query")
+		result := "[]"
+		for key, value := range metrics {
+			if strings.Contains(query, key) {
+				result = fmt.Sprintf(`[{"metric":{},"value":[1583000000,"%s"]}]`, value)
+				break
+			}
+		}
+		fmt.Fprintf(w, `{"status":"success","data":{"resultType":"vector","result":%s}}`, result)
+	}))
+}
+
+func newTidbClusterAutoScaler(metricsURL string) *v1alpha1.TidbClusterAutoScaler {
+	return &v1alpha1.TidbClusterAutoScaler{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "TidbClusterAutoScaler",
+			APIVersion: "pingcap.com/v1alpha1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "auto-scaler",
+			Namespace: corev1.NamespaceDefault,
+			UID:       types.UID("test"),
+		},
+		Spec: v1alpha1.TidbClusterAutoScalerSpec{
+			Cluster:    v1alpha1.TidbClusterRef{Name: "demo"},
+			MetricsURL: metricsURL,
+			TiDB: &v1alpha1.TidbAutoScalerSpec{BasicAutoScalerSpec: v1alpha1.BasicAutoScalerSpec{
+				MinReplicas: controller.Int32Ptr(2),
+				MaxReplicas: 5,
+			}},
+		},
+	}
+}
+
+func newTidbCluster() *v1alpha1.TidbCluster {
+	requests := &v1alpha1.ResourceRequirement{CPU: "1"}
+	return &v1alpha1.TidbCluster{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "TidbCluster",
+			APIVersion: "pingcap.com/v1alpha1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "demo",
+			Namespace: corev1.NamespaceDefault,
+			UID:       types.UID("test"),
+		},
+		Spec: v1alpha1.TidbClusterSpec{
+			TiKV: v1alpha1.TiKVSpec{
+				Resources: v1alpha1.Resources{Requests: requests},
+				Replicas:  3,
+			},
+			TiDB: v1alpha1.TiDBSpec{
+				Resources: v1alpha1.Resources{Requests: requests},
+				Replicas:  2,
+			},
+		},
+		Status: v1alpha1.TidbClusterStatus{
+			TiKV: v1alpha1.TiKVStatus{
+				Phase:       v1alpha1.NormalPhase,
+				StatefulSet: &apps.StatefulSetStatus{Replicas: 3},
+				Stores: map[string]v1alpha1.TiKVStore{
+					"1": {ID: "1"},
+					"2": {ID: "2"},
+					"3": {ID: "3"},
+				},
+			},
+			TiDB: v1alpha1.TiDBStatus{
+				Phase:       v1alpha1.NormalPhase,
+				StatefulSet: &apps.StatefulSetStatus{Replicas: 2},
+			},
+		},
+	}
+}
+
+func newStoreInfo(id uint64, capacity, available typeutil.ByteSize) *pdapi.StoreInfo {
+	return &pdapi.StoreInfo{
+		Store: &pdapi.MetaStore{Store: &metapb.Store{Id: id}},
+		Status: &pdapi.StoreStatus{
+			Capacity:  capacity,
+			Available: available,
+		},
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoscaler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	// the labels are added to the targets by TidbMonitor
+	cpuQueryPattern     = `sum(rate(process_cpu_seconds_total{cluster="%s",kubernetes_namespace="%s",component="%s"}[%s]))`
+	tidbQPSQueryPattern = `sum(rate(tidb_server_query_total{cluster="%s",kubernetes_namespace="%s",component="tidb"}[%s]))`
+	tikvQPSQueryPattern = `sum(rate(tikv_grpc_msg_duration_seconds_count{cluster="%s",kubernetes_namespace="%s",component="tikv",type!="kv_gc"}[%s]))`
+)
+
+// queryResponse is the response of the Prometheus instant query API
+type queryResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Metric map[string]string `json:"metric"`
+			Value  []interface{}     `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// queryMetric queries the Prometheus compatible API at metricsURL and returns the sum of the result vector
+func queryMetric(cli *http.Client, metricsURL string, query string) (float64, error) {
+	u := fmt.Sprintf("%s/api/v1/query?%s", strings.TrimSuffix(metricsURL, "/"), url.Values{"query": []string{query}}.Encode())
+	res, err := cli.Get(u)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return 0, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("query %s failed, status: %s, body: %s", query, res.Status, string(body))
+	}
+
+	resp := &queryResponse{}
+	if err := json.Unmarshal(body, resp); err != nil {
+		return 0, fmt.Errorf("query %s failed, unmarshal response %s failed, err: %v", query, string(body), err)
+	}
+	if resp.Status != "success" {
+		return 0, fmt.Errorf("query %s failed, err: %s", query, resp.Error)
+	}
+	if resp.Data.ResultType != "vector" {
+		return 0, fmt.Errorf("query %s failed, unexpected result type %s", query, resp.Data.ResultType)
+	}
+	if len(resp.Data.Result) == 0 {
+		return 0, fmt.Errorf("query %s returns no data", query)
+	}
+
+	var sum float64
+	for _, sample := range resp.Data.Result {
+		if len(sample.Value) != 2 {
+			return 0, fmt.Errorf("query %s failed, unexpected sample %v", query, sample.Value)
+		}
+		s, ok := sample.Value[1].(string)
+		if !ok {
+			return 0, fmt.Errorf("query %s failed, unexpected sample %v", query, sample.Value)
+		}
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return 0, fmt.Errorf("query %s failed, parse sample %s failed, err: %v", query, s, err)
+		}
+		sum += v
+	}
+	return sum, nil
+}
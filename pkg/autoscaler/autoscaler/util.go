@@ -0,0 +1,120 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoscaler
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	defaultTiDBMinReplicas int32 = 1
+	// defaultTiKVMinReplicas is the default max-replicas of PD, the stores deleted below it never become tombstone
+	defaultTiKVMinReplicas         int32 = 3
+	defaultScaleOutIntervalSeconds int32 = 300
+	defaultScaleInIntervalSeconds  int32 = 500
+	defaultMetricsTimeDuration           = "3m"
+	defaultTargetCPUUtilization    int32 = 80
+)
+
+// defaultMetrics returns the metrics of the spec, the CPU utilization of 80% if there is none
+func defaultMetrics(spec *v1alpha1.BasicAutoScalerSpec) []v1alpha1.AutoScalerMetric {
+	if len(spec.Metrics) > 0 {
+		return spec.Metrics
+	}
+	target := defaultTargetCPUUtilization
+	return []v1alpha1.AutoScalerMetric{{
+		Type:                     v1alpha1.CPUAutoScalerMetricType,
+		TargetAverageUtilization: &target,
+	}}
+}
+
+// minReplicas returns the minReplicas of the spec, or defaultMin if it is not set
+func minReplicas(spec *v1alpha1.BasicAutoScalerSpec, defaultMin int32) int32 {
+	if spec.MinReplicas != nil {
+		return *spec.MinReplicas
+	}
+	return defaultMin
+}
+
+// validateReplicas rejects the bounds of the spec which can not be satisfied
+func validateReplicas(memberType v1alpha1.MemberType, spec *v1alpha1.BasicAutoScalerSpec, defaultMin int32) error {
+	if min := minReplicas(spec, defaultMin); min > spec.MaxReplicas {
+		return fmt.Errorf("%s minReplicas %d is larger than maxReplicas %d", memberType, min, spec.MaxReplicas)
+	}
+	return nil
+}
+
+func metricsTimeDuration(spec *v1alpha1.BasicAutoScalerSpec) string {
+	if spec.MetricsTimeDuration != "" {
+		return spec.MetricsTimeDuration
+	}
+	return defaultMetricsTimeDuration
+}
+
+// limitReplicas keeps the replicas within min and the maxReplicas of the spec
+func limitReplicas(spec *v1alpha1.BasicAutoScalerSpec, min int32, replicas int32) int32 {
+	if replicas > spec.MaxReplicas {
+		replicas = spec.MaxReplicas
+	}
+	if replicas < min {
+		replicas = min
+	}
+	return replicas
+}
+
+// inCooldown returns the remaining cooldown before scaling from current to recommended replicas
+func inCooldown(spec *v1alpha1.BasicAutoScalerSpec, lastScaling *metav1.Time, current, recommended int32, now time.Time) time.Duration {
+	if lastScaling == nil {
+		return 0
+	}
+	interval := defaultScaleOutIntervalSeconds
+	if recommended > current && spec.ScaleOutIntervalSeconds != nil {
+		interval = *spec.ScaleOutIntervalSeconds
+	}
+	if recommended < current {
+		interval = defaultScaleInIntervalSeconds
+		if spec.ScaleInIntervalSeconds != nil {
+			interval = *spec.ScaleInIntervalSeconds
+		}
+	}
+	remaining := lastScaling.Add(time.Duration(interval) * time.Second).Sub(now)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// recommendByUtilization returns the replicas keeping the total usage at the target percentage of the capacity
+// of each replica
+func recommendByUtilization(usage, capacityPerReplica float64, targetPercentage int32) int32 {
+	return int32(math.Ceil(usage / (capacityPerReplica * float64(targetPercentage) / 100)))
+}
+
+// cpuRequestCores returns the CPU cores requested by each Pod of the component
+func cpuRequestCores(resources v1alpha1.Resources) (float64, error) {
+	if resources.Requests == nil || resources.Requests.CPU == "" {
+		return 0, fmt.Errorf("cpu requests are not set")
+	}
+	q, err := resource.ParseQuantity(resources.Requests.CPU)
+	if err != nil {
+		return 0, fmt.Errorf("parse cpu requests %s failed, err: %v", resources.Requests.CPU, err)
+	}
+	return float64(q.MilliValue()) / 1000, nil
+}
@@ -27,6 +27,12 @@ type RestoreManager interface {
 	Sync(backup *v1alpha1.Restore) error
 }
 
+// DataImportManager implements the logic for manage data import.
+type DataImportManager interface {
+	// Sync	implements the logic for syncing DataImport.
+	Sync(di *v1alpha1.DataImport) error
+}
+
 // BackupScheduleManager implements the logic for manage backupSchedule.
 type BackupScheduleManager interface {
 	// Sync	implements the logic for syncing BackupSchedule.
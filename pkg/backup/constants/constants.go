@@ -43,4 +43,16 @@ const (
 
 	// GcsCredentialsKey represents the gcs service account credentials json key in related secret
 	GcsCredentialsKey = "credentials"
+
+	// DataImportSourcePath is the path the PVC of the local source data is mounted to in the data import job
+	DataImportSourcePath = "/source"
+
+	// LightningBinPath is the path the tidb-lightning binary is copied to in the data import job
+	LightningBinPath = "/lightning"
+
+	// LightningStatusPort is the port of the status server of tidb-lightning
+	LightningStatusPort = 8289
+
+	// DefaultImporterPort is the port of tikv-importer
+	DefaultImporterPort = 8287
 )
@@ -0,0 +1,383 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataimport
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/pingcap/kvproto/pkg/import_sstpb"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/backup"
+	"github.com/pingcap/tidb-operator/pkg/backup/constants"
+	backuputil "github.com/pingcap/tidb-operator/pkg/backup/util"
+	listers "github.com/pingcap/tidb-operator/pkg/client/listers/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	batchlisters "k8s.io/client-go/listers/batch/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	glog "k8s.io/klog"
+)
+
+type dataImportManager struct {
+	tcLister      listers.TidbClusterLister
+	statusUpdater controller.DataImportStatusUpdaterInterface
+	secretLister  corelisters.SecretLister
+	jobLister     batchlisters.JobLister
+	jobControl    controller.JobControlInterface
+	pvcLister     corelisters.PersistentVolumeClaimLister
+	pvcControl    controller.GeneralPVCControlInterface
+	tikvControl   controller.TiKVControlInterface
+}
+
+// NewDataImportManager return dataImportManager
+func NewDataImportManager(
+	tcLister listers.TidbClusterLister,
+	statusUpdater controller.DataImportStatusUpdaterInterface,
+	secretLister corelisters.SecretLister,
+	jobLister batchlisters.JobLister,
+	jobControl controller.JobControlInterface,
+	pvcLister corelisters.PersistentVolumeClaimLister,
+	pvcControl controller.GeneralPVCControlInterface,
+	tikvControl controller.TiKVControlInterface,
+) backup.DataImportManager {
+	return &dataImportManager{
+		tcLister,
+		statusUpdater,
+		secretLister,
+		jobLister,
+		jobControl,
+		pvcLister,
+		pvcControl,
+		tikvControl,
+	}
+}
+
+func (dm *dataImportManager) Sync(di *v1alpha1.DataImport) error {
+	if v1alpha1.IsDataImportComplete(di) || v1alpha1.IsDataImportFailed(di) {
+		return dm.switchToNormalMode(di)
+	}
+	return dm.syncDataImportJob(di)
+}
+
+func (dm *dataImportManager) syncDataImportJob(di *v1alpha1.DataImport) error {
+	ns := di.GetNamespace()
+	name := di.GetName()
+	jobName := di.GetDataImportJobName()
+
+	_, err := dm.jobLister.Jobs(ns).Get(jobName)
+	if err == nil {
+		// already have a data import job running，return directly
+		return nil
+	}
+
+	if !errors.IsNotFound(err) {
+		return fmt.Errorf("data import %s/%s get job %s failed, err: %v", ns, name, jobName, err)
+	}
+
+	// not found data import job, need to create it
+	tc, err := dm.tcLister.TidbClusters(ns).Get(di.Spec.Cluster)
+	if err != nil {
+		errMsg := fmt.Errorf("data import %s/%s get tidbcluster %s failed, err: %v", ns, name, di.Spec.Cluster, err)
+		dm.statusUpdater.Update(di, &v1alpha1.DataImportCondition{
+			Type:    v1alpha1.DataImportRetryFailed,
+			Status:  corev1.ConditionTrue,
+			Reason:  "GetTidbClusterFailed",
+			Message: errMsg.Error(),
+		})
+		return errMsg
+	}
+
+	job, reason, err := dm.makeDataImportJob(di, tc)
+	if err != nil {
+		dm.statusUpdater.Update(di, &v1alpha1.DataImportCondition{
+			Type:    v1alpha1.DataImportRetryFailed,
+			Status:  corev1.ConditionTrue,
+			Reason:  reason,
+			Message: err.Error(),
+		})
+		return err
+	}
+
+	reason, err = dm.ensureDataImportPVCExist(di)
+	if err != nil {
+		dm.statusUpdater.Update(di, &v1alpha1.DataImportCondition{
+			Type:    v1alpha1.DataImportRetryFailed,
+			Status:  corev1.ConditionTrue,
+			Reason:  reason,
+			Message: err.Error(),
+		})
+		return err
+	}
+
+	if di.GetBackend() == v1alpha1.ImportBackendImporter {
+		// tidb-lightning switches the TiKV stores to import mode too, switching them by tidb-operator
+		// makes sure that they are switched back to normal mode even if tidb-lightning exits abnormally
+		if err := dm.tikvControl.SwitchMode(tc, import_sstpb.SwitchMode_Import); err != nil {
+			errMsg := fmt.Errorf("data import %s/%s switch tidbcluster %s to import mode failed, err: %v", ns, name, tc.GetName(), err)
+			dm.statusUpdater.Update(di, &v1alpha1.DataImportCondition{
+				Type:    v1alpha1.DataImportRetryFailed,
+				Status:  corev1.ConditionTrue,
+				Reason:  "SwitchImportModeFailed",
+				Message: errMsg.Error(),
+			})
+			return errMsg
+		}
+		if err := dm.statusUpdater.Update(di, &v1alpha1.DataImportCondition{
+			Type:   v1alpha1.DataImportTiKVImportMode,
+			Status: corev1.ConditionTrue,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := dm.jobControl.CreateJob(di, job); err != nil {
+		errMsg := fmt.Errorf("create data import %s/%s job %s failed, err: %v", ns, name, jobName, err)
+		dm.statusUpdater.Update(di, &v1alpha1.DataImportCondition{
+			Type:    v1alpha1.DataImportRetryFailed,
+			Status:  corev1.ConditionTrue,
+			Reason:  "CreateDataImportJobFailed",
+			Message: errMsg.Error(),
+		})
+		return errMsg
+	}
+
+	return dm.statusUpdater.Update(di, &v1alpha1.DataImportCondition{
+		Type:   v1alpha1.DataImportScheduled,
+		Status: corev1.ConditionTrue,
+	})
+}
+
+// switchToNormalMode switches the TiKV stores back to normal mode after the import completes or fails
+func (dm *dataImportManager) switchToNormalMode(di *v1alpha1.DataImport) error {
+	if !v1alpha1.IsTiKVInImportMode(di) {
+		return nil
+	}
+	ns := di.GetNamespace()
+	name := di.GetName()
+
+	tc, err := dm.tcLister.TidbClusters(ns).Get(di.Spec.Cluster)
+	if errors.IsNotFound(err) {
+		glog.Infof("data import %s/%s tidbcluster %s is deleted, skip switching it to normal mode", ns, name, di.Spec.Cluster)
+		return dm.statusUpdater.Update(di, &v1alpha1.DataImportCondition{
+			Type:   v1alpha1.DataImportTiKVImportMode,
+			Status: corev1.ConditionFalse,
+			Reason: "TidbClusterNotFound",
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("data import %s/%s get tidbcluster %s failed, err: %v", ns, name, di.Spec.Cluster, err)
+	}
+
+	if err := dm.tikvControl.SwitchMode(tc, import_sstpb.SwitchMode_Normal); err != nil {
+		return fmt.Errorf("data import %s/%s switch tidbcluster %s to normal mode failed, err: %v", ns, name, tc.GetName(), err)
+	}
+	return dm.statusUpdater.Update(di, &v1alpha1.DataImportCondition{
+		Type:   v1alpha1.DataImportTiKVImportMode,
+		Status: corev1.ConditionFalse,
+		Reason: "ImportFinished",
+	})
+}
+
+func (dm *dataImportManager) makeDataImportJob(di *v1alpha1.DataImport, tc *v1alpha1.TidbCluster) (*batchv1.Job, string, error) {
+	ns := di.GetNamespace()
+	name := di.GetName()
+
+	user, password, reason, err := backuputil.GetTidbUserAndPassword(ns, name, di.Spec.TidbSecretName, dm.secretLister)
+	if err != nil {
+		return nil, reason, err
+	}
+
+	storageEnv, reason, err := backuputil.GenerateStorageProviderCertEnv("data import", ns, name,
+		di.Spec.StorageType, &di.Spec.StorageProvider, dm.secretLister)
+	if err != nil {
+		return nil, reason, err
+	}
+
+	importer := di.Spec.Importer
+	if importer == "" {
+		importer = fmt.Sprintf("%s-importer:%d", di.Spec.Cluster, constants.DefaultImporterPort)
+	}
+	args := []string{
+		"import",
+		fmt.Sprintf("--namespace=%s", ns),
+		fmt.Sprintf("--dataImportName=%s", name),
+		fmt.Sprintf("--tidbcluster=%s", di.Spec.Cluster),
+		fmt.Sprintf("--tidbservice=%s", controller.TiDBMemberName(di.Spec.Cluster)),
+		fmt.Sprintf("--pd=%s:2379", controller.PDMemberName(di.Spec.Cluster)),
+		fmt.Sprintf("--importer=%s", importer),
+		fmt.Sprintf("--password=%s", password),
+		fmt.Sprintf("--user=%s", user),
+	}
+
+	image := di.Spec.Image
+	if image == "" {
+		version := tc.Spec.Version
+		if version == "" {
+			version = "latest"
+		}
+		image = fmt.Sprintf("pingcap/tidb-lightning:%s", version)
+	}
+
+	volumeMounts := []corev1.VolumeMount{
+		{Name: label.DataImportJobLabelVal, MountPath: constants.BackupRootPath},
+		{Name: "lightning", MountPath: constants.LightningBinPath},
+	}
+	volumes := []corev1.Volume{
+		{
+			Name: label.DataImportJobLabelVal,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: di.GetDataImportPVCName(),
+				},
+			},
+		},
+		{
+			Name: "lightning",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+	}
+	if di.Spec.StorageType == v1alpha1.BackupStorageTypeLocal {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name: "source", MountPath: constants.DataImportSourcePath, ReadOnly: true,
+		})
+		volumes = append(volumes, corev1.Volume{
+			Name: "source",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: di.Spec.Local.ClaimName,
+					ReadOnly:  true,
+				},
+			},
+		})
+	}
+
+	diLabel := label.NewDataImport().Instance(di.Spec.Cluster).DataImportJob().DataImport(name)
+	podSpec := &corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:      diLabel.Labels(),
+			Annotations: controller.AnnProm(constants.LightningStatusPort),
+		},
+		Spec: corev1.PodSpec{
+			ServiceAccountName: constants.DefaultServiceAccountName,
+			InitContainers: []corev1.Container{
+				{
+					// the data import job runs the tidb-lightning binary in the image of the cluster version
+					Name:    "lightning",
+					Image:   image,
+					Command: []string{"/bin/sh", "-c", fmt.Sprintf("cp /tidb-lightning %s", filepath.Join(constants.LightningBinPath, "tidb-lightning"))},
+					VolumeMounts: []corev1.VolumeMount{
+						{Name: "lightning", MountPath: constants.LightningBinPath},
+					},
+				},
+			},
+			Containers: []corev1.Container{
+				{
+					Name:            label.DataImportJobLabelVal,
+					Image:           controller.TidbBackupManagerImage,
+					Args:            args,
+					ImagePullPolicy: corev1.PullAlways,
+					Ports: []corev1.ContainerPort{{
+						Name:          "status",
+						ContainerPort: constants.LightningStatusPort,
+					}},
+					VolumeMounts: volumeMounts,
+					Env:          storageEnv,
+					Resources:    di.Spec.Resources,
+				},
+			},
+			RestartPolicy: corev1.RestartPolicyNever,
+			Volumes:       volumes,
+		},
+	}
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      di.GetDataImportJobName(),
+			Namespace: ns,
+			Labels:    diLabel,
+			OwnerReferences: []metav1.OwnerReference{
+				controller.GetDataImportOwnerRef(di),
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: controller.Int32Ptr(0),
+			Template:     *podSpec,
+		},
+	}
+	return job, "", nil
+}
+
+// ensureDataImportPVCExist creates the PVC keeping the downloaded data and the checkpoint of tidb-lightning,
+// the PVC is owned by the DataImport so that it is kept across the retries and deleted with the DataImport
+func (dm *dataImportManager) ensureDataImportPVCExist(di *v1alpha1.DataImport) (string, error) {
+	ns := di.GetNamespace()
+	name := di.GetName()
+
+	storageSize := constants.DefaultStorageSize
+	if di.Spec.StorageSize != "" {
+		storageSize = di.Spec.StorageSize
+	}
+	rs, err := resource.ParseQuantity(storageSize)
+	if err != nil {
+		errMsg := fmt.Errorf("data import %s/%s parse storage size %s failed, err: %v", ns, name, storageSize, err)
+		return "ParseStorageSizeFailed", errMsg
+	}
+
+	pvcName := di.GetDataImportPVCName()
+	_, err = dm.pvcLister.PersistentVolumeClaims(ns).Get(pvcName)
+	if err != nil {
+		// get the object from the local cache, the error can only be IsNotFound,
+		// so we need to create PVC for data import job
+		storageClassName := controller.DefaultBackupStorageClassName
+		if di.Spec.StorageClassName != "" {
+			storageClassName = di.Spec.StorageClassName
+		}
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      pvcName,
+				Namespace: ns,
+				Labels:    label.NewDataImport().Instance(di.Spec.Cluster).DataImport(name),
+				OwnerReferences: []metav1.OwnerReference{
+					controller.GetDataImportOwnerRef(di),
+				},
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				StorageClassName: &storageClassName,
+				AccessModes: []corev1.PersistentVolumeAccessMode{
+					corev1.ReadWriteOnce,
+				},
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: rs,
+					},
+				},
+			},
+		}
+		if err := dm.pvcControl.CreatePVC(di, pvc); err != nil {
+			errMsg := fmt.Errorf("%s/%s create data import pvc %s failed, err: %v", ns, name, pvc.GetName(), err)
+			return "CreatePVCFailed", errMsg
+		}
+	}
+	return "", nil
+}
+
+var _ backup.DataImportManager = &dataImportManager{}
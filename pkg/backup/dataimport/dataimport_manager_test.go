@@ -0,0 +1,306 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataimport
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/kvproto/pkg/import_sstpb"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/backup/constants"
+	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned/fake"
+	informers "github.com/pingcap/tidb-operator/pkg/client/informers/externalversions"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kubeinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestDataImportManagerSync(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name           string
+		prepare        func(di *v1alpha1.DataImport, fi *fakeIndexers)
+		switchModeErr  bool
+		errExpectFn    func(*GomegaWithT, error)
+		expectFn       func(*GomegaWithT, *v1alpha1.DataImport, *fakeIndexers)
+		expectMode     *import_sstpb.SwitchMode
+		expectJobCount int
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+
+		di := newDataImport()
+		dm, fi := newFakeDataImportManager()
+		fi.di.Add(di)
+		fi.tc.Add(newTidbCluster())
+		fi.secret.Add(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "demo-tidb-secret", Namespace: corev1.NamespaceDefault},
+			Data: map[string][]byte{
+				constants.TidbUserKey:     []byte("root"),
+				constants.TidbPasswordKey: []byte("secret"),
+			},
+		})
+		if test.prepare != nil {
+			test.prepare(di, fi)
+		}
+		if test.switchModeErr {
+			fi.tikvControl.SetSwitchModeError(fmt.Errorf("switch mode failed"))
+		}
+
+		err := dm.Sync(di)
+		test.errExpectFn(g, err)
+
+		mode, ok := fi.tikvControl.GetMode(newTidbCluster())
+		if test.expectMode == nil {
+			g.Expect(ok).To(BeFalse())
+		} else {
+			g.Expect(ok).To(BeTrue())
+			g.Expect(mode).To(Equal(*test.expectMode))
+		}
+		g.Expect(fi.job.List()).To(HaveLen(test.expectJobCount))
+		if test.expectFn != nil {
+			test.expectFn(g, di, fi)
+		}
+	}
+
+	importMode := import_sstpb.SwitchMode_Import
+	normalMode := import_sstpb.SwitchMode_Normal
+	tests := []testcase{
+		{
+			name:           "create the import job with the importer backend",
+			errExpectFn:    errExpectNil,
+			expectMode:     &importMode,
+			expectJobCount: 1,
+			expectFn: func(g *GomegaWithT, di *v1alpha1.DataImport, fi *fakeIndexers) {
+				g.Expect(v1alpha1.IsTiKVInImportMode(di)).To(BeTrue())
+				g.Expect(v1alpha1.IsDataImportScheduled(di)).To(BeTrue())
+				g.Expect(fi.pvc.List()).To(HaveLen(1))
+
+				obj, _, err := fi.job.GetByKey("default/import-demo-import")
+				g.Expect(err).NotTo(HaveOccurred())
+				job := obj.(*batchv1.Job)
+				g.Expect(job.Spec.Template.Spec.InitContainers[0].Image).To(Equal("pingcap/tidb-lightning:v3.0.8"))
+				g.Expect(job.Spec.Template.Spec.Containers[0].Args).To(ContainElement("--importer=demo-importer:8287"))
+			},
+		},
+		{
+			name: "create the import job with the tidb backend",
+			prepare: func(di *v1alpha1.DataImport, _ *fakeIndexers) {
+				di.Spec.Backend = v1alpha1.ImportBackendTiDB
+			},
+			errExpectFn:    errExpectNil,
+			expectJobCount: 1,
+			expectFn: func(g *GomegaWithT, di *v1alpha1.DataImport, _ *fakeIndexers) {
+				g.Expect(v1alpha1.IsTiKVInImportMode(di)).To(BeFalse())
+				g.Expect(v1alpha1.IsDataImportScheduled(di)).To(BeTrue())
+			},
+		},
+		{
+			name: "the import job exists",
+			prepare: func(di *v1alpha1.DataImport, fi *fakeIndexers) {
+				fi.job.Add(&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: di.GetDataImportJobName(), Namespace: di.GetNamespace()}})
+			},
+			errExpectFn:    errExpectNil,
+			expectJobCount: 1,
+			expectFn: func(g *GomegaWithT, di *v1alpha1.DataImport, _ *fakeIndexers) {
+				g.Expect(di.Status.Conditions).To(BeEmpty())
+			},
+		},
+		{
+			name: "the tidbcluster is not found",
+			prepare: func(di *v1alpha1.DataImport, _ *fakeIndexers) {
+				di.Spec.Cluster = "missing"
+			},
+			errExpectFn: errExpectNotNil,
+			expectFn: func(g *GomegaWithT, di *v1alpha1.DataImport, _ *fakeIndexers) {
+				_, condition := v1alpha1.GetDataImportCondition(&di.Status, v1alpha1.DataImportRetryFailed)
+				g.Expect(condition.Reason).To(Equal("GetTidbClusterFailed"))
+			},
+		},
+		{
+			name: "the tidb secret is not found",
+			prepare: func(di *v1alpha1.DataImport, _ *fakeIndexers) {
+				di.Spec.TidbSecretName = "missing"
+			},
+			errExpectFn: errExpectNotNil,
+			expectFn: func(g *GomegaWithT, di *v1alpha1.DataImport, fi *fakeIndexers) {
+				_, condition := v1alpha1.GetDataImportCondition(&di.Status, v1alpha1.DataImportRetryFailed)
+				g.Expect(condition.Reason).To(Equal("GetTidbSecretFailed"))
+				g.Expect(fi.pvc.List()).To(BeEmpty())
+			},
+		},
+		{
+			name:          "switching to import mode failed",
+			switchModeErr: true,
+			errExpectFn:   errExpectNotNil,
+			expectFn: func(g *GomegaWithT, di *v1alpha1.DataImport, _ *fakeIndexers) {
+				_, condition := v1alpha1.GetDataImportCondition(&di.Status, v1alpha1.DataImportRetryFailed)
+				g.Expect(condition.Reason).To(Equal("SwitchImportModeFailed"))
+				g.Expect(v1alpha1.IsTiKVInImportMode(di)).To(BeFalse())
+			},
+		},
+		{
+			name: "switch back to normal mode after the import completes",
+			prepare: func(di *v1alpha1.DataImport, _ *fakeIndexers) {
+				setDataImportCondition(di, v1alpha1.DataImportTiKVImportMode)
+				setDataImportCondition(di, v1alpha1.DataImportComplete)
+			},
+			errExpectFn: errExpectNil,
+			expectMode:  &normalMode,
+			expectFn: func(g *GomegaWithT, di *v1alpha1.DataImport, _ *fakeIndexers) {
+				_, condition := v1alpha1.GetDataImportCondition(&di.Status, v1alpha1.DataImportTiKVImportMode)
+				g.Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+				g.Expect(condition.Reason).To(Equal("ImportFinished"))
+			},
+		},
+		{
+			name: "switching back to normal mode failed is retried",
+			prepare: func(di *v1alpha1.DataImport, _ *fakeIndexers) {
+				setDataImportCondition(di, v1alpha1.DataImportTiKVImportMode)
+				setDataImportCondition(di, v1alpha1.DataImportFailed)
+			},
+			switchModeErr: true,
+			errExpectFn:   errExpectNotNil,
+			expectFn: func(g *GomegaWithT, di *v1alpha1.DataImport, _ *fakeIndexers) {
+				g.Expect(v1alpha1.IsTiKVInImportMode(di)).To(BeTrue())
+			},
+		},
+		{
+			name: "the tidbcluster is deleted after the import fails",
+			prepare: func(di *v1alpha1.DataImport, fi *fakeIndexers) {
+				setDataImportCondition(di, v1alpha1.DataImportTiKVImportMode)
+				setDataImportCondition(di, v1alpha1.DataImportFailed)
+				fi.tc.Delete(newTidbCluster())
+			},
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, di *v1alpha1.DataImport, _ *fakeIndexers) {
+				_, condition := v1alpha1.GetDataImportCondition(&di.Status, v1alpha1.DataImportTiKVImportMode)
+				g.Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+				g.Expect(condition.Reason).To(Equal("TidbClusterNotFound"))
+			},
+		},
+		{
+			name: "the completed import with the tidb backend is not synced",
+			prepare: func(di *v1alpha1.DataImport, _ *fakeIndexers) {
+				di.Spec.Backend = v1alpha1.ImportBackendTiDB
+				setDataImportCondition(di, v1alpha1.DataImportComplete)
+			},
+			errExpectFn: errExpectNil,
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
+
+type fakeIndexers struct {
+	di          cache.Indexer
+	tc          cache.Indexer
+	secret      cache.Indexer
+	job         cache.Indexer
+	pvc         cache.Indexer
+	tikvControl *controller.FakeTiKVControl
+}
+
+func newFakeDataImportManager() (*dataImportManager, *fakeIndexers) {
+	cli := fake.NewSimpleClientset()
+	kubeCli := kubefake.NewSimpleClientset()
+	informerFactory := informers.NewSharedInformerFactory(cli, 0)
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeCli, 0)
+	diInformer := informerFactory.Pingcap().V1alpha1().DataImports()
+	tcInformer := informerFactory.Pingcap().V1alpha1().TidbClusters()
+	secretInformer := kubeInformerFactory.Core().V1().Secrets()
+	jobInformer := kubeInformerFactory.Batch().V1().Jobs()
+	pvcInformer := kubeInformerFactory.Core().V1().PersistentVolumeClaims()
+	tikvControl := controller.NewFakeTiKVControl()
+
+	dm := &dataImportManager{
+		tcInformer.Lister(),
+		controller.NewFakeDataImportStatusUpdater(diInformer),
+		secretInformer.Lister(),
+		jobInformer.Lister(),
+		controller.NewFakeJobControl(jobInformer),
+		pvcInformer.Lister(),
+		controller.NewFakeGeneralPVCControl(pvcInformer),
+		tikvControl,
+	}
+	return dm, &fakeIndexers{
+		di:          diInformer.Informer().GetIndexer(),
+		tc:          tcInformer.Informer().GetIndexer(),
+		secret:      secretInformer.Informer().GetIndexer(),
+		job:         jobInformer.Informer().GetIndexer(),
+		pvc:         pvcInformer.Informer().GetIndexer(),
+		tikvControl: tikvControl,
+	}
+}
+
+func newDataImport() *v1alpha1.DataImport {
+	return &v1alpha1.DataImport{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "DataImport",
+			APIVersion: "pingcap.com/v1alpha1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "demo-import",
+			Namespace: corev1.NamespaceDefault,
+			UID:       types.UID("test-di"),
+		},
+		Spec: v1alpha1.DataImportSpec{
+			Cluster:        "demo",
+			TidbSecretName: "demo-tidb-secret",
+			StorageType:    v1alpha1.BackupStorageTypeLocal,
+			StorageProvider: v1alpha1.StorageProvider{
+				Local: &v1alpha1.LocalStorageProvider{ClaimName: "demo-backup"},
+			},
+			Path: "/data",
+		},
+	}
+}
+
+func newTidbCluster() *v1alpha1.TidbCluster {
+	return &v1alpha1.TidbCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "demo",
+			Namespace: corev1.NamespaceDefault,
+		},
+		Spec: v1alpha1.TidbClusterSpec{
+			Version: "v3.0.8",
+		},
+	}
+}
+
+func setDataImportCondition(di *v1alpha1.DataImport, conditionType v1alpha1.DataImportConditionType) {
+	v1alpha1.UpdateDataImportCondition(&di.Status, &v1alpha1.DataImportCondition{
+		Type:   conditionType,
+		Status: corev1.ConditionTrue,
+	})
+}
+
+func errExpectNil(g *GomegaWithT, err error) {
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+func errExpectNotNil(g *GomegaWithT, err error) {
+	g.Expect(err).To(HaveOccurred())
+}
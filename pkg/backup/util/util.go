@@ -132,52 +132,72 @@ func GenerateGcsCertEnvVar(secret *corev1.Secret, gcs *v1alpha1.GcsStorageProvid
 
 // GenerateStorageCertEnv generate the env info in order to access backend backup storage
 func GenerateStorageCertEnv(backup *v1alpha1.Backup, secretLister corelisters.SecretLister) ([]corev1.EnvVar, string, error) {
-	ns := backup.GetNamespace()
-	name := backup.GetName()
+	if backup.Spec.StorageType == v1alpha1.BackupStorageTypeLocal {
+		err := fmt.Errorf("backup %s/%s don't support storage type %s", backup.GetNamespace(), backup.GetName(), backup.Spec.StorageType)
+		return nil, "NotSupportStorageType", err
+	}
+	return GenerateStorageProviderCertEnv("backup", backup.GetNamespace(), backup.GetName(),
+		backup.Spec.StorageType, &backup.Spec.StorageProvider, secretLister)
+}
 
+// GenerateStorageProviderCertEnv generate the env info in order to access the storage of the provider,
+// kind and name are the kind and name of the object the storage belongs to, e.g. backup and dataimport.
+// The local storage needs no cert.
+func GenerateStorageProviderCertEnv(kind, ns, name string, storageType v1alpha1.BackupStorageType,
+	provider *v1alpha1.StorageProvider, secretLister corelisters.SecretLister) ([]corev1.EnvVar, string, error) {
 	var certEnv []corev1.EnvVar
 	var reason string
 
-	switch backup.Spec.StorageType {
+	switch storageType {
 	case v1alpha1.BackupStorageTypeS3:
-		s3SecretName := backup.Spec.S3.SecretName
+		if provider.S3 == nil {
+			return certEnv, "S3ConfigIsEmpty", fmt.Errorf("%s %s/%s s3 config is empty", kind, ns, name)
+		}
+		s3SecretName := provider.S3.SecretName
 		secret, err := secretLister.Secrets(ns).Get(s3SecretName)
 		if err != nil {
-			err := fmt.Errorf("backup %s/%s get s3 secret %s failed, err: %v", ns, name, s3SecretName, err)
+			err := fmt.Errorf("%s %s/%s get s3 secret %s failed, err: %v", kind, ns, name, s3SecretName, err)
 			return certEnv, "GetS3SecretFailed", err
 		}
 
 		keyStr, exist := CheckAllKeysExistInSecret(secret, constants.S3AccessKey, constants.S3SecretKey)
 		if !exist {
-			err := fmt.Errorf("backup %s/%s, The s3 secret %s missing some keys %s", ns, name, s3SecretName, keyStr)
+			err := fmt.Errorf("%s %s/%s, The s3 secret %s missing some keys %s", kind, ns, name, s3SecretName, keyStr)
 			return certEnv, "s3KeyNotExist", err
 		}
 
-		certEnv, reason, err = GenerateS3CertEnvVar(secret, backup.Spec.S3.DeepCopy())
+		certEnv, reason, err = GenerateS3CertEnvVar(secret, provider.S3.DeepCopy())
 		if err != nil {
 			return certEnv, reason, err
 		}
 	case v1alpha1.BackupStorageTypeGcs:
-		gcsSecretName := backup.Spec.Gcs.SecretName
+		if provider.Gcs == nil {
+			return certEnv, "GcsConfigIsEmpty", fmt.Errorf("%s %s/%s gcs config is empty", kind, ns, name)
+		}
+		gcsSecretName := provider.Gcs.SecretName
 		secret, err := secretLister.Secrets(ns).Get(gcsSecretName)
 		if err != nil {
-			err := fmt.Errorf("backup %s/%s get gcs secret %s failed, err: %v", ns, name, gcsSecretName, err)
+			err := fmt.Errorf("%s %s/%s get gcs secret %s failed, err: %v", kind, ns, name, gcsSecretName, err)
 			return certEnv, "GetGcsSecretFailed", err
 		}
 
 		keyStr, exist := CheckAllKeysExistInSecret(secret, constants.GcsCredentialsKey)
 		if !exist {
-			err := fmt.Errorf("backup %s/%s, The gcs secret %s missing some keys %s", ns, name, gcsSecretName, keyStr)
+			err := fmt.Errorf("%s %s/%s, The gcs secret %s missing some keys %s", kind, ns, name, gcsSecretName, keyStr)
 			return certEnv, "gcsKeyNotExist", err
 		}
 
-		certEnv, reason, err = GenerateGcsCertEnvVar(secret, backup.Spec.Gcs)
+		certEnv, reason, err = GenerateGcsCertEnvVar(secret, provider.Gcs)
 
 		if err != nil {
 			return certEnv, reason, err
 		}
+	case v1alpha1.BackupStorageTypeLocal:
+		if provider.Local == nil || provider.Local.ClaimName == "" {
+			return certEnv, "LocalClaimNameIsEmpty", fmt.Errorf("%s %s/%s local claim name is empty", kind, ns, name)
+		}
 	default:
-		err := fmt.Errorf("backup %s/%s don't support storage type %s", ns, name, backup.Spec.StorageType)
+		err := fmt.Errorf("%s %s/%s don't support storage type %s", kind, ns, name, storageType)
 		return certEnv, "NotSupportStorageType", err
 	}
 	return certEnv, reason, nil
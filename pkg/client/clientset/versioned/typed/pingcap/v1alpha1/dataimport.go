@@ -0,0 +1,188 @@
+// Copyright 2019. PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"time"
+
+	v1alpha1 "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	scheme "github.com/pingcap/tidb-operator/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// DataImportsGetter has a method to return a DataImportInterface.
+// A group's client should implement this interface.
+type DataImportsGetter interface {
+	DataImports(namespace string) DataImportInterface
+}
+
+// DataImportInterface has methods to work with DataImport resources.
+type DataImportInterface interface {
+	Create(*v1alpha1.DataImport) (*v1alpha1.DataImport, error)
+	Update(*v1alpha1.DataImport) (*v1alpha1.DataImport, error)
+	UpdateStatus(*v1alpha1.DataImport) (*v1alpha1.DataImport, error)
+	Delete(name string, options *v1.DeleteOptions) error
+	DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error
+	Get(name string, options v1.GetOptions) (*v1alpha1.DataImport, error)
+	List(opts v1.ListOptions) (*v1alpha1.DataImportList, error)
+	Watch(opts v1.ListOptions) (watch.Interface, error)
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.DataImport, err error)
+	DataImportExpansion
+}
+
+// dataImports implements DataImportInterface
+type dataImports struct {
+	client rest.Interface
+	ns     string
+}
+
+// newDataImports returns a DataImports
+func newDataImports(c *PingcapV1alpha1Client, namespace string) *dataImports {
+	return &dataImports{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the dataImport, and returns the corresponding dataImport object, and an error if there is any.
+func (c *dataImports) Get(name string, options v1.GetOptions) (result *v1alpha1.DataImport, err error) {
+	result = &v1alpha1.DataImport{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("dataimports").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of DataImports that match those selectors.
+func (c *dataImports) List(opts v1.ListOptions) (result *v1alpha1.DataImportList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.DataImportList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("dataimports").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do().
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested dataImports.
+func (c *dataImports) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("dataimports").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch()
+}
+
+// Create takes the representation of a dataImport and creates it.  Returns the server's representation of the dataImport, and an error, if there is any.
+func (c *dataImports) Create(dataImport *v1alpha1.DataImport) (result *v1alpha1.DataImport, err error) {
+	result = &v1alpha1.DataImport{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("dataimports").
+		Body(dataImport).
+		Do().
+		Into(result)
+	return
+}
+
+// Update takes the representation of a dataImport and updates it. Returns the server's representation of the dataImport, and an error, if there is any.
+func (c *dataImports) Update(dataImport *v1alpha1.DataImport) (result *v1alpha1.DataImport, err error) {
+	result = &v1alpha1.DataImport{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("dataimports").
+		Name(dataImport.Name).
+		Body(dataImport).
+		Do().
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+
+func (c *dataImports) UpdateStatus(dataImport *v1alpha1.DataImport) (result *v1alpha1.DataImport, err error) {
+	result = &v1alpha1.DataImport{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("dataimports").
+		Name(dataImport.Name).
+		SubResource("status").
+		Body(dataImport).
+		Do().
+		Into(result)
+	return
+}
+
+// Delete takes name of the dataImport and deletes it. Returns an error if one occurs.
+func (c *dataImports) Delete(name string, options *v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("dataimports").
+		Name(name).
+		Body(options).
+		Do().
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *dataImports) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	var timeout time.Duration
+	if listOptions.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOptions.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("dataimports").
+		VersionedParams(&listOptions, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(options).
+		Do().
+		Error()
+}
+
+// Patch applies the patch and returns the patched dataImport.
+func (c *dataImports) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.DataImport, err error) {
+	result = &v1alpha1.DataImport{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("dataimports").
+		SubResource(subresources...).
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return
+}
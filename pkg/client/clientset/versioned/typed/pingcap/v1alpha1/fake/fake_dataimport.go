@@ -0,0 +1,137 @@
+// Copyright 2019. PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	v1alpha1 "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeDataImports implements DataImportInterface
+type FakeDataImports struct {
+	Fake *FakePingcapV1alpha1
+	ns   string
+}
+
+var dataimportsResource = schema.GroupVersionResource{Group: "pingcap.com", Version: "v1alpha1", Resource: "dataimports"}
+
+var dataimportsKind = schema.GroupVersionKind{Group: "pingcap.com", Version: "v1alpha1", Kind: "DataImport"}
+
+// Get takes name of the dataImport, and returns the corresponding dataImport object, and an error if there is any.
+func (c *FakeDataImports) Get(name string, options v1.GetOptions) (result *v1alpha1.DataImport, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(dataimportsResource, c.ns, name), &v1alpha1.DataImport{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.DataImport), err
+}
+
+// List takes label and field selectors, and returns the list of DataImports that match those selectors.
+func (c *FakeDataImports) List(opts v1.ListOptions) (result *v1alpha1.DataImportList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(dataimportsResource, dataimportsKind, c.ns, opts), &v1alpha1.DataImportList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.DataImportList{ListMeta: obj.(*v1alpha1.DataImportList).ListMeta}
+	for _, item := range obj.(*v1alpha1.DataImportList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested dataImports.
+func (c *FakeDataImports) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(dataimportsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a dataImport and creates it.  Returns the server's representation of the dataImport, and an error, if there is any.
+func (c *FakeDataImports) Create(dataImport *v1alpha1.DataImport) (result *v1alpha1.DataImport, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(dataimportsResource, c.ns, dataImport), &v1alpha1.DataImport{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.DataImport), err
+}
+
+// Update takes the representation of a dataImport and updates it. Returns the server's representation of the dataImport, and an error, if there is any.
+func (c *FakeDataImports) Update(dataImport *v1alpha1.DataImport) (result *v1alpha1.DataImport, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(dataimportsResource, c.ns, dataImport), &v1alpha1.DataImport{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.DataImport), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeDataImports) UpdateStatus(dataImport *v1alpha1.DataImport) (*v1alpha1.DataImport, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(dataimportsResource, "status", c.ns, dataImport), &v1alpha1.DataImport{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.DataImport), err
+}
+
+// Delete takes name of the dataImport and deletes it. Returns an error if one occurs.
+func (c *FakeDataImports) Delete(name string, options *v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(dataimportsResource, c.ns, name), &v1alpha1.DataImport{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeDataImports) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(dataimportsResource, c.ns, listOptions)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.DataImportList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched dataImport.
+func (c *FakeDataImports) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.DataImport, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(dataimportsResource, c.ns, name, pt, data, subresources...), &v1alpha1.DataImport{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.DataImport), err
+}
@@ -33,6 +33,10 @@ func (c *FakePingcapV1alpha1) BackupSchedules(namespace string) v1alpha1.BackupS
 	return &FakeBackupSchedules{c, namespace}
 }
 
+func (c *FakePingcapV1alpha1) DataImports(namespace string) v1alpha1.DataImportInterface {
+	return &FakeDataImports{c, namespace}
+}
+
 func (c *FakePingcapV1alpha1) DataResources(namespace string) v1alpha1.DataResourceInterface {
 	return &FakeDataResources{c, namespace}
 }
@@ -45,6 +49,14 @@ func (c *FakePingcapV1alpha1) TidbClusters(namespace string) v1alpha1.TidbCluste
 	return &FakeTidbClusters{c, namespace}
 }
 
+func (c *FakePingcapV1alpha1) TidbClusterAutoScalers(namespace string) v1alpha1.TidbClusterAutoScalerInterface {
+	return &FakeTidbClusterAutoScalers{c, namespace}
+}
+
+func (c *FakePingcapV1alpha1) TidbMonitors(namespace string) v1alpha1.TidbMonitorInterface {
+	return &FakeTidbMonitors{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakePingcapV1alpha1) RESTClient() rest.Interface {
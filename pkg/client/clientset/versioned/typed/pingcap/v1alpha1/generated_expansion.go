@@ -19,8 +19,14 @@ type BackupExpansion interface{}
 
 type BackupScheduleExpansion interface{}
 
+type DataImportExpansion interface{}
+
 type DataResourceExpansion interface{}
 
 type RestoreExpansion interface{}
 
 type TidbClusterExpansion interface{}
+
+type TidbClusterAutoScalerExpansion interface{}
+
+type TidbMonitorExpansion interface{}
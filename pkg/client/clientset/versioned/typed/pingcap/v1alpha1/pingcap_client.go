@@ -25,9 +25,12 @@ type PingcapV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	BackupsGetter
 	BackupSchedulesGetter
+	DataImportsGetter
 	DataResourcesGetter
 	RestoresGetter
 	TidbClustersGetter
+	TidbClusterAutoScalersGetter
+	TidbMonitorsGetter
 }
 
 // PingcapV1alpha1Client is used to interact with features provided by the pingcap.com group.
@@ -43,6 +46,10 @@ func (c *PingcapV1alpha1Client) BackupSchedules(namespace string) BackupSchedule
 	return newBackupSchedules(c, namespace)
 }
 
+func (c *PingcapV1alpha1Client) DataImports(namespace string) DataImportInterface {
+	return newDataImports(c, namespace)
+}
+
 func (c *PingcapV1alpha1Client) DataResources(namespace string) DataResourceInterface {
 	return newDataResources(c, namespace)
 }
@@ -55,6 +62,14 @@ func (c *PingcapV1alpha1Client) TidbClusters(namespace string) TidbClusterInterf
 	return newTidbClusters(c, namespace)
 }
 
+func (c *PingcapV1alpha1Client) TidbClusterAutoScalers(namespace string) TidbClusterAutoScalerInterface {
+	return newTidbClusterAutoScalers(c, namespace)
+}
+
+func (c *PingcapV1alpha1Client) TidbMonitors(namespace string) TidbMonitorInterface {
+	return newTidbMonitors(c, namespace)
+}
+
 // NewForConfig creates a new PingcapV1alpha1Client for the given config.
 func NewForConfig(c *rest.Config) (*PingcapV1alpha1Client, error) {
 	config := *c
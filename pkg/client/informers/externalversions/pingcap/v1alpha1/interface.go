@@ -25,12 +25,18 @@ type Interface interface {
 	Backups() BackupInformer
 	// BackupSchedules returns a BackupScheduleInformer.
 	BackupSchedules() BackupScheduleInformer
+	// DataImports returns a DataImportInformer.
+	DataImports() DataImportInformer
 	// DataResources returns a DataResourceInformer.
 	DataResources() DataResourceInformer
 	// Restores returns a RestoreInformer.
 	Restores() RestoreInformer
 	// TidbClusters returns a TidbClusterInformer.
 	TidbClusters() TidbClusterInformer
+	// TidbClusterAutoScalers returns a TidbClusterAutoScalerInformer.
+	TidbClusterAutoScalers() TidbClusterAutoScalerInformer
+	// TidbMonitors returns a TidbMonitorInformer.
+	TidbMonitors() TidbMonitorInformer
 }
 
 type version struct {
@@ -54,6 +60,11 @@ func (v *version) BackupSchedules() BackupScheduleInformer {
 	return &backupScheduleInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// DataImports returns a DataImportInformer.
+func (v *version) DataImports() DataImportInformer {
+	return &dataImportInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // DataResources returns a DataResourceInformer.
 func (v *version) DataResources() DataResourceInformer {
 	return &dataResourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -68,3 +79,13 @@ func (v *version) Restores() RestoreInformer {
 func (v *version) TidbClusters() TidbClusterInformer {
 	return &tidbClusterInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// TidbClusterAutoScalers returns a TidbClusterAutoScalerInformer.
+func (v *version) TidbClusterAutoScalers() TidbClusterAutoScalerInformer {
+	return &tidbClusterAutoScalerInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// TidbMonitors returns a TidbMonitorInformer.
+func (v *version) TidbMonitors() TidbMonitorInformer {
+	return &tidbMonitorInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
@@ -0,0 +1,86 @@
+// Copyright 2019. PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	time "time"
+
+	pingcapv1alpha1 "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	versioned "github.com/pingcap/tidb-operator/pkg/client/clientset/versioned"
+	internalinterfaces "github.com/pingcap/tidb-operator/pkg/client/informers/externalversions/internalinterfaces"
+	v1alpha1 "github.com/pingcap/tidb-operator/pkg/client/listers/pingcap/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// TidbClusterAutoScalerInformer provides access to a shared informer and lister for
+// TidbClusterAutoScalers.
+type TidbClusterAutoScalerInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1alpha1.TidbClusterAutoScalerLister
+}
+
+type tidbClusterAutoScalerInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+	namespace        string
+}
+
+// NewTidbClusterAutoScalerInformer constructs a new informer for TidbClusterAutoScaler type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewTidbClusterAutoScalerInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredTidbClusterAutoScalerInformer(client, namespace, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredTidbClusterAutoScalerInformer constructs a new informer for TidbClusterAutoScaler type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredTidbClusterAutoScalerInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options v1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.PingcapV1alpha1().TidbClusterAutoScalers(namespace).List(options)
+			},
+			WatchFunc: func(options v1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.PingcapV1alpha1().TidbClusterAutoScalers(namespace).Watch(options)
+			},
+		},
+		&pingcapv1alpha1.TidbClusterAutoScaler{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *tidbClusterAutoScalerInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredTidbClusterAutoScalerInformer(client, f.namespace, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *tidbClusterAutoScalerInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&pingcapv1alpha1.TidbClusterAutoScaler{}, f.defaultInformer)
+}
+
+func (f *tidbClusterAutoScalerInformer) Lister() v1alpha1.TidbClusterAutoScalerLister {
+	return v1alpha1.NewTidbClusterAutoScalerLister(f.Informer().GetIndexer())
+}
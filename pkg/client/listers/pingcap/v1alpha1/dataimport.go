@@ -0,0 +1,91 @@
+// Copyright 2019. PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// DataImportLister helps list DataImports.
+type DataImportLister interface {
+	// List lists all DataImports in the indexer.
+	List(selector labels.Selector) (ret []*v1alpha1.DataImport, err error)
+	// DataImports returns an object that can list and get DataImports.
+	DataImports(namespace string) DataImportNamespaceLister
+	DataImportListerExpansion
+}
+
+// dataImportLister implements the DataImportLister interface.
+type dataImportLister struct {
+	indexer cache.Indexer
+}
+
+// NewDataImportLister returns a new DataImportLister.
+func NewDataImportLister(indexer cache.Indexer) DataImportLister {
+	return &dataImportLister{indexer: indexer}
+}
+
+// List lists all DataImports in the indexer.
+func (s *dataImportLister) List(selector labels.Selector) (ret []*v1alpha1.DataImport, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.DataImport))
+	})
+	return ret, err
+}
+
+// DataImports returns an object that can list and get DataImports.
+func (s *dataImportLister) DataImports(namespace string) DataImportNamespaceLister {
+	return dataImportNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// DataImportNamespaceLister helps list and get DataImports.
+type DataImportNamespaceLister interface {
+	// List lists all DataImports in the indexer for a given namespace.
+	List(selector labels.Selector) (ret []*v1alpha1.DataImport, err error)
+	// Get retrieves the DataImport from the indexer for a given namespace and name.
+	Get(name string) (*v1alpha1.DataImport, error)
+	DataImportNamespaceListerExpansion
+}
+
+// dataImportNamespaceLister implements the DataImportNamespaceLister
+// interface.
+type dataImportNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all DataImports in the indexer for a given namespace.
+func (s dataImportNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.DataImport, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.DataImport))
+	})
+	return ret, err
+}
+
+// Get retrieves the DataImport from the indexer for a given namespace and name.
+func (s dataImportNamespaceLister) Get(name string) (*v1alpha1.DataImport, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("dataimport"), name)
+	}
+	return obj.(*v1alpha1.DataImport), nil
+}
@@ -31,6 +31,14 @@ type BackupScheduleListerExpansion interface{}
 // BackupScheduleNamespaceLister.
 type BackupScheduleNamespaceListerExpansion interface{}
 
+// DataImportListerExpansion allows custom methods to be added to
+// DataImportLister.
+type DataImportListerExpansion interface{}
+
+// DataImportNamespaceListerExpansion allows custom methods to be added to
+// DataImportNamespaceLister.
+type DataImportNamespaceListerExpansion interface{}
+
 // DataResourceListerExpansion allows custom methods to be added to
 // DataResourceLister.
 type DataResourceListerExpansion interface{}
@@ -54,3 +62,19 @@ type TidbClusterListerExpansion interface{}
 // TidbClusterNamespaceListerExpansion allows custom methods to be added to
 // TidbClusterNamespaceLister.
 type TidbClusterNamespaceListerExpansion interface{}
+
+// TidbClusterAutoScalerListerExpansion allows custom methods to be added to
+// TidbClusterAutoScalerLister.
+type TidbClusterAutoScalerListerExpansion interface{}
+
+// TidbClusterAutoScalerNamespaceListerExpansion allows custom methods to be added to
+// TidbClusterAutoScalerNamespaceLister.
+type TidbClusterAutoScalerNamespaceListerExpansion interface{}
+
+// TidbMonitorListerExpansion allows custom methods to be added to
+// TidbMonitorLister.
+type TidbMonitorListerExpansion interface{}
+
+// TidbMonitorNamespaceListerExpansion allows custom methods to be added to
+// TidbMonitorNamespaceLister.
+type TidbMonitorNamespaceListerExpansion interface{}
@@ -0,0 +1,46 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoscaler
+
+import (
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/autoscaler"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+)
+
+// ControlInterface implements the control logic for updating TidbClusterAutoScaler
+// It is implemented as an interface to allow for extensions that provide different semantics.
+// Currently, there is only one implementation.
+type ControlInterface interface {
+	// UpdateAutoScaler implements the control logic for scaling the tidb cluster
+	UpdateAutoScaler(tac *v1alpha1.TidbClusterAutoScaler) error
+}
+
+// NewDefaultAutoScalerControl returns a new instance of the default implementation ControlInterface that
+// implements the documented semantics for TidbClusterAutoScaler.
+func NewDefaultAutoScalerControl(autoScalerManager autoscaler.AutoScalerManager) ControlInterface {
+	return &defaultAutoScalerControl{
+		autoScalerManager,
+	}
+}
+
+type defaultAutoScalerControl struct {
+	autoScalerManager autoscaler.AutoScalerManager
+}
+
+// UpdateAutoScaler executes the core logic loop for a TidbClusterAutoScaler.
+func (ac *defaultAutoScalerControl) UpdateAutoScaler(tac *v1alpha1.TidbClusterAutoScaler) error {
+	tac.SetGroupVersionKind(controller.TidbClusterAutoScalerControllerKind)
+	return ac.autoScalerManager.Sync(tac)
+}
@@ -0,0 +1,185 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoscaler
+
+import (
+	"fmt"
+	"time"
+
+	perrors "github.com/pingcap/errors"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/autoscaler/autoscaler"
+	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned"
+	informers "github.com/pingcap/tidb-operator/pkg/client/informers/externalversions"
+	listers "github.com/pingcap/tidb-operator/pkg/client/listers/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	eventv1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	glog "k8s.io/klog"
+)
+
+// Controller controls tidbclusterautoscaler.
+type Controller struct {
+	// kubernetes client interface
+	kubeClient kubernetes.Interface
+	// operator client interface
+	cli versioned.Interface
+	// control returns an interface capable of syncing a tidbclusterautoscaler.
+	// Abstracted out for testing.
+	control ControlInterface
+	// tacLister is able to list/get tidbclusterautoscaler from a shared informer's store
+	tacLister listers.TidbClusterAutoScalerLister
+	// tacListerSynced returns true if the tidbclusterautoscaler shared informer has synced at least once
+	tacListerSynced cache.InformerSynced
+	// tidbclusterautoscalers that need to be synced.
+	queue workqueue.RateLimitingInterface
+}
+
+// NewController creates a tidbclusterautoscaler controller.
+func NewController(
+	kubeCli kubernetes.Interface,
+	cli versioned.Interface,
+	informerFactory informers.SharedInformerFactory,
+	kubeInformerFactory kubeinformers.SharedInformerFactory,
+) *Controller {
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartLogging(glog.Infof)
+	eventBroadcaster.StartRecordingToSink(&eventv1.EventSinkImpl{
+		Interface: eventv1.New(kubeCli.CoreV1().RESTClient()).Events("")})
+	recorder := eventBroadcaster.NewRecorder(v1alpha1.Scheme, corev1.EventSource{Component: "tidbclusterautoscaler"})
+
+	tacInformer := informerFactory.Pingcap().V1alpha1().TidbClusterAutoScalers()
+	tcInformer := informerFactory.Pingcap().V1alpha1().TidbClusters()
+	tacControl := controller.NewRealTidbClusterAutoScalerControl(cli, tacInformer.Lister(), recorder)
+
+	ac := &Controller{
+		kubeClient: kubeCli,
+		cli:        cli,
+		control: NewDefaultAutoScalerControl(
+			autoscaler.NewAutoScalerManager(
+				cli,
+				tcInformer.Lister(),
+				tacControl,
+				pdapi.NewDefaultPDControl(kubeInformerFactory.Core().V1().Secrets()),
+				recorder,
+			),
+		),
+		queue: workqueue.NewNamedRateLimitingQueue(
+			workqueue.DefaultControllerRateLimiter(),
+			"tidbclusterautoscaler",
+		),
+	}
+
+	// the metrics are evaluated on every resync of the informer
+	tacInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: ac.enqueueAutoScaler,
+		UpdateFunc: func(old, cur interface{}) {
+			ac.enqueueAutoScaler(cur)
+		},
+		DeleteFunc: ac.enqueueAutoScaler,
+	})
+	ac.tacLister = tacInformer.Lister()
+	ac.tacListerSynced = tacInformer.Informer().HasSynced
+
+	return ac
+}
+
+// Run runs the tidbclusterautoscaler controller.
+func (ac *Controller) Run(workers int, stopCh <-chan struct{}) {
+	defer utilruntime.HandleCrash()
+	defer ac.queue.ShutDown()
+
+	glog.Info("Starting tidbclusterautoscaler controller")
+	defer glog.Info("Shutting down tidbclusterautoscaler controller")
+
+	for i := 0; i < workers; i++ {
+		go wait.Until(ac.worker, time.Second, stopCh)
+	}
+
+	<-stopCh
+}
+
+// worker runs a worker goroutine that invokes processNextWorkItem until the the controller's queue is closed
+func (ac *Controller) worker() {
+	for ac.processNextWorkItem() {
+		// revive:disable:empty-block
+	}
+}
+
+// processNextWorkItem dequeues items, processes them, and marks them done. It enforces that the syncHandler is never
+// invoked concurrently with the same key.
+func (ac *Controller) processNextWorkItem() bool {
+	key, quit := ac.queue.Get()
+	if quit {
+		return false
+	}
+	defer ac.queue.Done(key)
+	if err := ac.sync(key.(string)); err != nil {
+		if perrors.Find(err, controller.IsRequeueError) != nil {
+			glog.Infof("TidbClusterAutoScaler: %v, still need sync: %v, requeuing", key.(string), err)
+		} else {
+			utilruntime.HandleError(fmt.Errorf("TidbClusterAutoScaler: %v, sync failed, err: %v, requeuing", key.(string), err))
+		}
+		ac.queue.AddRateLimited(key)
+	} else {
+		ac.queue.Forget(key)
+	}
+	return true
+}
+
+// sync syncs the given tidbclusterautoscaler.
+func (ac *Controller) sync(key string) error {
+	startTime := time.Now()
+	defer func() {
+		glog.V(4).Infof("Finished syncing TidbClusterAutoScaler %q (%v)", key, time.Since(startTime))
+	}()
+
+	ns, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+	tac, err := ac.tacLister.TidbClusterAutoScalers(ns).Get(name)
+	if errors.IsNotFound(err) {
+		glog.Infof("TidbClusterAutoScaler has been deleted %v", key)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return ac.syncAutoScaler(tac.DeepCopy())
+}
+
+func (ac *Controller) syncAutoScaler(tac *v1alpha1.TidbClusterAutoScaler) error {
+	return ac.control.UpdateAutoScaler(tac)
+}
+
+// enqueueAutoScaler enqueues the given tidbclusterautoscaler in the work queue.
+func (ac *Controller) enqueueAutoScaler(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("Cound't get key for object %+v: %v", obj, err))
+		return
+	}
+	ac.queue.Add(key)
+}
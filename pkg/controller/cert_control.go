@@ -14,17 +14,23 @@
 package controller
 
 import (
+	"crypto/x509"
 	"encoding/pem"
 	"fmt"
 	"time"
 
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/label"
 	certutil "github.com/pingcap/tidb-operator/pkg/util/crypto"
 	capi "k8s.io/api/certificates/v1beta1"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	types "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	certlisters "k8s.io/client-go/listers/certificates/v1beta1"
 	glog "k8s.io/klog"
@@ -39,18 +45,51 @@ type TiDBClusterCertOptions struct {
 	IPList     []string
 	Suffix     string
 	Component  string
+	// Issuer is the issuer of the certificate, nil means the Kubernetes CSR API
+	Issuer *v1alpha1.TLSIssuer
+	// CASecretName is the name of the Secret holding the CA of the SelfSignedCA issuer
+	CASecretName string
+}
+
+// IssuerType returns the type of the issuer of the certificate
+func (o *TiDBClusterCertOptions) IssuerType() v1alpha1.TLSIssuerType {
+	if o.Issuer == nil || o.Issuer.Type == "" {
+		return v1alpha1.TLSIssuerCSR
+	}
+	return o.Issuer.Type
+}
+
+// SecretName returns the name of the CSR and the Secret of the certificate
+func (o *TiDBClusterCertOptions) SecretName() string {
+	if o.Suffix == "" {
+		return o.Instance
+	}
+	return fmt.Sprintf("%s-%s", o.Instance, o.Suffix)
 }
 
 // CertControlInterface manages certificates used by TiDB clusters
 type CertControlInterface interface {
 	Create(or metav1.OwnerReference, certOpts *TiDBClusterCertOptions) error
 	CheckSecret(ns string, secretName string) bool
+	// GetCert returns the certificate stored in the Secret
+	GetCert(ns string, secretName string) (*x509.Certificate, error)
+	// GetCA returns the PEM encoded CA certificate which signs the certificate stored in the Secret
+	GetCA(ns string, secretName string) ([]byte, error)
 	//RevokeCert() error
-	//RenewCert() error
+	// RenewCert issues a new certificate, overwrites the existing Secret with it and returns it
+	RenewCert(or metav1.OwnerReference, certOpts *TiDBClusterCertOptions) (*x509.Certificate, error)
+}
+
+// certManagerCertificateGVR is the resource of the cert-manager Certificates
+var certManagerCertificateGVR = schema.GroupVersionResource{
+	Group:    "cert-manager.io",
+	Version:  "v1alpha2",
+	Resource: "certificates",
 }
 
 type realCertControl struct {
 	kubeCli    kubernetes.Interface
+	dynamicCli dynamic.Interface
 	csrLister  certlisters.CertificateSigningRequestLister
 	secControl SecretControlInterface
 }
@@ -58,23 +97,20 @@ type realCertControl struct {
 // NewRealCertControl creates a new CertControlInterface
 func NewRealCertControl(
 	kubeCli kubernetes.Interface,
+	dynamicCli dynamic.Interface,
 	csrLister certlisters.CertificateSigningRequestLister,
 	secControl SecretControlInterface,
 ) CertControlInterface {
 	return &realCertControl{
 		kubeCli:    kubeCli,
+		dynamicCli: dynamicCli,
 		csrLister:  csrLister,
 		secControl: secControl,
 	}
 }
 
 func (rcc *realCertControl) Create(or metav1.OwnerReference, certOpts *TiDBClusterCertOptions) error {
-	var csrName string
-	if certOpts.Suffix == "" {
-		csrName = certOpts.Instance
-	} else {
-		csrName = fmt.Sprintf("%s-%s", certOpts.Instance, certOpts.Suffix)
-	}
+	csrName := certOpts.SecretName()
 
 	// generate certificate if not exist
 	if rcc.secControl.Check(certOpts.Namespace, csrName) {
@@ -82,6 +118,165 @@ func (rcc *realCertControl) Create(or metav1.OwnerReference, certOpts *TiDBClust
 		return nil
 	}
 
+	switch certOpts.IssuerType() {
+	case v1alpha1.TLSIssuerSelfSignedCA:
+		return rcc.signCert(or, certOpts, rcc.secControl.Create)
+	case v1alpha1.TLSIssuerSecret:
+		return fmt.Errorf("secret %s/%s of the TLS certificate does not exist or is invalid, it must be provided when the TLS issuer is %s",
+			certOpts.Namespace, csrName, v1alpha1.TLSIssuerSecret)
+	case v1alpha1.TLSIssuerCertManager:
+		return rcc.requestCertificate(or, certOpts)
+	default:
+		return rcc.issueCert(or, certOpts, rcc.secControl.Create)
+	}
+}
+
+func (rcc *realCertControl) RenewCert(or metav1.OwnerReference, certOpts *TiDBClusterCertOptions) (*x509.Certificate, error) {
+	glog.Infof("renewing certificate for [%s/%s]: %s", certOpts.Namespace, certOpts.Instance, certOpts.SecretName())
+	var cert *x509.Certificate
+	saveFn := func(or metav1.OwnerReference, certOpts *TiDBClusterCertOptions, certBytes []byte, key []byte, ca []byte) error {
+		var err error
+		cert, err = certutil.ParseCert(certBytes)
+		if err != nil {
+			return err
+		}
+		return rcc.secControl.Update(or, certOpts, certBytes, key, ca)
+	}
+
+	var err error
+	switch certOpts.IssuerType() {
+	case v1alpha1.TLSIssuerCSR:
+		err = rcc.issueCert(or, certOpts, saveFn)
+	case v1alpha1.TLSIssuerSelfSignedCA:
+		err = rcc.signCert(or, certOpts, saveFn)
+	default:
+		err = fmt.Errorf("certificates issued by %s are not renewed by tidb-operator", certOpts.IssuerType())
+	}
+	return cert, err
+}
+
+// signCert generates a new key pair, signs the certificate with the CA of the SelfSignedCA issuer, and saves them by saveFn
+func (rcc *realCertControl) signCert(or metav1.OwnerReference, certOpts *TiDBClusterCertOptions,
+	saveFn func(metav1.OwnerReference, *TiDBClusterCertOptions, []byte, []byte, []byte) error) error {
+	caCert, caKey, err := rcc.loadOrCreateCA(or, certOpts)
+	if err != nil {
+		return err
+	}
+
+	cert, key, err := certutil.SignCert(caCert, caKey, certOpts.CommonName, certOpts.HostList, certOpts.IPList)
+	if err != nil {
+		return fmt.Errorf("fail to sign certificate for %s/%s, %v", certOpts.Namespace, certOpts.SecretName(), err)
+	}
+	glog.Infof("signed certificate for [%s/%s]: %s with CA %s", certOpts.Namespace, certOpts.Instance, certOpts.SecretName(), certOpts.CASecretName)
+	return saveFn(or, certOpts, cert, key, caCert)
+}
+
+// loadOrCreateCA returns the certificate and key of the CA of the SelfSignedCA issuer, the CA is generated and saved
+// in the Secret if it does not exist
+func (rcc *realCertControl) loadOrCreateCA(or metav1.OwnerReference, certOpts *TiDBClusterCertOptions) ([]byte, []byte, error) {
+	ns := certOpts.Namespace
+	secretName := certOpts.CASecretName
+
+	secret, err := rcc.kubeCli.CoreV1().Secrets(ns).Get(secretName, types.GetOptions{})
+	if err == nil {
+		cert, key := tlsSecretKeyPair(secret)
+		return cert, key, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, nil, fmt.Errorf("fail to get CA secret %s/%s, %v", ns, secretName, err)
+	}
+
+	cert, key, err := certutil.NewCA(fmt.Sprintf("TiDB %s/%s CA", ns, certOpts.Instance))
+	if err != nil {
+		return nil, nil, fmt.Errorf("fail to generate CA for %s/%s, %v", ns, certOpts.Instance, err)
+	}
+	secret = &corev1.Secret{
+		ObjectMeta: types.ObjectMeta{
+			Name:            secretName,
+			Labels:          label.New().Instance(certOpts.Instance).Component("ca").Labels(),
+			OwnerReferences: []metav1.OwnerReference{or},
+		},
+		Data: tlsSecretData(cert, key, nil),
+	}
+	if _, err := rcc.kubeCli.CoreV1().Secrets(ns).Create(secret); err != nil {
+		return nil, nil, fmt.Errorf("fail to create CA secret %s/%s, %v", ns, secretName, err)
+	}
+	glog.Infof("generated CA in secret %s/%s", ns, secretName)
+	return cert, key, nil
+}
+
+// requestCertificate creates the cert-manager Certificate of the certificate, the Secret is
+// created by cert-manager once the certificate is issued
+func (rcc *realCertControl) requestCertificate(or metav1.OwnerReference, certOpts *TiDBClusterCertOptions) error {
+	ns := certOpts.Namespace
+	name := certOpts.SecretName()
+	if certOpts.Issuer.CertManager == nil {
+		return fmt.Errorf("the cert-manager issuer of the certificate %s/%s is not specified", ns, name)
+	}
+
+	certificates := rcc.dynamicCli.Resource(certManagerCertificateGVR).Namespace(ns)
+	_, err := certificates.Get(name, types.GetOptions{})
+	if err == nil {
+		return RequeueErrorf("waiting for cert-manager to issue the certificate %s/%s", ns, name)
+	}
+	if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("fail to get cert-manager Certificate %s/%s, %v", ns, name, err)
+	}
+
+	if _, err := certificates.Create(newCertManagerCertificate(or, certOpts), types.CreateOptions{}); err != nil {
+		return fmt.Errorf("fail to create cert-manager Certificate %s/%s, %v", ns, name, err)
+	}
+	glog.Infof("cert-manager Certificate created for [%s/%s]: %s", ns, certOpts.Instance, name)
+	return RequeueErrorf("waiting for cert-manager to issue the certificate %s/%s", ns, name)
+}
+
+func newCertManagerCertificate(or metav1.OwnerReference, certOpts *TiDBClusterCertOptions) *unstructured.Unstructured {
+	issuer := certOpts.Issuer.CertManager
+	kind := issuer.Kind
+	if kind == "" {
+		kind = "Issuer"
+	}
+
+	spec := map[string]interface{}{
+		"secretName": certOpts.SecretName(),
+		"commonName": certOpts.CommonName,
+		"usages":     []interface{}{"server auth", "client auth"},
+		"issuerRef": map[string]interface{}{
+			"name":  issuer.Name,
+			"kind":  kind,
+			"group": certManagerCertificateGVR.Group,
+		},
+	}
+	if len(certOpts.HostList) > 0 {
+		spec["dnsNames"] = stringsToInterfaces(certOpts.HostList)
+	}
+	if len(certOpts.IPList) > 0 {
+		spec["ipAddresses"] = stringsToInterfaces(certOpts.IPList)
+	}
+
+	certificate := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	certificate.SetAPIVersion(certManagerCertificateGVR.GroupVersion().String())
+	certificate.SetKind("Certificate")
+	certificate.SetName(certOpts.SecretName())
+	certificate.SetNamespace(certOpts.Namespace)
+	certificate.SetLabels(label.New().Instance(certOpts.Instance).Component(certOpts.Component).Labels())
+	certificate.SetOwnerReferences([]metav1.OwnerReference{or})
+	return certificate
+}
+
+func stringsToInterfaces(strs []string) []interface{} {
+	result := make([]interface{}, 0, len(strs))
+	for _, str := range strs {
+		result = append(result, str)
+	}
+	return result
+}
+
+// issueCert generates a new key pair, gets the certificate signed through a CSR, and saves them by saveFn
+func (rcc *realCertControl) issueCert(or metav1.OwnerReference, certOpts *TiDBClusterCertOptions,
+	saveFn func(metav1.OwnerReference, *TiDBClusterCertOptions, []byte, []byte, []byte) error) error {
+	csrName := certOpts.SecretName()
+
 	rawCSR, key, err := certutil.NewCSR(certOpts.CommonName, certOpts.HostList, certOpts.IPList)
 	if err != nil {
 		return fmt.Errorf("fail to generate new key and certificate for %s/%s, %v", certOpts.Namespace, csrName, err)
@@ -136,7 +331,7 @@ func (rcc *realCertControl) Create(or metav1.OwnerReference, certOpts *TiDBClust
 				glog.Infof("signed certificate for [%s/%s]: %s", certOpts.Namespace, certOpts.Instance, csrName)
 
 				// save signed certificate and key to secret
-				err = rcc.secControl.Create(or, certOpts, updatedCSR.Status.Certificate, key)
+				err = saveFn(or, certOpts, updatedCSR.Status.Certificate, key, nil)
 				if err == nil {
 					// cleanup the approved csr
 					delOpts := &types.DeleteOptions{TypeMeta: types.TypeMeta{Kind: "CertificateSigningRequest"}}
@@ -237,30 +432,122 @@ func (rcc *realCertControl) RevokeCert() error {
 	return nil
 }
 */
-/*
-func (rcc *realCertControl) RenewCert() error {
-	return nil
-}
-*/
 
 func (rcc *realCertControl) CheckSecret(ns string, secretName string) bool {
 	return rcc.secControl.Check(ns, secretName)
 }
 
+func (rcc *realCertControl) GetCert(ns string, secretName string) (*x509.Certificate, error) {
+	certBytes, _, err := rcc.secControl.Load(ns, secretName)
+	if err != nil {
+		return nil, err
+	}
+	return certutil.ParseCert(certBytes)
+}
+
+// GetCA returns the CA in the Secret, or the Kubernetes cluster CA if the Secret does not contain it,
+// which is the case for the certificates issued by the CSR API
+func (rcc *realCertControl) GetCA(ns string, secretName string) ([]byte, error) {
+	ca, err := rcc.secControl.LoadCA(ns, secretName)
+	if err != nil {
+		return nil, err
+	}
+	if len(ca) > 0 {
+		return ca, nil
+	}
+	return certutil.ReadK8sCA()
+}
+
 var _ CertControlInterface = &realCertControl{}
 
 type FakeCertControl struct {
 	realCertControl
+	certs             map[string]*x509.Certificate
+	cas               map[string][]byte
+	created           []*TiDBClusterCertOptions
+	createCertTracker RequestTracker
+	renewCertTracker  RequestTracker
 }
 
 func NewFakeCertControl(
 	kubeCli kubernetes.Interface,
 	csrLister certlisters.CertificateSigningRequestLister,
 	secControl SecretControlInterface,
-) CertControlInterface {
-	return &realCertControl{
-		kubeCli:    kubeCli,
-		csrLister:  csrLister,
-		secControl: secControl,
+) *FakeCertControl {
+	return &FakeCertControl{
+		realCertControl: realCertControl{
+			kubeCli:    kubeCli,
+			csrLister:  csrLister,
+			secControl: secControl,
+		},
+		certs: map[string]*x509.Certificate{},
+		cas:   map[string][]byte{},
+	}
+}
+
+// SetCert sets the certificate returned by GetCert for the Secret
+func (fcc *FakeCertControl) SetCert(ns string, secretName string, cert *x509.Certificate) {
+	fcc.certs[fmt.Sprintf("%s/%s", ns, secretName)] = cert
+}
+
+// SetCA sets the CA returned by GetCA for the Secret
+func (fcc *FakeCertControl) SetCA(ns string, secretName string, ca []byte) {
+	fcc.cas[fmt.Sprintf("%s/%s", ns, secretName)] = ca
+}
+
+// SetCreateCertError sets the error attributes of createCertTracker
+func (fcc *FakeCertControl) SetCreateCertError(err error, after int) {
+	fcc.createCertTracker.SetError(err).SetAfter(after)
+}
+
+// Created returns the options of the certificates created by Create
+func (fcc *FakeCertControl) Created() []*TiDBClusterCertOptions {
+	return fcc.created
+}
+
+// Create records the options of the certificate without issuing it
+func (fcc *FakeCertControl) Create(_ metav1.OwnerReference, certOpts *TiDBClusterCertOptions) error {
+	defer fcc.createCertTracker.Inc()
+	if fcc.createCertTracker.ErrorReady() {
+		defer fcc.createCertTracker.Reset()
+		return fcc.createCertTracker.GetError()
 	}
+
+	fcc.created = append(fcc.created, certOpts)
+	return nil
 }
+
+func (fcc *FakeCertControl) GetCA(ns string, secretName string) ([]byte, error) {
+	ca, ok := fcc.cas[fmt.Sprintf("%s/%s", ns, secretName)]
+	if !ok {
+		return nil, apierrors.NewNotFound(corev1.Resource("secret"), secretName)
+	}
+	return ca, nil
+}
+
+// SetRenewCertError sets the error attributes of renewCertTracker
+func (fcc *FakeCertControl) SetRenewCertError(err error, after int) {
+	fcc.renewCertTracker.SetError(err).SetAfter(after)
+}
+
+func (fcc *FakeCertControl) GetCert(ns string, secretName string) (*x509.Certificate, error) {
+	cert, ok := fcc.certs[fmt.Sprintf("%s/%s", ns, secretName)]
+	if !ok {
+		return nil, apierrors.NewNotFound(corev1.Resource("secret"), secretName)
+	}
+	return cert, nil
+}
+
+// RenewCert returns a certificate valid for a year, the certificate returned by GetCert is not changed
+func (fcc *FakeCertControl) RenewCert(_ metav1.OwnerReference, _ *TiDBClusterCertOptions) (*x509.Certificate, error) {
+	defer fcc.renewCertTracker.Inc()
+	if fcc.renewCertTracker.ErrorReady() {
+		defer fcc.renewCertTracker.Reset()
+		return nil, fcc.renewCertTracker.GetError()
+	}
+
+	now := time.Now()
+	return &x509.Certificate{NotBefore: now, NotAfter: now.Add(365 * 24 * time.Hour)}, nil
+}
+
+var _ CertControlInterface = &FakeCertControl{}
@@ -0,0 +1,164 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"crypto/x509"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	certutil "github.com/pingcap/tidb-operator/pkg/util/crypto"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubeinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCertControlCreate(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name        string
+		issuer      *v1alpha1.TLSIssuer
+		errExpectFn func(*GomegaWithT, error)
+		expectFn    func(*GomegaWithT, *realCertControl)
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+
+		rcc := newRealCertControlForTest()
+		certOpts := newCertOptionsForTest(test.issuer)
+		err := rcc.Create(GetOwnerRef(newTidbCluster()), certOpts)
+		test.errExpectFn(g, err)
+		test.expectFn(g, rcc)
+	}
+
+	tests := []testcase{
+		{
+			name:   "self-signed CA",
+			issuer: &v1alpha1.TLSIssuer{Type: v1alpha1.TLSIssuerSelfSignedCA},
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+			expectFn: func(g *GomegaWithT, rcc *realCertControl) {
+				ca, err := rcc.kubeCli.CoreV1().Secrets(metav1.NamespaceDefault).Get("demo-ca", metav1.GetOptions{})
+				g.Expect(err).NotTo(HaveOccurred())
+				secret, err := rcc.kubeCli.CoreV1().Secrets(metav1.NamespaceDefault).Get("demo-pd", metav1.GetOptions{})
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(secret.Data[TLSSecretCAKey]).To(Equal(ca.Data[TLSSecretCertKey]))
+
+				rootCAs, err := certutil.LoadCACerts(secret.Data[TLSSecretCAKey])
+				g.Expect(err).NotTo(HaveOccurred())
+				cert, err := certutil.ParseCert(secret.Data[TLSSecretCertKey])
+				g.Expect(err).NotTo(HaveOccurred())
+				_, err = cert.Verify(x509.VerifyOptions{DNSName: "demo-pd", Roots: rootCAs})
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+		},
+		{
+			name:   "user-supplied secret does not exist",
+			issuer: &v1alpha1.TLSIssuer{Type: v1alpha1.TLSIssuerSecret},
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring("must be provided"))
+			},
+			expectFn: func(g *GomegaWithT, rcc *realCertControl) {
+				secrets, err := rcc.kubeCli.CoreV1().Secrets(metav1.NamespaceDefault).List(metav1.ListOptions{})
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(secrets.Items).To(BeEmpty())
+			},
+		},
+		{
+			name: "cert-manager",
+			issuer: &v1alpha1.TLSIssuer{
+				Type:        v1alpha1.TLSIssuerCertManager,
+				CertManager: &v1alpha1.CertManagerIssuer{Name: "ca-issuer", Kind: "ClusterIssuer"},
+			},
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(IsRequeueError(err)).To(BeTrue())
+			},
+			expectFn: func(g *GomegaWithT, rcc *realCertControl) {
+				certificate, err := rcc.dynamicCli.Resource(certManagerCertificateGVR).Namespace(metav1.NamespaceDefault).Get("demo-pd", metav1.GetOptions{})
+				g.Expect(err).NotTo(HaveOccurred())
+				spec := certificate.Object["spec"].(map[string]interface{})
+				g.Expect(spec["secretName"]).To(Equal("demo-pd"))
+				g.Expect(spec["dnsNames"]).To(Equal([]interface{}{"demo-pd", "demo-pd.default"}))
+				g.Expect(spec["issuerRef"]).To(HaveKeyWithValue("kind", "ClusterIssuer"))
+			},
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
+
+func TestCertControlRenewCertWithSelfSignedCA(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	rcc := newRealCertControlForTest()
+	certOpts := newCertOptionsForTest(&v1alpha1.TLSIssuer{Type: v1alpha1.TLSIssuerSelfSignedCA, CASecretName: "my-ca"})
+	or := GetOwnerRef(newTidbCluster())
+	g.Expect(rcc.Create(or, certOpts)).To(Succeed())
+	old, err := rcc.kubeCli.CoreV1().Secrets(metav1.NamespaceDefault).Get("demo-pd", metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	cert, err := rcc.RenewCert(or, certOpts)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cert.Subject.CommonName).To(Equal("demo-pd"))
+
+	renewed, err := rcc.kubeCli.CoreV1().Secrets(metav1.NamespaceDefault).Get("demo-pd", metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(renewed.Data[TLSSecretCertKey]).NotTo(Equal(old.Data[TLSSecretCertKey]))
+	g.Expect(renewed.Data[TLSSecretCAKey]).To(Equal(old.Data[TLSSecretCAKey]))
+}
+
+func TestCertControlRenewCertWithExternalIssuer(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	rcc := newRealCertControlForTest()
+	certOpts := newCertOptionsForTest(&v1alpha1.TLSIssuer{Type: v1alpha1.TLSIssuerSecret})
+	_, err := rcc.RenewCert(GetOwnerRef(newTidbCluster()), certOpts)
+	g.Expect(err).To(HaveOccurred())
+}
+
+func newRealCertControlForTest() *realCertControl {
+	kubeCli := kubefake.NewSimpleClientset()
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeCli, 0)
+	csrInformer := kubeInformerFactory.Certificates().V1beta1().CertificateSigningRequests()
+	secretInformer := kubeInformerFactory.Core().V1().Secrets()
+	secControl := NewRealSecretControl(kubeCli, secretInformer.Lister())
+	return NewRealCertControl(kubeCli, dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()), csrInformer.Lister(), secControl).(*realCertControl)
+}
+
+func newCertOptionsForTest(issuer *v1alpha1.TLSIssuer) *TiDBClusterCertOptions {
+	caSecretName := "demo-ca"
+	if issuer != nil && issuer.CASecretName != "" {
+		caSecretName = issuer.CASecretName
+	}
+	return &TiDBClusterCertOptions{
+		Namespace:    metav1.NamespaceDefault,
+		Instance:     "demo",
+		CommonName:   "demo-pd",
+		HostList:     []string{"demo-pd", "demo-pd.default"},
+		Component:    "pd",
+		Suffix:       "pd",
+		Issuer:       issuer,
+		CASecretName: caSecretName,
+	}
+}
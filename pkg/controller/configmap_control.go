@@ -175,8 +175,14 @@ func (cc *FakeConfigMapControl) UpdateConfigMap(_ *v1alpha1.TidbCluster, cm *cor
 }
 
 // DeleteConfigMap deletes the ConfigMap of CmIndexer
-func (cc *FakeConfigMapControl) DeleteConfigMap(_ *v1alpha1.TidbCluster, _ *corev1.ConfigMap) error {
-	return nil
+func (cc *FakeConfigMapControl) DeleteConfigMap(_ *v1alpha1.TidbCluster, cm *corev1.ConfigMap) error {
+	defer cc.deleteConfigMapTracker.Inc()
+	if cc.deleteConfigMapTracker.ErrorReady() {
+		defer cc.deleteConfigMapTracker.Reset()
+		return cc.deleteConfigMapTracker.GetError()
+	}
+
+	return cc.CmIndexer.Delete(cm)
 }
 
 var _ ConfigMapControlInterface = &FakeConfigMapControl{}
@@ -35,6 +35,15 @@ var (
 	// RestoreControllerKind contains the schema.GroupVersionKind for restore controller type.
 	RestoreControllerKind = v1alpha1.SchemeGroupVersion.WithKind("Restore")
 
+	// DataImportControllerKind contains the schema.GroupVersionKind for data import controller type.
+	DataImportControllerKind = v1alpha1.SchemeGroupVersion.WithKind("DataImport")
+
+	// TidbMonitorControllerKind contains the schema.GroupVersionKind for tidb monitor controller type.
+	TidbMonitorControllerKind = v1alpha1.SchemeGroupVersion.WithKind("TidbMonitor")
+
+	// TidbClusterAutoScalerControllerKind contains the schema.GroupVersionKind for tidb cluster auto-scaler controller type.
+	TidbClusterAutoScalerControllerKind = v1alpha1.SchemeGroupVersion.WithKind("TidbClusterAutoScaler")
+
 	// backupScheduleControllerKind contains the schema.GroupVersionKind for backupschedule controller type.
 	backupScheduleControllerKind = v1alpha1.SchemeGroupVersion.WithKind("BackupSchedule")
 
@@ -143,6 +152,34 @@ func GetRestoreOwnerRef(restore *v1alpha1.Restore) metav1.OwnerReference {
 	}
 }
 
+// GetDataImportOwnerRef returns DataImport's OwnerReference
+func GetDataImportOwnerRef(di *v1alpha1.DataImport) metav1.OwnerReference {
+	controller := true
+	blockOwnerDeletion := true
+	return metav1.OwnerReference{
+		APIVersion:         DataImportControllerKind.GroupVersion().String(),
+		Kind:               DataImportControllerKind.Kind,
+		Name:               di.GetName(),
+		UID:                di.GetUID(),
+		Controller:         &controller,
+		BlockOwnerDeletion: &blockOwnerDeletion,
+	}
+}
+
+// GetTidbMonitorOwnerRef returns TidbMonitor's OwnerReference
+func GetTidbMonitorOwnerRef(tm *v1alpha1.TidbMonitor) metav1.OwnerReference {
+	controller := true
+	blockOwnerDeletion := true
+	return metav1.OwnerReference{
+		APIVersion:         TidbMonitorControllerKind.GroupVersion().String(),
+		Kind:               TidbMonitorControllerKind.Kind,
+		Name:               tm.GetName(),
+		UID:                tm.GetUID(),
+		Controller:         &controller,
+		BlockOwnerDeletion: &blockOwnerDeletion,
+	}
+}
+
 // GetBackupScheduleOwnerRef returns BackupSchedule's OwnerReference
 func GetBackupScheduleOwnerRef(bs *v1alpha1.BackupSchedule) metav1.OwnerReference {
 	controller := true
@@ -216,21 +253,46 @@ func TiKVMemberName(clusterName string) string {
 	return fmt.Sprintf("%s-tikv", clusterName)
 }
 
+// TiKVGroupMemberName returns the member name of a tikv group
+func TiKVGroupMemberName(clusterName, group string) string {
+	return fmt.Sprintf("%s-tikv-%s", clusterName, group)
+}
+
 // TiKVPeerMemberName returns tikv peer service name
 func TiKVPeerMemberName(clusterName string) string {
 	return fmt.Sprintf("%s-tikv-peer", clusterName)
 }
 
+// TiFlashMemberName returns tiflash member name
+func TiFlashMemberName(clusterName string) string {
+	return fmt.Sprintf("%s-tiflash", clusterName)
+}
+
+// TiFlashPeerMemberName returns tiflash peer service name
+func TiFlashPeerMemberName(clusterName string) string {
+	return fmt.Sprintf("%s-tiflash-peer", clusterName)
+}
+
 // TiDBMemberName returns tidb member name
 func TiDBMemberName(clusterName string) string {
 	return fmt.Sprintf("%s-tidb", clusterName)
 }
 
+// TiDBGroupMemberName returns the member name of a tidb group
+func TiDBGroupMemberName(clusterName, group string) string {
+	return fmt.Sprintf("%s-tidb-%s", clusterName, group)
+}
+
 // TiDBPeerMemberName returns tidb peer service name
 func TiDBPeerMemberName(clusterName string) string {
 	return fmt.Sprintf("%s-tidb-peer", clusterName)
 }
 
+// TiDBClientCAConfigMapName returns the name of the ConfigMap publishing the CA of the TiDB server certificate
+func TiDBClientCAConfigMapName(clusterName string) string {
+	return fmt.Sprintf("%s-tidb-client-ca", clusterName)
+}
+
 // PumpMemberName returns pump member name
 func PumpMemberName(clusterName string) string {
 	return fmt.Sprintf("%s-pump", clusterName)
@@ -242,6 +304,17 @@ func PumpPeerMemberName(clusterName string) string {
 	return fmt.Sprintf("%s-pump", clusterName)
 }
 
+// DrainerMemberName returns the name of the StatefulSet and the headless Service of the drainer,
+// which are the names used by the tidb-drainer chart
+func DrainerMemberName(clusterName, drainerName string) string {
+	return fmt.Sprintf("%s-%s-drainer", clusterName, drainerName)
+}
+
+// TidbMonitorMemberName returns the name of the Deployment, ConfigMap, Secret and PVC of the TidbMonitor
+func TidbMonitorMemberName(monitorName string) string {
+	return fmt.Sprintf("%s-monitor", monitorName)
+}
+
 // AnnProm adds annotations for prometheus scraping metrics
 func AnnProm(port int32) map[string]string {
 	return map[string]string{
@@ -273,6 +346,18 @@ func MemberConfigMapName(tc *v1alpha1.TidbCluster, member v1alpha1.MemberType) s
 	return nameKey + getConfigMapSuffix(tc, member.String(), nameKey)
 }
 
+// TiKVGroupConfigMapName returns the ConfigMap name of a tikv group
+func TiKVGroupConfigMapName(tc *v1alpha1.TidbCluster, group string) string {
+	nameKey := TiKVGroupMemberName(tc.Name, group)
+	return nameKey + getConfigMapSuffix(tc, v1alpha1.TiKVMemberType.String(), nameKey)
+}
+
+// TiDBGroupConfigMapName returns the ConfigMap name of a tidb group
+func TiDBGroupConfigMapName(tc *v1alpha1.TidbCluster, group string) string {
+	nameKey := TiDBGroupMemberName(tc.Name, group)
+	return nameKey + getConfigMapSuffix(tc, v1alpha1.TiDBMemberType.String(), nameKey)
+}
+
 // getConfigMapSuffix return the ConfigMap name suffix
 func getConfigMapSuffix(tc *v1alpha1.TidbCluster, component string, name string) string {
 	if tc.Annotations == nil {
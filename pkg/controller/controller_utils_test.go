@@ -174,16 +174,58 @@ func TestTiKVMemberName(t *testing.T) {
 	g.Expect(TiKVMemberName("demo")).To(Equal("demo-tikv"))
 }
 
+func TestTiKVGroupMemberName(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(TiKVGroupMemberName("demo", "hot")).To(Equal("demo-tikv-hot"))
+}
+
+func TestTiKVGroupConfigMapName(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := &v1alpha1.TidbCluster{}
+	tc.Name = "demo"
+	g.Expect(TiKVGroupConfigMapName(tc, "hot")).To(Equal("demo-tikv-hot"))
+
+	tc.Annotations = map[string]string{"pingcap.com/tikv.demo-tikv-hot.sha": "uuuuuuuu"}
+	g.Expect(TiKVGroupConfigMapName(tc, "hot")).To(Equal("demo-tikv-hot-uuuuuuuu"))
+}
+
 func TestTiKVPeerMemberName(t *testing.T) {
 	g := NewGomegaWithT(t)
 	g.Expect(TiKVPeerMemberName("demo")).To(Equal("demo-tikv-peer"))
 }
 
+func TestTiFlashMemberName(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(TiFlashMemberName("demo")).To(Equal("demo-tiflash"))
+}
+
+func TestTiFlashPeerMemberName(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(TiFlashPeerMemberName("demo")).To(Equal("demo-tiflash-peer"))
+}
+
 func TestTiDBMemberName(t *testing.T) {
 	g := NewGomegaWithT(t)
 	g.Expect(TiDBMemberName("demo")).To(Equal("demo-tidb"))
 }
 
+func TestTiDBGroupMemberName(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(TiDBGroupMemberName("demo", "olap")).To(Equal("demo-tidb-olap"))
+}
+
+func TestTiDBGroupConfigMapName(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := &v1alpha1.TidbCluster{}
+	tc.Name = "demo"
+	g.Expect(TiDBGroupConfigMapName(tc, "olap")).To(Equal("demo-tidb-olap"))
+
+	tc.Annotations = map[string]string{"pingcap.com/tidb.demo-tidb-olap.sha": "uuuuuuuu"}
+	g.Expect(TiDBGroupConfigMapName(tc, "olap")).To(Equal("demo-tidb-olap-uuuuuuuu"))
+}
+
 func TestTiDBPeerMemberName(t *testing.T) {
 	g := NewGomegaWithT(t)
 	g.Expect(TiDBPeerMemberName("demo")).To(Equal("demo-tidb-peer"))
@@ -199,6 +241,11 @@ func TestPumpPeerMemberName(t *testing.T) {
 	g.Expect(PumpPeerMemberName("demo")).To(Equal("demo-pump"))
 }
 
+func TestDrainerMemberName(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(DrainerMemberName("demo", "kafka")).To(Equal("demo-kafka-drainer"))
+}
+
 func TestAnnProm(t *testing.T) {
 	g := NewGomegaWithT(t)
 
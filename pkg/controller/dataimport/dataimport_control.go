@@ -0,0 +1,82 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataimport
+
+import (
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/backup"
+	informers "github.com/pingcap/tidb-operator/pkg/client/informers/externalversions/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ControlInterface implements the control logic for updating DataImport
+// It is implemented as an interface to allow for extensions that provide different semantics.
+// Currently, there is only one implementation.
+type ControlInterface interface {
+	// UpdateDataImport implements the control logic for data import job creation, update, and deletion
+	UpdateDataImport(di *v1alpha1.DataImport) error
+}
+
+// NewDefaultDataImportControl returns a new instance of the default implementation ControlInterface that
+// implements the documented semantics for DataImport.
+func NewDefaultDataImportControl(dataImportManager backup.DataImportManager) ControlInterface {
+	return &defaultDataImportControl{
+		dataImportManager,
+	}
+}
+
+type defaultDataImportControl struct {
+	dataImportManager backup.DataImportManager
+}
+
+// UpdateDataImport executes the core logic loop for a DataImport.
+func (dc *defaultDataImportControl) UpdateDataImport(di *v1alpha1.DataImport) error {
+	di.SetGroupVersionKind(controller.DataImportControllerKind)
+	return dc.dataImportManager.Sync(di)
+}
+
+var _ ControlInterface = &defaultDataImportControl{}
+
+// FakeDataImportControl is a fake ControlInterface
+type FakeDataImportControl struct {
+	diIndexer               cache.Indexer
+	updateDataImportTracker controller.RequestTracker
+}
+
+// NewFakeDataImportControl returns a FakeDataImportControl
+func NewFakeDataImportControl(diInformer informers.DataImportInformer) *FakeDataImportControl {
+	return &FakeDataImportControl{
+		diInformer.Informer().GetIndexer(),
+		controller.RequestTracker{},
+	}
+}
+
+// SetUpdateDataImportError sets the error attributes of updateDataImportTracker
+func (fdc *FakeDataImportControl) SetUpdateDataImportError(err error, after int) {
+	fdc.updateDataImportTracker.SetError(err).SetAfter(after)
+}
+
+// UpdateDataImport adds the data import to diIndexer
+func (fdc *FakeDataImportControl) UpdateDataImport(di *v1alpha1.DataImport) error {
+	defer fdc.updateDataImportTracker.Inc()
+	if fdc.updateDataImportTracker.ErrorReady() {
+		defer fdc.updateDataImportTracker.Reset()
+		return fdc.updateDataImportTracker.GetError()
+	}
+
+	return fdc.diIndexer.Add(di)
+}
+
+var _ ControlInterface = &FakeDataImportControl{}
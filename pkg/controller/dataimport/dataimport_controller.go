@@ -0,0 +1,220 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataimport
+
+import (
+	"fmt"
+	"time"
+
+	perrors "github.com/pingcap/errors"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/backup/dataimport"
+	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned"
+	informers "github.com/pingcap/tidb-operator/pkg/client/informers/externalversions"
+	listers "github.com/pingcap/tidb-operator/pkg/client/listers/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	eventv1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	glog "k8s.io/klog"
+)
+
+// Controller controls data import.
+type Controller struct {
+	// kubernetes client interface
+	kubeClient kubernetes.Interface
+	// operator client interface
+	cli versioned.Interface
+	// control returns an interface capable of syncing a data import.
+	// Abstracted out for testing.
+	control ControlInterface
+	// diLister is able to list/get data import from a shared informer's store
+	diLister listers.DataImportLister
+	// diListerSynced returns true if the data import shared informer has synced at least once
+	diListerSynced cache.InformerSynced
+	// data imports that need to be synced.
+	queue workqueue.RateLimitingInterface
+}
+
+// NewController creates a data import controller.
+func NewController(
+	kubeCli kubernetes.Interface,
+	cli versioned.Interface,
+	informerFactory informers.SharedInformerFactory,
+	kubeInformerFactory kubeinformers.SharedInformerFactory,
+) *Controller {
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartLogging(glog.Infof)
+	eventBroadcaster.StartRecordingToSink(&eventv1.EventSinkImpl{
+		Interface: eventv1.New(kubeCli.CoreV1().RESTClient()).Events("")})
+	recorder := eventBroadcaster.NewRecorder(v1alpha1.Scheme, corev1.EventSource{Component: "data-import"})
+
+	diInformer := informerFactory.Pingcap().V1alpha1().DataImports()
+	tcInformer := informerFactory.Pingcap().V1alpha1().TidbClusters()
+	jobInformer := kubeInformerFactory.Batch().V1().Jobs()
+	pvcInformer := kubeInformerFactory.Core().V1().PersistentVolumeClaims()
+	secretInformer := kubeInformerFactory.Core().V1().Secrets()
+	statusUpdater := controller.NewRealDataImportStatusUpdater(cli, diInformer.Lister(), recorder)
+	jobControl := controller.NewRealJobControl(kubeCli, recorder)
+	pvcControl := controller.NewRealGeneralPVCControl(kubeCli, recorder)
+	tikvControl := controller.NewDefaultTiKVControl(secretInformer, pdapi.NewDefaultPDControl(secretInformer))
+
+	dic := &Controller{
+		kubeClient: kubeCli,
+		cli:        cli,
+		control: NewDefaultDataImportControl(
+			dataimport.NewDataImportManager(
+				tcInformer.Lister(),
+				statusUpdater,
+				secretInformer.Lister(),
+				jobInformer.Lister(),
+				jobControl,
+				pvcInformer.Lister(),
+				pvcControl,
+				tikvControl,
+			),
+		),
+		queue: workqueue.NewNamedRateLimitingQueue(
+			workqueue.DefaultControllerRateLimiter(),
+			"data-import",
+		),
+	}
+
+	diInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: dic.updateDataImport,
+		UpdateFunc: func(old, cur interface{}) {
+			dic.updateDataImport(cur)
+		},
+		DeleteFunc: dic.enqueueDataImport,
+	})
+	dic.diLister = diInformer.Lister()
+	dic.diListerSynced = diInformer.Informer().HasSynced
+
+	return dic
+}
+
+// Run runs the data import controller.
+func (dic *Controller) Run(workers int, stopCh <-chan struct{}) {
+	defer utilruntime.HandleCrash()
+	defer dic.queue.ShutDown()
+
+	glog.Info("Starting data import controller")
+	defer glog.Info("Shutting down data import controller")
+
+	for i := 0; i < workers; i++ {
+		go wait.Until(dic.worker, time.Second, stopCh)
+	}
+
+	<-stopCh
+}
+
+// worker runs a worker goroutine that invokes processNextWorkItem until the the controller's queue is closed
+func (dic *Controller) worker() {
+	for dic.processNextWorkItem() {
+		// revive:disable:empty-block
+	}
+}
+
+// processNextWorkItem dequeues items, processes them, and marks them done. It enforces that the syncHandler is never
+// invoked concurrently with the same key.
+func (dic *Controller) processNextWorkItem() bool {
+	key, quit := dic.queue.Get()
+	if quit {
+		return false
+	}
+	defer dic.queue.Done(key)
+	if err := dic.sync(key.(string)); err != nil {
+		if perrors.Find(err, controller.IsRequeueError) != nil {
+			glog.Infof("DataImport: %v, still need sync: %v, requeuing", key.(string), err)
+		} else {
+			utilruntime.HandleError(fmt.Errorf("DataImport: %v, sync failed, err: %v, requeuing", key.(string), err))
+		}
+		dic.queue.AddRateLimited(key)
+	} else {
+		dic.queue.Forget(key)
+	}
+	return true
+}
+
+// sync syncs the given data import.
+func (dic *Controller) sync(key string) error {
+	startTime := time.Now()
+	defer func() {
+		glog.V(4).Infof("Finished syncing DataImport %q (%v)", key, time.Since(startTime))
+	}()
+
+	ns, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+	di, err := dic.diLister.DataImports(ns).Get(name)
+	if errors.IsNotFound(err) {
+		glog.Infof("DataImport has been deleted %v", key)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return dic.syncDataImport(di.DeepCopy())
+}
+
+func (dic *Controller) syncDataImport(di *v1alpha1.DataImport) error {
+	return dic.control.UpdateDataImport(di)
+}
+
+func (dic *Controller) updateDataImport(cur interface{}) {
+	newDI := cur.(*v1alpha1.DataImport)
+	ns := newDI.GetNamespace()
+	name := newDI.GetName()
+
+	finished := v1alpha1.IsDataImportComplete(newDI) || v1alpha1.IsDataImportFailed(newDI)
+	if finished && v1alpha1.IsTiKVInImportMode(newDI) {
+		// the TiKV stores must be switched back to normal mode
+		glog.V(4).Infof("data import %s/%s is finished with TiKV in import mode, enqueue", ns, name)
+		dic.enqueueDataImport(newDI)
+		return
+	}
+
+	if finished {
+		glog.V(4).Infof("data import %s/%s is finished, skipping.", ns, name)
+		return
+	}
+
+	if v1alpha1.IsDataImportScheduled(newDI) {
+		glog.V(4).Infof("data import %s/%s is already scheduled, skipping", ns, name)
+		return
+	}
+
+	glog.V(4).Infof("data import object %s/%s enqueue", ns, name)
+	dic.enqueueDataImport(newDI)
+}
+
+// enqueueDataImport enqueues the given data import in the work queue.
+func (dic *Controller) enqueueDataImport(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("Cound't get key for object %+v: %v", obj, err))
+		return
+	}
+	dic.queue.Add(key)
+}
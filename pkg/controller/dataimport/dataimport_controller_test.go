@@ -0,0 +1,218 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataimport
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned/fake"
+	informers "github.com/pingcap/tidb-operator/pkg/client/informers/externalversions"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kubeinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestDataImportControllerEnqueueDataImport(t *testing.T) {
+	g := NewGomegaWithT(t)
+	dic, _, _ := newFakeDataImportController()
+	dic.enqueueDataImport(newDataImport())
+	g.Expect(dic.queue.Len()).To(Equal(1))
+}
+
+func TestDataImportControllerEnqueueDataImportFailed(t *testing.T) {
+	g := NewGomegaWithT(t)
+	dic, _, _ := newFakeDataImportController()
+	dic.enqueueDataImport(struct{}{})
+	g.Expect(dic.queue.Len()).To(Equal(0))
+}
+
+func TestDataImportControllerUpdateDataImport(t *testing.T) {
+	g := NewGomegaWithT(t)
+	type testcase struct {
+		name        string
+		conditions  []v1alpha1.DataImportConditionType
+		expectQueue int
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+
+		di := newDataImport()
+		dic, _, _ := newFakeDataImportController()
+		for _, conditionType := range test.conditions {
+			v1alpha1.UpdateDataImportCondition(&di.Status, &v1alpha1.DataImportCondition{
+				Type:   conditionType,
+				Status: corev1.ConditionTrue,
+			})
+		}
+
+		dic.updateDataImport(di)
+		g.Expect(dic.queue.Len()).To(Equal(test.expectQueue))
+	}
+
+	tests := []testcase{
+		{
+			name:        "data import is newly created",
+			expectQueue: 1,
+		},
+		{
+			name:        "data import has been scheduled",
+			conditions:  []v1alpha1.DataImportConditionType{v1alpha1.DataImportScheduled},
+			expectQueue: 0,
+		},
+		{
+			name:        "data import has been completed",
+			conditions:  []v1alpha1.DataImportConditionType{v1alpha1.DataImportScheduled, v1alpha1.DataImportComplete},
+			expectQueue: 0,
+		},
+		{
+			name:        "data import has failed",
+			conditions:  []v1alpha1.DataImportConditionType{v1alpha1.DataImportScheduled, v1alpha1.DataImportFailed},
+			expectQueue: 0,
+		},
+		{
+			name:        "data import has been completed with TiKV in import mode",
+			conditions:  []v1alpha1.DataImportConditionType{v1alpha1.DataImportTiKVImportMode, v1alpha1.DataImportScheduled, v1alpha1.DataImportComplete},
+			expectQueue: 1,
+		},
+		{
+			name:        "data import has failed with TiKV in import mode",
+			conditions:  []v1alpha1.DataImportConditionType{v1alpha1.DataImportTiKVImportMode, v1alpha1.DataImportScheduled, v1alpha1.DataImportFailed},
+			expectQueue: 1,
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
+
+func TestDataImportControllerSync(t *testing.T) {
+	g := NewGomegaWithT(t)
+	type testcase struct {
+		name                    string
+		addDataImportToIndexer  bool
+		errWhenUpdateDataImport bool
+		invalidKeyFn            func(di *v1alpha1.DataImport) string
+		errExpectFn             func(*GomegaWithT, error)
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+
+		di := newDataImport()
+		dic, diIndexer, diControl := newFakeDataImportController()
+
+		if test.addDataImportToIndexer {
+			err := diIndexer.Add(di)
+			g.Expect(err).NotTo(HaveOccurred())
+		}
+
+		key, _ := cache.DeletionHandlingMetaNamespaceKeyFunc(di)
+		if test.invalidKeyFn != nil {
+			key = test.invalidKeyFn(di)
+		}
+
+		if test.errWhenUpdateDataImport {
+			diControl.SetUpdateDataImportError(fmt.Errorf("update data import failed"), 0)
+		}
+
+		err := dic.sync(key)
+		test.errExpectFn(g, err)
+	}
+
+	tests := []testcase{
+		{
+			name:                   "normal",
+			addDataImportToIndexer: true,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+		},
+		{
+			name:                   "invalid data import key",
+			addDataImportToIndexer: true,
+			invalidKeyFn: func(di *v1alpha1.DataImport) string {
+				return fmt.Sprintf("test/demo/%s", di.GetName())
+			},
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+			},
+		},
+		{
+			name:                   "can't found data import",
+			addDataImportToIndexer: false,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+		},
+		{
+			name:                    "update data import failed",
+			addDataImportToIndexer:  true,
+			errWhenUpdateDataImport: true,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(strings.Contains(err.Error(), "update data import failed")).To(Equal(true))
+			},
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
+
+func newFakeDataImportController() (*Controller, cache.Indexer, *FakeDataImportControl) {
+	cli := fake.NewSimpleClientset()
+	kubeCli := kubefake.NewSimpleClientset()
+	informerFactory := informers.NewSharedInformerFactory(cli, 0)
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeCli, 0)
+
+	diInformer := informerFactory.Pingcap().V1alpha1().DataImports()
+	diControl := NewFakeDataImportControl(diInformer)
+
+	dic := NewController(kubeCli, cli, informerFactory, kubeInformerFactory)
+	dic.control = diControl
+
+	return dic, diInformer.Informer().GetIndexer(), diControl
+}
+
+func newDataImport() *v1alpha1.DataImport {
+	return &v1alpha1.DataImport{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "DataImport",
+			APIVersion: "pingcap.com/v1alpha1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-import",
+			Namespace: corev1.NamespaceDefault,
+			UID:       types.UID("test-di"),
+		},
+		Spec: v1alpha1.DataImportSpec{
+			Cluster:        "demo1",
+			TidbSecretName: "demo1-tidb-secret",
+			StorageType:    v1alpha1.BackupStorageTypeLocal,
+			StorageProvider: v1alpha1.StorageProvider{
+				Local: &v1alpha1.LocalStorageProvider{ClaimName: "demo1-backup"},
+			},
+		},
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned"
+	informers "github.com/pingcap/tidb-operator/pkg/client/informers/externalversions/pingcap/v1alpha1"
+	listers "github.com/pingcap/tidb-operator/pkg/client/listers/pingcap/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	glog "k8s.io/klog"
+)
+
+// DataImportStatusUpdaterInterface enables updating DataImport status.
+type DataImportStatusUpdaterInterface interface {
+	// Update updates the condition of the DataImport if condition is not nil, the other fields of the
+	// status, e.g. the progress and the checksums, are updated too if they are changed.
+	Update(di *v1alpha1.DataImport, condition *v1alpha1.DataImportCondition) error
+}
+
+type realDataImportStatusUpdater struct {
+	cli      versioned.Interface
+	diLister listers.DataImportLister
+	recorder record.EventRecorder
+}
+
+// NewRealDataImportStatusUpdater returns a DataImportStatusUpdaterInterface that updates the Status of a DataImport
+func NewRealDataImportStatusUpdater(
+	cli versioned.Interface,
+	diLister listers.DataImportLister,
+	recorder record.EventRecorder) DataImportStatusUpdaterInterface {
+	return &realDataImportStatusUpdater{
+		cli,
+		diLister,
+		recorder,
+	}
+}
+
+func (dsu *realDataImportStatusUpdater) Update(di *v1alpha1.DataImport, condition *v1alpha1.DataImportCondition) error {
+	ns := di.GetNamespace()
+	diName := di.GetName()
+	status := di.Status.DeepCopy()
+	var isUpdate, conditionUpdated bool
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		isUpdate = false
+		if condition != nil {
+			conditionUpdated = v1alpha1.UpdateDataImportCondition(&di.Status, condition)
+			isUpdate = conditionUpdated
+		}
+		if old, err := dsu.diLister.DataImports(ns).Get(diName); err == nil && !apiequality.Semantic.DeepEqual(old.Status, di.Status) {
+			isUpdate = true
+		}
+		if isUpdate {
+			_, updateErr := dsu.cli.PingcapV1alpha1().DataImports(ns).Update(di)
+			if updateErr == nil {
+				glog.Infof("DataImport: [%s/%s] updated successfully", ns, diName)
+				return nil
+			}
+			if updated, err := dsu.diLister.DataImports(ns).Get(diName); err == nil {
+				// make a copy so we don't mutate the shared cache
+				di = updated.DeepCopy()
+				di.Status = *status.DeepCopy()
+			} else {
+				utilruntime.HandleError(fmt.Errorf("error getting updated data import %s/%s from lister: %v", ns, diName, err))
+			}
+			return updateErr
+		}
+		return nil
+	})
+	if conditionUpdated {
+		dsu.recordDataImportEvent("update", di, err)
+	}
+	return err
+}
+
+func (dsu *realDataImportStatusUpdater) recordDataImportEvent(verb string, di *v1alpha1.DataImport, err error) {
+	diName := di.GetName()
+	if err == nil {
+		reason := fmt.Sprintf("Successful%s", strings.Title(verb))
+		msg := fmt.Sprintf("%s DataImport %s successful",
+			strings.ToLower(verb), diName)
+		dsu.recorder.Event(di, corev1.EventTypeNormal, reason, msg)
+	} else {
+		reason := fmt.Sprintf("Failed%s", strings.Title(verb))
+		msg := fmt.Sprintf("%s DataImport %s failed error: %s",
+			strings.ToLower(verb), diName, err)
+		dsu.recorder.Event(di, corev1.EventTypeWarning, reason, msg)
+	}
+}
+
+var _ DataImportStatusUpdaterInterface = &realDataImportStatusUpdater{}
+
+// FakeDataImportStatusUpdater is a fake DataImportStatusUpdaterInterface
+type FakeDataImportStatusUpdater struct {
+	DataImportLister        listers.DataImportLister
+	DataImportIndexer       cache.Indexer
+	updateDataImportTracker RequestTracker
+}
+
+// NewFakeDataImportStatusUpdater returns a FakeDataImportStatusUpdater
+func NewFakeDataImportStatusUpdater(diInformer informers.DataImportInformer) *FakeDataImportStatusUpdater {
+	return &FakeDataImportStatusUpdater{
+		diInformer.Lister(),
+		diInformer.Informer().GetIndexer(),
+		RequestTracker{},
+	}
+}
+
+// SetUpdateDataImportError sets the error attributes of updateDataImportTracker
+func (fdu *FakeDataImportStatusUpdater) SetUpdateDataImportError(err error, after int) {
+	fdu.updateDataImportTracker.SetError(err).SetAfter(after)
+}
+
+// Update updates the DataImport
+func (fdu *FakeDataImportStatusUpdater) Update(di *v1alpha1.DataImport, condition *v1alpha1.DataImportCondition) error {
+	defer fdu.updateDataImportTracker.Inc()
+	if fdu.updateDataImportTracker.ErrorReady() {
+		defer fdu.updateDataImportTracker.Reset()
+		return fdu.updateDataImportTracker.GetError()
+	}
+
+	if condition != nil {
+		v1alpha1.UpdateDataImportCondition(&di.Status, condition)
+	}
+	return fdu.DataImportIndexer.Update(di)
+}
+
+var _ DataImportStatusUpdaterInterface = &FakeDataImportStatusUpdater{}
@@ -0,0 +1,98 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+)
+
+// drainerStatus is the status returned by the status API of drainer
+// https://github.com/pingcap/tidb-binlog/blob/master/drainer/server.go
+type drainerStatus struct {
+	PumpPos map[string]int64 `json:"PumpPos"`
+	Synced  bool             `json:"Synced"`
+	LastTS  int64            `json:"LastTS"`
+}
+
+// DrainerControlInterface is the interface that knows how to query the drainers
+type DrainerControlInterface interface {
+	// GetStatus returns the replication status of the drainer with the name
+	GetStatus(tc *v1alpha1.TidbCluster, name string) (*drainerStatus, error)
+}
+
+// defaultDrainerControl is default implementation of DrainerControlInterface.
+type defaultDrainerControl struct {
+	httpClients *clusterHTTPClients
+}
+
+// NewDefaultDrainerControl returns a defaultDrainerControl instance
+func NewDefaultDrainerControl(secretInformer coreinformers.SecretInformer) DrainerControlInterface {
+	return &defaultDrainerControl{httpClients: newClusterHTTPClients(secretInformer)}
+}
+
+func (ddc *defaultDrainerControl) GetStatus(tc *v1alpha1.TidbCluster, name string) (*drainerStatus, error) {
+	httpClient, err := ddc.httpClients.get(tc)
+	if err != nil {
+		return nil, err
+	}
+
+	setName := DrainerMemberName(tc.GetName(), name)
+	url := fmt.Sprintf("%s://%s-0.%s.%s:8249/status", tc.Scheme(), setName, setName, tc.GetNamespace())
+	body, err := getBodyOK(httpClient, url)
+	if err != nil {
+		return nil, err
+	}
+	status := drainerStatus{}
+	err = json.Unmarshal(body, &status)
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// FakeDrainerControl is a fake implementation of DrainerControlInterface.
+type FakeDrainerControl struct {
+	status map[string]*drainerStatus
+	err    error
+}
+
+// NewFakeDrainerControl returns a FakeDrainerControl instance
+func NewFakeDrainerControl() *FakeDrainerControl {
+	return &FakeDrainerControl{status: map[string]*drainerStatus{}}
+}
+
+// SetStatus sets the status of the drainer with the name returned by GetStatus
+func (fdc *FakeDrainerControl) SetStatus(name string, synced bool, lastTS int64) {
+	fdc.status[name] = &drainerStatus{Synced: synced, LastTS: lastTS}
+}
+
+// SetGetStatusError sets the error returned by GetStatus
+func (fdc *FakeDrainerControl) SetGetStatusError(err error) {
+	fdc.err = err
+}
+
+func (fdc *FakeDrainerControl) GetStatus(_ *v1alpha1.TidbCluster, name string) (*drainerStatus, error) {
+	if fdc.err != nil {
+		return nil, fdc.err
+	}
+	status, ok := fdc.status[name]
+	if !ok {
+		return nil, fmt.Errorf("drainer %s not found", name)
+	}
+	return status, nil
+}
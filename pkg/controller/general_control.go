@@ -0,0 +1,228 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb-operator/pkg/label"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	glog "k8s.io/klog"
+)
+
+// GeneralControlInterface manages the Deployments, ConfigMaps, Secrets and Services owned by the objects
+// other than TidbCluster, e.g. TidbMonitor
+type GeneralControlInterface interface {
+	CreateDeployment(object runtime.Object, deploy *apps.Deployment) error
+	UpdateDeployment(object runtime.Object, deploy *apps.Deployment) (*apps.Deployment, error)
+	CreateConfigMap(object runtime.Object, cm *corev1.ConfigMap) error
+	UpdateConfigMap(object runtime.Object, cm *corev1.ConfigMap) (*corev1.ConfigMap, error)
+	CreateSecret(object runtime.Object, secret *corev1.Secret) error
+	UpdateSecret(object runtime.Object, secret *corev1.Secret) (*corev1.Secret, error)
+	CreateService(object runtime.Object, svc *corev1.Service) error
+	UpdateService(object runtime.Object, svc *corev1.Service) (*corev1.Service, error)
+}
+
+type realGeneralControl struct {
+	kubeCli  kubernetes.Interface
+	recorder record.EventRecorder
+}
+
+// NewRealGeneralControl creates a new GeneralControlInterface
+func NewRealGeneralControl(
+	kubeCli kubernetes.Interface,
+	recorder record.EventRecorder,
+) GeneralControlInterface {
+	return &realGeneralControl{
+		kubeCli:  kubeCli,
+		recorder: recorder,
+	}
+}
+
+func (gc *realGeneralControl) CreateDeployment(object runtime.Object, deploy *apps.Deployment) error {
+	_, err := gc.kubeCli.AppsV1().Deployments(deploy.GetNamespace()).Create(deploy)
+	gc.recordEvent("create", object, "Deployment", deploy, err)
+	return err
+}
+
+func (gc *realGeneralControl) UpdateDeployment(object runtime.Object, deploy *apps.Deployment) (*apps.Deployment, error) {
+	updated, err := gc.kubeCli.AppsV1().Deployments(deploy.GetNamespace()).Update(deploy)
+	gc.recordEvent("update", object, "Deployment", deploy, err)
+	return updated, err
+}
+
+func (gc *realGeneralControl) CreateConfigMap(object runtime.Object, cm *corev1.ConfigMap) error {
+	_, err := gc.kubeCli.CoreV1().ConfigMaps(cm.GetNamespace()).Create(cm)
+	gc.recordEvent("create", object, "ConfigMap", cm, err)
+	return err
+}
+
+func (gc *realGeneralControl) UpdateConfigMap(object runtime.Object, cm *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+	updated, err := gc.kubeCli.CoreV1().ConfigMaps(cm.GetNamespace()).Update(cm)
+	gc.recordEvent("update", object, "ConfigMap", cm, err)
+	return updated, err
+}
+
+func (gc *realGeneralControl) CreateSecret(object runtime.Object, secret *corev1.Secret) error {
+	_, err := gc.kubeCli.CoreV1().Secrets(secret.GetNamespace()).Create(secret)
+	gc.recordEvent("create", object, "Secret", secret, err)
+	return err
+}
+
+func (gc *realGeneralControl) UpdateSecret(object runtime.Object, secret *corev1.Secret) (*corev1.Secret, error) {
+	updated, err := gc.kubeCli.CoreV1().Secrets(secret.GetNamespace()).Update(secret)
+	gc.recordEvent("update", object, "Secret", secret, err)
+	return updated, err
+}
+
+func (gc *realGeneralControl) CreateService(object runtime.Object, svc *corev1.Service) error {
+	_, err := gc.kubeCli.CoreV1().Services(svc.GetNamespace()).Create(svc)
+	gc.recordEvent("create", object, "Service", svc, err)
+	return err
+}
+
+func (gc *realGeneralControl) UpdateService(object runtime.Object, svc *corev1.Service) (*corev1.Service, error) {
+	updated, err := gc.kubeCli.CoreV1().Services(svc.GetNamespace()).Update(svc)
+	gc.recordEvent("update", object, "Service", svc, err)
+	return updated, err
+}
+
+func (gc *realGeneralControl) recordEvent(verb string, obj runtime.Object, resource string, meta metav1.Object, err error) {
+	ns := meta.GetNamespace()
+	name := meta.GetName()
+	instanceName := meta.GetLabels()[label.InstanceLabelKey]
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if err == nil {
+		glog.V(4).Infof("%s %s: [%s/%s] successfully, %s: %s", verb, resource, ns, name, kind, instanceName)
+		reason := fmt.Sprintf("Successful%s", strings.Title(verb))
+		msg := fmt.Sprintf("%s %s %s/%s for %s/%s successful",
+			strings.ToLower(verb), resource, ns, name, kind, instanceName)
+		gc.recorder.Event(obj, corev1.EventTypeNormal, reason, msg)
+	} else {
+		glog.Errorf("failed to %s %s: [%s/%s], %s: %s, %v", verb, resource, ns, name, kind, instanceName, err)
+		reason := fmt.Sprintf("Failed%s", strings.Title(verb))
+		msg := fmt.Sprintf("%s %s %s/%s for %s/%s failed error: %s",
+			strings.ToLower(verb), resource, ns, name, kind, instanceName, err)
+		gc.recorder.Event(obj, corev1.EventTypeWarning, reason, msg)
+	}
+}
+
+var _ GeneralControlInterface = &realGeneralControl{}
+
+// FakeGeneralControl is a fake GeneralControlInterface
+type FakeGeneralControl struct {
+	DeployIndexer    cache.Indexer
+	CmIndexer        cache.Indexer
+	SecretIndexer    cache.Indexer
+	SvcIndexer       cache.Indexer
+	createTracker    RequestTracker
+	updateTracker    RequestTracker
+	updateDeployment int
+}
+
+// NewFakeGeneralControl returns a FakeGeneralControl
+func NewFakeGeneralControl(deployIndexer, cmIndexer, secretIndexer, svcIndexer cache.Indexer) *FakeGeneralControl {
+	return &FakeGeneralControl{
+		DeployIndexer: deployIndexer,
+		CmIndexer:     cmIndexer,
+		SecretIndexer: secretIndexer,
+		SvcIndexer:    svcIndexer,
+	}
+}
+
+// SetCreateError sets the error attributes of createTracker
+func (fgc *FakeGeneralControl) SetCreateError(err error, after int) {
+	fgc.createTracker.SetError(err).SetAfter(after)
+}
+
+// SetUpdateError sets the error attributes of updateTracker
+func (fgc *FakeGeneralControl) SetUpdateError(err error, after int) {
+	fgc.updateTracker.SetError(err).SetAfter(after)
+}
+
+// DeploymentUpdated returns the number of the updates of the Deployments
+func (fgc *FakeGeneralControl) DeploymentUpdated() int {
+	return fgc.updateDeployment
+}
+
+func (fgc *FakeGeneralControl) create(indexer cache.Indexer, obj interface{}) error {
+	defer fgc.createTracker.Inc()
+	if fgc.createTracker.ErrorReady() {
+		defer fgc.createTracker.Reset()
+		return fgc.createTracker.GetError()
+	}
+	return indexer.Add(obj)
+}
+
+func (fgc *FakeGeneralControl) update(indexer cache.Indexer, obj interface{}) error {
+	defer fgc.updateTracker.Inc()
+	if fgc.updateTracker.ErrorReady() {
+		defer fgc.updateTracker.Reset()
+		return fgc.updateTracker.GetError()
+	}
+	return indexer.Update(obj)
+}
+
+// CreateDeployment adds the Deployment to DeployIndexer
+func (fgc *FakeGeneralControl) CreateDeployment(_ runtime.Object, deploy *apps.Deployment) error {
+	return fgc.create(fgc.DeployIndexer, deploy)
+}
+
+// UpdateDeployment updates the Deployment in DeployIndexer
+func (fgc *FakeGeneralControl) UpdateDeployment(_ runtime.Object, deploy *apps.Deployment) (*apps.Deployment, error) {
+	if err := fgc.update(fgc.DeployIndexer, deploy); err != nil {
+		return nil, err
+	}
+	fgc.updateDeployment++
+	return deploy, nil
+}
+
+// CreateConfigMap adds the ConfigMap to CmIndexer
+func (fgc *FakeGeneralControl) CreateConfigMap(_ runtime.Object, cm *corev1.ConfigMap) error {
+	return fgc.create(fgc.CmIndexer, cm)
+}
+
+// UpdateConfigMap updates the ConfigMap in CmIndexer
+func (fgc *FakeGeneralControl) UpdateConfigMap(_ runtime.Object, cm *corev1.ConfigMap) (*corev1.ConfigMap, error) {
+	return cm, fgc.update(fgc.CmIndexer, cm)
+}
+
+// CreateSecret adds the Secret to SecretIndexer
+func (fgc *FakeGeneralControl) CreateSecret(_ runtime.Object, secret *corev1.Secret) error {
+	return fgc.create(fgc.SecretIndexer, secret)
+}
+
+// UpdateSecret updates the Secret in SecretIndexer
+func (fgc *FakeGeneralControl) UpdateSecret(_ runtime.Object, secret *corev1.Secret) (*corev1.Secret, error) {
+	return secret, fgc.update(fgc.SecretIndexer, secret)
+}
+
+// CreateService adds the Service to SvcIndexer
+func (fgc *FakeGeneralControl) CreateService(_ runtime.Object, svc *corev1.Service) error {
+	return fgc.create(fgc.SvcIndexer, svc)
+}
+
+// UpdateService updates the Service in SvcIndexer
+func (fgc *FakeGeneralControl) UpdateService(_ runtime.Object, svc *corev1.Service) (*corev1.Service, error) {
+	return svc, fgc.update(fgc.SvcIndexer, svc)
+}
+
+var _ GeneralControlInterface = &FakeGeneralControl{}
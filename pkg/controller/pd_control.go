@@ -23,6 +23,17 @@ func GetPDClient(pdControl pdapi.PDControlInterface, tc *v1alpha1.TidbCluster) p
 	return pdControl.GetPDClient(pdapi.Namespace(tc.GetNamespace()), tc.GetName(), tc.Spec.EnableTLSCluster)
 }
 
+// GetClusterRefPDClient returns the pd client of the cluster referenced by tc.Spec.Cluster,
+// the client of the pd cluster of tc is returned if tc does not join another cluster
+func GetClusterRefPDClient(pdControl pdapi.PDControlInterface, tc *v1alpha1.TidbCluster) pdapi.PDClient {
+	ref := tc.ClusterRef()
+	if ref == nil {
+		return GetPDClient(pdControl, tc)
+	}
+	host := pdapi.PDClientHost(pdapi.Namespace(ref.Namespace), ref.Name, ref.ClusterDomain)
+	return pdControl.GetPDClientForHost(pdapi.Namespace(tc.GetNamespace()), tc.GetName(), host, tc.Spec.EnableTLSCluster)
+}
+
 // NewFakePDClient creates a fake pdclient that is set as the pd client
 func NewFakePDClient(pdControl *pdapi.FakePDControl, tc *v1alpha1.TidbCluster) *pdapi.FakePDClient {
 	pdClient := pdapi.NewFakePDClient()
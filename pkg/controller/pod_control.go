@@ -39,6 +39,7 @@ type PodControlInterface interface {
 	UpdateMetaInfo(*v1alpha1.TidbCluster, *corev1.Pod) (*corev1.Pod, error)
 	DeletePod(*v1alpha1.TidbCluster, *corev1.Pod) error
 	UpdatePod(*v1alpha1.TidbCluster, *corev1.Pod) (*corev1.Pod, error)
+	UpdatePodStatus(*v1alpha1.TidbCluster, *corev1.Pod) (*corev1.Pod, error)
 }
 
 type realPodControl struct {
@@ -97,6 +98,38 @@ func (rpc *realPodControl) UpdatePod(tc *v1alpha1.TidbCluster, pod *corev1.Pod)
 	return updatePod, err
 }
 
+func (rpc *realPodControl) UpdatePodStatus(tc *v1alpha1.TidbCluster, pod *corev1.Pod) (*corev1.Pod, error) {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	podName := pod.GetName()
+
+	status := pod.Status.DeepCopy()
+
+	var updatePod *corev1.Pod
+	// don't wait due to limited number of clients, but backoff after the default number of steps
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var updateErr error
+		updatePod, updateErr = rpc.kubeCli.CoreV1().Pods(ns).UpdateStatus(pod)
+		if updateErr == nil {
+			glog.Infof("Pod: [%s/%s] status updated successfully, TidbCluster: [%s/%s]", ns, podName, ns, tcName)
+			return nil
+		}
+		glog.Errorf("failed to update Pod: [%s/%s] status, error: %v", ns, podName, updateErr)
+
+		if updated, err := rpc.podLister.Pods(ns).Get(podName); err == nil {
+			// make a copy so we don't mutate the shared cache
+			pod = updated.DeepCopy()
+			pod.Status.Conditions = status.Conditions
+		} else {
+			utilruntime.HandleError(fmt.Errorf("error getting updated Pod %s/%s from lister: %v", ns, podName, err))
+		}
+
+		return updateErr
+	})
+	rpc.recordPodEvent("update", tc, podName, err)
+	return updatePod, err
+}
+
 func (rpc *realPodControl) UpdateMetaInfo(tc *v1alpha1.TidbCluster, pod *corev1.Pod) (*corev1.Pod, error) {
 	ns := pod.GetNamespace()
 	podName := pod.GetName()
@@ -138,7 +171,7 @@ func (rpc *realPodControl) UpdateMetaInfo(tc *v1alpha1.TidbCluster, pod *corev1.
 				}
 			}
 		}
-	case label.TiKVLabelVal:
+	case label.TiKVLabelVal, label.TiFlashLabelVal:
 		if labels[label.StoreIDLabelKey] == "" {
 			// get store id
 			stores, err := pdClient.GetStores()
@@ -336,4 +369,14 @@ func (fpc *FakePodControl) UpdatePod(_ *v1alpha1.TidbCluster, pod *corev1.Pod) (
 	return pod, fpc.PodIndexer.Update(pod)
 }
 
+func (fpc *FakePodControl) UpdatePodStatus(_ *v1alpha1.TidbCluster, pod *corev1.Pod) (*corev1.Pod, error) {
+	defer fpc.updatePodTracker.Inc()
+	if fpc.updatePodTracker.ErrorReady() {
+		defer fpc.updatePodTracker.Reset()
+		return nil, fpc.updatePodTracker.GetError()
+	}
+
+	return pod, fpc.PodIndexer.Update(pod)
+}
+
 var _ PodControlInterface = &FakePodControl{}
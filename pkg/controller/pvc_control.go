@@ -35,6 +35,7 @@ import (
 // PVCControlInterface manages PVCs used in TidbCluster
 type PVCControlInterface interface {
 	UpdateMetaInfo(*v1alpha1.TidbCluster, *corev1.PersistentVolumeClaim, *corev1.Pod) (*corev1.PersistentVolumeClaim, error)
+	CreatePVC(*v1alpha1.TidbCluster, *corev1.PersistentVolumeClaim) error
 	UpdatePVC(*v1alpha1.TidbCluster, *corev1.PersistentVolumeClaim) (*corev1.PersistentVolumeClaim, error)
 	DeletePVC(*v1alpha1.TidbCluster, *corev1.PersistentVolumeClaim) error
 	GetPVC(name, namespace string) (*corev1.PersistentVolumeClaim, error)
@@ -62,6 +63,20 @@ func (rpc *realPVCControl) GetPVC(name, namespace string) (*corev1.PersistentVol
 	return rpc.pvcLister.PersistentVolumeClaims(namespace).Get(name)
 }
 
+func (rpc *realPVCControl) CreatePVC(tc *v1alpha1.TidbCluster, pvc *corev1.PersistentVolumeClaim) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	pvcName := pvc.GetName()
+	_, err := rpc.kubeCli.CoreV1().PersistentVolumeClaims(ns).Create(pvc)
+	if err != nil {
+		glog.Errorf("failed to create PVC: [%s/%s], TidbCluster: %s, %v", ns, pvcName, tcName, err)
+	} else {
+		glog.V(4).Infof("create PVC: [%s/%s] successfully, TidbCluster: %s", ns, pvcName, tcName)
+	}
+	rpc.recordPVCEvent("create", tc, pvcName, err)
+	return err
+}
+
 func (rpc *realPVCControl) DeletePVC(tc *v1alpha1.TidbCluster, pvc *corev1.PersistentVolumeClaim) error {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
@@ -185,6 +200,7 @@ var _ PVCControlInterface = &realPVCControl{}
 // FakePVCControl is a fake PVCControlInterface
 type FakePVCControl struct {
 	PVCIndexer       cache.Indexer
+	createPVCTracker RequestTracker
 	updatePVCTracker RequestTracker
 	deletePVCTracker RequestTracker
 }
@@ -195,9 +211,15 @@ func NewFakePVCControl(pvcInformer coreinformers.PersistentVolumeClaimInformer)
 		pvcInformer.Informer().GetIndexer(),
 		RequestTracker{},
 		RequestTracker{},
+		RequestTracker{},
 	}
 }
 
+// SetCreatePVCError sets the error attributes of createPVCTracker
+func (fpc *FakePVCControl) SetCreatePVCError(err error, after int) {
+	fpc.createPVCTracker.SetError(err).SetAfter(after)
+}
+
 // SetUpdatePVCError sets the error attributes of updatePVCTracker
 func (fpc *FakePVCControl) SetUpdatePVCError(err error, after int) {
 	fpc.updatePVCTracker.SetError(err).SetAfter(after)
@@ -208,6 +230,17 @@ func (fpc *FakePVCControl) SetDeletePVCError(err error, after int) {
 	fpc.deletePVCTracker.SetError(err).SetAfter(after)
 }
 
+// CreatePVC adds the pvc to the indexer
+func (fpc *FakePVCControl) CreatePVC(_ *v1alpha1.TidbCluster, pvc *corev1.PersistentVolumeClaim) error {
+	defer fpc.createPVCTracker.Inc()
+	if fpc.createPVCTracker.ErrorReady() {
+		defer fpc.createPVCTracker.Reset()
+		return fpc.createPVCTracker.GetError()
+	}
+
+	return fpc.PVCIndexer.Add(pvc)
+}
+
 // DeletePVC deletes the pvc
 func (fpc *FakePVCControl) DeletePVC(_ *v1alpha1.TidbCluster, pvc *corev1.PersistentVolumeClaim) error {
 	defer fpc.deletePVCTracker.Inc()
@@ -17,7 +17,6 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/pem"
-	"fmt"
 
 	"github.com/pingcap/tidb-operator/pkg/label"
 	certutil "github.com/pingcap/tidb-operator/pkg/util/crypto"
@@ -29,10 +28,23 @@ import (
 	glog "k8s.io/klog"
 )
 
+const (
+	// TLSSecretCertKey is the key of the certificate in the Secrets created by tidb-operator
+	TLSSecretCertKey = "cert"
+	// TLSSecretKeyKey is the key of the private key in the Secrets created by tidb-operator
+	TLSSecretKeyKey = "key"
+	// TLSSecretCAKey is the key of the CA certificate in the Secrets created by tidb-operator,
+	// it is absent if the certificate is signed by the Kubernetes cluster CA
+	TLSSecretCAKey = "ca"
+)
+
 // SecretControlInterface manages certificates used by TiDB clusters
 type SecretControlInterface interface {
-	Create(or metav1.OwnerReference, certOpts *TiDBClusterCertOptions, cert []byte, key []byte) error
+	Create(or metav1.OwnerReference, certOpts *TiDBClusterCertOptions, cert []byte, key []byte, ca []byte) error
+	Update(or metav1.OwnerReference, certOpts *TiDBClusterCertOptions, cert []byte, key []byte, ca []byte) error
 	Load(ns string, secretName string) ([]byte, []byte, error)
+	// LoadCA loads the CA certificate from the Secret, nil is returned if the Secret does not contain it
+	LoadCA(ns string, secretName string) ([]byte, error)
 	Check(ns string, secretName string) bool
 }
 
@@ -52,8 +64,8 @@ func NewRealSecretControl(
 	}
 }
 
-func (rsc *realSecretControl) Create(or metav1.OwnerReference, certOpts *TiDBClusterCertOptions, cert []byte, key []byte) error {
-	secretName := fmt.Sprintf("%s-%s", certOpts.Instance, certOpts.Suffix)
+func (rsc *realSecretControl) Create(or metav1.OwnerReference, certOpts *TiDBClusterCertOptions, cert []byte, key []byte, ca []byte) error {
+	secretName := certOpts.SecretName()
 
 	secretLabel := label.New().Instance(certOpts.Instance).
 		Component(certOpts.Component).Labels()
@@ -64,10 +76,7 @@ func (rsc *realSecretControl) Create(or metav1.OwnerReference, certOpts *TiDBClu
 			Labels:          secretLabel,
 			OwnerReferences: []metav1.OwnerReference{or},
 		},
-		Data: map[string][]byte{
-			"cert": cert,
-			"key":  key,
-		},
+		Data: tlsSecretData(cert, key, ca),
 	}
 
 	_, err := rsc.kubeCli.CoreV1().Secrets(certOpts.Namespace).Create(secret)
@@ -77,14 +86,46 @@ func (rsc *realSecretControl) Create(or metav1.OwnerReference, certOpts *TiDBClu
 	return err
 }
 
-// Load loads cert and key from Secret matching the name
+// Update overwrites the cert and key in the existing Secret
+func (rsc *realSecretControl) Update(or metav1.OwnerReference, certOpts *TiDBClusterCertOptions, cert []byte, key []byte, ca []byte) error {
+	secretName := certOpts.SecretName()
+
+	secret, err := rsc.kubeCli.CoreV1().Secrets(certOpts.Namespace).Get(secretName, types.GetOptions{})
+	if err != nil {
+		return err
+	}
+	secret = secret.DeepCopy()
+	secret.Data = tlsSecretData(cert, key, ca)
+
+	_, err = rsc.kubeCli.CoreV1().Secrets(certOpts.Namespace).Update(secret)
+	if err == nil {
+		glog.Infof("update cert in secret %s/%s", certOpts.Namespace, secretName)
+	}
+	return err
+}
+
+// Load loads cert and key from Secret matching the name, both the layout of the Secrets created by
+// tidb-operator and the kubernetes.io/tls layout are supported
 func (rsc *realSecretControl) Load(ns string, secretName string) ([]byte, []byte, error) {
 	secret, err := rsc.secretLister.Secrets(ns).Get(secretName)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return secret.Data["cert"], secret.Data["key"], nil
+	cert, key := tlsSecretKeyPair(secret)
+	return cert, key, nil
+}
+
+func (rsc *realSecretControl) LoadCA(ns string, secretName string) ([]byte, error) {
+	secret, err := rsc.secretLister.Secrets(ns).Get(secretName)
+	if err != nil {
+		return nil, err
+	}
+
+	if ca, ok := secret.Data[TLSSecretCAKey]; ok {
+		return ca, nil
+	}
+	return secret.Data[corev1.ServiceAccountRootCAKey], nil
 }
 
 // Check returns true if the secret already exist
@@ -106,7 +147,12 @@ func (rsc *realSecretControl) Check(ns string, secretName string) bool {
 		glog.Errorf("certificate validation failed for [%s/%s], can not parse cert, %v", ns, secretName, err)
 		return false
 	}
-	rootCAs, err := certutil.ReadCACerts()
+	ca, err := rsc.LoadCA(ns, secretName)
+	if err != nil {
+		glog.Errorf("certificate validation failed for [%s/%s], error loading CA from secret, %v", ns, secretName, err)
+		return false
+	}
+	rootCAs, err := certutil.LoadCACerts(ca)
 	if err != nil {
 		glog.Errorf("certificate validation failed for [%s/%s], error loading CAs, %v", ns, secretName, err)
 		return false
@@ -135,6 +181,27 @@ func (rsc *realSecretControl) Check(ns string, secretName string) bool {
 	return true
 }
 
+// tlsSecretKeyPair returns the cert and key in the Secret, which is either created by tidb-operator
+// or of the kubernetes.io/tls type
+func tlsSecretKeyPair(secret *corev1.Secret) ([]byte, []byte) {
+	if _, ok := secret.Data[TLSSecretCertKey]; !ok {
+		return secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey]
+	}
+	return secret.Data[TLSSecretCertKey], secret.Data[TLSSecretKeyKey]
+}
+
+// tlsSecretData returns the data of the Secret created by tidb-operator, ca is omitted if it is empty
+func tlsSecretData(cert []byte, key []byte, ca []byte) map[string][]byte {
+	data := map[string][]byte{
+		TLSSecretCertKey: cert,
+		TLSSecretKeyKey:  key,
+	}
+	if len(ca) > 0 {
+		data[TLSSecretCAKey] = ca
+	}
+	return data
+}
+
 var _ SecretControlInterface = &realSecretControl{}
 
 type FakeSecretControl struct {
@@ -205,8 +205,14 @@ func (ssc *FakeServiceControl) UpdateService(_ *v1alpha1.TidbCluster, svc *corev
 }
 
 // DeleteService deletes the service of SvcIndexer
-func (ssc *FakeServiceControl) DeleteService(_ *v1alpha1.TidbCluster, _ *corev1.Service) error {
-	return nil
+func (ssc *FakeServiceControl) DeleteService(_ *v1alpha1.TidbCluster, svc *corev1.Service) error {
+	defer ssc.deleteStatefulSetTracker.Inc()
+	if ssc.deleteStatefulSetTracker.ErrorReady() {
+		defer ssc.deleteStatefulSetTracker.Reset()
+		return ssc.deleteStatefulSetTracker.GetError()
+	}
+
+	return ssc.SvcIndexer.Delete(svc)
 }
 
 var _ ServiceControlInterface = &FakeServiceControl{}
@@ -205,8 +205,14 @@ func (ssc *FakeStatefulSetControl) UpdateStatefulSet(_ *v1alpha1.TidbCluster, se
 }
 
 // DeleteStatefulSet deletes the statefulset of SetIndexer
-func (ssc *FakeStatefulSetControl) DeleteStatefulSet(_ *v1alpha1.TidbCluster, _ *apps.StatefulSet) error {
-	return nil
+func (ssc *FakeStatefulSetControl) DeleteStatefulSet(_ *v1alpha1.TidbCluster, set *apps.StatefulSet) error {
+	defer ssc.deleteStatefulSetTracker.Inc()
+	if ssc.deleteStatefulSetTracker.ErrorReady() {
+		defer ssc.deleteStatefulSetTracker.Reset()
+		return ssc.deleteStatefulSetTracker.GetError()
+	}
+
+	return ssc.SetIndexer.Delete(set)
 }
 
 var _ StatefulSetControlInterface = &FakeStatefulSetControl{}
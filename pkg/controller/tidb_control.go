@@ -19,12 +19,16 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/httputil"
-	certutil "github.com/pingcap/tidb-operator/pkg/util/crypto"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
 	"github.com/pingcap/tidb/config"
+	coreinformers "k8s.io/client-go/informers/core/v1"
 )
 
 const (
@@ -38,6 +42,12 @@ type dbInfo struct {
 	IsOwner bool `json:"is_owner"`
 }
 
+type serverStatus struct {
+	Connections int    `json:"connections"`
+	Version     string `json:"version"`
+	GitHash     string `json:"git_hash"`
+}
+
 // TiDBControlInterface is the interface that knows how to manage tidb peers
 type TiDBControlInterface interface {
 	// GetHealth returns tidb's health info
@@ -46,30 +56,84 @@ type TiDBControlInterface interface {
 	GetInfo(tc *v1alpha1.TidbCluster, ordinal int32) (*dbInfo, error)
 	// GetSettings return the TiDB instance settings
 	GetSettings(tc *v1alpha1.TidbCluster, ordinal int32) (*config.Config, error)
+	// GetStatus returns the TiDB instance status, including the number of active connections
+	GetStatus(tc *v1alpha1.TidbCluster, ordinal int32) (*serverStatus, error)
 }
 
 // defaultTiDBControl is default implementation of TiDBControlInterface.
 type defaultTiDBControl struct {
+	httpClients *clusterHTTPClients
+}
+
+// NewDefaultTiDBControl returns a defaultTiDBControl instance, the certificates of the TLS enabled clusters
+// are read from the Secrets in secretInformer
+func NewDefaultTiDBControl(secretInformer coreinformers.SecretInformer) TiDBControlInterface {
+	return &defaultTiDBControl{httpClients: newClusterHTTPClients(secretInformer)}
+}
+
+func (tdc *defaultTiDBControl) getHTTPClient(tc *v1alpha1.TidbCluster) (*http.Client, error) {
+	return tdc.httpClients.get(tc)
+}
+
+// clusterHTTPClients caches the http clients to access the components of the clusters
+type clusterHTTPClients struct {
+	mutex      sync.Mutex
 	httpClient *http.Client
+	tlsConfigs *pdapi.TLSConfigCache
+	// tlsHTTPClients are the http clients of the TLS enabled clusters keyed by the cluster and the CA,
+	// the client is recreated once the certificate is rotated
+	tlsHTTPClients       map[string]*http.Client
+	tlsHTTPClientConfigs map[string]*tls.Config
 }
 
-// NewDefaultTiDBControl returns a defaultTiDBControl instance
-func NewDefaultTiDBControl() TiDBControlInterface {
-	return &defaultTiDBControl{httpClient: &http.Client{Timeout: timeout}}
+func newClusterHTTPClients(secretInformer coreinformers.SecretInformer) *clusterHTTPClients {
+	c := &clusterHTTPClients{
+		httpClient:           &http.Client{Timeout: timeout},
+		tlsConfigs:           pdapi.NewTLSConfigCache(secretInformer),
+		tlsHTTPClients:       map[string]*http.Client{},
+		tlsHTTPClientConfigs: map[string]*tls.Config{},
+	}
+	c.tlsConfigs.OnEvict(c.evict)
+	return c
 }
 
-func (tdc *defaultTiDBControl) useTLSHTTPClient(enableTLS bool) error {
-	if enableTLS {
-		rootCAs, err := certutil.ReadCACerts()
-		if err != nil {
-			return err
-		}
-		config := &tls.Config{
-			RootCAs: rootCAs,
+// evict evicts the http clients of the TLS enabled cluster
+func (c *clusterHTTPClients) evict(namespace pdapi.Namespace, tcName string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	prefix := fmt.Sprintf("%s/%s/", namespace, tcName)
+	for key := range c.tlsHTTPClients {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.tlsHTTPClients, key)
+			delete(c.tlsHTTPClientConfigs, key)
 		}
-		tdc.httpClient.Transport = &http.Transport{TLSClientConfig: config}
 	}
-	return nil
+}
+
+// get returns the http client to access the components of tc, the client of a TLS enabled
+// cluster trusts the CA and presents the certificate in the PD client Secret of the cluster
+func (c *clusterHTTPClients) get(tc *v1alpha1.TidbCluster) (*http.Client, error) {
+	if !tc.Spec.EnableTLSCluster {
+		return c.httpClient, nil
+	}
+
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	config, caID, err := c.tlsConfigs.Get(pdapi.Namespace(ns), tcName)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	key := fmt.Sprintf("%s/%s/%s", ns, tcName, caID)
+	if _, ok := c.tlsHTTPClients[key]; !ok || c.tlsHTTPClientConfigs[key] != config {
+		c.tlsHTTPClients[key] = &http.Client{Timeout: timeout, Transport: &http.Transport{TLSClientConfig: config}}
+		c.tlsHTTPClientConfigs[key] = config
+	}
+	return c.tlsHTTPClients[key], nil
 }
 
 func (tdc *defaultTiDBControl) GetHealth(tc *v1alpha1.TidbCluster) map[string]bool {
@@ -79,14 +143,15 @@ func (tdc *defaultTiDBControl) GetHealth(tc *v1alpha1.TidbCluster) map[string]bo
 
 	result := map[string]bool{}
 
-	if err := tdc.useTLSHTTPClient(tc.Spec.EnableTLSCluster); err != nil {
+	httpClient, err := tdc.getHTTPClient(tc)
+	if err != nil {
 		return result
 	}
 
 	for i := 0; i < int(tc.TiDBStsActualReplicas()); i++ {
-		hostName := fmt.Sprintf("%s-%d", TiDBMemberName(tcName), i)
+		hostName := fmt.Sprintf("%s-%d", tidbSetName(tc), i)
 		url := fmt.Sprintf("%s://%s.%s.%s:10080/status", scheme, hostName, TiDBPeerMemberName(tcName), ns)
-		_, err := tdc.getBodyOK(url)
+		_, err := getBodyOK(httpClient, url)
 		if err != nil {
 			result[hostName] = false
 		} else {
@@ -100,17 +165,18 @@ func (tdc *defaultTiDBControl) GetInfo(tc *v1alpha1.TidbCluster, ordinal int32)
 	tcName := tc.GetName()
 	ns := tc.GetNamespace()
 	scheme := tc.Scheme()
-	if err := tdc.useTLSHTTPClient(tc.Spec.EnableTLSCluster); err != nil {
+	httpClient, err := tdc.getHTTPClient(tc)
+	if err != nil {
 		return nil, err
 	}
 
-	hostName := fmt.Sprintf("%s-%d", TiDBMemberName(tcName), ordinal)
+	hostName := fmt.Sprintf("%s-%d", tidbSetName(tc), ordinal)
 	url := fmt.Sprintf("%s://%s.%s.%s:10080/info", scheme, hostName, TiDBPeerMemberName(tcName), ns)
 	req, err := http.NewRequest("POST", url, nil)
 	if err != nil {
 		return nil, err
 	}
-	res, err := tdc.httpClient.Do(req)
+	res, err := httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -135,17 +201,18 @@ func (tdc *defaultTiDBControl) GetSettings(tc *v1alpha1.TidbCluster, ordinal int
 	tcName := tc.GetName()
 	ns := tc.GetNamespace()
 	scheme := tc.Scheme()
-	if err := tdc.useTLSHTTPClient(tc.Spec.EnableTLSCluster); err != nil {
+	httpClient, err := tdc.getHTTPClient(tc)
+	if err != nil {
 		return nil, err
 	}
 
-	hostName := fmt.Sprintf("%s-%d", TiDBMemberName(tcName), ordinal)
+	hostName := fmt.Sprintf("%s-%d", tidbSetName(tc), ordinal)
 	url := fmt.Sprintf("%s://%s.%s.%s:10080/settings", scheme, hostName, TiDBPeerMemberName(tcName), ns)
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
-	res, err := tdc.httpClient.Do(req)
+	res, err := httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -166,8 +233,40 @@ func (tdc *defaultTiDBControl) GetSettings(tc *v1alpha1.TidbCluster, ordinal int
 	return &info, nil
 }
 
-func (tdc *defaultTiDBControl) getBodyOK(apiURL string) ([]byte, error) {
-	res, err := tdc.httpClient.Get(apiURL)
+func (tdc *defaultTiDBControl) GetStatus(tc *v1alpha1.TidbCluster, ordinal int32) (*serverStatus, error) {
+	tcName := tc.GetName()
+	ns := tc.GetNamespace()
+	scheme := tc.Scheme()
+	httpClient, err := tdc.getHTTPClient(tc)
+	if err != nil {
+		return nil, err
+	}
+
+	hostName := fmt.Sprintf("%s-%d", tidbSetName(tc), ordinal)
+	url := fmt.Sprintf("%s://%s.%s.%s:10080/status", scheme, hostName, TiDBPeerMemberName(tcName), ns)
+	body, err := getBodyOK(httpClient, url)
+	if err != nil {
+		return nil, err
+	}
+	status := serverStatus{}
+	err = json.Unmarshal(body, &status)
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// tidbSetName returns the name of the TiDB StatefulSet of tc, tc may be a view of a TiDB group
+// labelled with the group name
+func tidbSetName(tc *v1alpha1.TidbCluster) string {
+	if group := tc.GetLabels()[label.TiDBGroupLabelKey]; group != "" {
+		return TiDBGroupMemberName(tc.GetName(), group)
+	}
+	return TiDBMemberName(tc.GetName())
+}
+
+func getBodyOK(httpClient *http.Client, apiURL string) ([]byte, error) {
+	res, err := httpClient.Get(apiURL)
 	if err != nil {
 		return nil, err
 	}
@@ -190,6 +289,7 @@ type FakeTiDBControl struct {
 	tidbInfo     *dbInfo
 	getInfoError error
 	tidbConfig   *config.Config
+	tidbStatus   *serverStatus
 }
 
 // NewFakeTiDBControl returns a FakeTiDBControl instance
@@ -213,3 +313,15 @@ func (ftd *FakeTiDBControl) GetInfo(tc *v1alpha1.TidbCluster, ordinal int32) (*d
 func (ftd *FakeTiDBControl) GetSettings(tc *v1alpha1.TidbCluster, ordinal int32) (*config.Config, error) {
 	return ftd.tidbConfig, ftd.getInfoError
 }
+
+// SetConnections sets the number of active connections returned by GetStatus
+func (ftd *FakeTiDBControl) SetConnections(connections int) {
+	ftd.tidbStatus = &serverStatus{Connections: connections}
+}
+
+func (ftd *FakeTiDBControl) GetStatus(tc *v1alpha1.TidbCluster, ordinal int32) (*serverStatus, error) {
+	if ftd.tidbStatus == nil {
+		return &serverStatus{}, ftd.getInfoError
+	}
+	return ftd.tidbStatus, ftd.getInfoError
+}
@@ -15,12 +15,15 @@ package tidbcluster
 
 import (
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1/validation"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/manager"
 	"github.com/pingcap/tidb-operator/pkg/manager/member"
+	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	errorutils "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/tools/record"
+	glog "k8s.io/klog"
 )
 
 // ControlInterface implements the control logic for updating TidbClusters and their children StatefulSets.
@@ -37,23 +40,33 @@ func NewDefaultTidbClusterControl(
 	tcControl controller.TidbClusterControlInterface,
 	pdMemberManager manager.Manager,
 	tikvMemberManager manager.Manager,
+	tiflashMemberManager manager.Manager,
 	tidbMemberManager manager.Manager,
 	reclaimPolicyManager manager.Manager,
 	metaManager manager.Manager,
 	orphanPodsCleaner member.OrphanPodsCleaner,
 	pvcCleaner member.PVCCleanerInterface,
 	pumpMemberManager manager.Manager,
+	drainerMemberManager manager.Manager,
+	tlsCertManager manager.Manager,
+	pvcResizer manager.Manager,
+	memberReplacer manager.Manager,
 	recorder record.EventRecorder) ControlInterface {
 	return &defaultTidbClusterControl{
 		tcControl,
 		pdMemberManager,
 		tikvMemberManager,
+		tiflashMemberManager,
 		tidbMemberManager,
 		reclaimPolicyManager,
 		metaManager,
 		orphanPodsCleaner,
 		pvcCleaner,
 		pumpMemberManager,
+		drainerMemberManager,
+		tlsCertManager,
+		pvcResizer,
+		memberReplacer,
 		recorder,
 	}
 }
@@ -62,12 +75,17 @@ type defaultTidbClusterControl struct {
 	tcControl            controller.TidbClusterControlInterface
 	pdMemberManager      manager.Manager
 	tikvMemberManager    manager.Manager
+	tiflashMemberManager manager.Manager
 	tidbMemberManager    manager.Manager
 	reclaimPolicyManager manager.Manager
 	metaManager          manager.Manager
 	orphanPodsCleaner    member.OrphanPodsCleaner
 	pvcCleaner           member.PVCCleanerInterface
 	pumpMemberManager    manager.Manager
+	drainerMemberManager manager.Manager
+	tlsCertManager       manager.Manager
+	pvcResizer           manager.Manager
+	memberReplacer       manager.Manager
 	recorder             record.EventRecorder
 }
 
@@ -75,11 +93,18 @@ type defaultTidbClusterControl struct {
 func (tcc *defaultTidbClusterControl) UpdateTidbCluster(tc *v1alpha1.TidbCluster) error {
 	var errs []error
 	oldStatus := tc.Status.DeepCopy()
+	oldReplaceMembers := append([]string(nil), tc.Spec.ReplaceMembers...)
+
+	// the invalid cluster is not synced, it is synced again once the spec is fixed
+	if !tcc.validate(tc) {
+		return nil
+	}
 
 	if err := tcc.updateTidbCluster(tc); err != nil {
 		errs = append(errs, err)
 	}
-	if apiequality.Semantic.DeepEqual(&tc.Status, oldStatus) {
+	// the member replacer removes the replaced members from the spec
+	if apiequality.Semantic.DeepEqual(&tc.Status, oldStatus) && apiequality.Semantic.DeepEqual(tc.Spec.ReplaceMembers, oldReplaceMembers) {
 		return errorutils.NewAggregate(errs)
 	}
 	if _, err := tcc.tcControl.UpdateTidbCluster(tc.DeepCopy(), &tc.Status, oldStatus); err != nil {
@@ -89,6 +114,18 @@ func (tcc *defaultTidbClusterControl) UpdateTidbCluster(tc *v1alpha1.TidbCluster
 	return errorutils.NewAggregate(errs)
 }
 
+// validate returns whether the spec of tc is valid, the errors are recorded as an event of tc
+func (tcc *defaultTidbClusterControl) validate(tc *v1alpha1.TidbCluster) bool {
+	errs := validation.ValidateTidbCluster(tc)
+	if len(errs) == 0 {
+		return true
+	}
+	aggregatedErr := errs.ToAggregate()
+	glog.Errorf("tidbcluster: [%s/%s] is invalid and is not synced until it is fixed, %v", tc.GetNamespace(), tc.GetName(), aggregatedErr)
+	tcc.recorder.Event(tc, corev1.EventTypeWarning, "FailedValidation", aggregatedErr.Error())
+	return false
+}
+
 func (tcc *defaultTidbClusterControl) updateTidbCluster(tc *v1alpha1.TidbCluster) error {
 	// syncing all PVs managed by operator's reclaim policy to Retain
 	if err := tcc.reclaimPolicyManager.Sync(tc); err != nil {
@@ -128,6 +165,24 @@ func (tcc *defaultTidbClusterControl) updateTidbCluster(tc *v1alpha1.TidbCluster
 		return err
 	}
 
+	// works that should do to making the tiflash cluster current state match the desired state:
+	//   - waiting for the pd cluster available(pd cluster is in quorum)
+	//   - create or update tiflash config map and headless service
+	//   - create the tiflash statefulset
+	//   - sync tiflash cluster status from pd to TidbCluster object
+	//   - set the engine label and scheduler labels to tiflash stores
+	//   - upgrade the tiflash cluster after tikv
+	//   - scale out/in the tiflash cluster
+	//   - failover the tiflash cluster
+	if err := tcc.tiflashMemberManager.Sync(tc); err != nil {
+		return err
+	}
+
+	// syncing the pump cluster before tidb, so that tidb is upgraded after pump
+	if err := tcc.pumpMemberManager.Sync(tc); err != nil {
+		return err
+	}
+
 	// works that should do to making the tidb cluster current state match the desired state:
 	//   - waiting for the tikv cluster available(at least one peer works)
 	//   - create or update tidb headless service
@@ -140,6 +195,15 @@ func (tcc *defaultTidbClusterControl) updateTidbCluster(tc *v1alpha1.TidbCluster
 		return err
 	}
 
+	// works that should do to making the drainers current state match the desired state:
+	//   - create or update the headless service, the config map and the statefulset of each drainer
+	//   - sync the synced TSO and the lag of each drainer from its status API
+	//   - upgrade the drainers after all the other components
+	//   - remove the drainers that are removed from spec.drainers, their checkpoint PVCs are retained
+	if err := tcc.drainerMemberManager.Sync(tc); err != nil {
+		return err
+	}
+
 	// syncing the labels from Pod to PVC and PV, these labels include:
 	//   - label.StoreIDLabelKey
 	//   - label.MemberIDLabelKey
@@ -148,13 +212,27 @@ func (tcc *defaultTidbClusterControl) updateTidbCluster(tc *v1alpha1.TidbCluster
 		return err
 	}
 
-	// cleaning the pod scheduling annotation for pd and tikv
-	if _, err := tcc.pvcCleaner.Clean(tc); err != nil {
+	// tracking the expiry of the TLS certificates and renewing them before they expire,
+	// the pods mounting the renewed certificates are restarted by the upgraders in the next round
+	if err := tcc.tlsCertManager.Sync(tc); err != nil {
 		return err
 	}
 
-	// syncing the pump cluster
-	return tcc.pumpMemberManager.Sync(tc)
+	// expanding the PVCs of pd, tikv and pump to the storage size in the spec, the pods whose
+	// file systems are resized offline are restarted by the upgraders in the next round
+	if err := tcc.pvcResizer.Sync(tc); err != nil {
+		return err
+	}
+
+	// replacing the pd and tikv members in spec.replaceMembers one at a time, the members are
+	// removed from pd before their pods and PVCs are deleted
+	if err := tcc.memberReplacer.Sync(tc); err != nil {
+		return err
+	}
+
+	// cleaning the pod scheduling annotation for pd and tikv
+	_, err := tcc.pvcCleaner.Clean(tc)
+	return err
 }
 
 var _ ControlInterface = &defaultTidbClusterControl{}
@@ -273,6 +273,22 @@ func TestTidbClusterControlUpdateTidbCluster(t *testing.T) {
 				g.Expect(err).NotTo(HaveOccurred())
 			},
 		},
+		{
+			name: "invalid cluster is not synced",
+			update: func(cluster *v1alpha1.TidbCluster) {
+				cluster.Spec.TiDB.TLSClient = &v1alpha1.TiDBTLSClient{Users: []string{"app_user"}}
+			},
+			syncReclaimPolicyErr:     true,
+			orphanPodCleanerErr:      false,
+			syncPDMemberManagerErr:   false,
+			syncTiKVMemberManagerErr: false,
+			syncTiDBMemberManagerErr: false,
+			syncMetaManagerErr:       false,
+			updateTCStatusErr:        false,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+		},
 	}
 
 	for i := range tests {
@@ -310,22 +326,32 @@ func newFakeTidbClusterControl() (
 	tcUpdater := controller.NewFakeTidbClusterControl(tcInformer)
 	pdMemberManager := mm.NewFakePDMemberManager()
 	tikvMemberManager := mm.NewFakeTiKVMemberManager()
+	tiflashMemberManager := mm.NewFakeTiFlashMemberManager()
 	tidbMemberManager := mm.NewFakeTiDBMemberManager()
 	reclaimPolicyManager := meta.NewFakeReclaimPolicyManager()
 	metaManager := meta.NewFakeMetaManager()
 	orphanPodCleaner := mm.NewFakeOrphanPodsCleaner()
 	pvcCleaner := mm.NewFakePVCCleaner()
 	pumpMemberManager := mm.NewFakePumpMemberManager()
+	drainerMemberManager := mm.NewFakeDrainerMemberManager()
+	tlsCertManager := mm.NewFakeTLSCertManager()
+	pvcResizer := mm.NewFakePVCResizer()
+	memberReplacer := mm.NewFakeMemberReplacer()
 	control := NewDefaultTidbClusterControl(
 		tcUpdater,
 		pdMemberManager,
 		tikvMemberManager,
+		tiflashMemberManager,
 		tidbMemberManager,
 		reclaimPolicyManager,
 		metaManager,
 		orphanPodCleaner,
 		pvcCleaner,
 		pumpMemberManager,
+		drainerMemberManager,
+		tlsCertManager,
+		pvcResizer,
+		memberReplacer,
 		recorder,
 	)
 
@@ -32,6 +32,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
 	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	eventv1 "k8s.io/client-go/kubernetes/typed/core/v1"
@@ -67,6 +68,7 @@ type Controller struct {
 func NewController(
 	kubeCli kubernetes.Interface,
 	cli versioned.Interface,
+	dynamicCli dynamic.Interface,
 	informerFactory informers.SharedInformerFactory,
 	kubeInformerFactory kubeinformers.SharedInformerFactory,
 	autoFailover bool,
@@ -88,29 +90,33 @@ func NewController(
 	pvInformer := kubeInformerFactory.Core().V1().PersistentVolumes()
 	podInformer := kubeInformerFactory.Core().V1().Pods()
 	nodeInformer := kubeInformerFactory.Core().V1().Nodes()
+	scInformer := kubeInformerFactory.Storage().V1().StorageClasses()
 	csrInformer := kubeInformerFactory.Certificates().V1beta1().CertificateSigningRequests()
 	secretInformer := kubeInformerFactory.Core().V1().Secrets()
 	cmInformer := kubeInformerFactory.Core().V1().ConfigMaps()
 
 	tcControl := controller.NewRealTidbClusterControl(cli, tcInformer.Lister(), recorder)
-	pdControl := pdapi.NewDefaultPDControl(kubeCli)
-	tidbControl := controller.NewDefaultTiDBControl()
+	pdControl := pdapi.NewDefaultPDControl(secretInformer)
+	tidbControl := controller.NewDefaultTiDBControl(secretInformer)
 	setControl := controller.NewRealStatefuSetControl(kubeCli, setInformer.Lister(), recorder)
 	svcControl := controller.NewRealServiceControl(kubeCli, svcInformer.Lister(), recorder)
 	pvControl := controller.NewRealPVControl(kubeCli, pvcInformer.Lister(), pvInformer.Lister(), recorder)
 	pvcControl := controller.NewRealPVCControl(kubeCli, recorder, pvcInformer.Lister())
 	podControl := controller.NewRealPodControl(kubeCli, pdControl, podInformer.Lister(), recorder)
 	secControl := controller.NewRealSecretControl(kubeCli, secretInformer.Lister())
-	certControl := controller.NewRealCertControl(kubeCli, csrInformer.Lister(), secControl)
+	certControl := controller.NewRealCertControl(kubeCli, dynamicCli, csrInformer.Lister(), secControl)
 	cmControl := controller.NewRealConfigMapControl(kubeCli, cmInformer.Lister(), recorder)
 	pdScaler := mm.NewPDScaler(pdControl, pvcInformer.Lister(), pvcControl)
 	tikvScaler := mm.NewTiKVScaler(pdControl, pvcInformer.Lister(), pvcControl, podInformer.Lister())
+	tiflashScaler := mm.NewTiFlashScaler(pdControl, pvcInformer.Lister(), pvcControl, podInformer.Lister())
 	pdFailover := mm.NewPDFailover(cli, pdControl, pdFailoverPeriod, podInformer.Lister(), podControl, pvcInformer.Lister(), pvcControl, pvInformer.Lister())
 	tikvFailover := mm.NewTiKVFailover(tikvFailoverPeriod)
+	tiflashFailover := mm.NewTiFlashFailover(tikvFailoverPeriod)
 	tidbFailover := mm.NewTiDBFailover(tidbFailoverPeriod)
 	pdUpgrader := mm.NewPDUpgrader(pdControl, podControl, podInformer.Lister())
-	tikvUpgrader := mm.NewTiKVUpgrader(pdControl, podControl, podInformer.Lister())
-	tidbUpgrader := mm.NewTiDBUpgrader(tidbControl, podInformer.Lister())
+	tikvUpgrader := mm.NewTiKVUpgrader(pdControl, podControl, podInformer.Lister(), recorder)
+	tiflashUpgrader := mm.NewTiFlashUpgrader(podInformer.Lister(), recorder)
+	tidbUpgrader := mm.NewTiDBUpgrader(tidbControl, podControl, podInformer.Lister(), recorder)
 
 	tcc := &Controller{
 		kubeClient: kubeCli,
@@ -128,6 +134,7 @@ func NewController(
 				podInformer.Lister(),
 				epsInformer.Lister(),
 				pvcInformer.Lister(),
+				nodeInformer.Lister(),
 				pdScaler,
 				pdUpgrader,
 				autoFailover,
@@ -146,15 +153,36 @@ func NewController(
 				tikvFailover,
 				tikvScaler,
 				tikvUpgrader,
+				mm.NewTiKVStorageMigrator(pdControl, podInformer.Lister(), podControl, pvcInformer.Lister(), pvcControl, recorder),
+			),
+			mm.NewTiFlashMemberManager(
+				pdControl,
+				setControl,
+				svcControl,
+				cmControl,
+				certControl,
+				setInformer.Lister(),
+				svcInformer.Lister(),
+				cmInformer.Lister(),
+				podInformer.Lister(),
+				nodeInformer.Lister(),
+				autoFailover,
+				tiflashFailover,
+				tiflashScaler,
+				tiflashUpgrader,
 			),
 			mm.NewTiDBMemberManager(
 				setControl,
 				svcControl,
+				podControl,
 				tidbControl,
+				pdControl,
 				certControl,
+				cmControl,
 				setInformer.Lister(),
 				svcInformer.Lister(),
 				podInformer.Lister(),
+				cmInformer.Lister(),
 				tidbUpgrader,
 				autoFailover,
 				tidbFailover,
@@ -187,13 +215,31 @@ func NewController(
 				pvControl,
 			),
 			mm.NewPumpMemberManager(
+				pdControl,
 				setControl,
 				svcControl,
 				cmControl,
+				certControl,
 				setInformer.Lister(),
 				svcInformer.Lister(),
 				cmInformer.Lister(),
+				podInformer.Lister(),
+			),
+			mm.NewDrainerMemberManager(
+				pdControl,
+				controller.NewDefaultDrainerControl(secretInformer),
+				setControl,
+				svcControl,
+				cmControl,
+				certControl,
+				setInformer.Lister(),
+				svcInformer.Lister(),
+				cmInformer.Lister(),
+				podInformer.Lister(),
 			),
+			mm.NewTLSCertManager(certControl, svcInformer.Lister(), recorder),
+			mm.NewPVCResizer(pvcInformer.Lister(), podInformer.Lister(), scInformer.Lister(), pvcControl, recorder),
+			mm.NewMemberReplacer(pdControl, podInformer.Lister(), podControl, pvcInformer.Lister(), pvcControl, recorder),
 			recorder,
 		),
 		queue: workqueue.NewNamedRateLimitingQueue(
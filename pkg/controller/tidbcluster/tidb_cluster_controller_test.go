@@ -27,7 +27,9 @@ import (
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
 	kubeinformers "k8s.io/client-go/informers"
 	kubefake "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/tools/cache"
@@ -272,6 +274,7 @@ func newFakeTidbClusterController() (*Controller, cache.Indexer, *FakeTidbCluste
 	tcc := NewController(
 		kubeCli,
 		cli,
+		dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()),
 		informerFactory,
 		kubeInformerFactory,
 		autoFailover,
@@ -0,0 +1,128 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned"
+	tcinformers "github.com/pingcap/tidb-operator/pkg/client/informers/externalversions/pingcap/v1alpha1"
+	listers "github.com/pingcap/tidb-operator/pkg/client/listers/pingcap/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	glog "k8s.io/klog"
+)
+
+// TidbClusterAutoScalerControlInterface manages TidbClusterAutoScalers
+type TidbClusterAutoScalerControlInterface interface {
+	UpdateTidbClusterAutoScaler(*v1alpha1.TidbClusterAutoScaler) (*v1alpha1.TidbClusterAutoScaler, error)
+}
+
+type realTidbClusterAutoScalerControl struct {
+	cli       versioned.Interface
+	tacLister listers.TidbClusterAutoScalerLister
+	recorder  record.EventRecorder
+}
+
+// NewRealTidbClusterAutoScalerControl creates a new TidbClusterAutoScalerControlInterface
+func NewRealTidbClusterAutoScalerControl(cli versioned.Interface,
+	tacLister listers.TidbClusterAutoScalerLister,
+	recorder record.EventRecorder) TidbClusterAutoScalerControlInterface {
+	return &realTidbClusterAutoScalerControl{
+		cli,
+		tacLister,
+		recorder,
+	}
+}
+
+func (rtac *realTidbClusterAutoScalerControl) UpdateTidbClusterAutoScaler(tac *v1alpha1.TidbClusterAutoScaler) (*v1alpha1.TidbClusterAutoScaler, error) {
+	ns := tac.GetNamespace()
+	tacName := tac.GetName()
+
+	status := tac.Status.DeepCopy()
+	var updateTAC *v1alpha1.TidbClusterAutoScaler
+
+	// don't wait due to limited number of clients, but backoff after the default number of steps
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var updateErr error
+		updateTAC, updateErr = rtac.cli.PingcapV1alpha1().TidbClusterAutoScalers(ns).Update(tac)
+		if updateErr == nil {
+			glog.Infof("TidbClusterAutoScaler: [%s/%s] updated successfully", ns, tacName)
+			return nil
+		}
+		glog.Errorf("failed to update TidbClusterAutoScaler: [%s/%s], error: %v", ns, tacName, updateErr)
+
+		if updated, err := rtac.tacLister.TidbClusterAutoScalers(ns).Get(tacName); err == nil {
+			// make a copy so we don't mutate the shared cache
+			tac = updated.DeepCopy()
+			tac.Status = *status
+		} else {
+			utilruntime.HandleError(fmt.Errorf("error getting updated TidbClusterAutoScaler %s/%s from lister: %v", ns, tacName, err))
+		}
+
+		return updateErr
+	})
+	if err != nil {
+		rtac.recordTidbClusterAutoScalerEvent("update", tac, err)
+	}
+	return updateTAC, err
+}
+
+func (rtac *realTidbClusterAutoScalerControl) recordTidbClusterAutoScalerEvent(verb string, tac *v1alpha1.TidbClusterAutoScaler, err error) {
+	tacName := tac.GetName()
+	reason := fmt.Sprintf("Failed%s", strings.Title(verb))
+	msg := fmt.Sprintf("%s TidbClusterAutoScaler %s failed error: %s",
+		strings.ToLower(verb), tacName, err)
+	rtac.recorder.Event(tac, corev1.EventTypeWarning, reason, msg)
+}
+
+// FakeTidbClusterAutoScalerControl is a fake TidbClusterAutoScalerControlInterface
+type FakeTidbClusterAutoScalerControl struct {
+	TacLister                          listers.TidbClusterAutoScalerLister
+	TacIndexer                         cache.Indexer
+	updateTidbClusterAutoScalerTracker RequestTracker
+}
+
+// NewFakeTidbClusterAutoScalerControl returns a FakeTidbClusterAutoScalerControl
+func NewFakeTidbClusterAutoScalerControl(tacInformer tcinformers.TidbClusterAutoScalerInformer) *FakeTidbClusterAutoScalerControl {
+	return &FakeTidbClusterAutoScalerControl{
+		tacInformer.Lister(),
+		tacInformer.Informer().GetIndexer(),
+		RequestTracker{},
+	}
+}
+
+// SetUpdateTidbClusterAutoScalerError sets the error attributes of updateTidbClusterAutoScalerTracker
+func (ftac *FakeTidbClusterAutoScalerControl) SetUpdateTidbClusterAutoScalerError(err error, after int) {
+	ftac.updateTidbClusterAutoScalerTracker.SetError(err).SetAfter(after)
+}
+
+// UpdateTidbClusterAutoScaler updates the TidbClusterAutoScaler
+func (ftac *FakeTidbClusterAutoScalerControl) UpdateTidbClusterAutoScaler(tac *v1alpha1.TidbClusterAutoScaler) (*v1alpha1.TidbClusterAutoScaler, error) {
+	defer ftac.updateTidbClusterAutoScalerTracker.Inc()
+	if ftac.updateTidbClusterAutoScalerTracker.ErrorReady() {
+		defer ftac.updateTidbClusterAutoScalerTracker.Reset()
+		return tac, ftac.updateTidbClusterAutoScalerTracker.GetError()
+	}
+
+	return tac, ftac.TacIndexer.Update(tac)
+}
+
+var _ TidbClusterAutoScalerControlInterface = &realTidbClusterAutoScalerControl{}
+var _ TidbClusterAutoScalerControlInterface = &FakeTidbClusterAutoScalerControl{}
@@ -0,0 +1,46 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbmonitor
+
+import (
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/monitor"
+)
+
+// ControlInterface implements the control logic for updating TidbMonitor
+// It is implemented as an interface to allow for extensions that provide different semantics.
+// Currently, there is only one implementation.
+type ControlInterface interface {
+	// UpdateTidbMonitor implements the control logic for the monitor Deployment and the resources it depends on
+	UpdateTidbMonitor(tm *v1alpha1.TidbMonitor) error
+}
+
+// NewDefaultTidbMonitorControl returns a new instance of the default implementation ControlInterface that
+// implements the documented semantics for TidbMonitor.
+func NewDefaultTidbMonitorControl(monitorManager monitor.MonitorManager) ControlInterface {
+	return &defaultTidbMonitorControl{
+		monitorManager,
+	}
+}
+
+type defaultTidbMonitorControl struct {
+	monitorManager monitor.MonitorManager
+}
+
+// UpdateTidbMonitor executes the core logic loop for a TidbMonitor.
+func (tmc *defaultTidbMonitorControl) UpdateTidbMonitor(tm *v1alpha1.TidbMonitor) error {
+	tm.SetGroupVersionKind(controller.TidbMonitorControllerKind)
+	return tmc.monitorManager.Sync(tm)
+}
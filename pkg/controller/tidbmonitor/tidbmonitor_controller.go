@@ -0,0 +1,236 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tidbmonitor
+
+import (
+	"fmt"
+	"time"
+
+	perrors "github.com/pingcap/errors"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned"
+	informers "github.com/pingcap/tidb-operator/pkg/client/informers/externalversions"
+	listers "github.com/pingcap/tidb-operator/pkg/client/listers/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	mm "github.com/pingcap/tidb-operator/pkg/monitor/monitor"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	eventv1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	glog "k8s.io/klog"
+)
+
+// Controller controls tidbmonitor.
+type Controller struct {
+	// kubernetes client interface
+	kubeClient kubernetes.Interface
+	// operator client interface
+	cli versioned.Interface
+	// control returns an interface capable of syncing a tidbmonitor.
+	// Abstracted out for testing.
+	control ControlInterface
+	// tmLister is able to list/get tidbmonitor from a shared informer's store
+	tmLister listers.TidbMonitorLister
+	// tmListerSynced returns true if the tidbmonitor shared informer has synced at least once
+	tmListerSynced cache.InformerSynced
+	// tidbmonitors that need to be synced.
+	queue workqueue.RateLimitingInterface
+}
+
+// NewController creates a tidbmonitor controller.
+func NewController(
+	kubeCli kubernetes.Interface,
+	cli versioned.Interface,
+	informerFactory informers.SharedInformerFactory,
+	kubeInformerFactory kubeinformers.SharedInformerFactory,
+) *Controller {
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartLogging(glog.Infof)
+	eventBroadcaster.StartRecordingToSink(&eventv1.EventSinkImpl{
+		Interface: eventv1.New(kubeCli.CoreV1().RESTClient()).Events("")})
+	recorder := eventBroadcaster.NewRecorder(v1alpha1.Scheme, corev1.EventSource{Component: "tidbmonitor"})
+
+	tmInformer := informerFactory.Pingcap().V1alpha1().TidbMonitors()
+	tcInformer := informerFactory.Pingcap().V1alpha1().TidbClusters()
+	podInformer := kubeInformerFactory.Core().V1().Pods()
+	secretInformer := kubeInformerFactory.Core().V1().Secrets()
+	cmInformer := kubeInformerFactory.Core().V1().ConfigMaps()
+	svcInformer := kubeInformerFactory.Core().V1().Services()
+	deployInformer := kubeInformerFactory.Apps().V1().Deployments()
+	pvcInformer := kubeInformerFactory.Core().V1().PersistentVolumeClaims()
+	generalControl := controller.NewRealGeneralControl(kubeCli, recorder)
+	pvcControl := controller.NewRealGeneralPVCControl(kubeCli, recorder)
+	tmControl := controller.NewRealTidbMonitorControl(cli, tmInformer.Lister(), recorder)
+
+	tmc := &Controller{
+		kubeClient: kubeCli,
+		cli:        cli,
+		control: NewDefaultTidbMonitorControl(
+			mm.NewMonitorManager(
+				tcInformer.Lister(),
+				podInformer.Lister(),
+				secretInformer.Lister(),
+				cmInformer.Lister(),
+				svcInformer.Lister(),
+				deployInformer.Lister(),
+				pvcInformer.Lister(),
+				generalControl,
+				pvcControl,
+				tmControl,
+			),
+		),
+		queue: workqueue.NewNamedRateLimitingQueue(
+			workqueue.DefaultControllerRateLimiter(),
+			"tidbmonitor",
+		),
+	}
+
+	tmInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: tmc.enqueueTidbMonitor,
+		UpdateFunc: func(old, cur interface{}) {
+			tmc.enqueueTidbMonitor(cur)
+		},
+		DeleteFunc: tmc.enqueueTidbMonitor,
+	})
+	// the scrape targets follow the Pods of the monitored clusters, which are reflected in the
+	// status of the TidbCluster
+	tcInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: tmc.enqueueTidbMonitorsOfCluster,
+		UpdateFunc: func(old, cur interface{}) {
+			tmc.enqueueTidbMonitorsOfCluster(cur)
+		},
+		DeleteFunc: tmc.enqueueTidbMonitorsOfCluster,
+	})
+	tmc.tmLister = tmInformer.Lister()
+	tmc.tmListerSynced = tmInformer.Informer().HasSynced
+
+	return tmc
+}
+
+// Run runs the tidbmonitor controller.
+func (tmc *Controller) Run(workers int, stopCh <-chan struct{}) {
+	defer utilruntime.HandleCrash()
+	defer tmc.queue.ShutDown()
+
+	glog.Info("Starting tidbmonitor controller")
+	defer glog.Info("Shutting down tidbmonitor controller")
+
+	for i := 0; i < workers; i++ {
+		go wait.Until(tmc.worker, time.Second, stopCh)
+	}
+
+	<-stopCh
+}
+
+// worker runs a worker goroutine that invokes processNextWorkItem until the the controller's queue is closed
+func (tmc *Controller) worker() {
+	for tmc.processNextWorkItem() {
+		// revive:disable:empty-block
+	}
+}
+
+// processNextWorkItem dequeues items, processes them, and marks them done. It enforces that the syncHandler is never
+// invoked concurrently with the same key.
+func (tmc *Controller) processNextWorkItem() bool {
+	key, quit := tmc.queue.Get()
+	if quit {
+		return false
+	}
+	defer tmc.queue.Done(key)
+	if err := tmc.sync(key.(string)); err != nil {
+		if perrors.Find(err, controller.IsRequeueError) != nil {
+			glog.Infof("TidbMonitor: %v, still need sync: %v, requeuing", key.(string), err)
+		} else {
+			utilruntime.HandleError(fmt.Errorf("TidbMonitor: %v, sync failed, err: %v, requeuing", key.(string), err))
+		}
+		tmc.queue.AddRateLimited(key)
+	} else {
+		tmc.queue.Forget(key)
+	}
+	return true
+}
+
+// sync syncs the given tidbmonitor.
+func (tmc *Controller) sync(key string) error {
+	startTime := time.Now()
+	defer func() {
+		glog.V(4).Infof("Finished syncing TidbMonitor %q (%v)", key, time.Since(startTime))
+	}()
+
+	ns, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+	tm, err := tmc.tmLister.TidbMonitors(ns).Get(name)
+	if errors.IsNotFound(err) {
+		glog.Infof("TidbMonitor has been deleted %v", key)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return tmc.syncTidbMonitor(tm.DeepCopy())
+}
+
+func (tmc *Controller) syncTidbMonitor(tm *v1alpha1.TidbMonitor) error {
+	return tmc.control.UpdateTidbMonitor(tm)
+}
+
+// enqueueTidbMonitor enqueues the given tidbmonitor in the work queue.
+func (tmc *Controller) enqueueTidbMonitor(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("Cound't get key for object %+v: %v", obj, err))
+		return
+	}
+	tmc.queue.Add(key)
+}
+
+// enqueueTidbMonitorsOfCluster enqueues the tidbmonitors monitoring the given tidbcluster.
+func (tmc *Controller) enqueueTidbMonitorsOfCluster(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	tc, ok := obj.(*v1alpha1.TidbCluster)
+	if !ok {
+		return
+	}
+
+	tms, err := tmc.tmLister.List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("Cound't list tidbmonitors for tidbcluster %s/%s: %v", tc.GetNamespace(), tc.GetName(), err))
+		return
+	}
+	for _, tm := range tms {
+		for _, ref := range tm.Spec.Clusters {
+			ns := ref.Namespace
+			if ns == "" {
+				ns = tm.GetNamespace()
+			}
+			if ns == tc.GetNamespace() && ref.Name == tc.GetName() {
+				glog.V(4).Infof("tidbmonitor %s/%s enqueued for tidbcluster %s/%s", tm.GetNamespace(), tm.GetName(), ns, ref.Name)
+				tmc.enqueueTidbMonitor(tm)
+				break
+			}
+		}
+	}
+}
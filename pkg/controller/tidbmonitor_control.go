@@ -0,0 +1,128 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned"
+	tcinformers "github.com/pingcap/tidb-operator/pkg/client/informers/externalversions/pingcap/v1alpha1"
+	listers "github.com/pingcap/tidb-operator/pkg/client/listers/pingcap/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	glog "k8s.io/klog"
+)
+
+// TidbMonitorControlInterface manages TidbMonitors
+type TidbMonitorControlInterface interface {
+	UpdateTidbMonitor(*v1alpha1.TidbMonitor) (*v1alpha1.TidbMonitor, error)
+}
+
+type realTidbMonitorControl struct {
+	cli      versioned.Interface
+	tmLister listers.TidbMonitorLister
+	recorder record.EventRecorder
+}
+
+// NewRealTidbMonitorControl creates a new TidbMonitorControlInterface
+func NewRealTidbMonitorControl(cli versioned.Interface,
+	tmLister listers.TidbMonitorLister,
+	recorder record.EventRecorder) TidbMonitorControlInterface {
+	return &realTidbMonitorControl{
+		cli,
+		tmLister,
+		recorder,
+	}
+}
+
+func (rtm *realTidbMonitorControl) UpdateTidbMonitor(tm *v1alpha1.TidbMonitor) (*v1alpha1.TidbMonitor, error) {
+	ns := tm.GetNamespace()
+	tmName := tm.GetName()
+
+	status := tm.Status.DeepCopy()
+	var updateTM *v1alpha1.TidbMonitor
+
+	// don't wait due to limited number of clients, but backoff after the default number of steps
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var updateErr error
+		updateTM, updateErr = rtm.cli.PingcapV1alpha1().TidbMonitors(ns).Update(tm)
+		if updateErr == nil {
+			glog.Infof("TidbMonitor: [%s/%s] updated successfully", ns, tmName)
+			return nil
+		}
+		glog.Errorf("failed to update TidbMonitor: [%s/%s], error: %v", ns, tmName, updateErr)
+
+		if updated, err := rtm.tmLister.TidbMonitors(ns).Get(tmName); err == nil {
+			// make a copy so we don't mutate the shared cache
+			tm = updated.DeepCopy()
+			tm.Status = *status
+		} else {
+			utilruntime.HandleError(fmt.Errorf("error getting updated TidbMonitor %s/%s from lister: %v", ns, tmName, err))
+		}
+
+		return updateErr
+	})
+	if err != nil {
+		rtm.recordTidbMonitorEvent("update", tm, err)
+	}
+	return updateTM, err
+}
+
+func (rtm *realTidbMonitorControl) recordTidbMonitorEvent(verb string, tm *v1alpha1.TidbMonitor, err error) {
+	tmName := tm.GetName()
+	reason := fmt.Sprintf("Failed%s", strings.Title(verb))
+	msg := fmt.Sprintf("%s TidbMonitor %s failed error: %s",
+		strings.ToLower(verb), tmName, err)
+	rtm.recorder.Event(tm, corev1.EventTypeWarning, reason, msg)
+}
+
+// FakeTidbMonitorControl is a fake TidbMonitorControlInterface
+type FakeTidbMonitorControl struct {
+	TmLister                 listers.TidbMonitorLister
+	TmIndexer                cache.Indexer
+	updateTidbMonitorTracker RequestTracker
+}
+
+// NewFakeTidbMonitorControl returns a FakeTidbMonitorControl
+func NewFakeTidbMonitorControl(tmInformer tcinformers.TidbMonitorInformer) *FakeTidbMonitorControl {
+	return &FakeTidbMonitorControl{
+		tmInformer.Lister(),
+		tmInformer.Informer().GetIndexer(),
+		RequestTracker{},
+	}
+}
+
+// SetUpdateTidbMonitorError sets the error attributes of updateTidbMonitorTracker
+func (ftm *FakeTidbMonitorControl) SetUpdateTidbMonitorError(err error, after int) {
+	ftm.updateTidbMonitorTracker.SetError(err).SetAfter(after)
+}
+
+// UpdateTidbMonitor updates the TidbMonitor
+func (ftm *FakeTidbMonitorControl) UpdateTidbMonitor(tm *v1alpha1.TidbMonitor) (*v1alpha1.TidbMonitor, error) {
+	defer ftm.updateTidbMonitorTracker.Inc()
+	if ftm.updateTidbMonitorTracker.ErrorReady() {
+		defer ftm.updateTidbMonitorTracker.Reset()
+		return tm, ftm.updateTidbMonitorTracker.GetError()
+	}
+
+	return tm, ftm.TmIndexer.Update(tm)
+}
+
+var _ TidbMonitorControlInterface = &realTidbMonitorControl{}
+var _ TidbMonitorControlInterface = &FakeTidbMonitorControl{}
@@ -0,0 +1,145 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/pingcap/kvproto/pkg/import_sstpb"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	errorutils "k8s.io/apimachinery/pkg/util/errors"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+)
+
+// TiKVControlInterface is the interface that knows how to manage the TiKV stores
+type TiKVControlInterface interface {
+	// SwitchMode switches the TiKV stores of spec.tikv and spec.tikvGroups of the cluster to the mode, the stores
+	// in import mode write the ingested SST files without throttling.
+	SwitchMode(tc *v1alpha1.TidbCluster, mode import_sstpb.SwitchMode) error
+}
+
+// defaultTiKVControl is default implementation of TiKVControlInterface.
+type defaultTiKVControl struct {
+	pdControl  pdapi.PDControlInterface
+	tlsConfigs *pdapi.TLSConfigCache
+}
+
+// NewDefaultTiKVControl returns a defaultTiKVControl instance
+func NewDefaultTiKVControl(secretInformer coreinformers.SecretInformer, pdControl pdapi.PDControlInterface) TiKVControlInterface {
+	return &defaultTiKVControl{pdControl: pdControl, tlsConfigs: pdapi.NewTLSConfigCache(secretInformer)}
+}
+
+func (tkc *defaultTiKVControl) SwitchMode(tc *v1alpha1.TidbCluster, mode import_sstpb.SwitchMode) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	storesInfo, err := GetPDClient(tkc.pdControl, tc).GetStores()
+	if err != nil {
+		return err
+	}
+
+	dialOpt := grpc.WithInsecure()
+	if tc.Spec.EnableTLSCluster {
+		tlsConfig, _, err := tkc.tlsConfigs.Get(pdapi.Namespace(ns), tcName)
+		if err != nil {
+			return err
+		}
+		dialOpt = grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))
+	}
+
+	var errs []error
+	for _, store := range importModeStores(tc, storesInfo) {
+		if err := switchStoreMode(store.GetAddress(), dialOpt, mode); err != nil {
+			errs = append(errs, fmt.Errorf("failed to switch store %d of tidbcluster %s/%s to %s mode, %v",
+				store.GetId(), ns, tcName, mode, err))
+		}
+	}
+	return errorutils.NewAggregate(errs)
+}
+
+// importModeStores returns the up stores of spec.tikv and spec.tikvGroups of tc among the stores known by PD.
+// The TiFlash stores and the stores of the other clusters sharing the PD are not switched.
+func importModeStores(tc *v1alpha1.TidbCluster, storesInfo *pdapi.StoresInfo) []*metapb.Store {
+	var stores []*metapb.Store
+	for _, store := range storesInfo.Stores {
+		if store.Store == nil || store.Store.StateName != v1alpha1.TiKVStateUp {
+			continue
+		}
+		id := strconv.FormatUint(store.Store.GetId(), 10)
+		if !isTiKVStoreOf(tc, id) {
+			continue
+		}
+		stores = append(stores, store.Store.Store)
+	}
+	return stores
+}
+
+func isTiKVStoreOf(tc *v1alpha1.TidbCluster, id string) bool {
+	if _, ok := tc.Status.TiKV.Stores[id]; ok {
+		return true
+	}
+	for _, status := range tc.Status.TiKVGroups {
+		if _, ok := status.Stores[id]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func switchStoreMode(addr string, dialOpt grpc.DialOption, mode import_sstpb.SwitchMode) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, addr, dialOpt, grpc.WithBlock())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = import_sstpb.NewImportSSTClient(conn).SwitchMode(ctx, &import_sstpb.SwitchModeRequest{Mode: mode})
+	return err
+}
+
+// FakeTiKVControl is a fake implementation of TiKVControlInterface.
+type FakeTiKVControl struct {
+	modes map[string]import_sstpb.SwitchMode
+	err   error
+}
+
+// NewFakeTiKVControl returns a FakeTiKVControl instance
+func NewFakeTiKVControl() *FakeTiKVControl {
+	return &FakeTiKVControl{modes: map[string]import_sstpb.SwitchMode{}}
+}
+
+// SetSwitchModeError sets the error returned by SwitchMode
+func (ftc *FakeTiKVControl) SetSwitchModeError(err error) {
+	ftc.err = err
+}
+
+// GetMode returns the mode the TiKV stores of the cluster are switched to
+func (ftc *FakeTiKVControl) GetMode(tc *v1alpha1.TidbCluster) (import_sstpb.SwitchMode, bool) {
+	mode, ok := ftc.modes[fmt.Sprintf("%s/%s", tc.GetNamespace(), tc.GetName())]
+	return mode, ok
+}
+
+func (ftc *FakeTiKVControl) SwitchMode(tc *v1alpha1.TidbCluster, mode import_sstpb.SwitchMode) error {
+	if ftc.err != nil {
+		return ftc.err
+	}
+	ftc.modes[fmt.Sprintf("%s/%s", tc.GetNamespace(), tc.GetName())] = mode
+	return nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+)
+
+func TestImportModeStores(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbCluster()
+	tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+		"1": {ID: "1"},
+		"2": {ID: "2"},
+	}
+	tc.Status.TiKVGroups = map[string]v1alpha1.TiKVStatus{
+		"hot": {Stores: map[string]v1alpha1.TiKVStore{"3": {ID: "3"}}},
+	}
+	tc.Status.TiFlash.Stores = map[string]v1alpha1.TiKVStore{"4": {ID: "4"}}
+
+	newStore := func(id uint64, state string) *pdapi.StoreInfo {
+		return &pdapi.StoreInfo{Store: &pdapi.MetaStore{Store: &metapb.Store{Id: id}, StateName: state}}
+	}
+	storesInfo := &pdapi.StoresInfo{Stores: []*pdapi.StoreInfo{
+		newStore(1, v1alpha1.TiKVStateUp),
+		// the store is down
+		newStore(2, v1alpha1.TiKVStateDown),
+		// the store of a TiKV group
+		newStore(3, v1alpha1.TiKVStateUp),
+		// the TiFlash store
+		newStore(4, v1alpha1.TiKVStateUp),
+		// the store of another cluster sharing the PD
+		newStore(5, v1alpha1.TiKVStateUp),
+		{},
+	}}
+
+	var ids []uint64
+	for _, store := range importModeStores(tc, storesInfo) {
+		ids = append(ids, store.GetId())
+	}
+	g.Expect(ids).To(Equal([]uint64{1, 3}))
+}
@@ -15,6 +15,7 @@ package discovery
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"strings"
 	"sync"
@@ -24,7 +25,7 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/pdapi"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/kubernetes"
+	coreinformers "k8s.io/client-go/informers/core/v1"
 	glog "k8s.io/klog"
 )
 
@@ -47,11 +48,12 @@ type clusterInfo struct {
 	peers           map[string]struct{}
 }
 
-// NewTiDBDiscovery returns a TiDBDiscovery
-func NewTiDBDiscovery(cli versioned.Interface, kubeCli kubernetes.Interface) TiDBDiscovery {
+// NewTiDBDiscovery returns a TiDBDiscovery, the certificates of the TLS enabled clusters are read
+// from the Secrets in secretInformer
+func NewTiDBDiscovery(cli versioned.Interface, secretInformer coreinformers.SecretInformer) TiDBDiscovery {
 	td := &tidbDiscovery{
 		cli:       cli,
-		pdControl: pdapi.NewDefaultPDControl(kubeCli),
+		pdControl: pdapi.NewDefaultPDControl(secretInformer),
 		clusters:  map[string]*clusterInfo{},
 	}
 	td.tcGetFn = td.realTCGetFn
@@ -66,12 +68,11 @@ func (td *tidbDiscovery) Discover(advertisePeerUrl string) (string, error) {
 		return "", fmt.Errorf("advertisePeerUrl is empty")
 	}
 	glog.Infof("advertisePeerUrl is: %s", advertisePeerUrl)
-	strArr := strings.Split(advertisePeerUrl, ".")
-	if len(strArr) != 4 {
-		return "", fmt.Errorf("advertisePeerUrl format is wrong: %s", advertisePeerUrl)
+	podName, peerServiceName, ns, err := parseAdvertisePeerURL(advertisePeerUrl)
+	if err != nil {
+		return "", err
 	}
 
-	podName, peerServiceName, ns := strArr[0], strArr[1], strArr[2]
 	tcName := strings.TrimSuffix(peerServiceName, "-pd-peer")
 	podNamespace := os.Getenv("MY_POD_NAMESPACE")
 	if ns != podNamespace {
@@ -81,6 +82,13 @@ func (td *tidbDiscovery) Discover(advertisePeerUrl string) (string, error) {
 	if err != nil {
 		return "", err
 	}
+
+	// the members of a cluster joining another cluster never bootstrap a new PD cluster
+	if ref := tc.ClusterRef(); ref != nil {
+		glog.Infof("%s joins the PD cluster of tidbcluster %s/%s in cluster domain %q", podName, ref.Namespace, ref.Name, ref.ClusterDomain)
+		return td.join(controller.GetClusterRefPDClient(td.pdControl, tc), podName)
+	}
+
 	keyName := fmt.Sprintf("%s/%s", ns, tcName)
 	// TODO: the replicas should be the total replicas of pd sets.
 	replicas := tc.Spec.PD.Replicas
@@ -100,7 +108,16 @@ func (td *tidbDiscovery) Discover(advertisePeerUrl string) (string, error) {
 		return fmt.Sprintf("--initial-cluster=%s=%s://%s", podName, tc.Scheme(), advertisePeerUrl), nil
 	}
 
-	pdClient := td.pdControl.GetPDClient(pdapi.Namespace(tc.GetNamespace()), tc.GetName(), tc.Spec.EnableTLSCluster)
+	result, err := td.join(td.pdControl.GetPDClient(pdapi.Namespace(tc.GetNamespace()), tc.GetName(), tc.Spec.EnableTLSCluster), podName)
+	if err != nil {
+		return "", err
+	}
+	delete(currentCluster.peers, podName)
+	return result, nil
+}
+
+// join returns the arguments for the PD member named podName to join the PD cluster of pdClient
+func (td *tidbDiscovery) join(pdClient pdapi.PDClient, podName string) (string, error) {
 	membersInfo, err := pdClient.GetMembers()
 	if err != nil {
 		return "", err
@@ -108,13 +125,30 @@ func (td *tidbDiscovery) Discover(advertisePeerUrl string) (string, error) {
 
 	membersArr := make([]string, 0)
 	for _, member := range membersInfo.Members {
+		// the members of the clusters in other namespaces or Kubernetes clusters may be named after the same pod name
+		if member.GetName() == podName {
+			return "", fmt.Errorf("the PD cluster already has a member named %s with peer urls %v", podName, member.PeerUrls)
+		}
 		memberURL := strings.ReplaceAll(member.PeerUrls[0], ":2380", ":2379")
 		membersArr = append(membersArr, memberURL)
 	}
-	delete(currentCluster.peers, podName)
 	return fmt.Sprintf("--join=%s", strings.Join(membersArr, ",")), nil
 }
 
+// parseAdvertisePeerURL parses the advertise peer url of a PD member, which is <pod>.<peer service>.<namespace>.svc:2380,
+// or <pod>.<peer service>.<namespace>.svc.<cluster domain>:2380 if the cluster domain is set
+func parseAdvertisePeerURL(advertisePeerUrl string) (podName, peerServiceName, ns string, err error) {
+	host, _, err := net.SplitHostPort(advertisePeerUrl)
+	if err != nil {
+		return "", "", "", fmt.Errorf("advertisePeerUrl format is wrong: %s, %v", advertisePeerUrl, err)
+	}
+	strArr := strings.SplitN(host, ".", 4)
+	if len(strArr) != 4 || (strArr[3] != "svc" && !strings.HasPrefix(strArr[3], "svc.")) {
+		return "", "", "", fmt.Errorf("advertisePeerUrl format is wrong: %s", advertisePeerUrl)
+	}
+	return strArr[0], strArr[1], strArr[2], nil
+}
+
 func (td *tidbDiscovery) realTCGetFn(ns, tcName string) (*v1alpha1.TidbCluster, error) {
 	return td.cli.PingcapV1alpha1().TidbClusters(ns).Get(tcName, metav1.GetOptions{})
 }
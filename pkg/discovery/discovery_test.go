@@ -48,6 +48,7 @@ func TestDiscoveryDiscovery(t *testing.T) {
 		tc, err := test.tcFn()
 		if err == nil {
 			fakePDControl.SetPDClient(pdapi.Namespace(tc.GetNamespace()), tc.GetName(), pdClient)
+			fakePDControl.SetPDClientForHost(pdapi.Namespace(tc.GetNamespace()), tc.GetName(), "origin-pd.other.svc.cluster2.com:2379", pdClient)
 		}
 		pdClient.AddReaction(pdapi.GetMembersActionType, func(action *pdapi.Action) (interface{}, error) {
 			return test.getMembersFn()
@@ -396,6 +397,58 @@ func TestDiscoveryDiscovery(t *testing.T) {
 				g.Expect(s).To(Equal("--join=demo-pd-0.demo-pd-peer.default.svc:2379,demo-pd-1.demo-pd-peer.default.svc:2379,demo-pd-2.demo-pd-peer.default.svc:2379,demo-pd-3.demo-pd-peer.default.svc:2379"))
 			},
 		},
+		{
+			name: "advertisePeerUrl with cluster domain joins the referenced cluster",
+			ns:   "default",
+			url:  "demo-pd-0.demo-pd-peer.default.svc.cluster1.com:2380",
+			tcFn: func() (*v1alpha1.TidbCluster, error) {
+				tc, _ := newTC()
+				tc.Spec.ClusterDomain = "cluster1.com"
+				tc.Spec.Cluster = &v1alpha1.TidbClusterRef{Namespace: "other", Name: "origin", ClusterDomain: "cluster2.com"}
+				return tc, nil
+			},
+			getMembersFn: func() (*pdapi.MembersInfo, error) {
+				return &pdapi.MembersInfo{
+					Members: []*pdpb.Member{
+						{
+							Name:     "origin-pd-0",
+							PeerUrls: []string{"origin-pd-0.origin-pd-peer.other.svc.cluster2.com:2380"},
+						},
+					},
+				}, nil
+			},
+			clusters: map[string]*clusterInfo{},
+			expectFn: func(g *GomegaWithT, td *tidbDiscovery, s string, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(len(td.clusters)).To(BeZero())
+				g.Expect(s).To(Equal("--join=origin-pd-0.origin-pd-peer.other.svc.cluster2.com:2379"))
+			},
+		},
+		{
+			name: "member name conflicts with the referenced cluster",
+			ns:   "default",
+			url:  "demo-pd-0.demo-pd-peer.default.svc:2380",
+			tcFn: func() (*v1alpha1.TidbCluster, error) {
+				tc, _ := newTC()
+				tc.Spec.Cluster = &v1alpha1.TidbClusterRef{Namespace: "other", Name: "origin", ClusterDomain: "cluster2.com"}
+				return tc, nil
+			},
+			getMembersFn: func() (*pdapi.MembersInfo, error) {
+				return &pdapi.MembersInfo{
+					Members: []*pdpb.Member{
+						{
+							Name:     "demo-pd-0",
+							PeerUrls: []string{"demo-pd-0.demo-pd-peer.other.svc.cluster2.com:2380"},
+						},
+					},
+				}, nil
+			},
+			clusters: map[string]*clusterInfo{},
+			expectFn: func(g *GomegaWithT, td *tidbDiscovery, s string, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring("already has a member named demo-pd-0"))
+			},
+		},
 	}
 	for i := range tests {
 		testFn(&tests[i], t)
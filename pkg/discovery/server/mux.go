@@ -20,9 +20,7 @@ import (
 	"net/http"
 
 	restful "github.com/emicklei/go-restful"
-	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned"
 	"github.com/pingcap/tidb-operator/pkg/discovery"
-	"k8s.io/client-go/kubernetes"
 	glog "k8s.io/klog"
 )
 
@@ -31,8 +29,8 @@ type server struct {
 }
 
 // StartServer starts a TiDB Discovery server
-func StartServer(cli versioned.Interface, kubeCli kubernetes.Interface, port int) {
-	svr := &server{discovery.NewTiDBDiscovery(cli, kubeCli)}
+func StartServer(td discovery.TiDBDiscovery, port int) {
+	svr := &server{td}
 
 	ws := new(restful.WebService)
 	ws.Route(ws.GET("/new/{advertise-peer-url}").To(svr.newHandler))
@@ -33,7 +33,8 @@ var (
 )
 
 const (
-	// StableScheduling controls stable scheduling of TiDB members.
+	// StableScheduling is the default of the stable scheduling of TiDB members, which is
+	// overridden by spec.tidb.stableScheduling of the TidbCluster.
 	StableScheduling string = "StableScheduling"
 
 	// AdvancedStatefulSet controls whether to use AdvancedStatefulSet to manage pods
@@ -45,6 +45,12 @@ const (
 	StoreIDLabelKey string = "tidb.pingcap.com/store-id"
 	// MemberIDLabelKey is member id label key
 	MemberIDLabelKey string = "tidb.pingcap.com/member-id"
+	// TiKVGroupLabelKey is the label key of the TiKV group a TiKV pod belongs to
+	TiKVGroupLabelKey string = "tidb.pingcap.com/tikv-group"
+	// TiDBGroupLabelKey is the label key of the TiDB group a TiDB pod belongs to
+	TiDBGroupLabelKey string = "tidb.pingcap.com/tidb-group"
+	// DrainerLabelKey is the label key of the name of the drainer a drainer pod belongs to
+	DrainerLabelKey string = "tidb.pingcap.com/drainer"
 
 	// BackupScheduleLabelKey is backup schedule key
 	BackupScheduleLabelKey string = "tidb.pingcap.com/backup-schedule"
@@ -55,6 +61,9 @@ const (
 	// RestoreLabelKey is restore key
 	RestoreLabelKey string = "tidb.pingcap.com/restore"
 
+	// DataImportLabelKey is data import key
+	DataImportLabelKey string = "tidb.pingcap.com/data-import"
+
 	// BackupProtectionFinalizer is the name of finalizer on backups
 	BackupProtectionFinalizer string = "tidb.pingcap.com/backup-protection"
 
@@ -71,6 +80,8 @@ const (
 	AnnTiDBPartition string = "tidb.pingcap.com/tidb-partition"
 	// AnnTiKVPartition is pod annotation which TiKV pod should upgrade to
 	AnnTiKVPartition string = "tidb.pingcap.com/tikv-partition"
+	// AnnUpgradeContinue is tc annotation key listing the update revisions approved to proceed past the canary
+	AnnUpgradeContinue = "tidb.pingcap.com/upgrade-continue"
 	// AnnForceUpgradeKey is tc annotation key to indicate whether force upgrade should be done
 	AnnForceUpgradeKey = "tidb.pingcap.com/force-upgrade"
 	// AnnPDDeferDeleting is pd pod annotation key  in pod for defer for deleting pod
@@ -79,6 +90,15 @@ const (
 	AnnSysctlInit = "tidb.pingcap.com/sysctl-init"
 	// AnnEvictLeaderBeginTime is pod annotation key to indicate the begin time for evicting region leader
 	AnnEvictLeaderBeginTime = "tidb.pingcap.com/evictLeaderBeginTime"
+	// AnnTiDBDrainBeginTime is pod annotation key to indicate the begin time for draining tidb connections
+	AnnTiDBDrainBeginTime = "tidb.pingcap.com/drain-begin-time"
+	// AnnTiDBDrainBy is pod annotation key to indicate who begins draining tidb connections
+	AnnTiDBDrainBy = "tidb.pingcap.com/drain-by"
+	// AnnTLSCertRenewTime is pod annotation key to indicate the last renew time of the certificates mounted by the pod
+	AnnTLSCertRenewTime = "tidb.pingcap.com/tls-cert-renew-time"
+	// AnnFileSystemResizeTime is pod annotation key to indicate the last time the pod was restarted
+	// to resize the file system of its volume
+	AnnFileSystemResizeTime = "tidb.pingcap.com/fs-resize-time"
 
 	// AnnForceUpgradeVal is tc annotation value to indicate whether force upgrade should be done
 	AnnForceUpgradeVal = "true"
@@ -91,8 +111,14 @@ const (
 	TiDBLabelVal string = "tidb"
 	// TiKVLabelVal is TiKV label value
 	TiKVLabelVal string = "tikv"
+	// TiFlashLabelVal is TiFlash label value
+	TiFlashLabelVal string = "tiflash"
 	// PumpLabelVal is Pump label value
 	PumpLabelVal string = "pump"
+	// DrainerLabelVal is Drainer label value
+	DrainerLabelVal string = "drainer"
+	// MonitorLabelVal is the label value of the monitor of TidbMonitor
+	MonitorLabelVal string = "monitor"
 
 	// CleanJobLabelVal is clean job label value
 	CleanJobLabelVal string = "clean"
@@ -100,6 +126,8 @@ const (
 	RestoreJobLabelVal string = "restore"
 	// BackupJobLabelVal is backup job label value
 	BackupJobLabelVal string = "backup"
+	// DataImportJobLabelVal is data import job label value
+	DataImportJobLabelVal string = "data-import"
 	// TiDBOperator is ManagedByLabelKey label value
 	TiDBOperator string = "tidb-operator"
 )
@@ -131,6 +159,22 @@ func NewRestore() Label {
 	}
 }
 
+// NewDataImport initialize a new Label for Jobs of data import
+func NewDataImport() Label {
+	return Label{
+		NameLabelKey:      "data-import",
+		ManagedByLabelKey: "data-import-operator",
+	}
+}
+
+// NewMonitor initialize a new Label for the monitor of TidbMonitor
+func NewMonitor() Label {
+	return Label{
+		NameLabelKey:      "tidb-monitor",
+		ManagedByLabelKey: TiDBOperator,
+	}
+}
+
 // NewBackupSchedule initialize a new Label for backups of bakcup schedule
 func NewBackupSchedule() Label {
 	return Label{
@@ -180,6 +224,12 @@ func (l Label) RestoreJob() Label {
 	return l
 }
 
+// DataImportJob assigns data-import to component key in label
+func (l Label) DataImportJob() Label {
+	l.Component(DataImportJobLabelVal)
+	return l
+}
+
 // Backup assigns specific value to backup key in label
 func (l Label) Backup(val string) Label {
 	l[BackupLabelKey] = val
@@ -198,6 +248,12 @@ func (l Label) Restore(val string) Label {
 	return l
 }
 
+// DataImport assigns specific value to data import key in label
+func (l Label) DataImport(val string) Label {
+	l[DataImportLabelKey] = val
+	return l
+}
+
 // PD assigns pd to component key in label
 func (l Label) PD() Label {
 	l.Component(PDLabelVal)
@@ -210,6 +266,19 @@ func (l Label) Pump() Label {
 	return l
 }
 
+// Drainer assigns drainer to component key and the drainer name to the drainer key in label
+func (l Label) Drainer(name string) Label {
+	l.Component(DrainerLabelVal)
+	l[DrainerLabelKey] = name
+	return l
+}
+
+// Monitor assigns monitor to component key in label
+func (l Label) Monitor() Label {
+	l.Component(MonitorLabelVal)
+	return l
+}
+
 // IsPD returns whether label is a PD
 func (l Label) IsPD() bool {
 	return l[ComponentLabelKey] == PDLabelVal
@@ -227,11 +296,34 @@ func (l Label) TiKV() Label {
 	return l
 }
 
+// TiFlash assigns tiflash to component key in label
+func (l Label) TiFlash() Label {
+	l.Component(TiFlashLabelVal)
+	return l
+}
+
+// TiKVGroup assigns the TiKV group name to the TiKV group key in label
+func (l Label) TiKVGroup(name string) Label {
+	l[TiKVGroupLabelKey] = name
+	return l
+}
+
+// TiDBGroup assigns the TiDB group name to the TiDB group key in label
+func (l Label) TiDBGroup(name string) Label {
+	l[TiDBGroupLabelKey] = name
+	return l
+}
+
 // IsTiKV returns whether label is a TiKV
 func (l Label) IsTiKV() bool {
 	return l[ComponentLabelKey] == TiKVLabelVal
 }
 
+// IsTiFlash returns whether label is a TiFlash
+func (l Label) IsTiFlash() bool {
+	return l[ComponentLabelKey] == TiFlashLabelVal
+}
+
 // IsTiDB returns whether label is a TiDB
 func (l Label) IsTiDB() bool {
 	return l[ComponentLabelKey] == TiDBLabelVal
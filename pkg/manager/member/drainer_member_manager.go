@@ -0,0 +1,805 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"path"
+	"text/template"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	"github.com/pingcap/tidb-operator/pkg/manager"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	"github.com/pingcap/tidb-operator/pkg/util"
+	"github.com/pingcap/tidb/store/tikv/oracle"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	errorutils "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	v1 "k8s.io/client-go/listers/apps/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	glog "k8s.io/klog"
+	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
+)
+
+const (
+	defaultDrainerLogLevel = "info"
+	// drainerTLSMountPath is the path where the TLS certificate of drainer is mounted
+	drainerTLSMountPath = "/var/lib/drainer-tls"
+	// drainerPasswordEnv is the environment variable drainer reads the password of the mysql and tidb sinks from
+	drainerPasswordEnv = "MYSQL_PSWD"
+	drainerPort        = 8249
+)
+
+// drainerStartScriptTpl is the template string of drainer start script, it is the start script of the tidb-drainer chart
+// Note: changing this will cause a rolling-update of the drainer
+var drainerStartScriptTpl = template.Must(template.New("drainer-start-script").Parse(`set -euo pipefail
+
+domain=` + "`" + `echo ${HOSTNAME}` + "`" + `.{{ .SetName }}
+
+elapseTime=0
+period=1
+threshold=30
+while true; do
+    sleep ${period}
+    elapseTime=$(( elapseTime+period ))
+
+    if [[ ${elapseTime} -ge ${threshold} ]]
+    then
+        echo "waiting for drainer domain ready timeout" >&2
+        exit 1
+    fi
+
+    if nslookup ${domain} 2>/dev/null
+    then
+        echo "nslookup domain ${domain} success"
+        break
+    else
+        echo "nslookup domain ${domain} failed" >&2
+    fi
+done
+
+/drainer \
+-L={{ .LogLevel }} \
+-pd-urls={{ .Scheme }}://{{ .ClusterName }}-pd:2379 \
+-addr=` + "`" + `echo ${HOSTNAME}` + "`" + `.{{ .SetName }}:8249 \
+-config=/etc/drainer/drainer.toml \
+-disable-detect={{ .DisableDetect }} \
+-initial-commit-ts={{ .InitialCommitTs }} \
+-data-dir=/data \
+-log-file=""`))
+
+type drainerMemberManager struct {
+	pdControl      pdapi.PDControlInterface
+	drainerControl controller.DrainerControlInterface
+	setControl     controller.StatefulSetControlInterface
+	svcControl     controller.ServiceControlInterface
+	cmControl      controller.ConfigMapControlInterface
+	certControl    controller.CertControlInterface
+	setLister      v1.StatefulSetLister
+	svcLister      corelisters.ServiceLister
+	cmLister       corelisters.ConfigMapLister
+	podLister      corelisters.PodLister
+}
+
+// NewDrainerMemberManager returns a controller to reconcile the drainers of the clusters
+func NewDrainerMemberManager(
+	pdControl pdapi.PDControlInterface,
+	drainerControl controller.DrainerControlInterface,
+	setControl controller.StatefulSetControlInterface,
+	svcControl controller.ServiceControlInterface,
+	cmControl controller.ConfigMapControlInterface,
+	certControl controller.CertControlInterface,
+	setLister v1.StatefulSetLister,
+	svcLister corelisters.ServiceLister,
+	cmLister corelisters.ConfigMapLister,
+	podLister corelisters.PodLister) manager.Manager {
+	return &drainerMemberManager{
+		pdControl,
+		drainerControl,
+		setControl,
+		svcControl,
+		cmControl,
+		certControl,
+		setLister,
+		svcLister,
+		cmLister,
+		podLister,
+	}
+}
+
+// Sync syncs the drainers in spec.drainers, and removes the StatefulSets and Services of the drainers
+// removed from spec.drainers. The checkpoint PVCs of the removed drainers are retained.
+func (dmm *drainerMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
+	var errs []error
+	for i := range tc.Spec.Drainers {
+		if err := dmm.syncDrainer(tc, &tc.Spec.Drainers[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := dmm.removeDrainers(tc); err != nil {
+		errs = append(errs, err)
+	}
+	return errorutils.NewAggregate(errs)
+}
+
+func (dmm *drainerMemberManager) syncDrainer(tc *v1alpha1.TidbCluster, spec *v1alpha1.DrainerSpec) error {
+	ns := tc.GetNamespace()
+	setName := controller.DrainerMemberName(tc.GetName(), spec.Name)
+
+	if err := dmm.syncHeadlessService(tc, spec); err != nil {
+		return err
+	}
+
+	oldSetTmp, err := dmm.setLister.StatefulSets(ns).Get(setName)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	notFound := errors.IsNotFound(err)
+
+	cm, err := dmm.syncConfigMap(tc, spec)
+	if err != nil {
+		return err
+	}
+	newSet, err := getNewDrainerStatefulSet(tc, spec, cm)
+	if err != nil {
+		return err
+	}
+
+	if notFound {
+		err = SetLastAppliedConfigAnnotation(newSet)
+		if err != nil {
+			return err
+		}
+		if tc.Spec.EnableTLSCluster {
+			if err := dmm.syncDrainerCerts(tc, spec); err != nil {
+				return err
+			}
+		}
+		err = dmm.setControl.CreateStatefulSet(tc, newSet)
+		if err != nil {
+			return err
+		}
+		setDrainerStatus(tc, spec.Name, v1alpha1.DrainerStatus{StatefulSet: &apps.StatefulSetStatus{}})
+		return nil
+	}
+
+	oldSet := oldSetTmp.DeepCopy()
+	if metav1.GetControllerOf(oldSet) == nil {
+		// the selector of the StatefulSet created by the tidb-drainer chart differs and can not be changed
+		return fmt.Errorf("tidbcluster: [%s/%s]'s drainer StatefulSet %s is not managed by tidb-operator, "+
+			"delete it without deleting its PVC to let tidb-operator take over the drainer", ns, tc.GetName(), setName)
+	}
+
+	dmm.syncDrainerStatus(tc, spec, oldSet)
+
+	if err := checkUpgradeVersion(tc, v1alpha1.DrainerMemberType, dmm.setLister, dmm.pdControl, oldSet, newSet); err != nil {
+		return err
+	}
+	// the drainer is upgraded after the other components, so that it never replicates the binlog of a newer cluster
+	if !templateEqual(newSet.Spec.Template, oldSet.Spec.Template) && drainerUpgradeBlocked(tc) {
+		glog.Infof("tidbcluster: [%s/%s]'s drainer %s is waiting for the other components to finish upgrading",
+			ns, tc.GetName(), spec.Name)
+		if err := keepOldPodSpec(oldSet, newSet); err != nil {
+			return err
+		}
+	}
+
+	if !templateEqual(newSet.Spec.Template, oldSet.Spec.Template) || tc.Status.Drainers[spec.Name].Phase == v1alpha1.UpgradePhase {
+		if err := dmm.upgradeDrainer(tc, spec, oldSet, newSet); err != nil {
+			return err
+		}
+	}
+
+	if !statefulSetEqual(*newSet, *oldSet) {
+		set := *oldSet
+		set.Spec.Template = newSet.Spec.Template
+		*set.Spec.Replicas = *newSet.Spec.Replicas
+		set.Spec.UpdateStrategy = newSet.Spec.UpdateStrategy
+		err := SetLastAppliedConfigAnnotation(&set)
+		if err != nil {
+			return err
+		}
+		_, err = dmm.setControl.UpdateStatefulSet(tc, &set)
+		return err
+	}
+	if statefulSetIsUpgrading(oldSet) {
+		return nil
+	}
+	return dmm.cleanConfigMaps(tc, spec, oldSet)
+}
+
+// upgradeDrainer upgrades the drainer pods from the highest ordinal by lowering the partition of newSet, the pod
+// template is applied with the partition of the replicas first. The upgrade pauses at the gate of the upgrade strategy
+// of the drainer, see checkUpgradeGate.
+func (dmm *drainerMemberManager) upgradeDrainer(tc *v1alpha1.TidbCluster, spec *v1alpha1.DrainerSpec, oldSet, newSet *apps.StatefulSet) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	status := tc.Status.Drainers[spec.Name]
+	status.Phase = v1alpha1.UpgradePhase
+	setDrainerStatus(tc, spec.Name, status)
+	if !templateEqual(newSet.Spec.Template, oldSet.Spec.Template) {
+		return nil
+	}
+	if status.StatefulSet.UpdateRevision == status.StatefulSet.CurrentRevision {
+		return nil
+	}
+	if oldSet.Spec.UpdateStrategy.Type == apps.OnDeleteStatefulSetStrategyType || oldSet.Spec.UpdateStrategy.RollingUpdate == nil ||
+		oldSet.Spec.UpdateStrategy.RollingUpdate.Partition == nil {
+		// the StatefulSet created before the drainer upgrades are staged, or modified manually
+		newSet.Spec.UpdateStrategy = oldSet.Spec.UpdateStrategy
+		glog.Warningf("tidbcluster: [%s/%s] drainer statefulset %s has no partition, upgrade it without the upgrade strategy",
+			ns, tcName, oldSet.GetName())
+		return nil
+	}
+
+	setUpgradePartition(newSet, *oldSet.Spec.UpdateStrategy.RollingUpdate.Partition)
+	progress := getUpgradeProgress(&status.Upgrade, status.StatefulSet.UpdateRevision)
+	setDrainerStatus(tc, spec.Name, status)
+	replicas := *oldSet.Spec.Replicas
+	var upgraded int32
+	for i := replicas - 1; i >= 0; i-- {
+		podName := fmt.Sprintf("%s-%d", oldSet.GetName(), i)
+		pod, err := dmm.podLister.Pods(ns).Get(podName)
+		if err != nil {
+			return err
+		}
+		revision, exist := pod.Labels[apps.ControllerRevisionHashLabelKey]
+		if !exist {
+			return controller.RequeueErrorf("tidbcluster: [%s/%s]'s drainer pod: [%s] has no label: %s", ns, tcName, podName, apps.ControllerRevisionHashLabelKey)
+		}
+
+		if revision == status.StatefulSet.UpdateRevision {
+			if !podutil.IsPodReady(pod) {
+				// restart the health window, the drainer is never rolled back
+				upgradedPodUnhealthy(spec.UpgradeStrategy, progress)
+				return controller.RequeueErrorf("tidbcluster: [%s/%s]'s drainer upgraded pod: [%s] is not ready", ns, tcName, podName)
+			}
+			upgraded++
+			continue
+		}
+
+		if err := checkUpgradeGate(tc, v1alpha1.DrainerMemberType, spec.UpgradeStrategy, progress, replicas, upgraded); err != nil {
+			return err
+		}
+		setUpgradePartition(newSet, i)
+		return nil
+	}
+	return nil
+}
+
+// cleanConfigMaps deletes the ConfigMaps of the previous configs of the drainer. It is called after the StatefulSet
+// finishes rolling to its pod template, before that the pods of the current revision may still mount them.
+func (dmm *drainerMemberManager) cleanConfigMaps(tc *v1alpha1.TidbCluster, spec *v1alpha1.DrainerSpec, set *apps.StatefulSet) error {
+	ns := tc.GetNamespace()
+	_, drainerLabel := getDrainerMeta(tc, spec)
+	selector, err := drainerLabel.Selector()
+	if err != nil {
+		return err
+	}
+	cms, err := dmm.cmLister.ConfigMaps(ns).List(selector)
+	if err != nil {
+		return err
+	}
+
+	used := map[string]bool{}
+	for _, vol := range set.Spec.Template.Spec.Volumes {
+		if vol.ConfigMap != nil {
+			used[vol.ConfigMap.Name] = true
+		}
+	}
+	for _, cm := range cms {
+		if used[cm.GetName()] || metav1.GetControllerOf(cm) == nil {
+			continue
+		}
+		glog.Infof("tidbcluster: [%s/%s]'s drainer %s no longer uses ConfigMap %s, delete it", ns, tc.GetName(), spec.Name, cm.GetName())
+		if err := dmm.cmControl.DeleteConfigMap(tc, cm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncDrainerStatus syncs the status of the StatefulSet and the replication progress of the drainer.
+// The progress is kept if the drainer can not be reached, e.g. when it is restarting.
+func (dmm *drainerMemberManager) syncDrainerStatus(tc *v1alpha1.TidbCluster, spec *v1alpha1.DrainerSpec, set *apps.StatefulSet) {
+	status := tc.Status.Drainers[spec.Name]
+	status.StatefulSet = &set.Status
+	if statefulSetIsUpgrading(set) {
+		status.Phase = v1alpha1.UpgradePhase
+	} else {
+		status.Phase = v1alpha1.NormalPhase
+	}
+
+	drainerStatus, err := dmm.drainerControl.GetStatus(tc, spec.Name)
+	if err != nil {
+		glog.Warningf("failed to get the status of tidbcluster: [%s/%s]'s drainer %s, %v",
+			tc.GetNamespace(), tc.GetName(), spec.Name, err)
+	} else {
+		now := metav1.Now()
+		status.Synced = drainerStatus.Synced
+		status.SyncedTS = drainerStatus.LastTS
+		status.LastUpdateTime = &now
+		status.Lag = nil
+		if drainerStatus.LastTS > 0 {
+			physical := oracle.ExtractPhysical(uint64(drainerStatus.LastTS))
+			lag := now.Sub(time.Unix(0, physical*int64(time.Millisecond)))
+			if lag < 0 {
+				lag = 0
+			}
+			status.Lag = &metav1.Duration{Duration: lag.Round(time.Second)}
+		}
+	}
+	setDrainerStatus(tc, spec.Name, status)
+}
+
+func setDrainerStatus(tc *v1alpha1.TidbCluster, name string, status v1alpha1.DrainerStatus) {
+	if tc.Status.Drainers == nil {
+		tc.Status.Drainers = map[string]v1alpha1.DrainerStatus{}
+	}
+	tc.Status.Drainers[name] = status
+}
+
+// drainerUpgradeBlocked returns whether any other component of tc is upgrading
+func drainerUpgradeBlocked(tc *v1alpha1.TidbCluster) bool {
+	if tc.Status.PD.Phase == v1alpha1.UpgradePhase || tc.TiKVUpgrading() || tc.TiKVGroupUpgrading() ||
+		tc.TiFlashUpgrading() || tc.TiDBUpgrading() {
+		return true
+	}
+	for _, status := range tc.Status.TiDBGroups {
+		if status.Phase == v1alpha1.UpgradePhase {
+			return true
+		}
+	}
+	return false
+}
+
+// removeDrainers deletes the StatefulSets, the Services and the ConfigMaps of the drainers which are not in spec.drainers
+func (dmm *drainerMemberManager) removeDrainers(tc *v1alpha1.TidbCluster) error {
+	ns := tc.GetNamespace()
+	instanceName := tc.GetLabels()[label.InstanceLabelKey]
+	selector, err := label.New().Instance(instanceName).Component(label.DrainerLabelVal).Selector()
+	if err != nil {
+		return err
+	}
+	sets, err := dmm.setLister.StatefulSets(ns).List(selector)
+	if err != nil {
+		return err
+	}
+
+	names := map[string]bool{}
+	for _, spec := range tc.Spec.Drainers {
+		names[spec.Name] = true
+	}
+	for _, set := range sets {
+		name := set.Labels[label.DrainerLabelKey]
+		if names[name] || metav1.GetControllerOf(set) == nil {
+			continue
+		}
+		glog.Infof("tidbcluster: [%s/%s]'s drainer %s is removed, delete its StatefulSet and Service", ns, tc.GetName(), name)
+		svc, err := dmm.svcLister.Services(ns).Get(set.Spec.ServiceName)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		if err == nil {
+			if err := dmm.svcControl.DeleteService(tc, svc); err != nil {
+				return err
+			}
+		}
+		if err := dmm.setControl.DeleteStatefulSet(tc, set); err != nil {
+			return err
+		}
+	}
+	cms, err := dmm.cmLister.ConfigMaps(ns).List(selector)
+	if err != nil {
+		return err
+	}
+	for _, cm := range cms {
+		if names[cm.Labels[label.DrainerLabelKey]] || metav1.GetControllerOf(cm) == nil {
+			continue
+		}
+		if err := dmm.cmControl.DeleteConfigMap(tc, cm); err != nil {
+			return err
+		}
+	}
+	for name := range tc.Status.Drainers {
+		if !names[name] {
+			delete(tc.Status.Drainers, name)
+		}
+	}
+	return nil
+}
+
+func (dmm *drainerMemberManager) syncHeadlessService(tc *v1alpha1.TidbCluster, spec *v1alpha1.DrainerSpec) error {
+	newSvc := getNewDrainerHeadlessService(tc, spec)
+	oldSvc, err := dmm.svcLister.Services(newSvc.Namespace).Get(newSvc.Name)
+	if errors.IsNotFound(err) {
+		err = SetServiceLastAppliedConfigAnnotation(newSvc)
+		if err != nil {
+			return err
+		}
+		return dmm.svcControl.CreateService(tc, newSvc)
+	}
+	if err != nil {
+		return err
+	}
+
+	equal, err := serviceEqual(newSvc, oldSvc)
+	if err != nil {
+		return err
+	}
+	if !equal {
+		svc := *oldSvc.DeepCopy()
+		svc.Spec = newSvc.Spec
+		err = SetServiceLastAppliedConfigAnnotation(&svc)
+		if err != nil {
+			return err
+		}
+		_, err = dmm.svcControl.UpdateService(tc, &svc)
+		return err
+	}
+	return nil
+}
+
+// syncConfigMap creates the ConfigMap of the drainer config, the ConfigMap is named after the hash of
+// its content so that a config change rolls the drainer, the superseded ones are deleted by cleanConfigMaps
+func (dmm *drainerMemberManager) syncConfigMap(tc *v1alpha1.TidbCluster, spec *v1alpha1.DrainerSpec) (*corev1.ConfigMap, error) {
+	newCm, err := getNewDrainerConfigMap(tc, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	oldCmTmp, err := dmm.cmLister.ConfigMaps(newCm.Namespace).Get(newCm.Name)
+	if errors.IsNotFound(err) {
+		err = dmm.cmControl.CreateConfigMap(tc, newCm)
+		if err != nil {
+			return nil, err
+		}
+		return newCm, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	oldCm := oldCmTmp.DeepCopy()
+	if !apiequality.Semantic.DeepEqual(oldCm.Data, newCm.Data) {
+		glog.Warningf("hash collision detected on configmap: %s, update configmap content in-place", newCm.Name)
+		oldCm.Data = newCm.Data
+		return dmm.cmControl.UpdateConfigMap(tc, oldCm)
+	}
+	return oldCm, nil
+}
+
+// syncDrainerCerts creates the cert pair for the drainer if not exist
+func (dmm *drainerMemberManager) syncDrainerCerts(tc *v1alpha1.TidbCluster, spec *v1alpha1.DrainerSpec) error {
+	certOpts := drainerCertOptions(tc, spec.Name)
+	if dmm.certControl.CheckSecret(certOpts.Namespace, certOpts.SecretName()) {
+		return nil
+	}
+	return dmm.certControl.Create(controller.GetOwnerRef(tc), certOpts)
+}
+
+// drainerCertOptions returns the options to create the cert pair of the drainer with the name
+func drainerCertOptions(tc *v1alpha1.TidbCluster, name string) *controller.TiDBClusterCertOptions {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	svcName := controller.DrainerMemberName(tcName, name)
+
+	hostList := []string{
+		svcName,
+		fmt.Sprintf("%s.%s", svcName, ns),
+		fmt.Sprintf("*.%s", svcName),
+		fmt.Sprintf("*.%s.%s", svcName, ns),
+		fmt.Sprintf("*.%s.%s.svc", svcName, ns),
+	}
+
+	return &controller.TiDBClusterCertOptions{
+		Namespace:    ns,
+		Instance:     tcName,
+		CommonName:   svcName,
+		HostList:     hostList,
+		Component:    "drainer",
+		Suffix:       fmt.Sprintf("%s-drainer", name),
+		Issuer:       tc.Spec.TLSIssuer,
+		CASecretName: tc.TLSCASecretName(),
+	}
+}
+
+func getNewDrainerHeadlessService(tc *v1alpha1.TidbCluster, spec *v1alpha1.DrainerSpec) *corev1.Service {
+	objMeta, drainerLabel := getDrainerMeta(tc, spec)
+
+	return &corev1.Service{
+		ObjectMeta: objMeta,
+		Spec: corev1.ServiceSpec{
+			ClusterIP: "None",
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "drainer",
+					Port:       drainerPort,
+					TargetPort: intstr.FromInt(drainerPort),
+					Protocol:   corev1.ProtocolTCP,
+				},
+			},
+			Selector:                 drainerLabel,
+			PublishNotReadyAddresses: true,
+		},
+	}
+}
+
+// getNewDrainerConfigMap returns the ConfigMap of the drainer config
+func getNewDrainerConfigMap(tc *v1alpha1.TidbCluster, spec *v1alpha1.DrainerSpec) (*corev1.ConfigMap, error) {
+	objMeta, _ := getDrainerMeta(tc, spec)
+
+	buff := new(bytes.Buffer)
+	if err := toml.NewEncoder(buff).Encode(getDrainerConfig(tc, spec)); err != nil {
+		return nil, err
+	}
+	data := buff.Bytes()
+
+	sum := sha256.Sum256(data)
+	objMeta.Name = fmt.Sprintf("%s-%s", objMeta.Name, fmt.Sprintf("%x", sum)[0:7])
+	return &corev1.ConfigMap{
+		ObjectMeta: objMeta,
+		Data: map[string]string{
+			"config-file": string(data),
+		},
+	}, nil
+}
+
+// getDrainerConfig returns the drainer config, the sink is rendered to the syncer.db-type and syncer.to sections
+// and the security section points to the mounted TLS certificate, the config set by the user is respected
+func getDrainerConfig(tc *v1alpha1.TidbCluster, spec *v1alpha1.DrainerSpec) map[string]interface{} {
+	sink := spec.Sink
+	to := map[string]interface{}{}
+	switch sink.Type {
+	case v1alpha1.DrainerSinkMySQL, v1alpha1.DrainerSinkTiDB:
+		port := sink.Port
+		if port == 0 {
+			port = 3306
+			if sink.Type == v1alpha1.DrainerSinkTiDB {
+				port = 4000
+			}
+		}
+		user := sink.User
+		if user == "" {
+			user = "root"
+		}
+		to["host"] = sink.Host
+		to["port"] = port
+		to["user"] = user
+	case v1alpha1.DrainerSinkKafka:
+		to["kafka-addrs"] = sink.KafkaAddrs
+		if sink.KafkaVersion != "" {
+			to["kafka-version"] = sink.KafkaVersion
+		}
+		if sink.TopicName != "" {
+			to["topic-name"] = sink.TopicName
+		}
+	case v1alpha1.DrainerSinkFile:
+		dir := sink.Dir
+		if dir == "" {
+			dir = "/data/pb"
+		}
+		to["dir"] = dir
+	}
+
+	config := map[string]interface{}{
+		"syncer": map[string]interface{}{
+			"db-type": string(sink.Type),
+			"to":      to,
+		},
+	}
+	if tc.Spec.EnableTLSCluster {
+		config["security"] = map[string]interface{}{
+			"ssl-ca":   tlsCAPath(tc, drainerTLSMountPath),
+			"ssl-cert": path.Join(drainerTLSMountPath, controller.TLSSecretCertKey),
+			"ssl-key":  path.Join(drainerTLSMountPath, controller.TLSSecretKeyKey),
+		}
+	}
+	mergeConfig(config, spec.Config)
+	return config
+}
+
+func getNewDrainerStatefulSet(tc *v1alpha1.TidbCluster, spec *v1alpha1.DrainerSpec, cm *corev1.ConfigMap) (*apps.StatefulSet, error) {
+	baseSpec := tc.BaseDrainerSpec(spec)
+	objMeta, drainerLabel := getDrainerMeta(tc, spec)
+	setName := objMeta.Name
+	storageClass := spec.StorageClassName
+	if storageClass == "" {
+		storageClass = controller.DefaultStorageClassName
+	}
+	podAnnos := CombineAnnotations(controller.AnnProm(drainerPort), baseSpec.Annotations())
+	podAnnos = CombineAnnotations(podAnnos, tlsCertRenewAnnotations(tc, setName))
+	storageRequest, err := controller.ParseStorageRequest(spec.Requests)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse storage request for drainer %s, tidbcluster %s/%s, error: %v", spec.Name, tc.Namespace, tc.Name, err)
+	}
+	startScript, err := getDrainerStartScript(tc, spec)
+	if err != nil {
+		return nil, fmt.Errorf("cannot render start-script for drainer %s, tidbcluster %s/%s, error: %v", spec.Name, tc.Namespace, tc.Name, err)
+	}
+
+	envs := []corev1.EnvVar{
+		{
+			Name:  "TZ",
+			Value: tc.Spec.Timezone,
+		},
+	}
+	if spec.Sink.PasswordSecret != "" {
+		envs = append(envs, corev1.EnvVar{
+			Name: drainerPasswordEnv,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: spec.Sink.PasswordSecret},
+					Key:                  "password",
+				},
+			},
+		})
+	}
+
+	volMounts := []corev1.VolumeMount{
+		{Name: "data", MountPath: "/data"},
+		{Name: "config", MountPath: "/etc/drainer"},
+	}
+	volumes := []corev1.Volume{
+		{
+			Name: "config",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: cm.Name,
+					},
+					Items: []corev1.KeyToPath{{Key: "config-file", Path: "drainer.toml"}},
+				},
+			},
+		},
+	}
+	if tc.Spec.EnableTLSCluster {
+		volMounts = append(volMounts, corev1.VolumeMount{
+			Name: "drainer-tls", ReadOnly: true, MountPath: drainerTLSMountPath,
+		})
+		volumes = append(volumes, tlsSecretVolume(tc, "drainer-tls", setName))
+	}
+
+	return &apps.StatefulSet{
+		ObjectMeta: objMeta,
+		Spec: apps.StatefulSetSpec{
+			Selector:    drainerLabel.LabelSelector(),
+			ServiceName: setName,
+			Replicas:    controller.Int32Ptr(1),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: podAnnos,
+					Labels:      drainerLabel,
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:            v1alpha1.DrainerMemberType.String(),
+							Image:           baseSpec.Image(),
+							ImagePullPolicy: baseSpec.ImagePullPolicy(),
+							Command:         []string{"/bin/sh", "-c", startScript},
+							Ports: []corev1.ContainerPort{{
+								Name:          "drainer",
+								ContainerPort: drainerPort,
+							}},
+							Resources:    util.ResourceRequirement(spec.Resources),
+							Env:          envs,
+							VolumeMounts: volMounts,
+						},
+					},
+					Volumes:           volumes,
+					Affinity:          baseSpec.Affinity(),
+					Tolerations:       baseSpec.Tolerations(),
+					NodeSelector:      baseSpec.NodeSelector(),
+					SchedulerName:     baseSpec.SchedulerName(),
+					SecurityContext:   baseSpec.PodSecurityContext(),
+					PriorityClassName: baseSpec.PriorityClassName(),
+				},
+			},
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "data",
+					},
+					Spec: corev1.PersistentVolumeClaimSpec{
+						AccessModes: []corev1.PersistentVolumeAccessMode{
+							corev1.ReadWriteOnce,
+						},
+						StorageClassName: &storageClass,
+						Resources:        *storageRequest,
+					},
+				},
+			},
+			UpdateStrategy: apps.StatefulSetUpdateStrategy{
+				Type:          apps.RollingUpdateStatefulSetStrategyType,
+				RollingUpdate: &apps.RollingUpdateStatefulSetStrategy{Partition: controller.Int32Ptr(1)},
+			},
+		},
+	}, nil
+}
+
+func getDrainerMeta(tc *v1alpha1.TidbCluster, spec *v1alpha1.DrainerSpec) (metav1.ObjectMeta, label.Label) {
+	instanceName := tc.GetLabels()[label.InstanceLabelKey]
+	drainerLabel := label.New().Instance(instanceName).Drainer(spec.Name)
+
+	objMeta := metav1.ObjectMeta{
+		Name:            controller.DrainerMemberName(tc.Name, spec.Name),
+		Namespace:       tc.Namespace,
+		Labels:          drainerLabel,
+		OwnerReferences: []metav1.OwnerReference{controller.GetOwnerRef(tc)},
+	}
+	return objMeta, drainerLabel
+}
+
+func getDrainerStartScript(tc *v1alpha1.TidbCluster, spec *v1alpha1.DrainerSpec) (string, error) {
+	buff := new(bytes.Buffer)
+	scheme := "http"
+	if tc.Spec.EnableTLSCluster {
+		scheme = "https"
+	}
+	err := drainerStartScriptTpl.Execute(buff, struct {
+		Scheme          string
+		ClusterName     string
+		SetName         string
+		LogLevel        string
+		DisableDetect   bool
+		InitialCommitTs int64
+	}{
+		scheme,
+		tc.Name,
+		controller.DrainerMemberName(tc.Name, spec.Name),
+		getDrainerLogLevel(spec),
+		spec.DisableDetect,
+		spec.InitialCommitTs,
+	})
+	if err != nil {
+		return "", err
+	}
+	return buff.String(), nil
+}
+
+func getDrainerLogLevel(spec *v1alpha1.DrainerSpec) string {
+	logLevel, ok := spec.Config["log-level"].(string)
+	if !ok {
+		return defaultDrainerLogLevel
+	}
+	return logLevel
+}
+
+type FakeDrainerMemberManager struct {
+	err error
+}
+
+func NewFakeDrainerMemberManager() *FakeDrainerMemberManager {
+	return &FakeDrainerMemberManager{}
+}
+
+func (fdmm *FakeDrainerMemberManager) SetSyncError(err error) {
+	fdmm.err = err
+}
+
+func (fdmm *FakeDrainerMemberManager) Sync(_ *v1alpha1.TidbCluster) error {
+	return fdmm.err
+}
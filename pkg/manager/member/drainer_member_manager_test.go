@@ -0,0 +1,474 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned/fake"
+	informers "github.com/pingcap/tidb-operator/pkg/client/informers/externalversions"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	"github.com/pingcap/tidb-operator/pkg/util/config"
+	"github.com/pingcap/tidb/store/tikv/oracle"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	kubeinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestDrainerMemberManagerSyncCreate(t *testing.T) {
+	g := NewGomegaWithT(t)
+	type testcase struct {
+		name                     string
+		prepare                  func(cluster *v1alpha1.TidbCluster)
+		errWhenCreateStatefulSet bool
+		errWhenCreateService     bool
+		err                      bool
+		svcCreated               bool
+		setCreated               bool
+		cmCreated                bool
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+
+		tc := newTidbClusterForDrainer()
+		ns := tc.Namespace
+		setName := controller.DrainerMemberName(tc.Name, "mysql")
+		if test.prepare != nil {
+			test.prepare(tc)
+		}
+
+		dmm, fakeSetControl, fakeSvcControl, _, _ := newFakeDrainerMemberManager()
+		if test.errWhenCreateStatefulSet {
+			fakeSetControl.SetCreateStatefulSetError(errors.NewInternalError(fmt.Errorf("API server failed")), 0)
+		}
+		if test.errWhenCreateService {
+			fakeSvcControl.SetCreateServiceError(errors.NewInternalError(fmt.Errorf("API server failed")), 0)
+		}
+
+		err := dmm.Sync(tc)
+		if test.err {
+			g.Expect(err).To(HaveOccurred())
+		} else {
+			g.Expect(err).NotTo(HaveOccurred())
+		}
+
+		_, err = dmm.svcLister.Services(ns).Get(setName)
+		if test.svcCreated {
+			g.Expect(err).NotTo(HaveOccurred())
+		} else {
+			expectErrIsNotFound(g, err)
+		}
+
+		set, err := dmm.setLister.StatefulSets(ns).Get(setName)
+		if test.setCreated {
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(*set.Spec.Replicas).To(Equal(int32(1)))
+			g.Expect(set.Spec.ServiceName).To(Equal(setName))
+			g.Expect(set.Spec.VolumeClaimTemplates[0].Name).To(Equal("data"))
+			g.Expect(tc.Status.Drainers).To(HaveKey("mysql"))
+		} else {
+			expectErrIsNotFound(g, err)
+		}
+
+		cms, err := dmm.cmLister.ConfigMaps(ns).List(labels.Everything())
+		g.Expect(err).NotTo(HaveOccurred())
+		if test.cmCreated {
+			g.Expect(cms).To(HaveLen(1))
+		} else {
+			g.Expect(cms).To(BeEmpty())
+		}
+	}
+
+	tests := []testcase{
+		{
+			name:       "normal",
+			svcCreated: true,
+			setCreated: true,
+			cmCreated:  true,
+		},
+		{
+			name: "drainer is not deployed",
+			prepare: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.Drainers = nil
+			},
+		},
+		{
+			name: "drainer's storage format is wrong",
+			prepare: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.Drainers[0].Requests.Storage = "100xxxxi"
+			},
+			err:        true,
+			svcCreated: true,
+			cmCreated:  true,
+		},
+		{
+			name:                     "error when create statefulset",
+			errWhenCreateStatefulSet: true,
+			err:                      true,
+			svcCreated:               true,
+			cmCreated:                true,
+		},
+		{
+			name:                 "error when create service",
+			errWhenCreateService: true,
+			err:                  true,
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
+
+func TestDrainerMemberManagerSyncRemove(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForDrainer()
+	ns := tc.Namespace
+	setName := controller.DrainerMemberName(tc.Name, "mysql")
+	dmm, _, _, _, _ := newFakeDrainerMemberManager()
+
+	err := dmm.Sync(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = dmm.setLister.StatefulSets(ns).Get(setName)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	tc.Spec.Drainers = nil
+	err = dmm.Sync(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = dmm.setLister.StatefulSets(ns).Get(setName)
+	expectErrIsNotFound(g, err)
+	_, err = dmm.svcLister.Services(ns).Get(setName)
+	expectErrIsNotFound(g, err)
+	cms, err := dmm.cmLister.ConfigMaps(ns).List(labels.Everything())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cms).To(BeEmpty())
+	g.Expect(tc.Status.Drainers).NotTo(HaveKey("mysql"))
+}
+
+func TestDrainerMemberManagerSyncUpgrade(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForDrainer()
+	tc.Spec.Drainers[0].UpgradeStrategy = &v1alpha1.UpgradeStrategy{
+		Canary:              &intstr.IntOrString{Type: intstr.Int, IntVal: 0},
+		HealthWindowSeconds: controller.Int32Ptr(0),
+		ManualApproval:      true,
+	}
+	ns := tc.Namespace
+	setName := controller.DrainerMemberName(tc.Name, "mysql")
+	dmm, _, _, _, podIndexer := newFakeDrainerMemberManager()
+
+	// rolls the StatefulSet to the revision, as the StatefulSet controller does
+	rollStatefulSet := func(currentRevision, updateRevision string, podRevision string) {
+		set, err := dmm.setLister.StatefulSets(ns).Get(setName)
+		g.Expect(err).NotTo(HaveOccurred())
+		set = set.DeepCopy()
+		set.Status.CurrentRevision = currentRevision
+		set.Status.UpdateRevision = updateRevision
+		g.Expect(dmm.setControl.(*controller.FakeStatefulSetControl).SetIndexer.Update(set)).To(Succeed())
+		pod := &corev1.Pod{}
+		pod.Name = fmt.Sprintf("%s-0", setName)
+		pod.Namespace = ns
+		pod.Labels = map[string]string{apps.ControllerRevisionHashLabelKey: podRevision}
+		pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+		g.Expect(podIndexer.Update(pod)).To(Succeed())
+	}
+	partition := func() int32 {
+		set, err := dmm.setLister.StatefulSets(ns).Get(setName)
+		g.Expect(err).NotTo(HaveOccurred())
+		return *set.Spec.UpdateStrategy.RollingUpdate.Partition
+	}
+	configMaps := func() []string {
+		cms, err := dmm.cmLister.ConfigMaps(ns).List(labels.Everything())
+		g.Expect(err).NotTo(HaveOccurred())
+		var names []string
+		for _, cm := range cms {
+			names = append(names, cm.GetName())
+		}
+		return names
+	}
+
+	g.Expect(dmm.Sync(tc)).To(Succeed())
+	rollStatefulSet("1", "1", "1")
+	g.Expect(configMaps()).To(HaveLen(1))
+	oldCm := configMaps()[0]
+
+	// the new config is applied without restarting the drainer
+	tc.Spec.Drainers[0].GenericConfig = config.New(map[string]interface{}{"syncer": map[string]interface{}{"worker-count": 8}})
+	g.Expect(dmm.Sync(tc)).To(Succeed())
+	g.Expect(partition()).To(Equal(int32(1)))
+	g.Expect(tc.Status.Drainers["mysql"].Phase).To(Equal(v1alpha1.UpgradePhase))
+	g.Expect(configMaps()).To(HaveLen(2))
+
+	// the upgrade waits for the approval
+	rollStatefulSet("1", "2", "1")
+	err := dmm.Sync(tc)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring(label.AnnUpgradeContinue))
+	g.Expect(partition()).To(Equal(int32(1)))
+	g.Expect(tc.Status.Drainers["mysql"].Upgrade.Step).To(Equal(v1alpha1.UpgradeStepWaitingApproval))
+
+	tc.Annotations = map[string]string{label.AnnUpgradeContinue: "2"}
+	g.Expect(dmm.Sync(tc)).To(Succeed())
+	g.Expect(partition()).To(Equal(int32(0)))
+	// the ConfigMap of the previous config is still mounted by the pod of the current revision
+	g.Expect(configMaps()).To(ContainElement(oldCm))
+
+	// the ConfigMap of the previous config is deleted after the upgrade
+	rollStatefulSet("2", "2", "2")
+	g.Expect(dmm.Sync(tc)).To(Succeed())
+	g.Expect(tc.Status.Drainers["mysql"].Phase).To(Equal(v1alpha1.NormalPhase))
+	g.Expect(partition()).To(Equal(int32(1)))
+	g.Expect(dmm.Sync(tc)).To(Succeed())
+	g.Expect(configMaps()).To(HaveLen(1))
+	g.Expect(configMaps()).NotTo(ContainElement(oldCm))
+}
+
+func TestDrainerMemberManagerSyncDrainerStatus(t *testing.T) {
+	g := NewGomegaWithT(t)
+	type testcase struct {
+		name      string
+		setStatus func(*controller.FakeDrainerControl)
+		expectFn  func(*GomegaWithT, v1alpha1.DrainerStatus)
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+
+		tc := newTidbClusterForDrainer()
+		dmm, _, _, drainerControl, _ := newFakeDrainerMemberManager()
+		if test.setStatus != nil {
+			test.setStatus(drainerControl)
+		}
+		set, err := getNewDrainerStatefulSet(tc, &tc.Spec.Drainers[0], &corev1.ConfigMap{})
+		g.Expect(err).NotTo(HaveOccurred())
+
+		dmm.syncDrainerStatus(tc, &tc.Spec.Drainers[0], set)
+		test.expectFn(g, tc.Status.Drainers["mysql"])
+	}
+
+	tests := []testcase{
+		{
+			name: "drainer is synced",
+			setStatus: func(dc *controller.FakeDrainerControl) {
+				ts := oracle.ComposeTS(oracle.GetPhysical(time.Now().Add(-time.Minute)), 0)
+				dc.SetStatus("mysql", true, int64(ts))
+			},
+			expectFn: func(g *GomegaWithT, status v1alpha1.DrainerStatus) {
+				g.Expect(status.Phase).To(Equal(v1alpha1.NormalPhase))
+				g.Expect(status.Synced).To(BeTrue())
+				g.Expect(status.SyncedTS).NotTo(BeZero())
+				g.Expect(status.Lag).NotTo(BeNil())
+				g.Expect(status.Lag.Duration).To(BeNumerically("~", time.Minute, 5*time.Second))
+				g.Expect(status.LastUpdateTime).NotTo(BeNil())
+			},
+		},
+		{
+			name: "drainer has not synced any binlog",
+			setStatus: func(dc *controller.FakeDrainerControl) {
+				dc.SetStatus("mysql", false, 0)
+			},
+			expectFn: func(g *GomegaWithT, status v1alpha1.DrainerStatus) {
+				g.Expect(status.Synced).To(BeFalse())
+				g.Expect(status.Lag).To(BeNil())
+			},
+		},
+		{
+			name: "failed to get drainer status",
+			setStatus: func(dc *controller.FakeDrainerControl) {
+				dc.SetGetStatusError(fmt.Errorf("connection refused"))
+			},
+			expectFn: func(g *GomegaWithT, status v1alpha1.DrainerStatus) {
+				g.Expect(status.StatefulSet).NotTo(BeNil())
+				g.Expect(status.LastUpdateTime).To(BeNil())
+			},
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
+
+func TestGetDrainerConfig(t *testing.T) {
+	g := NewGomegaWithT(t)
+	type testcase struct {
+		name     string
+		spec     v1alpha1.DrainerSpec
+		tls      bool
+		expectTo map[string]interface{}
+		expectFn func(*GomegaWithT, map[string]interface{})
+	}
+
+	tests := []testcase{
+		{
+			name: "mysql sink with default port and user",
+			spec: v1alpha1.DrainerSpec{
+				Sink: v1alpha1.DrainerSinkSpec{Type: v1alpha1.DrainerSinkMySQL, Host: "mysql"},
+			},
+			expectTo: map[string]interface{}{"host": "mysql", "port": int32(3306), "user": "root"},
+		},
+		{
+			name: "tidb sink",
+			spec: v1alpha1.DrainerSpec{
+				Sink: v1alpha1.DrainerSinkSpec{Type: v1alpha1.DrainerSinkTiDB, Host: "downstream-tidb", User: "binlog"},
+			},
+			expectTo: map[string]interface{}{"host": "downstream-tidb", "port": int32(4000), "user": "binlog"},
+		},
+		{
+			name: "kafka sink",
+			spec: v1alpha1.DrainerSpec{
+				Sink: v1alpha1.DrainerSinkSpec{Type: v1alpha1.DrainerSinkKafka, KafkaAddrs: "kafka:9092", TopicName: "binlog"},
+			},
+			expectTo: map[string]interface{}{"kafka-addrs": "kafka:9092", "topic-name": "binlog"},
+		},
+		{
+			name: "file sink",
+			spec: v1alpha1.DrainerSpec{
+				Sink: v1alpha1.DrainerSinkSpec{Type: v1alpha1.DrainerSinkFile},
+			},
+			expectTo: map[string]interface{}{"dir": "/data/pb"},
+		},
+		{
+			name: "tls is enabled",
+			spec: v1alpha1.DrainerSpec{
+				Sink: v1alpha1.DrainerSinkSpec{Type: v1alpha1.DrainerSinkFile},
+			},
+			tls:      true,
+			expectTo: map[string]interface{}{"dir": "/data/pb"},
+			expectFn: func(g *GomegaWithT, c map[string]interface{}) {
+				g.Expect(c).To(HaveKey("security"))
+				g.Expect(c["security"]).To(HaveKeyWithValue("ssl-cert", "/var/lib/drainer-tls/cert"))
+			},
+		},
+		{
+			name: "user config is respected",
+			spec: v1alpha1.DrainerSpec{
+				Sink: v1alpha1.DrainerSinkSpec{Type: v1alpha1.DrainerSinkFile},
+				GenericConfig: config.GenericConfig{
+					Config: map[string]interface{}{
+						"syncer": map[string]interface{}{
+							"worker-count": 16,
+							"to":           map[string]interface{}{"dir": "/data/binlog"},
+						},
+					},
+				},
+			},
+			expectTo: map[string]interface{}{"dir": "/data/binlog"},
+			expectFn: func(g *GomegaWithT, c map[string]interface{}) {
+				g.Expect(c["syncer"]).To(HaveKeyWithValue("worker-count", 16))
+				g.Expect(c["syncer"]).To(HaveKeyWithValue("db-type", "file"))
+			},
+		},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+		t.Log(test.name)
+		tc := newTidbClusterForDrainer()
+		tc.Spec.EnableTLSCluster = test.tls
+		c := getDrainerConfig(tc, &test.spec)
+		syncer := c["syncer"].(map[string]interface{})
+		g.Expect(syncer["to"]).To(Equal(test.expectTo))
+		if test.expectFn != nil {
+			test.expectFn(g, c)
+		}
+	}
+}
+
+func TestDrainerUpgradeBlocked(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForDrainer()
+	g.Expect(drainerUpgradeBlocked(tc)).To(BeFalse())
+	tc.Status.TiDB.Phase = v1alpha1.UpgradePhase
+	g.Expect(drainerUpgradeBlocked(tc)).To(BeTrue())
+
+	tc = newTidbClusterForDrainer()
+	tc.Status.TiDBGroups = map[string]v1alpha1.TiDBStatus{"g1": {Phase: v1alpha1.UpgradePhase}}
+	g.Expect(drainerUpgradeBlocked(tc)).To(BeTrue())
+
+	tc.Status.TiDBGroups = nil
+	tc.Status.Drainers = map[string]v1alpha1.DrainerStatus{"mysql": {Phase: v1alpha1.UpgradePhase}}
+	g.Expect(drainerUpgradeBlocked(tc)).To(BeFalse())
+}
+
+func newFakeDrainerMemberManager() (
+	*drainerMemberManager, *controller.FakeStatefulSetControl,
+	*controller.FakeServiceControl, *controller.FakeDrainerControl, cache.Indexer) {
+	cli := fake.NewSimpleClientset()
+	kubeCli := kubefake.NewSimpleClientset()
+	pdControl := pdapi.NewFakePDControl(kubeCli)
+	drainerControl := controller.NewFakeDrainerControl()
+	setInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Apps().V1().StatefulSets()
+	svcInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().Services()
+	epsInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().Endpoints()
+	cmInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().ConfigMaps()
+	podInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().Pods()
+	tcInformer := informers.NewSharedInformerFactory(cli, 0).Pingcap().V1alpha1().TidbClusters()
+	setControl := controller.NewFakeStatefulSetControl(setInformer, tcInformer)
+	svcControl := controller.NewFakeServiceControl(svcInformer, epsInformer, tcInformer)
+	cmControl := controller.NewFakeConfigMapControl(cmInformer)
+
+	dmm := &drainerMemberManager{
+		pdControl:      pdControl,
+		drainerControl: drainerControl,
+		setControl:     setControl,
+		svcControl:     svcControl,
+		cmControl:      cmControl,
+		setLister:      setInformer.Lister(),
+		svcLister:      svcInformer.Lister(),
+		cmLister:       cmInformer.Lister(),
+		podLister:      podInformer.Lister(),
+	}
+	return dmm, setControl, svcControl, drainerControl, podInformer.Informer().GetIndexer()
+}
+
+func newTidbClusterForDrainer() *v1alpha1.TidbCluster {
+	tc := newTidbClusterForPD()
+	tc.Labels = map[string]string{label.InstanceLabelKey: tc.Name}
+	tc.Spec.Drainers = []v1alpha1.DrainerSpec{
+		{
+			Name: "mysql",
+			ComponentSpec: v1alpha1.ComponentSpec{
+				Image: "tidb-binlog-test-image",
+			},
+			Resources: v1alpha1.Resources{
+				Requests: &v1alpha1.ResourceRequirement{
+					Storage: "10Gi",
+				},
+			},
+			Sink: v1alpha1.DrainerSinkSpec{
+				Type:           v1alpha1.DrainerSinkMySQL,
+				Host:           "mysql",
+				PasswordSecret: "mysql-password",
+			},
+		},
+	}
+	tc.Status.Drainers = nil
+	return tc
+}
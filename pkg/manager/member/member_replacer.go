@@ -0,0 +1,383 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/manager"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	"github.com/pingcap/tidb-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/record"
+	glog "k8s.io/klog"
+)
+
+type memberReplacer struct {
+	pdControl  pdapi.PDControlInterface
+	podLister  corelisters.PodLister
+	podControl controller.PodControlInterface
+	pvcLister  corelisters.PersistentVolumeClaimLister
+	pvcControl controller.PVCControlInterface
+	recorder   record.EventRecorder
+}
+
+// NewMemberReplacer returns a manager which replaces the PD and TiKV members listed in spec.replaceMembers.
+// The member is removed from PD first, then its pod and PVC are deleted, and the statefulset recreates the
+// pod with a new PVC. The name is removed from spec.replaceMembers after the new member joins the cluster.
+func NewMemberReplacer(pdControl pdapi.PDControlInterface,
+	podLister corelisters.PodLister,
+	podControl controller.PodControlInterface,
+	pvcLister corelisters.PersistentVolumeClaimLister,
+	pvcControl controller.PVCControlInterface,
+	recorder record.EventRecorder) manager.Manager {
+	return &memberReplacer{
+		pdControl:  pdControl,
+		podLister:  podLister,
+		podControl: podControl,
+		pvcLister:  pvcLister,
+		pvcControl: pvcControl,
+		recorder:   recorder,
+	}
+}
+
+func (mr *memberReplacer) Sync(tc *v1alpha1.TidbCluster) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	requested := map[string]bool{}
+	for _, podName := range tc.Spec.ReplaceMembers {
+		requested[podName] = true
+	}
+	for podName, replacement := range tc.Status.MemberReplacements {
+		if requested[podName] {
+			continue
+		}
+		if replacement.Phase != v1alpha1.MemberReplacementCompleted {
+			glog.Warningf("tidbcluster: [%s/%s]'s replacement of %s is cancelled in phase %s", ns, tcName, podName, replacement.Phase)
+		}
+		delete(tc.Status.MemberReplacements, podName)
+	}
+	if len(tc.Status.MemberReplacements) == 0 {
+		tc.Status.MemberReplacements = nil
+	}
+
+	// the members are replaced one at a time in the order of spec.replaceMembers
+	var current string
+	var replaceMembers []string
+	for _, podName := range tc.Spec.ReplaceMembers {
+		replacement, ok := tc.Status.MemberReplacements[podName]
+		if ok && replacement.Phase == v1alpha1.MemberReplacementCompleted {
+			continue
+		}
+		if !ok {
+			if err := mr.validate(tc, podName); err != nil {
+				mr.recorder.Eventf(tc, corev1.EventTypeWarning, "ReplaceMemberInvalid", "can't replace %s: %v", podName, err)
+				glog.Warningf("tidbcluster: [%s/%s] can't replace %s: %v", ns, tcName, podName, err)
+				continue
+			}
+		}
+		replaceMembers = append(replaceMembers, podName)
+		if current == "" {
+			current = podName
+		}
+	}
+	tc.Spec.ReplaceMembers = replaceMembers
+	if current == "" {
+		return nil
+	}
+
+	if _, ok := tc.Status.MemberReplacements[current]; !ok {
+		if tc.Status.MemberReplacements == nil {
+			tc.Status.MemberReplacements = map[string]v1alpha1.MemberReplacement{}
+		}
+		tc.Status.MemberReplacements[current] = v1alpha1.MemberReplacement{
+			MemberType: replaceMemberType(tc, current),
+			Phase:      v1alpha1.MemberReplacementOfflining,
+			StartTime:  metav1.Now(),
+		}
+		mr.recorder.Eventf(tc, corev1.EventTypeNormal, "ReplaceMemberStarted", "replacing %s", current)
+		glog.Infof("tidbcluster: [%s/%s] starts replacing %s", ns, tcName, current)
+	}
+	replacement := tc.Status.MemberReplacements[current]
+	err := mr.replace(tc, current, &replacement)
+	tc.Status.MemberReplacements[current] = replacement
+	if err != nil {
+		return err
+	}
+	if replacement.Phase == v1alpha1.MemberReplacementCompleted {
+		tc.Spec.ReplaceMembers = tc.Spec.ReplaceMembers[1:]
+		mr.recorder.Eventf(tc, corev1.EventTypeNormal, "MemberReplaced", "%s is replaced by member %s", current, replacement.ID)
+		glog.Infof("tidbcluster: [%s/%s]'s %s is replaced", ns, tcName, current)
+	}
+	return nil
+}
+
+// validate returns an error if the pod is not a PD or TiKV member of tc
+func (mr *memberReplacer) validate(tc *v1alpha1.TidbCluster, podName string) error {
+	var replicas int32
+	switch replaceMemberType(tc, podName) {
+	case v1alpha1.PDMemberType:
+		replicas = tc.Spec.PD.Replicas
+	case v1alpha1.TiKVMemberType:
+		replicas = tc.Spec.TiKV.Replicas
+	default:
+		return fmt.Errorf("%s is not a pd or tikv pod", podName)
+	}
+	ordinal, err := util.GetOrdinalFromPodName(podName)
+	if err != nil {
+		return err
+	}
+	if ordinal >= replicas {
+		return fmt.Errorf("%s doesn't exist", podName)
+	}
+	return nil
+}
+
+func (mr *memberReplacer) replace(tc *v1alpha1.TidbCluster, podName string, replacement *v1alpha1.MemberReplacement) error {
+	ns := tc.GetNamespace()
+	memberType := replacement.MemberType
+
+	ordinal, err := util.GetOrdinalFromPodName(podName)
+	if err != nil {
+		return err
+	}
+	pvcName := ordinalPVCName(memberType, replaceMemberSetName(tc, memberType), ordinal)
+	pvc, err := mr.pvcLister.PersistentVolumeClaims(ns).Get(pvcName)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	switch replacement.Phase {
+	case v1alpha1.MemberReplacementOfflining:
+		if replacement.PVCUID == "" && pvc != nil {
+			replacement.PVCUID = pvc.GetUID()
+		}
+		offline, err := mr.offline(tc, podName, replacement)
+		if err != nil || !offline {
+			return err
+		}
+		replacement.Phase = v1alpha1.MemberReplacementDeleting
+		replacement.Message = fmt.Sprintf("deleting the pod and PVC of %s", podName)
+		fallthrough
+
+	case v1alpha1.MemberReplacementDeleting:
+		// The order of the old PVC deleting and the new pod creating is not guaranteed by Kubernetes,
+		// so the pod and the old PVC are deleted over and over until the old PVC is gone.
+		if pvc != nil && pvc.GetUID() == replacement.PVCUID {
+			pod, err := mr.podLister.Pods(ns).Get(podName)
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+			if pvc.DeletionTimestamp == nil {
+				if err := mr.pvcControl.DeletePVC(tc, pvc); err != nil {
+					return err
+				}
+			}
+			if pod != nil && pod.DeletionTimestamp == nil {
+				if err := mr.podControl.DeletePod(tc, pod); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		replacement.Phase = v1alpha1.MemberReplacementJoining
+		replacement.Message = fmt.Sprintf("waiting for the new member of %s to join", podName)
+		fallthrough
+
+	case v1alpha1.MemberReplacementJoining:
+		id, joined := replaceMemberJoined(tc, podName, memberType)
+		if !joined || id == replacement.ID {
+			return nil
+		}
+		replacement.ID = id
+		replacement.Phase = v1alpha1.MemberReplacementCompleted
+		replacement.Message = ""
+	}
+	return nil
+}
+
+// offline removes the member from PD, and returns whether PD reports the member as removed, i.e. the PD member
+// is no longer in the PD cluster or the TiKV store is tombstone
+func (mr *memberReplacer) offline(tc *v1alpha1.TidbCluster, podName string, replacement *v1alpha1.MemberReplacement) (bool, error) {
+	ns := tc.GetNamespace()
+	pdClient := controller.GetPDClient(mr.pdControl, tc)
+
+	switch replacement.MemberType {
+	case v1alpha1.PDMemberType:
+		if replacement.ID == "" {
+			member, ok := tc.Status.PD.Members[podName]
+			if !ok {
+				replacement.Message = fmt.Sprintf("waiting for the pd member of %s to be reported", podName)
+				return false, nil
+			}
+			replacement.ID = member.ID
+		}
+		id, err := strconv.ParseUint(replacement.ID, 10, 64)
+		if err != nil {
+			return false, err
+		}
+		membersInfo, err := pdClient.GetMembers()
+		if err != nil {
+			return false, err
+		}
+		removed := true
+		for _, member := range membersInfo.Members {
+			if member.GetMemberId() == id {
+				removed = false
+				break
+			}
+		}
+		if removed {
+			return true, nil
+		}
+		if msg := pdMemberRemovable(tc, podName); msg != "" {
+			replacement.Message = msg
+			return false, nil
+		}
+		if err := pdClient.DeleteMemberByID(id); err != nil {
+			return false, err
+		}
+		glog.Infof("member replacer: delete pd member %d of %s/%s", id, ns, podName)
+		replacement.Message = fmt.Sprintf("waiting for pd member %s to be removed", replacement.ID)
+		return false, nil
+
+	case v1alpha1.TiKVMemberType:
+		if replacement.ID == "" {
+			store, ok := tikvStoreOfPod(tc, podName)
+			if !ok {
+				replacement.Message = fmt.Sprintf("waiting for the tikv store of %s to be reported", podName)
+				return false, nil
+			}
+			replacement.ID = store.ID
+		}
+		id, err := strconv.ParseUint(replacement.ID, 10, 64)
+		if err != nil {
+			return false, err
+		}
+		storeInfo, err := pdClient.GetStore(id)
+		if err != nil {
+			return false, err
+		}
+		if storeInfo.Store == nil {
+			return false, fmt.Errorf("store %d of %s/%s is not returned by pd", id, ns, podName)
+		}
+		switch storeInfo.Store.StateName {
+		case v1alpha1.TiKVStateTombstone:
+			return true, nil
+		case v1alpha1.TiKVStateOffline:
+			replacement.Message = fmt.Sprintf("waiting for the regions of store %s to be migrated", replacement.ID)
+			return false, nil
+		}
+		if tc.TiKVUpgrading() {
+			replacement.Message = "waiting for tikv to be upgraded"
+			return false, nil
+		}
+		config, err := pdClient.GetConfig()
+		if err != nil {
+			return false, err
+		}
+		var upStores uint64
+		for _, s := range tc.Status.TiKV.Stores {
+			if s.State == v1alpha1.TiKVStateUp && s.ID != replacement.ID {
+				upStores++
+			}
+		}
+		if upStores < config.Replication.MaxReplicas {
+			replacement.Message = fmt.Sprintf("%d other tikv stores are up, less than max-replicas %d", upStores, config.Replication.MaxReplicas)
+			return false, nil
+		}
+		if err := pdClient.DeleteStore(id); err != nil {
+			return false, err
+		}
+		glog.Infof("member replacer: delete store %d of %s/%s", id, ns, podName)
+		replacement.Message = fmt.Sprintf("waiting for the regions of store %s to be migrated", replacement.ID)
+		return false, nil
+	}
+	return false, fmt.Errorf("can't replace %s member %s", replacement.MemberType, podName)
+}
+
+// pdMemberRemovable returns why the pd member can't be removed, or an empty string if it can
+func pdMemberRemovable(tc *v1alpha1.TidbCluster, podName string) string {
+	if tc.PDUpgrading() {
+		return "waiting for pd to be upgraded"
+	}
+	if len(tc.Status.PD.Members) < 2 {
+		return "the only pd member can't be removed"
+	}
+	for name, member := range tc.Status.PD.Members {
+		if name != podName && !member.Health {
+			return fmt.Sprintf("pd member %s is unhealthy", name)
+		}
+	}
+	return ""
+}
+
+// replaceMemberJoined returns the ID of the member of the pod, and whether it's healthy or up
+func replaceMemberJoined(tc *v1alpha1.TidbCluster, podName string, memberType v1alpha1.MemberType) (string, bool) {
+	switch memberType {
+	case v1alpha1.PDMemberType:
+		member, ok := tc.Status.PD.Members[podName]
+		return member.ID, ok && member.Health
+	case v1alpha1.TiKVMemberType:
+		store, ok := tikvStoreOfPod(tc, podName)
+		return store.ID, ok && store.State == v1alpha1.TiKVStateUp
+	}
+	return "", false
+}
+
+// replaceMemberType returns the member type of the pod, the pod name must be exactly <set>-<ordinal> so that
+// the pods of the TiKV groups, named <tc>-tikv-<group>-<ordinal>, are not taken as the pods of spec.tikv
+func replaceMemberType(tc *v1alpha1.TidbCluster, podName string) v1alpha1.MemberType {
+	for _, memberType := range []v1alpha1.MemberType{v1alpha1.PDMemberType, v1alpha1.TiKVMemberType} {
+		ordinal := strings.TrimPrefix(podName, replaceMemberSetName(tc, memberType)+"-")
+		if ordinal == podName || ordinal == "" {
+			continue
+		}
+		if strings.Trim(ordinal, "0123456789") == "" {
+			return memberType
+		}
+	}
+	return v1alpha1.UnknownMemberType
+}
+
+func replaceMemberSetName(tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType) string {
+	if memberType == v1alpha1.PDMemberType {
+		return controller.PDMemberName(tc.GetName())
+	}
+	return controller.TiKVMemberName(tc.GetName())
+}
+
+type FakeMemberReplacer struct {
+	err error
+}
+
+func NewFakeMemberReplacer() *FakeMemberReplacer {
+	return &FakeMemberReplacer{}
+}
+
+func (fmr *FakeMemberReplacer) SetSyncError(err error) {
+	fmr.err = err
+}
+
+func (fmr *FakeMemberReplacer) Sync(_ *v1alpha1.TidbCluster) error {
+	return fmr.err
+}
@@ -0,0 +1,274 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	kubeinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestMemberReplacerSync(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name           string
+		replaceMembers []string
+		replacement    *v1alpha1.MemberReplacement
+		// the states of the tikv stores of the ordinals, the store doesn't exist if it's empty
+		storeStates []string
+		// the IDs of the pd members of the ordinals
+		pdMemberIDs []string
+		pdUnhealthy bool
+		// the pd member being replaced is not returned by pd
+		pdRemoved          bool
+		pvcUID             types.UID
+		expectReplace      []string
+		expectReplacement  *v1alpha1.MemberReplacement
+		expectStoreDeleted bool
+		expectPDDeleted    bool
+		expectPVCDeleted   bool
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+
+		kubeCli := kubefake.NewSimpleClientset()
+		podInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().Pods()
+		pvcInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().PersistentVolumeClaims()
+		pdControl := pdapi.NewFakePDControl(kubeCli)
+		podControl := controller.NewFakePodControl(podInformer)
+		pvcControl := controller.NewFakePVCControl(pvcInformer)
+		replacer := NewMemberReplacer(pdControl, podInformer.Lister(), podControl, pvcInformer.Lister(), pvcControl, record.NewFakeRecorder(10))
+
+		tc := newTidbClusterForPD()
+		tc.Spec.ReplaceMembers = test.replaceMembers
+		podName := ""
+		if len(test.replaceMembers) > 0 {
+			podName = test.replaceMembers[0]
+		}
+		if test.replacement != nil {
+			tc.Status.MemberReplacements = map[string]v1alpha1.MemberReplacement{podName: *test.replacement}
+		}
+		tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{}
+		for i, state := range test.storeStates {
+			if state == "" {
+				continue
+			}
+			id := fmt.Sprintf("%d", i+1)
+			tc.Status.TiKV.Stores[id] = v1alpha1.TiKVStore{ID: id, PodName: TikvPodName(tc.GetName(), int32(i)), State: state}
+		}
+		tc.Status.PD.Members = map[string]v1alpha1.PDMember{}
+		for i, id := range test.pdMemberIDs {
+			name := PdPodName(tc.GetName(), int32(i))
+			tc.Status.PD.Members[name] = v1alpha1.PDMember{Name: name, ID: id, Health: !test.pdUnhealthy || i == 0}
+		}
+
+		if test.pvcUID != "" {
+			memberType := replaceMemberType(tc, podName)
+			ordinal := int32(podName[len(podName)-1] - '0')
+			pvcInformer.Informer().GetIndexer().Add(&corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      ordinalPVCName(memberType, replaceMemberSetName(tc, memberType), ordinal),
+					Namespace: tc.GetNamespace(),
+					UID:       test.pvcUID,
+				},
+			})
+			podInformer.Informer().GetIndexer().Add(&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: podName, Namespace: tc.GetNamespace()},
+			})
+		}
+
+		storeDeleted := false
+		pdDeleted := false
+		pdClient := controller.NewFakePDClient(pdControl, tc)
+		pdClient.AddReaction(pdapi.DeleteStoreActionType, func(action *pdapi.Action) (interface{}, error) {
+			storeDeleted = true
+			return nil, nil
+		})
+		pdClient.AddReaction(pdapi.DeleteMemberByIDActionType, func(action *pdapi.Action) (interface{}, error) {
+			pdDeleted = true
+			return nil, nil
+		})
+		pdClient.AddReaction(pdapi.GetStoreActionType, func(action *pdapi.Action) (interface{}, error) {
+			state := v1alpha1.TiKVStateTombstone
+			if store, ok := tc.Status.TiKV.Stores[fmt.Sprintf("%d", action.ID)]; ok {
+				state = store.State
+			}
+			return &pdapi.StoreInfo{Store: &pdapi.MetaStore{Store: &metapb.Store{Id: action.ID}, StateName: state}}, nil
+		})
+		pdClient.AddReaction(pdapi.GetMembersActionType, func(action *pdapi.Action) (interface{}, error) {
+			membersInfo := &pdapi.MembersInfo{}
+			for name, member := range tc.Status.PD.Members {
+				if test.pdRemoved && name == podName {
+					continue
+				}
+				id, _ := strconv.ParseUint(member.ID, 10, 64)
+				membersInfo.Members = append(membersInfo.Members, &pdpb.Member{Name: name, MemberId: id})
+			}
+			return membersInfo, nil
+		})
+		pdClient.AddReaction(pdapi.GetConfigActionType, func(action *pdapi.Action) (interface{}, error) {
+			return &pdapi.Config{Replication: pdapi.ReplicationConfig{MaxReplicas: 3}}, nil
+		})
+
+		err := replacer.Sync(tc)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(tc.Spec.ReplaceMembers).To(Equal(test.expectReplace))
+		g.Expect(storeDeleted).To(Equal(test.expectStoreDeleted))
+		g.Expect(pdDeleted).To(Equal(test.expectPDDeleted))
+
+		if test.expectReplacement == nil {
+			g.Expect(tc.Status.MemberReplacements).To(BeEmpty())
+		} else {
+			replacement, ok := tc.Status.MemberReplacements[podName]
+			g.Expect(ok).To(BeTrue())
+			g.Expect(replacement.MemberType).To(Equal(test.expectReplacement.MemberType))
+			g.Expect(replacement.Phase).To(Equal(test.expectReplacement.Phase))
+			g.Expect(replacement.ID).To(Equal(test.expectReplacement.ID))
+		}
+
+		if test.pvcUID != "" {
+			pvcs, err := pvcInformer.Lister().List(labels.Everything())
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(len(pvcs) == 0).To(Equal(test.expectPVCDeleted))
+		}
+	}
+
+	tests := []testcase{
+		{
+			name:           "invalid member is dropped",
+			replaceMembers: []string{"test-tidb-0", "test-tikv-5"},
+		},
+		{
+			name:               "start offlining a tikv store",
+			replaceMembers:     []string{"test-tikv-1"},
+			storeStates:        []string{v1alpha1.TiKVStateUp, v1alpha1.TiKVStateUp, v1alpha1.TiKVStateUp, v1alpha1.TiKVStateUp},
+			pvcUID:             "old",
+			expectReplace:      []string{"test-tikv-1"},
+			expectReplacement:  &v1alpha1.MemberReplacement{MemberType: v1alpha1.TiKVMemberType, Phase: v1alpha1.MemberReplacementOfflining, ID: "2"},
+			expectStoreDeleted: true,
+		},
+		{
+			name:              "not enough tikv stores to offline",
+			replaceMembers:    []string{"test-tikv-1"},
+			storeStates:       []string{v1alpha1.TiKVStateUp, v1alpha1.TiKVStateUp, v1alpha1.TiKVStateUp},
+			pvcUID:            "old",
+			expectReplace:     []string{"test-tikv-1"},
+			expectReplacement: &v1alpha1.MemberReplacement{MemberType: v1alpha1.TiKVMemberType, Phase: v1alpha1.MemberReplacementOfflining, ID: "2"},
+		},
+		{
+			name:              "waiting for the tikv store to become tombstone",
+			replaceMembers:    []string{"test-tikv-1"},
+			replacement:       &v1alpha1.MemberReplacement{MemberType: v1alpha1.TiKVMemberType, Phase: v1alpha1.MemberReplacementOfflining, ID: "2", PVCUID: "old"},
+			storeStates:       []string{v1alpha1.TiKVStateUp, v1alpha1.TiKVStateOffline, v1alpha1.TiKVStateUp, v1alpha1.TiKVStateUp},
+			pvcUID:            "old",
+			expectReplace:     []string{"test-tikv-1"},
+			expectReplacement: &v1alpha1.MemberReplacement{MemberType: v1alpha1.TiKVMemberType, Phase: v1alpha1.MemberReplacementOfflining, ID: "2"},
+		},
+		{
+			name:              "tikv store is tombstone, delete the pod and PVC",
+			replaceMembers:    []string{"test-tikv-1"},
+			replacement:       &v1alpha1.MemberReplacement{MemberType: v1alpha1.TiKVMemberType, Phase: v1alpha1.MemberReplacementOfflining, ID: "2", PVCUID: "old"},
+			storeStates:       []string{v1alpha1.TiKVStateUp, "", v1alpha1.TiKVStateUp, v1alpha1.TiKVStateUp},
+			pvcUID:            "old",
+			expectReplace:     []string{"test-tikv-1"},
+			expectReplacement: &v1alpha1.MemberReplacement{MemberType: v1alpha1.TiKVMemberType, Phase: v1alpha1.MemberReplacementDeleting, ID: "2"},
+			expectPVCDeleted:  true,
+		},
+		{
+			name:              "new PVC is created, waiting for the new store",
+			replaceMembers:    []string{"test-tikv-1"},
+			replacement:       &v1alpha1.MemberReplacement{MemberType: v1alpha1.TiKVMemberType, Phase: v1alpha1.MemberReplacementDeleting, ID: "2", PVCUID: "old"},
+			storeStates:       []string{v1alpha1.TiKVStateUp, "", v1alpha1.TiKVStateUp, v1alpha1.TiKVStateUp},
+			pvcUID:            "new",
+			expectReplace:     []string{"test-tikv-1"},
+			expectReplacement: &v1alpha1.MemberReplacement{MemberType: v1alpha1.TiKVMemberType, Phase: v1alpha1.MemberReplacementJoining, ID: "2"},
+		},
+		{
+			name:              "new tikv store is up",
+			replaceMembers:    []string{"test-tikv-0", "test-pd-1"},
+			replacement:       &v1alpha1.MemberReplacement{MemberType: v1alpha1.TiKVMemberType, Phase: v1alpha1.MemberReplacementJoining, ID: "5"},
+			storeStates:       []string{v1alpha1.TiKVStateUp, v1alpha1.TiKVStateUp, v1alpha1.TiKVStateUp},
+			expectReplace:     []string{"test-pd-1"},
+			expectReplacement: &v1alpha1.MemberReplacement{MemberType: v1alpha1.TiKVMemberType, Phase: v1alpha1.MemberReplacementCompleted, ID: "1"},
+		},
+		{
+			name:              "completed member is removed from spec",
+			replaceMembers:    []string{"test-tikv-0"},
+			replacement:       &v1alpha1.MemberReplacement{MemberType: v1alpha1.TiKVMemberType, Phase: v1alpha1.MemberReplacementCompleted, ID: "1"},
+			expectReplacement: &v1alpha1.MemberReplacement{MemberType: v1alpha1.TiKVMemberType, Phase: v1alpha1.MemberReplacementCompleted, ID: "1"},
+		},
+		{
+			name:              "delete a pd member",
+			replaceMembers:    []string{"test-pd-1"},
+			pdMemberIDs:       []string{"11", "12", "13"},
+			pvcUID:            "old",
+			expectReplace:     []string{"test-pd-1"},
+			expectReplacement: &v1alpha1.MemberReplacement{MemberType: v1alpha1.PDMemberType, Phase: v1alpha1.MemberReplacementOfflining, ID: "12"},
+			expectPDDeleted:   true,
+		},
+		{
+			name:              "pd member is removed, delete the pod and PVC",
+			replaceMembers:    []string{"test-pd-1"},
+			replacement:       &v1alpha1.MemberReplacement{MemberType: v1alpha1.PDMemberType, Phase: v1alpha1.MemberReplacementOfflining, ID: "12", PVCUID: "old"},
+			pdMemberIDs:       []string{"11", "12", "13"},
+			pdRemoved:         true,
+			pvcUID:            "old",
+			expectReplace:     []string{"test-pd-1"},
+			expectReplacement: &v1alpha1.MemberReplacement{MemberType: v1alpha1.PDMemberType, Phase: v1alpha1.MemberReplacementDeleting, ID: "12"},
+			expectPVCDeleted:  true,
+		},
+		{
+			name:              "tikv store of the pod is not reported, wait for it",
+			replaceMembers:    []string{"test-tikv-1"},
+			storeStates:       []string{v1alpha1.TiKVStateUp, "", v1alpha1.TiKVStateUp, v1alpha1.TiKVStateUp},
+			pvcUID:            "old",
+			expectReplace:     []string{"test-tikv-1"},
+			expectReplacement: &v1alpha1.MemberReplacement{MemberType: v1alpha1.TiKVMemberType, Phase: v1alpha1.MemberReplacementOfflining},
+		},
+		{
+			name:           "tikv group pod is not taken as a pod of spec.tikv",
+			replaceMembers: []string{"test-tikv-group1-0"},
+			storeStates:    []string{v1alpha1.TiKVStateUp, v1alpha1.TiKVStateUp, v1alpha1.TiKVStateUp, v1alpha1.TiKVStateUp},
+		},
+		{
+			name:              "other pd members are unhealthy",
+			replaceMembers:    []string{"test-pd-1"},
+			pdMemberIDs:       []string{"11", "12", "13"},
+			pdUnhealthy:       true,
+			pvcUID:            "old",
+			expectReplace:     []string{"test-pd-1"},
+			expectReplacement: &v1alpha1.MemberReplacement{MemberType: v1alpha1.PDMemberType, Phase: v1alpha1.MemberReplacementOfflining, ID: "12"},
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
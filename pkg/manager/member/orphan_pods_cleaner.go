@@ -32,6 +32,7 @@ const (
 	skipReasonOrphanPodsCleanerPodIsNotPending = "orphan pods cleaner: pod is not pending"
 	skipReasonOrphanPodsCleanerPodIsNotFound   = "orphan pods cleaner: pod does not exist anymore"
 	skipReasonOrphanPodsCleanerPodChanged      = "orphan pods cleaner: pod changed before deletion"
+	skipReasonOrphanPodsCleanerPodIsMigrating  = "orphan pods cleaner: pod is waiting for the PVC on the new StorageClass"
 )
 
 // OrphanPodsCleaner implements the logic for cleaning the orphan pods(has no pvc)
@@ -83,7 +84,7 @@ func (opc *orphanPodsCleaner) Clean(tc *v1alpha1.TidbCluster) (map[string]string
 	for _, pod := range pods {
 		podName := pod.GetName()
 		l := label.Label(pod.Labels)
-		if !(l.IsPD() || l.IsTiKV()) {
+		if !(l.IsPD() || l.IsTiKV() || l.IsTiFlash()) {
 			skipReason[podName] = skipReasonOrphanPodsCleanerIsNotPDOrTiKV
 			continue
 		}
@@ -93,6 +94,12 @@ func (opc *orphanPodsCleaner) Clean(tc *v1alpha1.TidbCluster) (map[string]string
 			continue
 		}
 
+		// the PVC of the pod is created by the storage migrator instead of the statefulset
+		if tikvPodMigrating(tc, podName) {
+			skipReason[podName] = skipReasonOrphanPodsCleanerPodIsMigrating
+			continue
+		}
+
 		// TODO support multiple pvcs case?
 		var pvcName string
 		for _, vol := range pod.Spec.Volumes {
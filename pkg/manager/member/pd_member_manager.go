@@ -46,6 +46,7 @@ type pdMemberManager struct {
 	podLister    corelisters.PodLister
 	epsLister    corelisters.EndpointsLister
 	pvcLister    corelisters.PersistentVolumeClaimLister
+	nodeLister   corelisters.NodeLister
 	pdScaler     Scaler
 	pdUpgrader   Upgrader
 	autoFailover bool
@@ -63,6 +64,7 @@ func NewPDMemberManager(pdControl pdapi.PDControlInterface,
 	podLister corelisters.PodLister,
 	epsLister corelisters.EndpointsLister,
 	pvcLister corelisters.PersistentVolumeClaimLister,
+	nodeLister corelisters.NodeLister,
 	pdScaler Scaler,
 	pdUpgrader Upgrader,
 	autoFailover bool,
@@ -78,6 +80,7 @@ func NewPDMemberManager(pdControl pdapi.PDControlInterface,
 		podLister,
 		epsLister,
 		pvcLister,
+		nodeLister,
 		pdScaler,
 		pdUpgrader,
 		autoFailover,
@@ -224,6 +227,13 @@ func (pmm *pdMemberManager) syncPDStatefulSetForTidbCluster(tc *v1alpha1.TidbClu
 		}
 	}
 
+	if err := checkUpgradeVersion(tc, v1alpha1.PDMemberType, pmm.setLister, pmm.pdControl, oldPDSet, newPDSet); err != nil {
+		return err
+	}
+	if err := checkResize(tc, v1alpha1.PDMemberType, pmm.podLister, pmm.nodeLister, oldPDSet, newPDSet); err != nil {
+		return err
+	}
+
 	if !templateEqual(newPDSet.Spec.Template, oldPDSet.Spec.Template) || tc.Status.PD.Phase == v1alpha1.UpgradePhase {
 		if err := pmm.pdUpgrader.Upgrade(tc, oldPDSet, newPDSet); err != nil {
 			return err
@@ -255,6 +265,11 @@ func (pmm *pdMemberManager) syncPDStatefulSetForTidbCluster(tc *v1alpha1.TidbClu
 }
 
 func (pmm *pdMemberManager) syncPDClientCerts(tc *v1alpha1.TidbCluster) error {
+	return pmm.certControl.Create(controller.GetOwnerRef(tc), pdClientCertOptions(tc))
+}
+
+// pdClientCertOptions returns the options to create the PD client cert pair used by tidb-operator
+func pdClientCertOptions(tc *v1alpha1.TidbCluster) *controller.TiDBClusterCertOptions {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
 	commonName := fmt.Sprintf("%s-pd-client", tcName)
@@ -263,28 +278,33 @@ func (pmm *pdMemberManager) syncPDClientCerts(tc *v1alpha1.TidbCluster) error {
 		commonName,
 	}
 
-	certOpts := &controller.TiDBClusterCertOptions{
-		Namespace:  ns,
-		Instance:   tcName,
-		CommonName: commonName,
-		HostList:   hostList,
-		Component:  "pd",
-		Suffix:     "pd-client",
+	return &controller.TiDBClusterCertOptions{
+		Namespace:    ns,
+		Instance:     tcName,
+		CommonName:   commonName,
+		HostList:     hostList,
+		Component:    "pd",
+		Suffix:       "pd-client",
+		Issuer:       tc.Spec.TLSIssuer,
+		CASecretName: tc.TLSCASecretName(),
 	}
+}
 
+func (pmm *pdMemberManager) syncPDServerCerts(tc *v1alpha1.TidbCluster) error {
+	certOpts := pdServerCertOptions(tc)
+	if pmm.certControl.CheckSecret(certOpts.Namespace, certOpts.SecretName()) {
+		return nil
+	}
 	return pmm.certControl.Create(controller.GetOwnerRef(tc), certOpts)
 }
 
-func (pmm *pdMemberManager) syncPDServerCerts(tc *v1alpha1.TidbCluster) error {
+// pdServerCertOptions returns the options to create the PD server cert pair
+func pdServerCertOptions(tc *v1alpha1.TidbCluster) *controller.TiDBClusterCertOptions {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
 	svcName := controller.PDMemberName(tcName)
 	peerName := controller.PDPeerMemberName(tcName)
 
-	if pmm.certControl.CheckSecret(ns, svcName) {
-		return nil
-	}
-
 	hostList := []string{
 		svcName,
 		peerName,
@@ -293,16 +313,16 @@ func (pmm *pdMemberManager) syncPDServerCerts(tc *v1alpha1.TidbCluster) error {
 		fmt.Sprintf("*.%s.%s.svc", peerName, ns),
 	}
 
-	certOpts := &controller.TiDBClusterCertOptions{
-		Namespace:  ns,
-		Instance:   tcName,
-		CommonName: svcName,
-		HostList:   hostList,
-		Component:  "pd",
-		Suffix:     "pd",
+	return &controller.TiDBClusterCertOptions{
+		Namespace:    ns,
+		Instance:     tcName,
+		CommonName:   svcName,
+		HostList:     hostList,
+		Component:    "pd",
+		Suffix:       "pd",
+		Issuer:       tc.Spec.TLSIssuer,
+		CASecretName: tc.TLSCASecretName(),
 	}
-
-	return pmm.certControl.Create(controller.GetOwnerRef(tc), certOpts)
 }
 
 func (pmm *pdMemberManager) updateStatefulSet(tc *v1alpha1.TidbCluster, newPDSet, oldPDSet *apps.StatefulSet) error {
@@ -367,6 +387,7 @@ func (pmm *pdMemberManager) syncTidbClusterStatus(tc *v1alpha1.TidbCluster, set
 		return err
 	}
 	pdStatus := map[string]v1alpha1.PDMember{}
+	peerPDStatus := map[string]v1alpha1.PDMember{}
 	for _, memberHealth := range healthInfo.Healths {
 		id := memberHealth.MemberID
 		memberID := fmt.Sprintf("%d", id)
@@ -388,6 +409,17 @@ func (pmm *pdMemberManager) syncTidbClusterStatus(tc *v1alpha1.TidbCluster, set
 			Health:    memberHealth.Health,
 		}
 
+		// the members of the other clusters joining the same PD cluster
+		if isPeerMemberAddress(clientURL, controller.PDPeerMemberName(tcName), ns) {
+			oldPDMember, exist := tc.Status.PD.PeerMembers[name]
+			status.LastTransitionTime = metav1.Now()
+			if exist && status.Health == oldPDMember.Health {
+				status.LastTransitionTime = oldPDMember.LastTransitionTime
+			}
+			peerPDStatus[name] = status
+			continue
+		}
+
 		oldPDMember, exist := tc.Status.PD.Members[name]
 
 		status.LastTransitionTime = metav1.Now()
@@ -397,10 +429,17 @@ func (pmm *pdMemberManager) syncTidbClusterStatus(tc *v1alpha1.TidbCluster, set
 
 		pdStatus[name] = status
 	}
+	if len(peerPDStatus) == 0 {
+		peerPDStatus = nil
+	}
 
 	tc.Status.PD.Synced = true
 	tc.Status.PD.Members = pdStatus
+	tc.Status.PD.PeerMembers = peerPDStatus
 	tc.Status.PD.Leader = tc.Status.PD.Members[leader.GetName()]
+	if peerLeader, ok := peerPDStatus[leader.GetName()]; ok {
+		tc.Status.PD.Leader = peerLeader
+	}
 
 	return nil
 }
@@ -534,13 +573,7 @@ func getNewPDSetForTidbCluster(tc *v1alpha1.TidbCluster) (*apps.StatefulSet, err
 		},
 	}
 	if tc.Spec.EnableTLSCluster {
-		vols = append(vols, corev1.Volume{
-			Name: "pd-tls", VolumeSource: corev1.VolumeSource{
-				Secret: &corev1.SecretVolumeSource{
-					SecretName: controller.PDMemberName(tcName),
-				},
-			},
-		})
+		vols = append(vols, tlsSecretVolume(tc, "pd-tls", controller.PDMemberName(tcName)))
 	}
 
 	var q resource.Quantity
@@ -555,6 +588,8 @@ func getNewPDSetForTidbCluster(tc *v1alpha1.TidbCluster) (*apps.StatefulSet, err
 	pdLabel := label.New().Instance(instanceName).PD()
 	setName := controller.PDMemberName(tcName)
 	podAnnotations := CombineAnnotations(controller.AnnProm(2379), tc.BasePDSpec().Annotations())
+	podAnnotations = CombineAnnotations(podAnnotations, tlsCertRenewAnnotations(tc, setName))
+	podAnnotations = CombineAnnotations(podAnnotations, storageResizeAnnotations(tc, v1alpha1.PDMemberType.String()))
 	storageClassName := tc.Spec.PD.StorageClassName
 	if storageClassName == "" {
 		storageClassName = controller.DefaultStorageClassName
@@ -592,6 +627,7 @@ func getNewPDSetForTidbCluster(tc *v1alpha1.TidbCluster) (*apps.StatefulSet, err
 			Value: tc.Spec.Timezone,
 		},
 	}
+	env = append(env, clusterDomainEnv(tc)...)
 
 	dnsPolicy := corev1.DNSClusterFirst // same as k8s defaults
 	if tc.BasePDSpec().HostNetwork() {
@@ -338,6 +338,29 @@ func TestPDMemberManagerSyncUpdate(t *testing.T) {
 				g.Expect(tc.Status.PD.Members["pd3"].Health).To(Equal(false))
 			},
 		},
+		{
+			name: "members of other clusters joining the PD cluster",
+			modify: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.PD.Replicas = 5
+			},
+			pdHealth: &pdapi.HealthInfo{Healths: []pdapi.MemberHealth{
+				{Name: "pd1", MemberID: uint64(1), ClientUrls: []string{"http://pd1.test-pd-peer.default.svc:2379"}, Health: true},
+				{Name: "pd2", MemberID: uint64(2), ClientUrls: []string{"http://pd2:2379"}, Health: true},
+				{Name: "pd3", MemberID: uint64(3), ClientUrls: []string{"http://pd3.demo-pd-peer.other.svc.cluster2.com:2379"}, Health: true},
+			}},
+			expectPDServiceFn:     nil,
+			expectPDPeerServiceFn: nil,
+			expectStatefulSetFn: func(g *GomegaWithT, set *apps.StatefulSet, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+			expectTidbClusterFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster) {
+				g.Expect(tc.Status.PD.Members).To(HaveLen(2))
+				g.Expect(tc.Status.PD.Members).To(HaveKey("pd1"))
+				g.Expect(tc.Status.PD.Members).To(HaveKey("pd2"))
+				g.Expect(tc.Status.PD.PeerMembers).To(HaveLen(1))
+				g.Expect(tc.Status.PD.PeerMembers).To(HaveKey("pd3"))
+			},
+		},
 		{
 			name: "tidbcluster's storage format is wrong",
 			modify: func(tc *v1alpha1.TidbCluster) {
@@ -777,6 +800,7 @@ func newFakePDMemberManager() (*pdMemberManager, *controller.FakeStatefulSetCont
 	podInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().Pods()
 	epsInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().Endpoints()
 	pvcInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().PersistentVolumeClaims()
+	nodeInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().Nodes()
 	tcInformer := informers.NewSharedInformerFactory(cli, 0).Pingcap().V1alpha1().TidbClusters()
 	csrInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Certificates().V1beta1().CertificateSigningRequests()
 	secretInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().Secrets()
@@ -802,6 +826,7 @@ func newFakePDMemberManager() (*pdMemberManager, *controller.FakeStatefulSetCont
 		podInformer.Lister(),
 		epsInformer.Lister(),
 		pvcInformer.Lister(),
+		nodeInformer.Lister(),
 		pdScaler,
 		pdUpgrader,
 		autoFailover,
@@ -17,6 +17,7 @@ import (
 	"bytes"
 	"crypto/sha256"
 	"fmt"
+	"path"
 	"text/template"
 
 	"github.com/BurntSushi/toml"
@@ -24,6 +25,7 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/label"
 	"github.com/pingcap/tidb-operator/pkg/manager"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
 	"github.com/pingcap/tidb-operator/pkg/util"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -38,6 +40,8 @@ import (
 
 const (
 	defaultPumpLogLevel = "info"
+	// pumpTLSMountPath is the path where the TLS certificate of pump is mounted
+	pumpTLSMountPath = "/var/lib/pump-tls"
 )
 
 // pumpStartScriptTpl is the template string of pump start script
@@ -58,29 +62,38 @@ if [ $? == 0 ]; then
 fi`))
 
 type pumpMemberManager struct {
-	setControl controller.StatefulSetControlInterface
-	svcControl controller.ServiceControlInterface
-	cmControl  controller.ConfigMapControlInterface
-	setLister  v1.StatefulSetLister
-	svcLister  corelisters.ServiceLister
-	cmLister   corelisters.ConfigMapLister
+	pdControl   pdapi.PDControlInterface
+	setControl  controller.StatefulSetControlInterface
+	svcControl  controller.ServiceControlInterface
+	cmControl   controller.ConfigMapControlInterface
+	certControl controller.CertControlInterface
+	setLister   v1.StatefulSetLister
+	svcLister   corelisters.ServiceLister
+	cmLister    corelisters.ConfigMapLister
+	podLister   corelisters.PodLister
 }
 
 // NewPumpMemberManager returns a controller to reconcile pump clusters
 func NewPumpMemberManager(
+	pdControl pdapi.PDControlInterface,
 	setControl controller.StatefulSetControlInterface,
 	svcControl controller.ServiceControlInterface,
 	cmControl controller.ConfigMapControlInterface,
+	certControl controller.CertControlInterface,
 	setLister v1.StatefulSetLister,
 	svcLister corelisters.ServiceLister,
-	cmLister corelisters.ConfigMapLister) manager.Manager {
+	cmLister corelisters.ConfigMapLister,
+	podLister corelisters.PodLister) manager.Manager {
 	return &pumpMemberManager{
+		pdControl,
 		setControl,
 		svcControl,
 		cmControl,
+		certControl,
 		setLister,
 		svcLister,
 		cmLister,
+		podLister,
 	}
 }
 
@@ -95,7 +108,6 @@ func (pmm *pumpMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
 }
 
 // syncStatefulSet syncs the pump statefulset
-// TODO: sync statefulset status of pump to tidbcluster
 func (pmm *pumpMemberManager) syncStatefulSet(tc *v1alpha1.TidbCluster) error {
 
 	oldPumpSetTemp, err := pmm.setLister.StatefulSets(tc.Namespace).Get(controller.PumpMemberName(tc.Name))
@@ -119,9 +131,22 @@ func (pmm *pumpMemberManager) syncStatefulSet(tc *v1alpha1.TidbCluster) error {
 		if err != nil {
 			return err
 		}
+		if tc.Spec.EnableTLSCluster {
+			if err := pmm.syncPumpCerts(tc); err != nil {
+				return err
+			}
+		}
 		return pmm.setControl.CreateStatefulSet(tc, newPumpSet)
 	}
 
+	if err := pmm.syncTidbClusterStatus(tc, oldPumpSet); err != nil {
+		return err
+	}
+
+	if err := checkUpgradeVersion(tc, v1alpha1.PumpMemberType, pmm.setLister, pmm.pdControl, oldPumpSet, newPumpSet); err != nil {
+		return err
+	}
+
 	isOrphan := metav1.GetControllerOf(oldPumpSet) == nil
 
 	if !statefulSetEqual(*newPumpSet, *oldPumpSet) || isOrphan {
@@ -143,6 +168,41 @@ func (pmm *pumpMemberManager) syncStatefulSet(tc *v1alpha1.TidbCluster) error {
 	return nil
 }
 
+// syncTidbClusterStatus syncs the statefulset status and the nodes of the pump pods, the node of a pump member
+// is kept for the stable scheduling of tidb-scheduler when its pod is recreated
+func (pmm *pumpMemberManager) syncTidbClusterStatus(tc *v1alpha1.TidbCluster, set *appsv1.StatefulSet) error {
+	tc.Status.Pump.StatefulSet = &set.Status
+
+	_, pumpLabel := getPumpMeta(tc, controller.PumpMemberName)
+	selector, err := pumpLabel.Selector()
+	if err != nil {
+		return err
+	}
+	pods, err := pmm.podLister.Pods(tc.GetNamespace()).List(selector)
+	if err != nil {
+		return err
+	}
+	scheduledNodes := map[string]string{}
+	for _, pod := range pods {
+		scheduledNodes[pod.GetName()] = pod.Spec.NodeName
+	}
+
+	members := map[string]v1alpha1.PumpMember{}
+	for i := int32(0); i < *set.Spec.Replicas; i++ {
+		podName := fmt.Sprintf("%s-%d", controller.PumpMemberName(tc.GetName()), i)
+		member := v1alpha1.PumpMember{Name: podName}
+		if oldMember, exist := tc.Status.Pump.Members[podName]; exist {
+			member.NodeName = oldMember.NodeName
+		}
+		if nodeName := scheduledNodes[podName]; nodeName != "" {
+			member.NodeName = nodeName
+		}
+		members[podName] = member
+	}
+	tc.Status.Pump.Members = members
+	return nil
+}
+
 func (pmm *pumpMemberManager) syncHeadlessService(tc *v1alpha1.TidbCluster) error {
 
 	newSvc := getNewPumpHeadlessService(tc)
@@ -237,6 +297,41 @@ func (pmm *pumpMemberManager) syncConfigMap(tc *v1alpha1.TidbCluster, set *appsv
 	return oldCm, nil
 }
 
+// syncPumpCerts creates the cert pair for pump if not exist
+func (pmm *pumpMemberManager) syncPumpCerts(tc *v1alpha1.TidbCluster) error {
+	certOpts := pumpCertOptions(tc)
+	if pmm.certControl.CheckSecret(certOpts.Namespace, certOpts.SecretName()) {
+		return nil
+	}
+	return pmm.certControl.Create(controller.GetOwnerRef(tc), certOpts)
+}
+
+// pumpCertOptions returns the options to create the pump cert pair
+func pumpCertOptions(tc *v1alpha1.TidbCluster) *controller.TiDBClusterCertOptions {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	svcName := controller.PumpMemberName(tcName)
+
+	hostList := []string{
+		svcName,
+		fmt.Sprintf("%s.%s", svcName, ns),
+		fmt.Sprintf("*.%s", svcName),
+		fmt.Sprintf("*.%s.%s", svcName, ns),
+		fmt.Sprintf("*.%s.%s.svc", svcName, ns),
+	}
+
+	return &controller.TiDBClusterCertOptions{
+		Namespace:    ns,
+		Instance:     tcName,
+		CommonName:   svcName,
+		HostList:     hostList,
+		Component:    "pump",
+		Suffix:       "pump",
+		Issuer:       tc.Spec.TLSIssuer,
+		CASecretName: tc.TLSCASecretName(),
+	}
+}
+
 func getNewPumpHeadlessService(tc *v1alpha1.TidbCluster) *corev1.Service {
 	if tc.Spec.Pump == nil {
 		return nil
@@ -267,9 +362,14 @@ func getNewPumpConfigMap(tc *v1alpha1.TidbCluster) (*corev1.ConfigMap, error) {
 	spec := tc.Spec.Pump
 	objMeta, _ := getPumpMeta(tc, controller.PumpMemberName)
 
+	config := spec.Config
+	if tc.Spec.EnableTLSCluster {
+		config = withPumpSecurityConfig(tc, config)
+	}
+
 	buff := new(bytes.Buffer)
 	encoder := toml.NewEncoder(buff)
-	err := encoder.Encode(spec.Config)
+	err := encoder.Encode(config)
 	if err != nil {
 		return nil, err
 	}
@@ -300,6 +400,8 @@ func getNewPumpStatefulSet(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap) (*app
 	replicas := tc.Spec.Pump.Replicas
 	storageClass := tc.Spec.Pump.StorageClassName
 	podAnnos := CombineAnnotations(controller.AnnProm(8250), spec.Annotations())
+	podAnnos = CombineAnnotations(podAnnos, tlsCertRenewAnnotations(tc, controller.PumpMemberName(tc.Name)))
+	podAnnos = CombineAnnotations(podAnnos, storageResizeAnnotations(tc, v1alpha1.PumpMemberType.String()))
 	storageRequest, err := controller.ParseStorageRequest(tc.Spec.Pump.Requests)
 	if err != nil {
 		return nil, fmt.Errorf("cannot parse storage request for pump, tidbcluster %s/%s, error: %v", tc.Namespace, tc.Name, err)
@@ -376,6 +478,13 @@ func getNewPumpStatefulSet(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap) (*app
 		},
 	}
 
+	if tc.Spec.EnableTLSCluster {
+		containers[0].VolumeMounts = append(containers[0].VolumeMounts, corev1.VolumeMount{
+			Name: "pump-tls", ReadOnly: true, MountPath: pumpTLSMountPath,
+		})
+		volumes = append(volumes, tlsSecretVolume(tc, "pump-tls", controller.PumpMemberName(tc.Name)))
+	}
+
 	volumeClaims := []corev1.PersistentVolumeClaim{
 		{
 			ObjectMeta: metav1.ObjectMeta{
@@ -438,8 +547,7 @@ func getPumpMeta(tc *v1alpha1.TidbCluster, nameFunc func(string) string) (metav1
 
 func getPumpStartScript(tc *v1alpha1.TidbCluster) (string, error) {
 	buff := new(bytes.Buffer)
-	// Keep the logic same as helm chart, but pump has not supported tls yet (no cert mounted)
-	// TODO: support tls
+	// Keep the logic same as helm chart, the TLS certificate is configured in the security section of the config
 	scheme := "http"
 	if tc.Spec.EnableTLSCluster {
 		scheme = "https"
@@ -455,6 +563,24 @@ func getPumpStartScript(tc *v1alpha1.TidbCluster) (string, error) {
 	return buff.String(), nil
 }
 
+// withPumpSecurityConfig returns a copy of the pump config whose security section points to the mounted
+// TLS certificate, the security section set by the user is respected
+func withPumpSecurityConfig(tc *v1alpha1.TidbCluster, config map[string]interface{}) map[string]interface{} {
+	if _, ok := config["security"]; ok {
+		return config
+	}
+	result := make(map[string]interface{}, len(config)+1)
+	for k, v := range config {
+		result[k] = v
+	}
+	result["security"] = map[string]interface{}{
+		"ssl-ca":   tlsCAPath(tc, pumpTLSMountPath),
+		"ssl-cert": path.Join(pumpTLSMountPath, controller.TLSSecretCertKey),
+		"ssl-key":  path.Join(pumpTLSMountPath, controller.TLSSecretKeyKey),
+	}
+	return result
+}
+
 func getPumpLogLevel(tc *v1alpha1.TidbCluster) string {
 
 	config := tc.Spec.Pump.Config
@@ -24,6 +24,7 @@ import (
 	informers "github.com/pingcap/tidb-operator/pkg/client/informers/externalversions"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/label"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
 	"github.com/pingcap/tidb-operator/pkg/util/config"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -426,6 +427,7 @@ type pumpFakeIndexers struct {
 	cm  cache.Indexer
 	svc cache.Indexer
 	set cache.Indexer
+	pod cache.Indexer
 }
 
 type pumpFakeControls struct {
@@ -442,16 +444,25 @@ func newFakePumpMemberManager() (*pumpMemberManager, *pumpFakeControls, *pumpFak
 	svcInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().Services()
 	epsInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().Endpoints()
 	cmInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().ConfigMaps()
+	podInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().Pods()
 	setControl := controller.NewFakeStatefulSetControl(setInformer, tcInformer)
 	svcControl := controller.NewFakeServiceControl(svcInformer, epsInformer, tcInformer)
 	cmControl := controller.NewFakeConfigMapControl(cmInformer)
+	pdControl := pdapi.NewFakePDControl(kubeCli)
+	csrInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Certificates().V1beta1().CertificateSigningRequests()
+	secretInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().Secrets()
+	secControl := controller.NewFakeSecretControl(kubeCli, secretInformer.Lister())
+	certControl := controller.NewFakeCertControl(kubeCli, csrInformer.Lister(), secControl)
 	pmm := &pumpMemberManager{
+		pdControl,
 		setControl,
 		svcControl,
 		cmControl,
+		certControl,
 		setInformer.Lister(),
 		svcInformer.Lister(),
 		cmInformer.Lister(),
+		podInformer.Lister(),
 	}
 	controls := &pumpFakeControls{
 		svc: svcControl,
@@ -463,10 +474,49 @@ func newFakePumpMemberManager() (*pumpMemberManager, *pumpFakeControls, *pumpFak
 		svc: svcInformer.Informer().GetIndexer(),
 		cm:  cmInformer.Informer().GetIndexer(),
 		set: setInformer.Informer().GetIndexer(),
+		pod: podInformer.Informer().GetIndexer(),
 	}
 	return pmm, controls, indexers
 }
 
+func TestPumpMemberManagerSyncTidbClusterStatus(t *testing.T) {
+	g := NewGomegaWithT(t)
+	pmm, _, indexers := newFakePumpMemberManager()
+	tc := newTidbClusterForPump()
+	tc.Status.Pump.Members = map[string]v1alpha1.PumpMember{
+		"test-pump-1": {Name: "test-pump-1", NodeName: "node-1"},
+		"test-pump-3": {Name: "test-pump-3", NodeName: "node-3"},
+	}
+	_, pumpLabel := getPumpMeta(tc, controller.PumpMemberName)
+	for name, nodeName := range map[string]string{"test-pump-0": "node-0", "test-pump-1": ""} {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: corev1.NamespaceDefault,
+				Labels:    pumpLabel,
+			},
+			Spec: corev1.PodSpec{NodeName: nodeName},
+		}
+		g.Expect(indexers.pod.Add(pod)).To(Succeed())
+	}
+	set := &appsv1.StatefulSet{
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: controller.Int32Ptr(3),
+		},
+		Status: appsv1.StatefulSetStatus{
+			Replicas: 2,
+		},
+	}
+
+	g.Expect(pmm.syncTidbClusterStatus(tc, set)).To(Succeed())
+	g.Expect(tc.Status.Pump.StatefulSet.Replicas).To(Equal(int32(2)))
+	g.Expect(tc.Status.Pump.Members).To(Equal(map[string]v1alpha1.PumpMember{
+		"test-pump-0": {Name: "test-pump-0", NodeName: "node-0"},
+		"test-pump-1": {Name: "test-pump-1", NodeName: "node-1"},
+		"test-pump-2": {Name: "test-pump-2"},
+	}))
+}
+
 func newTidbClusterForPump() *v1alpha1.TidbCluster {
 	return &v1alpha1.TidbCluster{
 		TypeMeta: metav1.TypeMeta{
@@ -697,6 +747,60 @@ func TestGetNewPumpConfigMap(t *testing.T) {
 
 [storage]
   sync-log = "true"
+`,
+				},
+			},
+		},
+		{
+			name: "tls cluster with self-signed CA",
+			tc: v1alpha1.TidbCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo",
+					Namespace: "ns",
+				},
+				Spec: v1alpha1.TidbClusterSpec{
+					EnableTLSCluster: true,
+					TLSIssuer:        &v1alpha1.TLSIssuer{Type: v1alpha1.TLSIssuerSelfSignedCA},
+					Pump: &v1alpha1.PumpSpec{
+						GenericConfig: config.New(map[string]interface{}{
+							"gc": 7,
+						}),
+						ConfigUpdateStrategy: v1alpha1.ConfigUpdateStrategyInPlace,
+					},
+				},
+			},
+			expected: corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo-pump",
+					Namespace: "ns",
+					Labels: map[string]string{
+						"app.kubernetes.io/name":       "tidb-cluster",
+						"app.kubernetes.io/managed-by": "tidb-operator",
+						"app.kubernetes.io/instance":   "",
+						"app.kubernetes.io/component":  "pump",
+					},
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							APIVersion: "pingcap.com/v1alpha1",
+							Kind:       "TidbCluster",
+							Name:       "foo",
+							UID:        "",
+							Controller: func(b bool) *bool {
+								return &b
+							}(true),
+							BlockOwnerDeletion: func(b bool) *bool {
+								return &b
+							}(true),
+						},
+					},
+				},
+				Data: map[string]string{
+					"pump-config": `gc = 7
+
+[security]
+  ssl-ca = "/var/lib/pump-tls/ca"
+  ssl-cert = "/var/lib/pump-tls/cert"
+  ssl-key = "/var/lib/pump-tls/key"
 `,
 				},
 			},
@@ -102,7 +102,7 @@ func (rpc *realPVCCleaner) reclaimPV(tc *v1alpha1.TidbCluster) (map[string]strin
 	for _, pvc := range pvcs {
 		pvcName := pvc.GetName()
 		l := label.Label(pvc.Labels)
-		if !(l.IsPD() || l.IsTiKV()) {
+		if !(l.IsPD() || l.IsTiKV() || l.IsTiFlash()) {
 			skipReason[pvcName] = skipReasonPVCCleanerIsNotPDOrTiKV
 			continue
 		}
@@ -209,7 +209,7 @@ func (rpc *realPVCCleaner) cleanScheduleLock(tc *v1alpha1.TidbCluster) (map[stri
 	for _, pvc := range pvcs {
 		pvcName := pvc.GetName()
 		l := label.Label(pvc.Labels)
-		if !(l.IsPD() || l.IsTiKV()) {
+		if !(l.IsPD() || l.IsTiKV() || l.IsTiFlash()) {
 			skipReason[pvcName] = skipReasonPVCCleanerIsNotPDOrTiKV
 			continue
 		}
@@ -0,0 +1,354 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	"github.com/pingcap/tidb-operator/pkg/manager"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	storagelisters "k8s.io/client-go/listers/storage/v1"
+	"k8s.io/client-go/tools/record"
+	glog "k8s.io/klog"
+)
+
+const (
+	// isDefaultStorageClassAnnotation marks the default StorageClass of the Kubernetes cluster
+	isDefaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+	// fileSystemResizeGracePeriod is how long a PVC stays FileSystemResizePending before its pod is restarted,
+	// the file systems resized online by kubelet leave the condition in the meantime
+	fileSystemResizeGracePeriod = 5 * time.Minute
+)
+
+// storageComponent is a component whose PVCs are expanded by the pvcResizer
+type storageComponent struct {
+	memberType v1alpha1.MemberType
+	// key is the key of the storage status of the component
+	key string
+	// vctName and setName are the names of the volumeClaimTemplate and the statefulset creating the PVCs
+	vctName string
+	setName string
+	request *v1alpha1.ResourceRequirement
+}
+
+type pvcResizer struct {
+	pvcLister  corelisters.PersistentVolumeClaimLister
+	podLister  corelisters.PodLister
+	scLister   storagelisters.StorageClassLister
+	pvcControl controller.PVCControlInterface
+	recorder   record.EventRecorder
+}
+
+// NewPVCResizer returns a manager which expands the PVCs of PD, TiKV and Pump to the storage size
+// requested in the spec. The volumeClaimTemplates of the statefulsets can't be updated, so the PVCs
+// are patched one by one if their StorageClass allows volume expansion. If the file systems are
+// resized offline, the pods are restarted by the upgraders of their components.
+func NewPVCResizer(pvcLister corelisters.PersistentVolumeClaimLister, podLister corelisters.PodLister,
+	scLister storagelisters.StorageClassLister, pvcControl controller.PVCControlInterface, recorder record.EventRecorder) manager.Manager {
+	return &pvcResizer{
+		pvcLister:  pvcLister,
+		podLister:  podLister,
+		scLister:   scLister,
+		pvcControl: pvcControl,
+		recorder:   recorder,
+	}
+}
+
+func (prm *pvcResizer) Sync(tc *v1alpha1.TidbCluster) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	components := []storageComponent{
+		{
+			memberType: v1alpha1.PDMemberType,
+			key:        v1alpha1.PDMemberType.String(),
+			vctName:    v1alpha1.PDMemberType.String(),
+			setName:    controller.PDMemberName(tcName),
+			request:    tc.Spec.PD.Requests,
+		},
+		{
+			memberType: v1alpha1.TiKVMemberType,
+			key:        v1alpha1.TiKVMemberType.String(),
+			vctName:    v1alpha1.TiKVMemberType.String(),
+			setName:    controller.TiKVMemberName(tcName),
+			request:    tc.Spec.TiKV.Requests,
+		},
+	}
+	for _, group := range tc.Spec.TiKVGroups {
+		components = append(components, storageComponent{
+			memberType: v1alpha1.TiKVMemberType,
+			key:        tikvGroupStorageKey(group.Name),
+			vctName:    v1alpha1.TiKVMemberType.String(),
+			setName:    controller.TiKVGroupMemberName(tcName, group.Name),
+			request:    group.Requests,
+		})
+	}
+	if tc.Spec.Pump != nil {
+		components = append(components, storageComponent{
+			memberType: v1alpha1.PumpMemberType,
+			key:        v1alpha1.PumpMemberType.String(),
+			vctName:    "data",
+			setName:    controller.PumpMemberName(tcName),
+			request:    tc.Spec.Pump.Requests,
+		})
+	}
+
+	storage := map[string]v1alpha1.StorageStatus{}
+	var errs []error
+	for _, component := range components {
+		status, err := prm.syncComponent(tc, component)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if status != nil {
+			storage[component.key] = *status
+		}
+	}
+	if len(storage) == 0 {
+		storage = nil
+	}
+	tc.Status.Storage = storage
+
+	if len(errs) > 0 {
+		return fmt.Errorf("tidbcluster: [%s/%s] failed to resize PVCs, %v", ns, tcName, errs)
+	}
+	return nil
+}
+
+// syncComponent expands the PVCs of the component and returns their resize progress
+func (prm *pvcResizer) syncComponent(tc *v1alpha1.TidbCluster, component storageComponent) (*v1alpha1.StorageStatus, error) {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	memberType := component.memberType
+
+	oldStatus, exist := tc.Status.Storage[component.key]
+	if component.request == nil || component.request.Storage == "" {
+		return nil, nil
+	}
+	size, err := resource.ParseQuantity(component.request.Storage)
+	if err != nil {
+		if exist {
+			return &oldStatus, err
+		}
+		return nil, fmt.Errorf("cant' parse storage size: %s of %s, %v", component.request.Storage, component.key, err)
+	}
+
+	selector, err := label.New().Instance(tc.GetLabels()[label.InstanceLabelKey]).Component(memberType.String()).Selector()
+	if err != nil {
+		return nil, err
+	}
+	pvcs, err := prm.pvcLister.PersistentVolumeClaims(ns).List(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &v1alpha1.StorageStatus{
+		Size:                     size.String(),
+		LastFileSystemResizeTime: oldStatus.LastFileSystemResizeTime,
+	}
+	pvcPattern := storagePVCPattern(component.vctName, component.setName)
+	pvcStatuses := map[string]v1alpha1.PVCResizeStatus{}
+	var restart []string
+	var errs []error
+	for _, pvc := range pvcs {
+		if !pvcPattern.MatchString(pvc.GetName()) {
+			continue
+		}
+		pvcStatus, err := prm.resizePVC(tc, pvc, size)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if pvcStatus == nil {
+			continue
+		}
+		if old, ok := oldStatus.PVCs[pvc.GetName()]; ok && old.Phase == pvcStatus.Phase {
+			pvcStatus.LastTransitionTime = old.LastTransitionTime
+		} else if pvcStatus.Phase == v1alpha1.PVCResizeUnsupported {
+			prm.recorder.Event(tc, corev1.EventTypeWarning, "PVCResizeUnsupported", pvcStatus.Message)
+		}
+		pvcStatuses[pvc.GetName()] = *pvcStatus
+
+		podName := strings.TrimPrefix(pvc.GetName(), component.vctName+"-")
+		pending, err := prm.fileSystemResizePending(tc, status, podName, pvcStatus)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if pending {
+			restart = append(restart, podName)
+		}
+	}
+	if len(pvcStatuses) > 0 {
+		status.PVCs = pvcStatuses
+	}
+	if len(restart) > 0 {
+		// restart the pods through the upgrader to resize the file systems
+		glog.Infof("tidbcluster: [%s/%s]'s %s pods are restarted to resize the file systems of pods %v",
+			ns, tcName, component.key, restart)
+		now := metav1.Now()
+		status.LastFileSystemResizeTime = &now
+	}
+
+	if len(errs) > 0 {
+		return status, fmt.Errorf("%s: %v", component.key, errs)
+	}
+	return status, nil
+}
+
+// fileSystemResizePending returns whether the file system of the PVC of the pod waits for the pod to restart.
+// The PVC must stay FileSystemResizePending for fileSystemResizeGracePeriod, and neither the pod nor the pods
+// of the component may have been restarted since the PVC entered the phase.
+func (prm *pvcResizer) fileSystemResizePending(tc *v1alpha1.TidbCluster, status *v1alpha1.StorageStatus,
+	podName string, pvcStatus *v1alpha1.PVCResizeStatus) (bool, error) {
+	if pvcStatus.Phase != v1alpha1.PVCFileSystemResizePending {
+		return false, nil
+	}
+	pendingSince := pvcStatus.LastTransitionTime
+	if time.Since(pendingSince.Time) < fileSystemResizeGracePeriod {
+		return false, nil
+	}
+	if status.LastFileSystemResizeTime != nil && !status.LastFileSystemResizeTime.Before(&pendingSince) {
+		// the pods are being restarted
+		return false, nil
+	}
+	pod, err := prm.podLister.Pods(tc.GetNamespace()).Get(podName)
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return pod.CreationTimestamp.Before(&pendingSince), nil
+}
+
+// resizePVC expands the PVC to size if it requests less, and returns its resize progress,
+// nil is returned if the volume of the PVC is expanded to size
+func (prm *pvcResizer) resizePVC(tc *v1alpha1.TidbCluster, pvc *corev1.PersistentVolumeClaim, size resource.Quantity) (*v1alpha1.PVCResizeStatus, error) {
+	ns := tc.GetNamespace()
+	pvcName := pvc.GetName()
+
+	capacity := pvc.Status.Capacity[corev1.ResourceStorage]
+	status := &v1alpha1.PVCResizeStatus{
+		CurrentSize:        capacity.String(),
+		LastTransitionTime: metav1.Now(),
+	}
+
+	request := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	if request.Cmp(size) < 0 {
+		allowed, err := prm.allowVolumeExpansion(pvc)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			status.Phase = v1alpha1.PVCResizeUnsupported
+			status.Message = fmt.Sprintf("the StorageClass of PVC %s/%s doesn't allow volume expansion", ns, pvcName)
+			return status, nil
+		}
+		newPVC := pvc.DeepCopy()
+		newPVC.Spec.Resources.Requests[corev1.ResourceStorage] = size
+		if _, err := prm.pvcControl.UpdatePVC(tc, newPVC); err != nil {
+			return nil, err
+		}
+		glog.Infof("PVC: [%s/%s] is expanded from %s to %s", ns, pvcName, request.String(), size.String())
+		status.Phase = v1alpha1.PVCResizing
+		return status, nil
+	}
+	if request.Cmp(size) > 0 {
+		glog.Warningf("PVC: [%s/%s] requests %s, shrinking to %s is not supported", ns, pvcName, request.String(), size.String())
+	}
+	if capacity.Cmp(request) >= 0 {
+		return nil, nil
+	}
+
+	status.Phase = v1alpha1.PVCResizing
+	for _, cond := range pvc.Status.Conditions {
+		if cond.Type == corev1.PersistentVolumeClaimFileSystemResizePending && cond.Status == corev1.ConditionTrue {
+			status.Phase = v1alpha1.PVCFileSystemResizePending
+			status.Message = cond.Message
+		}
+	}
+	return status, nil
+}
+
+// allowVolumeExpansion returns whether the StorageClass of the PVC allows volume expansion,
+// the default StorageClass is used if the PVC doesn't specify one
+func (prm *pvcResizer) allowVolumeExpansion(pvc *corev1.PersistentVolumeClaim) (bool, error) {
+	if pvc.Spec.StorageClassName != nil && *pvc.Spec.StorageClassName != "" {
+		sc, err := prm.scLister.Get(*pvc.Spec.StorageClassName)
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return sc.AllowVolumeExpansion != nil && *sc.AllowVolumeExpansion, nil
+	}
+	scs, err := prm.scLister.List(labels.Everything())
+	if err != nil {
+		return false, err
+	}
+	for _, sc := range scs {
+		if sc.Annotations[isDefaultStorageClassAnnotation] == "true" {
+			return sc.AllowVolumeExpansion != nil && *sc.AllowVolumeExpansion, nil
+		}
+	}
+	return false, nil
+}
+
+// storagePVCPattern matches the names of the PVCs created from the volumeClaimTemplate named
+// vctName of the statefulset named setName
+func storagePVCPattern(vctName, setName string) *regexp.Regexp {
+	return regexp.MustCompile(fmt.Sprintf("^%s-%s-[0-9]+$", regexp.QuoteMeta(vctName), regexp.QuoteMeta(setName)))
+}
+
+// tikvGroupStorageKey returns the key of the storage status of the TiKV group
+func tikvGroupStorageKey(group string) string {
+	return fmt.Sprintf("%s-%s", v1alpha1.TiKVMemberType, group)
+}
+
+// storageResizeAnnotations returns the pod annotation of the last time the pods of the component keyed by key were
+// restarted, so that the file systems resized offline are resized after the restart
+func storageResizeAnnotations(tc *v1alpha1.TidbCluster, key string) map[string]string {
+	status, ok := tc.Status.Storage[key]
+	if !ok || status.LastFileSystemResizeTime == nil {
+		return nil
+	}
+	return map[string]string{label.AnnFileSystemResizeTime: status.LastFileSystemResizeTime.UTC().Format(time.RFC3339)}
+}
+
+type FakePVCResizer struct {
+	err error
+}
+
+func NewFakePVCResizer() *FakePVCResizer {
+	return &FakePVCResizer{}
+}
+
+func (fpr *FakePVCResizer) SetSyncError(err error) {
+	fpr.err = err
+}
+
+func (fpr *FakePVCResizer) Sync(_ *v1alpha1.TidbCluster) error {
+	return fpr.err
+}
@@ -0,0 +1,337 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestPVCResizerSync(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name            string
+		request         string
+		capacity        string
+		fsResizePending bool
+		// pendingFor is how long the PVC has been FileSystemResizePending in the last status
+		pendingFor time.Duration
+		// podRestarted is whether the pod was restarted after the PVC became FileSystemResizePending
+		podRestarted bool
+		// lastRestarted is whether the pods were restarted after the PVC became FileSystemResizePending
+		lastRestarted        bool
+		allowExpansion       bool
+		expectRequest        string
+		expectPhase          v1alpha1.PVCResizePhase
+		expectRestart        bool
+		expectEventRecorded  bool
+		expectPVCStatusEmpty bool
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+
+		resizer, pvcIndexer, podIndexer, scIndexer, recorder := newFakePVCResizer()
+
+		tc := newTidbClusterForPVCResizer()
+		scIndexer.Add(&storagev1.StorageClass{
+			ObjectMeta:           metav1.ObjectMeta{Name: "my-storage-class"},
+			AllowVolumeExpansion: &test.allowExpansion,
+		})
+		pvc := newPVCForPVCResizer(tc, "pd-test-pd-0", test.request, test.capacity)
+		if test.fsResizePending {
+			pvc.Status.Conditions = []corev1.PersistentVolumeClaimCondition{
+				{Type: corev1.PersistentVolumeClaimFileSystemResizePending, Status: corev1.ConditionTrue},
+			}
+		}
+		pvcIndexer.Add(pvc)
+		// the PVCs of other statefulsets are not resized
+		pvcIndexer.Add(newPVCForPVCResizer(tc, "pd-test-pd-group-0", "10Gi", "10Gi"))
+
+		pendingSince := time.Now().Add(-test.pendingFor)
+		podCreated := pendingSince.Add(-time.Hour)
+		if test.podRestarted {
+			podCreated = pendingSince.Add(time.Minute)
+		}
+		podIndexer.Add(&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "test-pd-0",
+				Namespace:         tc.GetNamespace(),
+				CreationTimestamp: metav1.NewTime(podCreated),
+			},
+		})
+		var lastRestartTime *metav1.Time
+		if test.pendingFor > 0 {
+			status := v1alpha1.StorageStatus{
+				Size: "20Gi",
+				PVCs: map[string]v1alpha1.PVCResizeStatus{
+					"pd-test-pd-0": {
+						Phase:              v1alpha1.PVCFileSystemResizePending,
+						LastTransitionTime: metav1.NewTime(pendingSince),
+					},
+				},
+			}
+			if test.lastRestarted {
+				restartTime := metav1.NewTime(pendingSince.Add(time.Minute))
+				lastRestartTime = &restartTime
+				status.LastFileSystemResizeTime = lastRestartTime
+			}
+			tc.Status.Storage = map[string]v1alpha1.StorageStatus{v1alpha1.PDMemberType.String(): status}
+		}
+
+		err := resizer.Sync(tc)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		obj, _, err := pvcIndexer.GetByKey(tc.GetNamespace() + "/pd-test-pd-0")
+		g.Expect(err).NotTo(HaveOccurred())
+		request := obj.(*corev1.PersistentVolumeClaim).Spec.Resources.Requests[corev1.ResourceStorage]
+		g.Expect(request.String()).To(Equal(test.expectRequest))
+		obj, _, err = pvcIndexer.GetByKey(tc.GetNamespace() + "/pd-test-pd-group-0")
+		g.Expect(err).NotTo(HaveOccurred())
+		otherRequest := obj.(*corev1.PersistentVolumeClaim).Spec.Resources.Requests[corev1.ResourceStorage]
+		g.Expect(otherRequest.String()).To(Equal("10Gi"))
+
+		status, ok := tc.Status.Storage[v1alpha1.PDMemberType.String()]
+		g.Expect(ok).To(BeTrue())
+		g.Expect(status.Size).To(Equal("20Gi"))
+		if test.expectRestart {
+			g.Expect(status.LastFileSystemResizeTime).NotTo(BeNil())
+			g.Expect(status.LastFileSystemResizeTime.Time.After(pendingSince)).To(BeTrue())
+		} else {
+			g.Expect(status.LastFileSystemResizeTime).To(Equal(lastRestartTime))
+		}
+		if test.expectPVCStatusEmpty {
+			g.Expect(status.PVCs).To(BeNil())
+		} else {
+			g.Expect(status.PVCs["pd-test-pd-0"].Phase).To(Equal(test.expectPhase))
+			g.Expect(status.PVCs["pd-test-pd-0"].CurrentSize).To(Equal(test.capacity))
+		}
+		g.Expect(len(recorder.Events) > 0).To(Equal(test.expectEventRecorded))
+	}
+
+	tests := []*testcase{
+		{
+			name:           "expand the PVC",
+			request:        "10Gi",
+			capacity:       "10Gi",
+			allowExpansion: true,
+			expectRequest:  "20Gi",
+			expectPhase:    v1alpha1.PVCResizing,
+		},
+		{
+			name:                "the StorageClass doesn't allow volume expansion",
+			request:             "10Gi",
+			capacity:            "10Gi",
+			allowExpansion:      false,
+			expectRequest:       "10Gi",
+			expectPhase:         v1alpha1.PVCResizeUnsupported,
+			expectEventRecorded: true,
+		},
+		{
+			name:           "the volume is being expanded",
+			request:        "20Gi",
+			capacity:       "10Gi",
+			allowExpansion: true,
+			expectRequest:  "20Gi",
+			expectPhase:    v1alpha1.PVCResizing,
+		},
+		{
+			name:            "the file system has just become pending on a restart",
+			request:         "20Gi",
+			capacity:        "10Gi",
+			fsResizePending: true,
+			allowExpansion:  true,
+			expectRequest:   "20Gi",
+			expectPhase:     v1alpha1.PVCFileSystemResizePending,
+		},
+		{
+			name:            "the file system is pending within the grace period",
+			request:         "20Gi",
+			capacity:        "10Gi",
+			fsResizePending: true,
+			pendingFor:      time.Minute,
+			allowExpansion:  true,
+			expectRequest:   "20Gi",
+			expectPhase:     v1alpha1.PVCFileSystemResizePending,
+		},
+		{
+			name:            "the pods are restarted to resize the file systems",
+			request:         "20Gi",
+			capacity:        "10Gi",
+			fsResizePending: true,
+			pendingFor:      10 * time.Minute,
+			allowExpansion:  true,
+			expectRequest:   "20Gi",
+			expectPhase:     v1alpha1.PVCFileSystemResizePending,
+			expectRestart:   true,
+		},
+		{
+			name:            "the pod has been restarted since the file system became pending",
+			request:         "20Gi",
+			capacity:        "10Gi",
+			fsResizePending: true,
+			pendingFor:      10 * time.Minute,
+			podRestarted:    true,
+			allowExpansion:  true,
+			expectRequest:   "20Gi",
+			expectPhase:     v1alpha1.PVCFileSystemResizePending,
+		},
+		{
+			name:            "the pods are being restarted",
+			request:         "20Gi",
+			capacity:        "10Gi",
+			fsResizePending: true,
+			pendingFor:      10 * time.Minute,
+			lastRestarted:   true,
+			allowExpansion:  true,
+			expectRequest:   "20Gi",
+			expectPhase:     v1alpha1.PVCFileSystemResizePending,
+		},
+		{
+			name:                 "the PVC is expanded",
+			request:              "20Gi",
+			capacity:             "20Gi",
+			allowExpansion:       true,
+			expectRequest:        "20Gi",
+			expectPVCStatusEmpty: true,
+		},
+	}
+
+	for _, test := range tests {
+		testFn(test, t)
+	}
+}
+
+func TestPVCResizerSyncTiKVGroup(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	resizer, pvcIndexer, _, scIndexer, _ := newFakePVCResizer()
+	allowExpansion := true
+	scIndexer.Add(&storagev1.StorageClass{
+		ObjectMeta:           metav1.ObjectMeta{Name: "my-storage-class"},
+		AllowVolumeExpansion: &allowExpansion,
+	})
+
+	tc := newTidbClusterForPVCResizer()
+	tc.Spec.PD.Requests = nil
+	tc.Spec.TiKV.Requests = &v1alpha1.ResourceRequirement{Storage: "20Gi"}
+	tc.Spec.TiKVGroups = []v1alpha1.TiKVGroupSpec{{
+		Name:     "hot",
+		TiKVSpec: v1alpha1.TiKVSpec{Resources: v1alpha1.Resources{Requests: &v1alpha1.ResourceRequirement{Storage: "30Gi"}}},
+	}}
+	for _, name := range []string{"tikv-test-tikv-0", "tikv-test-tikv-hot-0"} {
+		pvc := newPVCForPVCResizer(tc, name, "10Gi", "10Gi")
+		pvc.Labels = label.New().Instance(tc.GetName()).TiKV().Labels()
+		pvcIndexer.Add(pvc)
+	}
+
+	err := resizer.Sync(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	for name, size := range map[string]string{"tikv-test-tikv-0": "20Gi", "tikv-test-tikv-hot-0": "30Gi"} {
+		obj, _, err := pvcIndexer.GetByKey(tc.GetNamespace() + "/" + name)
+		g.Expect(err).NotTo(HaveOccurred())
+		request := obj.(*corev1.PersistentVolumeClaim).Spec.Resources.Requests[corev1.ResourceStorage]
+		g.Expect(request.String()).To(Equal(size))
+	}
+	g.Expect(tc.Status.Storage).To(HaveLen(2))
+	g.Expect(tc.Status.Storage["tikv"].Size).To(Equal("20Gi"))
+	g.Expect(tc.Status.Storage["tikv"].PVCs).To(HaveKey("tikv-test-tikv-0"))
+	g.Expect(tc.Status.Storage["tikv-hot"].Size).To(Equal("30Gi"))
+	g.Expect(tc.Status.Storage["tikv-hot"].PVCs).To(HaveKey("tikv-test-tikv-hot-0"))
+}
+
+func TestStorageResizeAnnotations(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForPVCResizer()
+	g.Expect(storageResizeAnnotations(tc, v1alpha1.PDMemberType.String())).To(BeNil())
+
+	restartTime := metav1.NewTime(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC))
+	tc.Status.Storage = map[string]v1alpha1.StorageStatus{
+		v1alpha1.PDMemberType.String(): {Size: "30Gi", LastFileSystemResizeTime: &restartTime},
+		tikvGroupStorageKey("hot"):     {Size: "30Gi", LastFileSystemResizeTime: &restartTime},
+	}
+	g.Expect(storageResizeAnnotations(tc, v1alpha1.PDMemberType.String())).To(Equal(map[string]string{
+		label.AnnFileSystemResizeTime: "2020-01-02T03:04:05Z",
+	}))
+	g.Expect(storageResizeAnnotations(tc, v1alpha1.TiKVMemberType.String())).To(BeNil())
+
+	// the pods of spec.tikv are not restarted for the PVCs of a TiKV group
+	tc.Spec.TiKVGroups = []v1alpha1.TiKVGroupSpec{{Name: "hot"}}
+	g.Expect(storageResizeAnnotations(tc, tikvStorageKey(tc))).To(BeNil())
+	view := tikvGroupView(tc, &tc.Spec.TiKVGroups[0])
+	g.Expect(storageResizeAnnotations(view, tikvStorageKey(view))).To(Equal(map[string]string{
+		label.AnnFileSystemResizeTime: "2020-01-02T03:04:05Z",
+	}))
+}
+
+func newFakePVCResizer() (*pvcResizer, cache.Indexer, cache.Indexer, cache.Indexer, *record.FakeRecorder) {
+	kubeCli := kubefake.NewSimpleClientset()
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeCli, 0)
+	pvcInformer := kubeInformerFactory.Core().V1().PersistentVolumeClaims()
+	podInformer := kubeInformerFactory.Core().V1().Pods()
+	scInformer := kubeInformerFactory.Storage().V1().StorageClasses()
+	recorder := record.NewFakeRecorder(10)
+	resizer := &pvcResizer{
+		pvcLister:  pvcInformer.Lister(),
+		podLister:  podInformer.Lister(),
+		scLister:   scInformer.Lister(),
+		pvcControl: controller.NewFakePVCControl(pvcInformer),
+		recorder:   recorder,
+	}
+	return resizer, pvcInformer.Informer().GetIndexer(), podInformer.Informer().GetIndexer(),
+		scInformer.Informer().GetIndexer(), recorder
+}
+
+func newTidbClusterForPVCResizer() *v1alpha1.TidbCluster {
+	tc := newTidbClusterForPD()
+	tc.Labels = map[string]string{label.InstanceLabelKey: tc.GetName()}
+	tc.Spec.PD.Requests.Storage = "20Gi"
+	return tc
+}
+
+func newPVCForPVCResizer(tc *v1alpha1.TidbCluster, name, request, capacity string) *corev1.PersistentVolumeClaim {
+	storageClassName := "my-storage-class"
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: tc.GetNamespace(),
+			Labels:    label.New().Instance(tc.GetName()).PD().Labels(),
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: &storageClassName,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(request)},
+			},
+		},
+		Status: corev1.PersistentVolumeClaimStatus{
+			Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(capacity)},
+		},
+	}
+}
@@ -0,0 +1,393 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	glog "k8s.io/klog"
+)
+
+const (
+	// blockCacheMemoryPercentage is the percentage of the memory limit of TiKV used by the shared block cache
+	blockCacheMemoryPercentage = 50
+	// blockCacheCapacityEnv is the env of TiKV setting the capacity of the shared block cache
+	blockCacheCapacityEnv = "BLOCK_CACHE_CAPACITY"
+)
+
+// resizedResources are the resources whose requests are checked against the allocatable resources of the nodes
+var resizedResources = []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory}
+
+// checkResize is called before the pods of memberType are resized from the resources of oldSet to the
+// resources of newSet. The resize starts only if the nodes of the pods can fit the new requests,
+// newSet keeps the resources of oldSet otherwise. The pods are restarted one by one by the upgrader
+// of memberType, which evicts the leaders from each pod before restarting it.
+func checkResize(tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType, podLister corelisters.PodLister,
+	nodeLister corelisters.NodeLister, oldSet, newSet *apps.StatefulSet) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	if err := pauseResizeOnFailure(tc, memberType, podLister, oldSet, newSet); err != nil {
+		return err
+	}
+
+	oldResources := containerResources(oldSet.Spec.Template.Spec, memberType)
+	newResources := containerResources(newSet.Spec.Template.Spec, memberType)
+	if resourcesEqual(oldResources, newResources) {
+		return nil
+	}
+
+	msg, err := resizeNotFit(memberType, podLister, nodeLister, oldSet, oldResources.Requests, newResources.Requests)
+	if err != nil {
+		return err
+	}
+	if msg != "" {
+		v1alpha1.UpdateTidbClusterCondition(&tc.Status, &v1alpha1.TidbClusterCondition{
+			Type:    v1alpha1.TidbClusterResizeRejected,
+			Status:  corev1.ConditionTrue,
+			Reason:  resizeReason(memberType, "InsufficientResources"),
+			Message: msg,
+		})
+		glog.Errorf("tidbcluster: [%s/%s] resize rejected, %s", ns, tcName, msg)
+		return keepOldResources(memberType, oldSet, newSet)
+	}
+
+	resetResizeCondition(tc, memberType, v1alpha1.TidbClusterResizeRejected, "InsufficientResources",
+		fmt.Sprintf("%s nodes can fit the new resource requests", memberType))
+	resetResizeCondition(tc, memberType, v1alpha1.TidbClusterResizePaused, "ResizeFailed",
+		fmt.Sprintf("%s resources changed, resize again", memberType))
+	glog.Infof("tidbcluster: [%s/%s]'s %s is resized from %s to %s", ns, tcName, memberType,
+		formatResources(oldResources), formatResources(newResources))
+	return nil
+}
+
+// pauseResizeOnFailure returns a RequeueError if a pod of the update revision of oldSet is OOMKilled or
+// unschedulable for insufficient resources, so that the other pods keep running with the old resources.
+// Only the rollouts changing the resources are paused, and they continue after newSet desires other resources.
+func pauseResizeOnFailure(tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType, podLister corelisters.PodLister,
+	oldSet, newSet *apps.StatefulSet) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	if !statefulSetIsUpgrading(oldSet) || !templateEqual(newSet.Spec.Template, oldSet.Spec.Template) {
+		return nil
+	}
+	pods, err := listSetPods(podLister, oldSet)
+	if err != nil {
+		return err
+	}
+	resized, err := resizeRollingOut(pods, oldSet, memberType)
+	if err != nil {
+		return err
+	}
+	if !resized {
+		return nil
+	}
+	var failures []string
+	for _, pod := range pods {
+		if pod.Labels[apps.ControllerRevisionHashLabelKey] != oldSet.Status.UpdateRevision {
+			continue
+		}
+		if reason := podResizeFailure(pod, memberType); reason != "" {
+			failures = append(failures, fmt.Sprintf("%s %s", pod.GetName(), reason))
+		}
+	}
+	if len(failures) == 0 {
+		resetResizeCondition(tc, memberType, v1alpha1.TidbClusterResizePaused, "ResizeFailed",
+			fmt.Sprintf("%s resized pods are running", memberType))
+		return nil
+	}
+
+	sort.Strings(failures)
+	msg := fmt.Sprintf("%s resize paused, %s", memberType, strings.Join(failures, ", "))
+	v1alpha1.UpdateTidbClusterCondition(&tc.Status, &v1alpha1.TidbClusterCondition{
+		Type:    v1alpha1.TidbClusterResizePaused,
+		Status:  corev1.ConditionTrue,
+		Reason:  resizeReason(memberType, "ResizeFailed"),
+		Message: msg,
+	})
+	return controller.RequeueErrorf("tidbcluster: [%s/%s] %s", ns, tcName, msg)
+}
+
+// resizeRollingOut returns whether the update revision of set changes the resources of memberType from the
+// current revision. The template of set is compared with the last applied pod spec of a pod of the current revision,
+// false is returned if no pod is left at the current revision
+func resizeRollingOut(pods []*corev1.Pod, set *apps.StatefulSet, memberType v1alpha1.MemberType) (bool, error) {
+	newResources := containerResources(set.Spec.Template.Spec, memberType)
+	for _, pod := range pods {
+		if pod.Labels[apps.ControllerRevisionHashLabelKey] != set.Status.CurrentRevision {
+			continue
+		}
+		podSpec := pod.Spec
+		if podSpecAppliedConfig, ok := pod.Annotations[LastAppliedConfigAnnotation]; ok {
+			podSpec = corev1.PodSpec{}
+			if err := json.Unmarshal([]byte(podSpecAppliedConfig), &podSpec); err != nil {
+				return false, err
+			}
+		}
+		return !resourcesEqual(containerResources(podSpec, memberType), newResources), nil
+	}
+	return false, nil
+}
+
+// podResizeFailure returns why the resized pod fails for its resources, or empty if it doesn't
+func podResizeFailure(pod *corev1.Pod, memberType v1alpha1.MemberType) string {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodScheduled && cond.Status == corev1.ConditionFalse &&
+			cond.Reason == corev1.PodReasonUnschedulable && strings.Contains(cond.Message, "Insufficient") {
+			return fmt.Sprintf("is unschedulable: %s", cond.Message)
+		}
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name != memberType.String() || status.Ready {
+			continue
+		}
+		if terminated := status.LastTerminationState.Terminated; terminated != nil && terminated.Reason == "OOMKilled" {
+			return "is OOMKilled"
+		}
+		if terminated := status.State.Terminated; terminated != nil && terminated.Reason == "OOMKilled" {
+			return "is OOMKilled"
+		}
+	}
+	return ""
+}
+
+// resizeNotFit returns why the nodes of the pods of set can't fit the new requests, or empty if they can.
+// The pods of set on the same node are all resized, the other pods on the node keep their requests.
+func resizeNotFit(memberType v1alpha1.MemberType, podLister corelisters.PodLister, nodeLister corelisters.NodeLister,
+	set *apps.StatefulSet, oldRequests, newRequests corev1.ResourceList) (string, error) {
+	increased := false
+	for _, name := range resizedResources {
+		newRequest, oldRequest := newRequests[name], oldRequests[name]
+		if newRequest.Cmp(oldRequest) > 0 {
+			increased = true
+		}
+	}
+	if !increased {
+		return "", nil
+	}
+
+	setPods, err := listSetPods(podLister, set)
+	if err != nil {
+		return "", err
+	}
+	resized := map[string]int64{}
+	for _, pod := range setPods {
+		if pod.Spec.NodeName != "" {
+			resized[pod.Spec.NodeName]++
+		}
+	}
+	if len(resized) == 0 {
+		return "", nil
+	}
+	allPods, err := podLister.List(labels.Everything())
+	if err != nil {
+		return "", err
+	}
+	isSetPod := map[string]bool{}
+	for _, pod := range setPods {
+		isSetPod[pod.GetNamespace()+"/"+pod.GetName()] = true
+	}
+
+	nodeNames := make([]string, 0, len(resized))
+	for nodeName := range resized {
+		nodeNames = append(nodeNames, nodeName)
+	}
+	sort.Strings(nodeNames)
+	for _, nodeName := range nodeNames {
+		node, err := nodeLister.Get(nodeName)
+		if err != nil {
+			return "", fmt.Errorf("get node %s failed, err: %v", nodeName, err)
+		}
+		requested := corev1.ResourceList{}
+		for _, pod := range allPods {
+			if pod.Spec.NodeName != nodeName || isSetPod[pod.GetNamespace()+"/"+pod.GetName()] ||
+				pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+				continue
+			}
+			addResourceList(requested, podRequests(pod))
+		}
+		for _, name := range resizedResources {
+			want, ok := newRequests[name]
+			if !ok {
+				continue
+			}
+			total := requested[name]
+			for i := int64(0); i < resized[nodeName]; i++ {
+				total.Add(want)
+			}
+			allocatable := node.Status.Allocatable[name]
+			if total.Cmp(allocatable) > 0 {
+				return fmt.Sprintf("node %s can't fit the %s requests of %s pods, %s requested, %s allocatable",
+					nodeName, name, memberType, total.String(), allocatable.String()), nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// keepOldResources sets the resources of memberType and the block cache capacity sized from them in newSet
+// to the last applied pod spec of oldSet, the other changes of the pod spec are kept
+func keepOldResources(memberType v1alpha1.MemberType, oldSet, newSet *apps.StatefulSet) error {
+	_, podSpec, err := GetLastAppliedConfig(oldSet)
+	if err != nil {
+		return err
+	}
+	for _, old := range podSpec.Containers {
+		if old.Name != memberType.String() {
+			continue
+		}
+		for i := range newSet.Spec.Template.Spec.Containers {
+			c := &newSet.Spec.Template.Spec.Containers[i]
+			if c.Name != memberType.String() {
+				continue
+			}
+			c.Resources = old.Resources
+			c.Env = keepOldEnv(old.Env, c.Env, blockCacheCapacityEnv)
+		}
+	}
+	return nil
+}
+
+// keepOldEnv returns env with the env named name set as in oldEnv, it is removed if oldEnv doesn't have it
+func keepOldEnv(oldEnv, env []corev1.EnvVar, name string) []corev1.EnvVar {
+	var kept []corev1.EnvVar
+	for _, e := range env {
+		if e.Name != name {
+			kept = append(kept, e)
+		}
+	}
+	for _, e := range oldEnv {
+		if e.Name == name {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
+
+func listSetPods(podLister corelisters.PodLister, set *apps.StatefulSet) ([]*corev1.Pod, error) {
+	selector, err := metav1.LabelSelectorAsSelector(set.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	return podLister.Pods(set.GetNamespace()).List(selector)
+}
+
+// podRequests returns the requests of the pod, an init container requesting more than
+// the containers determines the requests like the scheduler does
+func podRequests(pod *corev1.Pod) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for _, c := range pod.Spec.Containers {
+		addResourceList(requests, c.Resources.Requests)
+	}
+	for _, c := range pod.Spec.InitContainers {
+		for name, q := range c.Resources.Requests {
+			if current, ok := requests[name]; !ok || q.Cmp(current) > 0 {
+				requests[name] = q.DeepCopy()
+			}
+		}
+	}
+	return requests
+}
+
+func addResourceList(list, add corev1.ResourceList) {
+	for name, q := range add {
+		if current, ok := list[name]; ok {
+			current.Add(q)
+			list[name] = current
+		} else {
+			list[name] = q.DeepCopy()
+		}
+	}
+}
+
+func containerResources(podSpec corev1.PodSpec, memberType v1alpha1.MemberType) corev1.ResourceRequirements {
+	for _, c := range podSpec.Containers {
+		if c.Name == memberType.String() {
+			return c.Resources
+		}
+	}
+	return corev1.ResourceRequirements{}
+}
+
+func resourcesEqual(a, b corev1.ResourceRequirements) bool {
+	return resourceListEqual(a.Requests, b.Requests) && resourceListEqual(a.Limits, b.Limits)
+}
+
+func resourceListEqual(a, b corev1.ResourceList) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, q := range a {
+		other, ok := b[name]
+		if !ok || q.Cmp(other) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func formatResources(resources corev1.ResourceRequirements) string {
+	format := func(list corev1.ResourceList) string {
+		var items []string
+		for _, name := range resizedResources {
+			if q, ok := list[name]; ok {
+				items = append(items, fmt.Sprintf("%s=%s", name, q.String()))
+			}
+		}
+		return strings.Join(items, ",")
+	}
+	return fmt.Sprintf("requests[%s] limits[%s]", format(resources.Requests), format(resources.Limits))
+}
+
+// resetResizeCondition sets the condition of memberType to false if it's true
+func resetResizeCondition(tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType,
+	conditionType v1alpha1.TidbClusterConditionType, reason string, msg string) {
+	_, condition := v1alpha1.GetTidbClusterCondition(&tc.Status, conditionType)
+	if condition == nil || condition.Reason != resizeReason(memberType, reason) || condition.Status != corev1.ConditionTrue {
+		return
+	}
+	v1alpha1.UpdateTidbClusterCondition(&tc.Status, &v1alpha1.TidbClusterCondition{
+		Type:    conditionType,
+		Status:  corev1.ConditionFalse,
+		Reason:  resizeReason(memberType, reason),
+		Message: msg,
+	})
+}
+
+func resizeReason(memberType v1alpha1.MemberType, reason string) string {
+	return strings.Title(memberType.String()) + reason
+}
+
+// blockCacheCapacity returns the capacity of the shared block cache of TiKV sized from the memory limit,
+// or empty if the memory limit is not set
+func blockCacheCapacity(resources corev1.ResourceRequirements) string {
+	limit, ok := resources.Limits[corev1.ResourceMemory]
+	if !ok || limit.IsZero() {
+		return ""
+	}
+	capacity := resource.NewQuantity(limit.Value()*blockCacheMemoryPercentage/100, resource.BinarySI)
+	return fmt.Sprintf("%dMB", capacity.Value()/(1<<20))
+}
@@ -0,0 +1,332 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCheckResize(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name           string
+		oldCPU         string
+		newCPU         string
+		otherCPU       string
+		nodeCPU        string
+		expectCPU      string
+		expectRejected bool
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+
+		kubeCli := kubefake.NewSimpleClientset()
+		podInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().Pods()
+		nodeInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().Nodes()
+
+		tc := newTidbClusterForTiKVUpgrader()
+		oldSet := newStatefulSetForResize(tc, v1alpha1.TiKVMemberType, test.oldCPU)
+		oldSet.Spec.Template.Spec.Containers[0].Env = []corev1.EnvVar{{Name: blockCacheCapacityEnv, Value: "1024MB"}}
+		SetLastAppliedConfigAnnotation(oldSet)
+		newSet := newStatefulSetForResize(tc, v1alpha1.TiKVMemberType, test.newCPU)
+		// the image is upgraded with the resize
+		newSet.Spec.Template.Spec.Containers[0].Image = "tikv:new"
+		newSet.Spec.Template.Spec.Containers[0].Env = []corev1.EnvVar{
+			{Name: "TZ", Value: "UTC"},
+			{Name: blockCacheCapacityEnv, Value: "2048MB"},
+		}
+
+		// two tikv pods on node-1 and one on node-2
+		for i, nodeName := range []string{"node-1", "node-1", "node-2"} {
+			pod := newPodForResize(tc, oldSet, i, test.oldCPU)
+			pod.Spec.NodeName = nodeName
+			podInformer.Informer().GetIndexer().Add(pod)
+		}
+		other := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"},
+			Spec: corev1.PodSpec{
+				NodeName:   "node-1",
+				Containers: []corev1.Container{{Name: "other", Resources: resourcesForResize(test.otherCPU)}},
+			},
+		}
+		podInformer.Informer().GetIndexer().Add(other)
+		for _, nodeName := range []string{"node-1", "node-2"} {
+			nodeInformer.Informer().GetIndexer().Add(&corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: nodeName},
+				Status: corev1.NodeStatus{
+					Allocatable: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(test.nodeCPU)},
+				},
+			})
+		}
+
+		err := checkResize(tc, v1alpha1.TiKVMemberType, podInformer.Lister(), nodeInformer.Lister(), oldSet, newSet)
+		g.Expect(err).NotTo(HaveOccurred())
+		cpu := newSet.Spec.Template.Spec.Containers[0].Resources.Requests[corev1.ResourceCPU]
+		g.Expect(cpu.String()).To(Equal(test.expectCPU))
+		g.Expect(newSet.Spec.Template.Spec.Containers[0].Image).To(Equal("tikv:new"))
+		blockCache := "2048MB"
+		if test.expectRejected {
+			blockCache = "1024MB"
+		}
+		g.Expect(newSet.Spec.Template.Spec.Containers[0].Env).To(ConsistOf(
+			corev1.EnvVar{Name: "TZ", Value: "UTC"},
+			corev1.EnvVar{Name: blockCacheCapacityEnv, Value: blockCache},
+		))
+
+		_, condition := v1alpha1.GetTidbClusterCondition(&tc.Status, v1alpha1.TidbClusterResizeRejected)
+		if test.expectRejected {
+			g.Expect(condition).NotTo(BeNil())
+			g.Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+			g.Expect(condition.Reason).To(Equal("TikvInsufficientResources"))
+		} else {
+			g.Expect(condition).To(BeNil())
+		}
+	}
+
+	tests := []*testcase{
+		{
+			name:      "decreasing requests is not checked",
+			oldCPU:    "4",
+			newCPU:    "2",
+			otherCPU:  "8",
+			nodeCPU:   "8",
+			expectCPU: "2",
+		},
+		{
+			name:      "nodes can fit the new requests",
+			oldCPU:    "2",
+			newCPU:    "3",
+			otherCPU:  "2",
+			nodeCPU:   "8",
+			expectCPU: "3",
+		},
+		{
+			name:           "a node can't fit the new requests of all its pods",
+			oldCPU:         "2",
+			newCPU:         "4",
+			otherCPU:       "2",
+			nodeCPU:        "8",
+			expectCPU:      "2",
+			expectRejected: true,
+		},
+	}
+
+	for _, test := range tests {
+		testFn(test, t)
+	}
+}
+
+func TestCheckResizeClearsRejected(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	kubeCli := kubefake.NewSimpleClientset()
+	podInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().Pods()
+	nodeInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().Nodes()
+
+	tc := newTidbClusterForTiKVUpgrader()
+	v1alpha1.UpdateTidbClusterCondition(&tc.Status, &v1alpha1.TidbClusterCondition{
+		Type:   v1alpha1.TidbClusterResizeRejected,
+		Status: corev1.ConditionTrue,
+		Reason: "PdInsufficientResources",
+	})
+	oldSet := newStatefulSetForResize(tc, v1alpha1.PDMemberType, "2")
+	newSet := newStatefulSetForResize(tc, v1alpha1.PDMemberType, "1")
+
+	err := checkResize(tc, v1alpha1.PDMemberType, podInformer.Lister(), nodeInformer.Lister(), oldSet, newSet)
+	g.Expect(err).NotTo(HaveOccurred())
+	_, condition := v1alpha1.GetTidbClusterCondition(&tc.Status, v1alpha1.TidbClusterResizeRejected)
+	g.Expect(condition).NotTo(BeNil())
+	g.Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+}
+
+func TestPauseResizeOnFailure(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name         string
+		changeStatus func(*corev1.Pod)
+		// currentCPU is the cpu requests of the pods of the current revision
+		currentCPU   string
+		expectPaused bool
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+
+		kubeCli := kubefake.NewSimpleClientset()
+		podInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().Pods()
+
+		tc := newTidbClusterForTiKVUpgrader()
+		oldSet := newStatefulSetForResize(tc, v1alpha1.TiKVMemberType, "2")
+		oldSet.Status.UpdateRevision = "2"
+		newSet := newStatefulSetForResize(tc, v1alpha1.TiKVMemberType, "2")
+
+		for i := 0; i < 3; i++ {
+			pod := newPodForResize(tc, oldSet, i, test.currentCPU)
+			if i == 2 {
+				pod.Labels[apps.ControllerRevisionHashLabelKey] = "2"
+				test.changeStatus(pod)
+			}
+			podInformer.Informer().GetIndexer().Add(pod)
+		}
+
+		err := pauseResizeOnFailure(tc, v1alpha1.TiKVMemberType, podInformer.Lister(), oldSet, newSet)
+		_, condition := v1alpha1.GetTidbClusterCondition(&tc.Status, v1alpha1.TidbClusterResizePaused)
+		if test.expectPaused {
+			g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+			g.Expect(condition).NotTo(BeNil())
+			g.Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+			g.Expect(condition.Reason).To(Equal("TikvResizeFailed"))
+		} else {
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(condition).To(BeNil())
+		}
+	}
+
+	tests := []*testcase{
+		{
+			name:         "resized pod is running",
+			changeStatus: func(pod *corev1.Pod) {},
+			currentCPU:   "1",
+			expectPaused: false,
+		},
+		{
+			name: "resized pod is OOMKilled",
+			changeStatus: func(pod *corev1.Pod) {
+				pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+					{
+						Name:                 "tikv",
+						LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled"}},
+					},
+				}
+			},
+			currentCPU:   "1",
+			expectPaused: true,
+		},
+		{
+			name: "upgraded pod is OOMKilled when the resources are not changed",
+			changeStatus: func(pod *corev1.Pod) {
+				pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+					{
+						Name:                 "tikv",
+						LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled"}},
+					},
+				}
+			},
+			currentCPU:   "2",
+			expectPaused: false,
+		},
+		{
+			name: "resized pod is unschedulable",
+			changeStatus: func(pod *corev1.Pod) {
+				pod.Status.Conditions = []corev1.PodCondition{
+					{
+						Type:    corev1.PodScheduled,
+						Status:  corev1.ConditionFalse,
+						Reason:  corev1.PodReasonUnschedulable,
+						Message: "0/3 nodes are available: 3 Insufficient memory.",
+					},
+				}
+			},
+			currentCPU:   "1",
+			expectPaused: true,
+		},
+	}
+
+	for _, test := range tests {
+		testFn(test, t)
+	}
+}
+
+func TestBlockCacheCapacity(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(blockCacheCapacity(corev1.ResourceRequirements{})).To(Equal(""))
+	g.Expect(blockCacheCapacity(corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("32Gi")},
+	})).To(Equal("16384MB"))
+	g.Expect(blockCacheCapacity(corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1500Mi")},
+	})).To(Equal("750MB"))
+}
+
+func resourcesForResize(cpu string) corev1.ResourceRequirements {
+	return corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+	}
+}
+
+func newStatefulSetForResize(tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType, cpu string) *apps.StatefulSet {
+	setName := controller.TiKVMemberName(tc.GetName())
+	if memberType == v1alpha1.PDMemberType {
+		setName = controller.PDMemberName(tc.GetName())
+	}
+	set := &apps.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      setName,
+			Namespace: tc.GetNamespace(),
+		},
+		Spec: apps.StatefulSetSpec{
+			Replicas: controller.Int32Ptr(3),
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": setName},
+			},
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:      memberType.String(),
+							Resources: resourcesForResize(cpu),
+						},
+					},
+				},
+			},
+		},
+		Status: apps.StatefulSetStatus{
+			Replicas:        3,
+			CurrentRevision: "1",
+			UpdateRevision:  "1",
+		},
+	}
+	SetLastAppliedConfigAnnotation(set)
+	return set
+}
+
+func newPodForResize(tc *v1alpha1.TidbCluster, set *apps.StatefulSet, ordinal int, cpu string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ordinalPodName(v1alpha1.TiKVMemberType, tc.GetName(), int32(ordinal)),
+			Namespace: tc.GetNamespace(),
+			Labels: map[string]string{
+				"app":                               set.GetName(),
+				apps.ControllerRevisionHashLabelKey: set.Status.CurrentRevision,
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "tikv", Resources: resourcesForResize(cpu)}},
+		},
+	}
+}
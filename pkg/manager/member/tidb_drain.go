@@ -0,0 +1,135 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	glog "k8s.io/klog"
+)
+
+// TiDBServingCondition is the readiness gate of tidb pods when connection draining is enabled,
+// the pod is removed from the service endpoints when the condition is false
+const TiDBServingCondition corev1.PodConditionType = "tidb.pingcap.com/serving"
+
+const (
+	// TiDBDrainByUpgrader means the tidb pod is drained by the upgrader before it is restarted
+	TiDBDrainByUpgrader = "upgrader"
+	// TiDBDrainByWebhook means the tidb pod is drained by the pod admission webhook before it is deleted
+	TiDBDrainByWebhook = "webhook"
+)
+
+// HasTiDBServingGate returns whether the readiness of pod is gated by TiDBServingCondition
+func HasTiDBServingGate(pod *corev1.Pod) bool {
+	for _, gate := range pod.Spec.ReadinessGates {
+		if gate.ConditionType == TiDBServingCondition {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTiDBServing returns whether TiDBServingCondition of pod is true
+func IsTiDBServing(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == TiDBServingCondition {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// SetTiDBServingCondition sets TiDBServingCondition of pod, returns whether the condition is changed
+func SetTiDBServingCondition(pod *corev1.Pod, serving bool) bool {
+	status := corev1.ConditionFalse
+	reason := "Draining"
+	if serving {
+		status = corev1.ConditionTrue
+		reason = "Serving"
+	}
+	for i := range pod.Status.Conditions {
+		condition := &pod.Status.Conditions[i]
+		if condition.Type != TiDBServingCondition {
+			continue
+		}
+		if condition.Status == status {
+			return false
+		}
+		condition.Status = status
+		condition.Reason = reason
+		condition.LastTransitionTime = metav1.Now()
+		return true
+	}
+	pod.Status.Conditions = append(pod.Status.Conditions, corev1.PodCondition{
+		Type:               TiDBServingCondition,
+		Status:             status,
+		Reason:             reason,
+		LastTransitionTime: metav1.Now(),
+	})
+	return true
+}
+
+// BeginTiDBDrain marks pod as drained by the given drainer, it should be followed by setting TiDBServingCondition to false
+func BeginTiDBDrain(pod *corev1.Pod, by string) {
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[label.AnnTiDBDrainBeginTime] = time.Now().Format(time.RFC3339)
+	pod.Annotations[label.AnnTiDBDrainBy] = by
+}
+
+// IsTiDBDraining returns whether the connections of pod are being drained
+func IsTiDBDraining(pod *corev1.Pod) bool {
+	_, ok := pod.Annotations[label.AnnTiDBDrainBeginTime]
+	return ok
+}
+
+// EndTiDBDrain clears the draining mark of pod, it should be followed by setting TiDBServingCondition to true
+func EndTiDBDrain(pod *corev1.Pod) {
+	delete(pod.Annotations, label.AnnTiDBDrainBeginTime)
+	delete(pod.Annotations, label.AnnTiDBDrainBy)
+}
+
+// TiDBDrained returns whether the active connections of the draining pod drop to the threshold,
+// or the drain timeout is exceeded
+func TiDBDrained(tc *v1alpha1.TidbCluster, tidbControl controller.TiDBControlInterface, pod *corev1.Pod, ordinal int32) (bool, error) {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	drain := tc.Spec.TiDB.Drain
+
+	beginTime, err := time.Parse(time.RFC3339, pod.Annotations[label.AnnTiDBDrainBeginTime])
+	if err != nil {
+		glog.Errorf("parse annotation:[%s] to time failed.", label.AnnTiDBDrainBeginTime)
+		return true, nil
+	}
+	if time.Since(beginTime) > drain.Timeout() {
+		glog.Infof("tidbcluster: [%s/%s]'s tidb pod: [%s] drain timeout", ns, tcName, pod.GetName())
+		return true, nil
+	}
+
+	status, err := tidbControl.GetStatus(tc, ordinal)
+	if err != nil {
+		return false, err
+	}
+	var maxConnections int32
+	if drain != nil {
+		maxConnections = drain.MaxConnections
+	}
+	return int32(status.Connections) <= maxConnections, nil
+}
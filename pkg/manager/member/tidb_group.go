@@ -0,0 +1,110 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// tidbGroupView returns a copy of tc whose spec.tidb and status.tidb are the spec and the status of the TiDB group,
+// so that the TiDB StatefulSet and Service of the group are synced, upgraded and failed over as the TiDB of a cluster.
+// The group name is recorded in the labels of the copy, which is never written back to the apiserver.
+func tidbGroupView(tc *v1alpha1.TidbCluster, group *v1alpha1.TiDBGroupSpec) *v1alpha1.TidbCluster {
+	view := tc.DeepCopy()
+	if view.Labels == nil {
+		view.Labels = map[string]string{}
+	}
+	view.Labels[label.TiDBGroupLabelKey] = group.Name
+
+	view.Spec.TiDB = *group.TiDBSpec.DeepCopy()
+	if view.Spec.TiDB.Image == "" && view.Spec.TiDB.BaseImage == "" {
+		view.Spec.TiDB.Image = tc.Spec.TiDB.Image
+		view.Spec.TiDB.BaseImage = tc.Spec.TiDB.BaseImage
+		if view.Spec.TiDB.Version == "" {
+			view.Spec.TiDB.Version = tc.Spec.TiDB.Version
+		}
+	}
+
+	status := tc.Status.TiDBGroups[group.Name]
+	view.Status.TiDB = *status.DeepCopy()
+	return view
+}
+
+// TiDBGroupViewOfPod returns the view of the TiDB group the TiDB pod belongs to, see tidbGroupView, or tc itself if
+// the pod belongs to spec.tidb or to a group removed from tc
+func TiDBGroupViewOfPod(tc *v1alpha1.TidbCluster, pod *corev1.Pod) *v1alpha1.TidbCluster {
+	name := pod.Labels[label.TiDBGroupLabelKey]
+	if name == "" {
+		return tc
+	}
+	for i := range tc.Spec.TiDBGroups {
+		if tc.Spec.TiDBGroups[i].Name == name {
+			return tidbGroupView(tc, &tc.Spec.TiDBGroups[i])
+		}
+	}
+	return tc
+}
+
+// tidbGroupName returns the name of the TiDB group tc is a view of, or an empty string
+func tidbGroupName(tc *v1alpha1.TidbCluster) string {
+	return tc.GetLabels()[label.TiDBGroupLabelKey]
+}
+
+// tidbSetName returns the name of the TiDB StatefulSet and Service of tc, or of the TiDB group tc is a view of
+func tidbSetName(tc *v1alpha1.TidbCluster) string {
+	if group := tidbGroupName(tc); group != "" {
+		return controller.TiDBGroupMemberName(tc.GetName(), group)
+	}
+	return controller.TiDBMemberName(tc.GetName())
+}
+
+// tidbSetPodName returns the name of the pod of the TiDB StatefulSet of tc with the ordinal
+func tidbSetPodName(tc *v1alpha1.TidbCluster, ordinal int32) string {
+	return fmt.Sprintf("%s-%d", tidbSetName(tc), ordinal)
+}
+
+// tidbConfigMapName returns the name of the ConfigMap holding the TiDB config of tc, or of the TiDB group tc is a view of
+func tidbConfigMapName(tc *v1alpha1.TidbCluster) string {
+	if group := tidbGroupName(tc); group != "" {
+		return controller.TiDBGroupConfigMapName(tc, group)
+	}
+	return controller.MemberConfigMapName(tc, v1alpha1.TiDBMemberType)
+}
+
+// labelTiDB returns the labels of the TiDB pods of tc, which are also the selector of the TiDB StatefulSet of tc.
+// The pods of a TiDB group are labelled with the group.
+func labelTiDB(tc *v1alpha1.TidbCluster) label.Label {
+	instanceName := tc.GetLabels()[label.InstanceLabelKey]
+	l := label.New().Instance(instanceName).TiDB()
+	if group := tidbGroupName(tc); group != "" {
+		l = l.TiDBGroup(group)
+	}
+	return l
+}
+
+// tidbPodLabels returns the labels of the pod template of the TiDB StatefulSet of tc. Once the cluster has TiDB groups,
+// the pods of spec.tidb are labelled with the empty group, which the Service of spec.tidb selects. The selector of
+// the StatefulSet is immutable and left unchanged, see labelTiDB.
+func tidbPodLabels(tc *v1alpha1.TidbCluster) map[string]string {
+	l := labelTiDB(tc)
+	if tidbGroupName(tc) == "" && len(tc.Spec.TiDBGroups) > 0 {
+		l = l.TiDBGroup("")
+	}
+	return l.Labels()
+}
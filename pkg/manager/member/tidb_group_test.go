@@ -0,0 +1,58 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/label"
+)
+
+func TestTiDBGroupView(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForTiDB()
+	g.Expect(labelTiDB(tc).Labels()).NotTo(HaveKey(label.TiDBGroupLabelKey))
+
+	tc.Spec.TiDBGroups = []v1alpha1.TiDBGroupSpec{
+		{Name: "oltp", TiDBSpec: v1alpha1.TiDBSpec{Replicas: 4}},
+		{Name: "reporting", TiDBSpec: v1alpha1.TiDBSpec{ComponentSpec: v1alpha1.ComponentSpec{Image: "tidb-reporting"}, Replicas: 2}},
+	}
+	tc.Status.TiDB.Members = map[string]v1alpha1.TiDBMember{"test-tidb-0": {Name: "test-tidb-0"}}
+	tc.Status.TiDBGroups = map[string]v1alpha1.TiDBStatus{
+		"oltp": {Members: map[string]v1alpha1.TiDBMember{"test-tidb-oltp-0": {Name: "test-tidb-oltp-0"}}},
+	}
+
+	oltp := tidbGroupView(tc, &tc.Spec.TiDBGroups[0])
+	g.Expect(tidbGroupName(oltp)).To(Equal("oltp"))
+	g.Expect(tidbSetName(oltp)).To(Equal("test-tidb-oltp"))
+	g.Expect(tidbSetPodName(oltp, 1)).To(Equal("test-tidb-oltp-1"))
+	g.Expect(tidbConfigMapName(oltp)).To(Equal("test-tidb-oltp"))
+	g.Expect(oltp.Spec.TiDB.Replicas).To(Equal(int32(4)))
+	g.Expect(oltp.BaseTiDBSpec().Image()).To(Equal(v1alpha1.TiDBMemberType.String()))
+	g.Expect(oltp.Status.TiDB.Members).To(HaveKey("test-tidb-oltp-0"))
+	g.Expect(labelTiDB(oltp).Labels()).To(HaveKeyWithValue(label.TiDBGroupLabelKey, "oltp"))
+
+	reporting := tidbGroupView(tc, &tc.Spec.TiDBGroups[1])
+	g.Expect(reporting.BaseTiDBSpec().Image()).To(Equal("tidb-reporting"))
+	g.Expect(reporting.Status.TiDB.Members).To(BeEmpty())
+
+	// the template of spec.tidb is not changed by the groups
+	g.Expect(tidbGroupName(tc)).To(BeEmpty())
+	g.Expect(tidbSetName(tc)).To(Equal("test-tidb"))
+	g.Expect(labelTiDB(tc).Labels()).NotTo(HaveKey(label.TiDBGroupLabelKey))
+	g.Expect(tc.Status.TiDB.Members).To(HaveKey("test-tidb-0"))
+}
@@ -15,19 +15,23 @@ package member
 
 import (
 	"fmt"
+	"net"
 	"strconv"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/label"
 	"github.com/pingcap/tidb-operator/pkg/manager"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
 	"github.com/pingcap/tidb-operator/pkg/util"
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	v1 "k8s.io/client-go/listers/apps/v1"
 	corelisters "k8s.io/client-go/listers/core/v1"
@@ -42,11 +46,15 @@ const (
 type tidbMemberManager struct {
 	setControl                   controller.StatefulSetControlInterface
 	svcControl                   controller.ServiceControlInterface
+	podControl                   controller.PodControlInterface
 	tidbControl                  controller.TiDBControlInterface
+	pdControl                    pdapi.PDControlInterface
 	certControl                  controller.CertControlInterface
+	cmControl                    controller.ConfigMapControlInterface
 	setLister                    v1.StatefulSetLister
 	svcLister                    corelisters.ServiceLister
 	podLister                    corelisters.PodLister
+	cmLister                     corelisters.ConfigMapLister
 	tidbUpgrader                 Upgrader
 	autoFailover                 bool
 	tidbFailover                 Failover
@@ -56,22 +64,30 @@ type tidbMemberManager struct {
 // NewTiDBMemberManager returns a *tidbMemberManager
 func NewTiDBMemberManager(setControl controller.StatefulSetControlInterface,
 	svcControl controller.ServiceControlInterface,
+	podControl controller.PodControlInterface,
 	tidbControl controller.TiDBControlInterface,
+	pdControl pdapi.PDControlInterface,
 	certControl controller.CertControlInterface,
+	cmControl controller.ConfigMapControlInterface,
 	setLister v1.StatefulSetLister,
 	svcLister corelisters.ServiceLister,
 	podLister corelisters.PodLister,
+	cmLister corelisters.ConfigMapLister,
 	tidbUpgrader Upgrader,
 	autoFailover bool,
 	tidbFailover Failover) manager.Manager {
 	return &tidbMemberManager{
 		setControl:                   setControl,
 		svcControl:                   svcControl,
+		podControl:                   podControl,
 		tidbControl:                  tidbControl,
+		pdControl:                    pdControl,
 		certControl:                  certControl,
+		cmControl:                    cmControl,
 		setLister:                    setLister,
 		svcLister:                    svcLister,
 		podLister:                    podLister,
+		cmLister:                     cmLister,
 		tidbUpgrader:                 tidbUpgrader,
 		autoFailover:                 autoFailover,
 		tidbFailover:                 tidbFailover,
@@ -92,11 +108,65 @@ func (tmm *tidbMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
 		return err
 	}
 
+	// Bring the tidb pods gated by the serving condition into service
+	if err := tmm.syncTiDBServingConditions(tc); err != nil {
+		return err
+	}
+
+	// Issue the certificates of the MySQL clients and publish their CA
+	if err := tmm.syncTiDBClientTLS(tc); err != nil {
+		return err
+	}
+
 	// Sync Tidb StatefulSet
 	if err := tmm.syncTiDBStatefulSetForTidbCluster(tc); err != nil {
 		return err
 	}
 
+	if err := tmm.syncTiDBService(tc); err != nil {
+		return err
+	}
+
+	return tmm.syncTiDBGroups(tc)
+}
+
+// syncTiDBGroups syncs the StatefulSet and the Service of each TiDB group through a view of tc, see tidbGroupView.
+// The StatefulSet of a group removed from tc is left as it is, so a group should be scaled in to 0 before its removal.
+func (tmm *tidbMemberManager) syncTiDBGroups(tc *v1alpha1.TidbCluster) error {
+	if len(tc.Spec.TiDBGroups) == 0 {
+		tc.Status.TiDBGroups = nil
+		return nil
+	}
+
+	groupStatus := map[string]v1alpha1.TiDBStatus{}
+	for i := range tc.Spec.TiDBGroups {
+		group := &tc.Spec.TiDBGroups[i]
+		view := tidbGroupView(tc, group)
+		err := tmm.syncTiDBGroup(view)
+		groupStatus[group.Name] = view.Status.TiDB
+		tc.Status.Conditions = view.Status.Conditions
+		if err != nil {
+			// keep the status of the groups that are not synced yet
+			for name, status := range tc.Status.TiDBGroups {
+				if _, ok := groupStatus[name]; !ok {
+					groupStatus[name] = status
+				}
+			}
+			tc.Status.TiDBGroups = groupStatus
+			return err
+		}
+	}
+	tc.Status.TiDBGroups = groupStatus
+	return nil
+}
+
+func (tmm *tidbMemberManager) syncTiDBGroup(tc *v1alpha1.TidbCluster) error {
+	if err := tmm.syncTiDBServingConditions(tc); err != nil {
+		return err
+	}
+	if err := tmm.syncTiDBStatefulSetForTidbCluster(tc); err != nil {
+		return err
+	}
 	return tmm.syncTiDBService(tc)
 }
 
@@ -139,10 +209,9 @@ func (tmm *tidbMemberManager) syncTiDBHeadlessServiceForTidbCluster(tc *v1alpha1
 
 func (tmm *tidbMemberManager) syncTiDBStatefulSetForTidbCluster(tc *v1alpha1.TidbCluster) error {
 	ns := tc.GetNamespace()
-	tcName := tc.GetName()
 
 	newTiDBSet := getNewTiDBSetForTidbCluster(tc)
-	oldTiDBSetTemp, err := tmm.setLister.StatefulSets(ns).Get(controller.TiDBMemberName(tcName))
+	oldTiDBSetTemp, err := tmm.setLister.StatefulSets(ns).Get(tidbSetName(tc))
 	if errors.IsNotFound(err) {
 		err = SetLastAppliedConfigAnnotation(newTiDBSet)
 		if err != nil {
@@ -154,16 +223,6 @@ func (tmm *tidbMemberManager) syncTiDBStatefulSetForTidbCluster(tc *v1alpha1.Tid
 				return err
 			}
 		}
-		if tc.Spec.TiDB.EnableTLSClient {
-			err := tmm.syncTiDBServerCerts(tc)
-			if err != nil {
-				return err
-			}
-			err = tmm.syncTiDBClientCerts(tc)
-			if err != nil {
-				return err
-			}
-		}
 		err = tmm.setControl.CreateStatefulSet(tc, newTiDBSet)
 		if err != nil {
 			return err
@@ -180,6 +239,13 @@ func (tmm *tidbMemberManager) syncTiDBStatefulSetForTidbCluster(tc *v1alpha1.Tid
 		return err
 	}
 
+	if err := keepUpgradeRolledBack(tc, v1alpha1.TiDBMemberType, newTiDBSet, oldTiDBSet); err != nil {
+		return err
+	}
+	if err := checkUpgradeVersion(tc, v1alpha1.TiDBMemberType, tmm.setLister, tmm.pdControl, oldTiDBSet, newTiDBSet); err != nil {
+		return err
+	}
+
 	if !templateEqual(newTiDBSet.Spec.Template, oldTiDBSet.Spec.Template) || tc.Status.TiDB.Phase == v1alpha1.UpgradePhase {
 		if err := tmm.tidbUpgrader.Upgrade(tc, oldTiDBSet, newTiDBSet); err != nil {
 			return err
@@ -201,6 +267,7 @@ func (tmm *tidbMemberManager) syncTiDBStatefulSetForTidbCluster(tc *v1alpha1.Tid
 		set.Spec.Template = newTiDBSet.Spec.Template
 		*set.Spec.Replicas = *newTiDBSet.Spec.Replicas
 		set.Spec.UpdateStrategy = newTiDBSet.Spec.UpdateStrategy
+		setUpgradeRollbackAnnotations(&set, oldTiDBSet, newTiDBSet)
 		err := SetLastAppliedConfigAnnotation(&set)
 		if err != nil {
 			return err
@@ -212,18 +279,69 @@ func (tmm *tidbMemberManager) syncTiDBStatefulSetForTidbCluster(tc *v1alpha1.Tid
 	return nil
 }
 
+// syncTiDBServingConditions sets the serving condition of the tidb pods to true unless they are draining,
+// a pod drained by the upgrader is brought back into service if it is no longer going to be restarted.
+// The pods drained by the webhook are going to be deleted and are left to the webhook
+func (tmm *tidbMemberManager) syncTiDBServingConditions(tc *v1alpha1.TidbCluster) error {
+	ns := tc.GetNamespace()
+
+	if tc.Status.TiDB.StatefulSet == nil {
+		return nil
+	}
+	for i := int32(0); i < tc.TiDBStsActualReplicas(); i++ {
+		podName := tidbSetPodName(tc, i)
+		pod, err := tmm.podLister.Pods(ns).Get(podName)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !HasTiDBServingGate(pod) {
+			continue
+		}
+
+		pod = pod.DeepCopy()
+		if IsTiDBDraining(pod) {
+			if pod.Annotations[label.AnnTiDBDrainBy] == TiDBDrainByWebhook {
+				continue
+			}
+			if i >= tc.TiDBStsDesiredReplicas() || pod.Labels[apps.ControllerRevisionHashLabelKey] != tc.Status.TiDB.StatefulSet.UpdateRevision {
+				continue
+			}
+			EndTiDBDrain(pod)
+			updatedPod, err := tmm.podControl.UpdatePod(tc, pod)
+			if err != nil {
+				return err
+			}
+			pod = updatedPod.DeepCopy()
+		}
+		if SetTiDBServingCondition(pod, true) {
+			if _, err := tmm.podControl.UpdatePodStatus(tc, pod); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // syncTiDBClusterCerts creates the cert pair for TiDB if not exist, the cert
 // pair is used to communicate with other TiDB components, like TiKVs and PDs
 func (tmm *tidbMemberManager) syncTiDBClusterCerts(tc *v1alpha1.TidbCluster) error {
+	certOpts := tidbClusterCertOptions(tc)
+	if tmm.certControl.CheckSecret(certOpts.Namespace, certOpts.SecretName()) {
+		return nil
+	}
+	return tmm.certControl.Create(controller.GetOwnerRef(tc), certOpts)
+}
+
+// tidbClusterCertOptions returns the options to create the TiDB cluster cert pair
+func tidbClusterCertOptions(tc *v1alpha1.TidbCluster) *controller.TiDBClusterCertOptions {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
 	svcName := controller.TiDBMemberName(tcName)
 	peerName := controller.TiDBPeerMemberName(tcName)
 
-	if tmm.certControl.CheckSecret(ns, svcName) {
-		return nil
-	}
-
 	hostList := []string{
 		svcName,
 		peerName,
@@ -232,73 +350,209 @@ func (tmm *tidbMemberManager) syncTiDBClusterCerts(tc *v1alpha1.TidbCluster) err
 		fmt.Sprintf("*.%s.%s", peerName, ns),
 	}
 
-	certOpts := &controller.TiDBClusterCertOptions{
-		Namespace:  ns,
-		Instance:   tcName,
-		CommonName: svcName,
-		HostList:   hostList,
-		Component:  "tidb",
-		Suffix:     "tidb",
+	return &controller.TiDBClusterCertOptions{
+		Namespace:    ns,
+		Instance:     tcName,
+		CommonName:   svcName,
+		HostList:     hostList,
+		Component:    "tidb",
+		Suffix:       "tidb",
+		Issuer:       tc.Spec.TLSIssuer,
+		CASecretName: tc.TLSCASecretName(),
 	}
+}
 
-	return tmm.certControl.Create(controller.GetOwnerRef(tc), certOpts)
+// syncTiDBClientTLS creates the cert pairs for the MySQL protocol if not exist, and publishes the CA of
+// the TiDB server cert pair in a ConfigMap, so that DB clients can connect to TiDB with encrypted connections
+func (tmm *tidbMemberManager) syncTiDBClientTLS(tc *v1alpha1.TidbCluster) error {
+	if !tc.Spec.TiDB.EnableTLSClient {
+		return nil
+	}
+
+	svc, err := tmm.svcLister.Services(tc.GetNamespace()).Get(controller.TiDBMemberName(tc.GetName()))
+	if errors.IsNotFound(err) {
+		// the SANs of the LoadBalancer are added when the cert pair is renewed by the TLS cert manager
+		svc = nil
+	} else if err != nil {
+		return err
+	}
+
+	certs := append([]*controller.TiDBClusterCertOptions{
+		tidbServerCertOptions(tc, svc),
+		tidbClientCertOptions(tc),
+	}, tidbUserCertOptions(tc)...)
+	for _, certOpts := range certs {
+		if tmm.certControl.CheckSecret(certOpts.Namespace, certOpts.SecretName()) {
+			continue
+		}
+		if err := tmm.certControl.Create(controller.GetOwnerRef(tc), certOpts); err != nil {
+			return err
+		}
+	}
+
+	return tmm.syncTiDBClientCAConfigMap(tc)
 }
 
-// syncTiDBServerCerts creates the cert pair for TiDB if not exist, the cert
-// pair is used to communicate with DB clients with encrypted connections
-func (tmm *tidbMemberManager) syncTiDBServerCerts(tc *v1alpha1.TidbCluster) error {
-	suffix := "tidb-server"
+// syncTiDBClientCAConfigMap publishes the CA of the TiDB server cert pair in a ConfigMap
+func (tmm *tidbMemberManager) syncTiDBClientCAConfigMap(tc *v1alpha1.TidbCluster) error {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
-	svcName := fmt.Sprintf("%s-%s", tcName, suffix)
 
-	if tmm.certControl.CheckSecret(ns, svcName) {
+	secretName := tidbServerCertOptions(tc, nil).SecretName()
+	ca, err := tmm.certControl.GetCA(ns, secretName)
+	if errors.IsNotFound(err) {
+		return controller.RequeueErrorf("TidbCluster: [%s/%s], waiting for the TiDB server certificate in secret %s", ns, tcName, secretName)
+	}
+	if err != nil {
+		return err
+	}
+
+	newCm := getNewTiDBClientCAConfigMap(tc, ca)
+	oldCmTmp, err := tmm.cmLister.ConfigMaps(ns).Get(newCm.Name)
+	if errors.IsNotFound(err) {
+		return tmm.cmControl.CreateConfigMap(tc, newCm)
+	}
+	if err != nil {
+		return err
+	}
+
+	if apiequality.Semantic.DeepEqual(oldCmTmp.Data, newCm.Data) {
 		return nil
 	}
+	oldCm := oldCmTmp.DeepCopy()
+	oldCm.Data = newCm.Data
+	_, err = tmm.cmControl.UpdateConfigMap(tc, oldCm)
+	return err
+}
 
-	hostList := []string{
+// getNewTiDBClientCAConfigMap returns the ConfigMap holding the CA of the TiDB server cert pair in the ca.crt key
+func getNewTiDBClientCAConfigMap(tc *v1alpha1.TidbCluster, ca []byte) *corev1.ConfigMap {
+	instanceName := tc.GetLabels()[label.InstanceLabelKey]
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            controller.TiDBClientCAConfigMapName(tc.GetName()),
+			Namespace:       tc.GetNamespace(),
+			Labels:          label.New().Instance(instanceName).TiDB().Labels(),
+			OwnerReferences: []metav1.OwnerReference{controller.GetOwnerRef(tc)},
+		},
+		Data: map[string]string{
+			corev1.ServiceAccountRootCAKey: string(ca),
+		},
+	}
+}
+
+// tidbServerCertOptions returns the options to create the TiDB server cert pair, the SANs include the
+// TiDB Service, the ingress of its LoadBalancer if svc is not nil, and the extra SANs in the spec
+func tidbServerCertOptions(tc *v1alpha1.TidbCluster, svc *corev1.Service) *controller.TiDBClusterCertOptions {
+	suffix := "tidb-server"
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	commonName := fmt.Sprintf("%s-%s", tcName, suffix)
+	svcName := controller.TiDBMemberName(tcName)
+
+	hosts := sets.NewString(
+		commonName,
+		fmt.Sprintf("%s.%s", commonName, ns),
 		svcName,
 		fmt.Sprintf("%s.%s", svcName, ns),
+		fmt.Sprintf("%s.%s.svc", svcName, ns),
+	)
+	ips := sets.NewString()
+	addSAN := func(san string) {
+		if san == "" {
+			return
+		}
+		if ip := net.ParseIP(san); ip != nil {
+			ips.Insert(ip.String())
+		} else {
+			hosts.Insert(san)
+		}
 	}
 
-	certOpts := &controller.TiDBClusterCertOptions{
-		Namespace:  ns,
-		Instance:   tcName,
-		CommonName: svcName,
-		HostList:   hostList,
-		Component:  "tidb",
-		Suffix:     suffix,
+	if tc.Spec.TiDB.Service != nil {
+		addSAN(tc.Spec.TiDB.Service.LoadBalancerIP)
+	}
+	if svc != nil {
+		for _, ingress := range svc.Status.LoadBalancer.Ingress {
+			addSAN(ingress.IP)
+			addSAN(ingress.Hostname)
+		}
+	}
+	if tc.Spec.TiDB.TLSClient != nil {
+		for _, san := range tc.Spec.TiDB.TLSClient.ExtraSANs {
+			addSAN(san)
+		}
+	}
+	// the MySQL clients may connect to the Services of the TiDB groups as well
+	for _, group := range tc.Spec.TiDBGroups {
+		groupSvcName := controller.TiDBGroupMemberName(tcName, group.Name)
+		hosts.Insert(
+			groupSvcName,
+			fmt.Sprintf("%s.%s", groupSvcName, ns),
+			fmt.Sprintf("%s.%s.svc", groupSvcName, ns),
+		)
+		if group.Service != nil {
+			addSAN(group.Service.LoadBalancerIP)
+		}
 	}
 
-	return tmm.certControl.Create(controller.GetOwnerRef(tc), certOpts)
+	return &controller.TiDBClusterCertOptions{
+		Namespace:    ns,
+		Instance:     tcName,
+		CommonName:   commonName,
+		HostList:     hosts.List(),
+		IPList:       ips.List(),
+		Component:    "tidb",
+		Suffix:       suffix,
+		Issuer:       tc.Spec.TLSIssuer,
+		CASecretName: tc.TLSCASecretName(),
+	}
 }
 
-// syncTiDBClientCerts creates the cert pair for TiDB if not exist, the cert
-// pair is used for DB clients to connect to TiDB server with encrypted connections
-func (tmm *tidbMemberManager) syncTiDBClientCerts(tc *v1alpha1.TidbCluster) error {
+// tidbClientCertOptions returns the options to create the TiDB client cert pair
+func tidbClientCertOptions(tc *v1alpha1.TidbCluster) *controller.TiDBClusterCertOptions {
 	suffix := "tidb-client"
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
 	commonName := fmt.Sprintf("%s-%s", tcName, suffix)
 
-	if tmm.certControl.CheckSecret(ns, commonName) {
-		return nil
-	}
-
 	hostList := []string{
 		commonName,
 	}
 
-	certOpts := &controller.TiDBClusterCertOptions{
-		Namespace:  ns,
-		Instance:   tcName,
-		CommonName: commonName,
-		HostList:   hostList,
-		Component:  "tidb",
-		Suffix:     suffix,
+	return &controller.TiDBClusterCertOptions{
+		Namespace:    ns,
+		Instance:     tcName,
+		CommonName:   commonName,
+		HostList:     hostList,
+		Component:    "tidb",
+		Suffix:       suffix,
+		Issuer:       tc.Spec.TLSIssuer,
+		CASecretName: tc.TLSCASecretName(),
 	}
+}
 
-	return tmm.certControl.Create(controller.GetOwnerRef(tc), certOpts)
+// tidbUserCertOptions returns the options to create the client cert pairs of the MySQL users in the spec,
+// the common name of the cert pair is the name of the user
+func tidbUserCertOptions(tc *v1alpha1.TidbCluster) []*controller.TiDBClusterCertOptions {
+	if tc.Spec.TiDB.TLSClient == nil {
+		return nil
+	}
+
+	var certs []*controller.TiDBClusterCertOptions
+	for _, user := range tc.Spec.TiDB.TLSClient.Users {
+		certs = append(certs, &controller.TiDBClusterCertOptions{
+			Namespace:    tc.GetNamespace(),
+			Instance:     tc.GetName(),
+			CommonName:   user,
+			HostList:     []string{user},
+			Component:    "tidb",
+			Suffix:       fmt.Sprintf("tidb-client-%s", user),
+			Issuer:       tc.Spec.TLSIssuer,
+			CASecretName: tc.TLSCASecretName(),
+		})
+	}
+	return certs
 }
 
 func (tmm *tidbMemberManager) syncTiDBService(tc *v1alpha1.TidbCluster) error {
@@ -310,6 +564,11 @@ func (tmm *tidbMemberManager) syncTiDBService(tc *v1alpha1.TidbCluster) error {
 	}
 
 	ns := newSvc.Namespace
+	selector, err := tmm.tidbServiceSelector(tc)
+	if err != nil {
+		return err
+	}
+	newSvc.Spec.Selector = selector
 
 	oldSvcTmp, err := tmm.svcLister.Services(ns).Get(newSvc.Name)
 	if errors.IsNotFound(err) {
@@ -355,6 +614,44 @@ func (tmm *tidbMemberManager) syncTiDBService(tc *v1alpha1.TidbCluster) error {
 	return nil
 }
 
+// tidbServiceSelector returns the selector of the TiDB Service of tc. The pods of the TiDB groups also match the labels
+// of the pods of spec.tidb, so once the cluster has TiDB groups, the Service of spec.tidb selects the empty group,
+// with which the pods of spec.tidb are labelled before, see tidbPodLabels.
+func (tmm *tidbMemberManager) tidbServiceSelector(tc *v1alpha1.TidbCluster) (map[string]string, error) {
+	if len(tc.Spec.TiDBGroups) == 0 || tidbGroupName(tc) != "" {
+		return labelTiDB(tc).Labels(), nil
+	}
+	if err := tmm.syncTiDBGroupLabels(tc); err != nil {
+		return nil, err
+	}
+	return labelTiDB(tc).TiDBGroup("").Labels(), nil
+}
+
+// syncTiDBGroupLabels labels the existing pods of spec.tidb with the empty TiDB group in place, so that they stay
+// selected by the Service of spec.tidb until the StatefulSet recreates them from the template labelled with it.
+func (tmm *tidbMemberManager) syncTiDBGroupLabels(tc *v1alpha1.TidbCluster) error {
+	selector, err := labelTiDB(tc).Selector()
+	if err != nil {
+		return err
+	}
+	pods, err := tmm.podLister.Pods(tc.GetNamespace()).List(selector)
+	if err != nil {
+		return err
+	}
+	// the pods of the TiDB groups are always labelled with their group
+	for _, pod := range pods {
+		if _, ok := pod.Labels[label.TiDBGroupLabelKey]; ok {
+			continue
+		}
+		pod = pod.DeepCopy()
+		pod.Labels[label.TiDBGroupLabelKey] = ""
+		if _, err := tmm.podControl.UpdatePod(tc, pod); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func getNewTiDBServiceOrNil(tc *v1alpha1.TidbCluster) *corev1.Service {
 
 	svcSpec := tc.Spec.TiDB.Service
@@ -363,10 +660,8 @@ func getNewTiDBServiceOrNil(tc *v1alpha1.TidbCluster) *corev1.Service {
 	}
 
 	ns := tc.Namespace
-	tcName := tc.Name
-	instanceName := tc.GetLabels()[label.InstanceLabelKey]
-	tidbLabels := label.New().Instance(instanceName).TiDB().Labels()
-	svcName := controller.TiDBMemberName(tcName)
+	tidbLabels := labelTiDB(tc).Labels()
+	svcName := tidbSetName(tc)
 
 	ports := []corev1.ServicePort{
 		{
@@ -436,8 +731,7 @@ func getNewTiDBHeadlessServiceForTidbCluster(tc *v1alpha1.TidbCluster) *corev1.S
 func getNewTiDBSetForTidbCluster(tc *v1alpha1.TidbCluster) *apps.StatefulSet {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
-	instanceName := tc.GetLabels()[label.InstanceLabelKey]
-	tidbConfigMap := controller.MemberConfigMapName(tc, v1alpha1.TiDBMemberType)
+	tidbConfigMap := tidbConfigMapName(tc)
 
 	annMount, annVolume := annotationsMountVolume()
 	volMounts := []corev1.VolumeMount{
@@ -476,22 +770,10 @@ func getNewTiDBSetForTidbCluster(tc *v1alpha1.TidbCluster) *apps.StatefulSet {
 		},
 	}
 	if tc.Spec.EnableTLSCluster {
-		vols = append(vols, corev1.Volume{
-			Name: "tidb-tls", VolumeSource: corev1.VolumeSource{
-				Secret: &corev1.SecretVolumeSource{
-					SecretName: controller.TiDBMemberName(tcName),
-				},
-			},
-		})
+		vols = append(vols, tlsSecretVolume(tc, "tidb-tls", controller.TiDBMemberName(tcName)))
 	}
 	if tc.Spec.TiDB.EnableTLSClient {
-		vols = append(vols, corev1.Volume{
-			Name: "tidb-server-tls", VolumeSource: corev1.VolumeSource{
-				Secret: &corev1.SecretVolumeSource{
-					SecretName: fmt.Sprintf("%s-%s", controller.TiDBMemberName(tcName), "server"),
-				},
-			},
-		})
+		vols = append(vols, tlsSecretVolume(tc, "tidb-server-tls", fmt.Sprintf("%s-%s", controller.TiDBMemberName(tcName), "server")))
 	}
 
 	sysctls := "sysctl -w"
@@ -617,21 +899,23 @@ func getNewTiDBSetForTidbCluster(tc *v1alpha1.TidbCluster) *apps.StatefulSet {
 		dnsPolicy = corev1.DNSClusterFirstWithHostNet
 	}
 
-	tidbLabel := label.New().Instance(instanceName).TiDB()
+	tidbLabel := labelTiDB(tc)
 	podAnnotations := CombineAnnotations(controller.AnnProm(10080), tc.BaseTiDBSpec().Annotations())
+	podAnnotations = CombineAnnotations(podAnnotations, tlsCertRenewAnnotations(tc,
+		controller.TiDBMemberName(tcName), fmt.Sprintf("%s-%s", controller.TiDBMemberName(tcName), "server")))
 	tidbSet := &apps.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:            controller.TiDBMemberName(tcName),
+			Name:            tidbSetName(tc),
 			Namespace:       ns,
 			Labels:          tidbLabel.Labels(),
 			OwnerReferences: []metav1.OwnerReference{controller.GetOwnerRef(tc)},
 		},
 		Spec: apps.StatefulSetSpec{
 			Replicas: controller.Int32Ptr(tc.TiDBStsDesiredReplicas()),
-			Selector: tidbLabel.LabelSelector(),
+			Selector: labelTiDB(tc).LabelSelector(),
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels:      tidbLabel.Labels(),
+					Labels:      tidbPodLabels(tc),
 					Annotations: podAnnotations,
 				},
 				Spec: corev1.PodSpec{
@@ -656,6 +940,12 @@ func getNewTiDBSetForTidbCluster(tc *v1alpha1.TidbCluster) *apps.StatefulSet {
 			},
 		},
 	}
+	if tc.Spec.TiDB.Drain != nil {
+		// the pod is removed from the service endpoints by setting the condition to false before restarted
+		tidbSet.Spec.Template.Spec.ReadinessGates = []corev1.PodReadinessGate{
+			{ConditionType: TiDBServingCondition},
+		}
+	}
 	return tidbSet
 }
 
@@ -666,10 +956,11 @@ func (tmm *tidbMemberManager) syncTidbClusterStatus(tc *v1alpha1.TidbCluster, se
 	if err != nil {
 		return err
 	}
-	if upgrading && tc.Status.TiKV.Phase != v1alpha1.UpgradePhase && tc.Status.PD.Phase != v1alpha1.UpgradePhase {
+	if upgrading && tc.Status.TiKV.Phase != v1alpha1.UpgradePhase && !tc.TiKVGroupUpgrading() && !tc.TiFlashUpgrading() && tc.Status.PD.Phase != v1alpha1.UpgradePhase {
 		tc.Status.TiDB.Phase = v1alpha1.UpgradePhase
 	} else {
 		tc.Status.TiDB.Phase = v1alpha1.NormalPhase
+		tc.Status.TiDB.Upgrade = nil
 	}
 
 	tidbStatus := map[string]v1alpha1.TiDBMember{}
@@ -707,10 +998,7 @@ func tidbStatefulSetIsUpgrading(podLister corelisters.PodLister, set *apps.State
 	if statefulSetIsUpgrading(set) {
 		return true, nil
 	}
-	selector, err := label.New().
-		Instance(tc.GetLabels()[label.InstanceLabelKey]).
-		TiDB().
-		Selector()
+	selector, err := labelTiDB(tc).Selector()
 	if err != nil {
 		return false, err
 	}
@@ -719,6 +1007,10 @@ func tidbStatefulSetIsUpgrading(podLister corelisters.PodLister, set *apps.State
 		return false, err
 	}
 	for _, pod := range tidbPods {
+		// the pods of the TiDB groups also match the selector of the StatefulSet of spec.tidb
+		if pod.Labels[label.TiDBGroupLabelKey] != tidbGroupName(tc) {
+			continue
+		}
 		revisionHash, exist := pod.Labels[apps.ControllerRevisionHashLabelKey]
 		if !exist {
 			return false, nil
@@ -26,6 +26,7 @@ import (
 	informers "github.com/pingcap/tidb-operator/pkg/client/informers/externalversions"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/label"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
@@ -721,6 +722,203 @@ func TestTiDBMemberManagerSyncTidbService(t *testing.T) {
 	}
 }
 
+func TestTiDBMemberManagerSyncTiDBClientTLS(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name        string
+		disabled    bool
+		ca          []byte
+		oldCA       []byte
+		errExpectFn func(*GomegaWithT, error)
+		expectFn    func(*GomegaWithT, []*controller.TiDBClusterCertOptions, *corev1.ConfigMap, error)
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+
+		tc := newTidbClusterForTiDB()
+		tc.Spec.TiDB.EnableTLSClient = !test.disabled
+		tc.Spec.TiDB.TLSClient = &v1alpha1.TiDBTLSClient{Users: []string{"app"}}
+
+		tmm, _, _, _, indexers := newFakeTiDBMemberManager()
+		certControl := tmm.certControl.(*controller.FakeCertControl)
+		if test.ca != nil {
+			certControl.SetCA(tc.GetNamespace(), "test-tidb-server", test.ca)
+		}
+		if test.oldCA != nil {
+			g.Expect(indexers.cm.Add(getNewTiDBClientCAConfigMap(tc, test.oldCA))).To(Succeed())
+		}
+
+		err := tmm.syncTiDBClientTLS(tc)
+		test.errExpectFn(g, err)
+
+		cm, cmErr := tmm.cmLister.ConfigMaps(tc.GetNamespace()).Get("test-tidb-client-ca")
+		test.expectFn(g, certControl.Created(), cm, cmErr)
+	}
+
+	tests := []testcase{
+		{
+			name:     "tls client is disabled",
+			disabled: true,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+			expectFn: func(g *GomegaWithT, created []*controller.TiDBClusterCertOptions, _ *corev1.ConfigMap, err error) {
+				g.Expect(created).To(BeEmpty())
+				g.Expect(errors.IsNotFound(err)).To(BeTrue())
+			},
+		},
+		{
+			name: "server certificate is not issued yet",
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+			},
+			expectFn: func(g *GomegaWithT, created []*controller.TiDBClusterCertOptions, _ *corev1.ConfigMap, err error) {
+				var secretNames []string
+				for _, certOpts := range created {
+					secretNames = append(secretNames, certOpts.SecretName())
+				}
+				g.Expect(secretNames).To(Equal([]string{"test-tidb-server", "test-tidb-client", "test-tidb-client-app"}))
+				g.Expect(created[2].CommonName).To(Equal("app"))
+				g.Expect(errors.IsNotFound(err)).To(BeTrue())
+			},
+		},
+		{
+			name: "CA is published",
+			ca:   []byte("ca"),
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+			expectFn: func(g *GomegaWithT, _ []*controller.TiDBClusterCertOptions, cm *corev1.ConfigMap, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(cm.Data).To(Equal(map[string]string{"ca.crt": "ca"}))
+			},
+		},
+		{
+			name:  "CA is rotated",
+			ca:    []byte("new ca"),
+			oldCA: []byte("ca"),
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+			expectFn: func(g *GomegaWithT, _ []*controller.TiDBClusterCertOptions, cm *corev1.ConfigMap, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(cm.Data).To(Equal(map[string]string{"ca.crt": "new ca"}))
+			},
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
+
+func TestTiDBServerCertOptions(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForTiDB()
+	certOpts := tidbServerCertOptions(tc, nil)
+	g.Expect(certOpts.SecretName()).To(Equal("test-tidb-server"))
+	g.Expect(certOpts.HostList).To(ConsistOf(
+		"test-tidb-server",
+		"test-tidb-server.default",
+		"test-tidb",
+		"test-tidb.default",
+		"test-tidb.default.svc",
+	))
+	g.Expect(certOpts.IPList).To(BeEmpty())
+
+	tc.Spec.TiDB.Service = &v1alpha1.TiDBServiceSpec{
+		ServiceSpec: v1alpha1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer, LoadBalancerIP: "10.0.0.1"},
+	}
+	tc.Spec.TiDB.TLSClient = &v1alpha1.TiDBTLSClient{ExtraSANs: []string{"tidb.example.com", "192.168.0.1"}}
+	svc := &corev1.Service{
+		Status: corev1.ServiceStatus{
+			LoadBalancer: corev1.LoadBalancerStatus{
+				Ingress: []corev1.LoadBalancerIngress{
+					{IP: "10.0.0.1"},
+					{Hostname: "lb.example.com"},
+				},
+			},
+		},
+	}
+	certOpts = tidbServerCertOptions(tc, svc)
+	g.Expect(certOpts.HostList).To(ContainElement("lb.example.com"))
+	g.Expect(certOpts.HostList).To(ContainElement("tidb.example.com"))
+	g.Expect(certOpts.IPList).To(ConsistOf("10.0.0.1", "192.168.0.1"))
+}
+
+func TestTiDBMemberManagerSyncTiDBGroups(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForTiDB()
+	tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{
+		"tikv-0": {PodName: "tikv-0", State: v1alpha1.TiKVStateUp},
+	}
+	tc.Status.TiKV.StatefulSet = &apps.StatefulSetStatus{ReadyReplicas: 1}
+	tc.Spec.TiDB.Service = &v1alpha1.TiDBServiceSpec{ServiceSpec: v1alpha1.ServiceSpec{Type: corev1.ServiceTypeClusterIP}}
+	tc.Spec.TiDBGroups = []v1alpha1.TiDBGroupSpec{
+		{
+			Name: "reporting",
+			TiDBSpec: v1alpha1.TiDBSpec{
+				Replicas: 2,
+				Service:  &v1alpha1.TiDBServiceSpec{ServiceSpec: v1alpha1.ServiceSpec{Type: corev1.ServiceTypeClusterIP}},
+			},
+		},
+	}
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	tmm, _, podIndexer, _, _ := newFakeTiDBMemberManager()
+	// a pod of spec.tidb created before the TiDB group is added
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-tidb-0",
+			Namespace: ns,
+			Labels:    label.New().Instance(tc.GetLabels()[label.InstanceLabelKey]).TiDB().Labels(),
+		},
+	}
+	podIndexer.Add(pod)
+
+	err := tmm.Sync(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	set, err := tmm.setLister.StatefulSets(ns).Get(controller.TiDBMemberName(tcName))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(set.Spec.Selector.MatchLabels).NotTo(HaveKey(label.TiDBGroupLabelKey))
+	g.Expect(set.Spec.Template.Labels).To(HaveKeyWithValue(label.TiDBGroupLabelKey, ""))
+	groupSet, err := tmm.setLister.StatefulSets(ns).Get("test-tidb-reporting")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(*groupSet.Spec.Replicas).To(Equal(int32(2)))
+	g.Expect(groupSet.Spec.Selector.MatchLabels).To(HaveKeyWithValue(label.TiDBGroupLabelKey, "reporting"))
+	g.Expect(groupSet.Spec.Template.Labels).To(HaveKeyWithValue(label.TiDBGroupLabelKey, "reporting"))
+	g.Expect(groupSet.Spec.Template.Spec.Containers[0].Image).To(Equal(v1alpha1.TiDBMemberType.String()))
+	g.Expect(groupSet.Spec.ServiceName).To(Equal(controller.TiDBPeerMemberName(tcName)))
+	g.Expect(tc.Status.TiDBGroups).To(HaveKey("reporting"))
+	g.Expect(tc.Status.TiDBGroups["reporting"].StatefulSet).NotTo(BeNil())
+
+	groupSvc, err := tmm.svcLister.Services(ns).Get("test-tidb-reporting")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(groupSvc.Spec.Selector).To(HaveKeyWithValue(label.TiDBGroupLabelKey, "reporting"))
+	// the existing pod of spec.tidb is labelled in place, and the Service of spec.tidb excludes the pods of the groups
+	obj, _, err := podIndexer.GetByKey(fmt.Sprintf("%s/%s", ns, pod.GetName()))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(obj.(*corev1.Pod).Labels).To(HaveKeyWithValue(label.TiDBGroupLabelKey, ""))
+	svc, err := tmm.svcLister.Services(ns).Get(controller.TiDBMemberName(tcName))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(svc.Spec.Selector).To(HaveKeyWithValue(label.TiDBGroupLabelKey, ""))
+
+	// the status of removed groups is cleaned up
+	tc.Spec.TiDBGroups = nil
+	err = tmm.Sync(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tc.Status.TiDBGroups).To(BeNil())
+	set, err = tmm.setLister.StatefulSets(ns).Get(controller.TiDBMemberName(tcName))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(set.Spec.Template.Labels).NotTo(HaveKey(label.TiDBGroupLabelKey))
+}
+
 type fakeIndexers struct {
 	pod    cache.Indexer
 	tc     cache.Indexer
@@ -729,6 +927,7 @@ type fakeIndexers struct {
 	csr    cache.Indexer
 	secret cache.Indexer
 	set    cache.Indexer
+	cm     cache.Indexer
 }
 
 func newFakeTiDBMemberManager() (*tidbMemberManager, *controller.FakeStatefulSetControl, cache.Indexer, *controller.FakeTiDBControl, *fakeIndexers) {
@@ -741,22 +940,30 @@ func newFakeTiDBMemberManager() (*tidbMemberManager, *controller.FakeStatefulSet
 	podInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().Pods()
 	csrInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Certificates().V1beta1().CertificateSigningRequests()
 	secretInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().Secrets()
+	cmInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().ConfigMaps()
 	setControl := controller.NewFakeStatefulSetControl(setInformer, tcInformer)
 	svcControl := controller.NewFakeServiceControl(svcInformer, epsInformer, tcInformer)
 	secControl := controller.NewFakeSecretControl(kubeCli, secretInformer.Lister())
 	certControl := controller.NewFakeCertControl(kubeCli, csrInformer.Lister(), secControl)
+	cmControl := controller.NewFakeConfigMapControl(cmInformer)
 	tidbUpgrader := NewFakeTiDBUpgrader()
 	tidbFailover := NewFakeTiDBFailover()
 	tidbControl := controller.NewFakeTiDBControl()
 
+	pdControl := pdapi.NewFakePDControl(kubeCli)
+	podControl := controller.NewFakePodControl(podInformer)
 	tmm := &tidbMemberManager{
 		setControl,
 		svcControl,
+		podControl,
 		tidbControl,
+		pdControl,
 		certControl,
+		cmControl,
 		setInformer.Lister(),
 		svcInformer.Lister(),
 		podInformer.Lister(),
+		cmInformer.Lister(),
 		tidbUpgrader,
 		true,
 		tidbFailover,
@@ -770,6 +977,7 @@ func newFakeTiDBMemberManager() (*tidbMemberManager, *controller.FakeStatefulSet
 		csr:    csrInformer.Informer().GetIndexer(),
 		secret: secretInformer.Informer().GetIndexer(),
 		set:    setInformer.Informer().GetIndexer(),
+		cm:     cmInformer.Informer().GetIndexer(),
 	}
 	return tmm, setControl, podInformer.Informer().GetIndexer(), tidbControl, indexers
 }
@@ -802,6 +1010,104 @@ func newTidbClusterForTiDB() *v1alpha1.TidbCluster {
 	}
 }
 
+func TestTiDBMemberManagerSyncTiDBServingConditions(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name           string
+		ordinal        int32
+		revision       string
+		drainBy        string
+		expectDraining bool
+		expectServing  bool
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+
+		tmm, _, podIndexer, _, _ := newFakeTiDBMemberManager()
+		tc := newTidbClusterForTiDB()
+		tc.Spec.TiDB.Replicas = 2
+		tc.Status.TiDB.StatefulSet = &apps.StatefulSetStatus{
+			Replicas:        3,
+			CurrentRevision: "1",
+			UpdateRevision:  "2",
+		}
+
+		podName := tidbPodName(tc.GetName(), test.ordinal)
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      podName,
+				Namespace: corev1.NamespaceDefault,
+				Labels:    map[string]string{apps.ControllerRevisionHashLabelKey: test.revision},
+			},
+			Spec: corev1.PodSpec{
+				ReadinessGates: []corev1.PodReadinessGate{{ConditionType: TiDBServingCondition}},
+			},
+		}
+		if test.drainBy != "" {
+			BeginTiDBDrain(pod, test.drainBy)
+			SetTiDBServingCondition(pod, false)
+		}
+		podIndexer.Add(pod)
+
+		err := tmm.syncTiDBServingConditions(tc)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		obj, _, err := podIndexer.GetByKey(fmt.Sprintf("%s/%s", corev1.NamespaceDefault, podName))
+		g.Expect(err).NotTo(HaveOccurred())
+		pod = obj.(*corev1.Pod)
+		g.Expect(IsTiDBDraining(pod)).To(Equal(test.expectDraining))
+		g.Expect(IsTiDBServing(pod)).To(Equal(test.expectServing))
+	}
+
+	tests := []testcase{
+		{
+			name:           "new pod is brought into service",
+			ordinal:        0,
+			revision:       "2",
+			expectDraining: false,
+			expectServing:  true,
+		},
+		{
+			name:           "pod draining for upgrade stays out of service",
+			ordinal:        0,
+			revision:       "1",
+			drainBy:        TiDBDrainByUpgrader,
+			expectDraining: true,
+			expectServing:  false,
+		},
+		{
+			name:           "pod draining for scale in stays out of service",
+			ordinal:        2,
+			revision:       "2",
+			drainBy:        TiDBDrainByWebhook,
+			expectDraining: true,
+			expectServing:  false,
+		},
+		{
+			name:           "pod no longer to be upgraded is brought back into service",
+			ordinal:        0,
+			revision:       "2",
+			drainBy:        TiDBDrainByUpgrader,
+			expectDraining: false,
+			expectServing:  true,
+		},
+		{
+			name:           "pod drained by the webhook stays out of service",
+			ordinal:        0,
+			revision:       "2",
+			drainBy:        TiDBDrainByWebhook,
+			expectDraining: true,
+			expectServing:  false,
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
+
 func TestGetNewTiDBHeadlessServiceForTidbCluster(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -942,6 +1248,26 @@ func TestGetNewTiDBSetForTidbCluster(t *testing.T) {
 			},
 			testSts: testHostNetwork(t, false, v1.DNSClusterFirst),
 		},
+		{
+			name: "tidb pods are gated by the serving condition when draining is enabled",
+			tc: v1alpha1.TidbCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "tc",
+					Namespace: "ns",
+				},
+				Spec: v1alpha1.TidbClusterSpec{
+					TiDB: v1alpha1.TiDBSpec{
+						Drain: &v1alpha1.TiDBDrainSpec{},
+					},
+				},
+			},
+			testSts: func(sts *apps.StatefulSet) {
+				g := NewGomegaWithT(t)
+				g.Expect(sts.Spec.Template.Spec.ReadinessGates).To(Equal([]corev1.PodReadinessGate{
+					{ConditionType: TiDBServingCondition},
+				}))
+			},
+		},
 		// TODO add more tests
 	}
 
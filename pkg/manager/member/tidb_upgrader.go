@@ -17,20 +17,26 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/record"
 	glog "k8s.io/klog"
 )
 
 type tidbUpgrader struct {
 	podLister   corelisters.PodLister
+	podControl  controller.PodControlInterface
 	tidbControl controller.TiDBControlInterface
+	recorder    record.EventRecorder
 }
 
 // NewTiDBUpgrader returns a tidb Upgrader
-func NewTiDBUpgrader(tidbControl controller.TiDBControlInterface, podLister corelisters.PodLister) Upgrader {
+func NewTiDBUpgrader(tidbControl controller.TiDBControlInterface, podControl controller.PodControlInterface, podLister corelisters.PodLister, recorder record.EventRecorder) Upgrader {
 	return &tidbUpgrader{
 		tidbControl: tidbControl,
+		podControl:  podControl,
 		podLister:   podLister,
+		recorder:    recorder,
 	}
 }
 
@@ -38,7 +44,7 @@ func (tdu *tidbUpgrader) Upgrade(tc *v1alpha1.TidbCluster, oldSet *apps.Stateful
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
 
-	if tc.Status.PD.Phase == v1alpha1.UpgradePhase || tc.Status.TiKV.Phase == v1alpha1.UpgradePhase {
+	if tc.Status.PD.Phase == v1alpha1.UpgradePhase || tc.Status.TiKV.Phase == v1alpha1.UpgradePhase || tc.TiKVGroupUpgrading() || tc.TiFlashUpgrading() {
 		_, podSpec, err := GetLastAppliedConfig(oldSet)
 		if err != nil {
 			return err
@@ -52,7 +58,7 @@ func (tdu *tidbUpgrader) Upgrade(tc *v1alpha1.TidbCluster, oldSet *apps.Stateful
 		return nil
 	}
 
-	if tc.Status.TiDB.StatefulSet.UpdateRevision == tc.Status.TiDB.StatefulSet.CurrentRevision {
+	if upgradeSettled(tc.Status.TiDB.StatefulSet, oldSet) {
 		return nil
 	}
 
@@ -67,8 +73,10 @@ func (tdu *tidbUpgrader) Upgrade(tc *v1alpha1.TidbCluster, oldSet *apps.Stateful
 	}
 
 	setUpgradePartition(newSet, *oldSet.Spec.UpdateStrategy.RollingUpdate.Partition)
+	progress := getUpgradeProgress(&tc.Status.TiDB.Upgrade, tc.Status.TiDB.StatefulSet.UpdateRevision)
+	var upgraded int32
 	for i := tc.TiDBStsActualReplicas() - 1; i >= 0; i-- {
-		podName := tidbPodName(tcName, i)
+		podName := tidbSetPodName(tc, i)
 		pod, err := tdu.podLister.Pods(ns).Get(podName)
 		if err != nil {
 			return err
@@ -80,21 +88,75 @@ func (tdu *tidbUpgrader) Upgrade(tc *v1alpha1.TidbCluster, oldSet *apps.Stateful
 
 		if revision == tc.Status.TiDB.StatefulSet.UpdateRevision {
 			if member, exist := tc.Status.TiDB.Members[podName]; !exist || !member.Health {
+				if upgradedPodUnhealthy(tc.Spec.TiDB.UpgradeStrategy, progress) {
+					return rollbackUpgrade(tc, v1alpha1.TiDBMemberType, tdu.recorder, oldSet, newSet,
+						&tc.Status.TiDB.Upgrade, tc.Status.TiDB.StatefulSet.CurrentRevision)
+				}
 				return controller.RequeueErrorf("tidbcluster: [%s/%s]'s tidb upgraded pod: [%s] is not ready", ns, tcName, podName)
 			}
+			upgraded++
 			continue
 		}
-		return tdu.upgradeTiDBPod(tc, i, newSet)
+
+		if err := checkUpgradeGate(tc, v1alpha1.TiDBMemberType, tc.Spec.TiDB.UpgradeStrategy, progress, tc.TiDBStsActualReplicas(), upgraded); err != nil {
+			return err
+		}
+		return tdu.upgradeTiDBPod(tc, i, pod, newSet)
 	}
 
 	return nil
 }
 
-func (tdu *tidbUpgrader) upgradeTiDBPod(tc *v1alpha1.TidbCluster, ordinal int32, newSet *apps.StatefulSet) error {
+func (tdu *tidbUpgrader) upgradeTiDBPod(tc *v1alpha1.TidbCluster, ordinal int32, pod *corev1.Pod, newSet *apps.StatefulSet) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	podName := pod.GetName()
+
+	if tc.Spec.TiDB.Drain == nil || !HasTiDBServingGate(pod) {
+		setUpgradePartition(newSet, ordinal)
+		return nil
+	}
+
+	if !IsTiDBDraining(pod) || IsTiDBServing(pod) {
+		return tdu.beginDrain(tc, pod)
+	}
+
+	drained, err := TiDBDrained(tc, tdu.tidbControl, pod, ordinal)
+	if err != nil {
+		return err
+	}
+	if !drained {
+		return controller.RequeueErrorf("tidbcluster: [%s/%s]'s tidb pod: [%s] is draining connections", ns, tcName, podName)
+	}
 	setUpgradePartition(newSet, ordinal)
 	return nil
 }
 
+// beginDrain removes pod from the service endpoints, so that it stops receiving new connections
+func (tdu *tidbUpgrader) beginDrain(tc *v1alpha1.TidbCluster, pod *corev1.Pod) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	podName := pod.GetName()
+
+	pod = pod.DeepCopy()
+	if !IsTiDBDraining(pod) {
+		BeginTiDBDrain(pod, TiDBDrainByUpgrader)
+		updatedPod, err := tdu.podControl.UpdatePod(tc, pod)
+		if err != nil {
+			glog.Errorf("tidb upgrader: failed to mark tidb pod %s/%s as draining, %v", ns, podName, err)
+			return err
+		}
+		pod = updatedPod.DeepCopy()
+	}
+	if SetTiDBServingCondition(pod, false) {
+		if _, err := tdu.podControl.UpdatePodStatus(tc, pod); err != nil {
+			glog.Errorf("tidb upgrader: failed to remove tidb pod %s/%s from service endpoints, %v", ns, podName, err)
+			return err
+		}
+	}
+	return controller.RequeueErrorf("tidbcluster: [%s/%s]'s tidb pod: [%s] begins draining connections", ns, tcName, podName)
+}
+
 type fakeTiDBUpgrader struct{}
 
 // NewFakeTiDBUpgrader returns a fake tidb upgrader
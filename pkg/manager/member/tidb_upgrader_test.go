@@ -15,6 +15,7 @@ package member
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
@@ -27,6 +28,7 @@ import (
 	kubeinformers "k8s.io/client-go/informers"
 	podinformers "k8s.io/client-go/informers/core/v1"
 	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
 )
 
 func TestTiDBUpgrader_Upgrade(t *testing.T) {
@@ -192,11 +194,105 @@ func TestTiDBUpgrader_Upgrade(t *testing.T) {
 
 }
 
+func TestTiDBUpgrader_Drain(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name            string
+		drainBeginTime  *time.Time
+		connections     int
+		errorExpect     bool
+		expectPartition int32
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+		upgrader, tidbControl, podInformer := newTiDBUpgrader()
+		tc := newTidbClusterForTiDBUpgrader()
+		tc.Status.PD.Phase = v1alpha1.NormalPhase
+		tc.Status.TiKV.Phase = v1alpha1.NormalPhase
+		tc.Spec.TiDB.Drain = &v1alpha1.TiDBDrainSpec{
+			MaxConnections: 1,
+			TimeoutSeconds: controller.Int32Ptr(60),
+		}
+		tidbControl.SetConnections(test.connections)
+
+		pods := getTiDBPods()
+		for _, pod := range pods {
+			pod.Spec.ReadinessGates = []corev1.PodReadinessGate{{ConditionType: TiDBServingCondition}}
+			SetTiDBServingCondition(pod, true)
+		}
+		if test.drainBeginTime != nil {
+			pods[0].Annotations = map[string]string{label.AnnTiDBDrainBeginTime: test.drainBeginTime.Format(time.RFC3339)}
+			SetTiDBServingCondition(pods[0], false)
+		}
+		for _, pod := range pods {
+			podInformer.Informer().GetIndexer().Add(pod)
+		}
+
+		oldSet := newStatefulSetForTiDBUpgrader()
+		newSet := oldSet.DeepCopy()
+		SetLastAppliedConfigAnnotation(oldSet)
+
+		err := upgrader.Upgrade(tc, oldSet, newSet)
+		if test.errorExpect {
+			g.Expect(err).To(HaveOccurred())
+		} else {
+			g.Expect(err).NotTo(HaveOccurred())
+		}
+		g.Expect(newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(controller.Int32Ptr(test.expectPartition)))
+
+		pod, err := podInformer.Lister().Pods(corev1.NamespaceDefault).Get(tidbPodName(upgradeTcName, 0))
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(IsTiDBDraining(pod)).To(BeTrue())
+		g.Expect(IsTiDBServing(pod)).To(BeFalse())
+	}
+
+	now := time.Now()
+	expired := now.Add(-2 * time.Minute)
+	tests := []*testcase{
+		{
+			name:            "begin draining the pod to upgrade",
+			connections:     10,
+			errorExpect:     true,
+			expectPartition: 1,
+		},
+		{
+			name:            "wait for the connections to drain",
+			drainBeginTime:  &now,
+			connections:     10,
+			errorExpect:     true,
+			expectPartition: 1,
+		},
+		{
+			name:            "upgrade after the connections drop to the threshold",
+			drainBeginTime:  &now,
+			connections:     1,
+			expectPartition: 0,
+		},
+		{
+			name:            "upgrade after the drain timeout",
+			drainBeginTime:  &expired,
+			connections:     10,
+			expectPartition: 0,
+		},
+	}
+
+	for _, test := range tests {
+		testFn(test, t)
+	}
+}
+
 func newTiDBUpgrader() (Upgrader, *controller.FakeTiDBControl, podinformers.PodInformer) {
 	kubeCli := kubefake.NewSimpleClientset()
 	tidbControl := controller.NewFakeTiDBControl()
 	podInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().Pods()
-	return &tidbUpgrader{tidbControl: tidbControl, podLister: podInformer.Lister()}, tidbControl, podInformer
+	return &tidbUpgrader{
+		tidbControl: tidbControl,
+		podControl:  controller.NewFakePodControl(podInformer),
+		podLister:   podInformer.Lister(),
+		recorder:    record.NewFakeRecorder(100),
+	}, tidbControl, podInformer
 }
 
 func newStatefulSetForTiDBUpgrader() *apps.StatefulSet {
@@ -0,0 +1,87 @@
+// Copyright 2018 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	glog "k8s.io/klog"
+)
+
+type tiflashFailover struct {
+	tiflashFailoverPeriod time.Duration
+}
+
+// NewTiFlashFailover returns a tiflash Failover
+func NewTiFlashFailover(tiflashFailoverPeriod time.Duration) Failover {
+	return &tiflashFailover{tiflashFailoverPeriod}
+}
+
+func (tf *tiflashFailover) Failover(tc *v1alpha1.TidbCluster) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	for storeID, store := range tc.Status.TiFlash.Stores {
+		podName := store.PodName
+		if store.LastTransitionTime.IsZero() {
+			continue
+		}
+		deadline := store.LastTransitionTime.Add(tf.tiflashFailoverPeriod)
+		exist := false
+		for _, failureStore := range tc.Status.TiFlash.FailureStores {
+			if failureStore.PodName == podName {
+				exist = true
+				break
+			}
+		}
+		if store.State == v1alpha1.TiKVStateDown && time.Now().After(deadline) && !exist {
+			if tc.Status.TiFlash.FailureStores == nil {
+				tc.Status.TiFlash.FailureStores = map[string]v1alpha1.TiKVFailureStore{}
+			}
+			if tc.Spec.TiFlash.MaxFailoverCount > 0 && len(tc.Status.TiFlash.FailureStores) >= int(tc.Spec.TiFlash.MaxFailoverCount) {
+				glog.Warningf("%s/%s failure stores count reached the limit: %d", ns, tcName, tc.Spec.TiFlash.MaxFailoverCount)
+				return nil
+			}
+
+			tc.Status.TiFlash.FailureStores[storeID] = v1alpha1.TiKVFailureStore{
+				PodName:   podName,
+				StoreID:   store.ID,
+				CreatedAt: metav1.Now(),
+			}
+		}
+	}
+
+	return nil
+}
+
+func (tf *tiflashFailover) Recover(_ *v1alpha1.TidbCluster) {
+	// Do nothing now
+}
+
+type fakeTiFlashFailover struct{}
+
+// NewFakeTiFlashFailover returns a fake Failover
+func NewFakeTiFlashFailover() Failover {
+	return &fakeTiFlashFailover{}
+}
+
+func (ftf *fakeTiFlashFailover) Failover(_ *v1alpha1.TidbCluster) error {
+	return nil
+}
+
+func (ftf *fakeTiFlashFailover) Recover(_ *v1alpha1.TidbCluster) {
+	return
+}
@@ -0,0 +1,763 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"path"
+
+	"github.com/BurntSushi/toml"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	"github.com/pingcap/tidb-operator/pkg/manager"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	"github.com/pingcap/tidb-operator/pkg/util"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	v1 "k8s.io/client-go/listers/apps/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	glog "k8s.io/klog"
+)
+
+const (
+	// tiflashEngineLabelKey and tiflashEngineLabelVal are the PD store label of the TiFlash stores,
+	// PD places only the learner replicas of the placement rules on the stores with this label
+	tiflashEngineLabelKey = "engine"
+	tiflashEngineLabelVal = "tiflash"
+	// tiflashPodNamePlaceholder is replaced with the pod name in the config files by the start script
+	tiflashPodNamePlaceholder = "${POD_NAME}"
+	// tiflashTLSMountPath is the path where the TLS certificate of tiflash is mounted
+	tiflashTLSMountPath = "/var/lib/tiflash-tls"
+
+	tiflashFlashPort   = 3930
+	tiflashProxyPort   = 20170
+	tiflashTCPPort     = 9000
+	tiflashHTTPPort    = 8123
+	tiflashMetricsPort = 8234
+)
+
+// tiflashStartScript renders the config files of the pod from the mounted ConfigMap and starts tiflash
+// Note: changing this will cause a rolling-update of tiflash cluster
+const tiflashStartScript = `set -euo pipefail
+
+for f in tiflash.toml proxy.toml; do
+    sed "s/\${POD_NAME}/${POD_NAME}/g" /etc/tiflash/$f > /data0/$f
+done
+
+exec /tiflash/tiflash server --config-file /data0/tiflash.toml`
+
+// tiflashMemberManager implements manager.Manager.
+type tiflashMemberManager struct {
+	setControl                      controller.StatefulSetControlInterface
+	svcControl                      controller.ServiceControlInterface
+	cmControl                       controller.ConfigMapControlInterface
+	pdControl                       pdapi.PDControlInterface
+	certControl                     controller.CertControlInterface
+	setLister                       v1.StatefulSetLister
+	svcLister                       corelisters.ServiceLister
+	cmLister                        corelisters.ConfigMapLister
+	podLister                       corelisters.PodLister
+	nodeLister                      corelisters.NodeLister
+	autoFailover                    bool
+	tiflashFailover                 Failover
+	tiflashScaler                   Scaler
+	tiflashUpgrader                 Upgrader
+	tiflashStatefulSetIsUpgradingFn func(corelisters.PodLister, *apps.StatefulSet, *v1alpha1.TidbCluster) (bool, error)
+}
+
+// NewTiFlashMemberManager returns a *tiflashMemberManager
+func NewTiFlashMemberManager(pdControl pdapi.PDControlInterface,
+	setControl controller.StatefulSetControlInterface,
+	svcControl controller.ServiceControlInterface,
+	cmControl controller.ConfigMapControlInterface,
+	certControl controller.CertControlInterface,
+	setLister v1.StatefulSetLister,
+	svcLister corelisters.ServiceLister,
+	cmLister corelisters.ConfigMapLister,
+	podLister corelisters.PodLister,
+	nodeLister corelisters.NodeLister,
+	autoFailover bool,
+	tiflashFailover Failover,
+	tiflashScaler Scaler,
+	tiflashUpgrader Upgrader) manager.Manager {
+	tfmm := tiflashMemberManager{
+		setControl:      setControl,
+		svcControl:      svcControl,
+		cmControl:       cmControl,
+		pdControl:       pdControl,
+		certControl:     certControl,
+		setLister:       setLister,
+		svcLister:       svcLister,
+		cmLister:        cmLister,
+		podLister:       podLister,
+		nodeLister:      nodeLister,
+		autoFailover:    autoFailover,
+		tiflashFailover: tiflashFailover,
+		tiflashScaler:   tiflashScaler,
+		tiflashUpgrader: tiflashUpgrader,
+	}
+	tfmm.tiflashStatefulSetIsUpgradingFn = tiflashStatefulSetIsUpgrading
+	return &tfmm
+}
+
+// Sync fulfills the manager.Manager interface
+func (tfmm *tiflashMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
+	if tc.Spec.TiFlash == nil {
+		return nil
+	}
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	if !tc.PDIsAvailable() {
+		return controller.RequeueErrorf("TidbCluster: [%s/%s], waiting for PD cluster running", ns, tcName)
+	}
+
+	if err := tfmm.syncHeadlessService(tc); err != nil {
+		return err
+	}
+	return tfmm.syncStatefulSet(tc)
+}
+
+func (tfmm *tiflashMemberManager) syncHeadlessService(tc *v1alpha1.TidbCluster) error {
+	newSvc := getNewServiceForTidbCluster(tc, SvcConfig{
+		Name:       "flash",
+		Port:       tiflashFlashPort,
+		Headless:   true,
+		SvcLabel:   func(l label.Label) label.Label { return l.TiFlash() },
+		MemberName: controller.TiFlashPeerMemberName,
+	})
+	oldSvc, err := tfmm.svcLister.Services(newSvc.Namespace).Get(newSvc.Name)
+	if errors.IsNotFound(err) {
+		err = SetServiceLastAppliedConfigAnnotation(newSvc)
+		if err != nil {
+			return err
+		}
+		return tfmm.svcControl.CreateService(tc, newSvc)
+	}
+	if err != nil {
+		return err
+	}
+
+	equal, err := serviceEqual(newSvc, oldSvc)
+	if err != nil {
+		return err
+	}
+	if !equal {
+		svc := *oldSvc.DeepCopy()
+		svc.Spec = newSvc.Spec
+		err = SetServiceLastAppliedConfigAnnotation(&svc)
+		if err != nil {
+			return err
+		}
+		_, err = tfmm.svcControl.UpdateService(tc, &svc)
+		return err
+	}
+	return nil
+}
+
+func (tfmm *tiflashMemberManager) syncStatefulSet(tc *v1alpha1.TidbCluster) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	cm, err := tfmm.syncConfigMap(tc)
+	if err != nil {
+		return err
+	}
+	newSet, err := getNewTiFlashSetForTidbCluster(tc, cm)
+	if err != nil {
+		return err
+	}
+
+	oldSetTmp, err := tfmm.setLister.StatefulSets(ns).Get(controller.TiFlashMemberName(tcName))
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if errors.IsNotFound(err) {
+		err = SetLastAppliedConfigAnnotation(newSet)
+		if err != nil {
+			return err
+		}
+		if tc.Spec.EnableTLSCluster {
+			if err := tfmm.syncTiFlashServerCerts(tc); err != nil {
+				return err
+			}
+		}
+		err = tfmm.setControl.CreateStatefulSet(tc, newSet)
+		if err != nil {
+			return err
+		}
+		tc.Status.TiFlash.StatefulSet = &apps.StatefulSetStatus{}
+		return nil
+	}
+
+	oldSet := oldSetTmp.DeepCopy()
+
+	if err := tfmm.syncTidbClusterStatus(tc, oldSet); err != nil {
+		return err
+	}
+
+	if _, err := tfmm.setStoreLabelsForTiFlash(tc); err != nil {
+		return err
+	}
+
+	if err := keepUpgradeRolledBack(tc, v1alpha1.TiFlashMemberType, newSet, oldSet); err != nil {
+		return err
+	}
+	if err := checkUpgradeVersion(tc, v1alpha1.TiFlashMemberType, tfmm.setLister, tfmm.pdControl, oldSet, newSet); err != nil {
+		return err
+	}
+
+	if !templateEqual(newSet.Spec.Template, oldSet.Spec.Template) || tc.Status.TiFlash.Phase == v1alpha1.UpgradePhase {
+		if err := tfmm.tiflashUpgrader.Upgrade(tc, oldSet, newSet); err != nil {
+			return err
+		}
+	}
+
+	if *newSet.Spec.Replicas > *oldSet.Spec.Replicas {
+		if err := tfmm.tiflashScaler.ScaleOut(tc, oldSet, newSet); err != nil {
+			return err
+		}
+	}
+
+	if *newSet.Spec.Replicas < *oldSet.Spec.Replicas {
+		if err := tfmm.tiflashScaler.ScaleIn(tc, oldSet, newSet); err != nil {
+			return err
+		}
+	}
+
+	if tfmm.autoFailover {
+		if tc.TiFlashAllPodsStarted() && !tc.TiFlashAllStoresReady() {
+			if err := tfmm.tiflashFailover.Failover(tc); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !statefulSetEqual(*newSet, *oldSet) {
+		set := *oldSet
+		set.Spec.Template = newSet.Spec.Template
+		*set.Spec.Replicas = *newSet.Spec.Replicas
+		set.Spec.UpdateStrategy = newSet.Spec.UpdateStrategy
+		setUpgradeRollbackAnnotations(&set, oldSet, newSet)
+		err := SetLastAppliedConfigAnnotation(&set)
+		if err != nil {
+			return err
+		}
+		_, err = tfmm.setControl.UpdateStatefulSet(tc, &set)
+		return err
+	}
+
+	return nil
+}
+
+// syncConfigMap creates the ConfigMap of the rendered tiflash config, the ConfigMap is named after the hash of
+// its content so that a config change rolls the tiflash pods
+func (tfmm *tiflashMemberManager) syncConfigMap(tc *v1alpha1.TidbCluster) (*corev1.ConfigMap, error) {
+	newCm, err := getNewTiFlashConfigMap(tc)
+	if err != nil {
+		return nil, err
+	}
+
+	oldCmTmp, err := tfmm.cmLister.ConfigMaps(newCm.Namespace).Get(newCm.Name)
+	if errors.IsNotFound(err) {
+		// TODO: garbage collection for tiflash configmaps
+		err = tfmm.cmControl.CreateConfigMap(tc, newCm)
+		if err != nil {
+			return nil, err
+		}
+		return newCm, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	oldCm := oldCmTmp.DeepCopy()
+	if !apiequality.Semantic.DeepEqual(oldCm.Data, newCm.Data) {
+		glog.Warningf("hash collision detected on configmap: %s, update configmap content in-place", newCm.Name)
+		oldCm.Data = newCm.Data
+		return tfmm.cmControl.UpdateConfigMap(tc, oldCm)
+	}
+	return oldCm, nil
+}
+
+func (tfmm *tiflashMemberManager) syncTiFlashServerCerts(tc *v1alpha1.TidbCluster) error {
+	certOpts := tiflashServerCertOptions(tc)
+	if tfmm.certControl.CheckSecret(certOpts.Namespace, certOpts.SecretName()) {
+		return nil
+	}
+	return tfmm.certControl.Create(controller.GetOwnerRef(tc), certOpts)
+}
+
+// tiflashServerCertOptions returns the options to create the TiFlash server cert pair
+func tiflashServerCertOptions(tc *v1alpha1.TidbCluster) *controller.TiDBClusterCertOptions {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	svcName := controller.TiFlashMemberName(tcName)
+	peerName := controller.TiFlashPeerMemberName(tcName)
+
+	hostList := []string{
+		peerName,
+		fmt.Sprintf("%s.%s", peerName, ns),
+		fmt.Sprintf("*.%s.%s.svc", peerName, ns),
+	}
+
+	return &controller.TiDBClusterCertOptions{
+		Namespace:    ns,
+		Instance:     tcName,
+		CommonName:   svcName,
+		HostList:     hostList,
+		Component:    "tiflash",
+		Suffix:       "tiflash",
+		Issuer:       tc.Spec.TLSIssuer,
+		CASecretName: tc.TLSCASecretName(),
+	}
+}
+
+// getNewTiFlashConfigMap returns the ConfigMap of the tiflash config and the tiflash proxy config,
+// the placeholder of the pod name in the configs is replaced by the start script
+func getNewTiFlashConfigMap(tc *v1alpha1.TidbCluster) (*corev1.ConfigMap, error) {
+	config, proxyConfig := getTiFlashConfig(tc)
+
+	buff := new(bytes.Buffer)
+	if err := toml.NewEncoder(buff).Encode(config); err != nil {
+		return nil, err
+	}
+	configData := buff.String()
+
+	buff.Reset()
+	if err := toml.NewEncoder(buff).Encode(proxyConfig); err != nil {
+		return nil, err
+	}
+	proxyConfigData := buff.String()
+
+	sum := sha256.Sum256([]byte(configData + proxyConfigData))
+	instanceName := tc.GetLabels()[label.InstanceLabelKey]
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            fmt.Sprintf("%s-%x", controller.TiFlashMemberName(tc.GetName()), sum[0:4]),
+			Namespace:       tc.GetNamespace(),
+			Labels:          label.New().Instance(instanceName).TiFlash().Labels(),
+			OwnerReferences: []metav1.OwnerReference{controller.GetOwnerRef(tc)},
+		},
+		Data: map[string]string{
+			"config-file":  configData,
+			"proxy-config": proxyConfigData,
+		},
+	}, nil
+}
+
+// getTiFlashConfig returns the tiflash config and the tiflash proxy config. The addresses of the pod,
+// the PD cluster and the TiDB status service are filled in unless they are set in spec.tiflash.config
+func getTiFlashConfig(tc *v1alpha1.TidbCluster) (map[string]interface{}, map[string]interface{}) {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	host := fmt.Sprintf("%s.%s.%s.svc", tiflashPodNamePlaceholder, controller.TiFlashPeerMemberName(tcName), ns)
+	if tc.Spec.ClusterDomain != "" {
+		host = fmt.Sprintf("%s.%s", host, tc.Spec.ClusterDomain)
+	}
+	pdAddr := pdapi.PDClientHost(pdapi.Namespace(ns), tcName, "")
+	if ref := tc.ClusterRef(); ref != nil {
+		pdAddr = pdapi.PDClientHost(pdapi.Namespace(ref.Namespace), ref.Name, ref.ClusterDomain)
+	}
+
+	config := map[string]interface{}{
+		"path":      "/data0/db",
+		"tmp_path":  "/data0/tmp",
+		"tcp_port":  tiflashTCPPort,
+		"http_port": tiflashHTTPPort,
+		"flash": map[string]interface{}{
+			"service_addr":     fmt.Sprintf("0.0.0.0:%d", tiflashFlashPort),
+			"tidb_status_addr": fmt.Sprintf("%s.%s.svc:10080", controller.TiDBPeerMemberName(tcName), ns),
+			"proxy": map[string]interface{}{
+				"addr":           fmt.Sprintf("0.0.0.0:%d", tiflashProxyPort),
+				"advertise-addr": fmt.Sprintf("%s:%d", host, tiflashProxyPort),
+				"data-dir":       "/data0/proxy",
+				"config":         "/data0/proxy.toml",
+			},
+		},
+		"raft": map[string]interface{}{
+			"pd_addr": pdAddr,
+		},
+		"status": map[string]interface{}{
+			"metrics_port": tiflashMetricsPort,
+		},
+	}
+	proxyConfig := map[string]interface{}{
+		"server": map[string]interface{}{
+			"engine-addr": fmt.Sprintf("%s:%d", host, tiflashFlashPort),
+		},
+	}
+	if tc.Spec.EnableTLSCluster {
+		config["security"] = map[string]interface{}{
+			"ca_path":   tlsCAPath(tc, tiflashTLSMountPath),
+			"cert_path": path.Join(tiflashTLSMountPath, controller.TLSSecretCertKey),
+			"key_path":  path.Join(tiflashTLSMountPath, controller.TLSSecretKeyKey),
+		}
+		proxyConfig["security"] = map[string]interface{}{
+			"ca-path":   tlsCAPath(tc, tiflashTLSMountPath),
+			"cert-path": path.Join(tiflashTLSMountPath, controller.TLSSecretCertKey),
+			"key-path":  path.Join(tiflashTLSMountPath, controller.TLSSecretKeyKey),
+		}
+	}
+	mergeConfig(config, tc.Spec.TiFlash.Config)
+	return config, proxyConfig
+}
+
+// mergeConfig merges the config src into dst, the tables are merged recursively and the other values of src win
+func mergeConfig(dst, src map[string]interface{}) {
+	for k, v := range src {
+		srcTable, ok := v.(map[string]interface{})
+		dstTable, dstOk := dst[k].(map[string]interface{})
+		if ok && dstOk {
+			mergeConfig(dstTable, srcTable)
+			continue
+		}
+		dst[k] = v
+	}
+}
+
+func getNewTiFlashSetForTidbCluster(tc *v1alpha1.TidbCluster, cm *corev1.ConfigMap) (*apps.StatefulSet, error) {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	spec, _ := tc.BaseTiFlashSpec()
+
+	volMounts := []corev1.VolumeMount{
+		{Name: v1alpha1.TiFlashMemberType.String(), MountPath: "/data0"},
+		{Name: "config", ReadOnly: true, MountPath: "/etc/tiflash"},
+	}
+	vols := []corev1.Volume{
+		{Name: "config", VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: cm.Name,
+				},
+				Items: []corev1.KeyToPath{
+					{Key: "config-file", Path: "tiflash.toml"},
+					{Key: "proxy-config", Path: "proxy.toml"},
+				},
+			}},
+		},
+	}
+	if tc.Spec.EnableTLSCluster {
+		volMounts = append(volMounts, corev1.VolumeMount{
+			Name: "tiflash-tls", ReadOnly: true, MountPath: tiflashTLSMountPath,
+		})
+		vols = append(vols, tlsSecretVolume(tc, "tiflash-tls", controller.TiFlashMemberName(tcName)))
+	}
+
+	var q resource.Quantity
+	var err error
+	if tc.Spec.TiFlash.Requests != nil {
+		size := tc.Spec.TiFlash.Requests.Storage
+		q, err = resource.ParseQuantity(size)
+		if err != nil {
+			return nil, fmt.Errorf("cant' get storage size: %s for TidbCluster: %s/%s, %v", size, ns, tcName, err)
+		}
+	}
+	storageClassName := tc.Spec.TiFlash.StorageClassName
+	if storageClassName == "" {
+		storageClassName = controller.DefaultStorageClassName
+	}
+
+	tiflashLabel := labelTiFlash(tc)
+	podAnnotations := CombineAnnotations(controller.AnnProm(tiflashMetricsPort), spec.Annotations())
+	podAnnotations = CombineAnnotations(podAnnotations, tlsCertRenewAnnotations(tc, controller.TiFlashMemberName(tcName)))
+
+	env := []corev1.EnvVar{
+		{
+			Name: "POD_NAME",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: "metadata.name",
+				},
+			},
+		},
+		{
+			Name:  "TZ",
+			Value: tc.Spec.Timezone,
+		},
+	}
+
+	tiflashSet := &apps.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            controller.TiFlashMemberName(tcName),
+			Namespace:       ns,
+			Labels:          tiflashLabel.Labels(),
+			OwnerReferences: []metav1.OwnerReference{controller.GetOwnerRef(tc)},
+		},
+		Spec: apps.StatefulSetSpec{
+			Replicas: controller.Int32Ptr(tc.TiFlashStsDesiredReplicas()),
+			Selector: tiflashLabel.LabelSelector(),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      tiflashLabel.Labels(),
+					Annotations: podAnnotations,
+				},
+				Spec: corev1.PodSpec{
+					SchedulerName: spec.SchedulerName(),
+					Affinity:      spec.Affinity(),
+					NodeSelector:  spec.NodeSelector(),
+					HostNetwork:   spec.HostNetwork(),
+					DNSPolicy:     spec.DnsPolicy(),
+					Containers: []corev1.Container{
+						{
+							Name:            v1alpha1.TiFlashMemberType.String(),
+							Image:           spec.Image(),
+							Command:         []string{"/bin/sh", "-c", tiflashStartScript},
+							ImagePullPolicy: spec.ImagePullPolicy(),
+							SecurityContext: &corev1.SecurityContext{
+								Privileged: &tc.Spec.TiFlash.Privileged,
+							},
+							Ports: []corev1.ContainerPort{
+								{Name: "tcp", ContainerPort: tiflashTCPPort, Protocol: corev1.ProtocolTCP},
+								{Name: "http", ContainerPort: tiflashHTTPPort, Protocol: corev1.ProtocolTCP},
+								{Name: "flash", ContainerPort: tiflashFlashPort, Protocol: corev1.ProtocolTCP},
+								{Name: "proxy", ContainerPort: tiflashProxyPort, Protocol: corev1.ProtocolTCP},
+								{Name: "metrics", ContainerPort: tiflashMetricsPort, Protocol: corev1.ProtocolTCP},
+							},
+							VolumeMounts: volMounts,
+							Resources:    util.ResourceRequirement(tc.Spec.TiFlash.Resources),
+							Env:          env,
+						},
+					},
+					RestartPolicy:     corev1.RestartPolicyAlways,
+					Tolerations:       spec.Tolerations(),
+					Volumes:           vols,
+					SecurityContext:   spec.PodSecurityContext(),
+					PriorityClassName: spec.PriorityClassName(),
+				},
+			},
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+				volumeClaimTemplate(q, v1alpha1.TiFlashMemberType.String(), &storageClassName),
+			},
+			ServiceName:         controller.TiFlashPeerMemberName(tcName),
+			PodManagementPolicy: apps.ParallelPodManagement,
+			UpdateStrategy: apps.StatefulSetUpdateStrategy{
+				Type: apps.RollingUpdateStatefulSetStrategyType,
+				RollingUpdate: &apps.RollingUpdateStatefulSetStrategy{
+					Partition: controller.Int32Ptr(tc.TiFlashStsDesiredReplicas()),
+				},
+			},
+		},
+	}
+	return tiflashSet, nil
+}
+
+func labelTiFlash(tc *v1alpha1.TidbCluster) label.Label {
+	instanceName := tc.GetLabels()[label.InstanceLabelKey]
+	return label.New().Instance(instanceName).TiFlash()
+}
+
+// isTiFlashStore returns whether the store is a TiFlash store, either of tc or of another cluster joining the same PD cluster
+func isTiFlashStore(tc *v1alpha1.TidbCluster, store *pdapi.StoreInfo) bool {
+	if store.Store == nil {
+		return false
+	}
+	for _, l := range store.Store.Labels {
+		if l.GetKey() == tiflashEngineLabelKey && l.GetValue() == tiflashEngineLabelVal {
+			return true
+		}
+	}
+	return isMemberAddressOf(store.Store.GetAddress(), controller.TiFlashPeerMemberName(tc.GetName()), tc.GetNamespace())
+}
+
+func (tfmm *tiflashMemberManager) syncTidbClusterStatus(tc *v1alpha1.TidbCluster, set *apps.StatefulSet) error {
+	tc.Status.TiFlash.StatefulSet = &set.Status
+	upgrading, err := tfmm.tiflashStatefulSetIsUpgradingFn(tfmm.podLister, set, tc)
+	if err != nil {
+		return err
+	}
+	if upgrading && tc.Status.PD.Phase != v1alpha1.UpgradePhase && !tc.TiKVUpgrading() && !tc.TiKVGroupUpgrading() {
+		tc.Status.TiFlash.Phase = v1alpha1.UpgradePhase
+	} else {
+		tc.Status.TiFlash.Phase = v1alpha1.NormalPhase
+		tc.Status.TiFlash.Upgrade = nil
+	}
+
+	previousStores := tc.Status.TiFlash.Stores
+	stores := map[string]v1alpha1.TiKVStore{}
+	tombstoneStores := map[string]v1alpha1.TiKVStore{}
+	peerServiceName := controller.TiFlashPeerMemberName(tc.GetName())
+
+	pdCli := controller.GetPDClient(tfmm.pdControl, tc)
+	// This only returns Up/Down/Offline stores
+	storesInfo, err := pdCli.GetStores()
+	if err != nil {
+		tc.Status.TiFlash.Synced = false
+		return err
+	}
+
+	for _, store := range storesInfo.Stores {
+		status := getTiKVStore(store)
+		if status == nil || !isMemberAddressOf(store.Store.GetAddress(), peerServiceName, tc.GetNamespace()) {
+			continue
+		}
+		// avoid LastHeartbeatTime be overwrite by zero time when pd lost LastHeartbeatTime
+		oldStore, exist := previousStores[status.ID]
+		if status.LastHeartbeatTime.IsZero() && exist {
+			status.LastHeartbeatTime = oldStore.LastHeartbeatTime
+		}
+
+		status.LastTransitionTime = metav1.Now()
+		if exist && status.State == oldStore.State {
+			status.LastTransitionTime = oldStore.LastTransitionTime
+		}
+
+		// the node of the store is kept for the stable scheduling of tidb-scheduler
+		if exist {
+			status.NodeName = oldStore.NodeName
+		}
+		pod, err := tfmm.podLister.Pods(tc.GetNamespace()).Get(status.PodName)
+		if err != nil && !errors.IsNotFound(err) {
+			tc.Status.TiFlash.Synced = false
+			return err
+		}
+		if pod != nil && pod.Spec.NodeName != "" {
+			status.NodeName = pod.Spec.NodeName
+		}
+
+		stores[status.ID] = *status
+	}
+
+	//this returns all tombstone stores
+	tombstoneStoresInfo, err := pdCli.GetTombStoneStores()
+	if err != nil {
+		tc.Status.TiFlash.Synced = false
+		return err
+	}
+	for _, store := range tombstoneStoresInfo.Stores {
+		status := getTiKVStore(store)
+		if status == nil || !isMemberAddressOf(store.Store.GetAddress(), peerServiceName, tc.GetNamespace()) {
+			continue
+		}
+		tombstoneStores[status.ID] = *status
+	}
+
+	tc.Status.TiFlash.Synced = true
+	tc.Status.TiFlash.Stores = stores
+	tc.Status.TiFlash.TombstoneStores = tombstoneStores
+	return nil
+}
+
+// setStoreLabelsForTiFlash sets the engine label, the location labels of the nodes and spec.tiflash.storeLabels
+// to the TiFlash stores of tc
+func (tfmm *tiflashMemberManager) setStoreLabelsForTiFlash(tc *v1alpha1.TidbCluster) (int, error) {
+	ns := tc.GetNamespace()
+	// for unit test
+	setCount := 0
+
+	pdCli := controller.GetPDClient(tfmm.pdControl, tc)
+	storesInfo, err := pdCli.GetStores()
+	if err != nil {
+		return setCount, err
+	}
+
+	config, err := pdCli.GetConfig()
+	if err != nil {
+		return setCount, err
+	}
+	locationLabels := storeLocationLabels(tc, config)
+
+	peerServiceName := controller.TiFlashPeerMemberName(tc.GetName())
+	for _, store := range storesInfo.Stores {
+		status := getTiKVStore(store)
+		if status == nil || !isMemberAddressOf(store.Store.GetAddress(), peerServiceName, ns) {
+			continue
+		}
+		podName := status.PodName
+
+		pod, err := tfmm.podLister.Pods(ns).Get(podName)
+		if err != nil {
+			return setCount, err
+		}
+
+		ls := map[string]string{}
+		if len(locationLabels) > 0 {
+			nodeLabels, err := getNodeLabels(tfmm.nodeLister, pod.Spec.NodeName, locationLabels)
+			if err != nil {
+				glog.Warningf("failed to get the labels of node: [%s] for Pod: [%s/%s], %v", pod.Spec.NodeName, ns, podName, err)
+			}
+			for k, v := range nodeLabels {
+				ls[k] = v
+			}
+		}
+		for k, v := range tc.Spec.TiFlash.StoreLabels {
+			ls[k] = v
+		}
+		ls[tiflashEngineLabelKey] = tiflashEngineLabelVal
+
+		if !storeLabelsEqualNodeLabels(store.Store.Labels, ls) {
+			set, err := pdCli.SetStoreLabels(store.Store.Id, ls)
+			if err != nil {
+				glog.Warningf("failed to set pod: [%s/%s]'s store labels: %v", ns, podName, ls)
+				continue
+			}
+			if set {
+				setCount++
+				glog.Infof("pod: [%s/%s] set labels: %v successfully", ns, podName, ls)
+			}
+		}
+	}
+
+	return setCount, nil
+}
+
+func tiflashStatefulSetIsUpgrading(podLister corelisters.PodLister, set *apps.StatefulSet, tc *v1alpha1.TidbCluster) (bool, error) {
+	if statefulSetIsUpgrading(set) {
+		return true, nil
+	}
+	selector, err := labelTiFlash(tc).Selector()
+	if err != nil {
+		return false, err
+	}
+	tiflashPods, err := podLister.Pods(tc.GetNamespace()).List(selector)
+	if err != nil {
+		return false, err
+	}
+	for _, pod := range tiflashPods {
+		revisionHash, exist := pod.Labels[apps.ControllerRevisionHashLabelKey]
+		if !exist {
+			return false, nil
+		}
+		if revisionHash != tc.Status.TiFlash.StatefulSet.UpdateRevision {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+type FakeTiFlashMemberManager struct {
+	err error
+}
+
+func NewFakeTiFlashMemberManager() *FakeTiFlashMemberManager {
+	return &FakeTiFlashMemberManager{}
+}
+
+func (ftfmm *FakeTiFlashMemberManager) SetSyncError(err error) {
+	ftfmm.err = err
+}
+
+func (ftfmm *FakeTiFlashMemberManager) Sync(_ *v1alpha1.TidbCluster) error {
+	return ftfmm.err
+}
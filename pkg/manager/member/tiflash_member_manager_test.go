@@ -0,0 +1,434 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/pd/pkg/typeutil"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned/fake"
+	informers "github.com/pingcap/tidb-operator/pkg/client/informers/externalversions"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	"github.com/pingcap/tidb-operator/pkg/util/config"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	kubeinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestTiFlashMemberManagerSyncCreate(t *testing.T) {
+	g := NewGomegaWithT(t)
+	type testcase struct {
+		name                     string
+		prepare                  func(cluster *v1alpha1.TidbCluster)
+		errWhenCreateStatefulSet bool
+		errWhenCreatePeerService bool
+		err                      bool
+		peerSvcCreated           bool
+		setCreated               bool
+		cmCreated                bool
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+
+		tc := newTidbClusterForTiFlash()
+		ns := tc.Namespace
+		tcName := tc.Name
+		if test.prepare != nil {
+			test.prepare(tc)
+		}
+
+		tfmm, fakeSetControl, fakeSvcControl, _, _, _ := newFakeTiFlashMemberManager(tc)
+		if test.errWhenCreateStatefulSet {
+			fakeSetControl.SetCreateStatefulSetError(errors.NewInternalError(fmt.Errorf("API server failed")), 0)
+		}
+		if test.errWhenCreatePeerService {
+			fakeSvcControl.SetCreateServiceError(errors.NewInternalError(fmt.Errorf("API server failed")), 0)
+		}
+
+		err := tfmm.Sync(tc)
+		if test.err {
+			g.Expect(err).To(HaveOccurred())
+		} else {
+			g.Expect(err).NotTo(HaveOccurred())
+		}
+
+		_, err = tfmm.svcLister.Services(ns).Get(controller.TiFlashPeerMemberName(tcName))
+		if test.peerSvcCreated {
+			g.Expect(err).NotTo(HaveOccurred())
+		} else {
+			expectErrIsNotFound(g, err)
+		}
+
+		set, err := tfmm.setLister.StatefulSets(ns).Get(controller.TiFlashMemberName(tcName))
+		if test.setCreated {
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(*set.Spec.Replicas).To(Equal(int32(2)))
+			g.Expect(set.Spec.ServiceName).To(Equal(controller.TiFlashPeerMemberName(tcName)))
+		} else {
+			expectErrIsNotFound(g, err)
+		}
+
+		cms, err := tfmm.cmLister.ConfigMaps(ns).List(labels.Everything())
+		g.Expect(err).NotTo(HaveOccurred())
+		if test.cmCreated {
+			g.Expect(cms).To(HaveLen(1))
+		} else {
+			g.Expect(cms).To(BeEmpty())
+		}
+	}
+
+	tests := []testcase{
+		{
+			name:           "normal",
+			peerSvcCreated: true,
+			setCreated:     true,
+			cmCreated:      true,
+		},
+		{
+			name: "tiflash is not deployed",
+			prepare: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.TiFlash = nil
+			},
+		},
+		{
+			name: "pd is not available",
+			prepare: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.PD.Members = map[string]v1alpha1.PDMember{}
+			},
+			err: true,
+		},
+		{
+			name: "tidbcluster's storage format is wrong",
+			prepare: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.TiFlash.Requests.Storage = "100xxxxi"
+			},
+			err:            true,
+			peerSvcCreated: true,
+			cmCreated:      true,
+		},
+		{
+			name:                     "error when create statefulset",
+			errWhenCreateStatefulSet: true,
+			err:                      true,
+			peerSvcCreated:           true,
+			cmCreated:                true,
+		},
+		{
+			name:                     "error when create tiflash peer service",
+			errWhenCreatePeerService: true,
+			err:                      true,
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
+
+func TestTiFlashMemberManagerSyncTidbClusterStatus(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForTiFlash()
+	tc.Status.TiFlash.Stores = map[string]v1alpha1.TiKVStore{
+		"2": {ID: "2", State: v1alpha1.TiKVStateUp, LastHeartbeatTime: metav1.Now()},
+	}
+	tfmm, _, _, pdClient, podIndexer, _ := newFakeTiFlashMemberManager(tc)
+	podIndexer.Add(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-tiflash-0", Namespace: corev1.NamespaceDefault},
+		Spec:       corev1.PodSpec{NodeName: "node-1"},
+	})
+	pdClient.AddReaction(pdapi.GetStoresActionType, func(action *pdapi.Action) (interface{}, error) {
+		return &pdapi.StoresInfo{
+			Stores: []*pdapi.StoreInfo{
+				newStoreInfo(1, "test-tikv-0.test-tikv-peer.default.svc:20160", "Up", nil),
+				newStoreInfo(2, "test-tiflash-0.test-tiflash-peer.default.svc:20170", "Up",
+					[]*metapb.StoreLabel{{Key: tiflashEngineLabelKey, Value: tiflashEngineLabelVal}}),
+			},
+		}, nil
+	})
+	pdClient.AddReaction(pdapi.GetTombStoneStoresActionType, func(action *pdapi.Action) (interface{}, error) {
+		return &pdapi.StoresInfo{
+			Stores: []*pdapi.StoreInfo{
+				newStoreInfo(3, "test-tiflash-1.test-tiflash-peer.default.svc:20170", "Tombstone", nil),
+			},
+		}, nil
+	})
+
+	err := tfmm.syncTidbClusterStatus(tc, &apps.StatefulSet{Status: apps.StatefulSetStatus{Replicas: 2}})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tc.Status.TiFlash.Synced).To(BeTrue())
+	g.Expect(tc.Status.TiFlash.Phase).To(Equal(v1alpha1.NormalPhase))
+	g.Expect(tc.Status.TiFlash.StatefulSet.Replicas).To(Equal(int32(2)))
+	g.Expect(tc.Status.TiFlash.Stores).To(HaveLen(1))
+	g.Expect(tc.Status.TiFlash.Stores["2"].PodName).To(Equal("test-tiflash-0"))
+	g.Expect(tc.Status.TiFlash.Stores["2"].NodeName).To(Equal("node-1"))
+	g.Expect(tc.Status.TiFlash.TombstoneStores).To(HaveLen(1))
+	g.Expect(tc.Status.TiFlash.TombstoneStores).To(HaveKey("3"))
+}
+
+func TestTiFlashMemberManagerSetStoreLabelsForTiFlash(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForTiFlash()
+	tc.Spec.TiFlash.StoreLabels = map[string]string{"disk": "nvme"}
+	tfmm, _, _, pdClient, podIndexer, nodeIndexer := newFakeTiFlashMemberManager(tc)
+	pdClient.AddReaction(pdapi.GetConfigActionType, func(action *pdapi.Action) (interface{}, error) {
+		return &pdapi.Config{
+			Replication: pdapi.ReplicationConfig{
+				LocationLabels: typeutil.StringSlice{"zone"},
+			},
+		}, nil
+	})
+	pdClient.AddReaction(pdapi.GetStoresActionType, func(action *pdapi.Action) (interface{}, error) {
+		return &pdapi.StoresInfo{
+			Stores: []*pdapi.StoreInfo{
+				newStoreInfo(1, "test-tikv-0.test-tikv-peer.default.svc:20160", "Up", nil),
+				newStoreInfo(2, "test-tiflash-0.test-tiflash-peer.default.svc:20170", "Up", nil),
+				newStoreInfo(3, "test-tiflash-1.test-tiflash-peer.default.svc:20170", "Up", []*metapb.StoreLabel{
+					{Key: "zone", Value: "zone-1"},
+					{Key: "disk", Value: "nvme"},
+					{Key: tiflashEngineLabelKey, Value: tiflashEngineLabelVal},
+				}),
+			},
+		}, nil
+	})
+	var setLabels map[string]string
+	pdClient.AddReaction(pdapi.SetStoreLabelsActionType, func(action *pdapi.Action) (interface{}, error) {
+		g.Expect(action.ID).To(Equal(uint64(2)))
+		setLabels = action.Labels
+		return true, nil
+	})
+	for i := 0; i < 2; i++ {
+		podIndexer.Add(&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("test-tiflash-%d", i), Namespace: corev1.NamespaceDefault},
+			Spec:       corev1.PodSpec{NodeName: "node-1"},
+		})
+	}
+	nodeIndexer.Add(&corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"zone": "zone-1"}},
+	})
+
+	setCount, err := tfmm.setStoreLabelsForTiFlash(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(setCount).To(Equal(1))
+	g.Expect(setLabels).To(Equal(map[string]string{
+		"zone":                "zone-1",
+		"disk":                "nvme",
+		tiflashEngineLabelKey: tiflashEngineLabelVal,
+	}))
+}
+
+func TestGetTiFlashConfig(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name     string
+		prepare  func(tc *v1alpha1.TidbCluster)
+		expectFn func(config, proxyConfig map[string]interface{})
+	}
+	tests := []testcase{
+		{
+			name: "default",
+			expectFn: func(config, proxyConfig map[string]interface{}) {
+				g.Expect(config["path"]).To(Equal("/data0/db"))
+				flash := config["flash"].(map[string]interface{})
+				g.Expect(flash["tidb_status_addr"]).To(Equal("test-tidb-peer.default.svc:10080"))
+				g.Expect(flash["proxy"].(map[string]interface{})["advertise-addr"]).To(
+					Equal("${POD_NAME}.test-tiflash-peer.default.svc:20170"))
+				g.Expect(config["raft"].(map[string]interface{})["pd_addr"]).To(Equal("test-pd.default:2379"))
+				g.Expect(config).NotTo(HaveKey("security"))
+				g.Expect(proxyConfig["server"].(map[string]interface{})["engine-addr"]).To(
+					Equal("${POD_NAME}.test-tiflash-peer.default.svc:3930"))
+			},
+		},
+		{
+			name: "user config is merged",
+			prepare: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.TiFlash.GenericConfig = config.New(map[string]interface{}{
+					"path": "/data0/custom",
+					"flash": map[string]interface{}{
+						"tidb_status_addr": "tidb:10080",
+					},
+					"logger": map[string]interface{}{
+						"level": "debug",
+					},
+				})
+			},
+			expectFn: func(config, proxyConfig map[string]interface{}) {
+				g.Expect(config["path"]).To(Equal("/data0/custom"))
+				flash := config["flash"].(map[string]interface{})
+				g.Expect(flash["tidb_status_addr"]).To(Equal("tidb:10080"))
+				g.Expect(flash["service_addr"]).To(Equal("0.0.0.0:3930"))
+				g.Expect(config["logger"]).To(Equal(map[string]interface{}{"level": "debug"}))
+			},
+		},
+		{
+			name: "cluster domain and cluster reference",
+			prepare: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.ClusterDomain = "cluster-1.com"
+				tc.Spec.Cluster = &v1alpha1.TidbClusterRef{Namespace: "ns-2", Name: "main", ClusterDomain: "cluster-2.com"}
+			},
+			expectFn: func(config, proxyConfig map[string]interface{}) {
+				g.Expect(config["raft"].(map[string]interface{})["pd_addr"]).To(Equal("main-pd.ns-2.svc.cluster-2.com:2379"))
+				g.Expect(proxyConfig["server"].(map[string]interface{})["engine-addr"]).To(
+					Equal("${POD_NAME}.test-tiflash-peer.default.svc.cluster-1.com:3930"))
+			},
+		},
+		{
+			name: "tls cluster",
+			prepare: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.EnableTLSCluster = true
+			},
+			expectFn: func(config, proxyConfig map[string]interface{}) {
+				g.Expect(config["security"].(map[string]interface{})["cert_path"]).To(Equal("/var/lib/tiflash-tls/cert"))
+				g.Expect(proxyConfig["security"].(map[string]interface{})["key-path"]).To(Equal("/var/lib/tiflash-tls/key"))
+			},
+		},
+	}
+	for i := range tests {
+		test := tests[i]
+		t.Log(test.name)
+		tc := newTidbClusterForTiFlash()
+		if test.prepare != nil {
+			test.prepare(tc)
+		}
+		config, proxyConfig := getTiFlashConfig(tc)
+		test.expectFn(config, proxyConfig)
+	}
+}
+
+func TestIsTiFlashStore(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForTiFlash()
+	tests := []struct {
+		name   string
+		store  *pdapi.StoreInfo
+		expect bool
+	}{
+		{
+			name:   "tikv store",
+			store:  newStoreInfo(1, "test-tikv-0.test-tikv-peer.default.svc:20160", "Up", nil),
+			expect: false,
+		},
+		{
+			name:   "tiflash store of the cluster",
+			store:  newStoreInfo(2, "test-tiflash-0.test-tiflash-peer.default.svc:20170", "Up", nil),
+			expect: true,
+		},
+		{
+			name: "tiflash store of another cluster",
+			store: newStoreInfo(3, "other-tiflash-0.other-tiflash-peer.ns-2.svc:20170", "Up",
+				[]*metapb.StoreLabel{{Key: tiflashEngineLabelKey, Value: tiflashEngineLabelVal}}),
+			expect: true,
+		},
+		{
+			name:   "store without meta",
+			store:  &pdapi.StoreInfo{},
+			expect: false,
+		},
+	}
+	for _, test := range tests {
+		t.Log(test.name)
+		g.Expect(isTiFlashStore(tc, test.store)).To(Equal(test.expect))
+	}
+}
+
+func newStoreInfo(id uint64, address, state string, labels []*metapb.StoreLabel) *pdapi.StoreInfo {
+	return &pdapi.StoreInfo{
+		Store: &pdapi.MetaStore{
+			Store: &metapb.Store{
+				Id:      id,
+				Address: address,
+				Labels:  labels,
+			},
+			StateName: state,
+		},
+		Status: &pdapi.StoreStatus{
+			LastHeartbeatTS: time.Now(),
+		},
+	}
+}
+
+func newFakeTiFlashMemberManager(tc *v1alpha1.TidbCluster) (
+	*tiflashMemberManager, *controller.FakeStatefulSetControl,
+	*controller.FakeServiceControl, *pdapi.FakePDClient, cache.Indexer, cache.Indexer) {
+	cli := fake.NewSimpleClientset()
+	kubeCli := kubefake.NewSimpleClientset()
+	pdControl := pdapi.NewFakePDControl(kubeCli)
+	pdClient := controller.NewFakePDClient(pdControl, tc)
+	setInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Apps().V1().StatefulSets()
+	svcInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().Services()
+	epsInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().Endpoints()
+	cmInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().ConfigMaps()
+	tcInformer := informers.NewSharedInformerFactory(cli, 0).Pingcap().V1alpha1().TidbClusters()
+	setControl := controller.NewFakeStatefulSetControl(setInformer, tcInformer)
+	svcControl := controller.NewFakeServiceControl(svcInformer, epsInformer, tcInformer)
+	cmControl := controller.NewFakeConfigMapControl(cmInformer)
+	podInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().Pods()
+	nodeInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().Nodes()
+
+	tfmm := &tiflashMemberManager{
+		pdControl:       pdControl,
+		setControl:      setControl,
+		svcControl:      svcControl,
+		cmControl:       cmControl,
+		setLister:       setInformer.Lister(),
+		svcLister:       svcInformer.Lister(),
+		cmLister:        cmInformer.Lister(),
+		podLister:       podInformer.Lister(),
+		nodeLister:      nodeInformer.Lister(),
+		tiflashFailover: NewFakeTiFlashFailover(),
+		tiflashScaler:   NewFakeTiFlashScaler(),
+		tiflashUpgrader: NewFakeTiFlashUpgrader(),
+	}
+	tfmm.tiflashStatefulSetIsUpgradingFn = tiflashStatefulSetIsUpgrading
+	return tfmm, setControl, svcControl, pdClient, podInformer.Informer().GetIndexer(), nodeInformer.Informer().GetIndexer()
+}
+
+func newTidbClusterForTiFlash() *v1alpha1.TidbCluster {
+	tc := newTidbClusterForPD()
+	tc.Labels = map[string]string{label.InstanceLabelKey: tc.Name}
+	tc.Status.PD.Members = map[string]v1alpha1.PDMember{
+		"pd-0": {Name: "pd-0", Health: true},
+		"pd-1": {Name: "pd-1", Health: true},
+		"pd-2": {Name: "pd-2", Health: true},
+	}
+	tc.Status.PD.StatefulSet = &apps.StatefulSetStatus{ReadyReplicas: 3}
+	tc.Spec.TiFlash = &v1alpha1.TiFlashSpec{
+		ComponentSpec: v1alpha1.ComponentSpec{
+			Image: "tiflash-test-image",
+		},
+		Resources: v1alpha1.Resources{
+			Requests: &v1alpha1.ResourceRequirement{
+				Storage: "100Gi",
+			},
+		},
+		Replicas: 2,
+	}
+	return tc
+}
@@ -0,0 +1,435 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestTiFlashScalerScaleOut(t *testing.T) {
+	g := NewGomegaWithT(t)
+	type testcase struct {
+		name             string
+		tiflashUpgrading bool
+		hasPVC           bool
+		hasDeferAnn      bool
+		pvcDeleteErr     bool
+		errExpectFn      func(*GomegaWithT, error)
+		changed          bool
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+		tc := newTidbClusterForPD()
+		tc.Spec.TiFlash = &v1alpha1.TiFlashSpec{Replicas: 6}
+
+		if test.tiflashUpgrading {
+			tc.Status.TiFlash.Phase = v1alpha1.UpgradePhase
+		}
+
+		oldSet := newStatefulSetForPDScale()
+		newSet := oldSet.DeepCopy()
+		newSet.Spec.Replicas = controller.Int32Ptr(7)
+
+		scaler, _, pvcIndexer, _, pvcControl := newFakeTiFlashScaler()
+
+		pvc := newPVCForStatefulSet(oldSet, v1alpha1.TiFlashMemberType)
+		pvc.Name = ordinalPVCName(v1alpha1.TiFlashMemberType, oldSet.GetName(), *oldSet.Spec.Replicas)
+		if test.hasDeferAnn {
+			pvc.Annotations = map[string]string{label.AnnPVCDeferDeleting: time.Now().Format(time.RFC3339)}
+		}
+		if test.hasPVC {
+			pvcIndexer.Add(pvc)
+		}
+
+		if test.pvcDeleteErr {
+			pvcControl.SetDeletePVCError(errors.NewInternalError(fmt.Errorf("API server failed")), 0)
+		}
+
+		err := scaler.ScaleOut(tc, oldSet, newSet)
+		test.errExpectFn(g, err)
+		if test.changed {
+			g.Expect(int(*newSet.Spec.Replicas)).To(Equal(6))
+		} else {
+			g.Expect(int(*newSet.Spec.Replicas)).To(Equal(5))
+		}
+	}
+
+	tests := []testcase{
+		{
+			name:             "normal",
+			tiflashUpgrading: false,
+			hasPVC:           true,
+			hasDeferAnn:      false,
+			pvcDeleteErr:     false,
+			errExpectFn:      errExpectNil,
+			changed:          true,
+		},
+		{
+			name:             "tiflash is upgrading",
+			tiflashUpgrading: true,
+			hasPVC:           true,
+			hasDeferAnn:      false,
+			pvcDeleteErr:     false,
+			errExpectFn:      errExpectNil,
+			changed:          false,
+		},
+		{
+			name:             "cache don't have pvc",
+			tiflashUpgrading: false,
+			hasPVC:           false,
+			hasDeferAnn:      false,
+			pvcDeleteErr:     false,
+			errExpectFn:      errExpectNil,
+			changed:          true,
+		},
+		{
+			name:             "pvc annotations defer deletion is not nil, pvc delete failed",
+			tiflashUpgrading: false,
+			hasPVC:           true,
+			hasDeferAnn:      true,
+			pvcDeleteErr:     true,
+			errExpectFn:      errExpectNotNil,
+			changed:          false,
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
+
+func TestTiFlashScalerScaleIn(t *testing.T) {
+	g := NewGomegaWithT(t)
+	type testcase struct {
+		name             string
+		tiflashUpgrading bool
+		storeFun         func(tc *v1alpha1.TidbCluster)
+		delStoreErr      bool
+		hasPVC           bool
+		storeIDSynced    bool
+		isPodReady       bool
+		hasSynced        bool
+		pvcUpdateErr     bool
+		errExpectFn      func(*GomegaWithT, error)
+		changed          bool
+		expectDeleteID   uint64
+	}
+
+	controller.ResyncDuration = 0
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+		tc := newTidbClusterForPD()
+		tc.Spec.TiFlash = &v1alpha1.TiFlashSpec{Replicas: 4}
+		test.storeFun(tc)
+
+		if test.tiflashUpgrading {
+			tc.Status.TiFlash.Phase = v1alpha1.UpgradePhase
+		}
+
+		oldSet := newStatefulSetForPDScale()
+		newSet := oldSet.DeepCopy()
+		newSet.Spec.Replicas = controller.Int32Ptr(3)
+
+		pod := &corev1.Pod{
+			TypeMeta: metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              ordinalPodName(v1alpha1.TiFlashMemberType, tc.GetName(), 4),
+				Namespace:         corev1.NamespaceDefault,
+				CreationTimestamp: metav1.Time{Time: time.Now().Add(-1 * time.Hour)},
+			},
+		}
+
+		readyPodFunc(pod)
+		if !test.isPodReady {
+			notReadyPodFunc(pod)
+		}
+
+		if !test.hasSynced {
+			pod.CreationTimestamp = metav1.Time{Time: time.Now().Add(1 * time.Hour)}
+		}
+
+		scaler, pdControl, pvcIndexer, podIndexer, pvcControl := newFakeTiFlashScaler()
+
+		if test.hasPVC {
+			pvc := newPVCForStatefulSet(oldSet, v1alpha1.TiFlashMemberType)
+			pvcIndexer.Add(pvc)
+		}
+
+		pod.Labels = map[string]string{}
+		if test.storeIDSynced {
+			pod.Labels[label.StoreIDLabelKey] = "1"
+		}
+		podIndexer.Add(pod)
+
+		pdClient := controller.NewFakePDClient(pdControl, tc)
+
+		var deletedID uint64
+		pdClient.AddReaction(pdapi.DeleteStoreActionType, func(action *pdapi.Action) (interface{}, error) {
+			deletedID = action.ID
+			if test.delStoreErr {
+				return nil, fmt.Errorf("delete store error")
+			}
+			return nil, nil
+		})
+		if test.pvcUpdateErr {
+			pvcControl.SetUpdatePVCError(errors.NewInternalError(fmt.Errorf("API server failed")), 0)
+		}
+
+		err := scaler.ScaleIn(tc, oldSet, newSet)
+		test.errExpectFn(g, err)
+		g.Expect(deletedID).To(Equal(test.expectDeleteID))
+		if test.changed {
+			g.Expect(int(*newSet.Spec.Replicas)).To(Equal(4))
+			if test.hasPVC {
+				pvc, exist, err := pvcIndexer.GetByKey(fmt.Sprintf("%s/%s", corev1.NamespaceDefault,
+					ordinalPVCName(v1alpha1.TiFlashMemberType, oldSet.GetName(), 4)))
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(exist).To(BeTrue())
+				g.Expect(pvc.(*corev1.PersistentVolumeClaim).Annotations).To(HaveKey(label.AnnPVCDeferDeleting))
+			}
+		} else {
+			g.Expect(int(*newSet.Spec.Replicas)).To(Equal(5))
+		}
+	}
+
+	tests := []testcase{
+		{
+			name:             "store is up, delete store failed",
+			tiflashUpgrading: false,
+			storeFun:         normalTiFlashStoreFun,
+			delStoreErr:      true,
+			hasPVC:           true,
+			storeIDSynced:    true,
+			isPodReady:       true,
+			hasSynced:        true,
+			pvcUpdateErr:     false,
+			errExpectFn:      errExpectNotNil,
+			changed:          false,
+			expectDeleteID:   1,
+		},
+		{
+			name:             "store state is up, delete store success",
+			tiflashUpgrading: false,
+			storeFun:         normalTiFlashStoreFun,
+			delStoreErr:      false,
+			hasPVC:           true,
+			storeIDSynced:    true,
+			isPodReady:       true,
+			hasSynced:        true,
+			pvcUpdateErr:     false,
+			errExpectFn:      errExpectRequeue,
+			changed:          false,
+			expectDeleteID:   1,
+		},
+		{
+			name:             "tiflash is upgrading",
+			tiflashUpgrading: true,
+			storeFun:         normalTiFlashStoreFun,
+			delStoreErr:      false,
+			hasPVC:           true,
+			storeIDSynced:    true,
+			isPodReady:       true,
+			hasSynced:        true,
+			pvcUpdateErr:     false,
+			errExpectFn:      errExpectNil,
+			changed:          false,
+		},
+		{
+			name:             "status.TiFlash.Stores is empty",
+			tiflashUpgrading: false,
+			storeFun: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.TiFlash.Stores = map[string]v1alpha1.TiKVStore{}
+			},
+			delStoreErr:   false,
+			hasPVC:        true,
+			storeIDSynced: true,
+			isPodReady:    true,
+			hasSynced:     true,
+			pvcUpdateErr:  false,
+			errExpectFn:   errExpectNotNil,
+			changed:       false,
+		},
+		{
+			name:             "tiflash pod is not ready now, not sure if the status has been synced",
+			tiflashUpgrading: false,
+			storeFun: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.TiFlash.Stores = map[string]v1alpha1.TiKVStore{}
+			},
+			delStoreErr:   false,
+			hasPVC:        true,
+			storeIDSynced: true,
+			isPodReady:    false,
+			hasSynced:     false,
+			pvcUpdateErr:  false,
+			errExpectFn:   errExpectNotNil,
+			changed:       false,
+		},
+		{
+			name:             "tiflash pod is not ready now, make sure the status has been synced",
+			tiflashUpgrading: false,
+			storeFun: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.TiFlash.Stores = map[string]v1alpha1.TiKVStore{}
+			},
+			delStoreErr:   false,
+			hasPVC:        true,
+			storeIDSynced: true,
+			isPodReady:    false,
+			hasSynced:     true,
+			pvcUpdateErr:  false,
+			errExpectFn:   errExpectNil,
+			changed:       true,
+		},
+		{
+			name:             "store id is not integer",
+			tiflashUpgrading: false,
+			storeFun: func(tc *v1alpha1.TidbCluster) {
+				normalTiFlashStoreFun(tc)
+				store := tc.Status.TiFlash.Stores["1"]
+				store.ID = "not integer"
+				tc.Status.TiFlash.Stores["1"] = store
+			},
+			delStoreErr:   false,
+			hasPVC:        true,
+			storeIDSynced: true,
+			isPodReady:    true,
+			hasSynced:     true,
+			pvcUpdateErr:  false,
+			errExpectFn:   errExpectNotNil,
+			changed:       false,
+		},
+		{
+			name:             "store state is offline",
+			tiflashUpgrading: false,
+			storeFun: func(tc *v1alpha1.TidbCluster) {
+				normalTiFlashStoreFun(tc)
+				store := tc.Status.TiFlash.Stores["1"]
+				store.State = v1alpha1.TiKVStateOffline
+				tc.Status.TiFlash.Stores["1"] = store
+			},
+			delStoreErr:   false,
+			hasPVC:        true,
+			storeIDSynced: true,
+			isPodReady:    true,
+			hasSynced:     true,
+			pvcUpdateErr:  false,
+			errExpectFn:   errExpectRequeue,
+			changed:       false,
+		},
+		{
+			name:             "store state is tombstone",
+			tiflashUpgrading: false,
+			storeFun:         tombstoneTiFlashStoreFun,
+			delStoreErr:      false,
+			hasPVC:           true,
+			storeIDSynced:    true,
+			isPodReady:       true,
+			hasSynced:        true,
+			pvcUpdateErr:     false,
+			errExpectFn:      errExpectNil,
+			changed:          true,
+		},
+		{
+			name:             "store state is tombstone and store id not match",
+			tiflashUpgrading: false,
+			storeFun:         tombstoneTiFlashStoreFun,
+			delStoreErr:      false,
+			hasPVC:           true,
+			storeIDSynced:    false,
+			isPodReady:       true,
+			hasSynced:        true,
+			pvcUpdateErr:     false,
+			errExpectFn:      errExpectNotNil,
+			changed:          false,
+		},
+		{
+			name:             "store state is tombstone, don't have pvc",
+			tiflashUpgrading: false,
+			storeFun:         tombstoneTiFlashStoreFun,
+			delStoreErr:      false,
+			hasPVC:           false,
+			storeIDSynced:    true,
+			isPodReady:       true,
+			hasSynced:        true,
+			pvcUpdateErr:     false,
+			errExpectFn:      errExpectNotNil,
+			changed:          false,
+		},
+		{
+			name:             "store state is tombstone, update pvc failed",
+			tiflashUpgrading: false,
+			storeFun:         tombstoneTiFlashStoreFun,
+			delStoreErr:      false,
+			hasPVC:           true,
+			storeIDSynced:    true,
+			isPodReady:       true,
+			hasSynced:        true,
+			pvcUpdateErr:     true,
+			errExpectFn:      errExpectNotNil,
+			changed:          false,
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
+
+func newFakeTiFlashScaler() (*tiflashScaler, *pdapi.FakePDControl, cache.Indexer, cache.Indexer, *controller.FakePVCControl) {
+	kubeCli := kubefake.NewSimpleClientset()
+
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeCli, 0)
+	pvcInformer := kubeInformerFactory.Core().V1().PersistentVolumeClaims()
+	podInformer := kubeInformerFactory.Core().V1().Pods()
+	pdControl := pdapi.NewFakePDControl(kubeCli)
+	pvcControl := controller.NewFakePVCControl(pvcInformer)
+
+	return &tiflashScaler{generalScaler{pdControl, pvcInformer.Lister(), pvcControl}, podInformer.Lister()},
+		pdControl, pvcInformer.Informer().GetIndexer(), podInformer.Informer().GetIndexer(), pvcControl
+}
+
+func normalTiFlashStoreFun(tc *v1alpha1.TidbCluster) {
+	tc.Status.TiFlash.Stores = map[string]v1alpha1.TiKVStore{
+		"1": {
+			ID:      "1",
+			PodName: ordinalPodName(v1alpha1.TiFlashMemberType, tc.GetName(), 4),
+			State:   v1alpha1.TiKVStateUp,
+		},
+	}
+}
+
+func tombstoneTiFlashStoreFun(tc *v1alpha1.TidbCluster) {
+	tc.Status.TiFlash.TombstoneStores = map[string]v1alpha1.TiKVStore{
+		"1": {
+			ID:      "1",
+			PodName: ordinalPodName(v1alpha1.TiFlashMemberType, tc.GetName(), 4),
+			State:   v1alpha1.TiKVStateTombstone,
+		},
+	}
+}
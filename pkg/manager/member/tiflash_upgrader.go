@@ -0,0 +1,146 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/record"
+	glog "k8s.io/klog"
+)
+
+type tiflashUpgrader struct {
+	podLister corelisters.PodLister
+	recorder  record.EventRecorder
+}
+
+// NewTiFlashUpgrader returns a tiflash Upgrader
+func NewTiFlashUpgrader(podLister corelisters.PodLister, recorder record.EventRecorder) Upgrader {
+	return &tiflashUpgrader{
+		podLister: podLister,
+		recorder:  recorder,
+	}
+}
+
+// Upgrade upgrades the tiflash pods one by one after PD and TiKV are upgraded. The TiFlash stores hold
+// learner replicas only, so no leader is evicted, the next pod is upgraded once the upgraded store is up.
+func (tfu *tiflashUpgrader) Upgrade(tc *v1alpha1.TidbCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	if tc.Status.PD.Phase == v1alpha1.UpgradePhase || tc.TiKVUpgrading() || tc.TiKVGroupUpgrading() {
+		_, podSpec, err := GetLastAppliedConfig(oldSet)
+		if err != nil {
+			return err
+		}
+		newSet.Spec.Template.Spec = *podSpec
+		return nil
+	}
+
+	if !tc.Status.TiFlash.Synced {
+		return fmt.Errorf("Tidbcluster: [%s/%s]'s tiflash status sync failed, can not to be upgraded", ns, tcName)
+	}
+
+	tc.Status.TiFlash.Phase = v1alpha1.UpgradePhase
+	if !templateEqual(newSet.Spec.Template, oldSet.Spec.Template) {
+		return nil
+	}
+
+	if upgradeSettled(tc.Status.TiFlash.StatefulSet, oldSet) {
+		return nil
+	}
+
+	if oldSet.Spec.UpdateStrategy.Type == apps.OnDeleteStatefulSetStrategyType || oldSet.Spec.UpdateStrategy.RollingUpdate == nil {
+		// the update strategy of the tiflash statefulset is modified manually, let the statefulset controller do the upgrade
+		newSet.Spec.UpdateStrategy = oldSet.Spec.UpdateStrategy
+		glog.Warningf("tidbcluster: [%s/%s] tiflash statefulset %s UpdateStrategy has been modified manually", ns, tcName, oldSet.GetName())
+		return nil
+	}
+
+	setUpgradePartition(newSet, *oldSet.Spec.UpdateStrategy.RollingUpdate.Partition)
+	progress := getUpgradeProgress(&tc.Status.TiFlash.Upgrade, tc.Status.TiFlash.StatefulSet.UpdateRevision)
+	var upgraded int32
+	for i := tc.TiFlashStsActualReplicas() - 1; i >= 0; i-- {
+		store := tfu.getStoreByOrdinal(tc, i)
+		if store == nil {
+			continue
+		}
+		podName := ordinalPodName(v1alpha1.TiFlashMemberType, tcName, i)
+		pod, err := tfu.podLister.Pods(ns).Get(podName)
+		if err != nil {
+			return err
+		}
+		revision, exist := pod.Labels[apps.ControllerRevisionHashLabelKey]
+		if !exist {
+			return controller.RequeueErrorf("tidbcluster: [%s/%s]'s tiflash pod: [%s] has no label: %s", ns, tcName, podName, apps.ControllerRevisionHashLabelKey)
+		}
+
+		if revision == tc.Status.TiFlash.StatefulSet.UpdateRevision {
+			if pod.Status.Phase != corev1.PodRunning {
+				if upgradedPodUnhealthy(tc.Spec.TiFlash.UpgradeStrategy, progress) {
+					return tfu.rollback(tc, oldSet, newSet)
+				}
+				return controller.RequeueErrorf("tidbcluster: [%s/%s]'s upgraded tiflash pod: [%s] is not running", ns, tcName, podName)
+			}
+			if store.State != v1alpha1.TiKVStateUp {
+				if upgradedPodUnhealthy(tc.Spec.TiFlash.UpgradeStrategy, progress) {
+					return tfu.rollback(tc, oldSet, newSet)
+				}
+				return controller.RequeueErrorf("tidbcluster: [%s/%s]'s upgraded tiflash pod: [%s] is not all ready", ns, tcName, podName)
+			}
+
+			upgraded++
+			continue
+		}
+
+		if err := checkUpgradeGate(tc, v1alpha1.TiFlashMemberType, tc.Spec.TiFlash.UpgradeStrategy, progress, tc.TiFlashStsActualReplicas(), upgraded); err != nil {
+			return err
+		}
+		setUpgradePartition(newSet, i)
+		return nil
+	}
+
+	return nil
+}
+
+func (tfu *tiflashUpgrader) rollback(tc *v1alpha1.TidbCluster, oldSet, newSet *apps.StatefulSet) error {
+	return rollbackUpgrade(tc, v1alpha1.TiFlashMemberType, tfu.recorder, oldSet, newSet,
+		&tc.Status.TiFlash.Upgrade, tc.Status.TiFlash.StatefulSet.CurrentRevision)
+}
+
+func (tfu *tiflashUpgrader) getStoreByOrdinal(tc *v1alpha1.TidbCluster, ordinal int32) *v1alpha1.TiKVStore {
+	podName := ordinalPodName(v1alpha1.TiFlashMemberType, tc.GetName(), ordinal)
+	for _, store := range tc.Status.TiFlash.Stores {
+		if store.PodName == podName {
+			return &store
+		}
+	}
+	return nil
+}
+
+type fakeTiFlashUpgrader struct{}
+
+// NewFakeTiFlashUpgrader returns a fake tiflash upgrader
+func NewFakeTiFlashUpgrader() Upgrader {
+	return &fakeTiFlashUpgrader{}
+}
+
+func (tfu *fakeTiFlashUpgrader) Upgrade(tc *v1alpha1.TidbCluster, _ *apps.StatefulSet, _ *apps.StatefulSet) error {
+	tc.Status.TiFlash.Phase = v1alpha1.UpgradePhase
+	return nil
+}
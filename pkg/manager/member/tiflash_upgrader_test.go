@@ -0,0 +1,206 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestTiFlashUpgraderUpgrade(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name        string
+		changeFn    func(*v1alpha1.TidbCluster)
+		changePods  func([]*corev1.Pod)
+		errExpectFn func(*GomegaWithT, error)
+		expectFn    func(*GomegaWithT, *v1alpha1.TidbCluster, *apps.StatefulSet)
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+		kubeCli := kubefake.NewSimpleClientset()
+		podInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().Pods()
+		upgrader := NewTiFlashUpgrader(podInformer.Lister(), record.NewFakeRecorder(10))
+
+		tc := newTidbClusterForTiFlash()
+		tc.Status.TiFlash = v1alpha1.TiFlashStatus{
+			Synced: true,
+			StatefulSet: &apps.StatefulSetStatus{
+				Replicas:        2,
+				CurrentRevision: "1",
+				UpdateRevision:  "2",
+			},
+			Stores: map[string]v1alpha1.TiKVStore{
+				"1": {ID: "1", PodName: "test-tiflash-0", State: v1alpha1.TiKVStateUp},
+				"2": {ID: "2", PodName: "test-tiflash-1", State: v1alpha1.TiKVStateUp},
+			},
+		}
+		if test.changeFn != nil {
+			test.changeFn(tc)
+		}
+
+		newSet := newStatefulSetForTiFlashUpgrader()
+		oldSet := newStatefulSetForTiFlashUpgrader()
+		SetLastAppliedConfigAnnotation(oldSet)
+
+		var pods []*corev1.Pod
+		for i := 0; i < 2; i++ {
+			labels := label.New().Instance("test").TiFlash().Labels()
+			labels[apps.ControllerRevisionHashLabelKey] = "1"
+			pods = append(pods, &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      fmt.Sprintf("test-tiflash-%d", i),
+					Namespace: corev1.NamespaceDefault,
+					Labels:    labels,
+				},
+				Status: corev1.PodStatus{Phase: corev1.PodRunning},
+			})
+		}
+		if test.changePods != nil {
+			test.changePods(pods)
+		}
+		for _, pod := range pods {
+			podInformer.Informer().GetIndexer().Add(pod)
+		}
+
+		err := upgrader.Upgrade(tc, oldSet, newSet)
+		test.errExpectFn(g, err)
+		test.expectFn(g, tc, newSet)
+	}
+
+	tests := []*testcase{
+		{
+			name: "normal",
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet) {
+				g.Expect(tc.Status.TiFlash.Phase).To(Equal(v1alpha1.UpgradePhase))
+				g.Expect(*newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(1)))
+			},
+		},
+		{
+			name: "tikv is upgrading",
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.TiKV.Phase = v1alpha1.UpgradePhase
+			},
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet) {
+				g.Expect(tc.Status.TiFlash.Phase).NotTo(Equal(v1alpha1.UpgradePhase))
+				g.Expect(*newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(2)))
+			},
+		},
+		{
+			name: "a tikv group is upgrading",
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.TiKVGroups = map[string]v1alpha1.TiKVStatus{"hot": {Phase: v1alpha1.UpgradePhase}}
+			},
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet) {
+				g.Expect(tc.Status.TiFlash.Phase).NotTo(Equal(v1alpha1.UpgradePhase))
+				g.Expect(*newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(2)))
+			},
+		},
+		{
+			name: "tiflash status is not synced",
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.TiFlash.Synced = false
+			},
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+			},
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet) {
+				g.Expect(*newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(2)))
+			},
+		},
+		{
+			name: "upgraded store is not up",
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				store := tc.Status.TiFlash.Stores["2"]
+				store.State = v1alpha1.TiKVStateDown
+				tc.Status.TiFlash.Stores["2"] = store
+			},
+			changePods: func(pods []*corev1.Pod) {
+				pods[1].Labels[apps.ControllerRevisionHashLabelKey] = "2"
+			},
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+			},
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet) {
+				g.Expect(*newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(2)))
+			},
+		},
+		{
+			name: "upgraded store is up",
+			changePods: func(pods []*corev1.Pod) {
+				pods[1].Labels[apps.ControllerRevisionHashLabelKey] = "2"
+			},
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet) {
+				g.Expect(*newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(0)))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		testFn(test, t)
+	}
+}
+
+func newStatefulSetForTiFlashUpgrader() *apps.StatefulSet {
+	return &apps.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-tiflash",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: apps.StatefulSetSpec{
+			Replicas: controller.Int32Ptr(2),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:  "tiflash",
+							Image: "tiflash-test-image",
+						},
+					},
+				},
+			},
+			UpdateStrategy: apps.StatefulSetUpdateStrategy{
+				Type: apps.RollingUpdateStatefulSetStrategyType,
+				RollingUpdate: &apps.RollingUpdateStatefulSetStrategy{
+					Partition: controller.Int32Ptr(2),
+				}},
+		},
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+)
+
+// tikvGroupView returns a copy of tc whose spec.tikv and status.tikv are the spec and the status of the TiKV group,
+// so that the TiKV StatefulSet of the group is synced, scaled, upgraded and failed over as the TiKV of a cluster.
+// The group name is recorded in the labels of the copy, which is never written back to the apiserver.
+func tikvGroupView(tc *v1alpha1.TidbCluster, group *v1alpha1.TiKVGroupSpec) *v1alpha1.TidbCluster {
+	view := tc.DeepCopy()
+	if view.Labels == nil {
+		view.Labels = map[string]string{}
+	}
+	view.Labels[label.TiKVGroupLabelKey] = group.Name
+
+	view.Spec.TiKV = *group.TiKVSpec.DeepCopy()
+	// the location labels of PD are shared by all the stores
+	view.Spec.TiKV.LocationLabels = tc.Spec.TiKV.LocationLabels
+	view.Spec.TiKV.IsolationLevel = tc.Spec.TiKV.IsolationLevel
+	if view.Spec.TiKV.Image == "" && view.Spec.TiKV.BaseImage == "" {
+		view.Spec.TiKV.Image = tc.Spec.TiKV.Image
+		view.Spec.TiKV.BaseImage = tc.Spec.TiKV.BaseImage
+		if view.Spec.TiKV.Version == "" {
+			view.Spec.TiKV.Version = tc.Spec.TiKV.Version
+		}
+	}
+
+	status := tc.Status.TiKVGroups[group.Name]
+	view.Status.TiKV = *status.DeepCopy()
+	// the status of spec.tikv is kept in the view, keyed by the empty group name, so that the upgrade of
+	// the group can wait for the upgrade of spec.tikv, see tikvUpgradeBlocked
+	if view.Status.TiKVGroups == nil {
+		view.Status.TiKVGroups = map[string]v1alpha1.TiKVStatus{}
+	}
+	view.Status.TiKVGroups[""] = *tc.Status.TiKV.DeepCopy()
+	return view
+}
+
+// tikvUpgradeBlocked returns whether the upgrade of the TiKV StatefulSet of tc, or of the TiKV group tc is a view of,
+// must wait for another TiKV StatefulSet of the cluster. The StatefulSets of spec.tikv and spec.tikvGroups are
+// upgraded one at a time, so that no two stores restart at the same time. A StatefulSet already in the middle of
+// an upgrade only waits for the StatefulSets before it in that order, so that two of them never wait for each other
+func tikvUpgradeBlocked(tc *v1alpha1.TidbCluster) bool {
+	self := tikvGroupName(tc)
+	started := tc.Status.TiKV.Phase == v1alpha1.UpgradePhase
+	names := []string{""}
+	for _, group := range tc.Spec.TiKVGroups {
+		names = append(names, group.Name)
+	}
+	before := true
+	for _, name := range names {
+		if name == self {
+			before = false
+			continue
+		}
+		if tc.Status.TiKVGroups[name].Phase != v1alpha1.UpgradePhase {
+			continue
+		}
+		if before || !started {
+			return true
+		}
+	}
+	return false
+}
+
+// tikvGroupName returns the name of the TiKV group tc is a view of, or an empty string
+func tikvGroupName(tc *v1alpha1.TidbCluster) string {
+	return tc.GetLabels()[label.TiKVGroupLabelKey]
+}
+
+// tikvGroupSpec returns the spec of the TiKV group tc is a view of
+func tikvGroupSpec(tc *v1alpha1.TidbCluster) *v1alpha1.TiKVGroupSpec {
+	name := tikvGroupName(tc)
+	if name == "" {
+		return nil
+	}
+	for i := range tc.Spec.TiKVGroups {
+		if tc.Spec.TiKVGroups[i].Name == name {
+			return &tc.Spec.TiKVGroups[i]
+		}
+	}
+	return nil
+}
+
+// tikvSetName returns the name of the TiKV StatefulSet of tc, or of the TiKV group tc is a view of
+func tikvSetName(tc *v1alpha1.TidbCluster) string {
+	if group := tikvGroupName(tc); group != "" {
+		return controller.TiKVGroupMemberName(tc.GetName(), group)
+	}
+	return controller.TiKVMemberName(tc.GetName())
+}
+
+// tikvSetPodName returns the name of the pod of the TiKV StatefulSet of tc with the ordinal
+func tikvSetPodName(tc *v1alpha1.TidbCluster, ordinal int32) string {
+	return fmt.Sprintf("%s-%d", tikvSetName(tc), ordinal)
+}
+
+// tikvConfigMapName returns the name of the ConfigMap holding the TiKV config of tc, or of the TiKV group tc is a view of
+func tikvConfigMapName(tc *v1alpha1.TidbCluster) string {
+	if group := tikvGroupName(tc); group != "" {
+		return controller.TiKVGroupConfigMapName(tc, group)
+	}
+	return controller.MemberConfigMapName(tc, v1alpha1.TiKVMemberType)
+}
+
+// tikvStorageKey returns the key of the storage status of the PVCs of tc, or of the TiKV group tc is a view of
+func tikvStorageKey(tc *v1alpha1.TidbCluster) string {
+	if group := tikvGroupName(tc); group != "" {
+		return tikvGroupStorageKey(group)
+	}
+	return v1alpha1.TiKVMemberType.String()
+}
+
+// isTiKVSetPod returns whether the TiKV pod belongs to the TiKV StatefulSet of tc. It is always true if the cluster has
+// no TiKV groups, otherwise the pod name must be the name of the StatefulSet followed by an ordinal
+func isTiKVSetPod(tc *v1alpha1.TidbCluster, podName string) bool {
+	if len(tc.Spec.TiKVGroups) == 0 {
+		return true
+	}
+	prefix := tikvSetName(tc) + "-"
+	if !strings.HasPrefix(podName, prefix) {
+		return false
+	}
+	_, err := strconv.ParseUint(strings.TrimPrefix(podName, prefix), 10, 32)
+	return err == nil
+}
@@ -0,0 +1,196 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+)
+
+func TestTiKVGroupView(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForPD()
+	tc.Spec.TiKVGroups = []v1alpha1.TiKVGroupSpec{
+		{Name: "hot", TiKVSpec: v1alpha1.TiKVSpec{Replicas: 2}},
+		{Name: "cold", TiKVSpec: v1alpha1.TiKVSpec{ComponentSpec: v1alpha1.ComponentSpec{Image: "tikv-cold-image"}, Replicas: 5}},
+	}
+	tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{"1": {ID: "1"}}
+	tc.Status.TiKVGroups = map[string]v1alpha1.TiKVStatus{
+		"hot": {Stores: map[string]v1alpha1.TiKVStore{"2": {ID: "2"}}},
+	}
+
+	hot := tikvGroupView(tc, &tc.Spec.TiKVGroups[0])
+	g.Expect(tikvGroupName(hot)).To(Equal("hot"))
+	g.Expect(tikvGroupSpec(hot)).To(Equal(&tc.Spec.TiKVGroups[0]))
+	g.Expect(tikvSetName(hot)).To(Equal("test-tikv-hot"))
+	g.Expect(tikvSetPodName(hot, 1)).To(Equal("test-tikv-hot-1"))
+	g.Expect(tikvConfigMapName(hot)).To(Equal("test-tikv-hot"))
+	g.Expect(hot.Spec.TiKV.Replicas).To(Equal(int32(2)))
+	g.Expect(hot.BaseTiKVSpec().Image()).To(Equal("tikv-test-image"))
+	g.Expect(hot.Status.TiKV.Stores).To(HaveKey("2"))
+	g.Expect(hot.Status.TiKVGroups[""].Stores).To(HaveKey("1"))
+
+	cold := tikvGroupView(tc, &tc.Spec.TiKVGroups[1])
+	g.Expect(cold.BaseTiKVSpec().Image()).To(Equal("tikv-cold-image"))
+	g.Expect(cold.Status.TiKV.Stores).To(BeEmpty())
+
+	// the cluster is not changed by the views
+	g.Expect(tikvGroupName(tc)).To(BeEmpty())
+	g.Expect(tikvGroupSpec(tc)).To(BeNil())
+	g.Expect(tikvSetName(tc)).To(Equal("test-tikv"))
+	g.Expect(tc.Status.TiKV.Stores).To(HaveKey("1"))
+}
+
+func TestIsTiKVSetPod(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name    string
+		groups  []v1alpha1.TiKVGroupSpec
+		group   string
+		podName string
+		expect  bool
+	}
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+		tc := newTidbClusterForPD()
+		tc.Spec.TiKVGroups = test.groups
+		for i := range test.groups {
+			if test.groups[i].Name == test.group {
+				tc = tikvGroupView(tc, &test.groups[i])
+			}
+		}
+		g.Expect(isTiKVSetPod(tc, test.podName)).To(Equal(test.expect))
+	}
+	groups := []v1alpha1.TiKVGroupSpec{{Name: "hot"}}
+	tests := []testcase{
+		{
+			name:    "cluster without groups",
+			podName: "pod-1",
+			expect:  true,
+		},
+		{
+			name:    "pod of the cluster",
+			groups:  groups,
+			podName: "test-tikv-0",
+			expect:  true,
+		},
+		{
+			name:    "pod of a group is not a pod of the cluster",
+			groups:  groups,
+			podName: "test-tikv-hot-0",
+			expect:  false,
+		},
+		{
+			name:    "pod of the group",
+			groups:  groups,
+			group:   "hot",
+			podName: "test-tikv-hot-0",
+			expect:  true,
+		},
+		{
+			name:    "pod of the cluster is not a pod of the group",
+			groups:  groups,
+			group:   "hot",
+			podName: "test-tikv-0",
+			expect:  false,
+		},
+	}
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
+
+func TestTiKVUpgradeBlocked(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name    string
+		group   string
+		phases  map[string]v1alpha1.MemberPhase
+		started bool
+		expect  bool
+	}
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+		tc := newTidbClusterForPD()
+		tc.Spec.TiKVGroups = []v1alpha1.TiKVGroupSpec{{Name: "hot"}, {Name: "cold"}}
+		tc.Status.TiKVGroups = map[string]v1alpha1.TiKVStatus{}
+		for name, phase := range test.phases {
+			if name == "" {
+				tc.Status.TiKV.Phase = phase
+				continue
+			}
+			tc.Status.TiKVGroups[name] = v1alpha1.TiKVStatus{Phase: phase}
+		}
+		for i := range tc.Spec.TiKVGroups {
+			if tc.Spec.TiKVGroups[i].Name == test.group {
+				tc = tikvGroupView(tc, &tc.Spec.TiKVGroups[i])
+			}
+		}
+		if test.started {
+			tc.Status.TiKV.Phase = v1alpha1.UpgradePhase
+		}
+		g.Expect(tikvUpgradeBlocked(tc)).To(Equal(test.expect))
+	}
+	tests := []testcase{
+		{
+			name:   "nothing is upgrading",
+			expect: false,
+		},
+		{
+			name:   "spec.tikv waits for a group",
+			phases: map[string]v1alpha1.MemberPhase{"cold": v1alpha1.UpgradePhase},
+			expect: true,
+		},
+		{
+			name:   "a group waits for spec.tikv",
+			group:  "hot",
+			phases: map[string]v1alpha1.MemberPhase{"": v1alpha1.UpgradePhase},
+			expect: true,
+		},
+		{
+			name:   "a group waits for another group",
+			group:  "hot",
+			phases: map[string]v1alpha1.MemberPhase{"cold": v1alpha1.UpgradePhase},
+			expect: true,
+		},
+		{
+			name:    "a started group waits for the groups before it",
+			group:   "cold",
+			phases:  map[string]v1alpha1.MemberPhase{"hot": v1alpha1.UpgradePhase},
+			started: true,
+			expect:  true,
+		},
+		{
+			name:    "a started group does not wait for the groups after it",
+			group:   "hot",
+			phases:  map[string]v1alpha1.MemberPhase{"cold": v1alpha1.UpgradePhase},
+			started: true,
+			expect:  false,
+		},
+		{
+			name:    "started spec.tikv does not wait for the groups",
+			phases:  map[string]v1alpha1.MemberPhase{"hot": v1alpha1.UpgradePhase},
+			started: true,
+			expect:  false,
+		},
+	}
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	glog "k8s.io/klog"
+)
+
+// storeLocationLabels returns the location labels of the stores and the node labels they are read from,
+// they are spec.tikv.locationLabels if it's set, or the location-labels of PD read from the node labels
+// of the same names
+func storeLocationLabels(tc *v1alpha1.TidbCluster, config *pdapi.Config) []v1alpha1.StoreLabel {
+	if len(tc.Spec.TiKV.LocationLabels) > 0 {
+		return tc.Spec.TiKV.LocationLabels
+	}
+	var locationLabels []v1alpha1.StoreLabel
+	for _, name := range config.Replication.LocationLabels {
+		locationLabels = append(locationLabels, v1alpha1.StoreLabel{Name: name})
+	}
+	return locationLabels
+}
+
+// syncLocationLabels sets the location-labels and the isolation-level of PD to spec.tikv.locationLabels and
+// spec.tikv.isolationLevel, and returns the reason and the message if they can't be kept consistent
+func syncLocationLabels(tc *v1alpha1.TidbCluster, pdCli pdapi.PDClient, config *pdapi.Config) (string, string, error) {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	if len(tc.Spec.TiKV.LocationLabels) == 0 {
+		return "", "", nil
+	}
+
+	var names []string
+	for _, l := range tc.Spec.TiKV.LocationLabels {
+		names = append(names, l.Name)
+	}
+	var reason, msg string
+	isolationLevel := tc.Spec.TiKV.IsolationLevel
+	if isolationLevel != "" && !sets.NewString(names...).Has(isolationLevel) {
+		reason = "InvalidIsolationLevel"
+		msg = fmt.Sprintf("isolation level %s is not one of the location labels %v", isolationLevel, names)
+		isolationLevel = ""
+	}
+
+	// the isolation-level is not kept by PD before 4.0, it's posted to PD on every sync if it's set
+	if pdVersion := imageVersion(tc.BasePDSpec().Image()); isolationLevel != "" && pdVersion != nil && pdVersion.Major < 4 {
+		reason = "IsolationLevelUnsupported"
+		msg = fmt.Sprintf("isolation level %s is not supported by pd %s", isolationLevel, pdVersion)
+		isolationLevel = ""
+	}
+
+	current := []string(config.Replication.LocationLabels)
+	if reflect.DeepEqual(current, names) && (isolationLevel == "" || config.Replication.IsolationLevel == isolationLevel) {
+		return reason, msg, nil
+	}
+	if err := pdCli.SetLocationLabels(names, isolationLevel); err != nil {
+		return "PDConfigMismatch", fmt.Sprintf("failed to set the location-labels of pd from %v to %v: %v", current, names, err), err
+	}
+	glog.Infof("tidbcluster: [%s/%s]'s pd location-labels are set from %v to %v, isolation-level: %q",
+		ns, tcName, current, names, isolationLevel)
+	return reason, msg, nil
+}
+
+// haTopologyKeyMismatch returns the reason and the message if the HA topology key which the pods are spread across
+// is not the node label of a location label, PD doesn't isolate the region replicas across it then
+func haTopologyKeyMismatch(tc *v1alpha1.TidbCluster, locationLabels []v1alpha1.StoreLabel) (string, string) {
+	topologyKey := tc.HATopologyKey()
+	if topologyKey == "" {
+		return "", ""
+	}
+	for _, l := range locationLabels {
+		if l.NodeLabel == topologyKey || (l.NodeLabel == "" && l.Name == topologyKey) {
+			return "", ""
+		}
+	}
+	return "HATopologyKeyNotLocationLabel", fmt.Sprintf("ha topology key %s is not the node label of the location labels %s",
+		topologyKey, formatLocationLabels(locationLabels))
+}
+
+// getNodeLabels returns the values of the store location labels on the node
+func getNodeLabels(nodeLister corelisters.NodeLister, nodeName string, locationLabels []v1alpha1.StoreLabel) (map[string]string, error) {
+	node, err := nodeLister.Get(nodeName)
+	if err != nil {
+		return nil, err
+	}
+	labels := map[string]string{}
+	ls := node.GetLabels()
+	for _, locationLabel := range locationLabels {
+		key := locationLabel.NodeLabel
+		if key == "" {
+			key = locationLabel.Name
+		}
+		if value, found := ls[key]; found {
+			labels[locationLabel.Name] = value
+			continue
+		}
+
+		// TODO after pd supports storeLabel containing slash character, these codes should be deleted
+		if locationLabel.Name == "host" && locationLabel.NodeLabel == "" {
+			if host, found := ls[corev1.LabelHostname]; found {
+				labels[locationLabel.Name] = host
+			}
+		}
+	}
+	return labels, nil
+}
+
+// missingLocationLabels returns the names of the location labels not found in labels
+func missingLocationLabels(locationLabels []v1alpha1.StoreLabel, labels map[string]string) []string {
+	var missing []string
+	for _, l := range locationLabels {
+		if _, ok := labels[l.Name]; !ok {
+			missing = append(missing, l.Name)
+		}
+	}
+	return missing
+}
+
+// setLocationLabelsMismatch sets the LocationLabelsMismatch condition of tc to true with the reason, or
+// to false if the reason is empty and the condition is true
+func setLocationLabelsMismatch(tc *v1alpha1.TidbCluster, reason string, msg string) {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	if reason != "" {
+		v1alpha1.UpdateTidbClusterCondition(&tc.Status, &v1alpha1.TidbClusterCondition{
+			Type:    v1alpha1.TidbClusterLocationLabelsMismatch,
+			Status:  corev1.ConditionTrue,
+			Reason:  reason,
+			Message: msg,
+		})
+		glog.Warningf("tidbcluster: [%s/%s]'s location labels mismatch, %s", ns, tcName, msg)
+		return
+	}
+	_, condition := v1alpha1.GetTidbClusterCondition(&tc.Status, v1alpha1.TidbClusterLocationLabelsMismatch)
+	if condition == nil || condition.Status != corev1.ConditionTrue {
+		return
+	}
+	v1alpha1.UpdateTidbClusterCondition(&tc.Status, &v1alpha1.TidbClusterCondition{
+		Type:    v1alpha1.TidbClusterLocationLabelsMismatch,
+		Status:  corev1.ConditionFalse,
+		Reason:  "LocationLabelsMatched",
+		Message: fmt.Sprintf("the location labels of pd and the stores match %s", formatLocationLabels(tc.Spec.TiKV.LocationLabels)),
+	})
+}
+
+func formatLocationLabels(locationLabels []v1alpha1.StoreLabel) string {
+	var parts []string
+	for _, l := range locationLabels {
+		if l.NodeLabel == "" {
+			parts = append(parts, l.Name)
+		} else {
+			parts = append(parts, fmt.Sprintf("%s=%s", l.Name, l.NodeLabel))
+		}
+	}
+	return "[" + strings.Join(parts, " ") + "]"
+}
@@ -0,0 +1,256 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/pd/pkg/typeutil"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTiKVMemberManagerSyncLocationLabels(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name           string
+		locationLabels []v1alpha1.StoreLabel
+		isolationLevel string
+		haTopologyKey  string
+		pdVersion      string
+		pdLabels       []string
+		pdIsolation    string
+		setConfigErr   bool
+		nodeLabels     map[string]string
+		expectSet      bool
+		expectPDLabels []string
+		expectIsolate  string
+		expectStore    map[string]string
+		expectReason   string
+		expectErr      bool
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+
+		tc := newTidbClusterForPD()
+		tc.Spec.TiKV.LocationLabels = test.locationLabels
+		tc.Spec.TiKV.IsolationLevel = test.isolationLevel
+		tc.Spec.HATopologyKey = test.haTopologyKey
+		if test.pdVersion != "" {
+			tc.Spec.PD.Image = "pingcap/pd:" + test.pdVersion
+		}
+		tkmm, _, _, pdClient, podIndexer, nodeIndexer := newFakeTiKVMemberManager(tc)
+
+		pdClient.AddReaction(pdapi.GetConfigActionType, func(action *pdapi.Action) (interface{}, error) {
+			return &pdapi.Config{
+				Replication: pdapi.ReplicationConfig{
+					LocationLabels: typeutil.StringSlice(test.pdLabels),
+					IsolationLevel: test.pdIsolation,
+				},
+			}, nil
+		})
+		configSet := false
+		var pdLabels []string
+		var isolationLevel string
+		pdClient.AddReaction(pdapi.SetLocationLabelsActionType, func(action *pdapi.Action) (interface{}, error) {
+			configSet = true
+			pdLabels = action.LocationLabels
+			isolationLevel = action.Name
+			if test.setConfigErr {
+				return nil, fmt.Errorf("isolation-level is not supported")
+			}
+			return nil, nil
+		})
+		pdClient.AddReaction(pdapi.GetStoresActionType, func(action *pdapi.Action) (interface{}, error) {
+			return &pdapi.StoresInfo{
+				Stores: []*pdapi.StoreInfo{
+					{
+						Store: &pdapi.MetaStore{
+							Store:     &metapb.Store{Id: 1, Address: "test-tikv-0.test-tikv-peer.default.svc:20160"},
+							StateName: "Up",
+						},
+						Status: &pdapi.StoreStatus{},
+					},
+				},
+			}, nil
+		})
+		var storeLabels map[string]string
+		pdClient.AddReaction(pdapi.SetStoreLabelsActionType, func(action *pdapi.Action) (interface{}, error) {
+			storeLabels = action.Labels
+			return true, nil
+		})
+		nodeIndexer.Add(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: test.nodeLabels}})
+		podIndexer.Add(&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-tikv-0", Namespace: metav1.NamespaceDefault},
+			Spec:       corev1.PodSpec{NodeName: "node-1"},
+		})
+
+		_, err := tkmm.setStoreLabelsForTiKV(tc)
+		if test.expectErr {
+			g.Expect(err).To(HaveOccurred())
+		} else {
+			g.Expect(err).NotTo(HaveOccurred())
+		}
+		g.Expect(configSet).To(Equal(test.expectSet))
+		if test.expectSet {
+			g.Expect(pdLabels).To(Equal(test.expectPDLabels))
+			g.Expect(isolationLevel).To(Equal(test.expectIsolate))
+		}
+		g.Expect(storeLabels).To(Equal(test.expectStore))
+
+		_, condition := v1alpha1.GetTidbClusterCondition(&tc.Status, v1alpha1.TidbClusterLocationLabelsMismatch)
+		if test.expectReason == "" {
+			g.Expect(condition).To(BeNil())
+		} else {
+			g.Expect(condition).NotTo(BeNil())
+			g.Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+			g.Expect(condition.Reason).To(Equal(test.expectReason))
+		}
+	}
+
+	zoneHost := []v1alpha1.StoreLabel{
+		{Name: "zone", NodeLabel: corev1.LabelZoneFailureDomain},
+		{Name: "host"},
+	}
+	tests := []testcase{
+		{
+			name:           "ha topology key is the node label of a location label",
+			locationLabels: zoneHost,
+			haTopologyKey:  corev1.LabelZoneFailureDomain,
+			pdLabels:       []string{"zone", "host"},
+			nodeLabels:     map[string]string{corev1.LabelZoneFailureDomain: "z1", corev1.LabelHostname: "node-1"},
+			expectStore:    map[string]string{"zone": "z1", "host": "node-1"},
+		},
+		{
+			name:           "ha topology key is not the node label of a location label",
+			locationLabels: []v1alpha1.StoreLabel{{Name: "host"}},
+			haTopologyKey:  corev1.LabelZoneFailureDomain,
+			pdLabels:       []string{"host"},
+			nodeLabels:     map[string]string{corev1.LabelZoneFailureDomain: "z1", corev1.LabelHostname: "node-1"},
+			expectStore:    map[string]string{"host": "node-1"},
+			expectReason:   "HATopologyKeyNotLocationLabel",
+		},
+		{
+			name:          "ha topology key is a location label of PD",
+			haTopologyKey: "zone",
+			pdLabels:      []string{"zone", "host"},
+			nodeLabels:    map[string]string{"zone": "z1", corev1.LabelHostname: "node-1"},
+			expectStore:   map[string]string{"zone": "z1", "host": "node-1"},
+		},
+		{
+			name:          "ha topology key without location labels",
+			haTopologyKey: "zone",
+			expectReason:  "HATopologyKeyNotLocationLabel",
+		},
+		{
+			name:        "location labels of PD are read from the node labels of the same names",
+			pdLabels:    []string{"zone", "host"},
+			nodeLabels:  map[string]string{"zone": "z1", corev1.LabelHostname: "node-1"},
+			expectStore: map[string]string{"zone": "z1", "host": "node-1"},
+		},
+		{
+			name:           "location labels of PD are set to the mapping",
+			locationLabels: zoneHost,
+			isolationLevel: "zone",
+			pdLabels:       []string{"region", "zone", "rack", "host"},
+			nodeLabels:     map[string]string{corev1.LabelZoneFailureDomain: "z1", corev1.LabelHostname: "node-1"},
+			expectSet:      true,
+			expectPDLabels: []string{"zone", "host"},
+			expectIsolate:  "zone",
+			expectStore:    map[string]string{"zone": "z1", "host": "node-1"},
+		},
+		{
+			name:           "location labels of PD are consistent",
+			locationLabels: zoneHost,
+			isolationLevel: "zone",
+			pdLabels:       []string{"zone", "host"},
+			pdIsolation:    "zone",
+			nodeLabels:     map[string]string{corev1.LabelZoneFailureDomain: "z1", corev1.LabelHostname: "node-1"},
+			expectStore:    map[string]string{"zone": "z1", "host": "node-1"},
+		},
+		{
+			name:           "isolation level is not a location label",
+			locationLabels: zoneHost,
+			isolationLevel: "rack",
+			pdLabels:       []string{"zone", "host"},
+			nodeLabels:     map[string]string{corev1.LabelZoneFailureDomain: "z1", corev1.LabelHostname: "node-1"},
+			expectStore:    map[string]string{"zone": "z1", "host": "node-1"},
+			expectReason:   "InvalidIsolationLevel",
+		},
+		{
+			name:           "isolation level is not set to PD before 4.0",
+			locationLabels: zoneHost,
+			isolationLevel: "zone",
+			pdVersion:      "v3.1.0",
+			pdLabels:       []string{"region", "zone", "rack", "host"},
+			nodeLabels:     map[string]string{corev1.LabelZoneFailureDomain: "z1", corev1.LabelHostname: "node-1"},
+			expectSet:      true,
+			expectPDLabels: []string{"zone", "host"},
+			expectIsolate:  "",
+			expectStore:    map[string]string{"zone": "z1", "host": "node-1"},
+			expectReason:   "IsolationLevelUnsupported",
+		},
+		{
+			name:           "location labels of PD before 4.0 are consistent",
+			locationLabels: zoneHost,
+			isolationLevel: "zone",
+			pdVersion:      "v3.1.0",
+			pdLabels:       []string{"zone", "host"},
+			nodeLabels:     map[string]string{corev1.LabelZoneFailureDomain: "z1", corev1.LabelHostname: "node-1"},
+			expectStore:    map[string]string{"zone": "z1", "host": "node-1"},
+			expectReason:   "IsolationLevelUnsupported",
+		},
+		{
+			name:           "location labels of PD 4.0 are consistent",
+			locationLabels: zoneHost,
+			isolationLevel: "zone",
+			pdVersion:      "v4.0.0",
+			pdLabels:       []string{"zone", "host"},
+			pdIsolation:    "zone",
+			nodeLabels:     map[string]string{corev1.LabelZoneFailureDomain: "z1", corev1.LabelHostname: "node-1"},
+			expectStore:    map[string]string{"zone": "z1", "host": "node-1"},
+		},
+		{
+			name:           "failed to set the location labels of PD",
+			locationLabels: zoneHost,
+			isolationLevel: "zone",
+			pdLabels:       []string{"zone", "host"},
+			setConfigErr:   true,
+			expectSet:      true,
+			expectPDLabels: []string{"zone", "host"},
+			expectIsolate:  "zone",
+			expectReason:   "PDConfigMismatch",
+			expectErr:      true,
+		},
+		{
+			name:           "node has no zone label",
+			locationLabels: zoneHost,
+			pdLabels:       []string{"zone", "host"},
+			nodeLabels:     map[string]string{corev1.LabelHostname: "node-1"},
+			expectStore:    map[string]string{"host": "node-1"},
+			expectReason:   "NodeLabelsMissing",
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
@@ -51,6 +51,7 @@ type tikvMemberManager struct {
 	tikvFailover                 Failover
 	tikvScaler                   Scaler
 	tikvUpgrader                 Upgrader
+	tikvMigrator                 StorageMigrator
 	tikvStatefulSetIsUpgradingFn func(corelisters.PodLister, pdapi.PDControlInterface, *apps.StatefulSet, *v1alpha1.TidbCluster) (bool, error)
 }
 
@@ -66,7 +67,8 @@ func NewTiKVMemberManager(pdControl pdapi.PDControlInterface,
 	autoFailover bool,
 	tikvFailover Failover,
 	tikvScaler Scaler,
-	tikvUpgrader Upgrader) manager.Manager {
+	tikvUpgrader Upgrader,
+	tikvMigrator StorageMigrator) manager.Manager {
 	kvmm := tikvMemberManager{
 		pdControl:    pdControl,
 		podLister:    podLister,
@@ -80,6 +82,7 @@ func NewTiKVMemberManager(pdControl pdapi.PDControlInterface,
 		tikvFailover: tikvFailover,
 		tikvScaler:   tikvScaler,
 		tikvUpgrader: tikvUpgrader,
+		tikvMigrator: tikvMigrator,
 	}
 	kvmm.tikvStatefulSetIsUpgradingFn = tikvStatefulSetIsUpgrading
 	return &kvmm
@@ -117,7 +120,40 @@ func (tkmm *tikvMemberManager) Sync(tc *v1alpha1.TidbCluster) error {
 			return err
 		}
 	}
-	return tkmm.syncStatefulSetForTidbCluster(tc)
+	if err := tkmm.syncStatefulSetForTidbCluster(tc); err != nil {
+		return err
+	}
+	return tkmm.syncTiKVGroups(tc)
+}
+
+// syncTiKVGroups syncs the StatefulSet of each TiKV group through a view of tc, see tikvGroupView.
+// The StatefulSet of a group removed from tc is left as it is, so a group should be scaled in to 0 before its removal.
+func (tkmm *tikvMemberManager) syncTiKVGroups(tc *v1alpha1.TidbCluster) error {
+	if len(tc.Spec.TiKVGroups) == 0 {
+		tc.Status.TiKVGroups = nil
+		return nil
+	}
+
+	// drop the status of the removed groups, the status of a group is updated as soon as the group is synced,
+	// so that the groups synced after it see whether it is being upgraded
+	groupStatus := map[string]v1alpha1.TiKVStatus{}
+	for _, group := range tc.Spec.TiKVGroups {
+		if status, ok := tc.Status.TiKVGroups[group.Name]; ok {
+			groupStatus[group.Name] = status
+		}
+	}
+	tc.Status.TiKVGroups = groupStatus
+	for i := range tc.Spec.TiKVGroups {
+		group := &tc.Spec.TiKVGroups[i]
+		view := tikvGroupView(tc, group)
+		err := tkmm.syncStatefulSetForTidbCluster(view)
+		tc.Status.TiKVGroups[group.Name] = view.Status.TiKV
+		tc.Status.Conditions = view.Status.Conditions
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (tkmm *tikvMemberManager) syncServiceForTidbCluster(tc *v1alpha1.TidbCluster, svcConfig SvcConfig) error {
@@ -161,14 +197,13 @@ func (tkmm *tikvMemberManager) syncServiceForTidbCluster(tc *v1alpha1.TidbCluste
 
 func (tkmm *tikvMemberManager) syncStatefulSetForTidbCluster(tc *v1alpha1.TidbCluster) error {
 	ns := tc.GetNamespace()
-	tcName := tc.GetName()
 
 	newSet, err := getNewTiKVSetForTidbCluster(tc)
 	if err != nil {
 		return err
 	}
 
-	oldSetTmp, err := tkmm.setLister.StatefulSets(ns).Get(controller.TiKVMemberName(tcName))
+	oldSetTmp, err := tkmm.setLister.StatefulSets(ns).Get(tikvSetName(tc))
 	if err != nil && !errors.IsNotFound(err) {
 		return err
 	}
@@ -201,12 +236,26 @@ func (tkmm *tikvMemberManager) syncStatefulSetForTidbCluster(tc *v1alpha1.TidbCl
 		return err
 	}
 
+	if err := keepUpgradeRolledBack(tc, v1alpha1.TiKVMemberType, newSet, oldSet); err != nil {
+		return err
+	}
+	if err := checkUpgradeVersion(tc, v1alpha1.TiKVMemberType, tkmm.setLister, tkmm.pdControl, oldSet, newSet); err != nil {
+		return err
+	}
+	if err := checkResize(tc, v1alpha1.TiKVMemberType, tkmm.podLister, tkmm.nodeLister, oldSet, newSet); err != nil {
+		return err
+	}
+
 	if !templateEqual(newSet.Spec.Template, oldSet.Spec.Template) || tc.Status.TiKV.Phase == v1alpha1.UpgradePhase {
 		if err := tkmm.tikvUpgrader.Upgrade(tc, oldSet, newSet); err != nil {
 			return err
 		}
 	}
 
+	if err := tkmm.tikvMigrator.Migrate(tc, oldSet, newSet); err != nil {
+		return err
+	}
+
 	if *newSet.Spec.Replicas > *oldSet.Spec.Replicas {
 		if err := tkmm.tikvScaler.ScaleOut(tc, oldSet, newSet); err != nil {
 			return err
@@ -232,6 +281,7 @@ func (tkmm *tikvMemberManager) syncStatefulSetForTidbCluster(tc *v1alpha1.TidbCl
 		set.Spec.Template = newSet.Spec.Template
 		*set.Spec.Replicas = *newSet.Spec.Replicas
 		set.Spec.UpdateStrategy = newSet.Spec.UpdateStrategy
+		setUpgradeRollbackAnnotations(&set, oldSet, newSet)
 		err := SetLastAppliedConfigAnnotation(&set)
 		if err != nil {
 			return err
@@ -244,31 +294,36 @@ func (tkmm *tikvMemberManager) syncStatefulSetForTidbCluster(tc *v1alpha1.TidbCl
 }
 
 func (tkmm *tikvMemberManager) syncTiKVServerCerts(tc *v1alpha1.TidbCluster) error {
+	certOpts := tikvServerCertOptions(tc)
+	if tkmm.certControl.CheckSecret(certOpts.Namespace, certOpts.SecretName()) {
+		return nil
+	}
+	return tkmm.certControl.Create(controller.GetOwnerRef(tc), certOpts)
+}
+
+// tikvServerCertOptions returns the options to create the TiKV server cert pair
+func tikvServerCertOptions(tc *v1alpha1.TidbCluster) *controller.TiDBClusterCertOptions {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
 	svcName := controller.TiKVMemberName(tcName)
 	peerName := controller.TiKVPeerMemberName(tcName)
 
-	if tkmm.certControl.CheckSecret(ns, svcName) {
-		return nil
-	}
-
 	hostList := []string{
 		peerName,
 		fmt.Sprintf("%s.%s", peerName, ns),
 		fmt.Sprintf("*.%s.%s.svc", peerName, ns),
 	}
 
-	certOpts := &controller.TiDBClusterCertOptions{
-		Namespace:  ns,
-		Instance:   tcName,
-		CommonName: svcName,
-		HostList:   hostList,
-		Component:  "tikv",
-		Suffix:     "tikv",
+	return &controller.TiDBClusterCertOptions{
+		Namespace:    ns,
+		Instance:     tcName,
+		CommonName:   svcName,
+		HostList:     hostList,
+		Component:    "tikv",
+		Suffix:       "tikv",
+		Issuer:       tc.Spec.TLSIssuer,
+		CASecretName: tc.TLSCASecretName(),
 	}
-
-	return tkmm.certControl.Create(controller.GetOwnerRef(tc), certOpts)
 }
 
 func getNewServiceForTidbCluster(tc *v1alpha1.TidbCluster, svcConfig SvcConfig) *corev1.Service {
@@ -309,7 +364,7 @@ func getNewServiceForTidbCluster(tc *v1alpha1.TidbCluster, svcConfig SvcConfig)
 func getNewTiKVSetForTidbCluster(tc *v1alpha1.TidbCluster) (*apps.StatefulSet, error) {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
-	tikvConfigMap := controller.MemberConfigMapName(tc, v1alpha1.TiKVMemberType)
+	tikvConfigMap := tikvConfigMapName(tc)
 	annMount, annVolume := annotationsMountVolume()
 	volMounts := []corev1.VolumeMount{
 		annMount,
@@ -343,13 +398,7 @@ func getNewTiKVSetForTidbCluster(tc *v1alpha1.TidbCluster) (*apps.StatefulSet, e
 		},
 	}
 	if tc.Spec.EnableTLSCluster {
-		vols = append(vols, corev1.Volume{
-			Name: "tikv-tls", VolumeSource: corev1.VolumeSource{
-				Secret: &corev1.SecretVolumeSource{
-					SecretName: controller.TiKVMemberName(tcName),
-				},
-			},
-		})
+		vols = append(vols, tlsSecretVolume(tc, "tikv-tls", controller.TiKVMemberName(tcName)))
 	}
 
 	sysctls := "sysctl -w"
@@ -397,8 +446,10 @@ func getNewTiKVSetForTidbCluster(tc *v1alpha1.TidbCluster) (*apps.StatefulSet, e
 	}
 
 	tikvLabel := labelTiKV(tc)
-	setName := controller.TiKVMemberName(tcName)
+	setName := tikvSetName(tc)
 	podAnnotations := CombineAnnotations(controller.AnnProm(20180), tc.BaseTiKVSpec().Annotations())
+	podAnnotations = CombineAnnotations(podAnnotations, tlsCertRenewAnnotations(tc, controller.TiKVMemberName(tcName)))
+	podAnnotations = CombineAnnotations(podAnnotations, storageResizeAnnotations(tc, tikvStorageKey(tc)))
 	capacity := controller.TiKVCapacity(tc.Spec.TiKV.Limits)
 	headlessSvcName := controller.TiKVPeerMemberName(tcName)
 	storageClassName := tc.Spec.TiKV.StorageClassName
@@ -432,6 +483,16 @@ func getNewTiKVSetForTidbCluster(tc *v1alpha1.TidbCluster) (*apps.StatefulSet, e
 			Value: tc.Spec.Timezone,
 		},
 	}
+	env = append(env, clusterDomainEnv(tc)...)
+	// the block cache is resized with the memory limit unless it's set in the config
+	if tc.Spec.TiKV.BlockCacheFromMemoryLimit {
+		if blockCache := blockCacheCapacity(util.ResourceRequirement(tc.Spec.TiKV.Resources)); blockCache != "" {
+			env = append(env, corev1.EnvVar{
+				Name:  blockCacheCapacityEnv,
+				Value: blockCache,
+			})
+		}
+	}
 
 	dnsPolicy := corev1.DNSClusterFirst // same as k8s defaults
 	if tc.BaseTiKVSpec().HostNetwork() {
@@ -531,7 +592,11 @@ func volumeClaimTemplate(q resource.Quantity, metaName string, storageClassName
 
 func labelTiKV(tc *v1alpha1.TidbCluster) label.Label {
 	instanceName := tc.GetLabels()[label.InstanceLabelKey]
-	return label.New().Instance(instanceName).TiKV()
+	l := label.New().Instance(instanceName).TiKV()
+	if group := tikvGroupName(tc); group != "" {
+		l = l.TiKVGroup(group)
+	}
+	return l
 }
 
 func (tkmm *tikvMemberManager) syncTidbClusterStatus(tc *v1alpha1.TidbCluster, set *apps.StatefulSet) error {
@@ -544,11 +609,14 @@ func (tkmm *tikvMemberManager) syncTidbClusterStatus(tc *v1alpha1.TidbCluster, s
 		tc.Status.TiKV.Phase = v1alpha1.UpgradePhase
 	} else {
 		tc.Status.TiKV.Phase = v1alpha1.NormalPhase
+		tc.Status.TiKV.Upgrade = nil
 	}
 
 	previousStores := tc.Status.TiKV.Stores
 	stores := map[string]v1alpha1.TiKVStore{}
+	peerStores := map[string]v1alpha1.TiKVStore{}
 	tombstoneStores := map[string]v1alpha1.TiKVStore{}
+	peerServiceName := controller.TiKVPeerMemberName(tc.GetName())
 
 	pdCli := controller.GetPDClient(tkmm.pdControl, tc)
 	// This only returns Up/Down/Offline stores
@@ -559,10 +627,23 @@ func (tkmm *tikvMemberManager) syncTidbClusterStatus(tc *v1alpha1.TidbCluster, s
 	}
 
 	for _, store := range storesInfo.Stores {
-		status := tkmm.getTiKVStore(store)
+		status := getTiKVStore(store)
 		if status == nil {
 			continue
 		}
+		// the TiFlash stores are synced to status.tiflash
+		if isTiFlashStore(tc, store) {
+			continue
+		}
+		// the stores of the other clusters joining the same PD cluster
+		if isPeerMemberAddress(store.Store.GetAddress(), peerServiceName, tc.GetNamespace()) {
+			peerStores[status.ID] = *status
+			continue
+		}
+		// the stores of the other TiKV groups of the cluster
+		if !isTiKVSetPod(tc, status.PodName) {
+			continue
+		}
 		// avoid LastHeartbeatTime be overwrite by zero time when pd lost LastHeartbeatTime
 		if status.LastHeartbeatTime.IsZero() {
 			if oldStatus, ok := previousStores[status.ID]; ok {
@@ -577,6 +658,9 @@ func (tkmm *tikvMemberManager) syncTidbClusterStatus(tc *v1alpha1.TidbCluster, s
 		if exist && status.State == oldStore.State {
 			status.LastTransitionTime = oldStore.LastTransitionTime
 		}
+		if exist {
+			status.LastEvictLeaderDuration = oldStore.LastEvictLeaderDuration
+		}
 
 		stores[status.ID] = *status
 	}
@@ -588,8 +672,10 @@ func (tkmm *tikvMemberManager) syncTidbClusterStatus(tc *v1alpha1.TidbCluster, s
 		return err
 	}
 	for _, store := range tombstoneStoresInfo.Stores {
-		status := tkmm.getTiKVStore(store)
-		if status == nil {
+		status := getTiKVStore(store)
+		if status == nil || isTiFlashStore(tc, store) ||
+			isPeerMemberAddress(store.Store.GetAddress(), peerServiceName, tc.GetNamespace()) ||
+			!isTiKVSetPod(tc, status.PodName) {
 			continue
 		}
 		tombstoneStores[status.ID] = *status
@@ -598,10 +684,15 @@ func (tkmm *tikvMemberManager) syncTidbClusterStatus(tc *v1alpha1.TidbCluster, s
 	tc.Status.TiKV.Synced = true
 	tc.Status.TiKV.Stores = stores
 	tc.Status.TiKV.TombstoneStores = tombstoneStores
+	if len(peerStores) == 0 {
+		peerStores = nil
+	}
+	tc.Status.TiKV.PeerStores = peerStores
 	return nil
 }
 
-func (tkmm *tikvMemberManager) getTiKVStore(store *pdapi.StoreInfo) *v1alpha1.TiKVStore {
+// getTiKVStore returns the status of the TiKV or TiFlash store, the pod name is the first part of its address
+func getTiKVStore(store *pdapi.StoreInfo) *v1alpha1.TiKVStore {
 	if store.Store == nil || store.Status == nil {
 		return nil
 	}
@@ -614,6 +705,7 @@ func (tkmm *tikvMemberManager) getTiKVStore(store *pdapi.StoreInfo) *v1alpha1.Ti
 		PodName:           podName,
 		IP:                ip,
 		LeaderCount:       int32(store.Status.LeaderCount),
+		RegionCount:       int32(store.Status.RegionCount),
 		State:             store.Store.StateName,
 		LastHeartbeatTime: metav1.Time{Time: store.Status.LastHeartbeatTS},
 	}
@@ -635,14 +727,30 @@ func (tkmm *tikvMemberManager) setStoreLabelsForTiKV(tc *v1alpha1.TidbCluster) (
 		return setCount, err
 	}
 
-	locationLabels := []string(config.Replication.LocationLabels)
-	if locationLabels == nil {
+	reason, msg, err := syncLocationLabels(tc, pdCli, config)
+	if err != nil {
+		setLocationLabelsMismatch(tc, reason, msg)
+		return setCount, err
+	}
+
+	locationLabels := storeLocationLabels(tc, config)
+	if reason == "" {
+		reason, msg = haTopologyKeyMismatch(tc, locationLabels)
+	}
+	var groupLabels map[string]string
+	if group := tikvGroupSpec(tc); group != nil {
+		groupLabels = group.StoreLabels
+	}
+	if locationLabels == nil && len(groupLabels) == 0 {
+		setLocationLabelsMismatch(tc, reason, msg)
 		return setCount, nil
 	}
 
+	peerServiceName := controller.TiKVPeerMemberName(tc.GetName())
 	for _, store := range storesInfo.Stores {
-		status := tkmm.getTiKVStore(store)
-		if status == nil {
+		status := getTiKVStore(store)
+		if status == nil || isTiFlashStore(tc, store) || isPeerMemberAddress(store.Store.GetAddress(), peerServiceName, ns) ||
+			!isTiKVSetPod(tc, status.PodName) {
 			continue
 		}
 		podName := status.PodName
@@ -653,13 +761,23 @@ func (tkmm *tikvMemberManager) setStoreLabelsForTiKV(tc *v1alpha1.TidbCluster) (
 		}
 
 		nodeName := pod.Spec.NodeName
-		ls, err := tkmm.getNodeLabels(nodeName, locationLabels)
+		ls, err := getNodeLabels(tkmm.nodeLister, nodeName, locationLabels)
+		if missing := missingLocationLabels(locationLabels, ls); err == nil && reason == "" && len(missing) > 0 && len(tc.Spec.TiKV.LocationLabels) > 0 {
+			reason = "NodeLabelsMissing"
+			msg = fmt.Sprintf("node %s of %s has no labels for the location labels %v", nodeName, podName, missing)
+		}
 		if err != nil || len(ls) == 0 {
-			glog.Warningf("node: [%s] has no node labels, skipping set store labels for Pod: [%s/%s]", nodeName, ns, podName)
-			continue
+			if len(groupLabels) == 0 {
+				glog.Warningf("node: [%s] has no node labels, skipping set store labels for Pod: [%s/%s]", nodeName, ns, podName)
+				continue
+			}
+			ls = map[string]string{}
+		}
+		for k, v := range groupLabels {
+			ls[k] = v
 		}
 
-		if !tkmm.storeLabelsEqualNodeLabels(store.Store.Labels, ls) {
+		if !storeLabelsEqualNodeLabels(store.Store.Labels, ls) {
 			set, err := pdCli.SetStoreLabels(store.Store.Id, ls)
 			if err != nil {
 				glog.Warningf("failed to set pod: [%s/%s]'s store labels: %v", ns, podName, ls)
@@ -671,37 +789,14 @@ func (tkmm *tikvMemberManager) setStoreLabelsForTiKV(tc *v1alpha1.TidbCluster) (
 			}
 		}
 	}
+	setLocationLabelsMismatch(tc, reason, msg)
 
 	return setCount, nil
 }
 
-func (tkmm *tikvMemberManager) getNodeLabels(nodeName string, storeLabels []string) (map[string]string, error) {
-	node, err := tkmm.nodeLister.Get(nodeName)
-	if err != nil {
-		return nil, err
-	}
-	labels := map[string]string{}
-	ls := node.GetLabels()
-	for _, storeLabel := range storeLabels {
-		if value, found := ls[storeLabel]; found {
-			labels[storeLabel] = value
-			continue
-		}
-
-		// TODO after pd supports storeLabel containing slash character, these codes should be deleted
-		if storeLabel == "host" {
-			if host, found := ls[corev1.LabelHostname]; found {
-				labels[storeLabel] = host
-			}
-		}
-
-	}
-	return labels, nil
-}
-
 // storeLabelsEqualNodeLabels compares store labels with node labels
 // for historic reasons, PD stores TiKV labels as []*StoreLabel which is a key-value pair slice
-func (tkmm *tikvMemberManager) storeLabelsEqualNodeLabels(storeLabels []*metapb.StoreLabel, nodeLabels map[string]string) bool {
+func storeLabelsEqualNodeLabels(storeLabels []*metapb.StoreLabel, nodeLabels map[string]string) bool {
 	ls := map[string]string{}
 	for _, label := range storeLabels {
 		key := label.GetKey()
@@ -717,8 +812,7 @@ func tikvStatefulSetIsUpgrading(podLister corelisters.PodLister, pdControl pdapi
 	if statefulSetIsUpgrading(set) {
 		return true, nil
 	}
-	instanceName := tc.GetLabels()[label.InstanceLabelKey]
-	selector, err := label.New().Instance(instanceName).TiKV().Selector()
+	selector, err := labelTiKV(tc).Selector()
 	if err != nil {
 		return false, err
 	}
@@ -727,6 +821,10 @@ func tikvStatefulSetIsUpgrading(podLister corelisters.PodLister, pdControl pdapi
 		return false, err
 	}
 	for _, pod := range tikvPods {
+		// the pods of the TiKV groups also match the selector of the StatefulSet of spec.tikv
+		if pod.Labels[label.TiKVGroupLabelKey] != tikvGroupName(tc) {
+			continue
+		}
 		revisionHash, exist := pod.Labels[apps.ControllerRevisionHashLabelKey]
 		if !exist {
 			return false, nil
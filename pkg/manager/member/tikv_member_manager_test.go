@@ -483,6 +483,17 @@ func TestTiKVMemberManagerTiKVStatefulSetIsUpgrading(t *testing.T) {
 			errExpectFn:     errExpectNil,
 			expectUpgrading: false,
 		},
+		{
+			name:      "pod of a tikv group is not upgrading",
+			setUpdate: nil,
+			hasPod:    true,
+			updatePod: func(pod *corev1.Pod) {
+				pod.Labels[label.TiKVGroupLabelKey] = "hot"
+				pod.Labels[apps.ControllerRevisionHashLabelKey] = "v2"
+			},
+			errExpectFn:     errExpectNil,
+			expectUpgrading: false,
+		},
 	}
 
 	for i := range tests {
@@ -1362,6 +1373,129 @@ func TestTiKVMemberManagerSyncTidbClusterStatus(t *testing.T) {
 	}
 }
 
+func TestTiKVMemberManagerSyncTiKVGroups(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForPD()
+	tc.Status.PD.Members = map[string]v1alpha1.PDMember{
+		"pd-0": {Name: "pd-0", Health: true},
+		"pd-1": {Name: "pd-1", Health: true},
+		"pd-2": {Name: "pd-2", Health: true},
+	}
+	tc.Status.PD.StatefulSet = &apps.StatefulSetStatus{ReadyReplicas: 3}
+	tc.Spec.TiKVGroups = []v1alpha1.TiKVGroupSpec{
+		{
+			Name: "hot",
+			TiKVSpec: v1alpha1.TiKVSpec{
+				Resources: v1alpha1.Resources{
+					Requests: &v1alpha1.ResourceRequirement{
+						Storage: "500Gi",
+					},
+				},
+				Replicas:         2,
+				StorageClassName: "nvme",
+			},
+			StoreLabels: map[string]string{"disk": "nvme"},
+		},
+	}
+	ns := tc.Namespace
+	tcName := tc.Name
+
+	tkmm, _, _, pdClient, podIndexer, nodeIndexer := newFakeTiKVMemberManager(tc)
+	pdClient.AddReaction(pdapi.GetConfigActionType, func(action *pdapi.Action) (interface{}, error) {
+		return &pdapi.Config{
+			Replication: pdapi.ReplicationConfig{
+				LocationLabels: typeutil.StringSlice{"zone"},
+			},
+		}, nil
+	})
+	pdClient.AddReaction(pdapi.GetStoresActionType, func(action *pdapi.Action) (interface{}, error) {
+		return &pdapi.StoresInfo{
+			Stores: []*pdapi.StoreInfo{
+				{
+					Store: &pdapi.MetaStore{
+						Store: &metapb.Store{
+							Id:      1,
+							Address: "test-tikv-0.test-tikv-peer.default.svc:20160",
+						},
+						StateName: "Up",
+					},
+					Status: &pdapi.StoreStatus{},
+				},
+				{
+					Store: &pdapi.MetaStore{
+						Store: &metapb.Store{
+							Id:      2,
+							Address: "test-tikv-hot-0.test-tikv-peer.default.svc:20160",
+						},
+						StateName: "Up",
+					},
+					Status: &pdapi.StoreStatus{},
+				},
+			},
+		}, nil
+	})
+	pdClient.AddReaction(pdapi.GetTombStoneStoresActionType, func(action *pdapi.Action) (interface{}, error) {
+		return &pdapi.StoresInfo{Stores: []*pdapi.StoreInfo{}}, nil
+	})
+	storeLabels := map[uint64]map[string]string{}
+	pdClient.AddReaction(pdapi.SetStoreLabelsActionType, func(action *pdapi.Action) (interface{}, error) {
+		storeLabels[action.ID] = action.Labels
+		return true, nil
+	})
+	nodeIndexer.Add(&corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "node-1",
+			Labels: map[string]string{"zone": "zone-1"},
+		},
+	})
+	for _, podName := range []string{"test-tikv-0", "test-tikv-hot-0"} {
+		podIndexer.Add(&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: podName, Namespace: ns},
+			Spec:       corev1.PodSpec{NodeName: "node-1"},
+		})
+	}
+
+	// the StatefulSets are created
+	err := tkmm.Sync(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	set, err := tkmm.setLister.StatefulSets(ns).Get(controller.TiKVMemberName(tcName))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(set.Spec.Selector.MatchLabels).NotTo(HaveKey(label.TiKVGroupLabelKey))
+	groupSet, err := tkmm.setLister.StatefulSets(ns).Get("test-tikv-hot")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(*groupSet.Spec.Replicas).To(Equal(int32(2)))
+	g.Expect(groupSet.Spec.Selector.MatchLabels).To(HaveKeyWithValue(label.TiKVGroupLabelKey, "hot"))
+	g.Expect(groupSet.Spec.Template.Labels).To(HaveKeyWithValue(label.TiKVGroupLabelKey, "hot"))
+	g.Expect(groupSet.Spec.ServiceName).To(Equal(controller.TiKVPeerMemberName(tcName)))
+	g.Expect(*groupSet.Spec.VolumeClaimTemplates[0].Spec.StorageClassName).To(Equal("nvme"))
+	g.Expect(groupSet.Spec.Template.Spec.Containers[0].Image).To(Equal("tikv-test-image"))
+	g.Expect(groupSet.Spec.Template.Spec.Volumes).To(ContainElement(WithTransform(func(vol corev1.Volume) string {
+		if vol.ConfigMap == nil {
+			return ""
+		}
+		return vol.ConfigMap.Name
+	}, Equal("test-tikv-hot"))))
+	g.Expect(tc.Status.TiKVGroups).To(HaveKey("hot"))
+	g.Expect(tc.Status.TiKVGroups["hot"].StatefulSet).NotTo(BeNil())
+
+	// the stores are split into the status of the cluster and the groups
+	err = tkmm.Sync(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tc.Status.TiKV.Stores).To(HaveLen(1))
+	g.Expect(tc.Status.TiKV.Stores).To(HaveKey("1"))
+	g.Expect(tc.Status.TiKVGroups["hot"].Stores).To(HaveLen(1))
+	g.Expect(tc.Status.TiKVGroups["hot"].Stores).To(HaveKey("2"))
+	g.Expect(storeLabels[1]).To(Equal(map[string]string{"zone": "zone-1"}))
+	g.Expect(storeLabels[2]).To(Equal(map[string]string{"zone": "zone-1", "disk": "nvme"}))
+
+	// the status of removed groups is cleaned up
+	tc.Spec.TiKVGroups = nil
+	err = tkmm.Sync(tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tc.Status.TiKVGroups).To(BeNil())
+}
+
 func newFakeTiKVMemberManager(tc *v1alpha1.TidbCluster) (
 	*tikvMemberManager, *controller.FakeStatefulSetControl,
 	*controller.FakeServiceControl, *pdapi.FakePDClient, cache.Indexer, cache.Indexer) {
@@ -1390,6 +1524,7 @@ func newFakeTiKVMemberManager(tc *v1alpha1.TidbCluster) (
 		svcLister:    svcInformer.Lister(),
 		tikvScaler:   tikvScaler,
 		tikvUpgrader: tikvUpgrader,
+		tikvMigrator: NewFakeTiKVStorageMigrator(),
 	}
 	tmm.tikvStatefulSetIsUpgradingFn = tikvStatefulSetIsUpgrading
 	return tmm, setControl, svcControl, pdClient, podInformer.Informer().GetIndexer(), nodeInformer.Informer().GetIndexer()
@@ -1543,6 +1678,41 @@ func TestGetNewTiKVSetForTidbCluster(t *testing.T) {
 			},
 			testSts: testHostNetwork(t, false, v1.DNSClusterFirst),
 		},
+		{
+			name: "tikv block cache is not sized from the memory limit by default",
+			tc: v1alpha1.TidbCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "tc",
+					Namespace: "ns",
+				},
+				Spec: v1alpha1.TidbClusterSpec{
+					TiKV: v1alpha1.TiKVSpec{
+						Resources: v1alpha1.Resources{
+							Limits: &v1alpha1.ResourceRequirement{Memory: "32Gi"},
+						},
+					},
+				},
+			},
+			testSts: testBlockCacheCapacity(t, ""),
+		},
+		{
+			name: "tikv block cache is sized from the memory limit",
+			tc: v1alpha1.TidbCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "tc",
+					Namespace: "ns",
+				},
+				Spec: v1alpha1.TidbClusterSpec{
+					TiKV: v1alpha1.TiKVSpec{
+						Resources: v1alpha1.Resources{
+							Limits: &v1alpha1.ResourceRequirement{Memory: "32Gi"},
+						},
+						BlockCacheFromMemoryLimit: true,
+					},
+				},
+			},
+			testSts: testBlockCacheCapacity(t, "16384MB"),
+		},
 		// TODO add more tests
 	}
 
@@ -1557,6 +1727,20 @@ func TestGetNewTiKVSetForTidbCluster(t *testing.T) {
 	}
 }
 
+func testBlockCacheCapacity(t *testing.T, expected string) func(sts *apps.StatefulSet) {
+	return func(sts *apps.StatefulSet) {
+		capacity := ""
+		for _, env := range sts.Spec.Template.Spec.Containers[0].Env {
+			if env.Name == "BLOCK_CACHE_CAPACITY" {
+				capacity = env.Value
+			}
+		}
+		if capacity != expected {
+			t.Errorf("unexpected BLOCK_CACHE_CAPACITY %q, want %q", capacity, expected)
+		}
+	}
+}
+
 func TestTiKVInitContainers(t *testing.T) {
 	privileged := true
 	asRoot := false
@@ -73,7 +73,7 @@ func (tsd *tikvScaler) ScaleIn(tc *v1alpha1.TidbCluster, oldSet *apps.StatefulSe
 	}
 
 	// We need remove member from cluster before reducing statefulset replicas
-	podName := ordinalPodName(v1alpha1.TiKVMemberType, tcName, ordinal)
+	podName := tikvSetPodName(tc, ordinal)
 	pod, err := tsd.podLister.Pods(ns).Get(podName)
 	if err != nil {
 		resetReplicas(newSet, oldSet)
@@ -0,0 +1,394 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/record"
+	glog "k8s.io/klog"
+)
+
+const (
+	// migrationRegionBalanceRatio is the ratio of the average region count of the stores a migrated store
+	// should have, before the next old store is deleted
+	migrationRegionBalanceRatio = 0.8
+)
+
+// StorageMigrator migrates the stores of a component to another StorageClass
+type StorageMigrator interface {
+	Migrate(*v1alpha1.TidbCluster, *apps.StatefulSet, *apps.StatefulSet) error
+}
+
+type tikvStorageMigrator struct {
+	pdControl  pdapi.PDControlInterface
+	podLister  corelisters.PodLister
+	podControl controller.PodControlInterface
+	pvcLister  corelisters.PersistentVolumeClaimLister
+	pvcControl controller.PVCControlInterface
+	recorder   record.EventRecorder
+}
+
+// NewTiKVStorageMigrator returns a StorageMigrator which migrates the TiKV stores to the StorageClass in the spec
+// if spec.tikv.migrateStorageClass is set.
+// The volumeClaimTemplates of the statefulset can't be updated, so the PVCs on the new StorageClass are created
+// before the pods using them. A replacement store is added first, then the old stores are replaced one at a time:
+// the old store is deleted from PD, and the pod is recreated with a new PVC after the store becomes tombstone.
+// The next old store is deleted after PD rebalances the regions to the migrated stores.
+func NewTiKVStorageMigrator(pdControl pdapi.PDControlInterface,
+	podLister corelisters.PodLister,
+	podControl controller.PodControlInterface,
+	pvcLister corelisters.PersistentVolumeClaimLister,
+	pvcControl controller.PVCControlInterface,
+	recorder record.EventRecorder) StorageMigrator {
+	return &tikvStorageMigrator{
+		pdControl:  pdControl,
+		podLister:  podLister,
+		podControl: podControl,
+		pvcLister:  pvcLister,
+		pvcControl: pvcControl,
+		recorder:   recorder,
+	}
+}
+
+func (tsm *tikvStorageMigrator) Migrate(tc *v1alpha1.TidbCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	// the stores are migrated only to the StorageClass set in the spec explicitly
+	storageClassName := tc.Spec.TiKV.StorageClassName
+	if !tc.Spec.TiKV.MigrateStorageClass || storageClassName == "" {
+		if tc.Status.TiKV.Migration == nil {
+			return nil
+		}
+		storageClassName = tc.Status.TiKV.Migration.StorageClassName
+	}
+
+	// the PVC of the pod added by scaling out is created on the new StorageClass before the pod
+	if *newSet.Spec.Replicas > *oldSet.Spec.Replicas && tikvStorageClassName(oldSet) != storageClassName {
+		ready, err := tsm.ensurePVC(tc, oldSet, *oldSet.Spec.Replicas, storageClassName)
+		if err != nil || !ready {
+			resetReplicas(newSet, oldSet)
+			return err
+		}
+	}
+
+	// the stores are not migrated while tikv is upgrading or scaling
+	if tc.TiKVUpgrading() || !tc.Status.TiKV.Synced || *newSet.Spec.Replicas != *oldSet.Spec.Replicas {
+		return nil
+	}
+
+	pending, err := tsm.pendingOrdinals(oldSet, storageClassName)
+	if err != nil {
+		return err
+	}
+	migration := tc.Status.TiKV.Migration
+	if migration == nil {
+		if len(pending) == 0 {
+			return nil
+		}
+		tc.Status.TiKV.Migration = &v1alpha1.StorageMigrationProgress{
+			StorageClassName: storageClassName,
+			Phase:            v1alpha1.StorageMigrationRebalancing,
+			Replicas:         int32(len(pending)),
+			StartTime:        metav1.Now(),
+			Message:          "adding a replacement store",
+		}
+		tsm.recorder.Eventf(tc, corev1.EventTypeNormal, "StorageMigrationStarted",
+			"migrating %d tikv stores to StorageClass %s", len(pending), storageClassName)
+		glog.Infof("tidbcluster: [%s/%s]'s tikv stores %v are migrated to StorageClass %s", ns, tcName, pending, storageClassName)
+		return nil
+	}
+	if migration.StorageClassName != storageClassName {
+		glog.Infof("tidbcluster: [%s/%s]'s tikv stores are migrated to StorageClass %s instead of %s",
+			ns, tcName, storageClassName, migration.StorageClassName)
+		migration.StorageClassName = storageClassName
+		migration.Replicas = migration.MigratedReplicas + int32(len(pending))
+	}
+
+	if migration.Ordinal != nil {
+		return tsm.replaceStore(tc, oldSet, migration, storageClassName)
+	}
+	if len(pending) == 0 {
+		// the replacement store is removed by scaling in after the migration
+		tc.Status.TiKV.Migration = nil
+		tsm.recorder.Eventf(tc, corev1.EventTypeNormal, "StorageMigrated",
+			"%d tikv stores are migrated to StorageClass %s", migration.MigratedReplicas, storageClassName)
+		glog.Infof("tidbcluster: [%s/%s]'s tikv stores are migrated to StorageClass %s", ns, tcName, storageClassName)
+		return nil
+	}
+
+	// wait for the replacement store to be added and the regions to be rebalanced to the migrated stores
+	if *oldSet.Spec.Replicas != tc.TiKVStsDesiredReplicas() {
+		migration.Message = "adding a replacement store"
+		return nil
+	}
+	if msg, err := tsm.unbalancedStore(tc, oldSet, storageClassName); err != nil || msg != "" {
+		if msg != "" {
+			migration.Phase = v1alpha1.StorageMigrationRebalancing
+			migration.Message = msg
+		}
+		return err
+	}
+
+	ordinal := pending[len(pending)-1]
+	migration.Ordinal = &ordinal
+	migration.Phase = v1alpha1.StorageMigrationDeletingStore
+	migration.Message = fmt.Sprintf("deleting the store of %s", tikvSetPodName(tc, ordinal))
+	return tsm.replaceStore(tc, oldSet, migration, storageClassName)
+}
+
+// replaceStore deletes the store of the pod at migration.Ordinal from PD, and recreates the pod
+// with a new PVC on the StorageClass after the store becomes tombstone
+func (tsm *tikvStorageMigrator) replaceStore(tc *v1alpha1.TidbCluster, set *apps.StatefulSet,
+	migration *v1alpha1.StorageMigrationProgress, storageClassName string) error {
+	ns := tc.GetNamespace()
+	ordinal := *migration.Ordinal
+	podName := tikvSetPodName(tc, ordinal)
+	pvcName := ordinalPVCName(v1alpha1.TiKVMemberType, set.GetName(), ordinal)
+
+	pvc, err := tsm.pvcLister.PersistentVolumeClaims(ns).Get(pvcName)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	oldPVC := pvc != nil && pvcStorageClassName(pvc) != storageClassName
+
+	if migration.Phase == v1alpha1.StorageMigrationDeletingStore {
+		if store, ok := tikvStoreOfPod(tc, podName); ok {
+			if store.State != v1alpha1.TiKVStateOffline {
+				id, err := strconv.ParseUint(store.ID, 10, 64)
+				if err != nil {
+					return err
+				}
+				if err := controller.GetPDClient(tsm.pdControl, tc).DeleteStore(id); err != nil {
+					return err
+				}
+				glog.Infof("tikv storage migration: delete store %d of %s/%s", id, ns, podName)
+			}
+			migration.Message = fmt.Sprintf("waiting for the store %s of %s to become tombstone", store.ID, podName)
+			return nil
+		}
+		migration.Phase = v1alpha1.StorageMigrationRecreating
+		migration.Message = fmt.Sprintf("recreating %s with a new PVC", podName)
+	}
+
+	// the old PVC is deleted before the pod, it's removed after the pod is deleted
+	if oldPVC {
+		if pvc.DeletionTimestamp == nil {
+			if err := tsm.pvcControl.DeletePVC(tc, pvc); err != nil {
+				return err
+			}
+		}
+		pod, err := tsm.podLister.Pods(ns).Get(podName)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		if err == nil && pod.DeletionTimestamp == nil && podUsesPVC(pod, pvc) {
+			if err := tsm.podControl.DeletePod(tc, pod); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if pvc == nil {
+		// the pod recreated by the statefulset is pending until the new PVC is created
+		_, err := tsm.ensurePVC(tc, set, ordinal, storageClassName)
+		return err
+	}
+
+	store, ok := tikvStoreOfPod(tc, podName)
+	if !ok || store.State != v1alpha1.TiKVStateUp {
+		migration.Message = fmt.Sprintf("waiting for the new store of %s to be up", podName)
+		return nil
+	}
+	migration.Ordinal = nil
+	migration.MigratedReplicas++
+	migration.Phase = v1alpha1.StorageMigrationRebalancing
+	migration.Message = fmt.Sprintf("waiting for PD to rebalance the regions to the store %s of %s", store.ID, podName)
+	tsm.recorder.Eventf(tc, corev1.EventTypeNormal, "StoreReplaced",
+		"the store of %s is replaced by store %s on StorageClass %s", podName, store.ID, storageClassName)
+	return nil
+}
+
+// ensurePVC creates the PVC of the pod at ordinal on the StorageClass if it doesn't exist,
+// and returns whether the PVC is on the StorageClass
+func (tsm *tikvStorageMigrator) ensurePVC(tc *v1alpha1.TidbCluster, set *apps.StatefulSet, ordinal int32, storageClassName string) (bool, error) {
+	ns := tc.GetNamespace()
+	pvcName := ordinalPVCName(v1alpha1.TiKVMemberType, set.GetName(), ordinal)
+
+	pvc, err := tsm.pvcLister.PersistentVolumeClaims(ns).Get(pvcName)
+	if err == nil {
+		if pvcStorageClassName(pvc) == storageClassName && pvc.Annotations[label.AnnPVCDeferDeleting] == "" {
+			return true, nil
+		}
+		// the PVC left by scaling in is deleted, so that it's created on the StorageClass
+		if pvc.DeletionTimestamp == nil {
+			return false, tsm.pvcControl.DeletePVC(tc, pvc)
+		}
+		return false, nil
+	}
+	if !errors.IsNotFound(err) {
+		return false, err
+	}
+
+	vct := set.Spec.VolumeClaimTemplates[0]
+	newPVC := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pvcName,
+			Namespace: ns,
+			Labels:    map[string]string{},
+		},
+		Spec: *vct.Spec.DeepCopy(),
+	}
+	for k, v := range set.Spec.Selector.MatchLabels {
+		newPVC.Labels[k] = v
+	}
+	newPVC.Spec.StorageClassName = &storageClassName
+	if err := tsm.pvcControl.CreatePVC(tc, newPVC); err != nil {
+		return false, err
+	}
+	glog.Infof("tikv storage migration: create PVC %s/%s on StorageClass %s", ns, pvcName, storageClassName)
+	return false, nil
+}
+
+// pendingOrdinals returns the ordinals of the pods whose PVCs are not on the StorageClass, in ascending order
+func (tsm *tikvStorageMigrator) pendingOrdinals(set *apps.StatefulSet, storageClassName string) ([]int32, error) {
+	var pending []int32
+	for ordinal := int32(0); ordinal < *set.Spec.Replicas; ordinal++ {
+		pvcName := ordinalPVCName(v1alpha1.TiKVMemberType, set.GetName(), ordinal)
+		pvc, err := tsm.pvcLister.PersistentVolumeClaims(set.GetNamespace()).Get(pvcName)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if pvcStorageClassName(pvc) != storageClassName {
+			pending = append(pending, ordinal)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i] < pending[j] })
+	return pending, nil
+}
+
+// unbalancedStore returns why the migrated stores are not ready for deleting the next old store,
+// or empty if they are all up and have at least migrationRegionBalanceRatio of the average region count
+func (tsm *tikvStorageMigrator) unbalancedStore(tc *v1alpha1.TidbCluster, set *apps.StatefulSet, storageClassName string) (string, error) {
+	var total, count int32
+	for _, store := range tc.Status.TiKV.Stores {
+		if store.State == v1alpha1.TiKVStateUp {
+			total += store.RegionCount
+			count++
+		}
+	}
+	if count == 0 {
+		return "waiting for the stores to be up", nil
+	}
+	average := float64(total) / float64(count)
+
+	for ordinal := int32(0); ordinal < *set.Spec.Replicas; ordinal++ {
+		podName := tikvSetPodName(tc, ordinal)
+		pvcName := ordinalPVCName(v1alpha1.TiKVMemberType, set.GetName(), ordinal)
+		pvc, err := tsm.pvcLister.PersistentVolumeClaims(set.GetNamespace()).Get(pvcName)
+		if errors.IsNotFound(err) {
+			return fmt.Sprintf("waiting for the PVC of %s to be created", podName), nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if pvcStorageClassName(pvc) != storageClassName {
+			continue
+		}
+		store, ok := tikvStoreOfPod(tc, podName)
+		if !ok || store.State != v1alpha1.TiKVStateUp {
+			return fmt.Sprintf("waiting for the store of %s to be up", podName), nil
+		}
+		if float64(store.RegionCount) < average*migrationRegionBalanceRatio {
+			return fmt.Sprintf("waiting for PD to rebalance the regions to the store %s of %s, %d regions, %.0f on average",
+				store.ID, podName, store.RegionCount, average), nil
+		}
+	}
+	return "", nil
+}
+
+// tikvPodMigrating returns whether the pod of tikv or a tikv group is recreated with a new PVC by the storage migrator
+func tikvPodMigrating(tc *v1alpha1.TidbCluster, podName string) bool {
+	migrating := func(setName string, status v1alpha1.TiKVStatus) bool {
+		migration := status.Migration
+		return migration != nil && migration.Ordinal != nil && podName == fmt.Sprintf("%s-%d", setName, *migration.Ordinal)
+	}
+	if migrating(controller.TiKVMemberName(tc.GetName()), tc.Status.TiKV) {
+		return true
+	}
+	for group, status := range tc.Status.TiKVGroups {
+		if migrating(controller.TiKVGroupMemberName(tc.GetName(), group), status) {
+			return true
+		}
+	}
+	return false
+}
+
+func tikvStoreOfPod(tc *v1alpha1.TidbCluster, podName string) (v1alpha1.TiKVStore, bool) {
+	for _, store := range tc.Status.TiKV.Stores {
+		if store.PodName == podName {
+			return store, true
+		}
+	}
+	return v1alpha1.TiKVStore{}, false
+}
+
+func tikvStorageClassName(set *apps.StatefulSet) string {
+	if len(set.Spec.VolumeClaimTemplates) == 0 {
+		return ""
+	}
+	return pvcStorageClassName(&set.Spec.VolumeClaimTemplates[0])
+}
+
+func pvcStorageClassName(pvc *corev1.PersistentVolumeClaim) string {
+	if pvc.Spec.StorageClassName == nil {
+		return ""
+	}
+	return *pvc.Spec.StorageClassName
+}
+
+func podUsesPVC(pod *corev1.Pod, pvc *corev1.PersistentVolumeClaim) bool {
+	for _, vol := range pod.Spec.Volumes {
+		if vol.PersistentVolumeClaim != nil && vol.PersistentVolumeClaim.ClaimName == pvc.GetName() {
+			return true
+		}
+	}
+	return false
+}
+
+type fakeTiKVStorageMigrator struct{}
+
+// NewFakeTiKVStorageMigrator returns a fake StorageMigrator
+func NewFakeTiKVStorageMigrator() StorageMigrator {
+	return &fakeTiKVStorageMigrator{}
+}
+
+func (fsm *fakeTiKVStorageMigrator) Migrate(_ *v1alpha1.TidbCluster, _ *apps.StatefulSet, _ *apps.StatefulSet) error {
+	return nil
+}
@@ -0,0 +1,401 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestTiKVStorageMigratorMigrate(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name string
+		// migrateStorageClass is not set
+		disabled bool
+		// storageClassName is not set, the set uses the default StorageClass
+		defaultStorageClass bool
+		// the StorageClass of the PVCs of the ordinals, the PVC doesn't exist if it's empty
+		pvcStorageClasses []string
+		setReplicas       int32
+		migration         *v1alpha1.StorageMigrationProgress
+		// the region count of the stores of the ordinals, the store doesn't exist if it's negative
+		regionCounts      []int32
+		expectReplicas    int32
+		expectMigration   *v1alpha1.StorageMigrationProgress
+		expectStoreDelete bool
+		expectPVCs        []string
+		expectPodDeleted  bool
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+
+		kubeCli := kubefake.NewSimpleClientset()
+		podInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().Pods()
+		pvcInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().PersistentVolumeClaims()
+		pdControl := pdapi.NewFakePDControl(kubeCli)
+		podControl := controller.NewFakePodControl(podInformer)
+		pvcControl := controller.NewFakePVCControl(pvcInformer)
+		migrator := NewTiKVStorageMigrator(pdControl, podInformer.Lister(), podControl, pvcInformer.Lister(), pvcControl, record.NewFakeRecorder(10))
+
+		tc := newTidbClusterForPD()
+		tc.Spec.TiKV.MigrateStorageClass = !test.disabled
+		tc.Spec.TiKV.StorageClassName = "new"
+		if test.defaultStorageClass {
+			tc.Spec.TiKV.StorageClassName = ""
+		}
+		tc.Status.TiKV.Synced = true
+		tc.Status.TiKV.Migration = test.migration.DeepCopy()
+		tc.Status.TiKV.Stores = map[string]v1alpha1.TiKVStore{}
+		for i, count := range test.regionCounts {
+			if count < 0 {
+				continue
+			}
+			id := fmt.Sprintf("%d", i+1)
+			tc.Status.TiKV.Stores[id] = v1alpha1.TiKVStore{
+				ID:          id,
+				PodName:     tikvSetPodName(tc, int32(i)),
+				State:       v1alpha1.TiKVStateUp,
+				RegionCount: count,
+			}
+		}
+
+		oldSet := newStatefulSetForStorageMigrator(tc, "old", test.setReplicas)
+		newSet := newStatefulSetForStorageMigrator(tc, "new", tc.TiKVStsDesiredReplicas())
+		for i, storageClassName := range test.pvcStorageClasses {
+			if storageClassName == "" {
+				continue
+			}
+			pvc := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      ordinalPVCName(v1alpha1.TiKVMemberType, oldSet.GetName(), int32(i)),
+					Namespace: tc.GetNamespace(),
+				},
+				Spec: corev1.PersistentVolumeClaimSpec{StorageClassName: &test.pvcStorageClasses[i]},
+			}
+			pvcInformer.Informer().GetIndexer().Add(pvc)
+			podInformer.Informer().GetIndexer().Add(&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      tikvSetPodName(tc, int32(i)),
+					Namespace: tc.GetNamespace(),
+				},
+				Spec: corev1.PodSpec{
+					Volumes: []corev1.Volume{
+						{
+							Name: "tikv",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: pvc.GetName()},
+							},
+						},
+					},
+				},
+			})
+		}
+
+		storeDeleted := false
+		pdClient := controller.NewFakePDClient(pdControl, tc)
+		pdClient.AddReaction(pdapi.DeleteStoreActionType, func(action *pdapi.Action) (interface{}, error) {
+			storeDeleted = true
+			return nil, nil
+		})
+
+		err := migrator.Migrate(tc, oldSet, newSet)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(*newSet.Spec.Replicas).To(Equal(test.expectReplicas))
+		g.Expect(storeDeleted).To(Equal(test.expectStoreDelete))
+
+		migration := tc.Status.TiKV.Migration
+		if test.expectMigration == nil {
+			g.Expect(migration).To(BeNil())
+		} else {
+			g.Expect(migration).NotTo(BeNil())
+			g.Expect(migration.StorageClassName).To(Equal("new"))
+			g.Expect(migration.Phase).To(Equal(test.expectMigration.Phase))
+			g.Expect(migration.Ordinal).To(Equal(test.expectMigration.Ordinal))
+			g.Expect(migration.Replicas).To(Equal(test.expectMigration.Replicas))
+			g.Expect(migration.MigratedReplicas).To(Equal(test.expectMigration.MigratedReplicas))
+		}
+
+		pvcs := make([]string, len(test.expectPVCs))
+		for i := range test.expectPVCs {
+			pvcName := ordinalPVCName(v1alpha1.TiKVMemberType, oldSet.GetName(), int32(i))
+			pvc, err := pvcInformer.Lister().PersistentVolumeClaims(tc.GetNamespace()).Get(pvcName)
+			if err == nil {
+				pvcs[i] = *pvc.Spec.StorageClassName
+			}
+		}
+		g.Expect(pvcs).To(Equal(test.expectPVCs))
+
+		_, err = podInformer.Lister().Pods(tc.GetNamespace()).Get(tikvSetPodName(tc, 2))
+		g.Expect(err != nil).To(Equal(test.expectPodDeleted))
+	}
+
+	tests := []*testcase{
+		{
+			name:              "the stores are on the StorageClass",
+			pvcStorageClasses: []string{"new", "new", "new"},
+			setReplicas:       3,
+			regionCounts:      []int32{10, 10, 10},
+			expectReplicas:    3,
+			expectPVCs:        []string{"new", "new", "new"},
+		},
+		{
+			name:              "start the migration",
+			pvcStorageClasses: []string{"old", "old", "old"},
+			setReplicas:       3,
+			regionCounts:      []int32{10, 10, 10},
+			expectReplicas:    3,
+			expectMigration: &v1alpha1.StorageMigrationProgress{
+				Phase:    v1alpha1.StorageMigrationRebalancing,
+				Replicas: 3,
+			},
+			expectPVCs: []string{"old", "old", "old"},
+		},
+		{
+			name:              "the migration is not started without migrateStorageClass",
+			disabled:          true,
+			pvcStorageClasses: []string{"old", "old", "old"},
+			setReplicas:       3,
+			regionCounts:      []int32{10, 10, 10},
+			expectReplicas:    3,
+			expectPVCs:        []string{"old", "old", "old"},
+		},
+		{
+			name:                "the migration is not started to the default StorageClass",
+			defaultStorageClass: true,
+			pvcStorageClasses:   []string{"old", "old", "old"},
+			setReplicas:         3,
+			regionCounts:        []int32{10, 10, 10},
+			expectReplicas:      3,
+			expectPVCs:          []string{"old", "old", "old"},
+		},
+		{
+			name:              "a started migration is finished without migrateStorageClass",
+			disabled:          true,
+			pvcStorageClasses: []string{"old", "old", "old"},
+			setReplicas:       3,
+			migration: &v1alpha1.StorageMigrationProgress{
+				StorageClassName: "new",
+				Phase:            v1alpha1.StorageMigrationRebalancing,
+				Replicas:         3,
+			},
+			regionCounts:   []int32{10, 10, 10},
+			expectReplicas: 3,
+			expectMigration: &v1alpha1.StorageMigrationProgress{
+				Phase:    v1alpha1.StorageMigrationRebalancing,
+				Replicas: 3,
+			},
+			expectPVCs: []string{"old", "old", "old", "new"},
+		},
+		{
+			name:              "create the PVC of the replacement store before scaling out",
+			pvcStorageClasses: []string{"old", "old", "old"},
+			setReplicas:       3,
+			migration: &v1alpha1.StorageMigrationProgress{
+				StorageClassName: "new",
+				Phase:            v1alpha1.StorageMigrationRebalancing,
+				Replicas:         3,
+			},
+			regionCounts:   []int32{10, 10, 10},
+			expectReplicas: 3,
+			expectMigration: &v1alpha1.StorageMigrationProgress{
+				Phase:    v1alpha1.StorageMigrationRebalancing,
+				Replicas: 3,
+			},
+			expectPVCs: []string{"old", "old", "old", "new"},
+		},
+		{
+			name:              "scale out after the PVC of the replacement store is created",
+			pvcStorageClasses: []string{"old", "old", "old", "new"},
+			setReplicas:       3,
+			migration: &v1alpha1.StorageMigrationProgress{
+				StorageClassName: "new",
+				Phase:            v1alpha1.StorageMigrationRebalancing,
+				Replicas:         3,
+			},
+			regionCounts:   []int32{10, 10, 10},
+			expectReplicas: 4,
+			expectMigration: &v1alpha1.StorageMigrationProgress{
+				Phase:    v1alpha1.StorageMigrationRebalancing,
+				Replicas: 3,
+			},
+			expectPVCs: []string{"old", "old", "old", "new"},
+		},
+		{
+			name:              "wait for PD to rebalance the regions to the replacement store",
+			pvcStorageClasses: []string{"old", "old", "old", "new"},
+			setReplicas:       4,
+			migration: &v1alpha1.StorageMigrationProgress{
+				StorageClassName: "new",
+				Phase:            v1alpha1.StorageMigrationRebalancing,
+				Replicas:         3,
+			},
+			regionCounts:   []int32{10, 10, 10, 2},
+			expectReplicas: 4,
+			expectMigration: &v1alpha1.StorageMigrationProgress{
+				Phase:    v1alpha1.StorageMigrationRebalancing,
+				Replicas: 3,
+			},
+			expectPVCs: []string{"old", "old", "old", "new"},
+		},
+		{
+			name:              "delete the old store with the largest ordinal",
+			pvcStorageClasses: []string{"old", "old", "old", "new"},
+			setReplicas:       4,
+			migration: &v1alpha1.StorageMigrationProgress{
+				StorageClassName: "new",
+				Phase:            v1alpha1.StorageMigrationRebalancing,
+				Replicas:         3,
+			},
+			regionCounts:   []int32{10, 10, 10, 9},
+			expectReplicas: 4,
+			expectMigration: &v1alpha1.StorageMigrationProgress{
+				Phase:    v1alpha1.StorageMigrationDeletingStore,
+				Ordinal:  controller.Int32Ptr(2),
+				Replicas: 3,
+			},
+			expectStoreDelete: true,
+			expectPVCs:        []string{"old", "old", "old", "new"},
+		},
+		{
+			name:              "delete the old PVC and the pod after the store becomes tombstone",
+			pvcStorageClasses: []string{"old", "old", "old", "new"},
+			setReplicas:       4,
+			migration: &v1alpha1.StorageMigrationProgress{
+				StorageClassName: "new",
+				Phase:            v1alpha1.StorageMigrationDeletingStore,
+				Ordinal:          controller.Int32Ptr(2),
+				Replicas:         3,
+			},
+			regionCounts:   []int32{10, 10, -1, 10},
+			expectReplicas: 4,
+			expectMigration: &v1alpha1.StorageMigrationProgress{
+				Phase:    v1alpha1.StorageMigrationRecreating,
+				Ordinal:  controller.Int32Ptr(2),
+				Replicas: 3,
+			},
+			expectPVCs:       []string{"old", "old", "", "new"},
+			expectPodDeleted: true,
+		},
+		{
+			name:              "create the new PVC of the recreated pod",
+			pvcStorageClasses: []string{"old", "old", "", "new"},
+			setReplicas:       4,
+			migration: &v1alpha1.StorageMigrationProgress{
+				StorageClassName: "new",
+				Phase:            v1alpha1.StorageMigrationRecreating,
+				Ordinal:          controller.Int32Ptr(2),
+				Replicas:         3,
+			},
+			regionCounts:   []int32{10, 10, -1, 10},
+			expectReplicas: 4,
+			expectMigration: &v1alpha1.StorageMigrationProgress{
+				Phase:    v1alpha1.StorageMigrationRecreating,
+				Ordinal:  controller.Int32Ptr(2),
+				Replicas: 3,
+			},
+			expectPVCs:       []string{"old", "old", "new", "new"},
+			expectPodDeleted: true,
+		},
+		{
+			name:              "the store is replaced",
+			pvcStorageClasses: []string{"old", "old", "new", "new"},
+			setReplicas:       4,
+			migration: &v1alpha1.StorageMigrationProgress{
+				StorageClassName: "new",
+				Phase:            v1alpha1.StorageMigrationRecreating,
+				Ordinal:          controller.Int32Ptr(2),
+				Replicas:         3,
+			},
+			regionCounts:   []int32{10, 10, 0, 10},
+			expectReplicas: 4,
+			expectMigration: &v1alpha1.StorageMigrationProgress{
+				Phase:            v1alpha1.StorageMigrationRebalancing,
+				Replicas:         3,
+				MigratedReplicas: 1,
+			},
+			expectPVCs: []string{"old", "old", "new", "new"},
+		},
+		{
+			name:              "finish the migration",
+			pvcStorageClasses: []string{"new", "new", "new", "new"},
+			setReplicas:       4,
+			migration: &v1alpha1.StorageMigrationProgress{
+				StorageClassName: "new",
+				Phase:            v1alpha1.StorageMigrationRebalancing,
+				Replicas:         3,
+				MigratedReplicas: 3,
+			},
+			regionCounts:   []int32{10, 10, 10, 10},
+			expectReplicas: 4,
+			expectPVCs:     []string{"new", "new", "new", "new"},
+		},
+	}
+
+	for _, test := range tests {
+		testFn(test, t)
+	}
+}
+
+func TestTiKVPodMigrating(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForPD()
+	g.Expect(tikvPodMigrating(tc, "test-tikv-1")).To(BeFalse())
+
+	tc.Status.TiKV.Migration = &v1alpha1.StorageMigrationProgress{Ordinal: controller.Int32Ptr(1)}
+	g.Expect(tikvPodMigrating(tc, "test-tikv-1")).To(BeTrue())
+	g.Expect(tikvPodMigrating(tc, "test-tikv-0")).To(BeFalse())
+
+	tc.Status.TiKV.Migration = nil
+	tc.Status.TiKVGroups = map[string]v1alpha1.TiKVStatus{
+		"ssd": {Migration: &v1alpha1.StorageMigrationProgress{Ordinal: controller.Int32Ptr(0)}},
+	}
+	g.Expect(tikvPodMigrating(tc, controller.TiKVGroupMemberName("test", "ssd")+"-0")).To(BeTrue())
+}
+
+func newStatefulSetForStorageMigrator(tc *v1alpha1.TidbCluster, storageClassName string, replicas int32) *apps.StatefulSet {
+	return &apps.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      controller.TiKVMemberName(tc.GetName()),
+			Namespace: tc.GetNamespace(),
+		},
+		Spec: apps.StatefulSetSpec{
+			Replicas: controller.Int32Ptr(replicas),
+			Selector: &metav1.LabelSelector{
+				MatchLabels: label.New().Instance(tc.GetName()).TiKV().Labels(),
+			},
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.TiKVMemberType.String()},
+					Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: &storageClassName},
+				},
+			},
+		},
+	}
+}
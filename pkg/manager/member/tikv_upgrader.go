@@ -20,41 +20,45 @@ import (
 
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/metrics"
 	"github.com/pingcap/tidb-operator/pkg/pdapi"
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/record"
 	glog "k8s.io/klog"
 )
 
 const (
 	// EvictLeaderBeginTime is the key of evict Leader begin time
 	EvictLeaderBeginTime = "evictLeaderBeginTime"
-	// EvictLeaderTimeout is the timeout limit of evict leader
-	EvictLeaderTimeout = 3 * time.Minute
 )
 
 type tikvUpgrader struct {
 	pdControl  pdapi.PDControlInterface
 	podControl controller.PodControlInterface
 	podLister  corelisters.PodLister
+	recorder   record.EventRecorder
 }
 
 // NewTiKVUpgrader returns a tikv Upgrader
 func NewTiKVUpgrader(pdControl pdapi.PDControlInterface,
 	podControl controller.PodControlInterface,
-	podLister corelisters.PodLister) Upgrader {
+	podLister corelisters.PodLister,
+	recorder record.EventRecorder) Upgrader {
 	return &tikvUpgrader{
 		pdControl:  pdControl,
 		podControl: podControl,
 		podLister:  podLister,
+		recorder:   recorder,
 	}
 }
 
 func (tku *tikvUpgrader) Upgrade(tc *v1alpha1.TidbCluster, oldSet *apps.StatefulSet, newSet *apps.StatefulSet) error {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
-	if tc.Status.PD.Phase == v1alpha1.UpgradePhase {
+	if tc.Status.PD.Phase == v1alpha1.UpgradePhase || tikvUpgradeBlocked(tc) {
 		_, podSpec, err := GetLastAppliedConfig(oldSet)
 		if err != nil {
 			return err
@@ -72,7 +76,7 @@ func (tku *tikvUpgrader) Upgrade(tc *v1alpha1.TidbCluster, oldSet *apps.Stateful
 		return nil
 	}
 
-	if tc.Status.TiKV.StatefulSet.UpdateRevision == tc.Status.TiKV.StatefulSet.CurrentRevision {
+	if upgradeSettled(tc.Status.TiKV.StatefulSet, oldSet) {
 		return nil
 	}
 
@@ -87,12 +91,14 @@ func (tku *tikvUpgrader) Upgrade(tc *v1alpha1.TidbCluster, oldSet *apps.Stateful
 	}
 
 	setUpgradePartition(newSet, *oldSet.Spec.UpdateStrategy.RollingUpdate.Partition)
+	progress := getUpgradeProgress(&tc.Status.TiKV.Upgrade, tc.Status.TiKV.StatefulSet.UpdateRevision)
+	var upgraded int32
 	for i := tc.TiKVStsActualReplicas() - 1; i >= 0; i-- {
 		store := tku.getStoreByOrdinal(tc, i)
 		if store == nil {
 			continue
 		}
-		podName := TikvPodName(tcName, i)
+		podName := tikvSetPodName(tc, i)
 		pod, err := tku.podLister.Pods(ns).Get(podName)
 		if err != nil {
 			return err
@@ -105,25 +111,40 @@ func (tku *tikvUpgrader) Upgrade(tc *v1alpha1.TidbCluster, oldSet *apps.Stateful
 		if revision == tc.Status.TiKV.StatefulSet.UpdateRevision {
 
 			if pod.Status.Phase != corev1.PodRunning {
+				if upgradedPodUnhealthy(tc.Spec.TiKV.UpgradeStrategy, progress) {
+					return tku.rollback(tc, oldSet, newSet)
+				}
 				return controller.RequeueErrorf("tidbcluster: [%s/%s]'s upgraded tikv pod: [%s] is not running", ns, tcName, podName)
 			}
 			if store.State != v1alpha1.TiKVStateUp {
+				if upgradedPodUnhealthy(tc.Spec.TiKV.UpgradeStrategy, progress) {
+					return tku.rollback(tc, oldSet, newSet)
+				}
 				return controller.RequeueErrorf("tidbcluster: [%s/%s]'s upgraded tikv pod: [%s] is not all ready", ns, tcName, podName)
 			}
 
+			upgraded++
 			continue
 		}
 
+		if err := checkUpgradeGate(tc, v1alpha1.TiKVMemberType, tc.Spec.TiKV.UpgradeStrategy, progress, tc.TiKVStsActualReplicas(), upgraded); err != nil {
+			return err
+		}
 		return tku.upgradeTiKVPod(tc, i, newSet)
 	}
 
 	return nil
 }
 
+func (tku *tikvUpgrader) rollback(tc *v1alpha1.TidbCluster, oldSet, newSet *apps.StatefulSet) error {
+	return rollbackUpgrade(tc, v1alpha1.TiKVMemberType, tku.recorder, oldSet, newSet,
+		&tc.Status.TiKV.Upgrade, tc.Status.TiKV.StatefulSet.CurrentRevision)
+}
+
 func (tku *tikvUpgrader) upgradeTiKVPod(tc *v1alpha1.TidbCluster, ordinal int32, newSet *apps.StatefulSet) error {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
-	upgradePodName := TikvPodName(tcName, ordinal)
+	upgradePodName := tikvSetPodName(tc, ordinal)
 	upgradePod, err := tku.podLister.Pods(ns).Get(upgradePodName)
 	if err != nil {
 		return err
@@ -140,7 +161,11 @@ func (tku *tikvUpgrader) upgradeTiKVPod(tc *v1alpha1.TidbCluster, ordinal int32,
 				return tku.beginEvictLeader(tc, storeID, upgradePod)
 			}
 
-			if tku.readyToUpgrade(upgradePod, store) {
+			ready, err := tku.readyToUpgrade(tc, upgradePod, storeID)
+			if err != nil {
+				return err
+			}
+			if ready {
 				err := tku.endEvictLeader(tc, ordinal)
 				if err != nil {
 					return err
@@ -156,21 +181,85 @@ func (tku *tikvUpgrader) upgradeTiKVPod(tc *v1alpha1.TidbCluster, ordinal int32,
 	return controller.RequeueErrorf("tidbcluster: [%s/%s] no store status found for tikv pod: [%s]", ns, tcName, upgradePodName)
 }
 
-func (tku *tikvUpgrader) readyToUpgrade(upgradePod *corev1.Pod, store v1alpha1.TiKVStore) bool {
-	if store.LeaderCount == 0 {
-		return true
+// readyToUpgrade queries PD whether all the leaders are evicted from the store and none of its regions
+// would lose quorum when the store restarts, it stops waiting after the evict leader timeout of tc
+func (tku *tikvUpgrader) readyToUpgrade(tc *v1alpha1.TidbCluster, upgradePod *corev1.Pod, storeID uint64) (bool, error) {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	evictLeaderBeginTime, err := time.Parse(time.RFC3339, upgradePod.Annotations[EvictLeaderBeginTime])
+	if err != nil {
+		glog.Errorf("parse annotation:[%s] to time failed.", EvictLeaderBeginTime)
+		return false, nil
+	}
+
+	pdClient := controller.GetPDClient(tku.pdControl, tc)
+	storeInfo, err := pdClient.GetStore(storeID)
+	if err != nil {
+		return false, err
 	}
-	if evictLeaderBeginTimeStr, evicting := upgradePod.Annotations[EvictLeaderBeginTime]; evicting {
-		evictLeaderBeginTime, err := time.Parse(time.RFC3339, evictLeaderBeginTimeStr)
+	leaderCount := -1
+	if storeInfo.Status != nil {
+		leaderCount = storeInfo.Status.LeaderCount
+	}
+	if leaderCount == 0 {
+		healthy, err := storeRegionsHealthy(pdClient, storeID)
 		if err != nil {
-			glog.Errorf("parse annotation:[%s] to time failed.", EvictLeaderBeginTime)
-			return false
+			return false, err
+		}
+		if healthy {
+			recordEvictLeaderDuration(tc, storeID, evictLeaderBeginTime, metrics.EvictLeaderResultEvicted)
+			return true, nil
 		}
-		if time.Now().After(evictLeaderBeginTime.Add(EvictLeaderTimeout)) {
-			return true
+	}
+
+	if time.Now().After(evictLeaderBeginTime.Add(tc.TiKVEvictLeaderTimeout())) {
+		glog.Warningf("tidbcluster: [%s/%s]'s tikv store %d evict leader timeout, %d leaders left",
+			ns, tcName, storeID, leaderCount)
+		recordEvictLeaderDuration(tc, storeID, evictLeaderBeginTime, metrics.EvictLeaderResultTimeout)
+		return true, nil
+	}
+	return false, nil
+}
+
+// storeRegionsHealthy returns false if a region of the store has a down or pending peer on another store
+func storeRegionsHealthy(pdClient pdapi.PDClient, storeID uint64) (bool, error) {
+	regionsInfo, err := pdClient.GetUnhealthyRegions()
+	if err != nil {
+		return false, err
+	}
+	for _, region := range regionsInfo.Regions {
+		onStore := false
+		for _, peer := range region.Peers {
+			if peer.GetStoreId() == storeID {
+				onStore = true
+				break
+			}
 		}
+		if !onStore {
+			continue
+		}
+		for _, peer := range region.DownPeers {
+			if peer.GetPeer().GetStoreId() != storeID {
+				return false, nil
+			}
+		}
+		for _, peer := range region.PendingPeers {
+			if peer.GetStoreId() != storeID {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+func recordEvictLeaderDuration(tc *v1alpha1.TidbCluster, storeID uint64, beginTime time.Time, result string) {
+	duration := time.Since(beginTime)
+	metrics.TiKVEvictLeaderDuration.WithLabelValues(tc.GetNamespace(), tc.GetName(), result).Observe(duration.Seconds())
+	id := strconv.FormatUint(storeID, 10)
+	if store, ok := tc.Status.TiKV.Stores[id]; ok {
+		store.LastEvictLeaderDuration = &metav1.Duration{Duration: duration}
+		tc.Status.TiKV.Stores[id] = store
 	}
-	return false
 }
 
 func (tku *tikvUpgrader) beginEvictLeader(tc *v1alpha1.TidbCluster, storeID uint64, pod *corev1.Pod) error {
@@ -210,7 +299,7 @@ func (tku *tikvUpgrader) endEvictLeader(tc *v1alpha1.TidbCluster, ordinal int32)
 		return err
 	}
 
-	err = tku.pdControl.GetPDClient(pdapi.Namespace(tc.GetNamespace()), tc.GetName(), tc.Spec.EnableTLSCluster).EndEvictLeader(storeID)
+	err = controller.GetPDClient(tku.pdControl, tc).EndEvictLeader(storeID)
 	if err != nil {
 		glog.Errorf("tikv upgrader: failed to end evict leader storeID: %d ordinal: %d, %v", storeID, ordinal, err)
 		return err
@@ -220,7 +309,7 @@ func (tku *tikvUpgrader) endEvictLeader(tc *v1alpha1.TidbCluster, ordinal int32)
 }
 
 func (tku *tikvUpgrader) getStoreByOrdinal(tc *v1alpha1.TidbCluster, ordinal int32) *v1alpha1.TiKVStore {
-	podName := TikvPodName(tc.GetName(), ordinal)
+	podName := tikvSetPodName(tc, ordinal)
 	for _, store := range tc.Status.TiKV.Stores {
 		if store.PodName == podName {
 			return &store
@@ -20,6 +20,7 @@ import (
 	"time"
 
 	. "github.com/onsi/gomega"
+	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	"github.com/pingcap/tidb-operator/pkg/label"
@@ -28,9 +29,11 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	kubeinformers "k8s.io/client-go/informers"
 	podinformers "k8s.io/client-go/informers/core/v1"
 	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
 )
 
 const (
@@ -49,6 +52,7 @@ func TestTiKVUpgraderUpgrade(t *testing.T) {
 		beginEvictLeaderErr bool
 		endEvictLeaderErr   bool
 		updatePodErr        bool
+		unhealthyRegions    *pdapi.RegionsInfo
 		errExpectFn         func(*GomegaWithT, error)
 		expectFn            func(*GomegaWithT, *v1alpha1.TidbCluster, *apps.StatefulSet, map[string]*corev1.Pod)
 	}
@@ -88,6 +92,16 @@ func TestTiKVUpgraderUpgrade(t *testing.T) {
 			})
 		}
 
+		pdClient.AddReaction(pdapi.GetStoreActionType, func(action *pdapi.Action) (interface{}, error) {
+			store := tc.Status.TiKV.Stores[strconv.FormatUint(action.ID, 10)]
+			return &pdapi.StoreInfo{Status: &pdapi.StoreStatus{LeaderCount: int(store.LeaderCount)}}, nil
+		})
+		if test.unhealthyRegions != nil {
+			pdClient.AddReaction(pdapi.GetUnhealthyRegionsActionType, func(action *pdapi.Action) (interface{}, error) {
+				return test.unhealthyRegions, nil
+			})
+		}
+
 		tikvPods := getTiKVPods(oldSet)
 		if test.changePods != nil {
 			test.changePods(tikvPods)
@@ -186,6 +200,77 @@ func TestTiKVUpgraderUpgrade(t *testing.T) {
 			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet, pods map[string]*corev1.Pod) {
 				g.Expect(tc.Status.TiKV.Phase).To(Equal(v1alpha1.UpgradePhase))
 				g.Expect(*newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(2)))
+				g.Expect(tc.Status.TiKV.Stores["3"].LastEvictLeaderDuration).NotTo(BeNil())
+			},
+		},
+		{
+			name: "wait for the regions of the evicting store to be healthy",
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.PD.Phase = v1alpha1.NormalPhase
+				tc.Status.TiKV.Phase = v1alpha1.NormalPhase
+				tc.Status.TiKV.Synced = true
+				store := tc.Status.TiKV.Stores["3"]
+				store.LeaderCount = 0
+				tc.Status.TiKV.Stores["3"] = store
+			},
+			changeOldSet: func(oldSet *apps.StatefulSet) {
+				SetLastAppliedConfigAnnotation(oldSet)
+			},
+			changePods: func(pods []*corev1.Pod) {
+				for _, pod := range pods {
+					if pod.GetName() == TikvPodName(upgradeTcName, 2) {
+						pod.Annotations = map[string]string{EvictLeaderBeginTime: time.Now().Add(-1 * time.Minute).Format(time.RFC3339)}
+					}
+				}
+			},
+			beginEvictLeaderErr: false,
+			endEvictLeaderErr:   false,
+			updatePodErr:        false,
+			unhealthyRegions: &pdapi.RegionsInfo{
+				Count: 1,
+				Regions: []*pdapi.RegionInfo{{
+					ID:           1,
+					Peers:        []*metapb.Peer{{Id: 1, StoreId: 1}, {Id: 2, StoreId: 2}, {Id: 3, StoreId: 3}},
+					PendingPeers: []*metapb.Peer{{Id: 2, StoreId: 2}},
+				}},
+			},
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+			},
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet, pods map[string]*corev1.Pod) {
+				g.Expect(*newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(3)))
+				g.Expect(tc.Status.TiKV.Stores["3"].LastEvictLeaderDuration).To(BeNil())
+			},
+		},
+		{
+			name: "upgrade the pod with leaders left after the evict leader timeout of the cluster",
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.PD.Phase = v1alpha1.NormalPhase
+				tc.Status.TiKV.Phase = v1alpha1.NormalPhase
+				tc.Status.TiKV.Synced = true
+				timeout := "1m"
+				tc.Spec.TiKV.EvictLeaderTimeout = &timeout
+			},
+			changeOldSet: func(oldSet *apps.StatefulSet) {
+				SetLastAppliedConfigAnnotation(oldSet)
+			},
+			changePods: func(pods []*corev1.Pod) {
+				for _, pod := range pods {
+					if pod.GetName() == TikvPodName(upgradeTcName, 2) {
+						pod.Annotations = map[string]string{EvictLeaderBeginTime: time.Now().Add(-2 * time.Minute).Format(time.RFC3339)}
+					}
+				}
+			},
+			beginEvictLeaderErr: false,
+			endEvictLeaderErr:   false,
+			updatePodErr:        false,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet, pods map[string]*corev1.Pod) {
+				g.Expect(*newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(2)))
+				g.Expect(tc.Status.TiKV.Stores["3"].LastEvictLeaderDuration.Duration).To(BeNumerically(">=", time.Minute))
 			},
 		},
 		{
@@ -523,6 +608,117 @@ func TestTiKVUpgraderUpgrade(t *testing.T) {
 				g.Expect(*newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(2)))
 			},
 		},
+		{
+			name: "staged upgrade pauses after the canary pod",
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.PD.Phase = v1alpha1.NormalPhase
+				tc.Status.TiKV.Phase = v1alpha1.UpgradePhase
+				tc.Status.TiKV.Synced = true
+				tc.Status.TiKV.StatefulSet.CurrentReplicas = 2
+				tc.Status.TiKV.StatefulSet.UpdatedReplicas = 1
+				canary := intstr.FromInt(1)
+				tc.Spec.TiKV.UpgradeStrategy = &v1alpha1.UpgradeStrategy{Canary: &canary}
+			},
+			changeOldSet: func(oldSet *apps.StatefulSet) {
+				SetLastAppliedConfigAnnotation(oldSet)
+				oldSet.Status.CurrentReplicas = 2
+				oldSet.Status.UpdatedReplicas = 1
+				oldSet.Spec.UpdateStrategy.RollingUpdate.Partition = controller.Int32Ptr(2)
+			},
+			changePods:          nil,
+			beginEvictLeaderErr: false,
+			endEvictLeaderErr:   false,
+			updatePodErr:        false,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+			},
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet, pods map[string]*corev1.Pod) {
+				g.Expect(*newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(2)))
+				g.Expect(tc.Status.TiKV.Upgrade.Step).To(Equal(v1alpha1.UpgradeStepHealthCheck))
+				g.Expect(tc.Status.TiKV.Upgrade.UpgradedReplicas).To(Equal(int32(1)))
+				g.Expect(pods[TikvPodName(upgradeTcName, 1)].Annotations).NotTo(HaveKey(EvictLeaderBeginTime))
+			},
+		},
+		{
+			name: "staged upgrade continues after the canary is approved",
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.PD.Phase = v1alpha1.NormalPhase
+				tc.Status.TiKV.Phase = v1alpha1.UpgradePhase
+				tc.Status.TiKV.Synced = true
+				tc.Status.TiKV.StatefulSet.CurrentReplicas = 2
+				tc.Status.TiKV.StatefulSet.UpdatedReplicas = 1
+				canary := intstr.FromString("30%")
+				tc.Spec.TiKV.UpgradeStrategy = &v1alpha1.UpgradeStrategy{Canary: &canary, ManualApproval: true}
+				tc.Status.TiKV.Upgrade = &v1alpha1.UpgradeProgress{
+					Revision:     tc.Status.TiKV.StatefulSet.UpdateRevision,
+					Step:         v1alpha1.UpgradeStepHealthCheck,
+					HealthySince: &metav1.Time{Time: time.Now().Add(-2 * time.Minute)},
+				}
+				tc.Annotations = map[string]string{label.AnnUpgradeContinue: tc.Status.TiKV.StatefulSet.UpdateRevision}
+			},
+			changeOldSet: func(oldSet *apps.StatefulSet) {
+				SetLastAppliedConfigAnnotation(oldSet)
+				oldSet.Status.CurrentReplicas = 2
+				oldSet.Status.UpdatedReplicas = 1
+				oldSet.Spec.UpdateStrategy.RollingUpdate.Partition = controller.Int32Ptr(2)
+			},
+			changePods:          nil,
+			beginEvictLeaderErr: false,
+			endEvictLeaderErr:   false,
+			updatePodErr:        false,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet, pods map[string]*corev1.Pod) {
+				g.Expect(*newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(2)))
+				g.Expect(tc.Status.TiKV.Upgrade.Step).To(Equal(v1alpha1.UpgradeStepRolling))
+				g.Expect(pods[TikvPodName(upgradeTcName, 1)].Annotations).To(HaveKey(EvictLeaderBeginTime))
+			},
+		},
+		{
+			name: "upgrade is rolled back when the upgraded pod exceeds the health deadline",
+			changeFn: func(tc *v1alpha1.TidbCluster) {
+				tc.Status.PD.Phase = v1alpha1.NormalPhase
+				tc.Status.TiKV.Phase = v1alpha1.UpgradePhase
+				tc.Status.TiKV.Synced = true
+				tc.Status.TiKV.StatefulSet.CurrentReplicas = 2
+				tc.Status.TiKV.StatefulSet.UpdatedReplicas = 1
+				store := tc.Status.TiKV.Stores["3"]
+				store.State = v1alpha1.TiKVStateDown
+				tc.Status.TiKV.Stores["3"] = store
+				tc.Spec.TiKV.UpgradeStrategy = &v1alpha1.UpgradeStrategy{HealthDeadlineSeconds: controller.Int32Ptr(60)}
+				tc.Status.TiKV.Upgrade = &v1alpha1.UpgradeProgress{
+					Revision:       tc.Status.TiKV.StatefulSet.UpdateRevision,
+					Step:           v1alpha1.UpgradeStepRolling,
+					UnhealthySince: &metav1.Time{Time: time.Now().Add(-2 * time.Minute)},
+				}
+			},
+			changeOldSet: func(oldSet *apps.StatefulSet) {
+				SetLastAppliedConfigAnnotation(oldSet)
+				podSpec, _ := encode(corev1.PodSpec{Containers: []corev1.Container{{Name: "tikv", Image: "tikv-old-image"}}})
+				oldSet.Annotations[UpgradeRollbackConfigAnnotation] = podSpec
+				oldSet.Status.CurrentReplicas = 2
+				oldSet.Status.UpdatedReplicas = 1
+				oldSet.Spec.UpdateStrategy.RollingUpdate.Partition = controller.Int32Ptr(2)
+			},
+			changePods:          nil,
+			beginEvictLeaderErr: false,
+			endEvictLeaderErr:   false,
+			updatePodErr:        false,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet, pods map[string]*corev1.Pod) {
+				g.Expect(*newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(3)))
+				g.Expect(newSet.Spec.Template.Spec.Containers[0].Image).To(Equal("tikv-old-image"))
+				g.Expect(newSet.Annotations).To(HaveKey(FailedUpgradeConfigAnnotation))
+				g.Expect(tc.Status.TiKV.Upgrade.Revision).To(Equal("1"))
+				_, condition := v1alpha1.GetTidbClusterCondition(&tc.Status, v1alpha1.TidbClusterUpgradeFailed)
+				g.Expect(condition).NotTo(BeNil())
+				g.Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -539,6 +735,7 @@ func newTiKVUpgrader() (Upgrader, *pdapi.FakePDControl, *controller.FakePodContr
 		pdControl:  pdControl,
 		podControl: podControl,
 		podLister:  podInformer.Lister(),
+		recorder:   record.NewFakeRecorder(100),
 	}, pdControl, podControl, podInformer
 }
 
@@ -0,0 +1,283 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net"
+	"path"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	"github.com/pingcap/tidb-operator/pkg/manager"
+	"github.com/pingcap/tidb-operator/pkg/metrics"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/record"
+	glog "k8s.io/klog"
+)
+
+const (
+	// serviceAccountCAPath is the path of the Kubernetes cluster CA in pods
+	serviceAccountCAPath = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+)
+
+type tlsCertManager struct {
+	certControl controller.CertControlInterface
+	svcLister   corelisters.ServiceLister
+	recorder    record.EventRecorder
+}
+
+// NewTLSCertManager returns a manager which tracks the expiry of the TLS certificates issued for
+// the cluster, and renews them before they expire. The pods mounting a renewed certificate are
+// restarted by the upgraders of their components. The certificates whose SANs no longer cover the
+// hosts of the components, e.g. the ingress of the TiDB LoadBalancer, are renewed as well.
+func NewTLSCertManager(certControl controller.CertControlInterface, svcLister corelisters.ServiceLister, recorder record.EventRecorder) manager.Manager {
+	return &tlsCertManager{
+		certControl: certControl,
+		svcLister:   svcLister,
+		recorder:    recorder,
+	}
+}
+
+func (tcm *tlsCertManager) Sync(tc *v1alpha1.TidbCluster) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	var tidbSvc *corev1.Service
+	if tc.Spec.TiDB.EnableTLSClient {
+		svc, err := tcm.svcLister.Services(ns).Get(controller.TiDBMemberName(tcName))
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		if err == nil {
+			tidbSvc = svc
+		}
+	}
+
+	certs := map[string]v1alpha1.TLSCertStatus{}
+	var errs []error
+	for _, certOpts := range tlsCertOptionsForTidbCluster(tc, tidbSvc) {
+		secretName := certOpts.SecretName()
+		status, err := tcm.syncTLSCert(tc, certOpts)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if status != nil {
+			certs[secretName] = *status
+			metrics.TLSCertExpiryTimestamp.WithLabelValues(ns, tcName, secretName).Set(float64(status.NotAfter.Unix()))
+		}
+	}
+
+	for secretName := range tc.Status.TLSCerts {
+		if _, ok := certs[secretName]; !ok {
+			metrics.TLSCertExpiryTimestamp.DeleteLabelValues(ns, tcName, secretName)
+		}
+	}
+	if len(certs) == 0 {
+		certs = nil
+	}
+	tc.Status.TLSCerts = certs
+
+	if len(errs) > 0 {
+		return fmt.Errorf("tidbcluster: [%s/%s] failed to sync TLS certificates, %v", ns, tcName, errs)
+	}
+	return nil
+}
+
+// syncTLSCert returns the status of the certificate, which is renewed if it expires within tc.TLSCertRenewBefore
+// or its SANs do not cover certOpts, nil is returned if the certificate is not created yet
+func (tcm *tlsCertManager) syncTLSCert(tc *v1alpha1.TidbCluster, certOpts *controller.TiDBClusterCertOptions) (*v1alpha1.TLSCertStatus, error) {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	secretName := certOpts.SecretName()
+
+	status, exist := tc.Status.TLSCerts[secretName]
+	cert, err := tcm.certControl.GetCert(ns, secretName)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		if exist {
+			return &status, err
+		}
+		return nil, err
+	}
+
+	switch {
+	case exist && cert.NotBefore.After(status.NotBefore.Time) && cert.NotAfter.After(status.NotAfter.Time):
+		// the certificate is rotated by the issuer, e.g. cert-manager or the user providing the Secret
+		tcm.recordRenewal(tc, secretName, cert, &status)
+	case !exist || !cert.NotAfter.Before(status.NotAfter.Time):
+		// the Secret in the cache may not reflect the certificate renewed in the last round yet
+		status.NotBefore = metav1.NewTime(cert.NotBefore)
+		status.NotAfter = metav1.NewTime(cert.NotAfter)
+	}
+
+	renewBefore := tc.TLSCertRenewBefore()
+	missing := missingSANs(cert, certOpts)
+	if time.Until(status.NotAfter.Time) > renewBefore && len(missing) == 0 {
+		return &status, nil
+	}
+	if issuer := certOpts.IssuerType(); issuer != v1alpha1.TLSIssuerCSR && issuer != v1alpha1.TLSIssuerSelfSignedCA {
+		glog.Warningf("tidbcluster: [%s/%s]'s certificate in secret %s expires at %s and misses SANs %v, it should be renewed by the %s issuer",
+			ns, tcName, secretName, status.NotAfter, missing, issuer)
+		return &status, nil
+	}
+
+	if len(missing) > 0 {
+		glog.Infof("tidbcluster: [%s/%s]'s certificate in secret %s misses SANs %v, renewing", ns, tcName, secretName, missing)
+		tcm.recorder.Eventf(tc, corev1.EventTypeNormal, "TLSCertSANsChanged",
+			"renewing the certificate in secret %s to add SANs %v", secretName, missing)
+	} else {
+		glog.Infof("tidbcluster: [%s/%s]'s certificate in secret %s expires at %s, renewing", ns, tcName, secretName, status.NotAfter)
+	}
+	renewed, err := tcm.certControl.RenewCert(controller.GetOwnerRef(tc), certOpts)
+	if err != nil {
+		tcm.recorder.Eventf(tc, corev1.EventTypeWarning, "FailedRenewTLSCert",
+			"failed to renew the certificate in secret %s which expires at %s: %v", secretName, status.NotAfter, err)
+		return &status, err
+	}
+	tcm.recordRenewal(tc, secretName, renewed, &status)
+	return &status, nil
+}
+
+func (tcm *tlsCertManager) recordRenewal(tc *v1alpha1.TidbCluster, secretName string, renewed *x509.Certificate, status *v1alpha1.TLSCertStatus) {
+	now := metav1.Now()
+	status.NotBefore = metav1.NewTime(renewed.NotBefore)
+	status.NotAfter = metav1.NewTime(renewed.NotAfter)
+	status.LastRenewTime = &now
+	tcm.recorder.Eventf(tc, corev1.EventTypeNormal, "RenewedTLSCert",
+		"renewed the certificate in secret %s, which expires at %s now", secretName, status.NotAfter)
+}
+
+// missingSANs returns the hosts and IPs in certOpts which are not in the SANs of cert
+func missingSANs(cert *x509.Certificate, certOpts *controller.TiDBClusterCertOptions) []string {
+	sans := sets.NewString(cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		sans.Insert(ip.String())
+	}
+
+	var missing []string
+	for _, host := range certOpts.HostList {
+		if !sans.Has(host) {
+			missing = append(missing, host)
+		}
+	}
+	for _, ip := range certOpts.IPList {
+		if parsed := net.ParseIP(ip); parsed == nil || !sans.Has(parsed.String()) {
+			missing = append(missing, ip)
+		}
+	}
+	return missing
+}
+
+// tlsCertOptionsForTidbCluster returns the options of the TLS certificates issued for tc,
+// tidbSvc is the TiDB Service whose LoadBalancer ingress is added to the SANs of the TiDB server certificate
+func tlsCertOptionsForTidbCluster(tc *v1alpha1.TidbCluster, tidbSvc *corev1.Service) []*controller.TiDBClusterCertOptions {
+	var certs []*controller.TiDBClusterCertOptions
+	if tc.Spec.EnableTLSCluster {
+		certs = append(certs,
+			pdServerCertOptions(tc),
+			pdClientCertOptions(tc),
+			tikvServerCertOptions(tc),
+			tidbClusterCertOptions(tc),
+		)
+		if tc.Spec.TiFlash != nil {
+			certs = append(certs, tiflashServerCertOptions(tc))
+		}
+		if tc.Spec.Pump != nil {
+			certs = append(certs, pumpCertOptions(tc))
+		}
+		for _, drainer := range tc.Spec.Drainers {
+			certs = append(certs, drainerCertOptions(tc, drainer.Name))
+		}
+	}
+	if tc.Spec.TiDB.EnableTLSClient {
+		certs = append(certs,
+			tidbServerCertOptions(tc, tidbSvc),
+			tidbClientCertOptions(tc),
+		)
+		certs = append(certs, tidbUserCertOptions(tc)...)
+	}
+	return certs
+}
+
+// tlsCertRenewAnnotations returns the pod annotation of the last time any of the certificates
+// in the Secrets was renewed, so that the pods mounting them are restarted after the renewal
+func tlsCertRenewAnnotations(tc *v1alpha1.TidbCluster, secretNames ...string) map[string]string {
+	var renewTime *metav1.Time
+	for _, secretName := range secretNames {
+		status, ok := tc.Status.TLSCerts[secretName]
+		if !ok || status.LastRenewTime == nil {
+			continue
+		}
+		if renewTime == nil || renewTime.Before(status.LastRenewTime) {
+			renewTime = status.LastRenewTime
+		}
+	}
+	if renewTime == nil {
+		return nil
+	}
+	return map[string]string{label.AnnTLSCertRenewTime: renewTime.UTC().Format(time.RFC3339)}
+}
+
+// tlsSecretVolume returns the volume of the Secret of a TLS certificate. The kubernetes.io/tls Secrets provided
+// by the user or cert-manager are mapped to the layout of the Secrets created by tidb-operator, so that the
+// certificate, the key and the CA are always mounted as cert, key and ca
+func tlsSecretVolume(tc *v1alpha1.TidbCluster, volName string, secretName string) corev1.Volume {
+	source := &corev1.SecretVolumeSource{
+		SecretName: secretName,
+	}
+	switch tc.TLSIssuerType() {
+	case v1alpha1.TLSIssuerSecret, v1alpha1.TLSIssuerCertManager:
+		source.Items = []corev1.KeyToPath{
+			{Key: corev1.TLSCertKey, Path: controller.TLSSecretCertKey},
+			{Key: corev1.TLSPrivateKeyKey, Path: controller.TLSSecretKeyKey},
+			{Key: corev1.ServiceAccountRootCAKey, Path: controller.TLSSecretCAKey},
+		}
+	}
+	return corev1.Volume{Name: volName, VolumeSource: corev1.VolumeSource{Secret: source}}
+}
+
+// tlsCAPath returns the path of the CA certificate trusted by the component mounting its certificate at mountPath,
+// the certificates issued by the CSR API are signed by the Kubernetes cluster CA
+func tlsCAPath(tc *v1alpha1.TidbCluster, mountPath string) string {
+	if tc.TLSIssuerType() == v1alpha1.TLSIssuerCSR {
+		return serviceAccountCAPath
+	}
+	return path.Join(mountPath, controller.TLSSecretCAKey)
+}
+
+type FakeTLSCertManager struct {
+	err error
+}
+
+func NewFakeTLSCertManager() *FakeTLSCertManager {
+	return &FakeTLSCertManager{}
+}
+
+func (ftcm *FakeTLSCertManager) SetSyncError(err error) {
+	ftcm.err = err
+}
+
+func (ftcm *FakeTLSCertManager) Sync(_ *v1alpha1.TidbCluster) error {
+	return ftcm.err
+}
@@ -0,0 +1,259 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"crypto/x509"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestTLSCertManagerSync(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name          string
+		tlsDisabled   bool
+		certExpiresIn time.Duration
+		renewedStatus bool
+		rotated       bool
+		missingSANs   bool
+		issuer        v1alpha1.TLSIssuerType
+		renewErr      bool
+		errExpectFn   func(*GomegaWithT, error)
+		expectFn      func(*GomegaWithT, *v1alpha1.TidbCluster, []string)
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+
+		tc := newTidbClusterForPD()
+		tc.Spec.EnableTLSCluster = !test.tlsDisabled
+		if test.issuer != "" {
+			tc.Spec.TLSIssuer = &v1alpha1.TLSIssuer{Type: test.issuer}
+		}
+		secretName := controller.PDMemberName(tc.GetName())
+		now := time.Now()
+		if test.rotated {
+			tc.Status.TLSCerts = map[string]v1alpha1.TLSCertStatus{
+				secretName: {
+					NotBefore: metav1.NewTime(now.Add(-2 * 365 * 24 * time.Hour)),
+					NotAfter:  metav1.NewTime(now.Add(24 * time.Hour)),
+				},
+			}
+		}
+		if test.renewedStatus {
+			renewTime := metav1.NewTime(now.Add(-time.Minute))
+			tc.Status.TLSCerts = map[string]v1alpha1.TLSCertStatus{
+				secretName: {
+					NotBefore:     renewTime,
+					NotAfter:      metav1.NewTime(now.Add(365 * 24 * time.Hour)),
+					LastRenewTime: &renewTime,
+				},
+			}
+		}
+
+		tcm, certControl, recorder := newFakeTLSCertManager()
+		cert := &x509.Certificate{
+			NotBefore: now.Add(-365 * 24 * time.Hour),
+			NotAfter:  now.Add(test.certExpiresIn),
+			DNSNames:  pdServerCertOptions(tc).HostList,
+		}
+		if test.missingSANs {
+			cert.DNSNames = cert.DNSNames[:1]
+		}
+		certControl.SetCert(tc.GetNamespace(), secretName, cert)
+		if test.renewErr {
+			certControl.SetRenewCertError(fmt.Errorf("CSR is denied"), 0)
+		}
+
+		err := tcm.Sync(tc)
+		test.errExpectFn(g, err)
+
+		var events []string
+		close(recorder.Events)
+		for event := range recorder.Events {
+			events = append(events, event)
+		}
+		test.expectFn(g, tc, events)
+	}
+
+	tests := []testcase{
+		{
+			name:          "tls is disabled",
+			tlsDisabled:   true,
+			certExpiresIn: time.Hour,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, events []string) {
+				g.Expect(tc.Status.TLSCerts).To(BeNil())
+				g.Expect(events).To(BeEmpty())
+			},
+		},
+		{
+			name:          "certificate is not expiring",
+			certExpiresIn: 365 * 24 * time.Hour,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, events []string) {
+				g.Expect(tc.Status.TLSCerts).To(HaveLen(1))
+				status := tc.Status.TLSCerts["test-pd"]
+				g.Expect(time.Until(status.NotAfter.Time)).To(BeNumerically(">", 364*24*time.Hour))
+				g.Expect(status.LastRenewTime).To(BeNil())
+				g.Expect(events).To(BeEmpty())
+			},
+		},
+		{
+			name:          "certificate is renewed before expiry",
+			certExpiresIn: 24 * time.Hour,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, events []string) {
+				status := tc.Status.TLSCerts["test-pd"]
+				g.Expect(time.Until(status.NotAfter.Time)).To(BeNumerically(">", 364*24*time.Hour))
+				g.Expect(status.LastRenewTime).NotTo(BeNil())
+				g.Expect(events).To(HaveLen(1))
+				g.Expect(events[0]).To(ContainSubstring("RenewedTLSCert"))
+				g.Expect(tlsCertRenewAnnotations(tc, "test-pd")).To(HaveKey(label.AnnTLSCertRenewTime))
+			},
+		},
+		{
+			name:          "certificate misses SANs",
+			certExpiresIn: 365 * 24 * time.Hour,
+			missingSANs:   true,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, events []string) {
+				status := tc.Status.TLSCerts["test-pd"]
+				g.Expect(status.LastRenewTime).NotTo(BeNil())
+				g.Expect(events).To(HaveLen(2))
+				g.Expect(events[0]).To(ContainSubstring("TLSCertSANsChanged"))
+				g.Expect(events[0]).To(ContainSubstring("test-pd.default"))
+				g.Expect(events[1]).To(ContainSubstring("RenewedTLSCert"))
+			},
+		},
+		{
+			name:          "certificate renewed in the last round is not in the cache yet",
+			certExpiresIn: 24 * time.Hour,
+			renewedStatus: true,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, events []string) {
+				status := tc.Status.TLSCerts["test-pd"]
+				g.Expect(time.Until(status.NotAfter.Time)).To(BeNumerically(">", 364*24*time.Hour))
+				g.Expect(events).To(BeEmpty())
+			},
+		},
+		{
+			name:          "certificate is rotated by cert-manager",
+			certExpiresIn: 365 * 24 * time.Hour,
+			rotated:       true,
+			issuer:        v1alpha1.TLSIssuerCertManager,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, events []string) {
+				status := tc.Status.TLSCerts["test-pd"]
+				g.Expect(time.Until(status.NotAfter.Time)).To(BeNumerically(">", 364*24*time.Hour))
+				g.Expect(status.LastRenewTime).NotTo(BeNil())
+				g.Expect(events).To(HaveLen(1))
+				g.Expect(tlsCertRenewAnnotations(tc, "test-pd")).To(HaveKey(label.AnnTLSCertRenewTime))
+			},
+		},
+		{
+			name:          "certificate provided by the user is not renewed",
+			certExpiresIn: 24 * time.Hour,
+			issuer:        v1alpha1.TLSIssuerSecret,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, events []string) {
+				status := tc.Status.TLSCerts["test-pd"]
+				g.Expect(time.Until(status.NotAfter.Time)).To(BeNumerically("<", 24*time.Hour))
+				g.Expect(status.LastRenewTime).To(BeNil())
+				g.Expect(events).To(BeEmpty())
+			},
+		},
+		{
+			name:          "failed to renew certificate",
+			certExpiresIn: 24 * time.Hour,
+			renewErr:      true,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+			},
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, events []string) {
+				status := tc.Status.TLSCerts["test-pd"]
+				g.Expect(time.Until(status.NotAfter.Time)).To(BeNumerically("<", 24*time.Hour))
+				g.Expect(status.LastRenewTime).To(BeNil())
+				g.Expect(events).To(HaveLen(1))
+				g.Expect(events[0]).To(ContainSubstring("FailedRenewTLSCert"))
+			},
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
+
+func TestTLSSecretVolume(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForPD()
+	vol := tlsSecretVolume(tc, "pd-tls", "test-pd")
+	g.Expect(vol.Secret.SecretName).To(Equal("test-pd"))
+	g.Expect(vol.Secret.Items).To(BeEmpty())
+	g.Expect(tlsCAPath(tc, "/var/lib/pd-tls")).To(Equal(serviceAccountCAPath))
+
+	tc.Spec.TLSIssuer = &v1alpha1.TLSIssuer{Type: v1alpha1.TLSIssuerCertManager}
+	vol = tlsSecretVolume(tc, "pd-tls", "test-pd")
+	g.Expect(vol.Secret.Items).To(ConsistOf(
+		corev1.KeyToPath{Key: corev1.TLSCertKey, Path: "cert"},
+		corev1.KeyToPath{Key: corev1.TLSPrivateKeyKey, Path: "key"},
+		corev1.KeyToPath{Key: corev1.ServiceAccountRootCAKey, Path: "ca"},
+	))
+	g.Expect(tlsCAPath(tc, "/var/lib/pd-tls")).To(Equal("/var/lib/pd-tls/ca"))
+}
+
+func newFakeTLSCertManager() (*tlsCertManager, *controller.FakeCertControl, *record.FakeRecorder) {
+	kubeCli := kubefake.NewSimpleClientset()
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeCli, 0)
+	csrInformer := kubeInformerFactory.Certificates().V1beta1().CertificateSigningRequests()
+	secretInformer := kubeInformerFactory.Core().V1().Secrets()
+	secControl := controller.NewFakeSecretControl(kubeCli, secretInformer.Lister())
+	certControl := controller.NewFakeCertControl(kubeCli, csrInformer.Lister(), secControl)
+	svcInformer := kubeInformerFactory.Core().V1().Services()
+	recorder := record.NewFakeRecorder(10)
+	return &tlsCertManager{
+		certControl: certControl,
+		svcLister:   svcInformer.Lister(),
+		recorder:    recorder,
+	}, certControl, recorder
+}
@@ -0,0 +1,155 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	glog "k8s.io/klog"
+)
+
+const (
+	// FailedUpgradeConfigAnnotation is annotation key of the pod spec whose upgrade has been rolled back
+	FailedUpgradeConfigAnnotation = "pingcap.com/failed-upgrade-configuration"
+	// UpgradeRollbackConfigAnnotation is annotation key of the last applied pod spec before the upgrade,
+	// which the upgrade is rolled back to
+	UpgradeRollbackConfigAnnotation = "pingcap.com/upgrade-rollback-configuration"
+)
+
+// rollbackUpgrade reverts newSet's pod template to the pod spec oldSet applied before the upgrade,
+// and records the reverted pod spec as failed
+func rollbackUpgrade(tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType, recorder record.EventRecorder,
+	oldSet, newSet *apps.StatefulSet, progress **v1alpha1.UpgradeProgress, currentRevision string) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	rollbackConfig, ok := oldSet.Annotations[UpgradeRollbackConfigAnnotation]
+	if !ok {
+		recorder.Eventf(tc, corev1.EventTypeWarning, "UpgradeRollbackStuck",
+			"%s pods are unhealthy after the upgrade, but the pod spec before the upgrade is unknown", memberType)
+		return controller.RequeueErrorf("tidbcluster: [%s/%s]'s statefulset %s has no pod spec to roll back to", ns, tcName, oldSet.GetName())
+	}
+	podSpec := &corev1.PodSpec{}
+	if err := json.Unmarshal([]byte(rollbackConfig), podSpec); err != nil {
+		return err
+	}
+	failedConfig, err := encode(newSet.Spec.Template.Spec)
+	if err != nil {
+		return err
+	}
+	previousConfig, err := encode(podSpec)
+	if err != nil {
+		return err
+	}
+	if failedConfig == previousConfig {
+		// the pods are already being rolled back, there is nothing older to revert to
+		recorder.Eventf(tc, corev1.EventTypeWarning, "UpgradeRollbackStuck",
+			"%s pods are still unhealthy after rolling back to revision %s", memberType, currentRevision)
+		return controller.RequeueErrorf("tidbcluster: [%s/%s]'s %s pods are still unhealthy after rollback", ns, tcName, memberType)
+	}
+
+	if newSet.Annotations == nil {
+		newSet.Annotations = map[string]string{}
+	}
+	newSet.Annotations[FailedUpgradeConfigAnnotation] = failedConfig
+	newSet.Spec.Template.Spec = *podSpec
+	// hold all pods, the upgrader rolls them back one by one as an ordinary upgrade
+	setUpgradePartition(newSet, *newSet.Spec.Replicas)
+	*progress = &v1alpha1.UpgradeProgress{
+		Revision: currentRevision,
+		Step:     v1alpha1.UpgradeStepRolling,
+	}
+
+	msg := fmt.Sprintf("%s upgrade exceeded the health deadline, rolled back to revision %s", memberType, currentRevision)
+	v1alpha1.UpdateTidbClusterCondition(&tc.Status, &v1alpha1.TidbClusterCondition{
+		Type:    v1alpha1.TidbClusterUpgradeFailed,
+		Status:  corev1.ConditionTrue,
+		Reason:  upgradeFailedReason(memberType),
+		Message: msg,
+	})
+	recorder.Event(tc, corev1.EventTypeWarning, "UpgradeRolledBack", msg)
+	glog.Warningf("tidbcluster: [%s/%s] %s", ns, tcName, msg)
+	return nil
+}
+
+// setUpgradeRollbackAnnotations sets the annotations of the upgrade rollback on set, which is updated from oldSet
+// to newSet. The pod spec oldSet applied is kept as the pod spec to roll back to once the pod template changes
+// while no upgrade is in progress, reverting the pod template in a rollback keeps it unchanged
+func setUpgradeRollbackAnnotations(set, oldSet, newSet *apps.StatefulSet) {
+	if failedConfig, ok := newSet.Annotations[FailedUpgradeConfigAnnotation]; ok {
+		set.Annotations[FailedUpgradeConfigAnnotation] = failedConfig
+		return
+	}
+	if templateEqual(newSet.Spec.Template, oldSet.Spec.Template) || oldSet.Status.UpdateRevision != oldSet.Status.CurrentRevision {
+		return
+	}
+	if config, ok := oldSet.Spec.Template.Annotations[LastAppliedConfigAnnotation]; ok {
+		set.Annotations[UpgradeRollbackConfigAnnotation] = config
+	}
+}
+
+// keepUpgradeRolledBack keeps oldSet's pod template if newSet desires the pod spec of a rolled back upgrade,
+// a different pod spec starts a new upgrade and resets the UpgradeFailed condition
+func keepUpgradeRolledBack(tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType, newSet, oldSet *apps.StatefulSet) error {
+	failedConfig, ok := oldSet.Annotations[FailedUpgradeConfigAnnotation]
+	if !ok {
+		return nil
+	}
+	desiredConfig, err := encode(newSet.Spec.Template.Spec)
+	if err != nil {
+		return err
+	}
+	if desiredConfig == failedConfig {
+		newSet.Spec.Template = *oldSet.Spec.Template.DeepCopy()
+		if newSet.Annotations == nil {
+			newSet.Annotations = map[string]string{}
+		}
+		newSet.Annotations[FailedUpgradeConfigAnnotation] = failedConfig
+		return nil
+	}
+
+	delete(oldSet.Annotations, FailedUpgradeConfigAnnotation)
+	_, condition := v1alpha1.GetTidbClusterCondition(&tc.Status, v1alpha1.TidbClusterUpgradeFailed)
+	if condition != nil && condition.Reason == upgradeFailedReason(memberType) {
+		v1alpha1.UpdateTidbClusterCondition(&tc.Status, &v1alpha1.TidbClusterCondition{
+			Type:    v1alpha1.TidbClusterUpgradeFailed,
+			Status:  corev1.ConditionFalse,
+			Reason:  upgradeFailedReason(memberType),
+			Message: fmt.Sprintf("%s pod spec changed, upgrade again", memberType),
+		})
+	}
+	return nil
+}
+
+// upgradeSettled returns whether there is no pod to upgrade. A rollback reverts the pod template, so the update
+// revision of the statefulset equals the current revision again while the pods upgraded before the rollback are
+// still at the failed revision, they are rolled back by the upgrader as an ordinary upgrade until the pod spec changes
+func upgradeSettled(status *apps.StatefulSetStatus, set *apps.StatefulSet) bool {
+	if status.UpdateRevision != status.CurrentRevision {
+		return false
+	}
+	_, rolledBack := set.Annotations[FailedUpgradeConfigAnnotation]
+	return !rolledBack
+}
+
+func upgradeFailedReason(memberType v1alpha1.MemberType) string {
+	return fmt.Sprintf("%sHealthDeadlineExceeded", strings.Title(memberType.String()))
+}
@@ -0,0 +1,320 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestRollbackUpgrade(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name          string
+		rollbackImage string
+		errExpectFn   func(*GomegaWithT, error)
+		expectFn      func(*GomegaWithT, *v1alpha1.TidbCluster, *apps.StatefulSet)
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+
+		tc := newTidbClusterForTiKVUpgrader()
+		newSet := newStatefulSetForTiKVUpgrader()
+		newSet.Spec.UpdateStrategy.RollingUpdate.Partition = controller.Int32Ptr(1)
+
+		oldSet := oldStatefulSetForTiKVUpgrader()
+		oldSet.Annotations = map[string]string{}
+		if test.rollbackImage != "" {
+			podSpec, err := encode(corev1.PodSpec{Containers: []corev1.Container{{Name: "tikv", Image: test.rollbackImage}}})
+			g.Expect(err).NotTo(HaveOccurred())
+			oldSet.Annotations[UpgradeRollbackConfigAnnotation] = podSpec
+		}
+
+		err := rollbackUpgrade(tc, v1alpha1.TiKVMemberType, record.NewFakeRecorder(10), oldSet,
+			newSet, &tc.Status.TiKV.Upgrade, tc.Status.TiKV.StatefulSet.CurrentRevision)
+		test.errExpectFn(g, err)
+		test.expectFn(g, tc, newSet)
+	}
+
+	tests := []*testcase{
+		{
+			name:          "roll back to the pod spec before the upgrade",
+			rollbackImage: "tikv-old-image",
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+			},
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet) {
+				g.Expect(newSet.Spec.Template.Spec.Containers[0].Image).To(Equal("tikv-old-image"))
+				g.Expect(*newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(3)))
+				g.Expect(newSet.Annotations[FailedUpgradeConfigAnnotation]).To(ContainSubstring("tikv-test-image"))
+				g.Expect(tc.Status.TiKV.Upgrade.Revision).To(Equal("1"))
+				g.Expect(tc.Status.TiKV.Upgrade.Step).To(Equal(v1alpha1.UpgradeStepRolling))
+				_, condition := v1alpha1.GetTidbClusterCondition(&tc.Status, v1alpha1.TidbClusterUpgradeFailed)
+				g.Expect(condition).NotTo(BeNil())
+				g.Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+				g.Expect(condition.Reason).To(Equal("TikvHealthDeadlineExceeded"))
+			},
+		},
+		{
+			name:          "rollback is stuck when the pod template is already rolled back",
+			rollbackImage: "tikv-test-image",
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+			},
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet) {
+				g.Expect(*newSet.Spec.UpdateStrategy.RollingUpdate.Partition).To(Equal(int32(1)))
+				g.Expect(newSet.Annotations).NotTo(HaveKey(FailedUpgradeConfigAnnotation))
+			},
+		},
+		{
+			name:          "rollback is stuck without the pod spec before the upgrade",
+			rollbackImage: "",
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+			},
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet *apps.StatefulSet) {
+				g.Expect(newSet.Spec.Template.Spec.Containers[0].Image).To(Equal("tikv-test-image"))
+				g.Expect(newSet.Annotations).NotTo(HaveKey(FailedUpgradeConfigAnnotation))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		testFn(test, t)
+	}
+}
+
+func TestSetUpgradeRollbackAnnotations(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name      string
+		newImage  string
+		upgrading bool
+		failed    bool
+		expectFn  func(*GomegaWithT, *apps.StatefulSet)
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+
+		oldSet := oldStatefulSetForTiKVUpgrader()
+		oldSet.Spec.Template.Spec.Containers[0].Image = "tikv-old-image"
+		g.Expect(SetLastAppliedConfigAnnotation(oldSet)).To(Succeed())
+		oldSet.Status.CurrentRevision = "1"
+		oldSet.Status.UpdateRevision = "1"
+		if test.upgrading {
+			oldSet.Status.UpdateRevision = "2"
+		}
+		newSet := oldSet.DeepCopy()
+		newSet.Annotations = map[string]string{}
+		newSet.Spec.Template.Spec.Containers[0].Image = test.newImage
+		if test.failed {
+			newSet.Annotations[FailedUpgradeConfigAnnotation] = "failed"
+		}
+
+		set := *oldSet
+		set.Annotations = map[string]string{}
+		setUpgradeRollbackAnnotations(&set, oldSet, newSet)
+		test.expectFn(g, &set)
+	}
+
+	tests := []*testcase{
+		{
+			name:     "keep the pod spec before the upgrade",
+			newImage: "tikv-new-image",
+			expectFn: func(g *GomegaWithT, set *apps.StatefulSet) {
+				g.Expect(set.Annotations[UpgradeRollbackConfigAnnotation]).To(ContainSubstring("tikv-old-image"))
+				g.Expect(set.Annotations).NotTo(HaveKey(FailedUpgradeConfigAnnotation))
+			},
+		},
+		{
+			name:     "pod template is not changed",
+			newImage: "tikv-old-image",
+			expectFn: func(g *GomegaWithT, set *apps.StatefulSet) {
+				g.Expect(set.Annotations).NotTo(HaveKey(UpgradeRollbackConfigAnnotation))
+			},
+		},
+		{
+			name:      "pod template is changed during the upgrade",
+			newImage:  "tikv-new-image",
+			upgrading: true,
+			expectFn: func(g *GomegaWithT, set *apps.StatefulSet) {
+				g.Expect(set.Annotations).NotTo(HaveKey(UpgradeRollbackConfigAnnotation))
+			},
+		},
+		{
+			name:     "pod template is rolled back",
+			newImage: "tikv-new-image",
+			failed:   true,
+			expectFn: func(g *GomegaWithT, set *apps.StatefulSet) {
+				g.Expect(set.Annotations).NotTo(HaveKey(UpgradeRollbackConfigAnnotation))
+				g.Expect(set.Annotations[FailedUpgradeConfigAnnotation]).To(Equal("failed"))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		testFn(test, t)
+	}
+}
+
+func TestKeepUpgradeRolledBack(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name     string
+		newImage string
+		expectFn func(*GomegaWithT, *v1alpha1.TidbCluster, *apps.StatefulSet, *apps.StatefulSet)
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+
+		tc := newTidbClusterForTiKVUpgrader()
+		v1alpha1.UpdateTidbClusterCondition(&tc.Status, &v1alpha1.TidbClusterCondition{
+			Type:   v1alpha1.TidbClusterUpgradeFailed,
+			Status: corev1.ConditionTrue,
+			Reason: upgradeFailedReason(v1alpha1.TiKVMemberType),
+		})
+
+		oldSet := oldStatefulSetForTiKVUpgrader()
+		oldSet.Spec.Template.Spec.Containers[0].Image = "tikv-old-image"
+		failedConfig, err := encode(newStatefulSetForTiKVUpgrader().Spec.Template.Spec)
+		g.Expect(err).NotTo(HaveOccurred())
+		oldSet.Annotations = map[string]string{FailedUpgradeConfigAnnotation: failedConfig}
+
+		newSet := newStatefulSetForTiKVUpgrader()
+		newSet.Spec.Template.Spec.Containers[0].Image = test.newImage
+
+		err = keepUpgradeRolledBack(tc, v1alpha1.TiKVMemberType, newSet, oldSet)
+		g.Expect(err).NotTo(HaveOccurred())
+		test.expectFn(g, tc, newSet, oldSet)
+	}
+
+	tests := []*testcase{
+		{
+			name:     "keep the rolled back pod spec",
+			newImage: "tikv-test-image",
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet, oldSet *apps.StatefulSet) {
+				g.Expect(newSet.Spec.Template.Spec.Containers[0].Image).To(Equal("tikv-old-image"))
+				g.Expect(newSet.Annotations).To(HaveKey(FailedUpgradeConfigAnnotation))
+				_, condition := v1alpha1.GetTidbClusterCondition(&tc.Status, v1alpha1.TidbClusterUpgradeFailed)
+				g.Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+			},
+		},
+		{
+			name:     "a new pod spec resets the rollback",
+			newImage: "tikv-new-image",
+			expectFn: func(g *GomegaWithT, tc *v1alpha1.TidbCluster, newSet, oldSet *apps.StatefulSet) {
+				g.Expect(newSet.Spec.Template.Spec.Containers[0].Image).To(Equal("tikv-new-image"))
+				g.Expect(newSet.Annotations).NotTo(HaveKey(FailedUpgradeConfigAnnotation))
+				g.Expect(oldSet.Annotations).NotTo(HaveKey(FailedUpgradeConfigAnnotation))
+				_, condition := v1alpha1.GetTidbClusterCondition(&tc.Status, v1alpha1.TidbClusterUpgradeFailed)
+				g.Expect(condition.Status).To(Equal(corev1.ConditionFalse))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		testFn(test, t)
+	}
+}
+
+func TestTiDBUpgraderRollbackCompletes(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	upgrader, _, podInformer := newTiDBUpgrader()
+	tc := newTidbClusterForTiDBUpgrader()
+	tc.Labels = map[string]string{label.InstanceLabelKey: upgradeInstanceName}
+	deadline := int32(60)
+	tc.Spec.TiDB.UpgradeStrategy = &v1alpha1.UpgradeStrategy{HealthDeadlineSeconds: &deadline}
+	tc.Status.TiDB.Phase = v1alpha1.UpgradePhase
+	tc.Status.TiDB.Upgrade = &v1alpha1.UpgradeProgress{
+		Revision:       "2",
+		Step:           v1alpha1.UpgradeStepRolling,
+		UnhealthySince: &metav1.Time{Time: time.Now().Add(-time.Hour)},
+	}
+	// upgrader-tidb-1 is upgraded to the revision 2 and never becomes healthy
+	tc.Status.TiDB.Members["upgrader-tidb-1"] = v1alpha1.TiDBMember{Name: "upgrader-tidb-1", Health: false}
+
+	oldSpec := corev1.PodSpec{Containers: []corev1.Container{{Name: "tidb", Image: "tidb-old-image"}}}
+	oldConfig, err := encode(oldSpec)
+	g.Expect(err).NotTo(HaveOccurred())
+	revisions := map[string]string{oldConfig: "1"}
+
+	oldSet := newStatefulSetForTiDBUpgrader()
+	g.Expect(SetStatefulSetLastAppliedConfigAnnotation(oldSet)).To(Succeed())
+	oldSet.Annotations[UpgradeRollbackConfigAnnotation] = oldConfig
+	newConfig, err := encode(oldSet.Spec.Template.Spec)
+	g.Expect(err).NotTo(HaveOccurred())
+	revisions[newConfig] = "2"
+	for _, pod := range getTiDBPods() {
+		config := oldConfig
+		if pod.Labels[apps.ControllerRevisionHashLabelKey] == "2" {
+			config = newConfig
+		}
+		pod.Annotations = map[string]string{LastAppliedConfigAnnotation: config}
+		podInformer.Informer().GetIndexer().Add(pod)
+	}
+
+	// syncStatefulSet does what the statefulset controller does: the update revision follows the pod template,
+	// and the pods at or above the partition are recreated at the update revision
+	syncStatefulSet := func(set *apps.StatefulSet) {
+		config, err := encode(set.Spec.Template.Spec)
+		g.Expect(err).NotTo(HaveOccurred())
+		set.Spec.Template.Annotations = map[string]string{LastAppliedConfigAnnotation: config}
+		tc.Status.TiDB.StatefulSet.UpdateRevision = revisions[config]
+		for i := *set.Spec.UpdateStrategy.RollingUpdate.Partition; i < *set.Spec.Replicas; i++ {
+			obj, _, err := podInformer.Informer().GetIndexer().GetByKey(corev1.NamespaceDefault + "/" + tidbPodName(upgradeTcName, i))
+			g.Expect(err).NotTo(HaveOccurred())
+			pod := obj.(*corev1.Pod).DeepCopy()
+			pod.Labels[apps.ControllerRevisionHashLabelKey] = revisions[config]
+			pod.Annotations[LastAppliedConfigAnnotation] = config
+			podInformer.Informer().GetIndexer().Update(pod)
+			tc.Status.TiDB.Members[pod.GetName()] = v1alpha1.TiDBMember{Name: pod.GetName(), Health: revisions[config] == "1"}
+		}
+	}
+
+	for i := 0; i < 5; i++ {
+		newSet := oldSet.DeepCopy()
+		err := upgrader.Upgrade(tc, oldSet, newSet)
+		g.Expect(err).NotTo(HaveOccurred())
+		syncStatefulSet(newSet)
+		oldSet = newSet
+	}
+
+	g.Expect(oldSet.Annotations).To(HaveKey(FailedUpgradeConfigAnnotation))
+	g.Expect(oldSet.Spec.Template.Spec.Containers[0].Image).To(Equal("tidb-old-image"))
+	pods, err := podInformer.Lister().List(labels.Everything())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pods).To(HaveLen(2))
+	for _, pod := range pods {
+		g.Expect(pod.Labels[apps.ControllerRevisionHashLabelKey]).To(Equal("1"), pod.GetName())
+	}
+}
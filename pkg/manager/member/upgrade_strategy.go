@@ -0,0 +1,108 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	glog "k8s.io/klog"
+)
+
+// getUpgradeProgress returns the progress of the upgrade to revision,
+// the progress is reset if it belongs to another revision
+func getUpgradeProgress(progress **v1alpha1.UpgradeProgress, revision string) *v1alpha1.UpgradeProgress {
+	if *progress == nil || (*progress).Revision != revision {
+		*progress = &v1alpha1.UpgradeProgress{
+			Revision: revision,
+			Step:     v1alpha1.UpgradeStepCanary,
+		}
+	}
+	return *progress
+}
+
+// upgradedPodUnhealthy restarts the health window because an upgraded pod is not healthy,
+// it returns whether the upgraded pods have been unhealthy for longer than the health deadline
+func upgradedPodUnhealthy(strategy *v1alpha1.UpgradeStrategy, progress *v1alpha1.UpgradeProgress) bool {
+	progress.HealthySince = nil
+	now := time.Now()
+	if progress.UnhealthySince == nil {
+		progress.UnhealthySince = &metav1.Time{Time: now}
+	}
+	deadline, ok := strategy.HealthDeadline()
+	return ok && now.After(progress.UnhealthySince.Add(deadline))
+}
+
+// checkUpgradeGate is called before the next pod is upgraded, when all the upgraded pods are healthy.
+// It returns a RequeueError if the upgrade must pause after the canary.
+func checkUpgradeGate(tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType, strategy *v1alpha1.UpgradeStrategy,
+	progress *v1alpha1.UpgradeProgress, replicas int32, upgraded int32) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	progress.UpgradedReplicas = upgraded
+	progress.UnhealthySince = nil
+	if !strategy.Staged() {
+		progress.Step = v1alpha1.UpgradeStepRolling
+		return nil
+	}
+	progress.CanaryReplicas = strategy.CanaryReplicas(replicas)
+	if progress.Step == v1alpha1.UpgradeStepRolling {
+		return nil
+	}
+	if upgraded < progress.CanaryReplicas {
+		progress.Step = v1alpha1.UpgradeStepCanary
+		return nil
+	}
+
+	now := time.Now()
+	if progress.HealthySince == nil {
+		progress.HealthySince = &metav1.Time{Time: now}
+	}
+	deadline := progress.HealthySince.Add(strategy.HealthWindow())
+	if now.Before(deadline) {
+		progress.Step = v1alpha1.UpgradeStepHealthCheck
+		return controller.RequeueErrorf("tidbcluster: [%s/%s]'s %s canary pods are in health window until %s",
+			ns, tcName, memberType, deadline.Format(time.RFC3339))
+	}
+
+	if strategy.ManualApproval && !upgradeApproved(tc, progress.Revision) {
+		progress.Step = v1alpha1.UpgradeStepWaitingApproval
+		return controller.RequeueErrorf("tidbcluster: [%s/%s]'s %s upgrade is waiting for annotation %s: %s",
+			ns, tcName, memberType, label.AnnUpgradeContinue, progress.Revision)
+	}
+
+	glog.Infof("tidbcluster: [%s/%s]'s %s canary upgrade of revision %s succeeded, continue upgrading",
+		ns, tcName, memberType, progress.Revision)
+	progress.Step = v1alpha1.UpgradeStepRolling
+	return nil
+}
+
+// upgradeApproved returns whether revision is listed in the upgrade-continue annotation
+func upgradeApproved(tc *v1alpha1.TidbCluster, revision string) bool {
+	approved, ok := tc.Annotations[label.AnnUpgradeContinue]
+	if !ok {
+		return false
+	}
+	for _, r := range strings.Split(approved, ",") {
+		if strings.TrimSpace(r) == revision {
+			return true
+		}
+	}
+	return false
+}
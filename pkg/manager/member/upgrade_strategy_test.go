@@ -0,0 +1,131 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestCheckUpgradeGate(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name       string
+		strategy   *v1alpha1.UpgradeStrategy
+		progress   *v1alpha1.UpgradeProgress
+		approve    bool
+		upgraded   int32
+		expectErr  bool
+		expectStep v1alpha1.UpgradeStep
+	}
+
+	one := intstr.FromInt(1)
+	half := intstr.FromString("50%")
+	window := int32(60)
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+		tc := newTidbClusterForTiKVUpgrader()
+		if test.approve {
+			tc.Annotations = map[string]string{label.AnnUpgradeContinue: "1, 2"}
+		}
+		progress := getUpgradeProgress(&test.progress, "2")
+
+		err := checkUpgradeGate(tc, v1alpha1.TiKVMemberType, test.strategy, progress, 4, test.upgraded)
+		if test.expectErr {
+			g.Expect(controller.IsRequeueError(err)).To(BeTrue())
+		} else {
+			g.Expect(err).NotTo(HaveOccurred())
+		}
+		g.Expect(progress.Step).To(Equal(test.expectStep))
+		g.Expect(progress.UpgradedReplicas).To(Equal(test.upgraded))
+	}
+
+	tests := []testcase{
+		{
+			name:       "not staged",
+			strategy:   nil,
+			upgraded:   0,
+			expectStep: v1alpha1.UpgradeStepRolling,
+		},
+		{
+			name:       "canary is not finished",
+			strategy:   &v1alpha1.UpgradeStrategy{Canary: &half},
+			upgraded:   1,
+			expectStep: v1alpha1.UpgradeStepCanary,
+		},
+		{
+			name:       "canary finished, health window begins",
+			strategy:   &v1alpha1.UpgradeStrategy{Canary: &one, HealthWindowSeconds: &window},
+			upgraded:   1,
+			expectErr:  true,
+			expectStep: v1alpha1.UpgradeStepHealthCheck,
+		},
+		{
+			name:     "health window passed",
+			strategy: &v1alpha1.UpgradeStrategy{Canary: &one, HealthWindowSeconds: &window},
+			progress: &v1alpha1.UpgradeProgress{
+				Revision:     "2",
+				HealthySince: &metav1.Time{Time: time.Now().Add(-2 * time.Minute)},
+			},
+			upgraded:   1,
+			expectStep: v1alpha1.UpgradeStepRolling,
+		},
+		{
+			name:     "health window passed, waiting for approval",
+			strategy: &v1alpha1.UpgradeStrategy{Canary: &one, ManualApproval: true},
+			progress: &v1alpha1.UpgradeProgress{
+				Revision:     "2",
+				HealthySince: &metav1.Time{Time: time.Now().Add(-2 * time.Minute)},
+			},
+			upgraded:   1,
+			expectErr:  true,
+			expectStep: v1alpha1.UpgradeStepWaitingApproval,
+		},
+		{
+			name:     "health window passed and approved",
+			strategy: &v1alpha1.UpgradeStrategy{Canary: &one, ManualApproval: true},
+			progress: &v1alpha1.UpgradeProgress{
+				Revision:     "2",
+				HealthySince: &metav1.Time{Time: time.Now().Add(-2 * time.Minute)},
+			},
+			approve:    true,
+			upgraded:   1,
+			expectStep: v1alpha1.UpgradeStepRolling,
+		},
+		{
+			name:     "progress of a previous revision is reset",
+			strategy: &v1alpha1.UpgradeStrategy{Canary: &one},
+			progress: &v1alpha1.UpgradeProgress{
+				Revision: "1",
+				Step:     v1alpha1.UpgradeStepRolling,
+			},
+			upgraded:   1,
+			expectErr:  true,
+			expectStep: v1alpha1.UpgradeStepHealthCheck,
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
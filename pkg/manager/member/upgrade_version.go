@@ -0,0 +1,319 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/coreos/go-semver/semver"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/client-go/listers/apps/v1"
+	glog "k8s.io/klog"
+)
+
+// upgradeOrder is the order in which the components are upgraded, a component starts upgrading
+// after the components before it finish, and never runs a newer release series than them
+var upgradeOrder = []v1alpha1.MemberType{
+	v1alpha1.PDMemberType,
+	v1alpha1.TiKVMemberType,
+	v1alpha1.TiFlashMemberType,
+	v1alpha1.PumpMemberType,
+	v1alpha1.TiDBMemberType,
+	v1alpha1.DrainerMemberType,
+}
+
+// upgradePaths maps a release series to the oldest version that can be upgraded to it directly
+var upgradePaths = map[string]*semver.Version{
+	"3.0": semver.New("2.1.0"),
+	"3.1": semver.New("3.0.0"),
+	"4.0": semver.New("3.0.0"),
+}
+
+// checkUpgradeVersion is called before memberType is upgraded from the image of oldSet to the image of newSet.
+// newSet keeps the image of oldSet if the upgrade is rejected or has to wait for the other components.
+// Images without a semantic version tag, e.g. latest or nightly, are not checked.
+func checkUpgradeVersion(tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType, setLister v1.StatefulSetLister,
+	pdControl pdapi.PDControlInterface, oldSet, newSet *apps.StatefulSet) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	current := imageVersion(containerImage(oldSet.Spec.Template.Spec, memberType))
+	desired := imageVersion(containerImage(newSet.Spec.Template.Spec, memberType))
+	if current == nil || desired == nil || current.Equal(*desired) {
+		return nil
+	}
+
+	if msg := rejectUpgradeVersion(tc, memberType, current, desired); msg != "" {
+		v1alpha1.UpdateTidbClusterCondition(&tc.Status, &v1alpha1.TidbClusterCondition{
+			Type:    v1alpha1.TidbClusterUpgradeRejected,
+			Status:  corev1.ConditionTrue,
+			Reason:  upgradeRejectedReason(memberType),
+			Message: msg,
+		})
+		glog.Errorf("tidbcluster: [%s/%s] upgrade rejected, %s", ns, tcName, msg)
+		return keepOldImage(memberType, oldSet, newSet)
+	}
+	_, condition := v1alpha1.GetTidbClusterCondition(&tc.Status, v1alpha1.TidbClusterUpgradeRejected)
+	if condition != nil && condition.Reason == upgradeRejectedReason(memberType) {
+		v1alpha1.UpdateTidbClusterCondition(&tc.Status, &v1alpha1.TidbClusterCondition{
+			Type:    v1alpha1.TidbClusterUpgradeRejected,
+			Status:  corev1.ConditionFalse,
+			Reason:  upgradeRejectedReason(memberType),
+			Message: fmt.Sprintf("%s version %s is accepted", memberType, desired),
+		})
+	}
+
+	msg, err := waitUpgradeVersion(tc, memberType, setLister, pdControl, desired)
+	if err != nil {
+		return err
+	}
+	if msg != "" {
+		glog.Infof("tidbcluster: [%s/%s]'s %s upgrade to %s is waiting, %s", ns, tcName, memberType, desired, msg)
+		return keepOldImage(memberType, oldSet, newSet)
+	}
+	return nil
+}
+
+// rejectUpgradeVersion returns why memberType can never be upgraded from current to desired
+func rejectUpgradeVersion(tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType, current, desired *semver.Version) string {
+	if compareSeries(desired, current) > 0 {
+		oldest, ok := upgradePaths[releaseSeries(desired)]
+		if ok && compareSeries(current, oldest) < 0 {
+			return fmt.Sprintf("%s can not be upgraded from %s to %s directly, upgrade to %s first",
+				memberType, current, desired, releaseSeries(oldest))
+		}
+	}
+	for _, dep := range upgradeOrder {
+		if dep == memberType {
+			break
+		}
+		for _, image := range desiredImages(tc, dep) {
+			depDesired := imageVersion(image)
+			if depDesired != nil && compareSeries(desired, depDesired) > 0 {
+				return fmt.Sprintf("%s version %s is newer than %s version %s", memberType, desired, dep, depDesired)
+			}
+		}
+	}
+	return ""
+}
+
+// waitUpgradeVersion returns why the upgrade of memberType to desired has to wait
+func waitUpgradeVersion(tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType, setLister v1.StatefulSetLister,
+	pdControl pdapi.PDControlInterface, desired *semver.Version) (string, error) {
+	ns := tc.GetNamespace()
+
+	for _, dep := range upgradeOrder {
+		if dep == memberType {
+			break
+		}
+		for _, setName := range memberSetNames(tc, dep) {
+			set, err := setLister.StatefulSets(ns).Get(setName)
+			if errors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return "", err
+			}
+			if statefulSetIsUpgrading(set) {
+				return fmt.Sprintf("%s is upgrading", dep), nil
+			}
+			running := imageVersion(containerImage(set.Spec.Template.Spec, dep))
+			if running != nil && compareSeries(desired, running) > 0 {
+				return fmt.Sprintf("%s is running version %s", dep, running), nil
+			}
+		}
+	}
+
+	pdClient := controller.GetPDClient(pdControl, tc)
+	healthInfo, err := pdClient.GetHealth()
+	if err != nil {
+		return "", err
+	}
+	for _, member := range healthInfo.Healths {
+		if !member.Health {
+			return fmt.Sprintf("pd member %s is unhealthy", member.Name), nil
+		}
+	}
+	config, err := pdClient.GetConfig()
+	if err != nil {
+		return "", err
+	}
+	switch memberType {
+	case v1alpha1.TiKVMemberType:
+		var upStores uint64
+		for _, store := range tc.Status.TiKV.Stores {
+			if store.State == v1alpha1.TiKVStateUp {
+				upStores++
+			}
+		}
+		if upStores < config.Replication.MaxReplicas {
+			return fmt.Sprintf("%d tikv stores are up, less than max-replicas %d", upStores, config.Replication.MaxReplicas), nil
+		}
+	case v1alpha1.TiDBMemberType:
+		clusterVersion := config.ClusterVersion
+		if clusterVersion.Major+clusterVersion.Minor > 0 && compareSeries(desired, &clusterVersion) > 0 {
+			return fmt.Sprintf("pd cluster-version is %s", &clusterVersion), nil
+		}
+	}
+	return "", nil
+}
+
+// keepOldImage sets the image of the memberType container of newSet to the image in the last applied pod spec
+// of oldSet, the other changes of the pod spec are kept
+func keepOldImage(memberType v1alpha1.MemberType, oldSet, newSet *apps.StatefulSet) error {
+	_, podSpec, err := GetLastAppliedConfig(oldSet)
+	if err != nil {
+		return err
+	}
+	image := containerImage(*podSpec, memberType)
+	for i := range newSet.Spec.Template.Spec.Containers {
+		if newSet.Spec.Template.Spec.Containers[i].Name == memberType.String() {
+			newSet.Spec.Template.Spec.Containers[i].Image = image
+		}
+	}
+	return nil
+}
+
+// keepOldPodSpec sets newSet's pod spec to the last applied pod spec of oldSet
+func keepOldPodSpec(oldSet, newSet *apps.StatefulSet) error {
+	_, podSpec, err := GetLastAppliedConfig(oldSet)
+	if err != nil {
+		return err
+	}
+	newSet.Spec.Template.Spec = *podSpec
+	return nil
+}
+
+// imageVersion returns the version in the tag of image, or nil if the tag is not a semantic version
+func imageVersion(image string) *semver.Version {
+	name := image[strings.LastIndex(image, "/")+1:]
+	i := strings.LastIndex(name, ":")
+	if i < 0 {
+		return nil
+	}
+	v, err := semver.NewVersion(strings.TrimPrefix(name[i+1:], "v"))
+	if err != nil {
+		return nil
+	}
+	return v
+}
+
+func containerImage(podSpec corev1.PodSpec, memberType v1alpha1.MemberType) string {
+	for _, c := range podSpec.Containers {
+		if c.Name == memberType.String() {
+			return c.Image
+		}
+	}
+	return ""
+}
+
+// desiredImages returns the images in the spec of the StatefulSets of memberType, there is one for each drainer
+// and one for spec.tikv, spec.tidb and each of their groups
+func desiredImages(tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType) []string {
+	switch memberType {
+	case v1alpha1.PDMemberType:
+		return []string{tc.BasePDSpec().Image()}
+	case v1alpha1.TiKVMemberType:
+		images := []string{tc.BaseTiKVSpec().Image()}
+		for i := range tc.Spec.TiKVGroups {
+			images = append(images, tikvGroupView(tc, &tc.Spec.TiKVGroups[i]).BaseTiKVSpec().Image())
+		}
+		return images
+	case v1alpha1.TiFlashMemberType:
+		if spec, ok := tc.BaseTiFlashSpec(); ok {
+			return []string{spec.Image()}
+		}
+	case v1alpha1.TiDBMemberType:
+		images := []string{tc.BaseTiDBSpec().Image()}
+		for i := range tc.Spec.TiDBGroups {
+			images = append(images, tidbGroupView(tc, &tc.Spec.TiDBGroups[i]).BaseTiDBSpec().Image())
+		}
+		return images
+	case v1alpha1.PumpMemberType:
+		if spec, ok := tc.BasePumpSpec(); ok {
+			return []string{spec.Image()}
+		}
+	case v1alpha1.DrainerMemberType:
+		var images []string
+		for i := range tc.Spec.Drainers {
+			images = append(images, tc.BaseDrainerSpec(&tc.Spec.Drainers[i]).Image())
+		}
+		return images
+	}
+	return nil
+}
+
+// memberSetNames returns the names of the StatefulSets of memberType, there is one for each drainer
+// and one for spec.tikv, spec.tidb and each of their groups
+func memberSetNames(tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType) []string {
+	tcName := tc.GetName()
+	switch memberType {
+	case v1alpha1.PDMemberType:
+		return []string{controller.PDMemberName(tcName)}
+	case v1alpha1.TiKVMemberType:
+		names := []string{controller.TiKVMemberName(tcName)}
+		for _, group := range tc.Spec.TiKVGroups {
+			names = append(names, controller.TiKVGroupMemberName(tcName, group.Name))
+		}
+		return names
+	case v1alpha1.TiFlashMemberType:
+		return []string{controller.TiFlashMemberName(tcName)}
+	case v1alpha1.TiDBMemberType:
+		names := []string{controller.TiDBMemberName(tcName)}
+		for _, group := range tc.Spec.TiDBGroups {
+			names = append(names, controller.TiDBGroupMemberName(tcName, group.Name))
+		}
+		return names
+	case v1alpha1.PumpMemberType:
+		return []string{controller.PumpMemberName(tcName)}
+	case v1alpha1.DrainerMemberType:
+		var names []string
+		for _, drainer := range tc.Spec.Drainers {
+			names = append(names, controller.DrainerMemberName(tcName, drainer.Name))
+		}
+		return names
+	}
+	return nil
+}
+
+// compareSeries compares the release series, i.e. major and minor versions, of a and b
+func compareSeries(a, b *semver.Version) int {
+	if a.Major != b.Major {
+		if a.Major < b.Major {
+			return -1
+		}
+		return 1
+	}
+	if a.Minor != b.Minor {
+		if a.Minor < b.Minor {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+func releaseSeries(v *semver.Version) string {
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+func upgradeRejectedReason(memberType v1alpha1.MemberType) string {
+	return fmt.Sprintf("%sVersionNotAllowed", strings.Title(memberType.String()))
+}
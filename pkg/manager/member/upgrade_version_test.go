@@ -0,0 +1,235 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package member
+
+import (
+	"testing"
+
+	"github.com/coreos/go-semver/semver"
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCheckUpgradeVersion(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name           string
+		oldImage       string
+		newImage       string
+		tikvVersion    string
+		tikvUpgrading  bool
+		pdUnhealthy    bool
+		clusterVersion string
+		expectImage    string
+		expectRejected bool
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+
+		kubeCli := kubefake.NewSimpleClientset()
+		setInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Apps().V1().StatefulSets()
+		pdControl := pdapi.NewFakePDControl(kubeCli)
+
+		tc := newTidbClusterForTiKVUpgrader()
+		tc.Spec.TiKV.Image = "pingcap/tikv:" + test.tikvVersion
+		tc.Spec.TiDB.Image = test.newImage
+
+		tikvSet := newStatefulSetForUpgradeVersion(tc, v1alpha1.TiKVMemberType, "pingcap/tikv:"+test.tikvVersion)
+		if test.tikvUpgrading {
+			tikvSet.Status.UpdateRevision = "2"
+		}
+		setInformer.Informer().GetIndexer().Add(tikvSet)
+
+		pdClient := controller.NewFakePDClient(pdControl, tc)
+		pdClient.AddReaction(pdapi.GetHealthActionType, func(action *pdapi.Action) (interface{}, error) {
+			return &pdapi.HealthInfo{Healths: []pdapi.MemberHealth{{Name: "pd-0", Health: !test.pdUnhealthy}}}, nil
+		})
+		pdClient.AddReaction(pdapi.GetConfigActionType, func(action *pdapi.Action) (interface{}, error) {
+			config := &pdapi.Config{}
+			if test.clusterVersion != "" {
+				config.ClusterVersion = *semver.New(test.clusterVersion)
+			}
+			return config, nil
+		})
+
+		oldSet := newStatefulSetForUpgradeVersion(tc, v1alpha1.TiDBMemberType, test.oldImage)
+		newSet := newStatefulSetForUpgradeVersion(tc, v1alpha1.TiDBMemberType, test.newImage)
+		// the changes unrelated to the upgrade are kept even if the upgrade is rejected or waiting
+		env := []corev1.EnvVar{{Name: "TZ", Value: "UTC"}}
+		newSet.Spec.Template.Spec.Containers[0].Env = env
+		err := checkUpgradeVersion(tc, v1alpha1.TiDBMemberType, setInformer.Lister(), pdControl, oldSet, newSet)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(newSet.Spec.Template.Spec.Containers[0].Image).To(Equal(test.expectImage))
+		g.Expect(newSet.Spec.Template.Spec.Containers[0].Env).To(Equal(env))
+
+		_, condition := v1alpha1.GetTidbClusterCondition(&tc.Status, v1alpha1.TidbClusterUpgradeRejected)
+		if test.expectRejected {
+			g.Expect(condition).NotTo(BeNil())
+			g.Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+			g.Expect(condition.Reason).To(Equal("TidbVersionNotAllowed"))
+		} else {
+			g.Expect(condition).To(BeNil())
+		}
+	}
+
+	tests := []*testcase{
+		{
+			name:        "images without semantic version are not checked",
+			oldImage:    "pingcap/tidb:latest",
+			newImage:    "pingcap/tidb:nightly",
+			tikvVersion: "v3.0.0",
+			pdUnhealthy: true,
+			expectImage: "pingcap/tidb:nightly",
+		},
+		{
+			name:        "upgrade within the release series of tikv",
+			oldImage:    "pingcap/tidb:v3.0.0",
+			newImage:    "pingcap/tidb:v3.0.5",
+			tikvVersion: "v3.0.1",
+			expectImage: "pingcap/tidb:v3.0.5",
+		},
+		{
+			name:           "reject skipping a required release series",
+			oldImage:       "pingcap/tidb:v2.0.11",
+			newImage:       "pingcap/tidb:v3.0.0",
+			tikvVersion:    "v3.0.0",
+			expectImage:    "pingcap/tidb:v2.0.11",
+			expectRejected: true,
+		},
+		{
+			name:           "reject tidb newer than tikv",
+			oldImage:       "pingcap/tidb:v3.0.0",
+			newImage:       "pingcap/tidb:v3.1.0",
+			tikvVersion:    "v3.0.0",
+			expectImage:    "pingcap/tidb:v3.0.0",
+			expectRejected: true,
+		},
+		{
+			name:          "wait for tikv to finish upgrading",
+			oldImage:      "pingcap/tidb:v3.0.0",
+			newImage:      "pingcap/tidb:v3.1.0",
+			tikvVersion:   "v3.1.0",
+			tikvUpgrading: true,
+			expectImage:   "pingcap/tidb:v3.0.0",
+		},
+		{
+			name:        "wait for pd to be healthy",
+			oldImage:    "pingcap/tidb:v3.0.0",
+			newImage:    "pingcap/tidb:v3.1.0",
+			tikvVersion: "v3.1.0",
+			pdUnhealthy: true,
+			expectImage: "pingcap/tidb:v3.0.0",
+		},
+		{
+			name:           "wait for the cluster version reported by pd",
+			oldImage:       "pingcap/tidb:v3.0.0",
+			newImage:       "pingcap/tidb:v3.1.0",
+			tikvVersion:    "v3.1.0",
+			clusterVersion: "3.0.0",
+			expectImage:    "pingcap/tidb:v3.0.0",
+		},
+		{
+			name:           "upgrade after tikv and pd are ready",
+			oldImage:       "pingcap/tidb:v3.0.0",
+			newImage:       "pingcap/tidb:v3.1.0",
+			tikvVersion:    "v3.1.0",
+			clusterVersion: "3.1.0",
+			expectImage:    "pingcap/tidb:v3.1.0",
+		},
+	}
+
+	for _, test := range tests {
+		testFn(test, t)
+	}
+}
+
+func TestImageVersion(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(imageVersion("pingcap/tidb:v3.0.5").String()).To(Equal("3.0.5"))
+	g.Expect(imageVersion("localhost:5000/pingcap/tikv:3.1.0-beta").String()).To(Equal("3.1.0-beta"))
+	g.Expect(imageVersion("localhost:5000/pingcap/tikv")).To(BeNil())
+	g.Expect(imageVersion("pingcap/pd:latest")).To(BeNil())
+	g.Expect(imageVersion("pingcap/pd@sha256:0123456789abcdef")).To(BeNil())
+}
+
+func TestMemberSetNamesAndDesiredImages(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tc := newTidbClusterForPD()
+	g.Expect(memberSetNames(tc, v1alpha1.TiKVMemberType)).To(Equal([]string{"test-tikv"}))
+	g.Expect(desiredImages(tc, v1alpha1.PumpMemberType)).To(BeEmpty())
+	g.Expect(memberSetNames(tc, v1alpha1.DrainerMemberType)).To(BeEmpty())
+
+	tc.Spec.Version = "v3.0.8"
+	tc.Spec.Drainers = []v1alpha1.DrainerSpec{
+		{Name: "mysql", ComponentSpec: v1alpha1.ComponentSpec{BaseImage: "pingcap/tidb-binlog"}},
+		{Name: "kafka", ComponentSpec: v1alpha1.ComponentSpec{Image: "pingcap/tidb-binlog:v3.0.9"}},
+	}
+	g.Expect(memberSetNames(tc, v1alpha1.DrainerMemberType)).To(Equal([]string{"test-mysql-drainer", "test-kafka-drainer"}))
+	g.Expect(desiredImages(tc, v1alpha1.DrainerMemberType)).To(Equal([]string{"pingcap/tidb-binlog:v3.0.8", "pingcap/tidb-binlog:v3.0.9"}))
+
+	tc.Spec.TiKV.Image = "pingcap/tikv:v3.0.8"
+	tc.Spec.TiKVGroups = []v1alpha1.TiKVGroupSpec{
+		{Name: "hot"},
+		{Name: "cold", TiKVSpec: v1alpha1.TiKVSpec{ComponentSpec: v1alpha1.ComponentSpec{Image: "pingcap/tikv:v3.0.9"}}},
+	}
+	g.Expect(memberSetNames(tc, v1alpha1.TiKVMemberType)).To(Equal([]string{"test-tikv", "test-tikv-hot", "test-tikv-cold"}))
+	g.Expect(desiredImages(tc, v1alpha1.TiKVMemberType)).To(Equal([]string{"pingcap/tikv:v3.0.8", "pingcap/tikv:v3.0.8", "pingcap/tikv:v3.0.9"}))
+
+	tc.Spec.TiDB.Image = "pingcap/tidb:v3.0.8"
+	tc.Spec.TiDBGroups = []v1alpha1.TiDBGroupSpec{
+		{Name: "reporting", TiDBSpec: v1alpha1.TiDBSpec{ComponentSpec: v1alpha1.ComponentSpec{Image: "pingcap/tidb:v3.0.9"}}},
+	}
+	g.Expect(memberSetNames(tc, v1alpha1.TiDBMemberType)).To(Equal([]string{"test-tidb", "test-tidb-reporting"}))
+	g.Expect(desiredImages(tc, v1alpha1.TiDBMemberType)).To(Equal([]string{"pingcap/tidb:v3.0.8", "pingcap/tidb:v3.0.9"}))
+}
+
+func newStatefulSetForUpgradeVersion(tc *v1alpha1.TidbCluster, memberType v1alpha1.MemberType, image string) *apps.StatefulSet {
+	set := &apps.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      memberSetNames(tc, memberType)[0],
+			Namespace: tc.GetNamespace(),
+		},
+		Spec: apps.StatefulSetSpec{
+			Replicas: controller.Int32Ptr(3),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:  memberType.String(),
+							Image: image,
+						},
+					},
+				},
+			},
+		},
+		Status: apps.StatefulSetStatus{
+			Replicas:        3,
+			CurrentRevision: "1",
+			UpdateRevision:  "1",
+		},
+	}
+	SetLastAppliedConfigAnnotation(set)
+	return set
+}
@@ -16,6 +16,8 @@ package member
 import (
 	"encoding/json"
 	"fmt"
+	"net"
+	"net/url"
 	"strings"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
@@ -260,3 +262,43 @@ func FindPumpConfig(tcName string, vols []corev1.Volume) string {
 	}
 	return ""
 }
+
+// isPeerMemberAddress returns true if the address of a PD member or a TiKV store belongs to another TidbCluster
+// joining the same PD cluster. The members of a TidbCluster advertise the addresses <pod>.<peer service>.<namespace>.svc,
+// the addresses which are not in this form are considered to belong to the cluster itself.
+func isPeerMemberAddress(address string, peerServiceName string, ns string) bool {
+	parts := memberAddressParts(address)
+	if len(parts) < 3 {
+		return false
+	}
+	return parts[1] != peerServiceName || parts[2] != ns
+}
+
+// isMemberAddressOf returns true if the address is <pod>.<peer service>.<namespace>[.svc...] of the peer service
+func isMemberAddressOf(address string, peerServiceName string, ns string) bool {
+	parts := memberAddressParts(address)
+	return len(parts) >= 3 && parts[1] == peerServiceName && parts[2] == ns
+}
+
+// memberAddressParts returns the dot separated parts of the host of the address, or nil if the host is an IP
+func memberAddressParts(address string) []string {
+	host := address
+	if u, err := url.Parse(address); err == nil && u.Host != "" {
+		host = u.Hostname()
+	} else if h, _, err := net.SplitHostPort(address); err == nil {
+		host = h
+	}
+	if net.ParseIP(host) != nil {
+		return nil
+	}
+	return strings.Split(host, ".")
+}
+
+// clusterDomainEnv returns the environment variable of the Kubernetes cluster domain of the members,
+// which is appended to the addresses advertised by the members
+func clusterDomainEnv(tc *v1alpha1.TidbCluster) []corev1.EnvVar {
+	if tc.Spec.ClusterDomain == "" {
+		return nil
+	}
+	return []corev1.EnvVar{{Name: "CLUSTER_DOMAIN", Value: tc.Spec.ClusterDomain}}
+}
@@ -14,6 +14,7 @@
 package member
 
 import (
+	"strings"
 	"testing"
 
 	. "github.com/onsi/gomega"
@@ -132,3 +133,49 @@ func TestIsSubMapOf(t *testing.T) {
 			"k1": "v1",
 		})).To(BeFalse())
 }
+
+func TestIsPeerMemberAddress(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tests := []struct {
+		address string
+		peer    bool
+	}{
+		{"http://demo-pd-0.demo-pd-peer.default.svc:2379", false},
+		{"demo-pd-0.demo-pd-peer.default.svc.cluster1.com:2380", false},
+		{"demo-tikv-0.demo-tikv-peer.default.svc:20160", false},
+		{"http://demo-pd-0:2379", false},
+		{"10.0.0.1:20160", false},
+		{"http://demo-pd-0.demo-pd-peer.other.svc:2379", true},
+		{"origin-pd-0.origin-pd-peer.default.svc.cluster2.com:2380", true},
+		{"origin-tikv-0.origin-tikv-peer.default.svc:20160", true},
+	}
+
+	for _, test := range tests {
+		peerServiceName := "demo-pd-peer"
+		if strings.Contains(test.address, "tikv") {
+			peerServiceName = "demo-tikv-peer"
+		}
+		g.Expect(isPeerMemberAddress(test.address, peerServiceName, "default")).To(Equal(test.peer), test.address)
+	}
+}
+
+func TestIsMemberAddressOf(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tests := []struct {
+		address string
+		member  bool
+	}{
+		{"demo-tiflash-0.demo-tiflash-peer.default.svc:3930", true},
+		{"demo-tiflash-0.demo-tiflash-peer.default.svc.cluster1.com:3930", true},
+		{"demo-tikv-0.demo-tikv-peer.default.svc:20160", false},
+		{"demo-tiflash-0.demo-tiflash-peer.other.svc:3930", false},
+		{"demo-tiflash-0:3930", false},
+		{"10.0.0.1:3930", false},
+	}
+
+	for _, test := range tests {
+		g.Expect(isMemberAddressOf(test.address, "demo-tiflash-peer", "default")).To(Equal(test.member), test.address)
+	}
+}
@@ -73,9 +73,10 @@ func (pmm *metaManager) Sync(tc *v1alpha1.TidbCluster) error {
 		if err != nil {
 			return err
 		}
-		if component := pod.Labels[label.ComponentLabelKey]; component != label.PDLabelVal && component != label.TiKVLabelVal {
+		if component := pod.Labels[label.ComponentLabelKey]; component != label.PDLabelVal && component != label.TiKVLabelVal &&
+			component != label.TiFlashLabelVal {
 			// Skip syncing meta info for pod that doesn't use PV
-			// Currently only PD/TiKV uses PV
+			// Currently only PD/TiKV/TiFlash uses PV
 			continue
 		}
 		// update meta info for pvc
@@ -108,7 +109,7 @@ func (pmm *metaManager) resolvePVCFromPod(pod *corev1.Pod) (*corev1.PersistentVo
 	var pvcName string
 	for _, vol := range pod.Spec.Volumes {
 		switch vol.Name {
-		case v1alpha1.PDMemberType.String(), v1alpha1.TiKVMemberType.String():
+		case v1alpha1.PDMemberType.String(), v1alpha1.TiKVMemberType.String(), v1alpha1.TiFlashMemberType.String():
 			if vol.PersistentVolumeClaim != nil {
 				pvcName = vol.PersistentVolumeClaim.ClaimName
 				break
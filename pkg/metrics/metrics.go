@@ -0,0 +1,53 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	namespace = "tidb_operator"
+
+	// EvictLeaderResultEvicted means all the leaders were evicted from the store
+	EvictLeaderResultEvicted = "evicted"
+	// EvictLeaderResultTimeout means the eviction timed out with leaders left on the store
+	EvictLeaderResultTimeout = "timeout"
+)
+
+var (
+	// TiKVEvictLeaderDuration is the duration of evicting leaders from a TiKV store before it is upgraded
+	TiKVEvictLeaderDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "tikv",
+			Name:      "evict_leader_duration_seconds",
+			Help:      "Bucketed histogram of the duration of evicting leaders from a TiKV store during upgrade.",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 12),
+		}, []string{"namespace", "tidbcluster", "result"})
+
+	// TLSCertExpiryTimestamp is the expiry time of the TLS certificates issued for a TidbCluster
+	TLSCertExpiryTimestamp = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "tls",
+			Name:      "cert_expiry_timestamp_seconds",
+			Help:      "The time in unix seconds when the TLS certificate stored in the Secret expires.",
+		}, []string{"namespace", "tidbcluster", "secret"})
+)
+
+func init() {
+	prometheus.MustRegister(TiKVEvictLeaderDuration)
+	prometheus.MustRegister(TLSCertExpiryTimestamp)
+}
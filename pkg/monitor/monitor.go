@@ -0,0 +1,22 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitor
+
+import "github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+
+// MonitorManager implements the logic for manage the monitor of tidb clusters.
+type MonitorManager interface {
+	// Sync	implements the logic for syncing TidbMonitor.
+	Sync(tm *v1alpha1.TidbMonitor) error
+}
@@ -0,0 +1,333 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitor
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	listers "github.com/pingcap/tidb-operator/pkg/client/listers/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	"github.com/pingcap/tidb-operator/pkg/manager/member"
+	"github.com/pingcap/tidb-operator/pkg/monitor"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	glog "k8s.io/klog"
+)
+
+type monitorManager struct {
+	tcLister       listers.TidbClusterLister
+	podLister      corelisters.PodLister
+	secretLister   corelisters.SecretLister
+	cmLister       corelisters.ConfigMapLister
+	svcLister      corelisters.ServiceLister
+	deployLister   appslisters.DeploymentLister
+	pvcLister      corelisters.PersistentVolumeClaimLister
+	generalControl controller.GeneralControlInterface
+	pvcControl     controller.GeneralPVCControlInterface
+	tmControl      controller.TidbMonitorControlInterface
+}
+
+// NewMonitorManager returns a MonitorManager
+func NewMonitorManager(
+	tcLister listers.TidbClusterLister,
+	podLister corelisters.PodLister,
+	secretLister corelisters.SecretLister,
+	cmLister corelisters.ConfigMapLister,
+	svcLister corelisters.ServiceLister,
+	deployLister appslisters.DeploymentLister,
+	pvcLister corelisters.PersistentVolumeClaimLister,
+	generalControl controller.GeneralControlInterface,
+	pvcControl controller.GeneralPVCControlInterface,
+	tmControl controller.TidbMonitorControlInterface,
+) monitor.MonitorManager {
+	return &monitorManager{
+		tcLister,
+		podLister,
+		secretLister,
+		cmLister,
+		svcLister,
+		deployLister,
+		pvcLister,
+		generalControl,
+		pvcControl,
+		tmControl,
+	}
+}
+
+func (mm *monitorManager) Sync(tm *v1alpha1.TidbMonitor) error {
+	if tm.DeletionTimestamp != nil {
+		return nil
+	}
+
+	clusters, err := mm.getMonitoredClusters(tm)
+	if err != nil {
+		return err
+	}
+
+	tlsSecret, err := mm.syncMonitorTLSSecret(tm, clusters)
+	if err != nil {
+		return err
+	}
+
+	cm, err := getMonitorConfigMap(tm, clusters, tlsSecret)
+	if err != nil {
+		return err
+	}
+	if err := mm.syncConfigMap(tm, cm); err != nil {
+		return err
+	}
+
+	if tm.Spec.Grafana != nil {
+		if err := mm.syncSecret(tm, getGrafanaSecret(tm)); err != nil {
+			return err
+		}
+	}
+
+	for _, svc := range getMonitorServices(tm) {
+		if err := mm.syncService(tm, svc); err != nil {
+			return err
+		}
+	}
+
+	if tm.Spec.Persistent {
+		if err := mm.syncMonitorPVC(tm); err != nil {
+			return err
+		}
+	}
+
+	deploy, err := mm.syncMonitorDeployment(tm, getMonitorDeployment(tm, clusters, cm, tlsSecret))
+	if err != nil {
+		return err
+	}
+
+	return mm.syncTidbMonitorStatus(tm, clusters, deploy)
+}
+
+// getMonitoredClusters returns the referenced clusters with the scrape targets built from their Pods,
+// the missing clusters are skipped so that the others are still monitored
+func (mm *monitorManager) getMonitoredClusters(tm *v1alpha1.TidbMonitor) ([]*monitoredCluster, error) {
+	var clusters []*monitoredCluster
+	for _, ref := range tm.Spec.Clusters {
+		if ref.ClusterDomain != "" {
+			glog.Warningf("tidbmonitor %s/%s: cluster %s/%s in cluster domain %s is not supported, skip it",
+				tm.GetNamespace(), tm.GetName(), ref.Namespace, ref.Name, ref.ClusterDomain)
+			continue
+		}
+		ns := ref.Namespace
+		if ns == "" {
+			ns = tm.GetNamespace()
+		}
+		tc, err := mm.tcLister.TidbClusters(ns).Get(ref.Name)
+		if errors.IsNotFound(err) {
+			glog.Warningf("tidbmonitor %s/%s: tidbcluster %s/%s not found, skip it", tm.GetNamespace(), tm.GetName(), ns, ref.Name)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("tidbmonitor %s/%s get tidbcluster %s/%s failed, err: %v", tm.GetNamespace(), tm.GetName(), ns, ref.Name, err)
+		}
+
+		selector, err := label.New().Instance(tc.GetName()).Selector()
+		if err != nil {
+			return nil, err
+		}
+		pods, err := mm.podLister.Pods(ns).List(selector)
+		if err != nil {
+			return nil, fmt.Errorf("tidbmonitor %s/%s list pods of tidbcluster %s/%s failed, err: %v", tm.GetNamespace(), tm.GetName(), ns, ref.Name, err)
+		}
+		mc := newMonitoredCluster(tc, pods)
+		mc.clientTLSSecretName = ref.ClientTLSSecretName
+		if mc.clientTLSSecretName == "" && ns == tm.GetNamespace() {
+			mc.clientTLSSecretName = fmt.Sprintf("%s-pd-client", tc.GetName())
+		}
+		clusters = append(clusters, mc)
+	}
+	return clusters, nil
+}
+
+// syncMonitorTLSSecret gathers the client certificates of the TLS enabled clusters into one Secret, it returns nil
+// if none of the clusters enables TLS. Only the Secrets in the namespace of the monitor are read, the client Secret
+// of a cluster in another namespace must be copied into the namespace of the monitor by the user
+func (mm *monitorManager) syncMonitorTLSSecret(tm *v1alpha1.TidbMonitor, clusters []*monitoredCluster) (*corev1.Secret, error) {
+	clientSecrets := map[*monitoredCluster]*corev1.Secret{}
+	for _, mc := range clusters {
+		if !mc.tls {
+			continue
+		}
+		if mc.clientTLSSecretName == "" {
+			glog.Warningf("tidbmonitor %s/%s: clientTLSSecretName of tidbcluster %s/%s in another namespace is not set, scrape it without TLS",
+				tm.GetNamespace(), tm.GetName(), mc.tc.GetNamespace(), mc.tc.GetName())
+			mc.tls = false
+			continue
+		}
+		secret, err := mm.secretLister.Secrets(tm.GetNamespace()).Get(mc.clientTLSSecretName)
+		if errors.IsNotFound(err) {
+			// the certificate is not issued yet, scrape the cluster after it's ready
+			glog.Warningf("tidbmonitor %s/%s: client secret %s/%s not found", tm.GetNamespace(), tm.GetName(), tm.GetNamespace(), mc.clientTLSSecretName)
+			mc.tls = false
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("tidbmonitor %s/%s get secret %s/%s failed, err: %v", tm.GetNamespace(), tm.GetName(), tm.GetNamespace(), mc.clientTLSSecretName, err)
+		}
+		clientSecrets[mc] = secret
+	}
+
+	secret := getMonitorTLSSecret(tm, clusters, clientSecrets)
+	if secret == nil {
+		return nil, nil
+	}
+	return secret, mm.syncSecret(tm, secret)
+}
+
+func (mm *monitorManager) syncConfigMap(tm *v1alpha1.TidbMonitor, newCm *corev1.ConfigMap) error {
+	oldCm, err := mm.cmLister.ConfigMaps(newCm.GetNamespace()).Get(newCm.GetName())
+	if errors.IsNotFound(err) {
+		return mm.generalControl.CreateConfigMap(tm, newCm)
+	}
+	if err != nil {
+		return err
+	}
+	if apiequality.Semantic.DeepEqual(oldCm.Data, newCm.Data) {
+		return nil
+	}
+	cm := oldCm.DeepCopy()
+	cm.Data = newCm.Data
+	_, err = mm.generalControl.UpdateConfigMap(tm, cm)
+	return err
+}
+
+func (mm *monitorManager) syncSecret(tm *v1alpha1.TidbMonitor, newSecret *corev1.Secret) error {
+	oldSecret, err := mm.secretLister.Secrets(newSecret.GetNamespace()).Get(newSecret.GetName())
+	if errors.IsNotFound(err) {
+		return mm.generalControl.CreateSecret(tm, newSecret)
+	}
+	if err != nil {
+		return err
+	}
+	if apiequality.Semantic.DeepEqual(oldSecret.Data, newSecret.Data) {
+		return nil
+	}
+	secret := oldSecret.DeepCopy()
+	secret.Data = newSecret.Data
+	_, err = mm.generalControl.UpdateSecret(tm, secret)
+	return err
+}
+
+func (mm *monitorManager) syncService(tm *v1alpha1.TidbMonitor, newSvc *corev1.Service) error {
+	oldSvc, err := mm.svcLister.Services(newSvc.GetNamespace()).Get(newSvc.GetName())
+	if errors.IsNotFound(err) {
+		return mm.generalControl.CreateService(tm, newSvc)
+	}
+	if err != nil {
+		return err
+	}
+
+	// keep the allocated node ports, they are not specified by TidbMonitor
+	for i := range newSvc.Spec.Ports {
+		for _, oldPort := range oldSvc.Spec.Ports {
+			if oldPort.Name == newSvc.Spec.Ports[i].Name && newSvc.Spec.Type != corev1.ServiceTypeClusterIP {
+				newSvc.Spec.Ports[i].NodePort = oldPort.NodePort
+			}
+		}
+	}
+	if oldSvc.Spec.Type == newSvc.Spec.Type &&
+		apiequality.Semantic.DeepEqual(oldSvc.Spec.Ports, newSvc.Spec.Ports) &&
+		apiequality.Semantic.DeepEqual(oldSvc.Spec.Selector, newSvc.Spec.Selector) &&
+		oldSvc.Spec.LoadBalancerIP == newSvc.Spec.LoadBalancerIP {
+		return nil
+	}
+	svc := oldSvc.DeepCopy()
+	svc.Spec.Type = newSvc.Spec.Type
+	svc.Spec.Ports = newSvc.Spec.Ports
+	svc.Spec.Selector = newSvc.Spec.Selector
+	svc.Spec.LoadBalancerIP = newSvc.Spec.LoadBalancerIP
+	_, err = mm.generalControl.UpdateService(tm, svc)
+	return err
+}
+
+func (mm *monitorManager) syncMonitorPVC(tm *v1alpha1.TidbMonitor) error {
+	pvcName := controller.TidbMonitorMemberName(tm.GetName())
+	_, err := mm.pvcLister.PersistentVolumeClaims(tm.GetNamespace()).Get(pvcName)
+	if err == nil {
+		return nil
+	}
+	if !errors.IsNotFound(err) {
+		return fmt.Errorf("tidbmonitor %s/%s get pvc %s failed, err: %v", tm.GetNamespace(), tm.GetName(), pvcName, err)
+	}
+	pvc, err := getMonitorPVC(tm)
+	if err != nil {
+		return err
+	}
+	return mm.pvcControl.CreatePVC(tm, pvc)
+}
+
+func (mm *monitorManager) syncMonitorDeployment(tm *v1alpha1.TidbMonitor, newDeploy *apps.Deployment) (*apps.Deployment, error) {
+	specApply, err := json.Marshal(newDeploy.Spec)
+	if err != nil {
+		return nil, err
+	}
+	if newDeploy.Annotations == nil {
+		newDeploy.Annotations = map[string]string{}
+	}
+	newDeploy.Annotations[member.LastAppliedConfigAnnotation] = string(specApply)
+
+	oldDeploy, err := mm.deployLister.Deployments(newDeploy.GetNamespace()).Get(newDeploy.GetName())
+	if errors.IsNotFound(err) {
+		return newDeploy, mm.generalControl.CreateDeployment(tm, newDeploy)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if oldDeploy.Annotations[member.LastAppliedConfigAnnotation] == string(specApply) {
+		return oldDeploy, nil
+	}
+
+	deploy := oldDeploy.DeepCopy()
+	deploy.Spec = newDeploy.Spec
+	if deploy.Annotations == nil {
+		deploy.Annotations = map[string]string{}
+	}
+	deploy.Annotations[member.LastAppliedConfigAnnotation] = string(specApply)
+	return mm.generalControl.UpdateDeployment(tm, deploy)
+}
+
+func (mm *monitorManager) syncTidbMonitorStatus(tm *v1alpha1.TidbMonitor, clusters []*monitoredCluster, deploy *apps.Deployment) error {
+	status := v1alpha1.TidbMonitorStatus{
+		Deployment:       deploy.Status.DeepCopy(),
+		DashboardVersion: dashboardVersion(tm, clusters),
+	}
+	for _, mc := range clusters {
+		status.Clusters = append(status.Clusters, v1alpha1.MonitoredCluster{
+			Namespace: mc.tc.GetNamespace(),
+			Name:      mc.tc.GetName(),
+			Targets:   mc.targetCount(),
+			TLS:       mc.tls,
+		})
+	}
+	if apiequality.Semantic.DeepEqual(tm.Status, status) {
+		return nil
+	}
+
+	tm = tm.DeepCopy()
+	tm.Status = status
+	_, err := mm.tmControl.UpdateTidbMonitor(tm)
+	return err
+}
@@ -0,0 +1,377 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitor
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned/fake"
+	informers "github.com/pingcap/tidb-operator/pkg/client/informers/externalversions"
+	listers "github.com/pingcap/tidb-operator/pkg/client/listers/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kubeinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestMonitorManagerSync(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name        string
+		prepare     func(tm *v1alpha1.TidbMonitor, tc *v1alpha1.TidbCluster)
+		errOnCreate bool
+		errExpectFn func(*GomegaWithT, error)
+		expectFn    func(*GomegaWithT, *monitorManager, *fakeIndexers)
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+
+		tm := newTidbMonitor()
+		tc := newTidbCluster()
+		if test.prepare != nil {
+			test.prepare(tm, tc)
+		}
+
+		mm, fakeGeneralControl, indexers := newFakeMonitorManager()
+		indexers.tc.Add(tc)
+		indexers.tm.Add(tm)
+		for i := 0; i < 3; i++ {
+			indexers.pod.Add(newTiKVPod(tc, i))
+		}
+		if tc.Spec.EnableTLSCluster {
+			indexers.secret.Add(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "demo-pd-client", Namespace: tc.GetNamespace()},
+				Data: map[string][]byte{
+					controller.TLSSecretCertKey: []byte("cert"),
+					controller.TLSSecretKeyKey:  []byte("key"),
+				},
+			})
+		}
+		for _, ref := range tm.Spec.Clusters {
+			if ref.ClientTLSSecretName == "" {
+				continue
+			}
+			indexers.secret.Add(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: ref.ClientTLSSecretName, Namespace: tm.GetNamespace()},
+				Data: map[string][]byte{
+					corev1.TLSCertKey:       []byte("user-cert"),
+					corev1.TLSPrivateKeyKey: []byte("user-key"),
+				},
+			})
+		}
+		if test.errOnCreate {
+			fakeGeneralControl.SetCreateError(errors.NewInternalError(fmt.Errorf("API server failed")), 0)
+		}
+
+		err := mm.Sync(tm)
+		test.errExpectFn(g, err)
+		if test.expectFn != nil {
+			test.expectFn(g, mm, indexers)
+		}
+	}
+
+	tests := []testcase{
+		{
+			name:        "create the monitor",
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, mm *monitorManager, indexers *fakeIndexers) {
+				cm, err := mm.cmLister.ConfigMaps("default").Get("monitor-monitor")
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(cm.Data[prometheusConfigKey]).To(ContainSubstring("job_name: default/demo/http"))
+				g.Expect(cm.Data[prometheusConfigKey]).NotTo(ContainSubstring("https"))
+				g.Expect(cm.Data["default.demo.http.json"]).To(ContainSubstring("demo-tikv-0.demo-tikv-peer.default.svc:20180"))
+				g.Expect(cm.Data).NotTo(HaveKey(dashboardConfigKey))
+
+				deploy, err := mm.deployLister.Deployments("default").Get("monitor-monitor")
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(deploy.Spec.Template.Spec.Containers).To(HaveLen(2))
+				g.Expect(deploy.Spec.Template.Spec.InitContainers[0].Image).To(Equal("pingcap/tidb-monitor-initializer:v3.0.8"))
+
+				_, err = mm.secretLister.Secrets("default").Get("monitor-monitor-tls")
+				g.Expect(errors.IsNotFound(err)).To(BeTrue())
+				_, err = mm.pvcLister.PersistentVolumeClaims("default").Get("monitor-monitor")
+				g.Expect(errors.IsNotFound(err)).To(BeTrue())
+
+				tm, err := indexers.tmLister.TidbMonitors("default").Get("monitor")
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(tm.Status.DashboardVersion).To(Equal("v3.0.8"))
+				g.Expect(tm.Status.Clusters).To(Equal([]v1alpha1.MonitoredCluster{{Namespace: "default", Name: "demo", Targets: 3}}))
+			},
+		},
+		{
+			name: "create the monitor with grafana and persistent volume",
+			prepare: func(tm *v1alpha1.TidbMonitor, _ *v1alpha1.TidbCluster) {
+				tm.Spec.Grafana = &v1alpha1.GrafanaSpec{}
+				tm.Spec.Persistent = true
+			},
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, mm *monitorManager, _ *fakeIndexers) {
+				cm, err := mm.cmLister.ConfigMaps("default").Get("monitor-monitor")
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(cm.Data).To(HaveKey(dashboardConfigKey))
+
+				secret, err := mm.secretLister.Secrets("default").Get("monitor-grafana")
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(string(secret.Data["username"])).To(Equal("admin"))
+
+				_, err = mm.svcLister.Services("default").Get("monitor-grafana")
+				g.Expect(err).NotTo(HaveOccurred())
+				_, err = mm.pvcLister.PersistentVolumeClaims("default").Get("monitor-monitor")
+				g.Expect(err).NotTo(HaveOccurred())
+
+				deploy, err := mm.deployLister.Deployments("default").Get("monitor-monitor")
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(deploy.Spec.Template.Spec.Containers).To(HaveLen(3))
+				g.Expect(deploy.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim).NotTo(BeNil())
+			},
+		},
+		{
+			name: "the missing cluster is skipped",
+			prepare: func(tm *v1alpha1.TidbMonitor, _ *v1alpha1.TidbCluster) {
+				tm.Spec.Clusters = append(tm.Spec.Clusters, v1alpha1.MonitorClusterRef{TidbClusterRef: v1alpha1.TidbClusterRef{Namespace: "other", Name: "missing"}})
+			},
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, mm *monitorManager, indexers *fakeIndexers) {
+				tm, err := indexers.tmLister.TidbMonitors("default").Get("monitor")
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(tm.Status.Clusters).To(HaveLen(1))
+			},
+		},
+		{
+			name: "the client certificate of the TLS enabled cluster is copied",
+			prepare: func(_ *v1alpha1.TidbMonitor, tc *v1alpha1.TidbCluster) {
+				tc.Spec.EnableTLSCluster = true
+			},
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, mm *monitorManager, _ *fakeIndexers) {
+				secret, err := mm.secretLister.Secrets("default").Get("monitor-monitor-tls")
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(secret.Data).To(HaveKey("default.demo.cert"))
+				g.Expect(secret.Data).To(HaveKey("default.demo.key"))
+				g.Expect(secret.Data).NotTo(HaveKey("default.demo.ca"))
+
+				cm, err := mm.cmLister.ConfigMaps("default").Get("monitor-monitor")
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(cm.Data[prometheusConfigKey]).To(ContainSubstring("job_name: default/demo/https"))
+				g.Expect(cm.Data[prometheusConfigKey]).To(ContainSubstring(serviceAccountCA))
+				g.Expect(cm.Data[prometheusConfigKey]).NotTo(ContainSubstring("insecure_skip_verify"))
+				// TiKV is still scraped without TLS
+				g.Expect(cm.Data["default.demo.https.json"]).To(Equal("[]"))
+				g.Expect(cm.Data["default.demo.http.json"]).To(ContainSubstring("demo-tikv-0"))
+			},
+		},
+		{
+			name: "the client secret of the TLS enabled cluster in another namespace is not read",
+			prepare: func(tm *v1alpha1.TidbMonitor, tc *v1alpha1.TidbCluster) {
+				tc.Namespace = "other"
+				tc.Spec.EnableTLSCluster = true
+				tm.Spec.Clusters[0].Namespace = "other"
+			},
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, mm *monitorManager, indexers *fakeIndexers) {
+				_, err := mm.secretLister.Secrets("default").Get("monitor-monitor-tls")
+				g.Expect(errors.IsNotFound(err)).To(BeTrue())
+
+				tm, err := indexers.tmLister.TidbMonitors("default").Get("monitor")
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(tm.Status.Clusters).To(Equal([]v1alpha1.MonitoredCluster{{Namespace: "other", Name: "demo", Targets: 3}}))
+			},
+		},
+		{
+			name: "the client secret of the user is used for the TLS enabled cluster in another namespace",
+			prepare: func(tm *v1alpha1.TidbMonitor, tc *v1alpha1.TidbCluster) {
+				tc.Namespace = "other"
+				tc.Spec.EnableTLSCluster = true
+				tm.Spec.Clusters[0].Namespace = "other"
+				tm.Spec.Clusters[0].ClientTLSSecretName = "other-demo-client"
+			},
+			errExpectFn: errExpectNil,
+			expectFn: func(g *GomegaWithT, mm *monitorManager, indexers *fakeIndexers) {
+				secret, err := mm.secretLister.Secrets("default").Get("monitor-monitor-tls")
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(string(secret.Data["other.demo.cert"])).To(Equal("user-cert"))
+				g.Expect(string(secret.Data["other.demo.key"])).To(Equal("user-key"))
+
+				cm, err := mm.cmLister.ConfigMaps("default").Get("monitor-monitor")
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(cm.Data[prometheusConfigKey]).To(ContainSubstring("job_name: other/demo/https"))
+
+				tm, err := indexers.tmLister.TidbMonitors("default").Get("monitor")
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(tm.Status.Clusters[0].TLS).To(BeTrue())
+			},
+		},
+		{
+			name:        "create failed",
+			errOnCreate: true,
+			errExpectFn: func(g *GomegaWithT, err error) {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(strings.Contains(err.Error(), "API server failed")).To(BeTrue())
+			},
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
+
+func TestMonitorManagerSyncUpdate(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tm := newTidbMonitor()
+	tc := newTidbCluster()
+	mm, fakeGeneralControl, indexers := newFakeMonitorManager()
+	indexers.tc.Add(tc)
+	indexers.tm.Add(tm)
+	indexers.pod.Add(newTiKVPod(tc, 0))
+
+	g.Expect(mm.Sync(tm)).To(Succeed())
+	g.Expect(mm.Sync(tm)).To(Succeed())
+	g.Expect(fakeGeneralControl.DeploymentUpdated()).To(Equal(0))
+
+	// the new target is loaded by file-based service discovery without restarting Prometheus
+	indexers.pod.Add(newTiKVPod(tc, 1))
+	g.Expect(mm.Sync(tm)).To(Succeed())
+	g.Expect(fakeGeneralControl.DeploymentUpdated()).To(Equal(0))
+	cm, err := mm.cmLister.ConfigMaps("default").Get("monitor-monitor")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cm.Data["default.demo.http.json"]).To(ContainSubstring("demo-tikv-1"))
+
+	tm.Spec.Prometheus.ReserveDays = 30
+	g.Expect(mm.Sync(tm)).To(Succeed())
+	g.Expect(fakeGeneralControl.DeploymentUpdated()).To(Equal(1))
+	deploy, err := mm.deployLister.Deployments("default").Get("monitor-monitor")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(deploy.Spec.Template.Spec.Containers[0].Command).To(ContainElement("--storage.tsdb.retention=30d"))
+}
+
+type fakeIndexers struct {
+	tc       cache.Indexer
+	tm       cache.Indexer
+	pod      cache.Indexer
+	secret   cache.Indexer
+	tmLister listers.TidbMonitorLister
+}
+
+func newFakeMonitorManager() (*monitorManager, *controller.FakeGeneralControl, *fakeIndexers) {
+	cli := fake.NewSimpleClientset()
+	kubeCli := kubefake.NewSimpleClientset()
+	informerFactory := informers.NewSharedInformerFactory(cli, 0)
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeCli, 0)
+	tcInformer := informerFactory.Pingcap().V1alpha1().TidbClusters()
+	tmInformer := informerFactory.Pingcap().V1alpha1().TidbMonitors()
+	podInformer := kubeInformerFactory.Core().V1().Pods()
+	secretInformer := kubeInformerFactory.Core().V1().Secrets()
+	cmInformer := kubeInformerFactory.Core().V1().ConfigMaps()
+	svcInformer := kubeInformerFactory.Core().V1().Services()
+	deployInformer := kubeInformerFactory.Apps().V1().Deployments()
+	pvcInformer := kubeInformerFactory.Core().V1().PersistentVolumeClaims()
+	generalControl := controller.NewFakeGeneralControl(
+		deployInformer.Informer().GetIndexer(),
+		cmInformer.Informer().GetIndexer(),
+		secretInformer.Informer().GetIndexer(),
+		svcInformer.Informer().GetIndexer(),
+	)
+
+	mm := &monitorManager{
+		tcInformer.Lister(),
+		podInformer.Lister(),
+		secretInformer.Lister(),
+		cmInformer.Lister(),
+		svcInformer.Lister(),
+		deployInformer.Lister(),
+		pvcInformer.Lister(),
+		generalControl,
+		controller.NewFakeGeneralPVCControl(pvcInformer),
+		controller.NewFakeTidbMonitorControl(tmInformer),
+	}
+	indexers := &fakeIndexers{
+		tc:       tcInformer.Informer().GetIndexer(),
+		tm:       tmInformer.Informer().GetIndexer(),
+		pod:      podInformer.Informer().GetIndexer(),
+		secret:   secretInformer.Informer().GetIndexer(),
+		tmLister: tmInformer.Lister(),
+	}
+	return mm, generalControl, indexers
+}
+
+func newTidbMonitor() *v1alpha1.TidbMonitor {
+	return &v1alpha1.TidbMonitor{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "TidbMonitor",
+			APIVersion: "pingcap.com/v1alpha1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "monitor",
+			Namespace: corev1.NamespaceDefault,
+			UID:       types.UID("test"),
+		},
+		Spec: v1alpha1.TidbMonitorSpec{
+			Clusters: []v1alpha1.MonitorClusterRef{{TidbClusterRef: v1alpha1.TidbClusterRef{Name: "demo"}}},
+		},
+	}
+}
+
+func newTidbCluster() *v1alpha1.TidbCluster {
+	return &v1alpha1.TidbCluster{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "TidbCluster",
+			APIVersion: "pingcap.com/v1alpha1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "demo",
+			Namespace: corev1.NamespaceDefault,
+			UID:       types.UID("test"),
+		},
+		Spec: v1alpha1.TidbClusterSpec{
+			Version: "v3.0.8",
+		},
+	}
+}
+
+func newTiKVPod(tc *v1alpha1.TidbCluster, ordinal int) *corev1.Pod {
+	name := fmt.Sprintf("%s-tikv-%d", tc.GetName(), ordinal)
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: tc.GetNamespace(),
+			Labels:    label.New().Instance(tc.GetName()).TiKV().Labels(),
+			Annotations: map[string]string{
+				"prometheus.io/scrape": "true",
+				"prometheus.io/port":   "20180",
+				"prometheus.io/path":   "/metrics",
+			},
+		},
+		Spec: corev1.PodSpec{
+			Hostname:  name,
+			Subdomain: fmt.Sprintf("%s-tikv-peer", tc.GetName()),
+		},
+	}
+}
+
+func errExpectNil(g *GomegaWithT, err error) {
+	g.Expect(err).NotTo(HaveOccurred())
+}
@@ -0,0 +1,738 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitor
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	"github.com/pingcap/tidb-operator/pkg/util"
+	"gopkg.in/yaml.v2"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	defaultPrometheusBaseImage  = "prom/prometheus"
+	defaultPrometheusVersion    = "v2.11.1"
+	defaultGrafanaBaseImage     = "grafana/grafana"
+	defaultGrafanaVersion       = "6.0.1"
+	defaultReloaderBaseImage    = "pingcap/tidb-monitor-reloader"
+	defaultReloaderVersion      = "v1.0.1"
+	defaultInitializerBaseImage = "pingcap/tidb-monitor-initializer"
+	defaultInitializerVersion   = "latest"
+	defaultReserveDays          = 12
+	defaultStorage              = "10Gi"
+	defaultGrafanaUser          = "admin"
+	defaultLogLevel             = "info"
+
+	prometheusPort = 9090
+	grafanaPort    = 3000
+	reloaderPort   = 9089
+
+	prometheusConfigKey = "prometheus.yml"
+	dashboardConfigKey  = "dashboards.yaml"
+	prometheusConfigDir = "/etc/prometheus"
+	clientTLSDir        = "/var/lib/cluster-client-tls"
+	serviceAccountCA    = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+
+	// prometheusConfigHashAnnotation is the annotation of the monitor Pod recording the hash of prometheus.yml,
+	// the Pod is recreated to load the changed config. The targets are loaded by file-based service discovery
+	// so that the changes of the Pods of the clusters don't restart Prometheus
+	prometheusConfigHashAnnotation = "tidb.pingcap.com/prometheus-config-hash"
+)
+
+// monitoredCluster is a TidbCluster monitored by a TidbMonitor
+type monitoredCluster struct {
+	tc *v1alpha1.TidbCluster
+	// tls is true if the metrics are scraped with the client certificate of the cluster
+	tls bool
+	// clientTLSSecretName is the name of the Secret in the namespace of the monitor with the client certificate
+	clientTLSSecretName string
+	// targets are the target groups of file-based service discovery keyed by the scheme
+	targets map[string][]targetGroup
+}
+
+// targetGroup is a target group of Prometheus file-based service discovery
+type targetGroup struct {
+	Targets []string          `json:"targets"`
+	Labels  map[string]string `json:"labels"`
+}
+
+func (mc *monitoredCluster) targetCount() int32 {
+	var count int32
+	for _, groups := range mc.targets {
+		count += int32(len(groups))
+	}
+	return count
+}
+
+// targetsFileName returns the name of the file of the targets scraped by the scheme
+func (mc *monitoredCluster) targetsFileName(scheme string) string {
+	return fmt.Sprintf("%s.%s.%s.json", mc.tc.GetNamespace(), mc.tc.GetName(), scheme)
+}
+
+// tlsFileName returns the name of the file of the client certificate, key or CA of the cluster
+func (mc *monitoredCluster) tlsFileName(key string) string {
+	return fmt.Sprintf("%s.%s.%s", mc.tc.GetNamespace(), mc.tc.GetName(), key)
+}
+
+// podScheme returns the scheme to scrape the Pod, TiKV and TiFlash serve the metrics without TLS
+// This is a workaround of https://github.com/tikv/tikv/issues/5340 and should be removed after TiKV fix this issue
+func podScheme(tc *v1alpha1.TidbCluster, pod *corev1.Pod) string {
+	l := label.Label(pod.Labels)
+	if l.IsTiKV() || l.IsTiFlash() {
+		return "http"
+	}
+	return tc.Scheme()
+}
+
+// newMonitoredCluster returns the cluster with the targets built from the annotations of the Pods
+func newMonitoredCluster(tc *v1alpha1.TidbCluster, pods []*corev1.Pod) *monitoredCluster {
+	mc := &monitoredCluster{
+		tc:      tc,
+		tls:     tc.Spec.EnableTLSCluster,
+		targets: map[string][]targetGroup{},
+	}
+
+	sort.Slice(pods, func(i, j int) bool { return pods[i].GetName() < pods[j].GetName() })
+	for _, pod := range pods {
+		if pod.Annotations["prometheus.io/scrape"] != "true" {
+			continue
+		}
+		port, err := strconv.Atoi(pod.Annotations["prometheus.io/port"])
+		if err != nil {
+			continue
+		}
+		var host string
+		if pod.Spec.Subdomain != "" {
+			hostname := pod.Spec.Hostname
+			if hostname == "" {
+				hostname = pod.GetName()
+			}
+			host = fmt.Sprintf("%s.%s.%s.svc", hostname, pod.Spec.Subdomain, pod.GetNamespace())
+		} else if pod.Status.PodIP != "" {
+			host = pod.Status.PodIP
+		} else {
+			continue
+		}
+		metricsPath := pod.Annotations["prometheus.io/path"]
+		if metricsPath == "" {
+			metricsPath = "/metrics"
+		}
+
+		scheme := podScheme(tc, pod)
+		mc.targets[scheme] = append(mc.targets[scheme], targetGroup{
+			Targets: []string{fmt.Sprintf("%s:%d", host, port)},
+			Labels: map[string]string{
+				"__metrics_path__":     metricsPath,
+				"cluster":              tc.GetName(),
+				"kubernetes_namespace": tc.GetNamespace(),
+				"component":            pod.Labels[label.ComponentLabelKey],
+				"instance":             pod.GetName(),
+			},
+		})
+	}
+	return mc
+}
+
+type prometheusConfig struct {
+	Global        prometheusGlobalConfig    `yaml:"global"`
+	Alerting      *prometheusAlertingConfig `yaml:"alerting,omitempty"`
+	ScrapeConfigs []prometheusScrapeConfig  `yaml:"scrape_configs"`
+	RuleFiles     []string                  `yaml:"rule_files"`
+}
+
+type prometheusGlobalConfig struct {
+	ScrapeInterval     string `yaml:"scrape_interval"`
+	EvaluationInterval string `yaml:"evaluation_interval"`
+}
+
+type prometheusAlertingConfig struct {
+	Alertmanagers []prometheusStaticConfigs `yaml:"alertmanagers"`
+}
+
+type prometheusStaticConfigs struct {
+	StaticConfigs []prometheusStaticConfig `yaml:"static_configs"`
+}
+
+type prometheusStaticConfig struct {
+	Targets []string `yaml:"targets"`
+}
+
+type prometheusScrapeConfig struct {
+	JobName       string                   `yaml:"job_name"`
+	HonorLabels   bool                     `yaml:"honor_labels"`
+	Scheme        string                   `yaml:"scheme"`
+	TLSConfig     *prometheusTLSConfig     `yaml:"tls_config,omitempty"`
+	FileSDConfigs []prometheusFileSDConfig `yaml:"file_sd_configs"`
+}
+
+// prometheusTLSConfig verifies the certificates of the targets, which are addressed by the DNS names of the Pods
+// covered by the certificates of the cluster
+type prometheusTLSConfig struct {
+	CAFile   string `yaml:"ca_file"`
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+}
+
+type prometheusFileSDConfig struct {
+	Files []string `yaml:"files"`
+}
+
+// getPrometheusConfig returns prometheus.yml scraping the targets of the clusters
+func getPrometheusConfig(tm *v1alpha1.TidbMonitor, clusters []*monitoredCluster, tlsSecret *corev1.Secret) (string, error) {
+	config := prometheusConfig{
+		Global: prometheusGlobalConfig{
+			ScrapeInterval:     "15s",
+			EvaluationInterval: "15s",
+		},
+		RuleFiles: []string{"/prometheus-rules/rules/*.rules.yml"},
+	}
+	if tm.Spec.AlertmanagerURL != "" {
+		config.Alerting = &prometheusAlertingConfig{
+			Alertmanagers: []prometheusStaticConfigs{{
+				StaticConfigs: []prometheusStaticConfig{{Targets: []string{tm.Spec.AlertmanagerURL}}},
+			}},
+		}
+	}
+
+	for _, mc := range clusters {
+		for _, scheme := range []string{"http", "https"} {
+			if scheme == "https" && !mc.tls {
+				continue
+			}
+			sc := prometheusScrapeConfig{
+				JobName:     fmt.Sprintf("%s/%s/%s", mc.tc.GetNamespace(), mc.tc.GetName(), scheme),
+				HonorLabels: true,
+				Scheme:      scheme,
+				FileSDConfigs: []prometheusFileSDConfig{{
+					Files: []string{path.Join(prometheusConfigDir, mc.targetsFileName(scheme))},
+				}},
+			}
+			if scheme == "https" {
+				// the CA of the cluster is not copied if its client Secret has none, the certificates are then
+				// issued by the Kubernetes CSR API and signed by the CA of the service account
+				caFile := serviceAccountCA
+				if tlsSecret != nil {
+					if _, ok := tlsSecret.Data[mc.tlsFileName(controller.TLSSecretCAKey)]; ok {
+						caFile = path.Join(clientTLSDir, mc.tlsFileName(controller.TLSSecretCAKey))
+					}
+				}
+				sc.TLSConfig = &prometheusTLSConfig{
+					CAFile:   caFile,
+					CertFile: path.Join(clientTLSDir, mc.tlsFileName(controller.TLSSecretCertKey)),
+					KeyFile:  path.Join(clientTLSDir, mc.tlsFileName(controller.TLSSecretKeyKey)),
+				}
+			}
+			config.ScrapeConfigs = append(config.ScrapeConfigs, sc)
+		}
+	}
+
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// getDashboardConfig returns the provisioning config of the Grafana dashboards installed by the initializer
+func getDashboardConfig() (string, error) {
+	config := map[string]interface{}{
+		"apiVersion": 1,
+		"providers": []map[string]interface{}{{
+			"name":   "0",
+			"orgId":  1,
+			"folder": "",
+			"type":   "file",
+			"options": map[string]string{
+				"path": "/grafana-dashboard-definitions/tidb",
+			},
+		}},
+	}
+	data, err := json.MarshalIndent(config, "", "    ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func getMonitorConfigMap(tm *v1alpha1.TidbMonitor, clusters []*monitoredCluster, tlsSecret *corev1.Secret) (*corev1.ConfigMap, error) {
+	promConfig, err := getPrometheusConfig(tm, clusters, tlsSecret)
+	if err != nil {
+		return nil, err
+	}
+	data := map[string]string{
+		prometheusConfigKey: promConfig,
+	}
+	if tm.Spec.Grafana != nil {
+		dashboardConfig, err := getDashboardConfig()
+		if err != nil {
+			return nil, err
+		}
+		data[dashboardConfigKey] = dashboardConfig
+	}
+	for _, mc := range clusters {
+		for _, scheme := range []string{"http", "https"} {
+			if scheme == "https" && !mc.tls {
+				continue
+			}
+			groups := mc.targets[scheme]
+			if groups == nil {
+				groups = []targetGroup{}
+			}
+			targets, err := json.MarshalIndent(groups, "", "  ")
+			if err != nil {
+				return nil, err
+			}
+			data[mc.targetsFileName(scheme)] = string(targets)
+		}
+	}
+
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            controller.TidbMonitorMemberName(tm.GetName()),
+			Namespace:       tm.GetNamespace(),
+			Labels:          label.NewMonitor().Instance(tm.GetName()).Monitor(),
+			OwnerReferences: []metav1.OwnerReference{controller.GetTidbMonitorOwnerRef(tm)},
+		},
+		Data: data,
+	}, nil
+}
+
+// getMonitorTLSSecret returns the Secret with the client certificates of all the clusters, so that they are
+// mounted by the monitor Pod as one volume
+func getMonitorTLSSecret(tm *v1alpha1.TidbMonitor, clusters []*monitoredCluster, clientSecrets map[*monitoredCluster]*corev1.Secret) *corev1.Secret {
+	data := map[string][]byte{}
+	for _, mc := range clusters {
+		secret, ok := clientSecrets[mc]
+		if !ok {
+			continue
+		}
+		cert, key, ca := tlsSecretData(secret)
+		data[mc.tlsFileName(controller.TLSSecretCertKey)] = cert
+		data[mc.tlsFileName(controller.TLSSecretKeyKey)] = key
+		if len(ca) > 0 {
+			data[mc.tlsFileName(controller.TLSSecretCAKey)] = ca
+		}
+	}
+	if len(data) == 0 {
+		return nil
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            monitorTLSSecretName(tm),
+			Namespace:       tm.GetNamespace(),
+			Labels:          label.NewMonitor().Instance(tm.GetName()).Monitor(),
+			OwnerReferences: []metav1.OwnerReference{controller.GetTidbMonitorOwnerRef(tm)},
+		},
+		Data: data,
+	}
+}
+
+// tlsSecretData returns the cert, key and CA in the Secret, which is either created by tidb-operator
+// or of the kubernetes.io/tls type
+func tlsSecretData(secret *corev1.Secret) ([]byte, []byte, []byte) {
+	ca, ok := secret.Data[controller.TLSSecretCAKey]
+	if !ok {
+		ca = secret.Data[corev1.ServiceAccountRootCAKey]
+	}
+	if _, ok := secret.Data[controller.TLSSecretCertKey]; !ok {
+		return secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey], ca
+	}
+	return secret.Data[controller.TLSSecretCertKey], secret.Data[controller.TLSSecretKeyKey], ca
+}
+
+func monitorTLSSecretName(tm *v1alpha1.TidbMonitor) string {
+	return fmt.Sprintf("%s-monitor-tls", tm.GetName())
+}
+
+func grafanaSecretName(tm *v1alpha1.TidbMonitor) string {
+	return fmt.Sprintf("%s-grafana", tm.GetName())
+}
+
+func getGrafanaSecret(tm *v1alpha1.TidbMonitor) *corev1.Secret {
+	username := tm.Spec.Grafana.Username
+	if username == "" {
+		username = defaultGrafanaUser
+	}
+	password := tm.Spec.Grafana.Password
+	if password == "" {
+		password = defaultGrafanaUser
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            grafanaSecretName(tm),
+			Namespace:       tm.GetNamespace(),
+			Labels:          label.NewMonitor().Instance(tm.GetName()).Monitor(),
+			OwnerReferences: []metav1.OwnerReference{controller.GetTidbMonitorOwnerRef(tm)},
+		},
+		Data: map[string][]byte{
+			"username": []byte(username),
+			"password": []byte(password),
+		},
+	}
+}
+
+func getMonitorServices(tm *v1alpha1.TidbMonitor) []*corev1.Service {
+	svcs := []*corev1.Service{
+		newMonitorService(tm, fmt.Sprintf("%s-prometheus", tm.GetName()), "prometheus", prometheusPort, tm.Spec.Prometheus.Service),
+		newMonitorService(tm, fmt.Sprintf("%s-monitor-reloader", tm.GetName()), "reloader", reloaderPort, tm.Spec.Reloader.Service),
+	}
+	if tm.Spec.Grafana != nil {
+		svcs = append(svcs, newMonitorService(tm, fmt.Sprintf("%s-grafana", tm.GetName()), "grafana", grafanaPort, tm.Spec.Grafana.Service))
+	}
+	return svcs
+}
+
+func newMonitorService(tm *v1alpha1.TidbMonitor, name, portName string, port int32, spec v1alpha1.ServiceSpec) *corev1.Service {
+	monitorLabel := label.NewMonitor().Instance(tm.GetName()).Monitor()
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       tm.GetNamespace(),
+			Labels:          label.NewMonitor().Instance(tm.GetName()).Monitor(),
+			Annotations:     spec.Annotations,
+			OwnerReferences: []metav1.OwnerReference{controller.GetTidbMonitorOwnerRef(tm)},
+		},
+		Spec: corev1.ServiceSpec{
+			Type: spec.Type,
+			Ports: []corev1.ServicePort{{
+				Name:       portName,
+				Port:       port,
+				Protocol:   corev1.ProtocolTCP,
+				TargetPort: intstr.FromInt(int(port)),
+			}},
+			Selector: monitorLabel.Labels(),
+		},
+	}
+	if svc.Spec.Type == "" {
+		svc.Spec.Type = corev1.ServiceTypeClusterIP
+	}
+	if spec.LoadBalancerIP != "" && svc.Spec.Type == corev1.ServiceTypeLoadBalancer {
+		svc.Spec.LoadBalancerIP = spec.LoadBalancerIP
+	}
+	return svc
+}
+
+func getMonitorPVC(tm *v1alpha1.TidbMonitor) (*corev1.PersistentVolumeClaim, error) {
+	storage := tm.Spec.Storage
+	if storage == "" {
+		storage = defaultStorage
+	}
+	rs, err := resource.ParseQuantity(storage)
+	if err != nil {
+		return nil, fmt.Errorf("tidbmonitor %s/%s parse storage size %s failed, err: %v", tm.GetNamespace(), tm.GetName(), storage, err)
+	}
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            controller.TidbMonitorMemberName(tm.GetName()),
+			Namespace:       tm.GetNamespace(),
+			Labels:          label.NewMonitor().Instance(tm.GetName()).Monitor(),
+			OwnerReferences: []metav1.OwnerReference{controller.GetTidbMonitorOwnerRef(tm)},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: tm.Spec.StorageClassName,
+			AccessModes: []corev1.PersistentVolumeAccessMode{
+				corev1.ReadWriteOnce,
+			},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: rs,
+				},
+			},
+		},
+	}, nil
+}
+
+func containerImage(mc v1alpha1.MonitorContainer, defaultBaseImage, defaultVersion string) string {
+	baseImage := mc.BaseImage
+	if baseImage == "" {
+		baseImage = defaultBaseImage
+	}
+	version := mc.Version
+	if version == "" {
+		version = defaultVersion
+	}
+	return fmt.Sprintf("%s:%s", baseImage, version)
+}
+
+func containerPullPolicy(tm *v1alpha1.TidbMonitor, mc v1alpha1.MonitorContainer) corev1.PullPolicy {
+	if mc.ImagePullPolicy != nil {
+		return *mc.ImagePullPolicy
+	}
+	if tm.Spec.ImagePullPolicy != "" {
+		return tm.Spec.ImagePullPolicy
+	}
+	return corev1.PullIfNotPresent
+}
+
+// dashboardVersion returns the version of the dashboards and the Prometheus rules, which follows the version of
+// the first monitored cluster unless the version of the initializer is specified, so that they are upgraded
+// along with the clusters
+func dashboardVersion(tm *v1alpha1.TidbMonitor, clusters []*monitoredCluster) string {
+	if tm.Spec.Initializer.Version != "" {
+		return tm.Spec.Initializer.Version
+	}
+	for _, mc := range clusters {
+		if mc.tc.Spec.Version != "" {
+			return mc.tc.Spec.Version
+		}
+	}
+	return defaultInitializerVersion
+}
+
+func binlogEnabled(clusters []*monitoredCluster) bool {
+	for _, mc := range clusters {
+		if mc.tc.Spec.Pump != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func getMonitorDeployment(tm *v1alpha1.TidbMonitor, clusters []*monitoredCluster, cm *corev1.ConfigMap, tlsSecret *corev1.Secret) *apps.Deployment {
+	monitorLabel := label.NewMonitor().Instance(tm.GetName()).Monitor()
+	version := dashboardVersion(tm, clusters)
+
+	dataVolume := corev1.Volume{Name: "monitor-data"}
+	if tm.Spec.Persistent {
+		dataVolume.VolumeSource = corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: controller.TidbMonitorMemberName(tm.GetName()),
+			},
+		}
+	} else {
+		dataVolume.VolumeSource = corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}
+	}
+	volumes := []corev1.Volume{
+		dataVolume,
+		{Name: "prometheus-config", VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: cm.GetName()},
+			},
+		}},
+		{Name: "prometheus-rules", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+		{Name: "grafana-dashboard", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+		{Name: "datasource", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+	}
+
+	kubePrometheusURL := tm.Spec.KubePrometheusURL
+	if kubePrometheusURL == "" {
+		kubePrometheusURL = "http://prometheus-k8s.monitoring.svc:9090"
+	}
+	initEnvs := []corev1.EnvVar{
+		{Name: "GF_PROVISIONING_PATH", Value: "/grafana-dashboard-definitions/tidb"},
+		{Name: "GF_DATASOURCE_PATH", Value: "/etc/grafana/provisioning/datasources"},
+		{Name: "TIDB_CLUSTER_NAME", Value: tm.GetName()},
+		{Name: "TIDB_ENABLE_BINLOG", Value: strconv.FormatBool(binlogEnabled(clusters))},
+		{Name: "PROM_CONFIG_PATH", Value: "/prometheus-rules"},
+		{Name: "PROM_PERSISTENT_DIR", Value: "/data"},
+		{Name: "TIDB_VERSION", Value: version},
+		{Name: "GF_K8S_PROMETHEUS_URL", Value: kubePrometheusURL},
+		{Name: "GF_TIDB_PROMETHEUS_URL", Value: fmt.Sprintf("http://127.0.0.1:%d", prometheusPort)},
+		{Name: "TIDB_CLUSTER_NAMESPACE", Value: tm.GetNamespace()},
+	}
+	initEnvs = append(initEnvs, sortedEnvs(tm.Spec.Initializer.Envs)...)
+	var rootUser int64
+	initializer := corev1.Container{
+		Name:            "monitor-initializer",
+		Image:           containerImage(tm.Spec.Initializer.MonitorContainer, defaultInitializerBaseImage, version),
+		ImagePullPolicy: containerPullPolicy(tm, tm.Spec.Initializer.MonitorContainer),
+		Env:             initEnvs,
+		Command: []string{
+			"/bin/sh",
+			"-c",
+			"mkdir -p /data/prometheus /data/grafana\nchmod 777 /data/prometheus /data/grafana\n/usr/bin/init.sh",
+		},
+		SecurityContext: &corev1.SecurityContext{RunAsUser: &rootUser},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "grafana-dashboard", MountPath: "/grafana-dashboard-definitions/tidb"},
+			{Name: "prometheus-rules", MountPath: "/prometheus-rules"},
+			{Name: "monitor-data", MountPath: "/data"},
+			{Name: "datasource", MountPath: "/etc/grafana/provisioning/datasources"},
+		},
+		Resources: util.ResourceRequirement(tm.Spec.Initializer.Resources),
+	}
+
+	reserveDays := tm.Spec.Prometheus.ReserveDays
+	if reserveDays == 0 {
+		reserveDays = defaultReserveDays
+	}
+	promLogLevel := tm.Spec.Prometheus.LogLevel
+	if promLogLevel == "" {
+		promLogLevel = defaultLogLevel
+	}
+	promMounts := []corev1.VolumeMount{
+		{Name: "prometheus-config", MountPath: prometheusConfigDir, ReadOnly: true},
+		{Name: "monitor-data", MountPath: "/data"},
+		{Name: "prometheus-rules", MountPath: "/prometheus-rules"},
+	}
+	if tlsSecret != nil {
+		promMounts = append(promMounts, corev1.VolumeMount{Name: "cluster-client-tls", MountPath: clientTLSDir, ReadOnly: true})
+		volumes = append(volumes, corev1.Volume{Name: "cluster-client-tls", VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{SecretName: tlsSecret.GetName()},
+		}})
+	}
+	containers := []corev1.Container{
+		{
+			Name:            "prometheus",
+			Image:           containerImage(tm.Spec.Prometheus.MonitorContainer, defaultPrometheusBaseImage, defaultPrometheusVersion),
+			ImagePullPolicy: containerPullPolicy(tm, tm.Spec.Prometheus.MonitorContainer),
+			Command: []string{
+				"/bin/prometheus",
+				"--web.enable-admin-api",
+				"--web.enable-lifecycle",
+				fmt.Sprintf("--log.level=%s", promLogLevel),
+				fmt.Sprintf("--config.file=%s", path.Join(prometheusConfigDir, prometheusConfigKey)),
+				"--storage.tsdb.path=/data/prometheus",
+				fmt.Sprintf("--storage.tsdb.retention=%dd", reserveDays),
+			},
+			Ports: []corev1.ContainerPort{{
+				Name:          "prometheus",
+				ContainerPort: prometheusPort,
+				Protocol:      corev1.ProtocolTCP,
+			}},
+			VolumeMounts: promMounts,
+			Resources:    util.ResourceRequirement(tm.Spec.Prometheus.Resources),
+		},
+		{
+			Name:            "reloader",
+			Image:           containerImage(tm.Spec.Reloader.MonitorContainer, defaultReloaderBaseImage, defaultReloaderVersion),
+			ImagePullPolicy: containerPullPolicy(tm, tm.Spec.Reloader.MonitorContainer),
+			Command: []string{
+				"/bin/reload",
+				"--root-store-path=/data",
+				fmt.Sprintf("--sub-store-path=%s", version),
+				"--watch-path=/prometheus-rules/rules",
+				fmt.Sprintf("--prometheus-url=http://127.0.0.1:%d", prometheusPort),
+			},
+			Ports: []corev1.ContainerPort{{
+				Name:          "reloader",
+				ContainerPort: reloaderPort,
+				Protocol:      corev1.ProtocolTCP,
+			}},
+			VolumeMounts: []corev1.VolumeMount{
+				{Name: "prometheus-rules", MountPath: "/prometheus-rules"},
+				{Name: "monitor-data", MountPath: "/data"},
+			},
+			Resources: util.ResourceRequirement(tm.Spec.Reloader.Resources),
+		},
+	}
+
+	if tm.Spec.Grafana != nil {
+		grafanaLogLevel := tm.Spec.Grafana.LogLevel
+		if grafanaLogLevel == "" {
+			grafanaLogLevel = defaultLogLevel
+		}
+		secretName := grafanaSecretName(tm)
+		envs := []corev1.EnvVar{
+			{Name: "GF_PATHS_DATA", Value: "/data/grafana"},
+			{Name: "GF_LOG_LEVEL", Value: grafanaLogLevel},
+			{Name: "GF_SECURITY_ADMIN_USER", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+				Key:                  "username",
+			}}},
+			{Name: "GF_SECURITY_ADMIN_PASSWORD", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+				Key:                  "password",
+			}}},
+		}
+		envs = append(envs, sortedEnvs(tm.Spec.Grafana.Envs)...)
+		containers = append(containers, corev1.Container{
+			Name:            "grafana",
+			Image:           containerImage(tm.Spec.Grafana.MonitorContainer, defaultGrafanaBaseImage, defaultGrafanaVersion),
+			ImagePullPolicy: containerPullPolicy(tm, tm.Spec.Grafana.MonitorContainer),
+			Ports: []corev1.ContainerPort{{
+				Name:          "grafana",
+				ContainerPort: grafanaPort,
+				Protocol:      corev1.ProtocolTCP,
+			}},
+			Env: envs,
+			VolumeMounts: []corev1.VolumeMount{
+				{Name: "monitor-data", MountPath: "/data"},
+				{Name: "datasource", MountPath: "/etc/grafana/provisioning/datasources"},
+				{Name: "dashboards-provisioning", MountPath: "/etc/grafana/provisioning/dashboards"},
+				{Name: "grafana-dashboard", MountPath: "/grafana-dashboard-definitions/tidb"},
+			},
+			Resources: util.ResourceRequirement(tm.Spec.Grafana.Resources),
+		})
+		volumes = append(volumes, corev1.Volume{Name: "dashboards-provisioning", VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: cm.GetName()},
+				Items:                []corev1.KeyToPath{{Key: dashboardConfigKey, Path: dashboardConfigKey}},
+			},
+		}})
+	}
+
+	podAnnotations := map[string]string{
+		prometheusConfigHashAnnotation: fmt.Sprintf("%x", sha256.Sum256([]byte(cm.Data[prometheusConfigKey])))[:16],
+	}
+	for k, v := range tm.Spec.Annotations {
+		podAnnotations[k] = v
+	}
+
+	return &apps.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            controller.TidbMonitorMemberName(tm.GetName()),
+			Namespace:       tm.GetNamespace(),
+			Labels:          label.NewMonitor().Instance(tm.GetName()).Monitor(),
+			OwnerReferences: []metav1.OwnerReference{controller.GetTidbMonitorOwnerRef(tm)},
+		},
+		Spec: apps.DeploymentSpec{
+			Replicas: controller.Int32Ptr(1),
+			// Prometheus locks the data dir, the new Pod can't start before the old one exits
+			Strategy: apps.DeploymentStrategy{Type: apps.RecreateDeploymentStrategyType},
+			Selector: monitorLabel.LabelSelector(),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      monitorLabel.Labels(),
+					Annotations: podAnnotations,
+				},
+				Spec: corev1.PodSpec{
+					InitContainers: []corev1.Container{initializer},
+					Containers:     containers,
+					Volumes:        volumes,
+					NodeSelector:   tm.Spec.NodeSelector,
+					Tolerations:    tm.Spec.Tolerations,
+				},
+			},
+		},
+	}
+}
+
+// sortedEnvs returns the environment variables of the map sorted by the name
+func sortedEnvs(envs map[string]string) []corev1.EnvVar {
+	names := make([]string, 0, len(envs))
+	for name := range envs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	vars := make([]corev1.EnvVar, 0, len(names))
+	for _, name := range names {
+		vars = append(vars, corev1.EnvVar{Name: name, Value: envs[name]})
+	}
+	return vars
+}
@@ -126,6 +126,10 @@ type ReplicationConfig struct {
 	// For example, ["zone", "rack"] means that we should place replicas to
 	// different zones first, then to different racks if we don't have enough zones.
 	LocationLabels typeutil.StringSlice `toml:"location-labels,omitempty" json:"location-labels"`
+
+	// IsolationLevel is the minimum topology level the replicas of a region are isolated at, it's
+	// one of the location labels. It's supported since PD v4.0
+	IsolationLevel string `toml:"isolation-level,omitempty" json:"isolation-level,omitempty"`
 }
 
 // ScheduleConfig is the schedule configuration.
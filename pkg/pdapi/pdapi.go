@@ -30,8 +30,8 @@ import (
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/pingcap/pd/pkg/typeutil"
 	"github.com/pingcap/tidb-operator/pkg/httputil"
-	certutil "github.com/pingcap/tidb-operator/pkg/util/crypto"
-	types "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -46,62 +46,113 @@ type Namespace string
 type PDControlInterface interface {
 	// GetPDClient provides PDClient of the tidb cluster.
 	GetPDClient(Namespace, string, bool) PDClient
+	// GetPDClientForHost provides PDClient of the PD cluster serving at host, e.g. the PD cluster of another
+	// tidb cluster, the TLS certificate of the tidb cluster is used if TLS is enabled.
+	GetPDClientForHost(namespace Namespace, tcName string, host string, tlsEnabled bool) PDClient
 }
 
 // defaultPDControl is the default implementation of PDControlInterface.
 type defaultPDControl struct {
-	mutex     sync.Mutex
-	kubeCli   kubernetes.Interface
-	pdClients map[string]PDClient
+	mutex      sync.Mutex
+	tlsConfigs *TLSConfigCache
+	pdClients  map[string]PDClient
+	// pdClientTLSConfigs are the TLS configs of the TLS enabled pd clients,
+	// the pd client is recreated once its certificate is rotated
+	pdClientTLSConfigs map[string]*tls.Config
+	// pdClientClusters are the clusters, namespace/name, of the TLS enabled pd clients,
+	// the pd clients of a cluster are evicted once its TLS config is evicted
+	pdClientClusters map[string]string
 }
 
-// NewDefaultPDControl returns a defaultPDControl instance
-func NewDefaultPDControl(kubeCli kubernetes.Interface) PDControlInterface {
-	return &defaultPDControl{kubeCli: kubeCli, pdClients: map[string]PDClient{}}
+// NewDefaultPDControl returns a defaultPDControl instance, the certificates of the TLS enabled clusters
+// are read from the Secrets in secretInformer
+func NewDefaultPDControl(secretInformer coreinformers.SecretInformer) PDControlInterface {
+	return newDefaultPDControl(NewTLSConfigCache(secretInformer))
+}
+
+func newDefaultPDControl(tlsConfigs *TLSConfigCache) *defaultPDControl {
+	pdc := &defaultPDControl{
+		tlsConfigs:         tlsConfigs,
+		pdClients:          map[string]PDClient{},
+		pdClientTLSConfigs: map[string]*tls.Config{},
+		pdClientClusters:   map[string]string{},
+	}
+	tlsConfigs.OnEvict(pdc.evictPDClients)
+	return pdc
+}
+
+// evictPDClients evicts the TLS enabled pd clients of the cluster
+func (pdc *defaultPDControl) evictPDClients(namespace Namespace, tcName string) {
+	pdc.mutex.Lock()
+	defer pdc.mutex.Unlock()
+
+	cluster := fmt.Sprintf("%s/%s", namespace, tcName)
+	for key, c := range pdc.pdClientClusters {
+		if c == cluster {
+			delete(pdc.pdClients, key)
+			delete(pdc.pdClientTLSConfigs, key)
+			delete(pdc.pdClientClusters, key)
+		}
+	}
 }
 
 // GetPDClient provides a PDClient of real pd cluster,if the PDClient not existing, it will create new one.
 func (pdc *defaultPDControl) GetPDClient(namespace Namespace, tcName string, tlsEnabled bool) PDClient {
+	return pdc.GetPDClientForHost(namespace, tcName, PDClientHost(namespace, tcName, ""), tlsEnabled)
+}
+
+// GetPDClientForHost provides a PDClient of the PD cluster serving at host, if the PDClient not existing, it will create new one.
+func (pdc *defaultPDControl) GetPDClientForHost(namespace Namespace, tcName string, host string, tlsEnabled bool) PDClient {
 	pdc.mutex.Lock()
 	defer pdc.mutex.Unlock()
 
 	var tlsConfig *tls.Config
+	var ca string
 	scheme := "http"
 	if tlsEnabled {
 		scheme = "https"
-		secretName := fmt.Sprintf("%s-pd-client", tcName)
-		secret, err := pdc.kubeCli.CoreV1().Secrets(string(namespace)).Get(secretName, types.GetOptions{})
+		var err error
+		tlsConfig, ca, err = pdc.tlsConfigs.Get(namespace, tcName)
 		if err != nil {
-			glog.Errorf("unable to load certificates from secret %s/%s, PDClient may not work: %v", namespace, secretName, err)
-			return &pdClient{url: PdClientURL(namespace, tcName, scheme), httpClient: &http.Client{Timeout: timeout}}
-		}
-
-		rootCAs, tlsCert, err := certutil.LoadCerts(secret.Data["cert"], secret.Data["key"])
-		if err != nil {
-			glog.Errorf("unable to load certificates for %s discovery, PDClient may not work: %v", namespace, err)
-			return &pdClient{url: PdClientURL(namespace, tcName, scheme), httpClient: &http.Client{Timeout: timeout}}
-		}
-		tlsConfig = &tls.Config{
-			RootCAs:      rootCAs,
-			Certificates: []tls.Certificate{tlsCert},
+			glog.Errorf("PDClient of %s/%s may not work: %v", namespace, tcName, err)
+			return &pdClient{url: fmt.Sprintf("%s://%s", scheme, host), httpClient: &http.Client{Timeout: timeout}}
 		}
 	}
 
-	key := pdClientKey(scheme, namespace, tcName)
-	if _, ok := pdc.pdClients[key]; !ok {
-		pdc.pdClients[key] = NewPDClient(PdClientURL(namespace, tcName, scheme), timeout, tlsConfig)
+	key := pdClientKey(scheme, namespace, tcName, ca)
+	if host != PDClientHost(namespace, tcName, "") {
+		key = fmt.Sprintf("%s@%s", key, host)
+	}
+	if _, ok := pdc.pdClients[key]; !ok || pdc.pdClientTLSConfigs[key] != tlsConfig {
+		pdc.pdClients[key] = NewPDClient(fmt.Sprintf("%s://%s", scheme, host), timeout, tlsConfig)
+		pdc.pdClientTLSConfigs[key] = tlsConfig
+		if tlsConfig != nil {
+			pdc.pdClientClusters[key] = fmt.Sprintf("%s/%s", namespace, tcName)
+		}
 	}
 	return pdc.pdClients[key]
 }
 
-// pdClientKey returns the pd client key
-func pdClientKey(scheme string, namespace Namespace, clusterName string) string {
-	return fmt.Sprintf("%s.%s.%s", scheme, clusterName, string(namespace))
+// pdClientKey returns the pd client key, the clients of a cluster trusting different CAs are kept apart
+func pdClientKey(scheme string, namespace Namespace, clusterName string, caID string) string {
+	if caID == "" {
+		return fmt.Sprintf("%s.%s.%s", scheme, clusterName, string(namespace))
+	}
+	return fmt.Sprintf("%s.%s.%s.%s", scheme, clusterName, string(namespace), caID)
 }
 
 // pdClientUrl builds the url of pd client
 func PdClientURL(namespace Namespace, clusterName string, scheme string) string {
-	return fmt.Sprintf("%s://%s-pd.%s:2379", scheme, clusterName, string(namespace))
+	return fmt.Sprintf("%s://%s", scheme, PDClientHost(namespace, clusterName, ""))
+}
+
+// PDClientHost returns the host of the pd service of the tidb cluster, clusterDomain is the domain of
+// the Kubernetes cluster where the tidb cluster runs, empty means the local Kubernetes cluster
+func PDClientHost(namespace Namespace, clusterName string, clusterDomain string) string {
+	if clusterDomain == "" {
+		return fmt.Sprintf("%s-pd.%s:2379", clusterName, string(namespace))
+	}
+	return fmt.Sprintf("%s-pd.%s.svc.%s:2379", clusterName, string(namespace), clusterDomain)
 }
 
 // PDClient provides pd server's api
@@ -120,9 +171,14 @@ type PDClient interface {
 	GetTombStoneStores() (*StoresInfo, error)
 	// GetStore gets a TiKV store for a specific store id from cluster
 	GetStore(storeID uint64) (*StoreInfo, error)
+	// GetUnhealthyRegions lists the regions which have down or pending peers
+	GetUnhealthyRegions() (*RegionsInfo, error)
 	// storeLabelsEqualNodeLabels compares store labels with node labels
 	// for historic reasons, PD stores TiKV labels as []*StoreLabel which is a key-value pair slice
 	SetStoreLabels(storeID uint64, labels map[string]string) (bool, error)
+	// SetLocationLabels sets the location-labels and the isolation-level of the replication config,
+	// the isolation-level is not changed if it's empty
+	SetLocationLabels(locationLabels []string, isolationLevel string) error
 	// DeleteStore deletes a TiKV store from cluster
 	DeleteStore(storeID uint64) error
 	// DeleteMember deletes a PD member from cluster
@@ -148,10 +204,12 @@ var (
 	storesPrefix           = "pd/api/v1/stores"
 	storePrefix            = "pd/api/v1/store"
 	configPrefix           = "pd/api/v1/config"
+	replicateConfigPrefix  = "pd/api/v1/config/replicate"
 	clusterIDPrefix        = "pd/api/v1/cluster"
 	schedulersPrefix       = "pd/api/v1/schedulers"
 	pdLeaderPrefix         = "pd/api/v1/leader"
 	pdLeaderTransferPrefix = "pd/api/v1/leader/transfer"
+	regionsCheckPrefix     = "pd/api/v1/regions/check"
 )
 
 // pdClient is default implementation of PDClient
@@ -221,6 +279,21 @@ type StoresInfo struct {
 	Stores []*StoreInfo `json:"stores"`
 }
 
+// RegionInfo is a single region info returned from PD RESTful interface
+type RegionInfo struct {
+	ID           uint64            `json:"id"`
+	Peers        []*metapb.Peer    `json:"peers,omitempty"`
+	Leader       *metapb.Peer      `json:"leader,omitempty"`
+	DownPeers    []*pdpb.PeerStats `json:"down_peers,omitempty"`
+	PendingPeers []*metapb.Peer    `json:"pending_peers,omitempty"`
+}
+
+// RegionsInfo is regions info returned from PD RESTful interface
+type RegionsInfo struct {
+	Count   int           `json:"count"`
+	Regions []*RegionInfo `json:"regions"`
+}
+
 // MembersInfo is PD members info returned from PD RESTful interface
 //type Members map[string][]*pdpb.Member
 type MembersInfo struct {
@@ -335,6 +408,25 @@ func (pc *pdClient) GetStore(storeID uint64) (*StoreInfo, error) {
 	return storeInfo, nil
 }
 
+func (pc *pdClient) GetUnhealthyRegions() (*RegionsInfo, error) {
+	regionsInfo := &RegionsInfo{}
+	for _, state := range []string{"down-peer", "pending-peer"} {
+		apiURL := fmt.Sprintf("%s/%s/%s", pc.url, regionsCheckPrefix, state)
+		body, err := httputil.GetBodyOK(pc.httpClient, apiURL)
+		if err != nil {
+			return nil, err
+		}
+		regions := &RegionsInfo{}
+		err = json.Unmarshal(body, regions)
+		if err != nil {
+			return nil, err
+		}
+		regionsInfo.Count += regions.Count
+		regionsInfo.Regions = append(regionsInfo.Regions, regions.Regions...)
+	}
+	return regionsInfo, nil
+}
+
 func (pc *pdClient) DeleteStore(storeID uint64) error {
 	var exist bool
 	stores, err := pc.GetStores()
@@ -455,6 +547,29 @@ func (pc *pdClient) SetStoreLabels(storeID uint64, labels map[string]string) (bo
 	return false, fmt.Errorf("failed %v to set store labels: %v", res.StatusCode, err2)
 }
 
+func (pc *pdClient) SetLocationLabels(locationLabels []string, isolationLevel string) error {
+	apiURL := fmt.Sprintf("%s/%s", pc.url, replicateConfigPrefix)
+	// only the changed items are posted, PD keeps the others
+	config := map[string]string{"location-labels": strings.Join(locationLabels, ",")}
+	if isolationLevel != "" {
+		config["isolation-level"] = isolationLevel
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	res, err := pc.httpClient.Post(apiURL, "application/json", bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	defer httputil.DeferClose(res.Body)
+	if res.StatusCode == http.StatusOK {
+		return nil
+	}
+	err2 := httputil.ReadErrorBody(res.Body)
+	return fmt.Errorf("failed %v to set location labels: %v", res.StatusCode, err2)
+}
+
 func (pc *pdClient) BeginEvictLeader(storeID uint64) error {
 	leaderEvictInfo := getLeaderEvictSchedulerInfo(storeID)
 	apiURL := fmt.Sprintf("%s/%s", pc.url, schedulersPrefix)
@@ -616,13 +731,19 @@ type FakePDControl struct {
 }
 
 func NewFakePDControl(kubeCli kubernetes.Interface) *FakePDControl {
+	secretInformer := kubeinformers.NewSharedInformerFactory(kubeCli, 0).Core().V1().Secrets()
 	return &FakePDControl{
-		defaultPDControl{kubeCli: kubeCli, pdClients: map[string]PDClient{}},
+		*newDefaultPDControl(NewTLSConfigCache(secretInformer)),
 	}
 }
 
 func (fpc *FakePDControl) SetPDClient(namespace Namespace, tcName string, pdclient PDClient) {
-	fpc.defaultPDControl.pdClients[pdClientKey("http", namespace, tcName)] = pdclient
+	fpc.defaultPDControl.pdClients[pdClientKey("http", namespace, tcName, "")] = pdclient
+}
+
+// SetPDClientForHost sets the PDClient returned by GetPDClientForHost
+func (fpc *FakePDControl) SetPDClientForHost(namespace Namespace, tcName string, host string, pdclient PDClient) {
+	fpc.defaultPDControl.pdClients[fmt.Sprintf("%s@%s", pdClientKey("http", namespace, tcName, ""), host)] = pdclient
 }
 
 type ActionType string
@@ -635,10 +756,12 @@ const (
 	GetStoresActionType                ActionType = "GetStores"
 	GetTombStoneStoresActionType       ActionType = "GetTombStoneStores"
 	GetStoreActionType                 ActionType = "GetStore"
+	GetUnhealthyRegionsActionType      ActionType = "GetUnhealthyRegions"
 	DeleteStoreActionType              ActionType = "DeleteStore"
 	DeleteMemberByIDActionType         ActionType = "DeleteMemberByID"
 	DeleteMemberActionType             ActionType = "DeleteMember "
 	SetStoreLabelsActionType           ActionType = "SetStoreLabels"
+	SetLocationLabelsActionType        ActionType = "SetLocationLabels"
 	BeginEvictLeaderActionType         ActionType = "BeginEvictLeader"
 	EndEvictLeaderActionType           ActionType = "EndEvictLeader"
 	GetEvictLeaderSchedulersActionType ActionType = "GetEvictLeaderSchedulers"
@@ -655,9 +778,10 @@ func (nfr *NotFoundReaction) Error() string {
 }
 
 type Action struct {
-	ID     uint64
-	Name   string
-	Labels map[string]string
+	ID             uint64
+	Name           string
+	Labels         map[string]string
+	LocationLabels []string
 }
 
 type Reaction func(action *Action) (interface{}, error)
@@ -751,6 +875,15 @@ func (pc *FakePDClient) GetStore(id uint64) (*StoreInfo, error) {
 	return result.(*StoreInfo), nil
 }
 
+func (pc *FakePDClient) GetUnhealthyRegions() (*RegionsInfo, error) {
+	if reaction, ok := pc.reactions[GetUnhealthyRegionsActionType]; ok {
+		action := &Action{}
+		result, err := reaction(action)
+		return result.(*RegionsInfo), err
+	}
+	return &RegionsInfo{}, nil
+}
+
 func (pc *FakePDClient) DeleteStore(id uint64) error {
 	if reaction, ok := pc.reactions[DeleteStoreActionType]; ok {
 		action := &Action{ID: id}
@@ -788,6 +921,16 @@ func (pc *FakePDClient) SetStoreLabels(storeID uint64, labels map[string]string)
 	return true, nil
 }
 
+// SetLocationLabels sets the location labels and the isolation level
+func (pc *FakePDClient) SetLocationLabels(locationLabels []string, isolationLevel string) error {
+	if reaction, ok := pc.reactions[SetLocationLabelsActionType]; ok {
+		action := &Action{Name: isolationLevel, LocationLabels: locationLabels}
+		_, err := reaction(action)
+		return err
+	}
+	return nil
+}
+
 func (pc *FakePDClient) BeginEvictLeader(storeID uint64) error {
 	if reaction, ok := pc.reactions[BeginEvictLeaderActionType]; ok {
 		action := &Action{ID: storeID}
@@ -308,6 +308,51 @@ func TestGetStore(t *testing.T) {
 	}
 }
 
+func TestGetUnhealthyRegions(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	downPeer := &metapb.Peer{Id: 2, StoreId: 2}
+	pendingPeer := &metapb.Peer{Id: 5, StoreId: 3}
+	regions := map[string]*RegionsInfo{
+		fmt.Sprintf("/%s/down-peer", regionsCheckPrefix): {
+			Count: 1,
+			Regions: []*RegionInfo{{
+				ID:        1,
+				Peers:     []*metapb.Peer{{Id: 1, StoreId: 1}, downPeer},
+				DownPeers: []*pdpb.PeerStats{{Peer: downPeer, DownSeconds: 60}},
+			}},
+		},
+		fmt.Sprintf("/%s/pending-peer", regionsCheckPrefix): {
+			Count: 1,
+			Regions: []*RegionInfo{{
+				ID:           4,
+				Peers:        []*metapb.Peer{{Id: 4, StoreId: 1}, pendingPeer},
+				PendingPeers: []*metapb.Peer{pendingPeer},
+			}},
+		},
+	}
+
+	svc := getClientServer(func(w http.ResponseWriter, request *http.Request) {
+		g.Expect(request.Method).To(Equal("GET"), "test method")
+		resp, ok := regions[request.URL.Path]
+		g.Expect(ok).To(BeTrue(), "test url")
+
+		respBytes, err := json.Marshal(resp)
+		g.Expect(err).NotTo(HaveOccurred())
+		w.Header().Set("Content-Type", ContentTypeJSON)
+		w.Write(respBytes)
+	})
+	defer svc.Close()
+
+	pdClient := NewPDClient(svc.URL, timeout, &tls.Config{})
+	result, err := pdClient.GetUnhealthyRegions()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result.Count).To(Equal(2))
+	g.Expect(result.Regions).To(HaveLen(2))
+	g.Expect(result.Regions[0].DownPeers[0].Peer.StoreId).To(Equal(uint64(2)))
+	g.Expect(result.Regions[1].PendingPeers[0].StoreId).To(Equal(uint64(3)))
+}
+
 func TestSetStoreLabels(t *testing.T) {
 	g := NewGomegaWithT(t)
 	id := uint64(1)
@@ -356,6 +401,54 @@ func TestSetStoreLabels(t *testing.T) {
 	}
 }
 
+func TestSetLocationLabels(t *testing.T) {
+	g := NewGomegaWithT(t)
+	tcs := []struct {
+		caseName       string
+		isolationLevel string
+		expectConfig   map[string]string
+		want           bool
+	}{{
+		caseName:       "success_SetLocationLabels",
+		isolationLevel: "zone",
+		expectConfig:   map[string]string{"location-labels": "zone,host", "isolation-level": "zone"},
+		want:           true,
+	}, {
+		caseName:     "success_SetLocationLabels_without_isolation_level",
+		expectConfig: map[string]string{"location-labels": "zone,host"},
+		want:         true,
+	}, {
+		caseName:     "failed_SetLocationLabels",
+		expectConfig: map[string]string{"location-labels": "zone,host"},
+		want:         false,
+	},
+	}
+
+	for _, tc := range tcs {
+		svc := getClientServer(func(w http.ResponseWriter, request *http.Request) {
+			g.Expect(request.Method).To(Equal("POST"), "check method")
+			g.Expect(request.URL.Path).To(Equal("/"+replicateConfigPrefix), "check url")
+
+			config := map[string]string{}
+			err := readJSON(request.Body, &config)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(config).To(Equal(tc.expectConfig), "check config")
+
+			w.Header().Set("Content-Type", ContentTypeJSON)
+			if tc.want {
+				w.WriteHeader(http.StatusOK)
+			} else {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		})
+		defer svc.Close()
+
+		pdClient := NewPDClient(svc.URL, timeout, &tls.Config{})
+		err := pdClient.SetLocationLabels([]string{"zone", "host"}, tc.isolationLevel)
+		g.Expect(err == nil).To(Equal(tc.want), tc.caseName)
+	}
+}
+
 func TestDeleteMember(t *testing.T) {
 	g := NewGomegaWithT(t)
 	name := "testMember"
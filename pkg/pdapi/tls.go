@@ -0,0 +1,168 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pdapi
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"fmt"
+	"strings"
+	"sync"
+
+	certutil "github.com/pingcap/tidb-operator/pkg/util/crypto"
+	corev1 "k8s.io/api/core/v1"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// pdClientSecretSuffix is the suffix of the name of the PD client Secret of a cluster, <cluster>-pd-client
+const pdClientSecretSuffix = "-pd-client"
+
+// TLSConfigCache builds the TLS configs used by tidb-operator to access the components of the clusters
+// from the PD client Secrets of the clusters. The configs are cached per cluster, the config of a cluster
+// is evicted once its Secret is rotated or deleted.
+type TLSConfigCache struct {
+	mutex         sync.Mutex
+	secretLister  corelisters.SecretLister
+	configs       map[string]*clusterTLSConfig
+	evictHandlers []func(namespace Namespace, tcName string)
+}
+
+type clusterTLSConfig struct {
+	cert   []byte
+	ca     []byte
+	caID   string
+	config *tls.Config
+}
+
+// NewTLSConfigCache returns a TLSConfigCache reading the Secrets from secretInformer
+func NewTLSConfigCache(secretInformer coreinformers.SecretInformer) *TLSConfigCache {
+	c := &TLSConfigCache{
+		secretLister: secretInformer.Lister(),
+		configs:      map[string]*clusterTLSConfig{},
+	}
+	secretInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, cur interface{}) {
+			oldSecret := old.(*corev1.Secret)
+			curSecret := cur.(*corev1.Secret)
+			if oldSecret.ResourceVersion != curSecret.ResourceVersion {
+				c.evictSecret(curSecret, false)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if secret, ok := obj.(*corev1.Secret); ok {
+				c.evictSecret(secret, true)
+			}
+		},
+	})
+	return c
+}
+
+// OnEvict registers a handler called after the config of a cluster is evicted, so that the clients
+// built with the config are evicted too
+func (c *TLSConfigCache) OnEvict(handler func(namespace Namespace, tcName string)) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.evictHandlers = append(c.evictHandlers, handler)
+}
+
+// Get returns the TLS config of the cluster and the ID of the CA trusted by the config, the ID is
+// empty if the Kubernetes cluster CA is trusted. The same config is returned until the Secret is rotated.
+func (c *TLSConfigCache) Get(namespace Namespace, tcName string) (*tls.Config, string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	cacheKey := fmt.Sprintf("%s/%s", namespace, tcName)
+	if cached, ok := c.configs[cacheKey]; ok {
+		return cached.config, cached.caID, nil
+	}
+
+	secretName := tcName + pdClientSecretSuffix
+	secret, err := c.secretLister.Secrets(string(namespace)).Get(secretName)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to load certificates from secret %s/%s: %v", namespace, secretName, err)
+	}
+	cert, key, ca := loadTLSSecretData(secret)
+	rootCAs, tlsCert, err := certutil.LoadCerts(cert, key, ca)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to load certificates from secret %s/%s: %v", namespace, secretName, err)
+	}
+	cached := &clusterTLSConfig{
+		cert: cert,
+		ca:   ca,
+		caID: caID(ca),
+		config: &tls.Config{
+			RootCAs:      rootCAs,
+			Certificates: []tls.Certificate{tlsCert},
+		},
+	}
+	c.configs[cacheKey] = cached
+	return cached.config, cached.caID, nil
+}
+
+// evictSecret evicts the config of the cluster if the Secret is the PD client Secret of the cluster,
+// and the Secret is deleted or the certificate or the CA in it is rotated
+func (c *TLSConfigCache) evictSecret(secret *corev1.Secret, deleted bool) {
+	if !strings.HasSuffix(secret.GetName(), pdClientSecretSuffix) {
+		return
+	}
+	namespace := Namespace(secret.GetNamespace())
+	tcName := strings.TrimSuffix(secret.GetName(), pdClientSecretSuffix)
+	cacheKey := fmt.Sprintf("%s/%s", namespace, tcName)
+
+	c.mutex.Lock()
+	cached, ok := c.configs[cacheKey]
+	if ok && !deleted {
+		// the config is kept if the Secret is updated without rotating the certificate, e.g. relabeled
+		cert, _, ca := loadTLSSecretData(secret)
+		ok = !bytes.Equal(cached.cert, cert) || !bytes.Equal(cached.ca, ca)
+	}
+	if !ok {
+		c.mutex.Unlock()
+		return
+	}
+	delete(c.configs, cacheKey)
+	handlers := c.evictHandlers
+	c.mutex.Unlock()
+
+	for _, handler := range handlers {
+		handler(namespace, tcName)
+	}
+}
+
+// caID returns the ID of the CA, which is empty for the Kubernetes cluster CA
+func caID(ca []byte) string {
+	if len(ca) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(ca))[:16]
+}
+
+// loadTLSSecretData returns the cert, key and CA in the Secret, which is either created by tidb-operator
+// or of the kubernetes.io/tls type
+func loadTLSSecretData(secret *corev1.Secret) ([]byte, []byte, []byte) {
+	ca, ok := secret.Data["ca"]
+	if !ok {
+		ca = secret.Data[corev1.ServiceAccountRootCAKey]
+	}
+	if _, ok := secret.Data["cert"]; !ok {
+		return secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey], ca
+	}
+	return secret.Data["cert"], secret.Data["key"], ca
+}
@@ -0,0 +1,116 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pdapi
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	certutil "github.com/pingcap/tidb-operator/pkg/util/crypto"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestTLSConfigCache(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	kubeCli := kubefake.NewSimpleClientset()
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeCli, 0)
+	cache := NewTLSConfigCache(kubeInformerFactory.Core().V1().Secrets())
+	pdc := newDefaultPDControl(cache)
+	ns := Namespace(metav1.NamespaceDefault)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	kubeInformerFactory.Start(stopCh)
+	kubeInformerFactory.WaitForCacheSync(stopCh)
+
+	_, _, err := cache.Get(ns, "demo")
+	g.Expect(err).To(HaveOccurred())
+
+	secret := savePDClientSecret(g, kubeCli, "1", false)
+	g.Eventually(func() error {
+		_, _, err := cache.Get(ns, "demo")
+		return err
+	}, 5*time.Second, 10*time.Millisecond).Should(Succeed())
+	config, caID, err := cache.Get(ns, "demo")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(caID).NotTo(BeEmpty())
+	cached, cachedCAID, err := cache.Get(ns, "demo")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cached).To(BeIdenticalTo(config))
+	g.Expect(cachedCAID).To(Equal(caID))
+	pdClient := pdc.GetPDClient(ns, "demo", true)
+	g.Expect(pdc.GetPDClient(ns, "demo", true)).To(BeIdenticalTo(pdClient))
+
+	// the Secret is updated without rotating the certificate
+	secret.Labels = map[string]string{"app": "demo"}
+	cache.evictSecret(secret, false)
+	cached, _, err = cache.Get(ns, "demo")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cached).To(BeIdenticalTo(config))
+	g.Expect(pdc.GetPDClient(ns, "demo", true)).To(BeIdenticalTo(pdClient))
+
+	// the CA of the cluster is rotated
+	savePDClientSecret(g, kubeCli, "2", true)
+	g.Eventually(func() bool {
+		pdc.mutex.Lock()
+		defer pdc.mutex.Unlock()
+		return len(pdc.pdClients) == 0
+	}, 5*time.Second, 10*time.Millisecond).Should(BeTrue())
+	rotated, rotatedCAID, err := cache.Get(ns, "demo")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rotated).NotTo(BeIdenticalTo(config))
+	g.Expect(rotatedCAID).NotTo(Equal(caID))
+	g.Expect(pdc.GetPDClient(ns, "demo", true)).NotTo(BeIdenticalTo(pdClient))
+
+	// the Secret is deleted
+	err = kubeCli.CoreV1().Secrets(metav1.NamespaceDefault).Delete("demo-pd-client", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Eventually(func() error {
+		_, _, err := cache.Get(ns, "demo")
+		return err
+	}, 5*time.Second, 10*time.Millisecond).Should(HaveOccurred())
+	pdc.mutex.Lock()
+	defer pdc.mutex.Unlock()
+	g.Expect(pdc.pdClients).To(BeEmpty())
+	g.Expect(pdc.pdClientTLSConfigs).To(BeEmpty())
+	g.Expect(pdc.pdClientClusters).To(BeEmpty())
+}
+
+func savePDClientSecret(g *GomegaWithT, kubeCli kubernetes.Interface, resourceVersion string, update bool) *corev1.Secret {
+	caCert, caKey, err := certutil.NewCA("demo CA")
+	g.Expect(err).NotTo(HaveOccurred())
+	cert, key, err := certutil.SignCert(caCert, caKey, "demo-pd-client", []string{"demo-pd-client"}, nil)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo-pd-client", Namespace: metav1.NamespaceDefault, ResourceVersion: resourceVersion},
+		Data: map[string][]byte{
+			"cert": cert,
+			"key":  key,
+			"ca":   caCert,
+		},
+	}
+	if update {
+		secret, err = kubeCli.CoreV1().Secrets(metav1.NamespaceDefault).Update(secret)
+	} else {
+		secret, err = kubeCli.CoreV1().Secrets(metav1.NamespaceDefault).Create(secret)
+	}
+	g.Expect(err).NotTo(HaveOccurred())
+	return secret
+}
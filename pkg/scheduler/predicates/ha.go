@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned"
 	"github.com/pingcap/tidb-operator/pkg/label"
 	apiv1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -43,11 +44,16 @@ type ha struct {
 	podListFn     func(ns, instanceName, component string) (*apiv1.PodList, error)
 	podGetFn      func(ns, podName string) (*apiv1.Pod, error)
 	pvcGetFn      func(ns, pvcName string) (*apiv1.PersistentVolumeClaim, error)
+	nodeGetFn     func(nodeName string) (*apiv1.Node, error)
 	tcGetFn       func(ns, tcName string) (*v1alpha1.TidbCluster, error)
 	pvcListFn     func(ns, instanceName, component string) (*apiv1.PersistentVolumeClaimList, error)
 	updatePVCFn   func(*apiv1.PersistentVolumeClaim) error
 	acquireLockFn func(*apiv1.Pod) (*apiv1.PersistentVolumeClaim, *apiv1.PersistentVolumeClaim, error)
-	recorder      record.EventRecorder
+	// acquireTiDBLockFn serializes the scheduling of the TiDB pods of a tidbcluster, which have no PVC to lock
+	acquireTiDBLockFn func(*apiv1.Pod, string) error
+	// tidbSchedulingPods is the name of the TiDB pod being scheduled, keyed by the namespace and name of its tidbcluster
+	tidbSchedulingPods map[string]string
+	recorder           record.EventRecorder
 }
 
 // NewHA returns a Predicate
@@ -60,10 +66,12 @@ func NewHA(kubeCli kubernetes.Interface, cli versioned.Interface, recorder recor
 	h.podListFn = h.realPodListFn
 	h.podGetFn = h.realPodGetFn
 	h.pvcGetFn = h.realPVCGetFn
+	h.nodeGetFn = h.realNodeGetFn
 	h.tcGetFn = h.realTCGetFn
 	h.pvcListFn = h.realPVCListFn
 	h.updatePVCFn = h.realUpdatePVCFn
 	h.acquireLockFn = h.realAcquireLock
+	h.acquireTiDBLockFn = h.realAcquireTiDBLock
 	return h
 }
 
@@ -78,7 +86,11 @@ func (h *ha) Name() string {
 //  b) for TiKV (multiple raft groups, in each raft group, copies of data is hard-coded to 3)
 //     when replicas is less than 3, no HA is forced because HA is impossible
 //     when replicas is equal or greater than 3, we require TiKV pods are running on more than 3 nodes and no more than ceil(replicas / 3) per node
-//  for PD/TiKV, we both try to balance the number of pods acorss the nodes
+//  c) for TiDB (stateless), if spec.tidb.ha is set, no more than ceil(replicas / nodes) pods per node
+//  for PD/TiKV/TiDB, we all try to balance the number of pods acorss the nodes
+//  if the topology key of the tidbcluster is set, e.g. topology.kubernetes.io/zone, the rules apply to the topology
+//  domains as well as the nodes, so that the majority of replicas survives the loss of a whole zone, and the pods
+//  in a zone are still spread across its nodes
 // 3. let kube-scheduler to make the final decision
 func (h *ha) Filter(instanceName string, pod *apiv1.Pod, nodes []apiv1.Node) ([]apiv1.Node, error) {
 	h.lock.Lock()
@@ -89,7 +101,7 @@ func (h *ha) Filter(instanceName string, pod *apiv1.Pod, nodes []apiv1.Node) ([]
 	component := pod.Labels[label.ComponentLabelKey]
 	tcName := getTCNameFromPod(pod, component)
 
-	if component != label.PDLabelVal && component != label.TiKVLabelVal {
+	if component != label.PDLabelVal && component != label.TiKVLabelVal && component != label.TiDBLabelVal {
 		glog.V(4).Infof("component %s is ignored in HA predicate", component)
 		return nodes, nil
 	}
@@ -97,133 +109,290 @@ func (h *ha) Filter(instanceName string, pod *apiv1.Pod, nodes []apiv1.Node) ([]
 	if len(nodes) == 0 {
 		return nil, fmt.Errorf("kube nodes is empty")
 	}
-	if _, _, err := h.acquireLockFn(pod); err != nil {
-		return nil, err
-	}
 
-	if len(nodes) == 1 {
-		pvcName := pvcName(component, podName)
-		pvc, err := h.pvcGetFn(ns, pvcName)
+	var tc *v1alpha1.TidbCluster
+	var err error
+	if component == label.TiDBLabelVal {
+		// TiDB pods are spread only if spec.tidb.ha is set. The pods of the TiDB groups don't belong
+		// to the tidbcluster named after the StatefulSet and are not spread
+		tc, err = h.tcGetFn(ns, tcName)
 		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return nodes, nil
+			}
 			return nil, err
 		}
-		if pvc.Status.Phase == apiv1.ClaimBound {
+		if !tc.Spec.TiDB.HA {
 			return nodes, nil
 		}
+		if err := h.acquireTiDBLockFn(pod, tcName); err != nil {
+			return nil, err
+		}
+	} else {
+		if _, _, err := h.acquireLockFn(pod); err != nil {
+			return nil, err
+		}
+
+		if len(nodes) == 1 {
+			pvcName := pvcName(component, podName)
+			pvc, err := h.pvcGetFn(ns, pvcName)
+			if err != nil {
+				return nil, err
+			}
+			if pvc.Status.Phase == apiv1.ClaimBound {
+				return nodes, nil
+			}
+		}
 	}
 
 	podList, err := h.podListFn(ns, instanceName, component)
 	if err != nil {
 		return nil, err
 	}
-	tc, err := h.tcGetFn(ns, tcName)
-	if err != nil {
-		return nil, err
+	if tc == nil {
+		tc, err = h.tcGetFn(ns, tcName)
+		if err != nil {
+			return nil, err
+		}
 	}
 	replicas := getReplicasFrom(tc, component)
 	glog.Infof("ha: tidbcluster %s/%s component %s replicas %d", ns, tcName, component, replicas)
 
-	allNodes := make(sets.String)
-	nodeMap := make(map[string][]string)
+	topologyKey := tc.HATopologyKey()
+	nodeTopologies := make(map[string]string)
 	for _, node := range nodes {
-		nodeMap[node.GetName()] = make([]string, 0)
+		if topologyKey == "" {
+			nodeTopologies[node.GetName()] = node.GetName()
+			continue
+		}
+		nodeTopologies[node.GetName()] = node.Labels[topologyKey]
+	}
+	// the pods in a topology domain are spread across its nodes like without the topology key,
+	// so that a node can't host more pods than allowed and the least loaded nodes are chosen
+	nodePods := countPodsPerNode(pod, component, podList)
+	if topologyKey != "" {
+		feasibleNodes := filterNodesByPodsPerNode(component, replicas, nodePods, nodes)
+		if len(feasibleNodes) == 0 {
+			msg := fmt.Sprintf("can't schedule to nodes: %v, because these pods had been scheduled to nodes: %v", GetNodeNames(nodes), nodePods)
+			glog.Info(msg)
+			h.recorder.Event(pod, apiv1.EventTypeWarning, "FailedScheduling", msg)
+			return nil, errors.New(msg)
+		}
+		nodes = feasibleNodes
 	}
-	for _, pod := range podList.Items {
-		pName := pod.GetName()
-		nodeName := pod.Spec.NodeName
-		if nodeName != "" {
-			allNodes.Insert(nodeName)
+	// topologyOf returns the topology domain of the node, it's the node itself if there is no topology key
+	topologyOf := func(nodeName string) (string, error) {
+		if topologyKey == "" {
+			return nodeName, nil
 		}
-		if nodeName == "" || nodeMap[nodeName] == nil {
+		if topology, ok := nodeTopologies[nodeName]; ok {
+			return topology, nil
+		}
+		node, err := h.nodeGetFn(nodeName)
+		if err != nil {
+			return "", err
+		}
+		nodeTopologies[nodeName] = node.Labels[topologyKey]
+		return nodeTopologies[nodeName], nil
+	}
+
+	allTopologies := make(sets.String)
+	topologyMap := make(map[string][]string)
+	for _, node := range nodes {
+		if topology := nodeTopologies[node.GetName()]; topology != "" {
+			topologyMap[topology] = make([]string, 0)
+		} else {
+			glog.Infof("node %s has no label %s, skipping", node.GetName(), topologyKey)
+		}
+	}
+	if len(topologyMap) == 0 {
+		msg := fmt.Sprintf("can't schedule to nodes: %v, because they have no label %s", GetNodeNames(nodes), topologyKey)
+		glog.Info(msg)
+		h.recorder.Event(pod, apiv1.EventTypeWarning, "FailedScheduling", msg)
+		return nil, errors.New(msg)
+	}
+	for _, p := range podList.Items {
+		pName := p.GetName()
+		nodeName := p.Spec.NodeName
+		if nodeName == "" {
+			continue
+		}
+		// only the pods of the same StatefulSet are counted, the TiDB groups share the labels of spec.tidb
+		if component == label.TiDBLabelVal && p.GenerateName != pod.GenerateName {
+			continue
+		}
+		topology, err := topologyOf(nodeName)
+		if err != nil {
+			return nil, err
+		}
+		if topology != "" {
+			allTopologies.Insert(topology)
+		}
+		if topology == "" || topologyMap[topology] == nil {
 			continue
 		}
 
-		nodeMap[nodeName] = append(nodeMap[nodeName], pName)
+		topologyMap[topology] = append(topologyMap[topology], pName)
 	}
-	glog.V(4).Infof("nodeMap: %+v", nodeMap)
+	glog.V(4).Infof("topologyMap: %+v", topologyMap)
 
+	// the pods are spread across the topology domains the same way as across the nodes
 	min := -1
-	minNodeNames := make([]string, 0)
-	for nodeName, podNames := range nodeMap {
+	minTopologies := make([]string, 0)
+	for topology, podNames := range topologyMap {
 		podsCount := len(podNames)
-		maxPodsPerNode := 0
-
-		if component == label.PDLabelVal {
-			/**
-			 * replicas     maxPodsPerNode
-			 * ---------------------------
-			 * 1            1
-			 * 2            1
-			 * 3            1
-			 * 4            1
-			 * 5            2
-			 * ...
-			 */
-			maxPodsPerNode = int((replicas+1)/2) - 1
-			if maxPodsPerNode <= 0 {
-				maxPodsPerNode = 1
-			}
-		} else {
-			// replicas less than 3 cannot achieve high availability
-			if replicas < 3 {
-				minNodeNames = append(minNodeNames, nodeName)
-				glog.Infof("replicas is %d, add node %s to minNodeNames", replicas, nodeName)
-				continue
-			}
-
-			// 1. TiKV instances must run on at least 3 nodes, otherwise HA is not possible
-			if allNodes.Len() < 3 {
-				maxPodsPerNode = 1
-			} else {
-				/**
-				 * 2. we requires TiKV instances to run on at least 3 nodes, so max
-				 * allowed pods on each node is ceil(replicas / 3)
-				 *
-				 * replicas     maxPodsPerNode   best HA on three nodes
-				 * ---------------------------------------------------
-				 * 3            1                1, 1, 1
-				 * 4            2                1, 1, 2
-				 * 5            2                1, 2, 2
-				 * 6            2                2, 2, 2
-				 * 7            3                2, 2, 3
-				 * 8            3                2, 3, 3
-				 * ...
-				 */
-				maxPodsPerNode = int(math.Ceil(float64(replicas) / 3))
-			}
+		// replicas less than 3 cannot achieve high availability
+		if component == label.TiKVLabelVal && replicas < 3 {
+			minTopologies = append(minTopologies, topology)
+			glog.Infof("replicas is %d, add %s to minTopologies", replicas, topology)
+			continue
 		}
-
-		if podsCount+1 > maxPodsPerNode {
-			// pods on this node exceeds the limit, skip
-			glog.Infof("node %s has %d instances of component %s, max allowed is %d, skipping",
-				nodeName, podsCount, component, maxPodsPerNode)
+		// TiDB instances are spread evenly across all the nodes or topology domains known, including
+		// the ones hosting TiDB pods but not feasible for this pod
+		knownTopologies := allTopologies.Union(sets.StringKeySet(topologyMap))
+		maxPodsPerTopology := maxPodsPerDomain(component, replicas, allTopologies.Len(), knownTopologies.Len())
+
+		if podsCount+1 > maxPodsPerTopology {
+			// pods on this node or topology domain exceeds the limit, skip
+			glog.Infof("%s has %d instances of component %s, max allowed is %d, skipping",
+				topology, podsCount, component, maxPodsPerTopology)
 			continue
 		}
 
-		// Choose nodes which has minimum count of the component
+		// Choose the nodes or topology domains which have minimum count of the component
 		if min == -1 {
 			min = podsCount
 		}
 		if podsCount > min {
-			glog.Infof("node %s podsCount %d > min %d, skipping", nodeName, podsCount, min)
+			glog.Infof("%s podsCount %d > min %d, skipping", topology, podsCount, min)
 			continue
 		}
 		if podsCount < min {
 			min = podsCount
-			minNodeNames = make([]string, 0)
+			minTopologies = make([]string, 0)
 		}
-		minNodeNames = append(minNodeNames, nodeName)
+		minTopologies = append(minTopologies, topology)
 	}
 
-	if len(minNodeNames) == 0 {
-		msg := fmt.Sprintf("can't schedule to nodes: %v, because these pods had been scheduled to nodes: %v", GetNodeNames(nodes), nodeMap)
+	if len(minTopologies) == 0 {
+		msg := fmt.Sprintf("can't schedule to nodes: %v, because these pods had been scheduled to nodes: %v", GetNodeNames(nodes), topologyMap)
+		if topologyKey != "" {
+			msg = fmt.Sprintf("can't schedule to nodes: %v, because these pods had been scheduled to %s: %v",
+				GetNodeNames(nodes), topologyKey, topologyMap)
+		}
 		glog.Info(msg)
 		h.recorder.Event(pod, apiv1.EventTypeWarning, "FailedScheduling", msg)
 		return nil, errors.New(msg)
 	}
+
+	minNodeNames := make([]string, 0)
+	topologySet := sets.NewString(minTopologies...)
+	minNodePods := -1
+	for _, node := range nodes {
+		nodeName := node.GetName()
+		if !topologySet.Has(nodeTopologies[nodeName]) {
+			continue
+		}
+		// the nodes are the topology domains themselves without the topology key
+		if topologyKey != "" && minNodePods != -1 && nodePods[nodeName] > minNodePods {
+			continue
+		}
+		if topologyKey != "" && (minNodePods == -1 || nodePods[nodeName] < minNodePods) {
+			minNodePods = nodePods[nodeName]
+			minNodeNames = make([]string, 0)
+		}
+		minNodeNames = append(minNodeNames, nodeName)
+	}
 	return getNodeFromNames(nodes, minNodeNames), nil
 }
 
+// countPodsPerNode returns the number of the scheduled pods of the component on each node
+func countPodsPerNode(pod *apiv1.Pod, component string, podList *apiv1.PodList) map[string]int {
+	nodePods := make(map[string]int)
+	for _, p := range podList.Items {
+		nodeName := p.Spec.NodeName
+		if nodeName == "" {
+			continue
+		}
+		// only the pods of the same StatefulSet are counted, the TiDB groups share the labels of spec.tidb
+		if component == label.TiDBLabelVal && p.GenerateName != pod.GenerateName {
+			continue
+		}
+		nodePods[nodeName]++
+	}
+	return nodePods
+}
+
+// filterNodesByPodsPerNode returns the nodes which can host one more pod of the component without exceeding
+// the pods allowed per node
+func filterNodesByPodsPerNode(component string, replicas int32, nodePods map[string]int, nodes []apiv1.Node) []apiv1.Node {
+	knownNodes := sets.StringKeySet(nodePods)
+	for _, node := range nodes {
+		knownNodes.Insert(node.GetName())
+	}
+	maxPodsPerNode := maxPodsPerDomain(component, replicas, len(nodePods), knownNodes.Len())
+
+	feasibleNodes := make([]apiv1.Node, 0, len(nodes))
+	for _, node := range nodes {
+		if podsCount := nodePods[node.GetName()]; podsCount+1 > maxPodsPerNode {
+			glog.Infof("node %s has %d instances of component %s, max allowed is %d, skipping",
+				node.GetName(), podsCount, component, maxPodsPerNode)
+			continue
+		}
+		feasibleNodes = append(feasibleNodes, node)
+	}
+	return feasibleNodes
+}
+
+// maxPodsPerDomain returns how many pods of the component are allowed on a node, or in a topology domain.
+// hosting is the number of the nodes or domains hosting the pods of the component, and known is the number
+// of the nodes or domains either hosting the pods or feasible for the pod being scheduled
+func maxPodsPerDomain(component string, replicas int32, hosting, known int) int {
+	switch component {
+	case label.PDLabelVal:
+		/**
+		 * replicas     maxPodsPerTopology
+		 * -------------------------------
+		 * 1            1
+		 * 2            1
+		 * 3            1
+		 * 4            1
+		 * 5            2
+		 * ...
+		 */
+		maxPods := int((replicas+1)/2) - 1
+		if maxPods <= 0 {
+			maxPods = 1
+		}
+		return maxPods
+	case label.TiDBLabelVal:
+		return int(math.Ceil(float64(replicas) / float64(known)))
+	}
+	// replicas less than 3 cannot achieve high availability
+	if replicas < 3 {
+		return math.MaxInt32
+	}
+	// 1. TiKV instances must run on at least 3 nodes or topology domains, otherwise HA is not possible
+	if hosting < 3 {
+		return 1
+	}
+	/**
+	 * 2. we requires TiKV instances to run on at least 3 nodes or topology domains, so max
+	 * allowed pods on each of them is ceil(replicas / 3)
+	 *
+	 * replicas     maxPodsPerTopology   best HA on three nodes or topology domains
+	 * ----------------------------------------------------------------------------
+	 * 3            1                    1, 1, 1
+	 * 4            2                    1, 1, 2
+	 * 5            2                    1, 2, 2
+	 * 6            2                    2, 2, 2
+	 * 7            3                    2, 2, 3
+	 * 8            3                    2, 3, 3
+	 * ...
+	 */
+	return int(math.Ceil(float64(replicas) / 3))
+}
+
 // kubernetes scheduling is parallel, to achieve HA, we must ensure the scheduling is serial,
 // so when a pod is scheduling, we set an annotation to its PVC, other pods can't be scheduled at this time,
 // delete the PVC's annotation when the pod is scheduled(PVC is bound and the pod's nodeName is set)
@@ -285,6 +454,32 @@ func (h *ha) realAcquireLock(pod *apiv1.Pod) (*apiv1.PersistentVolumeClaim, *api
 	return schedulingPVC, currentPVC, h.setCurrentPodScheduling(currentPVC)
 }
 
+// the TiDB StatefulSet creates its pods in parallel and TiDB pods have no PVC to set the annotation to,
+// so the TiDB pod being scheduled is recorded for its tidbcluster in memory, the other TiDB pods of the
+// tidbcluster can't be scheduled until it's scheduled (its nodeName is set) or deleted.
+// Only the leader of kube-scheduler calls the extender, so the lock is not shared between the replicas
+func (h *ha) realAcquireTiDBLock(pod *apiv1.Pod, tcName string) error {
+	ns := pod.GetNamespace()
+	podName := pod.GetName()
+	key := fmt.Sprintf("%s/%s", ns, tcName)
+	if h.tidbSchedulingPods == nil {
+		h.tidbSchedulingPods = map[string]string{}
+	}
+
+	schedulingPodName, ok := h.tidbSchedulingPods[key]
+	if ok && schedulingPodName != podName {
+		schedulingPod, err := h.podGetFn(ns, schedulingPodName)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		if err == nil && schedulingPod.Spec.NodeName == "" {
+			return fmt.Errorf("waiting for Pod %s/%s scheduling", ns, schedulingPodName)
+		}
+	}
+	h.tidbSchedulingPods[key] = podName
+	return nil
+}
+
 func (h *ha) realPodListFn(ns, instanceName, component string) (*apiv1.PodList, error) {
 	selector := label.New().Instance(instanceName).Component(component).Labels()
 	return h.kubeCli.CoreV1().Pods(ns).List(metav1.ListOptions{
@@ -335,6 +530,10 @@ func (h *ha) realPVCGetFn(ns, pvcName string) (*apiv1.PersistentVolumeClaim, err
 	return h.kubeCli.CoreV1().PersistentVolumeClaims(ns).Get(pvcName, metav1.GetOptions{})
 }
 
+func (h *ha) realNodeGetFn(nodeName string) (*apiv1.Node, error) {
+	return h.kubeCli.CoreV1().Nodes().Get(nodeName, metav1.GetOptions{})
+}
+
 func (h *ha) realTCGetFn(ns, tcName string) (*v1alpha1.TidbCluster, error) {
 	return h.cli.PingcapV1alpha1().TidbClusters(ns).Get(tcName, metav1.GetOptions{})
 }
@@ -366,6 +565,9 @@ func getReplicasFrom(tc *v1alpha1.TidbCluster, component string) int32 {
 	if component == v1alpha1.PDMemberType.String() {
 		return tc.Spec.PD.Replicas
 	}
+	if component == v1alpha1.TiDBMemberType.String() {
+		return tc.Spec.TiDB.Replicas
+	}
 
 	return tc.Spec.TiKV.Replicas
 }
@@ -26,7 +26,9 @@ import (
 	"github.com/pingcap/tidb-operator/pkg/label"
 	apiv1 "k8s.io/api/core/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/tools/record"
 )
 
@@ -914,6 +916,372 @@ func TestHAFilter(t *testing.T) {
 	}
 }
 
+func TestHAFilterTopology(t *testing.T) {
+	g := NewGomegaWithT(t)
+	zoneKey := "topology.kubernetes.io/zone"
+
+	type testcase struct {
+		name        string
+		podFn       func(string, string, int32) *apiv1.Pod
+		nodeZones   []string
+		nodePodMap  map[string][]int32
+		updateTC    func(*v1alpha1.TidbCluster)
+		expectNodes []string
+		expectErr   string
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+		instanceName := "demo"
+		clusterName := "cluster-1"
+
+		nodes := make([]apiv1.Node, 0)
+		for i, zone := range test.nodeZones {
+			node := apiv1.Node{
+				TypeMeta:   metav1.TypeMeta{Kind: "Node", APIVersion: "v1"},
+				ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("kube-node-%d", i+1)},
+			}
+			if zone != "" {
+				node.Labels = map[string]string{zoneKey: zone}
+			}
+			nodes = append(nodes, node)
+		}
+		recorder := record.NewFakeRecorder(10)
+
+		ha := ha{
+			podListFn: podListFn(test.nodePodMap),
+			nodeGetFn: func(nodeName string) (*apiv1.Node, error) {
+				// the nodes out of the candidates are in zone-1
+				return &apiv1.Node{
+					ObjectMeta: metav1.ObjectMeta{Name: nodeName, Labels: map[string]string{zoneKey: "zone-1"}},
+				}, nil
+			},
+			tcGetFn: func(ns string, tcName string) (*v1alpha1.TidbCluster, error) {
+				tc, _ := tcGetFn(ns, tcName)
+				tc.Spec.HATopologyKey = zoneKey
+				tc.Spec.TiKV.Replicas = 3
+				if test.updateTC != nil {
+					test.updateTC(tc)
+				}
+				return tc, nil
+			},
+			acquireLockFn: acquireSuccess,
+			acquireTiDBLockFn: func(*apiv1.Pod, string) error {
+				return nil
+			},
+			recorder: recorder,
+		}
+		n, err := ha.Filter(instanceName, test.podFn(instanceName, clusterName, 0), nodes)
+		if test.expectErr != "" {
+			g.Expect(err).To(HaveOccurred())
+			g.Expect(err.Error()).To(ContainSubstring(test.expectErr))
+			return
+		}
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(getSortedNodeNames(n)).To(Equal(test.expectNodes))
+	}
+
+	threeZones := []string{"zone-1", "zone-1", "zone-2", "zone-2", "zone-3", "zone-3"}
+	tests := []testcase{
+		{
+			name:        "zero pod scheduled, return all the nodes",
+			podFn:       newHAPDPod,
+			nodeZones:   threeZones,
+			nodePodMap:  map[string][]int32{},
+			expectNodes: []string{"kube-node-1", "kube-node-2", "kube-node-3", "kube-node-4", "kube-node-5", "kube-node-6"},
+		},
+		{
+			name:        "one pod scheduled in zone-1, return the nodes of the other zones",
+			podFn:       newHAPDPod,
+			nodeZones:   threeZones,
+			nodePodMap:  map[string][]int32{"kube-node-1": {1}},
+			expectNodes: []string{"kube-node-3", "kube-node-4", "kube-node-5", "kube-node-6"},
+		},
+		{
+			name:        "pods scheduled to a node out of the candidates, return the nodes of the empty zone",
+			podFn:       newHAPDPod,
+			nodeZones:   threeZones,
+			nodePodMap:  map[string][]int32{"kube-node-7": {1}, "kube-node-3": {2}},
+			expectNodes: []string{"kube-node-5", "kube-node-6"},
+		},
+		{
+			name:       "every zone has a pd pod, return zero node",
+			podFn:      newHAPDPod,
+			nodeZones:  threeZones,
+			nodePodMap: map[string][]int32{"kube-node-1": {1}, "kube-node-3": {2}, "kube-node-5": {3}},
+			expectErr:  "because these pods had been scheduled to topology.kubernetes.io/zone",
+		},
+		{
+			name:       "two zones can't hold 3 pd pods",
+			podFn:      newHAPDPod,
+			nodeZones:  []string{"zone-1", "zone-1", "zone-2", "zone-2"},
+			nodePodMap: map[string][]int32{"kube-node-1": {1}, "kube-node-3": {2}},
+			expectErr:  "because these pods had been scheduled to topology.kubernetes.io/zone",
+		},
+		{
+			name:        "nodes without the zone label are skipped",
+			podFn:       newHAPDPod,
+			nodeZones:   []string{"zone-1", "", "zone-2"},
+			nodePodMap:  map[string][]int32{},
+			expectNodes: []string{"kube-node-1", "kube-node-3"},
+		},
+		{
+			name:       "no node has the zone label, return zero node",
+			podFn:      newHAPDPod,
+			nodeZones:  []string{"", ""},
+			nodePodMap: map[string][]int32{},
+			expectErr:  "because they have no label topology.kubernetes.io/zone",
+		},
+		{
+			name:       "the tikv isolation level doesn't set the topology key, return the nodes without tikv pods",
+			podFn:      newHATiKVPod,
+			nodeZones:  threeZones,
+			nodePodMap: map[string][]int32{"kube-node-1": {1}},
+			updateTC: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.HATopologyKey = ""
+				tc.Spec.TiKV.LocationLabels = []v1alpha1.StoreLabel{{Name: "zone", NodeLabel: zoneKey}, {Name: "host"}}
+				tc.Spec.TiKV.IsolationLevel = "zone"
+			},
+			expectNodes: []string{"kube-node-2", "kube-node-3", "kube-node-4", "kube-node-5", "kube-node-6"},
+		},
+		{
+			name:       "the pods are spread across the nodes of the zone, return the empty node of the least loaded zone",
+			podFn:      newHATiKVPod,
+			nodeZones:  threeZones,
+			nodePodMap: map[string][]int32{"kube-node-1": {1}, "kube-node-3": {2}, "kube-node-4": {3}, "kube-node-5": {4}, "kube-node-6": {5}},
+			updateTC: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.TiKV.Replicas = 6
+			},
+			expectNodes: []string{"kube-node-2"},
+		},
+		{
+			name: "the nodes hosting the max allowed tidb pods are skipped in the zones",
+			podFn: func(instanceName, clusterName string, ordinal int32) *apiv1.Pod {
+				return &apiv1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      fmt.Sprintf("%s-%d", controller.TiDBMemberName(clusterName), ordinal),
+						Namespace: corev1.NamespaceDefault,
+						Labels:    label.New().Instance(instanceName).TiDB().Labels(),
+					},
+				}
+			},
+			nodeZones:  threeZones,
+			nodePodMap: map[string][]int32{"kube-node-1": {1}, "kube-node-3": {2}, "kube-node-5": {3}},
+			updateTC: func(tc *v1alpha1.TidbCluster) {
+				tc.Spec.TiDB.HA = true
+				tc.Spec.TiDB.Replicas = 4
+			},
+			expectNodes: []string{"kube-node-2", "kube-node-4", "kube-node-6"},
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
+
+func TestHAFilterTiDB(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name        string
+		ha          bool
+		replicas    int32
+		tcNotFound  bool
+		candidates  []string
+		nodePodMap  map[string][]string
+		expectNodes []string
+		expectErr   string
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+		instanceName := "demo"
+		clusterName := "cluster-1"
+
+		nodes := make([]apiv1.Node, 0)
+		for _, nodeName := range test.candidates {
+			nodes = append(nodes, apiv1.Node{
+				TypeMeta:   metav1.TypeMeta{Kind: "Node", APIVersion: "v1"},
+				ObjectMeta: metav1.ObjectMeta{Name: nodeName},
+			})
+		}
+		pod := &apiv1.Pod{
+			TypeMeta: metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:         fmt.Sprintf("%s-%d", controller.TiDBMemberName(clusterName), 9),
+				GenerateName: controller.TiDBMemberName(clusterName) + "-",
+				Namespace:    corev1.NamespaceDefault,
+				Labels:       label.New().Instance(instanceName).TiDB().Labels(),
+			},
+		}
+
+		ha := ha{
+			podListFn: func(ns, instanceName, component string) (*apiv1.PodList, error) {
+				podList := &apiv1.PodList{}
+				for nodeName, podNames := range test.nodePodMap {
+					for _, podName := range podNames {
+						podList.Items = append(podList.Items, apiv1.Pod{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:         podName,
+								GenerateName: podName[:strings.LastIndex(podName, "-")+1],
+								Namespace:    corev1.NamespaceDefault,
+							},
+							Spec: apiv1.PodSpec{NodeName: nodeName},
+						})
+					}
+				}
+				return podList, nil
+			},
+			tcGetFn: func(ns string, tcName string) (*v1alpha1.TidbCluster, error) {
+				if test.tcNotFound {
+					return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "tidbclusters"}, tcName)
+				}
+				tc, _ := tcGetFn(ns, tcName)
+				tc.Spec.TiDB.HA = test.ha
+				tc.Spec.TiDB.Replicas = test.replicas
+				return tc, nil
+			},
+			acquireLockFn: func(*apiv1.Pod) (*apiv1.PersistentVolumeClaim, *apiv1.PersistentVolumeClaim, error) {
+				return nil, nil, errors.New("tidb pods should not acquire the lock")
+			},
+			acquireTiDBLockFn: func(*apiv1.Pod, string) error {
+				return nil
+			},
+			recorder: record.NewFakeRecorder(10),
+		}
+		n, err := ha.Filter(instanceName, pod, nodes)
+		if test.expectErr != "" {
+			g.Expect(err).To(HaveOccurred())
+			g.Expect(err.Error()).To(ContainSubstring(test.expectErr))
+			return
+		}
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(getSortedNodeNames(n)).To(Equal(test.expectNodes))
+	}
+
+	tests := []testcase{
+		{
+			name:        "ha is not enabled, return all the nodes",
+			replicas:    3,
+			candidates:  []string{"kube-node-1", "kube-node-2"},
+			nodePodMap:  map[string][]string{"kube-node-1": {"cluster-1-tidb-0", "cluster-1-tidb-1"}},
+			expectNodes: []string{"kube-node-1", "kube-node-2"},
+		},
+		{
+			name:        "tidbcluster is not found, return all the nodes",
+			ha:          true,
+			tcNotFound:  true,
+			candidates:  []string{"kube-node-1", "kube-node-2"},
+			expectNodes: []string{"kube-node-1", "kube-node-2"},
+		},
+		{
+			name:        "return the node with the least tidb pods",
+			ha:          true,
+			replicas:    4,
+			candidates:  []string{"kube-node-1", "kube-node-2"},
+			nodePodMap:  map[string][]string{"kube-node-1": {"cluster-1-tidb-0", "cluster-1-tidb-1"}, "kube-node-2": {"cluster-1-tidb-2"}},
+			expectNodes: []string{"kube-node-2"},
+		},
+		{
+			name:        "the pods of the tidb groups are not counted",
+			ha:          true,
+			replicas:    2,
+			candidates:  []string{"kube-node-1", "kube-node-2"},
+			nodePodMap:  map[string][]string{"kube-node-1": {"cluster-1-tidb-olap-0", "cluster-1-tidb-olap-1"}, "kube-node-2": {"cluster-1-tidb-0"}},
+			expectNodes: []string{"kube-node-1"},
+		},
+		{
+			name:       "the nodes out of the candidates count, return zero node",
+			ha:         true,
+			replicas:   2,
+			candidates: []string{"kube-node-1"},
+			nodePodMap: map[string][]string{"kube-node-1": {"cluster-1-tidb-0"}, "kube-node-3": {"cluster-1-tidb-1"}},
+			expectErr:  "because these pods had been scheduled to nodes",
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
+
+func TestHAFilterTiDBSchedulingLock(t *testing.T) {
+	g := NewGomegaWithT(t)
+	instanceName := "demo"
+	clusterName := "cluster-1"
+
+	nodes := []apiv1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "kube-node-1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "kube-node-2"}},
+	}
+	newPod := func(ordinal int32) *apiv1.Pod {
+		return &apiv1.Pod{
+			TypeMeta: metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:         fmt.Sprintf("%s-%d", controller.TiDBMemberName(clusterName), ordinal),
+				GenerateName: controller.TiDBMemberName(clusterName) + "-",
+				Namespace:    corev1.NamespaceDefault,
+				Labels:       label.New().Instance(instanceName).TiDB().Labels(),
+			},
+		}
+	}
+	// the StatefulSet creates both pods at the same time
+	pods := map[string]*apiv1.Pod{}
+	for _, pod := range []*apiv1.Pod{newPod(0), newPod(1)} {
+		pods[pod.GetName()] = pod
+	}
+
+	ha := ha{
+		podListFn: func(ns, instanceName, component string) (*apiv1.PodList, error) {
+			podList := &apiv1.PodList{}
+			for _, pod := range pods {
+				podList.Items = append(podList.Items, *pod)
+			}
+			return podList, nil
+		},
+		podGetFn: func(ns, podName string) (*apiv1.Pod, error) {
+			pod, ok := pods[podName]
+			if !ok {
+				return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "pods"}, podName)
+			}
+			return pod, nil
+		},
+		tcGetFn: func(ns string, tcName string) (*v1alpha1.TidbCluster, error) {
+			tc, _ := tcGetFn(ns, tcName)
+			tc.Spec.TiDB.HA = true
+			tc.Spec.TiDB.Replicas = 2
+			return tc, nil
+		},
+		recorder: record.NewFakeRecorder(10),
+	}
+	ha.acquireTiDBLockFn = ha.realAcquireTiDBLock
+
+	n, err := ha.Filter(instanceName, pods["cluster-1-tidb-0"], nodes)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(getSortedNodeNames(n)).To(Equal([]string{"kube-node-1", "kube-node-2"}))
+
+	// the other pod waits until the first pod is bound to a node
+	_, err = ha.Filter(instanceName, pods["cluster-1-tidb-1"], nodes)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("waiting for Pod default/cluster-1-tidb-0 scheduling"))
+
+	// the first pod can be filtered again while it holds the lock
+	_, err = ha.Filter(instanceName, pods["cluster-1-tidb-0"], nodes)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	pods["cluster-1-tidb-0"].Spec.NodeName = "kube-node-1"
+	n, err = ha.Filter(instanceName, pods["cluster-1-tidb-1"], nodes)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(getSortedNodeNames(n)).To(Equal([]string{"kube-node-2"}))
+
+	// the lock is released if the pod being scheduled is deleted
+	delete(pods, "cluster-1-tidb-1")
+	pods["cluster-1-tidb-2"] = newPod(2)
+	_, err = ha.Filter(instanceName, pods["cluster-1-tidb-2"], nodes)
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
 func newHAPDPod(instanceName, clusterName string, ordinal int32) *apiv1.Pod {
 	return &apiv1.Pod{
 		TypeMeta: metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
@@ -18,6 +18,7 @@ import (
 
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned"
+	"github.com/pingcap/tidb-operator/pkg/features"
 	"github.com/pingcap/tidb-operator/pkg/label"
 	apiv1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -36,7 +37,7 @@ const (
 
 var (
 	// supportedComponents holds the supported components
-	supportedComponents = sets.NewString(label.TiDBLabelVal)
+	supportedComponents = sets.NewString(label.TiDBLabelVal, label.PumpLabelVal, label.TiFlashLabelVal)
 )
 
 type stableScheduling struct {
@@ -59,16 +60,37 @@ func (p *stableScheduling) Name() string {
 	return "StableScheduling"
 }
 
-func (p *stableScheduling) findPreviousNodeInTC(tc *v1alpha1.TidbCluster, pod *apiv1.Pod) string {
-	members := tc.Status.TiDB.Members
-	if members == nil {
-		return ""
+// stableSchedulingEnabled returns whether the pods of the component are scheduled to their previous nodes,
+// it's enabled for TiDB by default unless the StableScheduling feature is disabled
+func stableSchedulingEnabled(tc *v1alpha1.TidbCluster, component string) bool {
+	switch component {
+	case label.TiDBLabelVal:
+		if tc.Spec.TiDB.StableScheduling != nil {
+			return *tc.Spec.TiDB.StableScheduling
+		}
+		return features.DefaultFeatureGate.Enabled(features.StableScheduling)
+	case label.PumpLabelVal:
+		return tc.Spec.Pump != nil && tc.Spec.Pump.StableScheduling
+	case label.TiFlashLabelVal:
+		return tc.Spec.TiFlash != nil && tc.Spec.TiFlash.StableScheduling
 	}
-	tidbMember, ok := tc.Status.TiDB.Members[pod.Name]
-	if !ok {
-		return ""
+	return false
+}
+
+func (p *stableScheduling) findPreviousNodeInTC(tc *v1alpha1.TidbCluster, pod *apiv1.Pod) string {
+	switch pod.Labels[label.ComponentLabelKey] {
+	case label.TiDBLabelVal:
+		return tc.Status.TiDB.Members[pod.Name].NodeName
+	case label.PumpLabelVal:
+		return tc.Status.Pump.Members[pod.Name].NodeName
+	case label.TiFlashLabelVal:
+		for _, store := range tc.Status.TiFlash.Stores {
+			if store.PodName == pod.Name {
+				return store.NodeName
+			}
+		}
 	}
-	return tidbMember.NodeName
+	return ""
 }
 
 func (p *stableScheduling) Filter(instanceName string, pod *apiv1.Pod, nodes []apiv1.Node) ([]apiv1.Node, error) {
@@ -92,6 +114,10 @@ func (p *stableScheduling) Filter(instanceName string, pod *apiv1.Pod, nodes []a
 		return nil, err
 	}
 
+	if !stableSchedulingEnabled(tc, component) {
+		return nodes, nil
+	}
+
 	nodeName := p.findPreviousNodeInTC(tc, pod)
 
 	if nodeName != "" {
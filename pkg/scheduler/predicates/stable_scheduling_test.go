@@ -158,6 +158,93 @@ func TestStableSchedulingFilter(t *testing.T) {
 				g.Expect(getSortedNodeNames(nodes)).To(Equal([]string{"node-2"}))
 			},
 		},
+		{
+			name:         "cannot schedule to previous node because it's disabled for tidb",
+			instanceName: "demo",
+			pod:          makePod("demo-tidb-0", label.TiDBLabelVal),
+			tidbCluster: func() *v1alpha1.TidbCluster {
+				tc := makeTidbCluster("demo-tidb-0", "node-2")
+				disabled := false
+				tc.Spec.TiDB.StableScheduling = &disabled
+				return tc
+			}(),
+			candicateNodes: []v1.Node{
+				makeNode("node-1"),
+				makeNode("node-2"),
+				makeNode("node-3"),
+			},
+			expectFn: func(nodes []v1.Node, err error, recorder *record.FakeRecorder) {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(len(nodes)).To(Equal(3))
+			},
+		},
+		{
+			name:         "cannot schedule to previous node because it's not enabled for pump",
+			instanceName: "demo",
+			pod:          makePod("demo-pump-0", label.PumpLabelVal),
+			tidbCluster: func() *v1alpha1.TidbCluster {
+				tc := makeTidbCluster("", "")
+				tc.Spec.Pump = &v1alpha1.PumpSpec{}
+				tc.Status.Pump.Members = map[string]v1alpha1.PumpMember{
+					"demo-pump-0": {Name: "demo-pump-0", NodeName: "node-2"},
+				}
+				return tc
+			}(),
+			candicateNodes: []v1.Node{
+				makeNode("node-1"),
+				makeNode("node-2"),
+				makeNode("node-3"),
+			},
+			expectFn: func(nodes []v1.Node, err error, recorder *record.FakeRecorder) {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(len(nodes)).To(Equal(3))
+			},
+		},
+		{
+			name:         "schedule the pump pod to previous node",
+			instanceName: "demo",
+			pod:          makePod("demo-pump-0", label.PumpLabelVal),
+			tidbCluster: func() *v1alpha1.TidbCluster {
+				tc := makeTidbCluster("", "")
+				tc.Spec.Pump = &v1alpha1.PumpSpec{StableScheduling: true}
+				tc.Status.Pump.Members = map[string]v1alpha1.PumpMember{
+					"demo-pump-0": {Name: "demo-pump-0", NodeName: "node-2"},
+				}
+				return tc
+			}(),
+			candicateNodes: []v1.Node{
+				makeNode("node-1"),
+				makeNode("node-2"),
+				makeNode("node-3"),
+			},
+			expectFn: func(nodes []v1.Node, err error, recorder *record.FakeRecorder) {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(getSortedNodeNames(nodes)).To(Equal([]string{"node-2"}))
+			},
+		},
+		{
+			name:         "schedule the tiflash pod to the previous node of its store",
+			instanceName: "demo",
+			pod:          makePod("demo-tiflash-1", label.TiFlashLabelVal),
+			tidbCluster: func() *v1alpha1.TidbCluster {
+				tc := makeTidbCluster("", "")
+				tc.Spec.TiFlash = &v1alpha1.TiFlashSpec{StableScheduling: true}
+				tc.Status.TiFlash.Stores = map[string]v1alpha1.TiKVStore{
+					"4": {ID: "4", PodName: "demo-tiflash-0", NodeName: "node-1"},
+					"5": {ID: "5", PodName: "demo-tiflash-1", NodeName: "node-3"},
+				}
+				return tc
+			}(),
+			candicateNodes: []v1.Node{
+				makeNode("node-1"),
+				makeNode("node-2"),
+				makeNode("node-3"),
+			},
+			expectFn: func(nodes []v1.Node, err error, recorder *record.FakeRecorder) {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(getSortedNodeNames(nodes)).To(Equal([]string{"node-3"}))
+			},
+		},
 	}
 
 	for _, tc := range tests {
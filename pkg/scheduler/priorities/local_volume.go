@@ -0,0 +1,103 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package priorities
+
+import (
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	v1helper "k8s.io/kubernetes/pkg/apis/core/v1/helper"
+	schedulerapiv1 "k8s.io/kubernetes/pkg/scheduler/api/v1"
+)
+
+type localVolume struct {
+	pvcGetFn func(ns, pvcName string) (*apiv1.PersistentVolumeClaim, error)
+	pvListFn func() ([]*apiv1.PersistentVolume, error)
+}
+
+// NewLocalVolume returns a Priority which prefers the nodes with available local PVs for the unbound PVCs of the pod,
+// the PVs of a StorageClass are local if they have node affinity, e.g. the PVs created by local-volume-provisioner.
+// The pvcLister must list the PVCs of all namespaces.
+func NewLocalVolume(pvcLister corelisters.PersistentVolumeClaimLister, pvLister corelisters.PersistentVolumeLister) Priority {
+	return &localVolume{
+		pvcGetFn: func(ns, pvcName string) (*apiv1.PersistentVolumeClaim, error) {
+			return pvcLister.PersistentVolumeClaims(ns).Get(pvcName)
+		},
+		pvListFn: func() ([]*apiv1.PersistentVolume, error) {
+			return pvLister.List(labels.Everything())
+		},
+	}
+}
+
+func (lv *localVolume) Name() string {
+	return "LocalVolume"
+}
+
+func (lv *localVolume) Score(_ string, pod *apiv1.Pod, nodes []apiv1.Node) (schedulerapiv1.HostPriorityList, error) {
+	var claims []*apiv1.PersistentVolumeClaim
+	for _, vol := range pod.Spec.Volumes {
+		if vol.PersistentVolumeClaim == nil {
+			continue
+		}
+		pvc, err := lv.pvcGetFn(pod.GetNamespace(), vol.PersistentVolumeClaim.ClaimName)
+		if err != nil {
+			return nil, err
+		}
+		// the node of a bound PVC is decided by the volume binding predicate
+		if pvc.Spec.VolumeName == "" && pvc.Spec.StorageClassName != nil {
+			claims = append(claims, pvc)
+		}
+	}
+	if len(claims) == 0 {
+		return scoreEach(nodes, func(apiv1.Node) int { return 0 }), nil
+	}
+
+	pvList, err := lv.pvListFn()
+	if err != nil {
+		return nil, err
+	}
+	var pvs []*apiv1.PersistentVolume
+	for _, pv := range pvList {
+		if pv.Status.Phase == apiv1.VolumeAvailable && pv.Spec.NodeAffinity != nil && pv.Spec.NodeAffinity.Required != nil {
+			pvs = append(pvs, pv)
+		}
+	}
+
+	return scoreEach(nodes, func(node apiv1.Node) int {
+		fit := 0
+		for _, pvc := range claims {
+			for _, pv := range pvs {
+				if pvFitsClaim(pv, pvc, &node) {
+					fit++
+					break
+				}
+			}
+		}
+		return MaxScore * fit / len(claims)
+	}), nil
+}
+
+// pvFitsClaim returns whether the local PV on the node can be bound to the PVC
+func pvFitsClaim(pv *apiv1.PersistentVolume, pvc *apiv1.PersistentVolumeClaim, node *apiv1.Node) bool {
+	if pv.Spec.StorageClassName != *pvc.Spec.StorageClassName {
+		return false
+	}
+	request := pvc.Spec.Resources.Requests[apiv1.ResourceStorage]
+	capacity := pv.Spec.Capacity[apiv1.ResourceStorage]
+	if capacity.Cmp(request) < 0 {
+		return false
+	}
+	return v1helper.MatchNodeSelectorTerms(pv.Spec.NodeAffinity.Required.NodeSelectorTerms,
+		labels.Set(node.GetLabels()), nil)
+}
@@ -0,0 +1,137 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package priorities
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestLocalVolumeScore(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name         string
+		bound        bool
+		pvs          []apiv1.PersistentVolume
+		expectScores map[string]int
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+
+		storageClassName := "local-storage"
+		lv := &localVolume{
+			pvcGetFn: func(ns, pvcName string) (*apiv1.PersistentVolumeClaim, error) {
+				pvc := &apiv1.PersistentVolumeClaim{
+					ObjectMeta: metav1.ObjectMeta{Name: pvcName, Namespace: ns},
+					Spec: apiv1.PersistentVolumeClaimSpec{
+						StorageClassName: &storageClassName,
+						Resources: apiv1.ResourceRequirements{
+							Requests: apiv1.ResourceList{apiv1.ResourceStorage: resource.MustParse("100Gi")},
+						},
+					},
+				}
+				if test.bound {
+					pvc.Spec.VolumeName = "pv-1"
+				}
+				return pvc, nil
+			},
+			pvListFn: func() ([]*apiv1.PersistentVolume, error) {
+				pvs := []*apiv1.PersistentVolume{}
+				for i := range test.pvs {
+					pvs = append(pvs, &test.pvs[i])
+				}
+				return pvs, nil
+			},
+		}
+		pod := newTiKVPod()
+		pod.Spec.Volumes = []apiv1.Volume{
+			{
+				Name: "tikv",
+				VolumeSource: apiv1.VolumeSource{
+					PersistentVolumeClaim: &apiv1.PersistentVolumeClaimVolumeSource{ClaimName: "tikv-demo-tikv-0"},
+				},
+			},
+		}
+		nodes := []apiv1.Node{}
+		for _, name := range []string{"node-1", "node-2", "node-3"} {
+			nodes = append(nodes, newNodes(map[string]string{apiv1.LabelHostname: name}, name)...)
+		}
+		scores, err := lv.Score("demo", pod, nodes)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(toScoreMap(scores)).To(Equal(test.expectScores))
+	}
+
+	tests := []testcase{
+		{
+			name: "nodes with available local PVs are preferred",
+			pvs: []apiv1.PersistentVolume{
+				newLocalPV("node-1", "local-storage", "200Gi", apiv1.VolumeAvailable),
+				newLocalPV("node-2", "local-storage", "200Gi", apiv1.VolumeBound),
+				newLocalPV("node-3", "local-storage", "50Gi", apiv1.VolumeAvailable),
+			},
+			expectScores: map[string]int{"node-1": 10, "node-2": 0, "node-3": 0},
+		},
+		{
+			name: "PVs of the other StorageClass are ignored",
+			pvs: []apiv1.PersistentVolume{
+				newLocalPV("node-1", "ssd", "200Gi", apiv1.VolumeAvailable),
+			},
+			expectScores: map[string]int{"node-1": 0, "node-2": 0, "node-3": 0},
+		},
+		{
+			name:  "the PVC is bound",
+			bound: true,
+			pvs: []apiv1.PersistentVolume{
+				newLocalPV("node-1", "local-storage", "200Gi", apiv1.VolumeAvailable),
+			},
+			expectScores: map[string]int{"node-1": 0, "node-2": 0, "node-3": 0},
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
+
+func newLocalPV(nodeName, storageClassName, capacity string, phase apiv1.PersistentVolumePhase) apiv1.PersistentVolume {
+	return apiv1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "local-pv-" + nodeName},
+		Spec: apiv1.PersistentVolumeSpec{
+			StorageClassName: storageClassName,
+			Capacity:         apiv1.ResourceList{apiv1.ResourceStorage: resource.MustParse(capacity)},
+			NodeAffinity: &apiv1.VolumeNodeAffinity{
+				Required: &apiv1.NodeSelector{
+					NodeSelectorTerms: []apiv1.NodeSelectorTerm{
+						{
+							MatchExpressions: []apiv1.NodeSelectorRequirement{
+								{
+									Key:      apiv1.LabelHostname,
+									Operator: apiv1.NodeSelectorOpIn,
+									Values:   []string{nodeName},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Status: apiv1.PersistentVolumeStatus{Phase: phase},
+	}
+}
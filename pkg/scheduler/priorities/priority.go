@@ -0,0 +1,71 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package priorities
+
+import (
+	"github.com/pingcap/tidb-operator/pkg/label"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	schedulerapiv1 "k8s.io/kubernetes/pkg/scheduler/api/v1"
+)
+
+// MaxScore is the score of the most preferred node, it's the same as the MaxPriority of kube-scheduler
+const MaxScore = 10
+
+// Priority is an interface as extender-implemented priority functions
+type Priority interface {
+	// Name return the priority name
+	Name() string
+
+	// Score function receives a set of nodes and returns the scores of them in [0, MaxScore],
+	// the nodes with higher scores are preferred.
+	Score(string, *apiv1.Pod, []apiv1.Node) (schedulerapiv1.HostPriorityList, error)
+}
+
+// scoreByCount returns the scores of the nodes which prefer the nodes whose keys have fewer pods, the counts
+// of the pods on the nodes out of the candidates are compared too
+func scoreByCount(nodes []apiv1.Node, counts map[string]int, keyOf func(apiv1.Node) string) schedulerapiv1.HostPriorityList {
+	max := 0
+	for _, count := range counts {
+		if count > max {
+			max = count
+		}
+	}
+	return scoreEach(nodes, func(node apiv1.Node) int {
+		if max == 0 {
+			return MaxScore
+		}
+		return MaxScore * (max - counts[keyOf(node)]) / max
+	})
+}
+
+func scoreEach(nodes []apiv1.Node, scoreOf func(apiv1.Node) int) schedulerapiv1.HostPriorityList {
+	result := schedulerapiv1.HostPriorityList{}
+	for _, node := range nodes {
+		result = append(result, schedulerapiv1.HostPriority{
+			Host:  node.GetName(),
+			Score: scoreOf(node),
+		})
+	}
+	return result
+}
+
+func listComponentPods(kubeCli kubernetes.Interface, ns, instanceName, component string) (*apiv1.PodList, error) {
+	selector := label.New().Instance(instanceName).Component(component).Labels()
+	return kubeCli.CoreV1().Pods(ns).List(metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(selector).String(),
+	})
+}
@@ -0,0 +1,98 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package priorities
+
+import (
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	schedulerapiv1 "k8s.io/kubernetes/pkg/scheduler/api/v1"
+)
+
+// headroomResources are the resources whose headroom on the nodes are scored
+var headroomResources = []apiv1.ResourceName{apiv1.ResourceCPU, apiv1.ResourceMemory}
+
+type resourceHeadroom struct {
+	podListFn func() ([]*apiv1.Pod, error)
+}
+
+// NewResourceHeadroom returns a Priority which prefers the nodes with more allocatable CPU and memory left
+// after the pod is placed, so that the members have room to grow, e.g. when their resources are increased.
+// The podLister must list the pods of all namespaces.
+func NewResourceHeadroom(podLister corelisters.PodLister) Priority {
+	return &resourceHeadroom{
+		podListFn: func() ([]*apiv1.Pod, error) {
+			return podLister.List(labels.Everything())
+		},
+	}
+}
+
+func (rh *resourceHeadroom) Name() string {
+	return "ResourceHeadroom"
+}
+
+func (rh *resourceHeadroom) Score(_ string, pod *apiv1.Pod, nodes []apiv1.Node) (schedulerapiv1.HostPriorityList, error) {
+	pods, err := rh.podListFn()
+	if err != nil {
+		return nil, err
+	}
+	requested := map[string]apiv1.ResourceList{}
+	for _, p := range pods {
+		if p.Spec.NodeName == "" || p.GetUID() == pod.GetUID() {
+			continue
+		}
+		// the resources of the terminated pods are released
+		if p.Status.Phase == apiv1.PodSucceeded || p.Status.Phase == apiv1.PodFailed {
+			continue
+		}
+		if requested[p.Spec.NodeName] == nil {
+			requested[p.Spec.NodeName] = apiv1.ResourceList{}
+		}
+		addRequests(requested[p.Spec.NodeName], p)
+	}
+	podRequests := apiv1.ResourceList{}
+	addRequests(podRequests, pod)
+
+	return scoreEach(nodes, func(node apiv1.Node) int {
+		var headroom float64
+		for _, name := range headroomResources {
+			allocatable := node.Status.Allocatable[name]
+			if allocatable.IsZero() {
+				continue
+			}
+			used := requested[node.GetName()][name]
+			used.Add(podRequests[name])
+			free := float64(allocatable.MilliValue()-used.MilliValue()) / float64(allocatable.MilliValue())
+			if free > 0 {
+				headroom += free
+			}
+		}
+		return int(float64(MaxScore) * headroom / float64(len(headroomResources)))
+	}), nil
+}
+
+// addRequests adds the resource requests of the containers of the pod to requests
+func addRequests(requests apiv1.ResourceList, pod *apiv1.Pod) {
+	for _, container := range pod.Spec.Containers {
+		for _, name := range headroomResources {
+			quantity, ok := container.Resources.Requests[name]
+			if !ok {
+				continue
+			}
+			sum := requests[name]
+			sum.Add(quantity)
+			requests[name] = sum
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package priorities
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestResourceHeadroomScore(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	newPod := func(name, nodeName, cpu, memory string) *apiv1.Pod {
+		return &apiv1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID("uid-" + name)},
+			Spec: apiv1.PodSpec{
+				NodeName: nodeName,
+				Containers: []apiv1.Container{
+					{
+						Resources: apiv1.ResourceRequirements{
+							Requests: apiv1.ResourceList{
+								apiv1.ResourceCPU:    resource.MustParse(cpu),
+								apiv1.ResourceMemory: resource.MustParse(memory),
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	newNode := func(name, cpu, memory string) apiv1.Node {
+		node := newNodes(nil, name)[0]
+		node.Status.Allocatable = apiv1.ResourceList{
+			apiv1.ResourceCPU:    resource.MustParse(cpu),
+			apiv1.ResourceMemory: resource.MustParse(memory),
+		}
+		return node
+	}
+
+	rh := &resourceHeadroom{
+		podListFn: func() ([]*apiv1.Pod, error) {
+			terminated := newPod("pod-4", "node-2", "4", "8Gi")
+			terminated.Status.Phase = apiv1.PodSucceeded
+			return []*apiv1.Pod{
+				newPod("pod-1", "node-1", "4", "8Gi"),
+				newPod("pod-2", "node-2", "1", "2Gi"),
+				newPod("pod-3", "", "8", "16Gi"),
+				terminated,
+			}, nil
+		},
+	}
+	pod := newPod("demo-tikv-0", "", "2", "4Gi")
+	pod.Labels = newTiKVPod().Labels
+	nodes := []apiv1.Node{
+		newNode("node-1", "8", "16Gi"),
+		newNode("node-2", "8", "16Gi"),
+		newNode("node-3", "4", "8Gi"),
+		newNodes(nil, "node-4")[0],
+	}
+
+	scores, err := rh.Score("demo", pod, nodes)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(toScoreMap(scores)).To(Equal(map[string]int{
+		// 2/8 of cpu and memory are left
+		"node-1": 2,
+		// 5/8 of cpu and memory are left
+		"node-2": 6,
+		"node-3": 5,
+		"node-4": 0,
+	}))
+}
@@ -0,0 +1,137 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package priorities
+
+import (
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	glog "k8s.io/klog"
+	schedulerapiv1 "k8s.io/kubernetes/pkg/scheduler/api/v1"
+)
+
+type memberSpreading struct {
+	podListFn func(ns, instanceName, component string) (*apiv1.PodList, error)
+}
+
+// NewMemberSpreading returns a Priority which prefers the nodes with fewer pods of the same cluster and component,
+// the pods are spread softly even if the HA predicate allows several pods on a node
+func NewMemberSpreading(kubeCli kubernetes.Interface) Priority {
+	return &memberSpreading{
+		podListFn: func(ns, instanceName, component string) (*apiv1.PodList, error) {
+			return listComponentPods(kubeCli, ns, instanceName, component)
+		},
+	}
+}
+
+func (ms *memberSpreading) Name() string {
+	return "MemberSpreading"
+}
+
+func (ms *memberSpreading) Score(instanceName string, pod *apiv1.Pod, nodes []apiv1.Node) (schedulerapiv1.HostPriorityList, error) {
+	podList, err := ms.podListFn(pod.GetNamespace(), instanceName, pod.Labels[label.ComponentLabelKey])
+	if err != nil {
+		return nil, err
+	}
+	counts := map[string]int{}
+	for _, p := range podList.Items {
+		if p.Spec.NodeName != "" && p.GetName() != pod.GetName() {
+			counts[p.Spec.NodeName]++
+		}
+	}
+	return scoreByCount(nodes, counts, func(node apiv1.Node) string {
+		return node.GetName()
+	}), nil
+}
+
+type zoneSpreading struct {
+	podListFn func(ns, instanceName, component string) (*apiv1.PodList, error)
+	nodeGetFn func(nodeName string) (*apiv1.Node, error)
+	tcGetFn   func(ns, tcName string) (*v1alpha1.TidbCluster, error)
+}
+
+// NewZoneSpreading returns a Priority which prefers the nodes in the zones with fewer pods of the same cluster
+// and component. The zones are the values of the HA topology key of the tidbcluster, or the zone label of the nodes
+func NewZoneSpreading(kubeCli kubernetes.Interface, cli versioned.Interface) Priority {
+	return &zoneSpreading{
+		podListFn: func(ns, instanceName, component string) (*apiv1.PodList, error) {
+			return listComponentPods(kubeCli, ns, instanceName, component)
+		},
+		nodeGetFn: func(nodeName string) (*apiv1.Node, error) {
+			return kubeCli.CoreV1().Nodes().Get(nodeName, metav1.GetOptions{})
+		},
+		tcGetFn: func(ns, tcName string) (*v1alpha1.TidbCluster, error) {
+			return cli.PingcapV1alpha1().TidbClusters(ns).Get(tcName, metav1.GetOptions{})
+		},
+	}
+}
+
+func (zs *zoneSpreading) Name() string {
+	return "ZoneSpreading"
+}
+
+func (zs *zoneSpreading) Score(instanceName string, pod *apiv1.Pod, nodes []apiv1.Node) (schedulerapiv1.HostPriorityList, error) {
+	ns := pod.GetNamespace()
+	tc, err := zs.tcGetFn(ns, instanceName)
+	if err != nil {
+		return nil, err
+	}
+	zoneKey := tc.HATopologyKey()
+	if zoneKey == "" {
+		zoneKey = apiv1.LabelZoneFailureDomain
+	}
+
+	nodeZones := map[string]string{}
+	for _, node := range nodes {
+		nodeZones[node.GetName()] = node.Labels[zoneKey]
+	}
+	podList, err := zs.podListFn(ns, instanceName, pod.Labels[label.ComponentLabelKey])
+	if err != nil {
+		return nil, err
+	}
+	counts := map[string]int{}
+	for _, p := range podList.Items {
+		nodeName := p.Spec.NodeName
+		if nodeName == "" || p.GetName() == pod.GetName() {
+			continue
+		}
+		zone, ok := nodeZones[nodeName]
+		if !ok {
+			node, err := zs.nodeGetFn(nodeName)
+			if err != nil {
+				return nil, err
+			}
+			zone = node.Labels[zoneKey]
+			nodeZones[nodeName] = zone
+		}
+		if zone != "" {
+			counts[zone]++
+		}
+	}
+	glog.V(4).Infof("zone spreading: %s/%s pods in zones %s: %v", ns, pod.GetName(), zoneKey, counts)
+
+	// the nodes without the zone label are not preferred
+	scores := scoreByCount(nodes, counts, func(node apiv1.Node) string {
+		return nodeZones[node.GetName()]
+	})
+	for i := range scores {
+		if nodeZones[scores[i].Host] == "" {
+			scores[i].Score = 0
+		}
+	}
+	return scores, nil
+}
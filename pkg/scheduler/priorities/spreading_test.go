@@ -0,0 +1,172 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package priorities
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	schedulerapiv1 "k8s.io/kubernetes/pkg/scheduler/api/v1"
+)
+
+func TestMemberSpreadingScore(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name         string
+		nodePodMap   map[string]int
+		expectScores map[string]int
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+
+		ms := &memberSpreading{podListFn: podListFn(test.nodePodMap)}
+		scores, err := ms.Score("demo", newTiKVPod(), newNodes(nil, "node-1", "node-2", "node-3"))
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(toScoreMap(scores)).To(Equal(test.expectScores))
+	}
+
+	tests := []testcase{
+		{
+			name:         "no pod scheduled",
+			nodePodMap:   map[string]int{},
+			expectScores: map[string]int{"node-1": 10, "node-2": 10, "node-3": 10},
+		},
+		{
+			name:         "nodes with fewer pods are preferred",
+			nodePodMap:   map[string]int{"node-1": 2, "node-2": 1},
+			expectScores: map[string]int{"node-1": 0, "node-2": 5, "node-3": 10},
+		},
+		{
+			name:         "pods on the other nodes are counted",
+			nodePodMap:   map[string]int{"node-4": 2, "node-2": 1},
+			expectScores: map[string]int{"node-1": 10, "node-2": 5, "node-3": 10},
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
+
+func TestZoneSpreadingScore(t *testing.T) {
+	g := NewGomegaWithT(t)
+	zoneKey := "topology.kubernetes.io/zone"
+
+	type testcase struct {
+		name          string
+		haTopologyKey string
+		nodePodMap    map[string]int
+		expectScores  map[string]int
+	}
+
+	testFn := func(test *testcase, t *testing.T) {
+		t.Log(test.name)
+
+		key := test.haTopologyKey
+		if key == "" {
+			key = apiv1.LabelZoneFailureDomain
+		}
+		zones := map[string]string{"node-1": "zone-1", "node-2": "zone-1", "node-3": "zone-2", "node-4": "zone-3"}
+		zs := &zoneSpreading{
+			podListFn: podListFn(test.nodePodMap),
+			nodeGetFn: func(nodeName string) (*apiv1.Node, error) {
+				node := newNodes(map[string]string{key: zones[nodeName]}, nodeName)[0]
+				return &node, nil
+			},
+			tcGetFn: func(ns, tcName string) (*v1alpha1.TidbCluster, error) {
+				tc := &v1alpha1.TidbCluster{}
+				tc.Spec.HATopologyKey = test.haTopologyKey
+				return tc, nil
+			},
+		}
+		nodes := append(newNodes(map[string]string{key: "zone-1"}, "node-1", "node-2"),
+			newNodes(map[string]string{key: "zone-2"}, "node-3")...)
+		nodes = append(nodes, newNodes(nil, "node-5")...)
+		scores, err := zs.Score("demo", newTiKVPod(), nodes)
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(toScoreMap(scores)).To(Equal(test.expectScores))
+	}
+
+	tests := []testcase{
+		{
+			name:         "no pod scheduled",
+			nodePodMap:   map[string]int{},
+			expectScores: map[string]int{"node-1": 10, "node-2": 10, "node-3": 10, "node-5": 0},
+		},
+		{
+			name:         "nodes in the zones with fewer pods are preferred",
+			nodePodMap:   map[string]int{"node-1": 1, "node-3": 1, "node-4": 2},
+			expectScores: map[string]int{"node-1": 5, "node-2": 5, "node-3": 5, "node-5": 0},
+		},
+		{
+			name:          "zones are read from the HA topology key",
+			haTopologyKey: zoneKey,
+			nodePodMap:    map[string]int{"node-2": 2},
+			expectScores:  map[string]int{"node-1": 0, "node-2": 0, "node-3": 10, "node-5": 0},
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i], t)
+	}
+}
+
+func podListFn(nodePodMap map[string]int) func(string, string, string) (*apiv1.PodList, error) {
+	return func(ns, instanceName, component string) (*apiv1.PodList, error) {
+		podList := &apiv1.PodList{}
+		for nodeName, count := range nodePodMap {
+			for i := 0; i < count; i++ {
+				podList.Items = append(podList.Items, apiv1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("demo-tikv-%s-%d", nodeName, i)},
+					Spec:       apiv1.PodSpec{NodeName: nodeName},
+				})
+			}
+		}
+		return podList, nil
+	}
+}
+
+func newTiKVPod() *apiv1.Pod {
+	return &apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "demo-tikv-0",
+			Namespace: metav1.NamespaceDefault,
+			UID:       "demo-tikv-0",
+			Labels:    label.New().Instance("demo").TiKV().Labels(),
+		},
+	}
+}
+
+func newNodes(labels map[string]string, names ...string) []apiv1.Node {
+	var nodes []apiv1.Node
+	for _, name := range names {
+		nodes = append(nodes, apiv1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}})
+	}
+	return nodes
+}
+
+func toScoreMap(scores schedulerapiv1.HostPriorityList) map[string]int {
+	m := map[string]int{}
+	for _, score := range scores {
+		m[score.Host] = score.Score
+	}
+	return m
+}
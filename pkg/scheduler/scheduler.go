@@ -16,13 +16,18 @@ package scheduler
 import (
 	"fmt"
 	"github.com/pingcap/tidb-operator/pkg/client/clientset/versioned"
-	"github.com/pingcap/tidb-operator/pkg/features"
 	"github.com/pingcap/tidb-operator/pkg/label"
 	"github.com/pingcap/tidb-operator/pkg/scheduler/predicates"
+	"github.com/pingcap/tidb-operator/pkg/scheduler/priorities"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	kubescheme "k8s.io/client-go/kubernetes/scheme"
 	eventv1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	glog "k8s.io/klog"
 	schedulerapiv1 "k8s.io/kubernetes/pkg/scheduler/api/v1"
@@ -45,6 +50,8 @@ type Scheduler interface {
 type scheduler struct {
 	// component => predicates
 	predicates map[string][]predicates.Predicate
+	// component => priorities
+	priorities map[string][]priorities.Priority
 }
 
 // NewScheduler returns a Scheduler
@@ -61,19 +68,90 @@ func NewScheduler(kubeCli kubernetes.Interface, cli versioned.Interface) Schedul
 		label.TiKVLabelVal: {
 			predicates.NewHA(kubeCli, cli, recorder),
 		},
-	}
-	if features.DefaultFeatureGate.Enabled(features.StableScheduling) {
-		predicatesByComponent[label.TiDBLabelVal] = []predicates.Predicate{
+		// the HA and stable scheduling of TiDB, Pump and TiFlash are enabled in the spec of the components
+		label.TiDBLabelVal: {
+			predicates.NewHA(kubeCli, cli, recorder),
 			predicates.NewStableScheduling(kubeCli, cli, recorder),
+		},
+		label.PumpLabelVal: {
+			predicates.NewStableScheduling(kubeCli, cli, recorder),
+		},
+		label.TiFlashLabelVal: {
+			predicates.NewStableScheduling(kubeCli, cli, recorder),
+		},
+	}
+	prioritiesByComponent := map[string][]priorities.Priority{
+		label.PDLabelVal: {
+			priorities.NewMemberSpreading(kubeCli),
+			priorities.NewZoneSpreading(kubeCli, cli),
+		},
+		label.TiKVLabelVal: {
+			priorities.NewMemberSpreading(kubeCli),
+			priorities.NewZoneSpreading(kubeCli, cli),
+		},
+		label.TiDBLabelVal: {
+			priorities.NewMemberSpreading(kubeCli),
+			priorities.NewZoneSpreading(kubeCli, cli),
+		},
+	}
+
+	// the LocalVolume and ResourceHeadroom priorities watch the PVs and the pods and PVCs of all namespaces,
+	// they are enabled only if the scheduler is granted the cluster scoped RBAC
+	if denied := deniedClusterResources(kubeCli, "pods", "persistentvolumeclaims", "persistentvolumes"); len(denied) > 0 {
+		glog.Errorf("the LocalVolume and ResourceHeadroom priorities are disabled, the scheduler is not allowed to list and watch %v of all namespaces, "+
+			"install tidb-operator with clusterScoped to enable them", denied)
+	} else {
+		informerFactory := kubeinformers.NewSharedInformerFactory(kubeCli, 0)
+		podInformer := informerFactory.Core().V1().Pods()
+		pvcInformer := informerFactory.Core().V1().PersistentVolumeClaims()
+		pvInformer := informerFactory.Core().V1().PersistentVolumes()
+		localVolume := priorities.NewLocalVolume(pvcInformer.Lister(), pvInformer.Lister())
+		resourceHeadroom := priorities.NewResourceHeadroom(podInformer.Lister())
+		prioritiesByComponent[label.PDLabelVal] = append(prioritiesByComponent[label.PDLabelVal], localVolume, resourceHeadroom)
+		prioritiesByComponent[label.TiKVLabelVal] = append(prioritiesByComponent[label.TiKVLabelVal], localVolume, resourceHeadroom)
+		prioritiesByComponent[label.TiDBLabelVal] = append(prioritiesByComponent[label.TiDBLabelVal], resourceHeadroom)
+
+		informerFactory.Start(wait.NeverStop)
+		if !cache.WaitForCacheSync(wait.NeverStop, podInformer.Informer().HasSynced,
+			pvcInformer.Informer().HasSynced, pvInformer.Informer().HasSynced) {
+			glog.Fatal("cache sync failed")
 		}
+		glog.Info("cache synced")
 	}
 	return &scheduler{
 		predicates: predicatesByComponent,
+		priorities: prioritiesByComponent,
 	}
 }
 
-// Filter selects a set of nodes from *schedulerapiv1.ExtenderArgs.Nodes when this is a pd or tikv pod
-// otherwise, returns the original nodes.
+// deniedClusterResources returns the resources which the scheduler is not allowed to list and watch in all namespaces
+func deniedClusterResources(kubeCli kubernetes.Interface, resources ...string) []string {
+	var denied []string
+	for _, resource := range resources {
+		for _, verb := range []string{"list", "watch"} {
+			review, err := kubeCli.AuthorizationV1().SelfSubjectAccessReviews().Create(&authorizationv1.SelfSubjectAccessReview{
+				Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+					ResourceAttributes: &authorizationv1.ResourceAttributes{
+						Namespace: metav1.NamespaceAll,
+						Verb:      verb,
+						Resource:  resource,
+					},
+				},
+			})
+			if err != nil {
+				glog.Errorf("failed to review the access to %s %s, %v", verb, resource, err)
+			}
+			if err != nil || !review.Status.Allowed {
+				denied = append(denied, resource)
+				break
+			}
+		}
+	}
+	return denied
+}
+
+// Filter selects a set of nodes from *schedulerapiv1.ExtenderArgs.Nodes when this is a pd, tikv, tidb, pump or
+// tiflash pod, otherwise, returns the original nodes.
 func (s *scheduler) Filter(args *schedulerapiv1.ExtenderArgs) (*schedulerapiv1.ExtenderFilterResult, error) {
 	pod := args.Pod
 	ns := pod.GetNamespace()
@@ -134,17 +212,52 @@ func (ferr FailureError) Error() string {
 	return fmt.Sprintf("pod %s had an intentional failure injected", ferr.PodName)
 }
 
-// We don't pass `prioritizeVerb` to kubernetes scheduler extender's config file, this method will not be called.
+// Priority scores the nodes by the priorities of the component of the pod, the score of a node is the average
+// of its scores of the priorities. The nodes get score 0 if the pod is not a pd, tikv or tidb pod.
+// The LocalVolume and ResourceHeadroom priorities are not registered without the cluster scoped RBAC.
 func (s *scheduler) Priority(args *schedulerapiv1.ExtenderArgs) (schedulerapiv1.HostPriorityList, error) {
 	result := schedulerapiv1.HostPriorityList{}
+	if args.Nodes == nil {
+		return result, nil
+	}
+	kubeNodes := args.Nodes.Items
 
-	if args.Nodes != nil {
-		for _, node := range args.Nodes.Items {
-			result = append(result, schedulerapiv1.HostPriority{
-				Host:  node.Name,
-				Score: 0,
-			})
+	var prioritiesByComponent []priorities.Priority
+	var instanceName string
+	if pod := args.Pod; pod != nil {
+		instanceName = pod.Labels[label.InstanceLabelKey]
+		if instanceName != "" {
+			prioritiesByComponent = s.priorities[pod.Labels[label.ComponentLabelKey]]
+		}
+	}
+
+	// the priorities are soft, a priority failed to score the nodes is skipped instead of failing the scheduling
+	scores := map[string]int{}
+	scored := 0
+	for _, priority := range prioritiesByComponent {
+		hostPriorities, err := priority.Score(instanceName, args.Pod, kubeNodes)
+		if err != nil {
+			glog.Warningf("priority: %s failed to score nodes for pod: %s/%s, %v", priority.Name(),
+				args.Pod.GetNamespace(), args.Pod.GetName(), err)
+			continue
+		}
+		glog.V(4).Infof("priority: %s, pod: %s/%s, scores: %v", priority.Name(),
+			args.Pod.GetNamespace(), args.Pod.GetName(), hostPriorities)
+		for _, hostPriority := range hostPriorities {
+			scores[hostPriority.Host] += hostPriority.Score
+		}
+		scored++
+	}
+
+	for _, node := range kubeNodes {
+		score := 0
+		if scored > 0 {
+			score = scores[node.Name] / scored
 		}
+		result = append(result, schedulerapiv1.HostPriority{
+			Host:  node.Name,
+			Score: score,
+		})
 	}
 
 	return result, nil
@@ -21,9 +21,14 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/pingcap/tidb-operator/pkg/label"
 	"github.com/pingcap/tidb-operator/pkg/scheduler/predicates"
+	"github.com/pingcap/tidb-operator/pkg/scheduler/priorities"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	apiv1 "k8s.io/api/core/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	core "k8s.io/client-go/testing"
 	schedulerapiv1 "k8s.io/kubernetes/pkg/scheduler/api/v1"
 )
 
@@ -192,7 +197,14 @@ func TestSchedulerPriority(t *testing.T) {
 	testFn := func(test *testcase, t *testing.T) {
 		t.Log(test.name)
 
-		s := scheduler{}
+		s := scheduler{
+			priorities: map[string][]priorities.Priority{
+				label.TiKVLabelVal: {
+					newFakePriority(map[string]int{"node-1": 10, "node-2": 4}),
+					newFakePriority(map[string]int{"node-1": 0, "node-2": 10}),
+				},
+			},
+		}
 		re, err := s.Priority(test.args)
 		test.expectFn(g, re, err)
 	}
@@ -262,6 +274,50 @@ func TestSchedulerPriority(t *testing.T) {
 				g.Expect(result[1].Score).To(Equal(0))
 			},
 		},
+		{
+			name: "tikv pod, scores are the average of the priorities",
+			args: &schedulerapiv1.ExtenderArgs{
+				Pod: &apiv1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "demo-tikv-0",
+						Labels: label.New().Instance("demo").TiKV().Labels(),
+					},
+				},
+				Nodes: &apiv1.NodeList{
+					Items: []apiv1.Node{
+						{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+						{ObjectMeta: metav1.ObjectMeta{Name: "node-2"}},
+					},
+				},
+			},
+			expectFn: func(g *GomegaWithT, result schedulerapiv1.HostPriorityList, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(result).To(Equal(schedulerapiv1.HostPriorityList{
+					{Host: "node-1", Score: 5},
+					{Host: "node-2", Score: 7},
+				}))
+			},
+		},
+		{
+			name: "tidb pod without priorities",
+			args: &schedulerapiv1.ExtenderArgs{
+				Pod: &apiv1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "demo-tidb-0",
+						Labels: label.New().Instance("demo").TiDB().Labels(),
+					},
+				},
+				Nodes: &apiv1.NodeList{
+					Items: []apiv1.Node{
+						{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+					},
+				},
+			},
+			expectFn: func(g *GomegaWithT, result schedulerapiv1.HostPriorityList, err error) {
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(result).To(Equal(schedulerapiv1.HostPriorityList{{Host: "node-1", Score: 0}}))
+			},
+		},
 	}
 
 	for i := range tests {
@@ -269,6 +325,24 @@ func TestSchedulerPriority(t *testing.T) {
 	}
 }
 
+func TestDeniedClusterResources(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	kubeCli := kubefake.NewSimpleClientset()
+	kubeCli.PrependReactor("create", "selfsubjectaccessreviews", func(action core.Action) (bool, runtime.Object, error) {
+		review := action.(core.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		attrs := review.Spec.ResourceAttributes
+		if attrs.Resource == "persistentvolumes" && attrs.Verb == "watch" {
+			return true, &authorizationv1.SelfSubjectAccessReview{}, fmt.Errorf("API server failed")
+		}
+		review.Status.Allowed = attrs.Resource != "pods"
+		return true, review, nil
+	})
+
+	g.Expect(deniedClusterResources(kubeCli, "persistentvolumeclaims")).To(BeEmpty())
+	g.Expect(deniedClusterResources(kubeCli, "pods", "persistentvolumeclaims", "persistentvolumes")).To(Equal([]string{"pods", "persistentvolumes"}))
+}
+
 type fakeErrPredicate struct {
 	err error
 }
@@ -292,3 +366,23 @@ func (fep *fakeErrPredicate) Filter(_ string, _ *apiv1.Pod, nodes []apiv1.Node)
 
 	return nodes, nil
 }
+
+type fakePriority struct {
+	scores map[string]int
+}
+
+func newFakePriority(scores map[string]int) *fakePriority {
+	return &fakePriority{scores: scores}
+}
+
+func (fp *fakePriority) Name() string {
+	return "fakePriority"
+}
+
+func (fp *fakePriority) Score(_ string, _ *apiv1.Pod, nodes []apiv1.Node) (schedulerapiv1.HostPriorityList, error) {
+	result := schedulerapiv1.HostPriorityList{}
+	for _, node := range nodes {
+		result = append(result, schedulerapiv1.HostPriority{Host: node.Name, Score: fp.scores[node.Name]})
+	}
+	return result, nil
+}
@@ -10,7 +10,7 @@ import (
 )
 
 const (
-	usage = "usage: to-crdgen generate [tidbcluster | backup | restore | backupschedule [<options>]"
+	usage = "usage: to-crdgen generate [tidbcluster | backup | restore | backupschedule | dataimport | tidbmonitor | tidbclusterautoscaler [<options>]"
 )
 
 func AddGenerateCommand(config *crdutils.Config) *cobra.Command {
@@ -171,6 +171,65 @@ var (
 		Priority:    1,
 		JSONPath:    ".status.lastBackupTime",
 	}
+	importAdditionalPrinterColumns []extensionsobj.CustomResourceColumnDefinition
+	importBackendColumn            = extensionsobj.CustomResourceColumnDefinition{
+		Name:        "Backend",
+		Type:        "string",
+		Description: "The backend of tidb-lightning",
+		JSONPath:    ".spec.backend",
+	}
+	importProgressColumn = extensionsobj.CustomResourceColumnDefinition{
+		Name:        "Progress",
+		Type:        "string",
+		Description: "The percentage of the imported chunks",
+		JSONPath:    ".status.progress",
+	}
+	importStartedColumn = extensionsobj.CustomResourceColumnDefinition{
+		Name:        "Started",
+		Type:        "date",
+		Description: "The time at which the import was started",
+		Priority:    1,
+		JSONPath:    ".status.timeStarted",
+	}
+	importCompletedColumn = extensionsobj.CustomResourceColumnDefinition{
+		Name:        "Completed",
+		Type:        "date",
+		Description: "The time at which the import was completed",
+		Priority:    1,
+		JSONPath:    ".status.timeCompleted",
+	}
+	tidbMonitorAdditionalPrinterColumns []extensionsobj.CustomResourceColumnDefinition
+	tidbMonitorPrometheusVersionColumn  = extensionsobj.CustomResourceColumnDefinition{
+		Name:        "Prometheus",
+		Type:        "string",
+		Description: "The version of Prometheus",
+		JSONPath:    ".spec.prometheus.version",
+	}
+	tidbMonitorDashboardVersionColumn = extensionsobj.CustomResourceColumnDefinition{
+		Name:        "Dashboard",
+		Type:        "string",
+		Description: "The version of the dashboards and Prometheus rules",
+		JSONPath:    ".status.dashboardVersion",
+	}
+	tidbMonitorReadyColumn = extensionsobj.CustomResourceColumnDefinition{
+		Name:        "Ready",
+		Type:        "integer",
+		Description: "The number of the ready monitor pods",
+		JSONPath:    ".status.deployment.readyReplicas",
+	}
+	autoScalerAdditionalPrinterColumns []extensionsobj.CustomResourceColumnDefinition
+	autoScalerTiKVColumn               = extensionsobj.CustomResourceColumnDefinition{
+		Name:        "TiKV",
+		Type:        "integer",
+		Description: "The recommended replicas of TiKV",
+		JSONPath:    ".status.tikv.recommendedReplicas",
+	}
+	autoScalerTiDBColumn = extensionsobj.CustomResourceColumnDefinition{
+		Name:        "TiDB",
+		Type:        "integer",
+		Description: "The recommended replicas of TiDB",
+		JSONPath:    ".status.tidb.recommendedReplicas",
+	}
 )
 
 func init() {
@@ -181,6 +240,9 @@ func init() {
 	backupAdditionalPrinterColumns = append(backupAdditionalPrinterColumns, backupStorageTypeColumn, backupBackupSizeColumn, backupCommitTSColumn, backupStartedColumn, backupCompletedColumn)
 	restoreAdditionalPrinterColumns = append(restoreAdditionalPrinterColumns, restoreBackupColumn, restoreStartedColumn, restoreCompletedColumn)
 	bksAdditionalPrinterColumns = append(bksAdditionalPrinterColumns, bksScheduleColumn, bksMaxBackups, bksLastBackup, bksLastBackupTime)
+	importAdditionalPrinterColumns = append(importAdditionalPrinterColumns, importBackendColumn, importProgressColumn, importStartedColumn, importCompletedColumn)
+	tidbMonitorAdditionalPrinterColumns = append(tidbMonitorAdditionalPrinterColumns, tidbMonitorPrometheusVersionColumn, tidbMonitorDashboardVersionColumn, tidbMonitorReadyColumn)
+	autoScalerAdditionalPrinterColumns = append(autoScalerAdditionalPrinterColumns, autoScalerTiKVColumn, autoScalerTiDBColumn)
 }
 
 func NewCustomResourceDefinition(crdKind v1alpha1.CrdKind, group string, labels map[string]string, validation bool) *extensionsobj.CustomResourceDefinition {
@@ -210,6 +272,12 @@ func GetCrdKindFromKindName(kindName string) (v1alpha1.CrdKind, error) {
 		return v1alpha1.DefaultCrdKinds.Restore, nil
 	case v1alpha1.BackupScheduleKindKey:
 		return v1alpha1.DefaultCrdKinds.BackupSchedule, nil
+	case v1alpha1.DataImportKindKey:
+		return v1alpha1.DefaultCrdKinds.DataImport, nil
+	case v1alpha1.TiDBMonitorKindKey:
+		return v1alpha1.DefaultCrdKinds.TiDBMonitor, nil
+	case v1alpha1.TidbClusterAutoScalerKindKey:
+		return v1alpha1.DefaultCrdKinds.TidbClusterAutoScaler, nil
 	default:
 		return v1alpha1.CrdKind{}, errors.New("unknown CrdKind Name")
 	}
@@ -229,6 +297,15 @@ func addAdditionalPrinterColumnsForCRD(crd *extensionsobj.CustomResourceDefiniti
 	case v1alpha1.DefaultCrdKinds.BackupSchedule.Kind:
 		crd.Spec.AdditionalPrinterColumns = bksAdditionalPrinterColumns
 		break
+	case v1alpha1.DefaultCrdKinds.DataImport.Kind:
+		crd.Spec.AdditionalPrinterColumns = importAdditionalPrinterColumns
+		break
+	case v1alpha1.DefaultCrdKinds.TiDBMonitor.Kind:
+		crd.Spec.AdditionalPrinterColumns = tidbMonitorAdditionalPrinterColumns
+		break
+	case v1alpha1.DefaultCrdKinds.TidbClusterAutoScaler.Kind:
+		crd.Spec.AdditionalPrinterColumns = autoScalerAdditionalPrinterColumns
+		break
 	default:
 		break
 	}
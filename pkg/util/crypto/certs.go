@@ -22,7 +22,10 @@ import (
 	"encoding/pem"
 	"fmt"
 	"io/ioutil"
+	"math"
+	"math/big"
 	"net"
+	"time"
 
 	glog "k8s.io/klog"
 )
@@ -30,6 +33,11 @@ import (
 const (
 	rsaKeySize = 2048
 	k8sCAFile  = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+
+	// caValidity is the validity of the CA generated by NewCA
+	caValidity = 10 * 365 * 24 * time.Hour
+	// certValidity is the validity of the certificates signed by SignCert
+	certValidity = 365 * 24 * time.Hour
 )
 
 // generate a new private key
@@ -84,6 +92,90 @@ func NewCSR(commonName string, hostList []string, IPList []string) ([]byte, []by
 	return csr, convertKeyToPEM("RSA PRIVATE KEY", privKey), nil
 }
 
+// NewCA generates a self-signed CA, the PEM encoded certificate and key are returned
+func NewCA(commonName string) ([]byte, []byte, error) {
+	privKey, err := newPrivateKey(rsaKeySize)
+	if err != nil {
+		return nil, nil, err
+	}
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization:       []string{"PingCAP"},
+			OrganizationalUnit: []string{"TiDB Operator"},
+			CommonName:         commonName,
+		},
+		NotBefore:             now.Add(-time.Minute),
+		NotAfter:              now.Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, privKey.Public(), privKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certBytes}), convertKeyToPEM("RSA PRIVATE KEY", privKey), nil
+}
+
+// SignCert generates a new key pair and signs the certificate for both server and client auth with the CA,
+// the PEM encoded certificate and key are returned
+func SignCert(caCert []byte, caKey []byte, commonName string, hostList []string, IPList []string) ([]byte, []byte, error) {
+	ca, err := tls.X509KeyPair(caCert, caKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("fail to load CA, %v", err)
+	}
+	caX509, err := x509.ParseCertificate(ca.Certificate[0])
+	if err != nil {
+		return nil, nil, fmt.Errorf("fail to parse CA certificate, %v", err)
+	}
+
+	privKey, err := newPrivateKey(rsaKeySize)
+	if err != nil {
+		return nil, nil, err
+	}
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var ipAddrList []net.IP
+	for _, ip := range IPList {
+		ipAddrList = append(ipAddrList, net.ParseIP(ip))
+	}
+
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization:       []string{"PingCAP"},
+			OrganizationalUnit: []string{"TiDB Operator"},
+			CommonName:         commonName,
+		},
+		DNSNames:    hostList,
+		IPAddresses: ipAddrList,
+		NotBefore:   now.Add(-time.Minute),
+		NotAfter:    now.Add(certValidity),
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, tmpl, caX509, privKey.Public(), ca.PrivateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certBytes}), convertKeyToPEM("RSA PRIVATE KEY", privKey), nil
+}
+
+func newSerialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).SetInt64(math.MaxInt64))
+}
+
 func ReadCACerts() (*x509.CertPool, error) {
 	// try to load system CA certs
 	rootCAs, err := x509.SystemCertPool()
@@ -106,12 +198,19 @@ func ReadCACerts() (*x509.CertPool, error) {
 	return rootCAs, nil
 }
 
-func LoadCerts(cert []byte, key []byte) (*x509.CertPool, tls.Certificate, error) {
+// ReadK8sCA returns the PEM encoded CA certificate of the Kubernetes cluster
+func ReadK8sCA() ([]byte, error) {
+	return ioutil.ReadFile(k8sCAFile)
+}
+
+// LoadCerts loads the client cert and key, and the root CAs which trust the CA issuing the cert,
+// the k8s CA is trusted if ca is empty
+func LoadCerts(cert []byte, key []byte, ca []byte) (*x509.CertPool, tls.Certificate, error) {
 	if cert == nil || key == nil {
 		return nil, tls.Certificate{}, fmt.Errorf("fail to load certs, cert and key can not be empty")
 	}
 
-	rootCAs, err := ReadCACerts()
+	rootCAs, err := LoadCACerts(ca)
 	if err != nil {
 		return rootCAs, tls.Certificate{}, err
 	}
@@ -120,3 +219,31 @@ func LoadCerts(cert []byte, key []byte) (*x509.CertPool, tls.Certificate, error)
 	tlsCert, err := tls.X509KeyPair(cert, key)
 	return rootCAs, tlsCert, err
 }
+
+// LoadCACerts returns the system CAs with ca appended, the k8s CA is appended instead if ca is empty
+func LoadCACerts(ca []byte) (*x509.CertPool, error) {
+	if len(ca) == 0 {
+		return ReadCACerts()
+	}
+
+	rootCAs, err := x509.SystemCertPool()
+	if err != nil {
+		return nil, err
+	}
+	if rootCAs == nil {
+		rootCAs = x509.NewCertPool()
+	}
+	if ok := rootCAs.AppendCertsFromPEM(ca); !ok {
+		return nil, fmt.Errorf("fail to append CA to pool")
+	}
+	return rootCAs, nil
+}
+
+// ParseCert parses the first PEM encoded certificate in cert
+func ParseCert(cert []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(cert)
+	if block == nil {
+		return nil, fmt.Errorf("fail to decode cert to PEM")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
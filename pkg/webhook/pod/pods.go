@@ -53,6 +53,8 @@ type PodAdmissionControl struct {
 	pvcControl controller.PVCControlInterface
 	// pd Control
 	pdControl pdapi.PDControlInterface
+	// tidb Control
+	tidbControl controller.TiDBControlInterface
 	// pod Lister
 	podLister corelisters.PodLister
 	// tc Lister
@@ -85,6 +87,7 @@ func NewPodAdmissionControl(kubeCli kubernetes.Interface, operatorCli versioned.
 		operatorCli:     operatorCli,
 		pvcControl:      PVCControl,
 		pdControl:       PdControl,
+		tidbControl:     controller.NewDefaultTiDBControl(kubeInformerFactory.Core().V1().Secrets()),
 		podLister:       podLister,
 		tcLister:        tcLister,
 		stsLister:       stsLister,
@@ -212,6 +215,8 @@ func (pc *PodAdmissionControl) admitDeletePods(name, namespace string) *admissio
 		return pc.admitDeletePdPods(payload)
 	} else if l.IsTiKV() {
 		return pc.admitDeleteTiKVPods(payload)
+	} else if l.IsTiDB() {
+		return pc.admitDeleteTiDBPods(payload)
 	}
 
 	klog.Infof("[%s/%s] is admit to be deleted", namespace, name)
@@ -146,6 +146,7 @@ func newPodAdmissionControl() (*PodAdmissionControl, *controller.FakePVCControl,
 			operatorCli: operatorCli,
 			pvcControl:  pvcControl,
 			pdControl:   pdControl,
+			tidbControl: controller.NewFakeTiDBControl(),
 			podLister:   podInformer.Lister(),
 			tcLister:    informer.Pingcap().V1alpha1().TidbClusters().Lister(),
 			stsLister:   stsInformer.Lister(),
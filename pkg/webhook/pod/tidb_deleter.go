@@ -0,0 +1,76 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"fmt"
+
+	memberUtils "github.com/pingcap/tidb-operator/pkg/manager/member"
+	operatorUtils "github.com/pingcap/tidb-operator/pkg/util"
+	"github.com/pingcap/tidb-operator/pkg/webhook/util"
+	admission "k8s.io/api/admission/v1"
+	"k8s.io/klog"
+)
+
+// admitDeleteTiDBPods drains the client connections of the tidb pod before it is deleted,
+// the pod is removed from the service endpoints first, and the deletion is refused until
+// its connections are drained or the drain timeout is exceeded. The pod of a TiDB group is drained
+// as specified by its group.
+func (pc *PodAdmissionControl) admitDeleteTiDBPods(payload *admitPayload) *admission.AdmissionResponse {
+
+	pod := payload.pod
+	tc := memberUtils.TiDBGroupViewOfPod(payload.tc, pod)
+	name := pod.Name
+	namespace := pod.Namespace
+	tcName := tc.Name
+
+	if tc.Spec.TiDB.Drain == nil || !memberUtils.HasTiDBServingGate(pod) {
+		klog.Infof("tc[%s/%s]'s tidb pod[%s/%s] doesn't drain connections,admit to delete", namespace, tcName, namespace, name)
+		return util.ARSuccess()
+	}
+
+	ordinal, err := operatorUtils.GetOrdinalFromPodName(name)
+	if err != nil {
+		return util.ARFail(err)
+	}
+
+	if !memberUtils.IsTiDBDraining(pod) {
+		memberUtils.BeginTiDBDrain(pod, memberUtils.TiDBDrainByWebhook)
+		updatedPod, err := pc.kubeCli.CoreV1().Pods(namespace).Update(pod)
+		if err != nil {
+			klog.Errorf("tc[%s/%s]'s tidb pod[%s/%s] failed to begin draining,%v", namespace, tcName, namespace, name, err)
+			return util.ARFail(err)
+		}
+		pod = updatedPod
+	}
+	if memberUtils.SetTiDBServingCondition(pod, false) {
+		if _, err := pc.kubeCli.CoreV1().Pods(namespace).UpdateStatus(pod); err != nil {
+			klog.Errorf("tc[%s/%s]'s tidb pod[%s/%s] failed to be removed from service endpoints,%v", namespace, tcName, namespace, name, err)
+			return util.ARFail(err)
+		}
+		return util.ARFail(fmt.Errorf("tc[%s/%s]'s tidb pod[%s/%s] begins draining connections", namespace, tcName, namespace, name))
+	}
+
+	drained, err := memberUtils.TiDBDrained(tc, pc.tidbControl, pod, ordinal)
+	if err != nil {
+		klog.Errorf("tc[%s/%s]'s tidb pod[%s/%s] failed to check connections,%v", namespace, tcName, namespace, name, err)
+		return util.ARFail(err)
+	}
+	if !drained {
+		return util.ARFail(fmt.Errorf("tc[%s/%s]'s tidb pod[%s/%s] is draining connections", namespace, tcName, namespace, name))
+	}
+
+	klog.Infof("tc[%s/%s]'s tidb pod[%s/%s] is drained,admit to delete", namespace, tcName, namespace, name)
+	return util.ARSuccess()
+}
@@ -0,0 +1,160 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	memberUtils "github.com/pingcap/tidb-operator/pkg/manager/member"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTiDBDeleterDelete(t *testing.T) {
+
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name           string
+		drain          bool
+		groupDrain     bool
+		drainBeginTime *time.Time
+		connections    int
+		expectAllowed  bool
+		expectServing  bool
+		expectDrainBy  string
+	}
+
+	testFn := func(test *testcase) {
+		t.Log(test.name)
+
+		tc := newTidbClusterForPodAdmissionControl()
+		if test.drain {
+			timeout := int32(60)
+			tc.Spec.TiDB.Drain = &v1alpha1.TiDBDrainSpec{MaxConnections: 1, TimeoutSeconds: &timeout}
+		}
+		pod := newTiDBPodForPodAdmissionControl(1)
+		if test.groupDrain {
+			timeout := int32(60)
+			tc.Spec.TiDBGroups = []v1alpha1.TiDBGroupSpec{{
+				Name:     "olap",
+				TiDBSpec: v1alpha1.TiDBSpec{Drain: &v1alpha1.TiDBDrainSpec{TimeoutSeconds: &timeout}},
+			}}
+			pod.Name = fmt.Sprintf("%s-tidb-olap-1", tcName)
+			pod.Labels[label.TiDBGroupLabelKey] = "olap"
+		}
+		if test.drainBeginTime != nil {
+			memberUtils.BeginTiDBDrain(pod, memberUtils.TiDBDrainByWebhook)
+			pod.Annotations[label.AnnTiDBDrainBeginTime] = test.drainBeginTime.Format(time.RFC3339)
+			memberUtils.SetTiDBServingCondition(pod, false)
+		} else {
+			memberUtils.SetTiDBServingCondition(pod, true)
+		}
+
+		podAdmissionControl, _, _, _, _, _ := newPodAdmissionControl()
+		_, err := podAdmissionControl.kubeCli.CoreV1().Pods(namespace).Create(pod)
+		g.Expect(err).NotTo(HaveOccurred())
+		podAdmissionControl.tidbControl.(*controller.FakeTiDBControl).SetConnections(test.connections)
+
+		payload := &admitPayload{
+			pod: pod,
+			tc:  tc,
+		}
+		response := podAdmissionControl.admitDeleteTiDBPods(payload)
+		g.Expect(response.Allowed).To(Equal(test.expectAllowed))
+
+		pod, err = podAdmissionControl.kubeCli.CoreV1().Pods(namespace).Get(pod.Name, meta.GetOptions{})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(memberUtils.IsTiDBServing(pod)).To(Equal(test.expectServing))
+		g.Expect(pod.Annotations[label.AnnTiDBDrainBy]).To(Equal(test.expectDrainBy))
+	}
+
+	now := time.Now()
+	expired := now.Add(-2 * time.Minute)
+	tests := []testcase{
+		{
+			name:          "connections are not drained",
+			drain:         false,
+			expectAllowed: true,
+			expectServing: true,
+		},
+		{
+			name:          "the pod of a group is drained as specified by the group",
+			groupDrain:    true,
+			connections:   10,
+			expectAllowed: false,
+			expectServing: false,
+			expectDrainBy: memberUtils.TiDBDrainByWebhook,
+		},
+		{
+			name:          "begin draining",
+			drain:         true,
+			connections:   10,
+			expectAllowed: false,
+			expectServing: false,
+			expectDrainBy: memberUtils.TiDBDrainByWebhook,
+		},
+		{
+			name:           "draining is in progress",
+			drain:          true,
+			drainBeginTime: &now,
+			connections:    10,
+			expectAllowed:  false,
+			expectServing:  false,
+			expectDrainBy:  memberUtils.TiDBDrainByWebhook,
+		},
+		{
+			name:           "connections are drained",
+			drain:          true,
+			drainBeginTime: &now,
+			connections:    1,
+			expectAllowed:  true,
+			expectServing:  false,
+			expectDrainBy:  memberUtils.TiDBDrainByWebhook,
+		},
+		{
+			name:           "drain timeout is exceeded",
+			drain:          true,
+			drainBeginTime: &expired,
+			connections:    10,
+			expectAllowed:  true,
+			expectServing:  false,
+			expectDrainBy:  memberUtils.TiDBDrainByWebhook,
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i])
+	}
+}
+
+func newTiDBPodForPodAdmissionControl(ordinal int32) *core.Pod {
+	return &core.Pod{
+		TypeMeta: meta.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+		ObjectMeta: meta.ObjectMeta{
+			Name:      fmt.Sprintf("%s-tidb-%d", tcName, ordinal),
+			Namespace: namespace,
+			Labels:    label.New().Instance(tcName).TiDB().Labels(),
+		},
+		Spec: core.PodSpec{
+			ReadinessGates: []core.PodReadinessGate{{ConditionType: memberUtils.TiDBServingCondition}},
+		},
+	}
+}
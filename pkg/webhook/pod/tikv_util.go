@@ -22,7 +22,7 @@ import (
 	"k8s.io/klog"
 
 	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
-	memberUtil "github.com/pingcap/tidb-operator/pkg/manager/member"
+	"github.com/pingcap/tidb-operator/pkg/label"
 	"github.com/pingcap/tidb-operator/pkg/pdapi"
 	apps "k8s.io/api/apps/v1"
 	core "k8s.io/api/core/v1"
@@ -30,24 +30,30 @@ import (
 )
 
 // checkFormerTiKVPodStatus would check all the former tikv pods whether their store state were UP during Upgrading
-// check need both  check former pod is ready ,store up, and no evict leader
-func checkFormerTiKVPodStatus(podLister corelisters.PodLister, tc *v1alpha1.TidbCluster, ordinal int32, replicas int32, storesInfo *pdapi.StoresInfo) error {
+// check need both  check former pod is ready ,store up, and no evict leader.
+// The former pods are the pods of the owner StatefulSet, which may be the StatefulSet of a TiKV group.
+func checkFormerTiKVPodStatus(podLister corelisters.PodLister, tc *v1alpha1.TidbCluster, set *apps.StatefulSet, ordinal int32, storesInfo *pdapi.StoresInfo) error {
 
 	tcName := tc.Name
 	namespace := tc.Namespace
 
-	for i := replicas - 1; i > ordinal; i-- {
-		podName := memberUtil.TikvPodName(tcName, i)
+	updateRevision, err := tikvUpdateRevision(tc, set)
+	if err != nil {
+		return err
+	}
+
+	for i := *set.Spec.Replicas - 1; i > ordinal; i-- {
+		podName := fmt.Sprintf("%s-%d", set.Name, i)
 		pod, err := podLister.Pods(namespace).Get(podName)
 		if err != nil {
 			return err
 		}
 		revision, exist := pod.Labels[apps.ControllerRevisionHashLabelKey]
 		if !exist {
-			return fmt.Errorf("tidbcluster: [%s/%s]'s pd pod: [%s] has no label: %s", namespace, tcName, podName, apps.ControllerRevisionHashLabelKey)
+			return fmt.Errorf("tidbcluster: [%s/%s]'s tikv pod: [%s] has no label: %s", namespace, tcName, podName, apps.ControllerRevisionHashLabelKey)
 		}
 
-		if revision != tc.Status.TiKV.StatefulSet.UpdateRevision {
+		if revision != updateRevision {
 			return fmt.Errorf("tc[%s/%s]'s tikv pod[%s/%s] is not upgraded yet", namespace, tcName, namespace, podName)
 		}
 
@@ -63,6 +69,23 @@ func checkFormerTiKVPodStatus(podLister corelisters.PodLister, tc *v1alpha1.Tidb
 	return nil
 }
 
+// tikvUpdateRevision returns the update revision recorded in the TidbCluster status for the TiKV StatefulSet,
+// which is the StatefulSet of spec.tikv or of the TiKV group its pods are labeled with
+func tikvUpdateRevision(tc *v1alpha1.TidbCluster, set *apps.StatefulSet) (string, error) {
+	status := tc.Status.TiKV
+	if group := set.Spec.Template.Labels[label.TiKVGroupLabelKey]; group != "" {
+		var exist bool
+		status, exist = tc.Status.TiKVGroups[group]
+		if !exist {
+			return "", fmt.Errorf("tc[%s/%s] has no status of tikv group %s", tc.Namespace, tc.Name, group)
+		}
+	}
+	if status.StatefulSet == nil {
+		return "", fmt.Errorf("tc[%s/%s] has no status of tikv statefulset %s", tc.Namespace, tc.Name, set.Name)
+	}
+	return status.StatefulSet.UpdateRevision, nil
+}
+
 func addEvictLeaderAnnotation(kubeCli kubernetes.Interface, pod *core.Pod) error {
 
 	name := pod.Name
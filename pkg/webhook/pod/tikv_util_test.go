@@ -0,0 +1,149 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pod
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	"github.com/pingcap/tidb-operator/pkg/label"
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	apps "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+)
+
+func TestCheckFormerTiKVPodStatus(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	type testcase struct {
+		name          string
+		group         string
+		podRevision   string
+		tikvRevision  string
+		groupRevision string
+		storeState    string
+		expectErr     bool
+	}
+
+	testFn := func(test *testcase) {
+		t.Log(test.name)
+
+		setName := controller.TiKVMemberName(tcName)
+		if test.group != "" {
+			setName = controller.TiKVGroupMemberName(tcName, test.group)
+		}
+		set := &apps.StatefulSet{}
+		set.Name = setName
+		set.Namespace = namespace
+		set.Spec.Replicas = func() *int32 { a := int32(3); return &a }()
+		set.Spec.Template.Labels = map[string]string{label.ComponentLabelKey: label.TiKVLabelVal}
+		if test.group != "" {
+			set.Spec.Template.Labels[label.TiKVGroupLabelKey] = test.group
+		}
+
+		tc := newTidbClusterForPodAdmissionControl()
+		tc.Status.TiKV.StatefulSet = &apps.StatefulSetStatus{UpdateRevision: test.tikvRevision}
+		tc.Status.TiKVGroups = map[string]v1alpha1.TiKVStatus{
+			"group": {StatefulSet: &apps.StatefulSetStatus{UpdateRevision: test.groupRevision}},
+		}
+
+		podAdmissionControl, _, _, podIndexer, _, _ := newPodAdmissionControl()
+		storesInfo := &pdapi.StoresInfo{}
+		for i := 0; i < 3; i++ {
+			pod := &core.Pod{}
+			pod.Name = fmt.Sprintf("%s-%d", setName, i)
+			pod.Namespace = namespace
+			pod.Labels = map[string]string{apps.ControllerRevisionHashLabelKey: test.podRevision}
+			podIndexer.Add(pod)
+			storesInfo.Stores = append(storesInfo.Stores, &pdapi.StoreInfo{
+				Store: &pdapi.MetaStore{
+					StateName: test.storeState,
+					Store: &metapb.Store{
+						Id:      uint64(i),
+						Address: fmt.Sprintf("%s-%d.%s-tikv-peer.%s.svc:20160", setName, i, tcName, namespace),
+					},
+				},
+			})
+		}
+
+		err := checkFormerTiKVPodStatus(podAdmissionControl.podLister, tc, set, 0, storesInfo)
+		if test.expectErr {
+			g.Expect(err).To(HaveOccurred())
+		} else {
+			g.Expect(err).NotTo(HaveOccurred())
+		}
+	}
+
+	tests := []testcase{
+		{
+			name:          "former tikv pods upgraded",
+			podRevision:   "2",
+			tikvRevision:  "2",
+			groupRevision: "1",
+			storeState:    v1alpha1.TiKVStateUp,
+			expectErr:     false,
+		},
+		{
+			name:          "former tikv pods not upgraded",
+			podRevision:   "1",
+			tikvRevision:  "2",
+			groupRevision: "1",
+			storeState:    v1alpha1.TiKVStateUp,
+			expectErr:     true,
+		},
+		{
+			name:          "former tikv stores not up",
+			podRevision:   "2",
+			tikvRevision:  "2",
+			groupRevision: "2",
+			storeState:    v1alpha1.TiKVStateDown,
+			expectErr:     true,
+		},
+		{
+			name:          "former pods of the group upgraded",
+			group:         "group",
+			podRevision:   "2",
+			tikvRevision:  "1",
+			groupRevision: "2",
+			storeState:    v1alpha1.TiKVStateUp,
+			expectErr:     false,
+		},
+		{
+			name:          "former pods of the group not upgraded",
+			group:         "group",
+			podRevision:   "1",
+			tikvRevision:  "1",
+			groupRevision: "2",
+			storeState:    v1alpha1.TiKVStateUp,
+			expectErr:     true,
+		},
+		{
+			name:          "no status of the group",
+			group:         "other",
+			podRevision:   "2",
+			tikvRevision:  "2",
+			groupRevision: "2",
+			storeState:    v1alpha1.TiKVStateUp,
+			expectErr:     true,
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i])
+	}
+}
@@ -55,7 +55,7 @@ func NewWebHookServer(kubeCli kubernetes.Interface, operatorCli versioned.Interf
 	}
 
 	// init pdControl
-	pdControl := pdapi.NewDefaultPDControl(kubeCli)
+	pdControl := pdapi.NewDefaultPDControl(kubeInformerFactory.Core().V1().Secrets())
 
 	// init recorder
 	eventBroadcaster := record.NewBroadcaster()
@@ -60,6 +60,8 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
+	kubeinformers "k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
 	typedappsv1 "k8s.io/client-go/kubernetes/typed/apps/v1"
 	glog "k8s.io/klog"
@@ -85,14 +87,15 @@ func NewOperatorActions(cli versioned.Interface,
 	cfg *Config,
 	clusters []*TidbClusterConfig) OperatorActions {
 
+	secretInformer := newSecretInformerOrDie(kubeCli)
 	oa := &operatorActions{
 		cli:         cli,
 		kubeCli:     kubeCli,
-		pdControl:   pdapi.NewDefaultPDControl(kubeCli),
+		pdControl:   pdapi.NewDefaultPDControl(secretInformer),
 		asCli:       asCli,
 		tcStsGetter: kubeCli.AppsV1(),
 		// tcStsGetter:  helper.NewHijackClient(kubeCli, asCli).AppsV1(),
-		tidbControl:  controller.NewDefaultTiDBControl(),
+		tidbControl:  controller.NewDefaultTiDBControl(secretInformer),
 		pollInterval: pollInterval,
 		cfg:          cfg,
 	}
@@ -107,6 +110,20 @@ func NewOperatorActions(cli versioned.Interface,
 	return oa
 }
 
+// newSecretInformerOrDie returns a started and synced Secret informer
+func newSecretInformerOrDie(kubeCli kubernetes.Interface) coreinformers.SecretInformer {
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeCli, 0)
+	secretInformer := kubeInformerFactory.Core().V1().Secrets()
+	secretInformer.Informer()
+	kubeInformerFactory.Start(wait.NeverStop)
+	for v, synced := range kubeInformerFactory.WaitForCacheSync(wait.NeverStop) {
+		if !synced {
+			slack.NotifyAndPanic(fmt.Errorf("error syncing informer for %v", v))
+		}
+	}
+	return secretInformer
+}
+
 const (
 	DefaultPollTimeout          time.Duration = 10 * time.Minute
 	DefaultPollInterval         time.Duration = 1 * time.Minute
@@ -1102,7 +1119,7 @@ func (oa *operatorActions) CheckScaleInSafely(info *TidbClusterConfig) error {
 			return false, nil
 		}
 
-		pdClient := controller.GetPDClient(pdapi.NewDefaultPDControl(oa.kubeCli), tc)
+		pdClient := controller.GetPDClient(oa.pdControl, tc)
 		stores, err := pdClient.GetStores()
 		if err != nil {
 			glog.Infof("pdClient.GetStores failed,error: %v", err)
@@ -1206,7 +1223,7 @@ func (oa *operatorActions) CheckUpgrade(ctx context.Context, info *TidbClusterCo
 		return fmt.Errorf("failed to get tidbcluster: %s/%s, %v", ns, tcName, err)
 
 	}
-	pdClient := pdapi.NewDefaultPDControl(oa.kubeCli).GetPDClient(pdapi.Namespace(tc.GetNamespace()), tc.GetName(), tc.Spec.EnableTLSCluster)
+	pdClient := oa.pdControl.GetPDClient(pdapi.Namespace(tc.GetNamespace()), tc.GetName(), tc.Spec.EnableTLSCluster)
 
 	replicas := tc.TiKVStsDesiredReplicas()
 	for i := replicas - 1; i >= 0; i-- {
@@ -3,7 +3,11 @@ package webhook
 import (
 	"net/http"
 
+	"github.com/pingcap/tidb-operator/pkg/pdapi"
+	"github.com/pingcap/tidb-operator/tests/pkg/client"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kubeinformers "k8s.io/client-go/informers"
 )
 
 type Interface interface {
@@ -12,10 +16,18 @@ type Interface interface {
 
 type webhook struct {
 	namespaces sets.String
+	pdControl  pdapi.PDControlInterface
 }
 
 func NewWebhook(namespaces []string) Interface {
+	_, kubeCli, _ := client.NewCliOrDie()
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeCli, 0)
+	pdControl := pdapi.NewDefaultPDControl(kubeInformerFactory.Core().V1().Secrets())
+	kubeInformerFactory.Start(wait.NeverStop)
+	kubeInformerFactory.WaitForCacheSync(wait.NeverStop)
+
 	return &webhook{
 		namespaces: sets.NewString(namespaces...),
+		pdControl:  pdControl,
 	}
 }